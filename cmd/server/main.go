@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
@@ -12,16 +13,33 @@ import (
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/joho/godotenv"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
+	"github.com/lumen/backend/internal/analytics"
 	"github.com/lumen/backend/internal/api"
+	"github.com/lumen/backend/internal/dailystats"
+	"github.com/lumen/backend/internal/events"
+	"github.com/lumen/backend/internal/fieldcrypt"
+	"github.com/lumen/backend/internal/handlers"
+	"github.com/lumen/backend/internal/integrations"
 	"github.com/lumen/backend/internal/middleware"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/pagination"
+	"github.com/lumen/backend/internal/reminders"
+	"github.com/lumen/backend/internal/repository"
+	"github.com/lumen/backend/internal/webhooks"
 	"github.com/lumen/backend/pkg/config"
 	"github.com/lumen/backend/pkg/logger"
 )
 
 func main() {
+	seedUser := flag.String("seed", "", "insert demo data for this user ID and exit (not allowed in production)")
+	flag.Parse()
+
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found")
 	}
@@ -34,14 +52,170 @@ func main() {
 	}
 	defer appLogger.Sync()
 
+	db, err := repository.NewDatabase(cfg.DatabaseURL, cfg.DatabaseReplicaURL)
+	if err != nil {
+		appLogger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+	defer db.Close()
+
+	db.ScanErrors, err = repository.ScanErrorModeFor(cfg.AppEnv, cfg.DBScanErrors)
+	if err != nil {
+		appLogger.Fatal("Invalid DB_SCAN_ERRORS", zap.Error(err))
+	}
+
+	if *seedUser != "" {
+		seedDemoData(cfg.AppEnv, db, *seedUser, appLogger)
+		return
+	}
+
+	// A cold pool only slows the first requests down, so a failed warm-up is
+	// logged rather than fatal.
+	if cfg.DBWarmUpTimeout > 0 {
+		if err := db.WarmUp(context.Background(), cfg.DBWarmUpTimeout); err != nil {
+			appLogger.Warn("Database warm-up incomplete", zap.Error(err))
+		}
+	}
+
+	statsCtx, stopStats := context.WithCancel(context.Background())
+	defer stopStats()
+	go db.LogStats(statsCtx, cfg.DBStatsInterval)
+
+	notesKeys, err := fieldcrypt.ParseKeys(cfg.NotesEncryptionKeys)
+	if err != nil {
+		appLogger.Fatal("Invalid NOTES_ENCRYPTION_KEYS", zap.Error(err))
+	}
+	notesKeyring, err := fieldcrypt.NewKeyring(cfg.NotesEncryptionKeyID, notesKeys)
+	if err != nil {
+		appLogger.Fatal("Invalid notes encryption config", zap.Error(err))
+	}
+
+	reminderRepo := repository.NewReminderRepository(db)
+	reminderEventRepo := repository.NewReminderEventRepository(db)
+
+	if cfg.EnableReminders {
+		notifier := reminders.NewRecordingNotifier(reminderEventRepo, reminders.LogNotifier{})
+		processor := reminders.Processors{
+			reminders.NewHabitReminders(repository.NewHabitRepository(db, notesKeyring), reminderRepo, notifier, cfg.ReminderInterval),
+			reminders.NewDailyLogReminders(reminderRepo, reminderRepo, repository.NewDailyLogRepository(db, notesKeyring), notifier, cfg.ReminderInterval),
+		}
+		scheduler := reminders.NewScheduler(reminderRepo, processor, reminders.Options{
+			Interval:    cfg.ReminderInterval,
+			TickTimeout: cfg.ReminderTickTimeout,
+			BatchSize:   cfg.ReminderBatchSize,
+			Concurrency: cfg.ReminderConcurrency,
+		})
+		remindersCtx, stopReminders := context.WithCancel(context.Background())
+		defer stopReminders()
+		go scheduler.Run(remindersCtx)
+	}
+
+	dailyStatsRepo := repository.NewDailyStatsRepository(db)
+	dailyStats := dailystats.NewService(dailyStatsRepo, reminderRepo)
+
+	if cfg.EnableDailyStats {
+		job := dailystats.NewJob(dailyStatsRepo, dailyStats, dailystats.JobOptions{
+			Hour:      cfg.DailyStatsHour,
+			Days:      cfg.DailyStatsRecomputeDays,
+			BatchSize: cfg.DailyStatsBatchSize,
+		})
+		dailyStatsCtx, stopDailyStats := context.WithCancel(context.Background())
+		defer stopDailyStats()
+		go job.Run(dailyStatsCtx)
+	}
+
+	authMiddleware := middleware.NewAuthMiddleware(cfg.JWTSecret, cfg.JWTIssuer, cfg.JWTAudience)
+
+	// Feed URLs sit in calendar clients and logs for years, so they get a
+	// secret of their own rather than sharing the JWT signing key.
+	if cfg.CalendarFeedSecret == "" {
+		appLogger.Fatal("CALENDAR_FEED_SECRET is required")
+	}
+	calendarFeedRepo := repository.NewCalendarFeedRepository(db)
+	feedTokens := middleware.NewFeedTokens(cfg.CalendarFeedSecret, calendarFeedRepo)
+
+	cursorSecret := cfg.CursorSecret
+	if cursorSecret == "" {
+		cursorSecret = cfg.JWTSecret
+	}
+	cursors := pagination.NewCursors(cursorSecret)
+
+	bus := events.NewBus(cfg.EventBusBufferSize)
+
+	habitRepo := repository.NewHabitRepository(db, notesKeyring)
+	habitHandler := handlers.NewHabitHandler(habitRepo, bus)
+	habitCompletionHandler := handlers.NewHabitCompletionHandler(repository.NewHabitCompletionRepository(db, repository.CompletionDebounce{
+		Window: cfg.HabitCompletionDebounce,
+		Reject: cfg.HabitCompletionDuplicates == "reject",
+	}, notesKeyring), habitRepo, reminderRepo, cursors, cfg.HabitCompletionPageSize)
+	taskHandler := handlers.NewTaskHandler(repository.NewTaskRepository(db), cfg.TaskMaxDueDateYears, models.TagLimits{
+		MaxCount:  cfg.MaxTagsPerResource,
+		MaxLength: cfg.MaxTagLength,
+	}, cfg.TaskEnforceDependencies, bus)
+	taskImportHandler := handlers.NewTaskImportHandler(repository.NewTaskRepository(db), cfg.TaskImportMaxRows)
+	profileRepo := repository.NewProfileRepository(db)
+	dailyLogRepo := repository.NewDailyLogRepository(db, notesKeyring)
+	dailyLogHandler := handlers.NewDailyLogHandler(dailyLogRepo, profileRepo, cursors, bus)
+	insightsHandler := handlers.NewInsightsHandler(dailyLogRepo)
+	dailyStatsHandler := handlers.NewDailyStatsHandler(dailyStats)
+	goalHandler := handlers.NewGoalHandler(repository.NewGoalRepository(db))
+	activityHandler := handlers.NewActivityHandler(repository.NewActivityRepository(db), cursors)
+	calendarHandler := handlers.NewCalendarHandler(repository.NewTaskRepository(db), calendarFeedRepo, feedTokens)
+	plannerHandler := handlers.NewPlannerHandler(habitRepo, repository.NewTaskRepository(db), reminderRepo, profileRepo)
+	profileHandler := handlers.NewProfileHandler(reminderRepo, profileRepo)
+	reminderHandler := handlers.NewReminderHandler(reminderEventRepo, bus, cfg.ReminderAckWindow)
+	maintenance := middleware.NewMaintenance(cfg.MaintenanceMode, cfg.MaintenanceMessage)
+	maintenanceHandler := handlers.NewMaintenanceHandler(maintenance)
+	featureFlagRepo := repository.NewFeatureFlagRepository(db)
+	featureFlags := middleware.NewFeatureFlags(models.ParseFeatureFlags(cfg.FeatureFlags), featureFlagRepo)
+	featureFlagHandler := handlers.NewFeatureFlagHandler(featureFlags, featureFlagRepo)
+	trashRepo := repository.NewTrashRepository(db)
+	trashHandler := handlers.NewTrashHandler(trashRepo, cfg.TrashRetention, bus)
+	webhookHandler := handlers.NewWebhookHandler(repository.NewWebhookRepository(db), webhooks.NewSender(integrations.TimeoutsFromConfig(cfg)))
+
+	if cfg.TrashPurgeInterval > 0 {
+		trashCtx, stopTrashPurge := context.WithCancel(context.Background())
+		defer stopTrashPurge()
+		go repository.RunTrashPurge(trashCtx, trashRepo, cfg.TrashRetention, cfg.TrashPurgeInterval)
+	}
+
 	if cfg.AppEnv == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	if cfg.HabitCompletionDuplicates != "ignore" && cfg.HabitCompletionDuplicates != "reject" {
+		appLogger.Fatal("Invalid HABIT_COMPLETION_DUPLICATES", zap.String("mode", cfg.HabitCompletionDuplicates))
+	}
+
+	validation, err := models.ValidationProfileFor(cfg.AppEnv, cfg.ValidationProfile)
+	if err != nil {
+		appLogger.Fatal("Invalid VALIDATION_PROFILE", zap.Error(err))
+	}
+
+	if !middleware.ValidRequestIDStrategy(cfg.RequestIDStrategy) {
+		appLogger.Fatal("Invalid REQUEST_ID_STRATEGY", zap.String("strategy", cfg.RequestIDStrategy))
+	}
+
+	clientClosedLevel, err := zapcore.ParseLevel(cfg.ClientClosedLogLevel)
+	if err != nil {
+		appLogger.Fatal("Invalid CLIENT_CLOSED_LOG_LEVEL", zap.Error(err))
+	}
+
 	router := gin.New()
 	router.Use(gin.Recovery())
 	router.Use(middleware.Logger(appLogger))
-	router.Use(middleware.RequestID())
+	router.Use(middleware.RequestID(middleware.RequestIDOptions{
+		Header:   cfg.RequestIDHeader,
+		Strategy: cfg.RequestIDStrategy,
+	}))
+	router.Use(middleware.ClientClosed(appLogger, clientClosedLevel))
+
+	if cfg.DBQueryCount {
+		router.Use(middleware.QueryCount(appLogger, cfg.DBQueryCountHeader))
+	}
+
+	if cfg.MaxConcurrentRequests > 0 {
+		router.Use(middleware.ExceptPaths(middleware.ConcurrencyLimit(cfg.MaxConcurrentRequests), "/healthz", "/health", "/health/details", "/ready", "/metrics"))
+	}
 
 	corsConfig := cors.Config{
 		AllowOrigins:     cfg.CORSAllowedOrigins,
@@ -50,14 +224,201 @@ func main() {
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}
-	router.Use(cors.New(corsConfig))
+	// The calendar feed is fetched by external calendar clients and carries
+	// its own CORS policy, so the app-wide one must not reject it first.
+	// Route groups listed in CORS_METHOD_OVERRIDES advertise a narrower set.
+	router.Use(middleware.ExceptPaths(
+		middleware.CORSWithMethodOverrides(corsConfig, middleware.ParseCORSMethods(cfg.CORSMethodOverrides)),
+		calendarFeedPath,
+	))
+
+	router.Use(middleware.ServiceIdentity(middleware.ParseServiceKeys(cfg.ServiceAPIKeys)))
+
+	// Writes are refused during maintenance, except the toggle that ends it.
+	router.Use(middleware.ExceptPaths(maintenance.ReadOnly(), maintenanceAdminPath))
+
+	if cfg.RateLimitEnabled {
+		rateLimitCtx, stopRateLimit := context.WithCancel(context.Background())
+		defer stopRateLimit()
+		router.Use(rateLimiter(rateLimitCtx, cfg, appLogger))
+	}
+
+	// Bodies are buffered here, capped at the largest upload limit; upload
+	// routes still enforce their own. Their sizes feed GET /metrics.
+	var bodySizes *middleware.BodySizeMetrics
+	if cfg.BodySizeMetrics {
+		bodySizes = middleware.NewBodySizeMetrics()
+	}
+	router.Use(middleware.BodyReadTimeout(middleware.BodyReadLimits{
+		Timeout:       cfg.BodyReadTimeout,
+		SlowThreshold: cfg.SlowBodyThreshold,
+		MaxSize:       max(cfg.ImportMaxUploadSize, cfg.AvatarMaxUploadSize),
+		Sizes:         bodySizes,
+	}))
+	router.Use(middleware.JSONDepthLimit(cfg.MaxJSONDepth))
+	router.Use(middleware.JSONStrictness(cfg.StrictJSON || validation.RejectUnknownFields))
+	router.Use(middleware.Validation(validation))
 
+	if cfg.EnableAnalytics && cfg.AnalyticsURL != "" {
+		opts := analytics.DefaultOptions()
+		opts.BufferSize = cfg.AnalyticsBufferSize
+		opts.SendTimeout = cfg.AnalyticsTimeout
+		opts.FailureThreshold = cfg.AnalyticsFailureThreshold
+		opts.Cooldown = cfg.AnalyticsBreakerCooldown
+
+		emitter := analytics.NewEmitter(analytics.NewHTTPSink(cfg.AnalyticsURL), opts)
+		analyticsCtx, stopAnalytics := context.WithCancel(context.Background())
+		defer stopAnalytics()
+		go emitter.Run(analyticsCtx)
+
+		router.Use(middleware.Analytics(emitter))
+		events.Subscribe(bus, "analytics", func(ctx context.Context, event events.Event) {
+			e := analytics.Event{Name: event.EventName(), UserID: event.EventUserID().String()}
+			if p, ok := event.(events.PropertiesEvent); ok {
+				e.Properties = p.EventProperties()
+			}
+			emitter.Emit(e)
+		})
+	}
+
+	router.GET("/healthz", api.Liveness)
 	router.GET("/health", api.HealthCheck)
+	healthHandler := handlers.NewHealthHandler(db, cfg.ReadinessCheckTables, bodySizes)
+	router.GET("/ready", healthHandler.Ready)
+	requireHealthToken := middleware.RequireHealthToken(cfg.HealthAuthToken)
+	router.GET("/health/details", requireHealthToken, healthHandler.Check)
+	router.GET("/metrics", requireHealthToken, healthHandler.Metrics)
 
-	v1 := router.Group("/api/v1")
-	{
+	middleware.RegisterVersion(router, apiVersion(cfg, "v1"), func(v1 *gin.RouterGroup) {
 		v1.GET("/ping", api.Ping)
-	}
+		v1.GET("/errors", api.ErrorCodes)
+		v1.GET("/maintenance", maintenanceHandler.Status)
+
+		admin := v1.Group("/admin", middleware.RequireService(), middleware.UUIDParams("id"))
+		{
+			admin.PUT("/maintenance", maintenanceHandler.Update)
+			admin.PUT("/users/:id/feature-flags/:flag", featureFlagHandler.SetOverride)
+			admin.DELETE("/users/:id/feature-flags/:flag", featureFlagHandler.DeleteOverride)
+		}
+
+		// The per-user concurrency limit keys on the authenticated user, so
+		// it runs right after Authenticate; mounted globally it would see
+		// every caller as anonymous and fall back to their IP.
+		authenticated := []gin.HandlerFunc{authMiddleware.Authenticate()}
+		if cfg.MaxConcurrentRequestsPerUser > 0 {
+			authenticated = append(authenticated, middleware.UserConcurrencyLimit(cfg.MaxConcurrentRequestsPerUser))
+		}
+		authed := v1.Group("", authenticated...)
+
+		authed.GET("/feature-flags", featureFlagHandler.GetAll)
+
+		habits := authed.Group("/habits", middleware.UUIDParams("id"))
+		{
+			habits.GET("", habitHandler.GetAll)
+			habits.POST("", habitHandler.Create)
+			habits.GET("/due", habitHandler.GetDue)
+			habits.GET("/incomplete-today", habitCompletionHandler.IncompleteToday)
+			habits.GET("/streaks", habitHandler.GetStreaks)
+			habits.GET("/streak-freezes", habitHandler.GetStreakFreezes)
+			habits.PATCH("/reorder", habitHandler.Reorder)
+			habits.PATCH("/reminders/shift", habitHandler.ShiftReminders)
+			habits.GET("/:id", habitHandler.GetByID)
+			habits.PATCH("/:id", habitHandler.Update)
+			habits.DELETE("/:id", habitHandler.Delete)
+			habits.POST("/:id/pause", habitHandler.Pause)
+			habits.POST("/:id/merge", habitHandler.Merge)
+			habits.POST("/:id/streak-freeze", habitHandler.SpendStreakFreeze)
+			habits.POST("/:id/reset-streak", habitHandler.ResetStreak)
+			habits.GET("/:id/adherence", habitHandler.Adherence)
+			habits.GET("/:id/consistency", habitHandler.Consistency)
+			habits.GET("/:id/best-time", habitCompletionHandler.BestTime)
+			habits.GET("/:id/by-weekday", habitCompletionHandler.ByWeekday)
+			habits.GET("/:id/completions", habitCompletionHandler.List)
+			habits.POST("/:id/completions/undo", habitCompletionHandler.Undo)
+			habits.DELETE("/completions/:id", habitCompletionHandler.Delete)
+		}
+
+		tasks := authed.Group("/tasks", middleware.UUIDParams("id"))
+		{
+			tasks.GET("", taskHandler.GetAll)
+			tasks.POST("", taskHandler.Create)
+			tasks.POST("/import", middleware.MultipartLimit(middleware.UploadLimits{
+				MaxMemory: cfg.UploadMaxMemory,
+				MaxSize:   cfg.ImportMaxUploadSize,
+			}), taskImportHandler.Import)
+			tasks.POST("/archive-completed", taskHandler.ArchiveCompleted)
+			tasks.PATCH("/move-to-goal", taskHandler.MoveToGoal)
+			tasks.GET("/velocity", taskHandler.Velocity)
+			tasks.GET("/by-horizon", taskHandler.ByHorizon)
+			tasks.GET("/:id", taskHandler.GetByID)
+			tasks.PATCH("/:id", taskHandler.Update)
+			tasks.DELETE("/:id", taskHandler.Delete)
+			tasks.POST("/:id/snooze", taskHandler.Snooze)
+		}
+
+		dailyLogs := authed.Group("/daily-log")
+		{
+			dailyLogs.GET("", dailyLogHandler.GetRange)
+			dailyLogs.POST("", dailyLogHandler.Create)
+			dailyLogs.GET("/averages", dailyLogHandler.GetAverages)
+			dailyLogs.GET("/incomplete", dailyLogHandler.GetIncomplete)
+			dailyLogs.GET("/stats", dailyStatsHandler.GetStats)
+			dailyLogs.GET("/:date", dailyLogHandler.GetByDate)
+			dailyLogs.PUT("/:date", dailyLogHandler.Update)
+		}
+
+		goals := authed.Group("/goals", middleware.UUIDParams("id", "milestone_id"))
+		{
+			goals.GET("/:id/progress", goalHandler.GetProgress)
+			goals.GET("/:id/items", goalHandler.GetItems)
+			goals.GET("/:id/forecast", goalHandler.GetForecast)
+			goals.POST("/:id/milestones", goalHandler.AddMilestone)
+			goals.POST("/:id/milestones/:milestone_id/complete", goalHandler.CompleteMilestone)
+		}
+
+		authed.GET("/feed", activityHandler.GetFeed)
+		authed.GET("/planner/week", plannerHandler.Week)
+
+		trash := authed.Group("/trash", middleware.UUIDParams("id"))
+		{
+			trash.GET("", trashHandler.List)
+			trash.POST("/tasks/:id/restore", trashHandler.RestoreTask)
+			trash.POST("/habits/:id/restore", trashHandler.RestoreHabit)
+		}
+
+		authed.GET("/calendar/feed-url", calendarHandler.GetFeedURL)
+		authed.POST("/calendar/feed-url/rotate", calendarHandler.RotateFeedURL)
+		calendar := v1.Group("/calendar")
+		{
+			feedCORS := middleware.FeedCORS(cfg.CalendarFeedAllowedOrigins)
+			calendar.GET("/feed.ics", feedCORS, feedTokens.Authenticate(), calendarHandler.Feed)
+			calendar.OPTIONS("/feed.ics", feedCORS)
+		}
+
+		profile := authed.Group("/profile")
+		{
+			profile.PUT("/daily-log-reminder", profileHandler.UpdateDailyLogReminder)
+			profile.PUT("/water-unit", profileHandler.UpdateWaterUnit)
+			profile.PUT("/week-start", profileHandler.UpdateWeekStart)
+		}
+
+		insights := authed.Group("/insights")
+		{
+			insights.GET("/sleep-productivity", insightsHandler.SleepProductivity)
+		}
+
+		reminderRoutes := authed.Group("/reminders", middleware.UUIDParams("id"))
+		{
+			reminderRoutes.GET("/effectiveness", reminderHandler.GetEffectiveness)
+			reminderRoutes.POST("/:id/ack", reminderHandler.Acknowledge)
+		}
+
+		webhookRoutes := authed.Group("/webhooks", middleware.UUIDParams("id", "delivery_id"))
+		{
+			webhookRoutes.POST("/:id/deliveries/:delivery_id/retry", webhookHandler.RetryDelivery)
+			webhookRoutes.POST("/:id/rotate-secret", webhookHandler.RotateSecret)
+		}
+	})
 
 	srv := &http.Server{
 		Addr:           fmt.Sprintf(":%s", cfg.AppPort),
@@ -65,7 +426,7 @@ func main() {
 		ReadTimeout:    10 * time.Second,
 		WriteTimeout:   10 * time.Second,
 		IdleTimeout:    120 * time.Second,
-		MaxHeaderBytes: 1 << 20,
+		MaxHeaderBytes: cfg.MaxHeaderBytes,
 	}
 
 	go func() {
@@ -92,5 +453,75 @@ func main() {
 		appLogger.Fatal("Server forced to shutdown", zap.Error(err))
 	}
 
+	// Deliver events from the last requests while consumers are still running.
+	bus.Close()
+
 	appLogger.Info("Server exited successfully")
 }
+
+// calendarFeedPath is exempt from the global CORS policy; see FeedCORS.
+const calendarFeedPath = "/api/v1/calendar/feed.ics"
+
+// maintenanceAdminPath stays writable in maintenance mode so it can be ended.
+const maintenanceAdminPath = "/api/v1/admin/maintenance"
+
+// apiVersion builds the lifecycle metadata for a mounted API version from config.
+func apiVersion(cfg *config.Config, name string) middleware.APIVersion {
+	version := middleware.APIVersion{Name: name}
+	for _, deprecated := range cfg.APIDeprecatedVersions {
+		if deprecated == name {
+			version.Deprecated = true
+			version.Sunset = cfg.APISunsetDate
+		}
+	}
+	return version
+}
+
+// rateLimiter picks the configured rate limit backend. A Redis backend that is
+// unreachable at boot degrades to the in-memory limiter rather than failing,
+// and probes Redis to recover until ctx is cancelled.
+func rateLimiter(ctx context.Context, cfg *config.Config, appLogger *zap.Logger) gin.HandlerFunc {
+	memory := middleware.RateLimitMemory{
+		CleanupInterval: cfg.RateLimitCleanupInterval,
+		MaxKeys:         cfg.RateLimitMaxKeys,
+	}
+	if cfg.RateLimitBackend != "redis" {
+		return middleware.BoundedRateLimit(cfg.RateLimitRequests, cfg.RateLimitWarnPercent, memory)
+	}
+
+	if cfg.RateLimitReconnectInterval <= 0 {
+		appLogger.Fatal("Invalid RATE_LIMIT_RECONNECT_INTERVAL: must be positive", zap.Duration("interval", cfg.RateLimitReconnectInterval))
+	}
+
+	opts, err := redis.ParseURL(cfg.RedisURL)
+	if err != nil {
+		appLogger.Fatal("Invalid REDIS_URL", zap.Error(err))
+	}
+	if cfg.RedisPassword != "" {
+		opts.Password = cfg.RedisPassword
+	}
+	opts.DB = cfg.RedisDB
+
+	store := middleware.NewRedisRateLimitStore(redis.NewClient(opts))
+	return middleware.DistributedRateLimit(ctx, store, cfg.RateLimitRequests, cfg.RateLimitWarnPercent, cfg.RateLimitWindow, cfg.RateLimitReconnectInterval, memory)
+}
+
+// seedDemoData handles --seed: it loads the demo data set for an existing
+// user. Seeding is for local development and is refused in production.
+func seedDemoData(appEnv string, db *repository.Database, user string, appLogger *zap.Logger) {
+	if appEnv == "production" {
+		appLogger.Fatal("Refusing to seed demo data in production")
+	}
+
+	userID, err := uuid.Parse(user)
+	if err != nil {
+		appLogger.Fatal("Invalid --seed user ID", zap.String("user_id", user))
+	}
+
+	inserted, err := repository.SeedDemoData(context.Background(), db, userID)
+	if err != nil {
+		appLogger.Fatal("Failed to seed demo data", zap.Error(err))
+	}
+
+	appLogger.Info("Demo data seeded", zap.String("user_id", userID.String()), zap.Int("rows_inserted", inserted))
+}
@@ -0,0 +1,176 @@
+// Package analytics ships product events to an external collector without
+// letting the collector's health affect request handling.
+package analytics
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type Event struct {
+	Name       string                 `json:"name"`
+	UserID     string                 `json:"user_id,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+	Timestamp  time.Time              `json:"timestamp"`
+}
+
+// Sink delivers a batch of events to the collector.
+type Sink interface {
+	Send(ctx context.Context, events []Event) error
+}
+
+type Options struct {
+	// BufferSize bounds events queued in memory; Emit drops beyond it.
+	BufferSize int
+	// BatchSize is the most events sent per Send call.
+	BatchSize int
+	// FlushInterval sends a partial batch after this long.
+	FlushInterval time.Duration
+	// SendTimeout bounds each Send call.
+	SendTimeout time.Duration
+	// FailureThreshold consecutive failed sends open the circuit breaker.
+	FailureThreshold int
+	// Cooldown is how long the breaker stays open before one trial send.
+	Cooldown time.Duration
+}
+
+func DefaultOptions() Options {
+	return Options{
+		BufferSize:       1000,
+		BatchSize:        100,
+		FlushInterval:    5 * time.Second,
+		SendTimeout:      2 * time.Second,
+		FailureThreshold: 5,
+		Cooldown:         time.Minute,
+	}
+}
+
+// Emitter queues events for a background worker (Run) that batches them to
+// the sink. Emit never blocks and never fails: when the queue is full, or
+// the breaker is open because the sink keeps failing, events are dropped and
+// counted instead.
+type Emitter struct {
+	sink   Sink
+	opts   Options
+	events chan Event
+	now    func() time.Time
+
+	dropped     atomic.Int64
+	breakerOpen atomic.Bool
+
+	// Owned by the Run goroutine.
+	failures  int
+	openUntil time.Time
+}
+
+func NewEmitter(sink Sink, opts Options) *Emitter {
+	return &Emitter{
+		sink:   sink,
+		opts:   opts,
+		events: make(chan Event, opts.BufferSize),
+		now:    time.Now,
+	}
+}
+
+// Emit queues an event, dropping it if the buffer is full.
+func (e *Emitter) Emit(event Event) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = e.now()
+	}
+
+	select {
+	case e.events <- event:
+	default:
+		e.dropped.Add(1)
+	}
+}
+
+// Dropped is the number of events discarded so far.
+func (e *Emitter) Dropped() int64 {
+	return e.dropped.Load()
+}
+
+// BreakerOpen reports whether sends are currently suspended.
+func (e *Emitter) BreakerOpen() bool {
+	return e.breakerOpen.Load()
+}
+
+// Run batches queued events to the sink until ctx is cancelled, then makes
+// one last attempt to send what is left.
+func (e *Emitter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Event, 0, e.opts.BatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			e.flush(e.drain(batch))
+			return
+		case event := <-e.events:
+			batch = append(batch, event)
+			if len(batch) >= e.opts.BatchSize {
+				e.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				e.flush(batch)
+				batch = batch[:0]
+			}
+		}
+	}
+}
+
+// drain appends every event still queued to batch.
+func (e *Emitter) drain(batch []Event) []Event {
+	for {
+		select {
+		case event := <-e.events:
+			batch = append(batch, event)
+		default:
+			return batch
+		}
+	}
+}
+
+func (e *Emitter) flush(batch []Event) {
+	if len(batch) == 0 {
+		return
+	}
+
+	now := e.now()
+	if e.breakerOpen.Load() && now.Before(e.openUntil) {
+		e.dropped.Add(int64(len(batch)))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.opts.SendTimeout)
+	err := e.sink.Send(ctx, batch)
+	cancel()
+
+	if err == nil {
+		if e.breakerOpen.Swap(false) {
+			logger.Info("Analytics sink recovered, circuit breaker closed")
+		}
+		e.failures = 0
+		return
+	}
+
+	e.dropped.Add(int64(len(batch)))
+	e.failures++
+	if e.failures >= e.opts.FailureThreshold {
+		e.openUntil = now.Add(e.opts.Cooldown)
+		if !e.breakerOpen.Swap(true) {
+			logger.Warn("Analytics sink failing, circuit breaker opened",
+				zap.Error(err),
+				zap.Int("consecutive_failures", e.failures),
+				zap.Duration("cooldown", e.opts.Cooldown),
+			)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSink struct {
+	mu    sync.Mutex
+	err   error
+	calls int
+	sent  []Event
+}
+
+func (s *fakeSink) Send(ctx context.Context, events []Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, events...)
+	return nil
+}
+
+func (s *fakeSink) setErr(err error) {
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+}
+
+func (s *fakeSink) callCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+func testOptions() Options {
+	return Options{
+		BufferSize:       10,
+		BatchSize:        1,
+		FlushInterval:    time.Hour,
+		SendTimeout:      time.Second,
+		FailureThreshold: 3,
+		Cooldown:         time.Minute,
+	}
+}
+
+func TestEmitter_DropsWhenBufferFull(t *testing.T) {
+	emitter := NewEmitter(&fakeSink{}, testOptions())
+
+	// Nothing is draining the queue, so everything past BufferSize is dropped
+	// without blocking the caller.
+	for i := 0; i < 15; i++ {
+		emitter.Emit(Event{Name: "test"})
+	}
+
+	assert.Equal(t, int64(5), emitter.Dropped())
+}
+
+func TestEmitter_BreakerOpensOnFailingSink(t *testing.T) {
+	sink := &fakeSink{err: errors.New("collector unavailable")}
+	emitter := NewEmitter(sink, testOptions())
+	now := time.Date(2025, 11, 13, 9, 0, 0, 0, time.UTC)
+	emitter.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		emitter.flush([]Event{{Name: "test"}})
+	}
+	assert.True(t, emitter.BreakerOpen())
+	assert.Equal(t, 3, sink.callCount())
+
+	// While open, batches are dropped without touching the sink.
+	emitter.flush([]Event{{Name: "test"}, {Name: "test"}})
+	assert.Equal(t, 3, sink.callCount())
+	assert.Equal(t, int64(5), emitter.Dropped())
+
+	// After the cooldown a trial send goes through; failing again reopens it.
+	now = now.Add(2 * time.Minute)
+	emitter.flush([]Event{{Name: "test"}})
+	assert.Equal(t, 4, sink.callCount())
+	assert.True(t, emitter.BreakerOpen())
+
+	// Once the sink recovers the breaker closes.
+	now = now.Add(2 * time.Minute)
+	sink.setErr(nil)
+	emitter.flush([]Event{{Name: "test"}})
+	assert.False(t, emitter.BreakerOpen())
+	assert.Len(t, sink.sent, 1)
+}
+
+func TestEmitter_RunDeliversAndFlushesOnShutdown(t *testing.T) {
+	sink := &fakeSink{}
+	opts := testOptions()
+	opts.BatchSize = 100
+	emitter := NewEmitter(sink, opts)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		emitter.Run(ctx)
+		close(done)
+	}()
+
+	emitter.Emit(Event{Name: "a"})
+	emitter.Emit(Event{Name: "b"})
+	cancel()
+	<-done
+
+	assert.Len(t, sink.sent, 2)
+	assert.Equal(t, int64(0), emitter.Dropped())
+}
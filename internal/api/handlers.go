@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	apperrors "github.com/lumen/backend/pkg/errors"
 )
 
 // HealthCheckResponse represents the health check response
@@ -28,9 +29,25 @@ func HealthCheck(c *gin.Context) {
 	})
 }
 
+// Liveness handles GET /healthz, a minimal probe that touches no
+// dependencies and is never behind auth.
+func Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
 // Ping handles GET /api/v1/ping
 func Ping(c *gin.Context) {
 	c.JSON(http.StatusOK, PingResponse{
 		Message: "pong",
 	})
 }
+
+// ErrorCodes handles GET /api/v1/errors, listing every error code the API can
+// return so clients can map them to localized messages.
+func ErrorCodes(c *gin.Context) {
+	codes := apperrors.Catalog()
+	c.JSON(http.StatusOK, gin.H{
+		"data":  codes,
+		"count": len(codes),
+	})
+}
@@ -0,0 +1,98 @@
+package dailystats
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// UserLister pages through users in ID order; after is the last ID of the
+// previous batch (uuid.Nil to start).
+type UserLister interface {
+	ListUserIDs(ctx context.Context, after uuid.UUID, limit int) ([]uuid.UUID, error)
+}
+
+type JobOptions struct {
+	// Hour is the UTC hour the job runs at each night.
+	Hour int
+	// Days is how many days before each user's current day are recomputed.
+	Days int
+	// BatchSize is how many user IDs are listed per query.
+	BatchSize int
+}
+
+func DefaultJobOptions() JobOptions {
+	return JobOptions{Hour: 3, Days: 7, BatchSize: 500}
+}
+
+// Job precomputes every user's recent daily stats once a night.
+type Job struct {
+	users   UserLister
+	service *Service
+	opts    JobOptions
+}
+
+func NewJob(users UserLister, service *Service, opts JobOptions) *Job {
+	if opts.BatchSize < 1 {
+		opts.BatchSize = 1
+	}
+	return &Job{users: users, service: service, opts: opts}
+}
+
+// Run runs the job at the configured hour every night until ctx is cancelled.
+func (j *Job) Run(ctx context.Context) {
+	for {
+		timer := time.NewTimer(time.Until(j.nextRun(time.Now())))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case now := <-timer.C:
+			users, failed := j.RunOnce(ctx, now)
+			logger.Info("Daily stats precomputed", zap.Int("users", users), zap.Int("failed", failed))
+		}
+	}
+}
+
+// nextRun returns the first occurrence of the configured hour after now.
+func (j *Job) nextRun(now time.Time) time.Time {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), j.opts.Hour, 0, 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// RunOnce refreshes every user's recent days, returning how many users were
+// processed and how many of them failed. A failing user is logged and
+// skipped; the days they are missing are computed on demand instead.
+func (j *Job) RunOnce(ctx context.Context, now time.Time) (users, failed int) {
+	after := uuid.Nil
+	for {
+		ids, err := j.users.ListUserIDs(ctx, after, j.opts.BatchSize)
+		if err != nil {
+			logger.Error("Failed to list users for daily stats", zap.Error(err))
+			return users, failed
+		}
+
+		for _, id := range ids {
+			if ctx.Err() != nil {
+				return users, failed
+			}
+			if err := j.service.Refresh(ctx, id, j.opts.Days, now); err != nil {
+				failed++
+				logger.Error("Failed to precompute daily stats", zap.Error(err), zap.String("user_id", id.String()))
+			}
+			users++
+			after = id
+		}
+
+		if len(ids) < j.opts.BatchSize {
+			return users, failed
+		}
+	}
+}
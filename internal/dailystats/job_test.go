@@ -0,0 +1,45 @@
+package dailystats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeUsers []uuid.UUID
+
+func (f fakeUsers) ListUserIDs(ctx context.Context, after uuid.UUID, limit int) ([]uuid.UUID, error) {
+	start := 0
+	if after != uuid.Nil {
+		for i, id := range f {
+			if id == after {
+				start = i + 1
+			}
+		}
+	}
+	end := min(start+limit, len(f))
+	return f[start:end], nil
+}
+
+func TestJob_RunOnceRefreshesEveryUserAcrossBatches(t *testing.T) {
+	repo := newFakeRepository(sampleActivity())
+	service := NewService(repo, fakeTimezones("UTC"))
+	users := fakeUsers{uuid.New(), uuid.New(), uuid.New()}
+
+	job := NewJob(users, service, JobOptions{Hour: 3, Days: 7, BatchSize: 2})
+	processed, failed := job.RunOnce(context.Background(), now)
+
+	assert.Equal(t, 3, processed)
+	assert.Equal(t, 0, failed)
+	assert.Len(t, repo.saved, 3)
+}
+
+func TestJob_NextRun(t *testing.T) {
+	job := NewJob(fakeUsers{}, nil, JobOptions{Hour: 3})
+
+	assert.Equal(t, time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC), job.nextRun(time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC), job.nextRun(time.Date(2026, 10, 16, 2, 59, 0, 0, time.UTC)))
+}
@@ -0,0 +1,158 @@
+// Package dailystats serves per-day stats from the precomputed daily_stats
+// table, which a nightly job keeps filled. Days the table does not hold are
+// computed live from the underlying activity and stored on demand.
+package dailystats
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Repository reads the activity stats are computed from and the stored stats.
+type Repository interface {
+	GetDailyStatsData(ctx context.Context, userID uuid.UUID, from, to time.Time) (*models.DailyStatsData, error)
+	GetStoredDailyStats(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) ([]models.DailyLogStats, error)
+	SaveDailyStats(ctx context.Context, userID uuid.UUID, stats []models.DailyLogStats) error
+}
+
+// UserTimezones looks up a user's IANA timezone name.
+type UserTimezones interface {
+	GetUserTimezone(ctx context.Context, userID uuid.UUID) (string, error)
+}
+
+type Service struct {
+	repo      Repository
+	timezones UserTimezones
+}
+
+func NewService(repo Repository, timezones UserTimezones) *Service {
+	return &Service{repo: repo, timezones: timezones}
+}
+
+// Stats returns one entry per calendar date from startDate to endDate
+// inclusive. Past days are read from the table; those not stored yet are
+// computed live and stored. The user's current day, and any later one, is
+// still changing, so it is always computed live and never stored.
+func (s *Service) Stats(ctx context.Context, userID uuid.UUID, startDate, endDate, now time.Time) ([]models.DailyLogStats, error) {
+	loc, err := s.location(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	today := models.StatsDate(now.In(loc))
+
+	stored, err := s.repo.GetStoredDailyStats(ctx, userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	byDate := make(map[time.Time]models.DailyLogStats, len(stored))
+	for _, day := range stored {
+		if date := models.StatsDate(day.Date); date.Before(today) {
+			day.Date = date
+			byDate[date] = day
+		}
+	}
+
+	var first, last time.Time
+	for day := startDate; !day.After(endDate); day = day.AddDate(0, 0, 1) {
+		if _, ok := byDate[day]; ok {
+			continue
+		}
+		if first.IsZero() {
+			first = day
+		}
+		last = day
+	}
+
+	if !first.IsZero() {
+		live, err := s.compute(ctx, userID, first, last, loc)
+		if err != nil {
+			return nil, err
+		}
+
+		var computed []models.DailyLogStats
+		for _, day := range live {
+			if _, ok := byDate[day.Date]; ok {
+				continue
+			}
+			byDate[day.Date] = day
+			if day.Date.Before(today) {
+				computed = append(computed, day)
+			}
+		}
+
+		// The response is complete either way; a failed save only means the
+		// days are computed again next time.
+		if len(computed) > 0 {
+			if err := s.repo.SaveDailyStats(ctx, userID, computed); err != nil {
+				logger.Warn("Failed to store daily stats", zap.Error(err), zap.String("user_id", userID.String()))
+			}
+		}
+	}
+
+	stats := make([]models.DailyLogStats, 0, len(byDate))
+	for day := startDate; !day.After(endDate); day = day.AddDate(0, 0, 1) {
+		stats = append(stats, byDate[day])
+	}
+	return stats, nil
+}
+
+// Refresh recomputes and stores the given number of days before the user's
+// current day, picking up late edits to recent days.
+func (s *Service) Refresh(ctx context.Context, userID uuid.UUID, days int, now time.Time) error {
+	if days < 1 {
+		return nil
+	}
+
+	loc, err := s.location(ctx, userID)
+	if err != nil {
+		return err
+	}
+	today := models.StatsDate(now.In(loc))
+
+	stats, err := s.compute(ctx, userID, today.AddDate(0, 0, -days), today.AddDate(0, 0, -1), loc)
+	if err != nil {
+		return err
+	}
+	return s.repo.SaveDailyStats(ctx, userID, stats)
+}
+
+// Live computes the days straight from the activity, bypassing the table.
+func (s *Service) Live(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) ([]models.DailyLogStats, error) {
+	loc, err := s.location(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.compute(ctx, userID, startDate, endDate, loc)
+}
+
+func (s *Service) compute(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, loc *time.Location) ([]models.DailyLogStats, error) {
+	from, _ := models.DayBounds(startDate, loc)
+	_, to := models.DayBounds(endDate, loc)
+
+	data, err := s.repo.GetDailyStatsData(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return models.ComputeDailyStats(*data, startDate, endDate, loc), nil
+}
+
+// location returns the user's timezone, falling back to UTC when the stored
+// name is unknown.
+func (s *Service) location(ctx context.Context, userID uuid.UUID) (*time.Location, error) {
+	timezone, err := s.timezones.GetUserTimezone(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC, nil
+	}
+	return loc, nil
+}
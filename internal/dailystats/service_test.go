@@ -0,0 +1,197 @@
+package dailystats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTimezones string
+
+func (f fakeTimezones) GetUserTimezone(ctx context.Context, userID uuid.UUID) (string, error) {
+	return string(f), nil
+}
+
+// fakeRepository holds one user's activity and stored days. Data queries
+// honour the requested bounds like the SQL does.
+type fakeRepository struct {
+	data    models.DailyStatsData
+	stored  map[time.Time]models.DailyLogStats
+	saved   [][]models.DailyLogStats
+	saveErr error
+}
+
+func newFakeRepository(data models.DailyStatsData) *fakeRepository {
+	return &fakeRepository{data: data, stored: map[time.Time]models.DailyLogStats{}}
+}
+
+func (f *fakeRepository) GetDailyStatsData(ctx context.Context, userID uuid.UUID, from, to time.Time) (*models.DailyStatsData, error) {
+	within := func(t *time.Time) bool { return t != nil && !t.Before(from) && t.Before(to) }
+
+	data := models.DailyStatsData{Habits: f.data.Habits}
+	for _, log := range f.data.Logs {
+		if !log.Date.Before(models.StatsDate(from)) && log.Date.Before(models.StatsDate(to)) {
+			data.Logs = append(data.Logs, log)
+		}
+	}
+	for _, completion := range f.data.Completions {
+		if within(&completion.CompletedAt) {
+			data.Completions = append(data.Completions, completion)
+		}
+	}
+	for _, task := range f.data.Tasks {
+		if within(task.DueDate) || within(task.CompletedAt) {
+			data.Tasks = append(data.Tasks, task)
+		}
+	}
+	return &data, nil
+}
+
+func (f *fakeRepository) GetStoredDailyStats(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) ([]models.DailyLogStats, error) {
+	var stats []models.DailyLogStats
+	for day := startDate; !day.After(endDate); day = day.AddDate(0, 0, 1) {
+		if s, ok := f.stored[day]; ok {
+			stats = append(stats, s)
+		}
+	}
+	return stats, nil
+}
+
+func (f *fakeRepository) SaveDailyStats(ctx context.Context, userID uuid.UUID, stats []models.DailyLogStats) error {
+	if f.saveErr != nil {
+		return f.saveErr
+	}
+	f.saved = append(f.saved, stats)
+	for _, s := range stats {
+		f.stored[s.Date] = s
+	}
+	return nil
+}
+
+func date(day int) time.Time {
+	return time.Date(2026, 10, day, 0, 0, 0, 0, time.UTC)
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
+
+// sampleActivity spans 10–16 October for a user in New York, with activity
+// near midnight to exercise the day boundaries.
+func sampleActivity() models.DailyStatsData {
+	ny, _ := time.LoadLocation("America/New_York")
+	read, walk := uuid.New(), uuid.New()
+
+	return models.DailyStatsData{
+		Logs: []models.DailyLog{
+			{Date: date(11), MoodRating: 3, EnergyLevel: 2, ProductivityRating: 4},
+			{Date: date(14), MoodRating: 5, EnergyLevel: 4, ProductivityRating: 5},
+			{Date: date(16), MoodRating: 2, EnergyLevel: 2, ProductivityRating: 2},
+		},
+		Habits: []models.Habit{
+			{ID: read, IsActive: true, CreatedAt: time.Date(2026, 9, 1, 9, 0, 0, 0, ny)},
+			{ID: walk, IsActive: true, CreatedAt: time.Date(2026, 10, 12, 23, 30, 0, 0, ny)},
+		},
+		Completions: []models.HabitCompletion{
+			{HabitID: read, CompletedAt: time.Date(2026, 10, 10, 23, 59, 0, 0, ny)},
+			{HabitID: read, CompletedAt: time.Date(2026, 10, 12, 7, 0, 0, 0, ny)},
+			{HabitID: walk, CompletedAt: time.Date(2026, 10, 13, 0, 1, 0, 0, ny)},
+			{HabitID: read, CompletedAt: time.Date(2026, 10, 16, 8, 0, 0, 0, ny)},
+		},
+		Tasks: []models.Task{
+			{DueDate: ptr(time.Date(2026, 10, 11, 18, 0, 0, 0, ny)), CompletedAt: ptr(time.Date(2026, 10, 12, 0, 30, 0, 0, ny))},
+			{DueDate: ptr(time.Date(2026, 10, 14, 12, 0, 0, 0, ny))},
+			{DueDate: ptr(time.Date(2026, 10, 16, 12, 0, 0, 0, ny)), CompletedAt: ptr(time.Date(2026, 10, 16, 9, 0, 0, 0, ny))},
+		},
+	}
+}
+
+// 15:00 UTC on the 16th is 11:00 in New York, so the 16th is the user's today.
+var now = time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)
+
+func TestStats_PrecomputedAndLiveAgree(t *testing.T) {
+	repo := newFakeRepository(sampleActivity())
+	service := NewService(repo, fakeTimezones("America/New_York"))
+	ctx := context.Background()
+	userID := uuid.New()
+
+	assert.NoError(t, service.Refresh(ctx, userID, 6, now))
+	assert.Len(t, repo.stored, 6)
+
+	precomputed, err := service.Stats(ctx, userID, date(10), date(16), now)
+	assert.NoError(t, err)
+
+	live, err := service.Live(ctx, userID, date(10), date(16))
+	assert.NoError(t, err)
+
+	assert.Equal(t, live, precomputed)
+	// Every past day was served from the table, so nothing new was stored.
+	assert.Len(t, repo.saved, 1)
+}
+
+func TestStats_ComputesMissingPastDaysAndStoresThem(t *testing.T) {
+	repo := newFakeRepository(sampleActivity())
+	service := NewService(repo, fakeTimezones("America/New_York"))
+	ctx := context.Background()
+	userID := uuid.New()
+
+	stats, err := service.Stats(ctx, userID, date(10), date(16), now)
+	assert.NoError(t, err)
+
+	live, _ := service.Live(ctx, userID, date(10), date(16))
+	assert.Equal(t, live, stats)
+
+	// The past days are stored for next time; today is not.
+	assert.Len(t, repo.stored, 6)
+	_, storedToday := repo.stored[date(16)]
+	assert.False(t, storedToday)
+}
+
+func TestStats_TodayIsAlwaysLive(t *testing.T) {
+	repo := newFakeRepository(sampleActivity())
+	service := NewService(repo, fakeTimezones("America/New_York"))
+	ctx := context.Background()
+	userID := uuid.New()
+
+	// A stale row for today must be ignored.
+	repo.stored[date(16)] = models.DailyLogStats{Date: date(16), HabitsTotal: 99}
+
+	stats, err := service.Stats(ctx, userID, date(16), date(16), now)
+	assert.NoError(t, err)
+
+	if assert.Len(t, stats, 1) {
+		assert.Equal(t, 2, stats[0].HabitsTotal)
+		assert.Equal(t, 1, stats[0].HabitsCompleted)
+		assert.Equal(t, 1, stats[0].TasksCompleted)
+		assert.Equal(t, 2, stats[0].MoodRating)
+	}
+	assert.Empty(t, repo.saved)
+}
+
+func TestStats_SaveFailureStillAnswers(t *testing.T) {
+	repo := newFakeRepository(sampleActivity())
+	repo.saveErr = errors.New("connection reset")
+	service := NewService(repo, fakeTimezones("America/New_York"))
+
+	stats, err := service.Stats(context.Background(), uuid.New(), date(10), date(12), now)
+
+	assert.NoError(t, err)
+	assert.Len(t, stats, 3)
+}
+
+func TestRefresh_SkipsToday(t *testing.T) {
+	repo := newFakeRepository(sampleActivity())
+	service := NewService(repo, fakeTimezones("America/New_York"))
+
+	assert.NoError(t, service.Refresh(context.Background(), uuid.New(), 2, now))
+
+	if assert.Len(t, repo.saved, 1) && assert.Len(t, repo.saved[0], 2) {
+		assert.Equal(t, date(14), repo.saved[0][0].Date)
+		assert.Equal(t, date(15), repo.saved[0][1].Date)
+	}
+}
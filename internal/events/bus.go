@@ -0,0 +1,146 @@
+// Package events is an in-process bus for domain events, letting consumers
+// such as webhooks, analytics and audit logging react to mutations without
+// the handlers that make them knowing who is listening.
+package events
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Event is a domain event, published after the mutation it describes has
+// been committed.
+type Event interface {
+	EventName() string
+	EventUserID() uuid.UUID
+}
+
+// Publisher is the side of the bus handlers depend on.
+type Publisher interface {
+	Publish(event Event)
+}
+
+type subscription struct {
+	name    string
+	accepts func(Event) bool
+	handle  func(context.Context, Event)
+	events  chan Event
+}
+
+// Bus fans events out to subscribers. Each subscriber has its own buffer and
+// goroutine, so a slow or panicking subscriber never holds up the publisher
+// or the other subscribers. Publish never blocks: an event that does not fit
+// a subscriber's buffer is dropped for that subscriber and counted.
+type Bus struct {
+	bufferSize int
+
+	mu     sync.RWMutex
+	subs   []*subscription
+	closed bool
+	wg     sync.WaitGroup
+
+	dropped atomic.Int64
+}
+
+func NewBus(bufferSize int) *Bus {
+	return &Bus{bufferSize: bufferSize}
+}
+
+// Subscribe registers handler for events of type E; subscribing with E set
+// to Event receives everything. name identifies the subscriber in logs.
+// Subscribing after Close is a no-op.
+func Subscribe[E Event](b *Bus, name string, handler func(ctx context.Context, event E)) {
+	sub := &subscription{
+		name: name,
+		accepts: func(event Event) bool {
+			_, ok := event.(E)
+			return ok
+		},
+		handle: func(ctx context.Context, event Event) {
+			handler(ctx, event.(E))
+		},
+		events: make(chan Event, b.bufferSize),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.subs = append(b.subs, sub)
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for event := range sub.events {
+			sub.deliver(event)
+		}
+	}()
+}
+
+// Publish queues event for every subscriber interested in it.
+func (b *Bus) Publish(event Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return
+	}
+
+	for _, sub := range b.subs {
+		if !sub.accepts(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			b.dropped.Add(1)
+			logger.Warn("Event dropped: subscriber buffer full",
+				zap.String("subscriber", sub.name),
+				zap.String("event", event.EventName()),
+			)
+		}
+	}
+}
+
+// Dropped is the number of deliveries discarded so far.
+func (b *Bus) Dropped() int64 {
+	return b.dropped.Load()
+}
+
+// Close stops accepting events and waits for subscribers to work through
+// what is already queued.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	for _, sub := range b.subs {
+		close(sub.events)
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+}
+
+// deliver runs the handler, recovering a panic so the subscriber keeps
+// receiving later events.
+func (s *subscription) deliver(event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Event subscriber panicked",
+				zap.String("subscriber", s.name),
+				zap.String("event", event.EventName()),
+				zap.Any("panic", r),
+			)
+		}
+	}()
+
+	s.handle(context.Background(), event)
+}
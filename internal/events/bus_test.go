@@ -0,0 +1,101 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// collector records the events a subscriber received.
+type collector struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (c *collector) add(event Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.events = append(c.events, event)
+}
+
+func TestBus_DeliversToEverySubscriber(t *testing.T) {
+	bus := NewBus(10)
+	first, second := &collector{}, &collector{}
+	Subscribe(bus, "first", func(ctx context.Context, event Event) { first.add(event) })
+	Subscribe(bus, "second", func(ctx context.Context, event TaskCreated) { second.add(event) })
+
+	event := TaskCreated{Task: models.Task{ID: uuid.New(), UserID: uuid.New()}}
+	bus.Publish(event)
+	bus.Close()
+
+	assert.Equal(t, []Event{event}, first.events)
+	assert.Equal(t, []Event{event}, second.events)
+}
+
+func TestBus_FiltersByEventType(t *testing.T) {
+	bus := NewBus(10)
+	tasks := &collector{}
+	Subscribe(bus, "tasks", func(ctx context.Context, event TaskDeleted) { tasks.add(event) })
+
+	deleted := TaskDeleted{UserID: uuid.New(), TaskID: uuid.New()}
+	bus.Publish(HabitDeleted{UserID: uuid.New(), HabitID: uuid.New()})
+	bus.Publish(deleted)
+	bus.Close()
+
+	assert.Equal(t, []Event{deleted}, tasks.events)
+	assert.Zero(t, bus.Dropped())
+}
+
+func TestBus_PanickingSubscriberDoesNotAffectOthers(t *testing.T) {
+	bus := NewBus(10)
+	healthy := &collector{}
+	panics := 0
+	Subscribe(bus, "broken", func(ctx context.Context, event Event) {
+		panics++
+		panic("boom")
+	})
+	Subscribe(bus, "healthy", func(ctx context.Context, event Event) { healthy.add(event) })
+
+	bus.Publish(TaskDeleted{UserID: uuid.New()})
+	bus.Publish(TaskDeleted{UserID: uuid.New()})
+	bus.Close()
+
+	assert.Equal(t, 2, panics, "the broken subscriber keeps receiving after a panic")
+	assert.Len(t, healthy.events, 2)
+}
+
+func TestBus_PublishDropsWhenSubscriberIsBehind(t *testing.T) {
+	bus := NewBus(1)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var once sync.Once
+	Subscribe(bus, "slow", func(ctx context.Context, event Event) {
+		once.Do(func() { close(started) })
+		<-release
+	})
+
+	bus.Publish(TaskDeleted{})
+	<-started // the first event is being handled; the buffer is empty again
+	bus.Publish(TaskDeleted{})
+	bus.Publish(TaskDeleted{})
+
+	assert.Equal(t, int64(1), bus.Dropped())
+	close(release)
+	bus.Close()
+}
+
+func TestBus_PublishAfterCloseIsIgnored(t *testing.T) {
+	bus := NewBus(1)
+	received := &collector{}
+	Subscribe(bus, "late", func(ctx context.Context, event Event) { received.add(event) })
+	bus.Close()
+
+	bus.Publish(TaskDeleted{})
+	bus.Close()
+
+	assert.Empty(t, received.events)
+}
@@ -0,0 +1,81 @@
+package events
+
+import (
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+)
+
+// PropertiesEvent is implemented by events that carry extra properties for
+// analytics.
+type PropertiesEvent interface {
+	Event
+	EventProperties() map[string]interface{}
+}
+
+type TaskCreated struct{ Task models.Task }
+
+func (TaskCreated) EventName() string        { return "task.created" }
+func (e TaskCreated) EventUserID() uuid.UUID { return e.Task.UserID }
+
+type TaskUpdated struct{ Task models.Task }
+
+func (TaskUpdated) EventName() string        { return "task.updated" }
+func (e TaskUpdated) EventUserID() uuid.UUID { return e.Task.UserID }
+
+type TaskDeleted struct{ UserID, TaskID uuid.UUID }
+
+func (TaskDeleted) EventName() string        { return "task.deleted" }
+func (e TaskDeleted) EventUserID() uuid.UUID { return e.UserID }
+
+// TaskRestored is published when a deleted task comes back from the trash.
+type TaskRestored struct{ Task models.Task }
+
+func (TaskRestored) EventName() string        { return "task.restored" }
+func (e TaskRestored) EventUserID() uuid.UUID { return e.Task.UserID }
+
+type HabitCreated struct{ Habit models.Habit }
+
+func (HabitCreated) EventName() string        { return "habit.created" }
+func (e HabitCreated) EventUserID() uuid.UUID { return e.Habit.UserID }
+
+type HabitUpdated struct{ Habit models.Habit }
+
+func (HabitUpdated) EventName() string        { return "habit.updated" }
+func (e HabitUpdated) EventUserID() uuid.UUID { return e.Habit.UserID }
+
+type HabitDeleted struct{ UserID, HabitID uuid.UUID }
+
+func (HabitDeleted) EventName() string        { return "habit.deleted" }
+func (e HabitDeleted) EventUserID() uuid.UUID { return e.UserID }
+
+// HabitRestored is published when a deleted habit comes back from the trash.
+type HabitRestored struct{ Habit models.Habit }
+
+func (HabitRestored) EventName() string        { return "habit.restored" }
+func (e HabitRestored) EventUserID() uuid.UUID { return e.Habit.UserID }
+
+// DailyLogSaved covers both creating a log (including the upsert of an
+// existing date) and updating one.
+type DailyLogSaved struct{ Log models.DailyLog }
+
+func (DailyLogSaved) EventName() string        { return "daily_log.saved" }
+func (e DailyLogSaved) EventUserID() uuid.UUID { return e.Log.UserID }
+
+// ReminderAcknowledged is published the first time a user acknowledges a
+// reminder. ActedOn reports whether that was within the acknowledgment window.
+type ReminderAcknowledged struct {
+	Reminder models.ReminderEvent
+	ActedOn  bool
+}
+
+func (ReminderAcknowledged) EventName() string        { return "reminder.acknowledged" }
+func (e ReminderAcknowledged) EventUserID() uuid.UUID { return e.Reminder.UserID }
+
+// EventProperties feeds the reminder effectiveness metric in analytics.
+func (e ReminderAcknowledged) EventProperties() map[string]interface{} {
+	return map[string]interface{}{
+		"kind":            e.Reminder.Kind,
+		"acted_on":        e.ActedOn,
+		"latency_seconds": int64(e.Reminder.AcknowledgedAt.Sub(e.Reminder.SentAt).Seconds()),
+	}
+}
@@ -0,0 +1,160 @@
+// Package fieldcrypt encrypts individual column values, such as free-text
+// notes, with AES-GCM before they are written to the database.
+//
+// Encrypted values are stored as "enc:<key id>:<nonce+ciphertext>", the last
+// part base64url encoded. The key ID lets keys rotate: new values are written
+// with the current key while values written under older keys still decrypt as
+// long as those keys stay configured. Values without that shape are read back
+// unchanged, so rows written before encryption was enabled keep working.
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const prefix = "enc:"
+
+var (
+	// ErrUnknownKey means a value was encrypted with a key that isn't
+	// configured, typically one retired too early during rotation.
+	ErrUnknownKey = errors.New("value encrypted with an unknown key")
+	// ErrCorrupt means a value failed authentication: it was altered, or the
+	// configured key under its ID is not the one it was written with.
+	ErrCorrupt = errors.New("encrypted value failed authentication")
+)
+
+// Keyring holds the keys values may be encrypted with, by ID. A nil Keyring,
+// or one without a current key, stores new values as plaintext.
+type Keyring struct {
+	current string
+	aeads   map[string]cipher.AEAD
+}
+
+// ParseKeys reads "id:key" entries, each key base64 (standard encoding) of
+// 16, 24 or 32 bytes for AES-128, -192 or -256.
+func ParseKeys(entries []string) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok || id == "" || encoded == "" {
+			return nil, errors.New("malformed key entry: want id:base64key")
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %q is not valid base64: %w", id, err)
+		}
+		if _, dup := keys[id]; dup {
+			return nil, fmt.Errorf("key %q is listed twice", id)
+		}
+		keys[id] = key
+	}
+	return keys, nil
+}
+
+// NewKeyring builds a keyring from keys by ID. current names the key new
+// values are encrypted with; empty leaves new values in plaintext while
+// existing encrypted ones still decrypt.
+func NewKeyring(current string, keys map[string][]byte) (*Keyring, error) {
+	if current == "" && len(keys) == 0 {
+		return nil, nil
+	}
+
+	k := &Keyring{current: current, aeads: make(map[string]cipher.AEAD, len(keys))}
+	for id, key := range keys {
+		if strings.Contains(id, ":") {
+			return nil, fmt.Errorf("key ID %q must not contain ':'", id)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		k.aeads[id] = aead
+	}
+
+	if current != "" && k.aeads[current] == nil {
+		return nil, fmt.Errorf("current key %q is not configured", current)
+	}
+
+	return k, nil
+}
+
+// Encrypt returns plaintext encrypted with the current key. Empty values and
+// keyrings without a current key return plaintext unchanged.
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	if k == nil || k.current == "" || plaintext == "" {
+		return plaintext, nil
+	}
+
+	aead := k.aeads[k.current]
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(k.current))
+	return prefix + k.current + ":" + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns the plaintext of a value written by Encrypt with any
+// configured key. Values that aren't encrypted are returned unchanged.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	id, sealed, ok := parse(value)
+	if !ok {
+		return value, nil
+	}
+
+	if k == nil || k.aeads[id] == nil {
+		return "", fmt.Errorf("%w %q", ErrUnknownKey, id)
+	}
+
+	aead := k.aeads[id]
+	if len(sealed) < aead.NonceSize() {
+		return "", ErrCorrupt
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(id))
+	if err != nil {
+		return "", ErrCorrupt
+	}
+
+	return string(plaintext), nil
+}
+
+// parse splits an encrypted value into its key ID and sealed bytes, reporting
+// false for anything not in that shape.
+func parse(value string) (string, []byte, bool) {
+	rest, ok := strings.CutPrefix(value, prefix)
+	if !ok {
+		return "", nil, false
+	}
+
+	id, encoded, ok := strings.Cut(rest, ":")
+	if !ok || id == "" || encoded == "" {
+		return "", nil, false
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", nil, false
+	}
+
+	return id, sealed, true
+}
@@ -0,0 +1,143 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+func newKeyring(t *testing.T, current string, keys map[string][]byte) *Keyring {
+	t.Helper()
+	k, err := NewKeyring(current, keys)
+	assert.NoError(t, err)
+	return k
+}
+
+func TestKeyring_RoundTrip(t *testing.T) {
+	k := newKeyring(t, "2026a", map[string][]byte{"2026a": testKey(1)})
+
+	encrypted, err := k.Encrypt("slept badly, worried about the launch")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encrypted, "enc:2026a:"))
+	assert.NotContains(t, encrypted, "launch")
+
+	decrypted, err := k.Decrypt(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, "slept badly, worried about the launch", decrypted)
+}
+
+func TestKeyring_NonceDiffersPerWrite(t *testing.T) {
+	k := newKeyring(t, "a", map[string][]byte{"a": testKey(1)})
+
+	first, _ := k.Encrypt("same note")
+	second, _ := k.Encrypt("same note")
+
+	assert.NotEqual(t, first, second)
+}
+
+func TestKeyring_RotatedKeysStillDecryptOldData(t *testing.T) {
+	old := newKeyring(t, "old", map[string][]byte{"old": testKey(1)})
+	written, err := old.Encrypt("written before rotation")
+	assert.NoError(t, err)
+
+	rotated := newKeyring(t, "new", map[string][]byte{"old": testKey(1), "new": testKey(2)})
+
+	decrypted, err := rotated.Decrypt(written)
+	assert.NoError(t, err)
+	assert.Equal(t, "written before rotation", decrypted)
+
+	fresh, err := rotated.Encrypt("written after rotation")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(fresh, "enc:new:"))
+}
+
+func TestKeyring_RetiredKeyIsUnknown(t *testing.T) {
+	old := newKeyring(t, "old", map[string][]byte{"old": testKey(1)})
+	written, _ := old.Encrypt("orphaned")
+
+	retired := newKeyring(t, "new", map[string][]byte{"new": testKey(2)})
+
+	_, err := retired.Decrypt(written)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestKeyring_DetectsTamperingAndWrongKey(t *testing.T) {
+	k := newKeyring(t, "a", map[string][]byte{"a": testKey(1)})
+	written, _ := k.Encrypt("private")
+
+	id, sealed, _ := parse(written)
+	sealed[len(sealed)-1] ^= 0xff
+	_, err := k.Decrypt(prefix + id + ":" + base64.RawURLEncoding.EncodeToString(sealed))
+	assert.ErrorIs(t, err, ErrCorrupt)
+
+	swapped := newKeyring(t, "a", map[string][]byte{"a": testKey(9)})
+	_, err = swapped.Decrypt(written)
+	assert.ErrorIs(t, err, ErrCorrupt)
+}
+
+func TestKeyring_DisabledByDefault(t *testing.T) {
+	k, err := NewKeyring("", nil)
+	assert.NoError(t, err)
+	assert.Nil(t, k)
+
+	stored, err := k.Encrypt("plain note")
+	assert.NoError(t, err)
+	assert.Equal(t, "plain note", stored)
+
+	read, err := k.Decrypt(stored)
+	assert.NoError(t, err)
+	assert.Equal(t, "plain note", read)
+}
+
+func TestKeyring_PlaintextPassesThrough(t *testing.T) {
+	k := newKeyring(t, "a", map[string][]byte{"a": testKey(1)})
+
+	for _, value := range []string{"", "legacy note", "enc: not ours", "enc:a:not base64!"} {
+		read, err := k.Decrypt(value)
+		assert.NoError(t, err)
+		assert.Equal(t, value, read)
+	}
+
+	empty, err := k.Encrypt("")
+	assert.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func TestNewKeyring_RejectsBadConfig(t *testing.T) {
+	_, err := NewKeyring("missing", map[string][]byte{"a": testKey(1)})
+	assert.Error(t, err)
+
+	_, err = NewKeyring("a", map[string][]byte{"a": []byte("too short")})
+	assert.Error(t, err)
+
+	_, err = NewKeyring("", map[string][]byte{"a:b": testKey(1)})
+	assert.Error(t, err)
+}
+
+func TestParseKeys(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString(testKey(7))
+
+	keys, err := ParseKeys([]string{"2026a:" + encoded, " ", " 2026b:" + encoded})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"2026a": testKey(7), "2026b": testKey(7)}, keys)
+
+	for _, entries := range [][]string{
+		{encoded},
+		{":" + encoded},
+		{"a:not-base64!"},
+		{"a:" + encoded, "a:" + encoded},
+	} {
+		_, err := ParseKeys(entries)
+		assert.Error(t, err)
+		if len(entries) == 1 && !strings.Contains(entries[0], ":") {
+			assert.NotContains(t, err.Error(), encoded)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/pagination"
+	"github.com/lumen/backend/internal/repository"
+	apperrors "github.com/lumen/backend/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// ActivityHandler serves the user's activity feed.
+type ActivityHandler struct {
+	repo    repository.ActivityRepository
+	cursors *pagination.Cursors
+}
+
+func NewActivityHandler(repo repository.ActivityRepository, cursors *pagination.Cursors) *ActivityHandler {
+	return &ActivityHandler{repo: repo, cursors: cursors}
+}
+
+// GetFeed lists the user's completed tasks, habit completions, saved daily
+// logs and reached goals, newest first, one cursor page at a time.
+func (h *ActivityHandler) GetFeed(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	page := models.ActivityPage{Limit: models.DefaultActivityPageSize}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > models.MaxActivityPageSize {
+			appErr := apperrors.NewBadRequest(fmt.Sprintf("limit must be between 1 and %d", models.MaxActivityPageSize))
+			respondError(c, appErr)
+			return
+		}
+		page.Limit = limit
+	}
+
+	if token := c.Query("cursor"); token != "" {
+		cursor, err := h.cursors.Decode(userID, token)
+		if err != nil {
+			appErr := apperrors.NewBadRequest("invalid cursor")
+			respondError(c, appErr)
+			return
+		}
+		page.After = &cursor
+	}
+
+	// Fetch one extra row to learn whether another page follows.
+	query := page
+	query.Limit++
+
+	feed, err := h.repo.GetFeed(c.Request.Context(), userID, query)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get activity feed", zap.String("user_id", userID.String()))
+		return
+	}
+
+	feed, meta := pagination.CursorPage(feed, page.Limit, func(last models.ActivityEvent) string {
+		return h.cursors.Encode(userID, pagination.Cursor{Date: last.OccurredAt, ID: last.ID})
+	})
+
+	if feed == nil {
+		feed = []models.ActivityEvent{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       feed,
+		"count":      len(feed),
+		"pagination": meta,
+	})
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockActivityRepository struct {
+	mock.Mock
+}
+
+func (m *MockActivityRepository) GetFeed(ctx context.Context, userID uuid.UUID, page models.ActivityPage) ([]models.ActivityEvent, error) {
+	args := m.Called(ctx, userID, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ActivityEvent), args.Error(1)
+}
+
+var activityCursors = pagination.NewCursors("secret")
+
+func setupActivityRouter(repo *MockActivityRepository, userID uuid.UUID) *gin.Engine {
+	router := setupTestRouter()
+	handler := NewActivityHandler(repo, activityCursors)
+
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	})
+	router.GET("/feed", handler.GetFeed)
+
+	return router
+}
+
+func getFeed(router *gin.Engine, query string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/feed"+query, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+type feedResponse struct {
+	Data       []models.ActivityEvent `json:"data"`
+	Count      int                    `json:"count"`
+	Pagination pagination.Pagination  `json:"pagination"`
+}
+
+func TestGetFeed_NewestFirstWithTypes(t *testing.T) {
+	mockRepo := new(MockActivityRepository)
+	userID := uuid.New()
+	now := time.Date(2026, 10, 16, 18, 0, 0, 0, time.UTC)
+	feed := []models.ActivityEvent{
+		{ID: uuid.New(), Type: models.ActivityGoalReached, Title: "Run a 10k", OccurredAt: now},
+		{ID: uuid.New(), Type: models.ActivityHabitCompleted, Title: "Stretch", OccurredAt: now.Add(-time.Hour)},
+		{ID: uuid.New(), Type: models.ActivityDailyLogSaved, Title: "2026-10-16", OccurredAt: now.Add(-2 * time.Hour)},
+		{ID: uuid.New(), Type: models.ActivityTaskCompleted, Title: "Sign up for the 10k", OccurredAt: now.Add(-3 * time.Hour)},
+	}
+
+	mockRepo.On("GetFeed", mock.Anything, userID, models.ActivityPage{Limit: 5}).Return(feed, nil)
+
+	w := getFeed(setupActivityRouter(mockRepo, userID), "?limit=4")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response feedResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 4, response.Count)
+	assert.False(t, response.Pagination.HasMore)
+
+	types := make([]string, len(response.Data))
+	for i, event := range response.Data {
+		types[i] = event.Type
+		if i > 0 {
+			assert.True(t, event.OccurredAt.Before(response.Data[i-1].OccurredAt), "feed must be newest first")
+		}
+	}
+	assert.Equal(t, []string{"goal.reached", "habit.completed", "daily_log.saved", "task.completed"}, types)
+	assert.Contains(t, w.Body.String(), `"occurred_at":"2026-10-16T18:00:00Z"`)
+}
+
+func TestGetFeed_CursorResumesAfterLastEvent(t *testing.T) {
+	mockRepo := new(MockActivityRepository)
+	userID := uuid.New()
+	now := time.Date(2026, 10, 16, 18, 0, 0, 0, time.UTC)
+	first := models.ActivityEvent{ID: uuid.New(), Type: models.ActivityTaskCompleted, OccurredAt: now}
+	second := models.ActivityEvent{ID: uuid.New(), Type: models.ActivityHabitCompleted, OccurredAt: now.Add(-time.Minute)}
+	third := models.ActivityEvent{ID: uuid.New(), Type: models.ActivityHabitCompleted, OccurredAt: now.Add(-time.Hour)}
+
+	mockRepo.On("GetFeed", mock.Anything, userID, models.ActivityPage{Limit: 3}).
+		Return([]models.ActivityEvent{first, second, third}, nil)
+
+	router := setupActivityRouter(mockRepo, userID)
+	w := getFeed(router, "?limit=2")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response feedResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 2)
+	assert.True(t, response.Pagination.HasMore)
+	if !assert.NotNil(t, response.Pagination.NextCursor) {
+		return
+	}
+
+	after := pagination.Cursor{Date: second.OccurredAt, ID: second.ID}
+	mockRepo.On("GetFeed", mock.Anything, userID, mock.MatchedBy(func(page models.ActivityPage) bool {
+		return page.Limit == 3 && page.After != nil && page.After.ID == after.ID && page.After.Date.Equal(after.Date)
+	})).Return([]models.ActivityEvent{third}, nil)
+
+	w = getFeed(router, "?limit=2&cursor="+*response.Pagination.NextCursor)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []models.ActivityEvent{third}, response.Data)
+	assert.False(t, response.Pagination.HasMore)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetFeed_RejectsInvalidParams(t *testing.T) {
+	userID := uuid.New()
+	otherUsers := activityCursors.Encode(uuid.New(), pagination.Cursor{Date: time.Now(), ID: uuid.New()})
+
+	for _, query := range []string{"?limit=0", "?limit=101", "?limit=ten", "?cursor=garbage", "?cursor=" + otherUsers} {
+		mockRepo := new(MockActivityRepository)
+
+		w := getFeed(setupActivityRouter(mockRepo, userID), query)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		mockRepo.AssertNotCalled(t, "GetFeed", mock.Anything, mock.Anything, mock.Anything)
+	}
+}
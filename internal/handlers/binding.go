@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/lumen/backend/internal/middleware"
+	apperrors "github.com/lumen/backend/pkg/errors"
+)
+
+// normalizer is implemented by requests that clean up free-text fields.
+type normalizer interface {
+	Normalize()
+}
+
+// unknownFieldsError lists JSON fields, as dotted paths, that the request
+// type doesn't define.
+type unknownFieldsError struct {
+	fields []string
+}
+
+func (e *unknownFieldsError) Error() string {
+	return "unknown fields: " + strings.Join(e.fields, ", ")
+}
+
+// bindJSON binds and validates the JSON body into req. Requests implementing
+// normalizer are normalized and then validated again, so that e.g. a title of
+// only whitespace fails its required rule instead of being stored blank. In
+// strict JSON mode, fields req doesn't define are rejected rather than ignored.
+func bindJSON(c *gin.Context, req interface{}) error {
+	if middleware.StrictJSON(c) && c.Request.Body != nil {
+		body, err := io.ReadAll(c.Request.Body)
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		if err == nil {
+			if fields := unknownJSONFields(body, reflect.TypeOf(req)); len(fields) > 0 {
+				return &unknownFieldsError{fields: fields}
+			}
+		}
+	}
+
+	if err := c.ShouldBindJSON(req); err != nil {
+		return err
+	}
+
+	if n, ok := req.(normalizer); ok {
+		n.Normalize()
+		return binding.Validator.ValidateStruct(req)
+	}
+
+	return nil
+}
+
+// bindError maps a bindJSON failure to its response: unknown fields make the
+// request malformed (400), anything else fails validation (422).
+func bindError(err error) *apperrors.AppError {
+	var unknown *unknownFieldsError
+	if errors.As(err, &unknown) {
+		return apperrors.NewBadRequest(unknown.Error())
+	}
+	return apperrors.NewValidationError(err.Error())
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// unknownJSONFields returns the keys in data that no field of typ would
+// decode, descending into nested objects and arrays. Keys match field names
+// case-insensitively, as in encoding/json. Malformed bodies yield nothing;
+// binding reports those.
+func unknownJSONFields(data []byte, typ reflect.Type) []string {
+	var unknown []string
+	collectUnknownFields(data, typ, "", &unknown)
+	return unknown
+}
+
+func collectUnknownFields(data []byte, typ reflect.Type, path string, unknown *[]string) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if reflect.PointerTo(typ).Implements(jsonUnmarshalerType) {
+		return
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		var object map[string]json.RawMessage
+		if json.Unmarshal(data, &object) != nil {
+			return
+		}
+		fields := jsonFields(typ)
+		for _, key := range sortedKeys(object) {
+			field, ok := lookupJSONField(fields, key)
+			if !ok {
+				*unknown = append(*unknown, path+key)
+				continue
+			}
+			collectUnknownFields(object[key], field, path+key+".", unknown)
+		}
+	case reflect.Map:
+		var object map[string]json.RawMessage
+		if json.Unmarshal(data, &object) != nil {
+			return
+		}
+		for _, key := range sortedKeys(object) {
+			collectUnknownFields(object[key], typ.Elem(), path+key+".", unknown)
+		}
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			return
+		}
+		prefix := strings.TrimSuffix(path, ".")
+		for i, item := range items {
+			collectUnknownFields(item, typ.Elem(), fmt.Sprintf("%s[%d].", prefix, i), unknown)
+		}
+	}
+}
+
+// jsonFields maps the JSON names of typ's fields, including those promoted
+// from embedded structs, to their types.
+func jsonFields(typ reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for n, t := range jsonFields(embedded) {
+					if _, exists := fields[n]; !exists {
+						fields[n] = t
+					}
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// lookupJSONField prefers an exact name match and otherwise falls back to a
+// case-insensitive one, as encoding/json does.
+func lookupJSONField(fields map[string]reflect.Type, key string) (reflect.Type, bool) {
+	if typ, ok := fields[key]; ok {
+		return typ, true
+	}
+	for name, typ := range fields {
+		if strings.EqualFold(name, key) {
+			return typ, true
+		}
+	}
+	return nil, false
+}
+
+func sortedKeys(object map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
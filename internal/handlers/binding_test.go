@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lumen/backend/internal/middleware"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupBindingRouter(strict bool) *gin.Engine {
+	router := setupTestRouter()
+	router.Use(middleware.JSONStrictness(strict))
+	router.POST("/tasks", func(c *gin.Context) {
+		var req models.CreateTaskRequest
+		if err := bindJSON(c, &req); err != nil {
+			appErr := bindError(err)
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusCreated, req)
+	})
+	return router
+}
+
+func postJSON(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/tasks", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+const typoTaskBody = `{"title":"Renew passport","horizon":"later","priority":"medium","prority":"high","tittle":"x"}`
+
+func TestBindJSON_LenientIgnoresUnknownFields(t *testing.T) {
+	w := postJSON(setupBindingRouter(false), typoTaskBody)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"priority":"medium"`)
+}
+
+func TestBindJSON_StrictRejectsUnknownFields(t *testing.T) {
+	w := postJSON(setupBindingRouter(true), typoTaskBody)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "unknown fields: prority, tittle")
+}
+
+func TestBindJSON_StrictAcceptsKnownFields(t *testing.T) {
+	w := postJSON(setupBindingRouter(true), `{"Title":"Renew passport","horizon":"later","priority":"medium","tags":["admin"]}`)
+
+	assert.Equal(t, http.StatusCreated, w.Code, "field names match case-insensitively, as in encoding/json")
+}
+
+func TestBindJSON_StrictStillValidates(t *testing.T) {
+	w := postJSON(setupBindingRouter(true), `{"title":"Renew passport","horizon":"soon","priority":"medium"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestUnknownJSONFields_Nested(t *testing.T) {
+	type item struct {
+		Name string `json:"name"`
+	}
+	type embedded struct {
+		Note string `json:"note"`
+	}
+	type request struct {
+		embedded
+		Items   []item     `json:"items"`
+		Due     *time.Time `json:"due"`
+		Skipped string     `json:"-"`
+	}
+
+	body := `{"note":"a","due":"2026-10-16T00:00:00Z","Skipped":"x","items":[{"name":"a"},{"name":"b","size":2}]}`
+
+	assert.Equal(t, []string{"Skipped", "items[1].size"}, unknownJSONFields([]byte(body), reflect.TypeOf(&request{})))
+	assert.Empty(t, unknownJSONFields([]byte(`not json`), reflect.TypeOf(&request{})))
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/repository"
+	apperrors "github.com/lumen/backend/pkg/errors"
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// FeedTokenSigner issues the token embedded in a user's calendar feed URL.
+type FeedTokenSigner interface {
+	Sign(userID uuid.UUID, version int) string
+}
+
+type CalendarHandler struct {
+	tasks  repository.TaskRepository
+	feeds  repository.CalendarFeedRepository
+	tokens FeedTokenSigner
+}
+
+func NewCalendarHandler(tasks repository.TaskRepository, feeds repository.CalendarFeedRepository, tokens FeedTokenSigner) *CalendarHandler {
+	return &CalendarHandler{tasks: tasks, feeds: feeds, tokens: tokens}
+}
+
+// GetFeedURL returns the subscribable feed URL for the authenticated user.
+func (h *CalendarHandler) GetFeedURL(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	version, err := h.feeds.GetFeedTokenVersion(c.Request.Context(), userID)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get calendar feed token version", zap.String("user_id", userID.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"url": h.feedURL(c, userID, version, "/feed-url")})
+}
+
+// RotateFeedURL revokes the user's feed URLs by bumping their token version
+// and returns the new URL.
+func (h *CalendarHandler) RotateFeedURL(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	version, err := h.feeds.RotateFeedToken(c.Request.Context(), userID)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to rotate calendar feed token", zap.String("user_id", userID.String()))
+		return
+	}
+
+	logger.Info("Calendar feed URL rotated", zap.String("user_id", userID.String()), zap.Int("version", version))
+	c.JSON(http.StatusOK, gin.H{"url": h.feedURL(c, userID, version, "/feed-url/rotate")})
+}
+
+// feedURL builds the feed URL next to the requested route, which ends in
+// suffix, with a token for the given version.
+func (h *CalendarHandler) feedURL(c *gin.Context, userID uuid.UUID, version int, suffix string) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	feedURL := url.URL{
+		Scheme:   scheme,
+		Host:     c.Request.Host,
+		Path:     strings.TrimSuffix(c.Request.URL.Path, suffix) + "/feed.ics",
+		RawQuery: url.Values{"token": {h.tokens.Sign(userID, version)}}.Encode(),
+	}
+	return feedURL.String()
+}
+
+// Feed serves the user's dated tasks as an iCalendar document.
+func (h *CalendarHandler) Feed(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	tasks, err := h.tasks.GetByUserID(c.Request.Context(), userID, models.TaskFilter{})
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get tasks for calendar feed", zap.String("user_id", userID.String()))
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(renderICalendar(tasks, time.Now())))
+}
+
+// renderICalendar renders tasks with a due date as VEVENTs. Archived tasks
+// are left out.
+func renderICalendar(tasks []models.Task, now time.Time) string {
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(foldICalLine(s))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//Lumen//Tasks//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:Lumen tasks")
+
+	stamp := now.UTC().Format(icalTimeFormat)
+	for _, task := range tasks {
+		if task.DueDate == nil || task.Status == "archived" {
+			continue
+		}
+
+		due := task.DueDate.UTC()
+		line("BEGIN:VEVENT")
+		line(fmt.Sprintf("UID:%s@lumen", task.ID))
+		line("DTSTAMP:" + stamp)
+		line("DTSTART:" + due.Format(icalTimeFormat))
+		line("DTEND:" + due.Add(30*time.Minute).Format(icalTimeFormat))
+		line("SUMMARY:" + escapeICalText(task.Title))
+		if task.Description != "" {
+			line("DESCRIPTION:" + escapeICalText(task.Description))
+		}
+		if task.Status == "done" {
+			line("X-LUMEN-STATUS:done")
+		}
+		line("END:VEVENT")
+	}
+
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+const icalTimeFormat = "20060102T150405Z"
+
+var icalTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeICalText(s string) string {
+	return icalTextEscaper.Replace(s)
+}
+
+// foldICalLine splits content lines longer than 75 octets (RFC 5545 3.1)
+// without breaking UTF-8 sequences.
+func foldICalLine(s string) string {
+	const limit = 75
+	if len(s) <= limit {
+		return s
+	}
+
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		size := len(string(r))
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type staticFeedSigner string
+
+func (s staticFeedSigner) Sign(userID uuid.UUID, version int) string {
+	return fmt.Sprintf("%s.%d.%s", userID, version, string(s))
+}
+
+// MockCalendarFeedRepository is a mock for repository.CalendarFeedRepository
+type MockCalendarFeedRepository struct {
+	mock.Mock
+}
+
+func (m *MockCalendarFeedRepository) GetFeedTokenVersion(ctx context.Context, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCalendarFeedRepository) RotateFeedToken(ctx context.Context, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func setupCalendarRouter(repo *MockTaskRepository, userID uuid.UUID) *gin.Engine {
+	return setupCalendarRouterWithFeeds(repo, new(MockCalendarFeedRepository), userID)
+}
+
+func setupCalendarRouterWithFeeds(repo *MockTaskRepository, feeds *MockCalendarFeedRepository, userID uuid.UUID) *gin.Engine {
+	router := setupTestRouter()
+	handler := NewCalendarHandler(repo, feeds, staticFeedSigner("sig"))
+
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	})
+	router.GET("/api/v1/calendar/feed-url", handler.GetFeedURL)
+	router.POST("/api/v1/calendar/feed-url/rotate", handler.RotateFeedURL)
+	router.GET("/api/v1/calendar/feed.ics", handler.Feed)
+
+	return router
+}
+
+func TestCalendarFeed_RendersDatedTasks(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	due := time.Date(2025, 11, 14, 9, 0, 0, 0, time.UTC)
+
+	dated := models.Task{ID: uuid.New(), UserID: userID, Title: "Call dentist, reschedule", Status: "todo", DueDate: &due}
+	undated := models.Task{ID: uuid.New(), UserID: userID, Title: "Someday", Status: "todo"}
+	archived := models.Task{ID: uuid.New(), UserID: userID, Title: "Old", Status: "archived", DueDate: &due}
+
+	mockRepo.On("GetByUserID", mock.Anything, userID, models.TaskFilter{}).
+		Return([]models.Task{dated, undated, archived}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/calendar/feed.ics", nil)
+	w := httptest.NewRecorder()
+	setupCalendarRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+
+	body := w.Body.String()
+	assert.True(t, strings.HasPrefix(body, "BEGIN:VCALENDAR\r\n"))
+	assert.Equal(t, 1, strings.Count(body, "BEGIN:VEVENT"))
+	assert.Contains(t, body, "UID:"+dated.ID.String()+"@lumen\r\n")
+	assert.Contains(t, body, "DTSTART:20251114T090000Z\r\n")
+	assert.Contains(t, body, `SUMMARY:Call dentist\, reschedule`+"\r\n")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCalendarFeedURL_EmbedsToken(t *testing.T) {
+	userID := uuid.New()
+	feeds := new(MockCalendarFeedRepository)
+	feeds.On("GetFeedTokenVersion", mock.Anything, userID).Return(3, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/calendar/feed-url", nil)
+	req.Host = "api.lumen.test"
+	req.Header.Set("X-Forwarded-Proto", "https")
+	w := httptest.NewRecorder()
+	setupCalendarRouterWithFeeds(new(MockTaskRepository), feeds, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "https://api.lumen.test/api/v1/calendar/feed.ics?token="+userID.String()+".3.sig")
+}
+
+func TestCalendarFeedURL_RotateBumpsVersion(t *testing.T) {
+	userID := uuid.New()
+	feeds := new(MockCalendarFeedRepository)
+	feeds.On("RotateFeedToken", mock.Anything, userID).Return(4, nil)
+
+	req, _ := http.NewRequest("POST", "/api/v1/calendar/feed-url/rotate", nil)
+	req.Host = "api.lumen.test"
+	w := httptest.NewRecorder()
+	setupCalendarRouterWithFeeds(new(MockTaskRepository), feeds, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "http://api.lumen.test/api/v1/calendar/feed.ics?token="+userID.String()+".4.sig")
+	feeds.AssertExpectations(t)
+}
+
+func TestFoldICalLine(t *testing.T) {
+	long := "SUMMARY:" + strings.Repeat("é", 60)
+	folded := foldICalLine(long)
+
+	for _, part := range strings.Split(folded, "\r\n") {
+		assert.LessOrEqual(t, len(part), 75)
+	}
+	assert.Equal(t, long, strings.ReplaceAll(folded, "\r\n ", ""))
+}
@@ -1,102 +1,135 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/events"
 	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/pagination"
 	"github.com/lumen/backend/internal/repository"
 	apperrors "github.com/lumen/backend/pkg/errors"
 	"github.com/lumen/backend/pkg/logger"
 	"go.uber.org/zap"
 )
 
+// DailyLogHandler serves daily logs; every committed save is published to
+// events. Water intake is stored in glasses and converted to and from each
+// user's preferred unit.
 type DailyLogHandler struct {
-	repo repository.DailyLogRepository
+	repo     repository.DailyLogRepository
+	profiles repository.ProfileRepository
+	cursors  *pagination.Cursors
+	events   events.Publisher
 }
 
-func NewDailyLogHandler(repo repository.DailyLogRepository) *DailyLogHandler {
-	return &DailyLogHandler{repo: repo}
+func NewDailyLogHandler(repo repository.DailyLogRepository, profiles repository.ProfileRepository, cursors *pagination.Cursors, publisher events.Publisher) *DailyLogHandler {
+	return &DailyLogHandler{repo: repo, profiles: profiles, cursors: cursors, events: publisher}
 }
 
 func (h *DailyLogHandler) Create(c *gin.Context) {
 	var req models.CreateDailyLogRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		appErr := apperrors.NewValidationError(err.Error())
-		c.JSON(appErr.StatusCode, appErr)
+	if err := bindJSON(c, &req); err != nil {
+		appErr := bindError(err)
+		respondError(c, appErr)
 		return
 	}
 
 	userID := getUserID(c)
 	if userID == uuid.Nil {
 		appErr := apperrors.NewUnauthorized("user not authenticated")
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
+		return
+	}
+
+	unit, ok := h.waterUnit(c, userID)
+	if !ok {
+		return
+	}
+
+	water, err := unit.ToGlasses(req.WaterIntake)
+	if err != nil {
+		appErr := apperrors.NewValidationError(err.Error())
+		respondError(c, appErr)
 		return
 	}
 
 	log := &models.DailyLog{
 		UserID:             userID,
-		Date:               req.Date,
+		Date:               normalizeDate(req.Date),
 		MorningRoutine:     req.MorningRoutine,
 		EveningRoutine:     req.EveningRoutine,
-		WaterIntake:        req.WaterIntake,
+		WaterIntake:        water,
 		SleepHours:         req.SleepHours,
-		EnergyLevel:        req.EnergyLevel,
-		MoodRating:         req.MoodRating,
-		ProductivityRating: req.ProductivityRating,
+		EnergyLevel:        models.RatingOrUnrated(req.EnergyLevel),
+		MoodRating:         models.RatingOrUnrated(req.MoodRating),
+		ProductivityRating: models.RatingOrUnrated(req.ProductivityRating),
 		Notes:              req.Notes,
+		IsRestDay:          req.IsRestDay,
 	}
 
 	if err := log.Validate(); err != nil {
 		appErr := apperrors.NewValidationError(err.Error())
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
 		return
 	}
 
-	if err := h.repo.Create(c.Request.Context(), log); err != nil {
-		logger.Error("Failed to create daily log", zap.Error(err), zap.String("user_id", userID.String()))
-		appErr := apperrors.NewDatabaseError(err)
-		c.JSON(appErr.StatusCode, appErr)
+	err = h.repo.Create(c.Request.Context(), log)
+	if errors.Is(err, models.ErrDailyLogExists) {
+		appErr := apperrors.NewConflict(err.Error())
+		respondError(c, appErr)
+		return
+	}
+
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to create daily log", zap.String("user_id", userID.String()))
 		return
 	}
 
 	logger.Info("Daily log created", zap.String("log_id", log.ID.String()), zap.String("user_id", userID.String()))
-	c.JSON(http.StatusCreated, log)
+	h.events.Publish(events.DailyLogSaved{Log: *log})
+	c.JSON(http.StatusCreated, log.InUnit(unit))
 }
 
 func (h *DailyLogHandler) GetByDate(c *gin.Context) {
 	dateStr := c.Param("date")
-	date, err := time.Parse("2006-01-02", dateStr)
+	date, err := parseDate(dateStr)
 	if err != nil {
-		appErr := apperrors.NewBadRequest("invalid date format, use YYYY-MM-DD")
-		c.JSON(appErr.StatusCode, appErr)
+		appErr := apperrors.NewBadRequest(err.Error())
+		respondError(c, appErr)
 		return
 	}
 
 	userID := getUserID(c)
 	if userID == uuid.Nil {
 		appErr := apperrors.NewUnauthorized("user not authenticated")
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
 		return
 	}
 
 	log, err := h.repo.GetByDate(c.Request.Context(), userID, date)
 	if err == models.ErrNotFound {
 		appErr := apperrors.NewNotFound("daily log")
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
 		return
 	}
 
 	if err != nil {
-		logger.Error("Failed to get daily log", zap.Error(err), zap.String("date", dateStr))
-		appErr := apperrors.NewDatabaseError(err)
-		c.JSON(appErr.StatusCode, appErr)
+		respondDatabaseError(c, err, "Failed to get daily log", zap.String("date", dateStr))
 		return
 	}
 
-	c.JSON(http.StatusOK, log)
+	unit, ok := h.waterUnit(c, userID)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, log.InUnit(unit))
 }
 
 func (h *DailyLogHandler) GetRange(c *gin.Context) {
@@ -105,46 +138,196 @@ func (h *DailyLogHandler) GetRange(c *gin.Context) {
 
 	if startDateStr == "" || endDateStr == "" {
 		appErr := apperrors.NewBadRequest("start_date and end_date query parameters are required")
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
+		return
+	}
+
+	startDate, err := parseDate(startDateStr)
+	if err != nil {
+		appErr := apperrors.NewBadRequest("start_date: " + err.Error())
+		respondError(c, appErr)
+		return
+	}
+
+	endDate, err := parseDate(endDateStr)
+	if err != nil {
+		appErr := apperrors.NewBadRequest("end_date: " + err.Error())
+		respondError(c, appErr)
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	var page models.DailyLogPage
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > models.MaxDailyLogPageSize {
+			appErr := apperrors.NewBadRequest(fmt.Sprintf("limit must be between 1 and %d", models.MaxDailyLogPageSize))
+			respondError(c, appErr)
+			return
+		}
+		page.Limit = limit
+	}
+
+	if token := c.Query("cursor"); token != "" {
+		cursor, err := h.cursors.Decode(userID, token)
+		if err != nil {
+			appErr := apperrors.NewBadRequest("invalid cursor")
+			respondError(c, appErr)
+			return
+		}
+		page.After = &cursor
+	}
+
+	// Fetch one extra row to learn whether another page follows.
+	query := page
+	if query.Limit > 0 {
+		query.Limit++
+	}
+
+	logs, err := h.repo.GetByDateRange(c.Request.Context(), userID, startDate, endDate, query)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get daily logs", zap.String("user_id", userID.String()))
+		return
+	}
+
+	logs, meta := pagination.CursorPage(logs, page.Limit, func(last models.DailyLog) string {
+		return h.cursors.Encode(userID, pagination.Cursor{Date: last.Date, ID: last.ID})
+	})
+
+	unit, ok := h.waterUnit(c, userID)
+	if !ok {
+		return
+	}
+
+	// next_cursor predates the pagination object and is kept for clients
+	// that still read it.
+	c.JSON(http.StatusOK, gin.H{
+		"data":        models.DailyLogsInUnit(logs, unit),
+		"count":       len(logs),
+		"start_date":  startDateStr,
+		"end_date":    endDateStr,
+		"next_cursor": meta.NextCursor,
+		"pagination":  meta,
+	})
+}
+
+// GetAverages returns averages over the user's logs, all-time or limited by
+// the optional start_date and end_date (inclusive).
+func (h *DailyLogHandler) GetAverages(c *gin.Context) {
+	startDate, ok := optionalDateQuery(c, "start_date")
+	if !ok {
+		return
+	}
+
+	endDate, ok := optionalDateQuery(c, "end_date")
+	if !ok {
+		return
+	}
+
+	if startDate != nil && endDate != nil && endDate.Before(*startDate) {
+		appErr := apperrors.NewBadRequest("end_date must not be before start_date")
+		respondError(c, appErr)
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	averages, err := h.repo.GetAverages(c.Request.Context(), userID, startDate, endDate)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get daily log averages", zap.String("user_id", userID.String()))
+		return
+	}
+
+	unit, ok := h.waterUnit(c, userID)
+	if !ok {
+		return
+	}
+	averages.InWaterUnit(unit)
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       averages,
+		"start_date": c.Query("start_date"),
+		"end_date":   c.Query("end_date"),
+	})
+}
+
+// MaxIncompleteDailyLogDays caps how many days one incomplete-logs request
+// may cover.
+const MaxIncompleteDailyLogDays = 366
+
+// GetIncomplete returns the logs between start_date and end_date (inclusive)
+// that were saved with key ratings left unset, so the user can be prompted to
+// finish them. Days without a log are not included.
+func (h *DailyLogHandler) GetIncomplete(c *gin.Context) {
+	startDateStr := c.Query("start_date")
+	endDateStr := c.Query("end_date")
+
+	if startDateStr == "" || endDateStr == "" {
+		appErr := apperrors.NewBadRequest("start_date and end_date query parameters are required")
+		respondError(c, appErr)
 		return
 	}
 
-	startDate, err := time.Parse("2006-01-02", startDateStr)
+	startDate, err := parseDate(startDateStr)
 	if err != nil {
-		appErr := apperrors.NewBadRequest("invalid start_date format, use YYYY-MM-DD")
-		c.JSON(appErr.StatusCode, appErr)
+		appErr := apperrors.NewBadRequest("start_date: " + err.Error())
+		respondError(c, appErr)
 		return
 	}
 
-	endDate, err := time.Parse("2006-01-02", endDateStr)
+	endDate, err := parseDate(endDateStr)
 	if err != nil {
-		appErr := apperrors.NewBadRequest("invalid end_date format, use YYYY-MM-DD")
-		c.JSON(appErr.StatusCode, appErr)
+		appErr := apperrors.NewBadRequest("end_date: " + err.Error())
+		respondError(c, appErr)
+		return
+	}
+
+	if endDate.Before(startDate) {
+		appErr := apperrors.NewBadRequest("end_date must not be before start_date")
+		respondError(c, appErr)
+		return
+	}
+
+	if endDate.Sub(startDate) >= MaxIncompleteDailyLogDays*24*time.Hour {
+		appErr := apperrors.NewBadRequest(fmt.Sprintf("the range may cover at most %d days", MaxIncompleteDailyLogDays))
+		respondError(c, appErr)
 		return
 	}
 
 	userID := getUserID(c)
 	if userID == uuid.Nil {
 		appErr := apperrors.NewUnauthorized("user not authenticated")
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
 		return
 	}
 
-	logs, err := h.repo.GetByDateRange(c.Request.Context(), userID, startDate, endDate)
+	logs, err := h.repo.GetByDateRange(c.Request.Context(), userID, startDate, endDate, models.DailyLogPage{})
 	if err != nil {
-		logger.Error("Failed to get daily logs", zap.Error(err), zap.String("user_id", userID.String()))
-		appErr := apperrors.NewDatabaseError(err)
-		c.JSON(appErr.StatusCode, appErr)
+		respondDatabaseError(c, err, "Failed to get daily logs", zap.String("user_id", userID.String()))
 		return
 	}
 
-	if logs == nil {
-		logs = []models.DailyLog{}
+	unit, ok := h.waterUnit(c, userID)
+	if !ok {
+		return
 	}
 
+	incomplete := models.IncompleteDailyLogs(logs, unit)
+
 	c.JSON(http.StatusOK, gin.H{
-		"data":       logs,
-		"count":      len(logs),
+		"data":       incomplete,
+		"count":      len(incomplete),
 		"start_date": startDateStr,
 		"end_date":   endDateStr,
 	})
@@ -152,37 +335,41 @@ func (h *DailyLogHandler) GetRange(c *gin.Context) {
 
 func (h *DailyLogHandler) Update(c *gin.Context) {
 	dateStr := c.Param("date")
-	date, err := time.Parse("2006-01-02", dateStr)
+	date, err := parseDate(dateStr)
 	if err != nil {
-		appErr := apperrors.NewBadRequest("invalid date format, use YYYY-MM-DD")
-		c.JSON(appErr.StatusCode, appErr)
+		appErr := apperrors.NewBadRequest(err.Error())
+		respondError(c, appErr)
 		return
 	}
 
 	userID := getUserID(c)
 	if userID == uuid.Nil {
 		appErr := apperrors.NewUnauthorized("user not authenticated")
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
 		return
 	}
 
-	log, err := h.repo.GetByDate(c.Request.Context(), userID, date)
+	log, err := h.repo.GetByDate(repository.WithPrimary(c.Request.Context()), userID, date)
 	if err == models.ErrNotFound {
 		appErr := apperrors.NewNotFound("daily log")
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
 		return
 	}
 
 	if err != nil {
-		appErr := apperrors.NewDatabaseError(err)
-		c.JSON(appErr.StatusCode, appErr)
+		respondDatabaseError(c, err, "Failed to get daily log", zap.String("date", dateStr))
 		return
 	}
 
 	var req models.UpdateDailyLogRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		appErr := apperrors.NewValidationError(err.Error())
-		c.JSON(appErr.StatusCode, appErr)
+	if err := bindJSON(c, &req); err != nil {
+		appErr := bindError(err)
+		respondError(c, appErr)
+		return
+	}
+
+	unit, ok := h.waterUnit(c, userID)
+	if !ok {
 		return
 	}
 
@@ -193,7 +380,12 @@ func (h *DailyLogHandler) Update(c *gin.Context) {
 		log.EveningRoutine = *req.EveningRoutine
 	}
 	if req.WaterIntake != nil {
-		log.WaterIntake = *req.WaterIntake
+		log.WaterIntake, err = unit.ToGlasses(*req.WaterIntake)
+		if err != nil {
+			appErr := apperrors.NewValidationError(err.Error())
+			respondError(c, appErr)
+			return
+		}
 	}
 	if req.SleepHours != nil {
 		log.SleepHours = *req.SleepHours
@@ -210,22 +402,41 @@ func (h *DailyLogHandler) Update(c *gin.Context) {
 	if req.Notes != nil {
 		log.Notes = *req.Notes
 	}
+	if req.IsRestDay != nil {
+		log.IsRestDay = *req.IsRestDay
+	}
 
 	if err := log.Validate(); err != nil {
 		appErr := apperrors.NewValidationError(err.Error())
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
 		return
 	}
 
 	if err := h.repo.Update(c.Request.Context(), log); err != nil {
-		logger.Error("Failed to update daily log", zap.Error(err), zap.String("date", dateStr))
-		appErr := apperrors.NewDatabaseError(err)
-		c.JSON(appErr.StatusCode, appErr)
+		respondDatabaseError(c, err, "Failed to update daily log", zap.String("date", dateStr))
 		return
 	}
 
 	logger.Info("Daily log updated", zap.String("log_id", log.ID.String()), zap.String("user_id", userID.String()))
-	c.JSON(http.StatusOK, log)
+	h.events.Publish(events.DailyLogSaved{Log: *log})
+	c.JSON(http.StatusOK, log.InUnit(unit))
+}
+
+// waterUnit loads the user's preferred water unit, falling back to
+// models.DefaultWaterUnit when none is stored or it is unknown. It responds
+// itself on database errors.
+func (h *DailyLogHandler) waterUnit(c *gin.Context, userID uuid.UUID) (models.WaterUnit, bool) {
+	stored, err := h.profiles.GetWaterUnit(c.Request.Context(), userID)
+	if err != nil && err != models.ErrNotFound {
+		respondDatabaseError(c, err, "Failed to get water unit", zap.String("user_id", userID.String()))
+		return "", false
+	}
+
+	unit, err := models.ParseWaterUnit(string(stored))
+	if err != nil {
+		unit = models.DefaultWaterUnit
+	}
+	return unit, true
 }
 
 func getUserID(c *gin.Context) uuid.UUID {
@@ -248,3 +459,21 @@ func getUserID(c *gin.Context) uuid.UUID {
 
 	return uuid.Nil
 }
+
+// optionalDateQuery parses an optional date query parameter, responding with
+// 400 and returning false when it is present but invalid.
+func optionalDateQuery(c *gin.Context, name string) (*time.Time, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+
+	date, err := parseDate(value)
+	if err != nil {
+		appErr := apperrors.NewBadRequest(name + ": " + err.Error())
+		respondError(c, appErr)
+		return nil, false
+	}
+
+	return &date, true
+}
@@ -0,0 +1,479 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/pagination"
+	"github.com/lumen/backend/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockDailyLogRepository is a mock for daily log repository
+type MockDailyLogRepository struct {
+	mock.Mock
+}
+
+func (m *MockDailyLogRepository) Create(ctx context.Context, log *models.DailyLog) error {
+	args := m.Called(ctx, log)
+	return args.Error(0)
+}
+
+func (m *MockDailyLogRepository) GetByDate(ctx context.Context, userID uuid.UUID, date time.Time) (*models.DailyLog, error) {
+	args := m.Called(ctx, userID, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DailyLog), args.Error(1)
+}
+
+func (m *MockDailyLogRepository) GetByDateRange(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, page models.DailyLogPage) ([]models.DailyLog, error) {
+	args := m.Called(ctx, userID, startDate, endDate, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DailyLog), args.Error(1)
+}
+
+func (m *MockDailyLogRepository) GetAverages(ctx context.Context, userID uuid.UUID, startDate, endDate *time.Time) (*models.DailyLogAverages, error) {
+	args := m.Called(ctx, userID, startDate, endDate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DailyLogAverages), args.Error(1)
+}
+
+func (m *MockDailyLogRepository) Update(ctx context.Context, log *models.DailyLog) error {
+	args := m.Called(ctx, log)
+	return args.Error(0)
+}
+
+func (m *MockDailyLogRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func setupDailyLogRouter(repo *MockDailyLogRepository, cursors *pagination.Cursors, userID uuid.UUID) *gin.Engine {
+	return setupDailyLogRouterWithProfiles(repo, glassesProfile(), cursors, userID)
+}
+
+func setupDailyLogRouterWithProfiles(repo *MockDailyLogRepository, profiles *MockProfileRepository, cursors *pagination.Cursors, userID uuid.UUID) *gin.Engine {
+	router := setupTestRouter()
+	handler := NewDailyLogHandler(repo, profiles, cursors, &recordingPublisher{})
+
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	})
+	router.GET("/daily-log", handler.GetRange)
+	router.POST("/daily-log", handler.Create)
+	router.GET("/daily-log/averages", handler.GetAverages)
+	router.GET("/daily-log/incomplete", handler.GetIncomplete)
+	router.GET("/daily-log/:date", handler.GetByDate)
+	router.PUT("/daily-log/:date", handler.Update)
+
+	return router
+}
+
+func getDailyLogs(router *gin.Engine, query string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/daily-log?start_date=2026-10-01&end_date=2026-10-16"+query, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func dailyLogOn(userID uuid.UUID, day int) models.DailyLog {
+	return models.DailyLog{ID: uuid.New(), UserID: userID, Date: time.Date(2026, 10, day, 0, 0, 0, 0, time.UTC)}
+}
+
+func TestGetDailyLogs_ReturnsSignedNextCursor(t *testing.T) {
+	mockRepo := new(MockDailyLogRepository)
+	cursors := pagination.NewCursors("secret")
+	userID := uuid.New()
+	logs := []models.DailyLog{dailyLogOn(userID, 16), dailyLogOn(userID, 15), dailyLogOn(userID, 14)}
+
+	mockRepo.On("GetByDateRange", mock.Anything, userID, mock.Anything, mock.Anything,
+		models.DailyLogPage{Limit: 3}).Return(logs, nil)
+
+	w := getDailyLogs(setupDailyLogRouter(mockRepo, cursors, userID), "&limit=2")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data       []models.DailyLog     `json:"data"`
+		NextCursor string                `json:"next_cursor"`
+		Pagination pagination.Pagination `json:"pagination"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 2)
+	assert.True(t, response.Pagination.HasMore)
+	if assert.NotNil(t, response.Pagination.NextCursor) {
+		assert.Equal(t, response.NextCursor, *response.Pagination.NextCursor)
+	}
+
+	cursor, err := cursors.Decode(userID, response.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, logs[1].ID, cursor.ID)
+	assert.True(t, cursor.Date.Equal(logs[1].Date))
+}
+
+func TestGetDailyLogs_ResumesFromCursor(t *testing.T) {
+	mockRepo := new(MockDailyLogRepository)
+	cursors := pagination.NewCursors("secret")
+	userID := uuid.New()
+	last := dailyLogOn(userID, 15)
+	after := pagination.Cursor{Date: last.Date, ID: last.ID}
+
+	mockRepo.On("GetByDateRange", mock.Anything, userID, mock.Anything, mock.Anything,
+		models.DailyLogPage{After: &after, Limit: 3}).Return([]models.DailyLog{dailyLogOn(userID, 14)}, nil)
+
+	w := getDailyLogs(setupDailyLogRouter(mockRepo, cursors, userID), "&limit=2&cursor="+cursors.Encode(userID, after))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"next_cursor":null`)
+	assert.Contains(t, w.Body.String(), `"pagination":{"has_more":false}`)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetDailyLogs_RejectsTamperedCursor(t *testing.T) {
+	cursors := pagination.NewCursors("secret")
+	userID := uuid.New()
+	valid := cursors.Encode(userID, pagination.Cursor{Date: time.Now(), ID: uuid.New()})
+
+	for name, token := range map[string]string{
+		"raw keyset":       "2026-10-15," + uuid.New().String(),
+		"altered":          "x" + valid,
+		"other user":       cursors.Encode(uuid.New(), pagination.Cursor{Date: time.Now(), ID: uuid.New()}),
+		"different secret": pagination.NewCursors("other").Encode(userID, pagination.Cursor{Date: time.Now(), ID: uuid.New()}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(MockDailyLogRepository)
+
+			w := getDailyLogs(setupDailyLogRouter(mockRepo, cursors, userID), "&cursor="+token)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), "invalid cursor")
+			mockRepo.AssertNotCalled(t, "GetByDateRange", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func getDailyLogAverages(router *gin.Engine, query string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/daily-log/averages"+query, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetDailyLogAverages_Range(t *testing.T) {
+	mockRepo := new(MockDailyLogRepository)
+	userID := uuid.New()
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	sleep, mood := 7.25, 4.0
+
+	mockRepo.On("GetAverages", mock.Anything, userID, &start, &end).
+		Return(&models.DailyLogAverages{Days: 4, SleepHours: &sleep, MoodRating: &mood}, nil)
+
+	w := getDailyLogAverages(setupDailyLogRouter(mockRepo, nil, userID), "?start_date=2026-10-01&end_date=2026-10-16")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"days":4`)
+	assert.Contains(t, w.Body.String(), `"sleep_hours":7.25`)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetDailyLogAverages_AllTimeAndEmpty(t *testing.T) {
+	mockRepo := new(MockDailyLogRepository)
+	userID := uuid.New()
+
+	mockRepo.On("GetAverages", mock.Anything, userID, (*time.Time)(nil), (*time.Time)(nil)).
+		Return(&models.DailyLogAverages{}, nil)
+
+	w := getDailyLogAverages(setupDailyLogRouter(mockRepo, nil, userID), "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"days":0`)
+	assert.Contains(t, w.Body.String(), `"sleep_hours":null`)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetDailyLogAverages_RejectsInvertedRange(t *testing.T) {
+	mockRepo := new(MockDailyLogRepository)
+
+	w := getDailyLogAverages(setupDailyLogRouter(mockRepo, nil, uuid.New()), "?start_date=2026-10-16&end_date=2026-10-01")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockRepo.AssertNotCalled(t, "GetAverages", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func getIncompleteDailyLogs(router *gin.Engine, query string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/daily-log/incomplete"+query, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetIncompleteDailyLogs_ReturnsOnlyPartialLogs(t *testing.T) {
+	mockRepo := new(MockDailyLogRepository)
+	userID := uuid.New()
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	complete := dailyLogOn(userID, 16)
+	complete.EnergyLevel, complete.MoodRating, complete.ProductivityRating = 4, 4, 3
+	partial := dailyLogOn(userID, 15)
+	partial.EnergyLevel = 2
+	restDay := dailyLogOn(userID, 14)
+	restDay.EnergyLevel, restDay.MoodRating, restDay.IsRestDay = 3, 5, true
+	empty := dailyLogOn(userID, 13)
+
+	mockRepo.On("GetByDateRange", mock.Anything, userID, start, end, models.DailyLogPage{}).
+		Return([]models.DailyLog{complete, partial, restDay, empty}, nil)
+
+	w := getIncompleteDailyLogs(setupDailyLogRouter(mockRepo, nil, userID), "?start_date=2026-10-01&end_date=2026-10-16")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data  []models.IncompleteDailyLog `json:"data"`
+		Count int                         `json:"count"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.Count)
+	if assert.Len(t, response.Data, 2) {
+		assert.Equal(t, partial.ID, response.Data[0].ID)
+		assert.Equal(t, []string{"mood_rating", "productivity_rating"}, response.Data[0].MissingFields)
+		assert.Equal(t, empty.ID, response.Data[1].ID)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetIncompleteDailyLogs_NoneMissing(t *testing.T) {
+	mockRepo := new(MockDailyLogRepository)
+	userID := uuid.New()
+
+	mockRepo.On("GetByDateRange", mock.Anything, userID, mock.Anything, mock.Anything, mock.Anything).
+		Return([]models.DailyLog(nil), nil)
+
+	w := getIncompleteDailyLogs(setupDailyLogRouter(mockRepo, nil, userID), "?start_date=2026-10-01&end_date=2026-10-16")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"data":[]`)
+	assert.Contains(t, w.Body.String(), `"count":0`)
+}
+
+func TestGetIncompleteDailyLogs_RejectsBadRanges(t *testing.T) {
+	for name, query := range map[string]string{
+		"missing end": "?start_date=2026-10-01",
+		"inverted":    "?start_date=2026-10-16&end_date=2026-10-01",
+		"too long":    "?start_date=2025-01-01&end_date=2026-10-16",
+		"bad date":    "?start_date=yesterday&end_date=2026-10-16",
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(MockDailyLogRepository)
+
+			w := getIncompleteDailyLogs(setupDailyLogRouter(mockRepo, nil, uuid.New()), query)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockRepo.AssertNotCalled(t, "GetByDateRange", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func postDailyLog(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/daily-log", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+const validDailyLogBody = `{"date": "2026-10-16T00:00:00Z", "energy_level": 3, "mood_rating": 4, "productivity_rating": 3}`
+
+func TestCreateDailyLog_UniqueViolationIsConflict(t *testing.T) {
+	mockRepo := new(MockDailyLogRepository)
+	userID := uuid.New()
+
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(models.ErrDailyLogExists)
+
+	w := postDailyLog(setupDailyLogRouter(mockRepo, pagination.NewCursors("secret"), userID), validDailyLogBody)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{"code": "CONFLICT", "message": "a daily log already exists for this date"}`, w.Body.String())
+}
+
+func TestCreateDailyLog_OtherDatabaseErrorsStayOpaque(t *testing.T) {
+	mockRepo := new(MockDailyLogRepository)
+	userID := uuid.New()
+
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(errors.New("failed to create daily log: daily_logs lacks its unique (user_id, date) index"))
+
+	w := postDailyLog(setupDailyLogRouter(mockRepo, pagination.NewCursors("secret"), userID), validDailyLogBody)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "daily_logs")
+}
+
+func profileWithWaterUnit(userID uuid.UUID, unit models.WaterUnit) *MockProfileRepository {
+	profiles := new(MockProfileRepository)
+	profiles.On("GetWaterUnit", mock.Anything, userID).Return(string(unit), nil)
+	return profiles
+}
+
+func TestCreateDailyLog_StoresWaterInGlasses(t *testing.T) {
+	mockRepo := new(MockDailyLogRepository)
+	userID := uuid.New()
+
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(log *models.DailyLog) bool {
+		return log.WaterIntake == 6
+	})).Return(nil)
+
+	router := setupDailyLogRouterWithProfiles(mockRepo, profileWithWaterUnit(userID, models.WaterUnitLiters), nil, userID)
+	w := postDailyLog(router, `{"date": "2026-10-16T00:00:00Z", "water_intake": 1.5, "energy_level": 3, "mood_rating": 4, "productivity_rating": 3}`)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"water_intake":1.5`)
+	assert.Contains(t, w.Body.String(), `"water_unit":"liters"`)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateDailyLog_WaterOnlyLeavesRatingsUnrated(t *testing.T) {
+	mockRepo := new(MockDailyLogRepository)
+	userID := uuid.New()
+
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(log *models.DailyLog) bool {
+		return log.WaterIntake == 5 &&
+			log.EnergyLevel == models.Unrated &&
+			log.MoodRating == models.Unrated &&
+			log.ProductivityRating == models.Unrated
+	})).Return(nil)
+
+	router := setupDailyLogRouterWithProfiles(mockRepo, profileWithWaterUnit(userID, models.WaterUnitGlasses), nil, userID)
+	w := postDailyLog(router, `{"date": "2026-10-16T00:00:00Z", "water_intake": 5}`)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"mood_rating":0`)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateDailyLog_RejectsOutOfRangeRatings(t *testing.T) {
+	mockRepo := new(MockDailyLogRepository)
+	userID := uuid.New()
+	router := setupDailyLogRouterWithProfiles(mockRepo, profileWithWaterUnit(userID, models.WaterUnitGlasses), nil, userID)
+
+	for _, body := range []string{
+		`{"date": "2026-10-16T00:00:00Z", "mood_rating": 0}`,
+		`{"date": "2026-10-16T00:00:00Z", "energy_level": 6}`,
+	} {
+		w := postDailyLog(router, body)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, body)
+	}
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateDailyLog_WaterBoundsFollowUserUnit(t *testing.T) {
+	tests := []struct {
+		unit    models.WaterUnit
+		max     string
+		tooMuch string
+	}{
+		{models.WaterUnitGlasses, "20", "21"},
+		{models.WaterUnitLiters, "5", "5.25"},
+		{models.WaterUnitOunces, "169.1", "177.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.unit), func(t *testing.T) {
+			mockRepo := new(MockDailyLogRepository)
+			userID := uuid.New()
+			mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(log *models.DailyLog) bool {
+				return log.WaterIntake == models.MaxWaterIntake
+			})).Return(nil)
+			router := setupDailyLogRouterWithProfiles(mockRepo, profileWithWaterUnit(userID, tt.unit), nil, userID)
+
+			body := `{"date": "2026-10-16T00:00:00Z", "water_intake": %s, "energy_level": 3, "mood_rating": 4, "productivity_rating": 3}`
+			w := postDailyLog(router, fmt.Sprintf(body, tt.max))
+			assert.Equal(t, http.StatusCreated, w.Code)
+
+			w = postDailyLog(router, fmt.Sprintf(body, tt.tooMuch))
+			assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+			w = postDailyLog(router, fmt.Sprintf(body, "-1"))
+			assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+			mockRepo.AssertNumberOfCalls(t, "Create", 1)
+		})
+	}
+}
+
+func TestUpdateDailyLog_ConvertsWaterFromOunces(t *testing.T) {
+	mockRepo := new(MockDailyLogRepository)
+	userID := uuid.New()
+	date := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	existing := &models.DailyLog{ID: uuid.New(), UserID: userID, Date: date, WaterIntake: 2, EnergyLevel: 3, MoodRating: 4, ProductivityRating: 3}
+
+	mockRepo.On("GetByDate", mock.MatchedBy(repository.ReadsPrimary), userID, date).Return(existing, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(log *models.DailyLog) bool {
+		return log.WaterIntake == 8
+	})).Return(nil)
+
+	router := setupDailyLogRouterWithProfiles(mockRepo, profileWithWaterUnit(userID, models.WaterUnitOunces), nil, userID)
+	req, _ := http.NewRequest("PUT", "/daily-log/2026-10-16", bytes.NewBufferString(`{"water_intake": 64}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"water_intake":67.6`)
+	assert.Contains(t, w.Body.String(), `"water_unit":"ounces"`)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetDailyLog_ReturnsWaterInUserUnit(t *testing.T) {
+	mockRepo := new(MockDailyLogRepository)
+	userID := uuid.New()
+	date := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	mockRepo.On("GetByDate", mock.Anything, userID, date).
+		Return(&models.DailyLog{ID: uuid.New(), UserID: userID, Date: date, WaterIntake: 7}, nil)
+
+	router := setupDailyLogRouterWithProfiles(mockRepo, profileWithWaterUnit(userID, models.WaterUnitLiters), nil, userID)
+	req, _ := http.NewRequest("GET", "/daily-log/2026-10-16", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 1.75, response["water_intake"])
+	assert.Equal(t, "liters", response["water_unit"])
+}
+
+func TestGetDailyLogAverages_WaterInUserUnit(t *testing.T) {
+	mockRepo := new(MockDailyLogRepository)
+	userID := uuid.New()
+	water := 8.0
+
+	mockRepo.On("GetAverages", mock.Anything, userID, (*time.Time)(nil), (*time.Time)(nil)).
+		Return(&models.DailyLogAverages{Days: 2, WaterIntake: &water}, nil)
+
+	router := setupDailyLogRouterWithProfiles(mockRepo, profileWithWaterUnit(userID, models.WaterUnitLiters), nil, userID)
+	w := getDailyLogAverages(router, "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"water_intake":2`)
+	assert.Contains(t, w.Body.String(), `"water_unit":"liters"`)
+}
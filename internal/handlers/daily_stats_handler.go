@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	apperrors "github.com/lumen/backend/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// MaxDailyStatsDays caps how many days one stats request may cover.
+const MaxDailyStatsDays = 366
+
+// DailyStats serves per-day stats, precomputed where available.
+type DailyStats interface {
+	Stats(ctx context.Context, userID uuid.UUID, startDate, endDate, now time.Time) ([]models.DailyLogStats, error)
+}
+
+type DailyStatsHandler struct {
+	stats DailyStats
+}
+
+func NewDailyStatsHandler(stats DailyStats) *DailyStatsHandler {
+	return &DailyStatsHandler{stats: stats}
+}
+
+// GetStats returns habit, task and rating figures for each day between
+// start_date and end_date (inclusive).
+func (h *DailyStatsHandler) GetStats(c *gin.Context) {
+	startDateStr := c.Query("start_date")
+	endDateStr := c.Query("end_date")
+
+	if startDateStr == "" || endDateStr == "" {
+		appErr := apperrors.NewBadRequest("start_date and end_date query parameters are required")
+		respondError(c, appErr)
+		return
+	}
+
+	startDate, err := parseDate(startDateStr)
+	if err != nil {
+		appErr := apperrors.NewBadRequest("start_date: " + err.Error())
+		respondError(c, appErr)
+		return
+	}
+
+	endDate, err := parseDate(endDateStr)
+	if err != nil {
+		appErr := apperrors.NewBadRequest("end_date: " + err.Error())
+		respondError(c, appErr)
+		return
+	}
+
+	if endDate.Before(startDate) {
+		appErr := apperrors.NewBadRequest("end_date must not be before start_date")
+		respondError(c, appErr)
+		return
+	}
+
+	if endDate.Sub(startDate) >= MaxDailyStatsDays*24*time.Hour {
+		appErr := apperrors.NewBadRequest(fmt.Sprintf("a stats range may cover at most %d days", MaxDailyStatsDays))
+		respondError(c, appErr)
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	stats, err := h.stats.Stats(c.Request.Context(), userID, startDate, endDate, time.Now())
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get daily stats", zap.String("user_id", userID.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       stats,
+		"count":      len(stats),
+		"start_date": startDateStr,
+		"end_date":   endDateStr,
+	})
+}
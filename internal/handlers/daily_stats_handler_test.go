@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockDailyStats is a mock for the daily stats service
+type MockDailyStats struct {
+	mock.Mock
+}
+
+func (m *MockDailyStats) Stats(ctx context.Context, userID uuid.UUID, startDate, endDate, now time.Time) ([]models.DailyLogStats, error) {
+	args := m.Called(ctx, userID, startDate, endDate, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DailyLogStats), args.Error(1)
+}
+
+func setupDailyStatsRouter(stats *MockDailyStats, userID uuid.UUID) *gin.Engine {
+	router := setupTestRouter()
+	handler := NewDailyStatsHandler(stats)
+
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	})
+	router.GET("/daily-log/stats", handler.GetStats)
+
+	return router
+}
+
+func TestGetDailyStats(t *testing.T) {
+	stats := new(MockDailyStats)
+	userID := uuid.New()
+	start := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+
+	stats.On("Stats", mock.Anything, userID, start, end, mock.Anything).Return([]models.DailyLogStats{
+		{Date: start, HabitsCompleted: 2, HabitsTotal: 3, MoodRating: 4},
+		{Date: end, TasksCompleted: 1, TasksTotal: 2},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/daily-log/stats?start_date=2026-10-14&end_date=2026-10-15", nil)
+	w := httptest.NewRecorder()
+	setupDailyStatsRouter(stats, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"count":2`)
+	assert.Contains(t, w.Body.String(), `"habits_completed":2`)
+	stats.AssertExpectations(t)
+}
+
+func TestGetDailyStats_RejectsInvalidRanges(t *testing.T) {
+	cases := map[string]string{
+		"missing end":   "/daily-log/stats?start_date=2026-10-14",
+		"bad date":      "/daily-log/stats?start_date=14/10/2026&end_date=2026-10-15",
+		"reversed":      "/daily-log/stats?start_date=2026-10-15&end_date=2026-10-14",
+		"too many days": "/daily-log/stats?start_date=2025-01-01&end_date=2026-10-15",
+	}
+
+	for name, url := range cases {
+		t.Run(name, func(t *testing.T) {
+			stats := new(MockDailyStats)
+
+			req, _ := http.NewRequest("GET", url, nil)
+			w := httptest.NewRecorder()
+			setupDailyStatsRouter(stats, uuid.New()).ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			stats.AssertNotCalled(t, "Stats", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// parseDate accepts a calendar date (YYYY-MM-DD) or an RFC3339 timestamp and
+// normalizes it to midnight UTC of the calendar date. Timestamps keep the
+// date as seen in their own offset, so "2025-11-13T23:30:00-05:00" is the 13th.
+func parseDate(value string) (time.Time, error) {
+	if date, err := time.Parse(dateLayout, value); err == nil {
+		return date, nil
+	}
+
+	if ts, err := time.Parse(time.RFC3339, value); err == nil {
+		return normalizeDate(ts), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date %q: accepted formats are YYYY-MM-DD and RFC3339 (e.g. 2025-11-13T09:00:00Z)", value)
+}
+
+// normalizeDate truncates t to midnight UTC of its calendar date.
+func normalizeDate(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
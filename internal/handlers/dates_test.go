@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDate_AcceptedFormats(t *testing.T) {
+	expected := time.Date(2025, 11, 13, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]string{
+		"calendar date":       "2025-11-13",
+		"RFC3339 UTC":         "2025-11-13T09:30:00Z",
+		"RFC3339 offset":      "2025-11-13T23:30:00-05:00",
+		"RFC3339 fractional":  "2025-11-13T09:30:00.123456Z",
+		"RFC3339 early local": "2025-11-13T00:15:00+09:00",
+	}
+
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			date, err := parseDate(input)
+			assert.NoError(t, err)
+			assert.True(t, date.Equal(expected), "got %s", date)
+		})
+	}
+}
+
+func TestParseDate_Invalid(t *testing.T) {
+	for _, input := range []string{"13/11/2025", "2025-13-01", "yesterday", ""} {
+		_, err := parseDate(input)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "YYYY-MM-DD")
+		assert.Contains(t, err.Error(), "RFC3339")
+	}
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/middleware"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/repository"
+	apperrors "github.com/lumen/backend/pkg/errors"
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type FeatureFlagHandler struct {
+	flags *middleware.FeatureFlags
+	repo  repository.FeatureFlagRepository
+}
+
+func NewFeatureFlagHandler(flags *middleware.FeatureFlags, repo repository.FeatureFlagRepository) *FeatureFlagHandler {
+	return &FeatureFlagHandler{flags: flags, repo: repo}
+}
+
+// GetAll returns every feature flag's value for the authenticated user, so
+// clients can show or hide features to match the API.
+func (h *FeatureFlagHandler) GetAll(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.flags.Resolve(c.Request.Context(), userID)})
+}
+
+// SetOverride pins a flag on or off for one user, taking precedence over the
+// global default. It is restricted to service keys.
+func (h *FeatureFlagHandler) SetOverride(c *gin.Context) {
+	userID, flag, ok := h.overrideTarget(c)
+	if !ok {
+		return
+	}
+
+	var req models.SetFeatureFlagRequest
+	if err := bindJSON(c, &req); err != nil {
+		appErr := bindError(err)
+		respondError(c, appErr)
+		return
+	}
+
+	err := h.repo.SetFeatureFlagOverride(c.Request.Context(), userID, flag, *req.Enabled)
+	if err == models.ErrNotFound {
+		appErr := apperrors.NewNotFoundWithID("user", userID)
+		respondError(c, appErr)
+		return
+	}
+
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to set feature flag override", zap.String("user_id", userID.String()), zap.String("flag", flag))
+		return
+	}
+
+	service, _ := middleware.GetServiceIdentity(c)
+	logger.Info("Feature flag override set",
+		zap.String("user_id", userID.String()),
+		zap.String("flag", flag),
+		zap.Bool("enabled", *req.Enabled),
+		zap.String("service", service),
+	)
+	c.JSON(http.StatusOK, gin.H{"data": h.flags.Resolve(c.Request.Context(), userID)})
+}
+
+// DeleteOverride removes a user's override so the global default applies
+// again. It is restricted to service keys.
+func (h *FeatureFlagHandler) DeleteOverride(c *gin.Context) {
+	userID, flag, ok := h.overrideTarget(c)
+	if !ok {
+		return
+	}
+
+	err := h.repo.DeleteFeatureFlagOverride(c.Request.Context(), userID, flag)
+	if err == models.ErrNotFound {
+		appErr := apperrors.NewNotFound("feature flag override")
+		respondError(c, appErr)
+		return
+	}
+
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to delete feature flag override", zap.String("user_id", userID.String()), zap.String("flag", flag))
+		return
+	}
+
+	service, _ := middleware.GetServiceIdentity(c)
+	logger.Info("Feature flag override removed", zap.String("user_id", userID.String()), zap.String("flag", flag), zap.String("service", service))
+	c.JSON(http.StatusOK, gin.H{"data": h.flags.Resolve(c.Request.Context(), userID)})
+}
+
+// overrideTarget reads the user and flag an admin override applies to. Only
+// globally defined flags can be overridden.
+func (h *FeatureFlagHandler) overrideTarget(c *gin.Context) (uuid.UUID, string, bool) {
+	userID, ok := pathUUID(c, "id")
+	if !ok {
+		return uuid.Nil, "", false
+	}
+
+	flag := c.Param("flag")
+	if !h.flags.Defined(flag) {
+		appErr := apperrors.NewValidationError(models.ErrUnknownFeatureFlag.Error() + ": " + flag)
+		respondError(c, appErr)
+		return uuid.Nil, "", false
+	}
+
+	return userID, flag, true
+}
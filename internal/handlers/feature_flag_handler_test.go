@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/middleware"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockFeatureFlagRepository is a mock for feature flag repository
+type MockFeatureFlagRepository struct {
+	mock.Mock
+}
+
+func (m *MockFeatureFlagRepository) GetFeatureFlagOverrides(ctx context.Context, userID uuid.UUID) (map[string]bool, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]bool), args.Error(1)
+}
+
+func (m *MockFeatureFlagRepository) SetFeatureFlagOverride(ctx context.Context, userID uuid.UUID, flag string, enabled bool) error {
+	args := m.Called(ctx, userID, flag, enabled)
+	return args.Error(0)
+}
+
+func (m *MockFeatureFlagRepository) DeleteFeatureFlagOverride(ctx context.Context, userID uuid.UUID, flag string) error {
+	args := m.Called(ctx, userID, flag)
+	return args.Error(0)
+}
+
+var testFeatureFlagDefaults = map[string]bool{"beta_insights": false, "new_calendar": true}
+
+func setupFeatureFlagRouter(repo *MockFeatureFlagRepository, userID uuid.UUID) *gin.Engine {
+	router := setupTestRouter()
+	flags := middleware.NewFeatureFlags(testFeatureFlagDefaults, repo)
+	handler := NewFeatureFlagHandler(flags, repo)
+
+	router.Use(middleware.UUIDParams("id"))
+	router.PUT("/admin/users/:id/feature-flags/:flag", handler.SetOverride)
+	router.DELETE("/admin/users/:id/feature-flags/:flag", handler.DeleteOverride)
+
+	authed := router.Group("", func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	})
+	authed.GET("/feature-flags", handler.GetAll)
+	authed.GET("/insights", flags.Require("beta_insights"), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	return router
+}
+
+func serveFeatureFlags(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetFeatureFlags_OverrideTakesPrecedence(t *testing.T) {
+	mockRepo := new(MockFeatureFlagRepository)
+	userID := uuid.New()
+
+	mockRepo.On("GetFeatureFlagOverrides", mock.Anything, userID).Return(map[string]bool{"beta_insights": true}, nil)
+
+	w := serveFeatureFlags(setupFeatureFlagRouter(mockRepo, userID), "GET", "/feature-flags", "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data": {"beta_insights": true, "new_calendar": true}}`, w.Body.String())
+}
+
+func TestGetFeatureFlags_FallsBackToDefaultsWhenOverridesFail(t *testing.T) {
+	mockRepo := new(MockFeatureFlagRepository)
+	userID := uuid.New()
+
+	mockRepo.On("GetFeatureFlagOverrides", mock.Anything, userID).Return(nil, errors.New("connection refused"))
+
+	w := serveFeatureFlags(setupFeatureFlagRouter(mockRepo, userID), "GET", "/feature-flags", "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data": {"beta_insights": false, "new_calendar": true}}`, w.Body.String())
+}
+
+func TestRequireFeature_GatesOnResolvedFlag(t *testing.T) {
+	betaUser := uuid.New()
+	otherUser := uuid.New()
+
+	mockRepo := new(MockFeatureFlagRepository)
+	mockRepo.On("GetFeatureFlagOverrides", mock.Anything, betaUser).Return(map[string]bool{"beta_insights": true}, nil)
+	mockRepo.On("GetFeatureFlagOverrides", mock.Anything, otherUser).Return(map[string]bool{}, nil)
+
+	w := serveFeatureFlags(setupFeatureFlagRouter(mockRepo, betaUser), "GET", "/insights", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = serveFeatureFlags(setupFeatureFlagRouter(mockRepo, otherUser), "GET", "/insights", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSetFeatureFlagOverride(t *testing.T) {
+	mockRepo := new(MockFeatureFlagRepository)
+	userID := uuid.New()
+
+	mockRepo.On("SetFeatureFlagOverride", mock.Anything, userID, "new_calendar", false).Return(nil)
+	mockRepo.On("GetFeatureFlagOverrides", mock.Anything, userID).Return(map[string]bool{"new_calendar": false}, nil)
+
+	w := serveFeatureFlags(setupFeatureFlagRouter(mockRepo, uuid.Nil), "PUT",
+		"/admin/users/"+userID.String()+"/feature-flags/new_calendar", `{"enabled": false}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data": {"beta_insights": false, "new_calendar": false}}`, w.Body.String())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSetFeatureFlagOverride_RejectsUndefinedFlag(t *testing.T) {
+	mockRepo := new(MockFeatureFlagRepository)
+
+	w := serveFeatureFlags(setupFeatureFlagRouter(mockRepo, uuid.Nil), "PUT",
+		"/admin/users/"+uuid.New().String()+"/feature-flags/typo_flag", `{"enabled": true}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "unknown feature flag: typo_flag")
+	mockRepo.AssertNotCalled(t, "SetFeatureFlagOverride", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSetFeatureFlagOverride_RequiresEnabled(t *testing.T) {
+	mockRepo := new(MockFeatureFlagRepository)
+
+	w := serveFeatureFlags(setupFeatureFlagRouter(mockRepo, uuid.Nil), "PUT",
+		"/admin/users/"+uuid.New().String()+"/feature-flags/beta_insights", `{}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockRepo.AssertNotCalled(t, "SetFeatureFlagOverride", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSetFeatureFlagOverride_UnknownUser(t *testing.T) {
+	mockRepo := new(MockFeatureFlagRepository)
+	userID := uuid.New()
+
+	mockRepo.On("SetFeatureFlagOverride", mock.Anything, userID, "beta_insights", true).Return(models.ErrNotFound)
+
+	w := serveFeatureFlags(setupFeatureFlagRouter(mockRepo, uuid.Nil), "PUT",
+		"/admin/users/"+userID.String()+"/feature-flags/beta_insights", `{"enabled": true}`)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDeleteFeatureFlagOverride_FallsBackToDefault(t *testing.T) {
+	mockRepo := new(MockFeatureFlagRepository)
+	userID := uuid.New()
+
+	mockRepo.On("DeleteFeatureFlagOverride", mock.Anything, userID, "beta_insights").Return(nil)
+	mockRepo.On("GetFeatureFlagOverrides", mock.Anything, userID).Return(map[string]bool{}, nil)
+
+	w := serveFeatureFlags(setupFeatureFlagRouter(mockRepo, uuid.Nil), "DELETE",
+		"/admin/users/"+userID.String()+"/feature-flags/beta_insights", "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data": {"beta_insights": false, "new_calendar": true}}`, w.Body.String())
+}
+
+func TestDeleteFeatureFlagOverride_NoOverride(t *testing.T) {
+	mockRepo := new(MockFeatureFlagRepository)
+	userID := uuid.New()
+
+	mockRepo.On("DeleteFeatureFlagOverride", mock.Anything, userID, "beta_insights").Return(models.ErrNotFound)
+
+	w := serveFeatureFlags(setupFeatureFlagRouter(mockRepo, uuid.Nil), "DELETE",
+		"/admin/users/"+userID.String()+"/feature-flags/beta_insights", "")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
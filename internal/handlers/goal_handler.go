@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/repository"
+	apperrors "github.com/lumen/backend/pkg/errors"
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type GoalHandler struct {
+	repo repository.GoalRepository
+}
+
+func NewGoalHandler(repo repository.GoalRepository) *GoalHandler {
+	return &GoalHandler{repo: repo}
+}
+
+func (h *GoalHandler) AddMilestone(c *gin.Context) {
+	goalID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	var req models.CreateGoalMilestoneRequest
+	if err := bindJSON(c, &req); err != nil {
+		appErr := bindError(err)
+		respondError(c, appErr)
+		return
+	}
+
+	if _, err := h.repo.GetByID(repository.WithPrimary(c.Request.Context()), goalID, userID); err == models.ErrNotFound {
+		appErr := apperrors.NewNotFoundWithID("goal", goalID)
+		respondError(c, appErr)
+		return
+	} else if err != nil {
+		respondDatabaseError(c, err, "Failed to get goal", zap.String("goal_id", goalID.String()))
+		return
+	}
+
+	milestone := &models.GoalMilestone{
+		GoalID: goalID,
+		UserID: userID,
+		Title:  req.Title,
+		Weight: req.Weight,
+	}
+	if milestone.Weight == 0 {
+		milestone.Weight = 1
+	}
+
+	if err := h.repo.CreateMilestone(c.Request.Context(), milestone); err != nil {
+		respondDatabaseError(c, err, "Failed to create goal milestone", zap.String("goal_id", goalID.String()))
+		return
+	}
+
+	logger.Info("Goal milestone created", zap.String("milestone_id", milestone.ID.String()), zap.String("goal_id", goalID.String()))
+	c.JSON(http.StatusCreated, milestone)
+}
+
+func (h *GoalHandler) CompleteMilestone(c *gin.Context) {
+	goalID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	milestoneID, ok := pathUUID(c, "milestone_id")
+	if !ok {
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	milestone, err := h.repo.CompleteMilestone(c.Request.Context(), milestoneID, goalID, userID)
+	if err == models.ErrNotFound {
+		appErr := apperrors.NewNotFound("goal milestone")
+		respondError(c, appErr)
+		return
+	}
+
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to complete goal milestone", zap.String("milestone_id", milestoneID.String()))
+		return
+	}
+
+	logger.Info("Goal milestone completed", zap.String("milestone_id", milestoneID.String()), zap.String("goal_id", goalID.String()))
+	c.JSON(http.StatusOK, milestone)
+}
+
+func (h *GoalHandler) GetProgress(c *gin.Context) {
+	goalID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	if _, err := h.repo.GetByID(ctx, goalID, userID); err == models.ErrNotFound {
+		appErr := apperrors.NewNotFoundWithID("goal", goalID)
+		respondError(c, appErr)
+		return
+	} else if err != nil {
+		respondDatabaseError(c, err, "Failed to get goal", zap.String("goal_id", goalID.String()))
+		return
+	}
+
+	milestones, err := h.repo.GetMilestones(ctx, goalID, userID)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get goal milestones", zap.String("goal_id", goalID.String()))
+		return
+	}
+
+	linked, err := h.repo.GetLinkedProgress(ctx, goalID, userID)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get goal linked progress", zap.String("goal_id", goalID.String()))
+		return
+	}
+
+	if milestones == nil {
+		milestones = []models.GoalMilestone{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"progress":   models.ComputeGoalProgress(milestones, linked),
+		"milestones": milestones,
+	})
+}
+
+// GetItems returns the habits and tasks linked to one of the user's goals.
+func (h *GoalHandler) GetItems(c *gin.Context) {
+	goalID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	if _, err := h.repo.GetByID(ctx, goalID, userID); err == models.ErrNotFound {
+		appErr := apperrors.NewNotFoundWithID("goal", goalID)
+		respondError(c, appErr)
+		return
+	} else if err != nil {
+		respondDatabaseError(c, err, "Failed to get goal", zap.String("goal_id", goalID.String()))
+		return
+	}
+
+	items, err := h.repo.GetItems(ctx, goalID, userID)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get goal items", zap.String("goal_id", goalID.String()))
+		return
+	}
+
+	if items.Habits == nil {
+		items.Habits = []models.Habit{}
+	}
+	if items.Tasks == nil {
+		items.Tasks = []models.Task{}
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// GetForecast projects when the goal will reach 100% from how fast its
+// milestones and tasks have been completed over the last four weeks.
+func (h *GoalHandler) GetForecast(c *gin.Context) {
+	goalID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	if _, err := h.repo.GetByID(ctx, goalID, userID); err == models.ErrNotFound {
+		appErr := apperrors.NewNotFoundWithID("goal", goalID)
+		respondError(c, appErr)
+		return
+	} else if err != nil {
+		respondDatabaseError(c, err, "Failed to get goal", zap.String("goal_id", goalID.String()))
+		return
+	}
+
+	milestones, err := h.repo.GetMilestones(ctx, goalID, userID)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get goal milestones", zap.String("goal_id", goalID.String()))
+		return
+	}
+
+	linked, err := h.repo.GetLinkedProgress(ctx, goalID, userID)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get goal linked progress", zap.String("goal_id", goalID.String()))
+		return
+	}
+
+	now := time.Now()
+	completions, err := h.repo.GetTaskCompletions(ctx, goalID, userID, now.AddDate(0, 0, -models.GoalForecastWindowDays))
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get goal task completions", zap.String("goal_id", goalID.String()))
+		return
+	}
+
+	forecast := models.ForecastGoal(milestones, linked, completions, now)
+	forecast.GoalID = goalID
+	c.JSON(http.StatusOK, forecast)
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/middleware"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockGoalRepository struct {
+	mock.Mock
+}
+
+func (m *MockGoalRepository) GetByID(ctx context.Context, id, userID uuid.UUID) (*models.Goal, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Goal), args.Error(1)
+}
+
+func (m *MockGoalRepository) GetMilestones(ctx context.Context, goalID, userID uuid.UUID) ([]models.GoalMilestone, error) {
+	args := m.Called(ctx, goalID, userID)
+	return args.Get(0).([]models.GoalMilestone), args.Error(1)
+}
+
+func (m *MockGoalRepository) CreateMilestone(ctx context.Context, milestone *models.GoalMilestone) error {
+	args := m.Called(ctx, milestone)
+	return args.Error(0)
+}
+
+func (m *MockGoalRepository) CompleteMilestone(ctx context.Context, id, goalID, userID uuid.UUID) (*models.GoalMilestone, error) {
+	args := m.Called(ctx, id, goalID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GoalMilestone), args.Error(1)
+}
+
+func (m *MockGoalRepository) GetLinkedProgress(ctx context.Context, goalID, userID uuid.UUID) (models.GoalLinkedProgress, error) {
+	args := m.Called(ctx, goalID, userID)
+	return args.Get(0).(models.GoalLinkedProgress), args.Error(1)
+}
+
+func (m *MockGoalRepository) GetItems(ctx context.Context, goalID, userID uuid.UUID) (models.GoalItems, error) {
+	args := m.Called(ctx, goalID, userID)
+	return args.Get(0).(models.GoalItems), args.Error(1)
+}
+
+func (m *MockGoalRepository) GetTaskCompletions(ctx context.Context, goalID, userID uuid.UUID, since time.Time) ([]time.Time, error) {
+	args := m.Called(ctx, goalID, userID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]time.Time), args.Error(1)
+}
+
+func setupGoalRouter(repo *MockGoalRepository, userID uuid.UUID) *gin.Engine {
+	router := setupTestRouter()
+	handler := NewGoalHandler(repo)
+
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	})
+	router.Use(middleware.UUIDParams("id"))
+	router.GET("/goals/:id/items", handler.GetItems)
+	router.GET("/goals/:id/forecast", handler.GetForecast)
+	router.POST("/goals/:id/milestones", handler.AddMilestone)
+
+	return router
+}
+
+func getGoalItems(router *gin.Engine, goalID uuid.UUID) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/goals/"+goalID.String()+"/items", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetGoalItems_ReturnsLinkedItemsOfOwnedGoal(t *testing.T) {
+	mockRepo := new(MockGoalRepository)
+	userID, goalID := uuid.New(), uuid.New()
+	habit := models.Habit{ID: uuid.New(), UserID: userID, Name: "Run"}
+	task := models.Task{ID: uuid.New(), UserID: userID, Title: "Sign up for the 10k"}
+
+	mockRepo.On("GetByID", mock.Anything, goalID, userID).Return(&models.Goal{ID: goalID, UserID: userID}, nil)
+	mockRepo.On("GetItems", mock.Anything, goalID, userID).Return(models.GoalItems{
+		Habits: []models.Habit{habit},
+		Tasks:  []models.Task{task},
+	}, nil)
+
+	w := getGoalItems(setupGoalRouter(mockRepo, userID), goalID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var items models.GoalItems
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &items))
+	if assert.Len(t, items.Habits, 1) {
+		assert.Equal(t, habit.ID, items.Habits[0].ID)
+	}
+	if assert.Len(t, items.Tasks, 1) {
+		assert.Equal(t, task.ID, items.Tasks[0].ID)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetGoalItems_EmptyListsWhenNothingLinked(t *testing.T) {
+	mockRepo := new(MockGoalRepository)
+	userID, goalID := uuid.New(), uuid.New()
+
+	mockRepo.On("GetByID", mock.Anything, goalID, userID).Return(&models.Goal{ID: goalID, UserID: userID}, nil)
+	mockRepo.On("GetItems", mock.Anything, goalID, userID).Return(models.GoalItems{}, nil)
+
+	w := getGoalItems(setupGoalRouter(mockRepo, userID), goalID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"habits":[],"tasks":[]}`, w.Body.String())
+}
+
+func TestGetGoalItems_NotOwnedGoalIsNotFound(t *testing.T) {
+	mockRepo := new(MockGoalRepository)
+	userID, goalID := uuid.New(), uuid.New()
+
+	// The goal exists but belongs to someone else, so the scoped lookup misses.
+	mockRepo.On("GetByID", mock.Anything, goalID, userID).Return(nil, models.ErrNotFound)
+
+	w := getGoalItems(setupGoalRouter(mockRepo, userID), goalID)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockRepo.AssertNotCalled(t, "GetItems", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func getGoalForecast(router *gin.Engine, goalID uuid.UUID) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/goals/"+goalID.String()+"/forecast", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetGoalForecast_SteadyProgressProjectsDate(t *testing.T) {
+	mockRepo := new(MockGoalRepository)
+	userID, goalID := uuid.New(), uuid.New()
+	now := time.Now()
+
+	mockRepo.On("GetByID", mock.Anything, goalID, userID).Return(&models.Goal{ID: goalID, UserID: userID}, nil)
+	mockRepo.On("GetMilestones", mock.Anything, goalID, userID).Return([]models.GoalMilestone{}, nil)
+	mockRepo.On("GetLinkedProgress", mock.Anything, goalID, userID).Return(models.GoalLinkedProgress{TasksCompleted: 2, TasksTotal: 4}, nil)
+	mockRepo.On("GetTaskCompletions", mock.Anything, goalID, userID, mock.MatchedBy(func(since time.Time) bool {
+		return since.Before(now.AddDate(0, 0, -models.GoalForecastWindowDays+1))
+	})).Return([]time.Time{now.AddDate(0, 0, -14), now.AddDate(0, 0, -1)}, nil)
+
+	w := getGoalForecast(setupGoalRouter(mockRepo, userID), goalID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var forecast models.GoalForecast
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &forecast))
+	assert.Equal(t, goalID, forecast.GoalID)
+	assert.Equal(t, models.GoalForecastProjected, forecast.Status)
+	if assert.NotNil(t, forecast.ProjectedDate) {
+		// Half done in four weeks: the other half takes about as long.
+		assert.WithinDuration(t, now.AddDate(0, 0, models.GoalForecastWindowDays), *forecast.ProjectedDate, 48*time.Hour)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetGoalForecast_FlatProgressIsInsufficientData(t *testing.T) {
+	mockRepo := new(MockGoalRepository)
+	userID, goalID := uuid.New(), uuid.New()
+
+	mockRepo.On("GetByID", mock.Anything, goalID, userID).Return(&models.Goal{ID: goalID, UserID: userID}, nil)
+	mockRepo.On("GetMilestones", mock.Anything, goalID, userID).Return([]models.GoalMilestone{}, nil)
+	mockRepo.On("GetLinkedProgress", mock.Anything, goalID, userID).Return(models.GoalLinkedProgress{TasksCompleted: 1, TasksTotal: 4}, nil)
+	mockRepo.On("GetTaskCompletions", mock.Anything, goalID, userID, mock.Anything).Return(nil, nil)
+
+	w := getGoalForecast(setupGoalRouter(mockRepo, userID), goalID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"insufficient_data"`)
+	assert.Contains(t, w.Body.String(), `"projected_date":null`)
+}
+
+func TestGetGoalForecast_NotOwnedGoalIsNotFound(t *testing.T) {
+	mockRepo := new(MockGoalRepository)
+	userID, goalID := uuid.New(), uuid.New()
+
+	mockRepo.On("GetByID", mock.Anything, goalID, userID).Return(nil, models.ErrNotFound)
+
+	w := getGoalForecast(setupGoalRouter(mockRepo, userID), goalID)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockRepo.AssertNotCalled(t, "GetTaskCompletions", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAddGoalMilestone_ChecksGoalOnPrimary(t *testing.T) {
+	mockRepo := new(MockGoalRepository)
+	userID := uuid.New()
+	goal := &models.Goal{ID: uuid.New(), UserID: userID}
+
+	// A goal created a moment ago may not have reached the replica yet.
+	mockRepo.On("GetByID", mock.MatchedBy(repository.ReadsPrimary), goal.ID, userID).Return(goal, nil)
+	mockRepo.On("CreateMilestone", mock.Anything, mock.AnythingOfType("*models.GoalMilestone")).Return(nil)
+
+	req, _ := http.NewRequest("POST", "/goals/"+goal.ID.String()+"/milestones", strings.NewReader(`{"title":"First draft"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	setupGoalRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,384 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/pagination"
+	"github.com/lumen/backend/internal/repository"
+	apperrors "github.com/lumen/backend/pkg/errors"
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// UserTimezones looks up a user's IANA timezone name.
+type UserTimezones interface {
+	GetUserTimezone(ctx context.Context, userID uuid.UUID) (string, error)
+}
+
+// HabitCompletionHandler serves habit completions. Completion history is
+// paged pageSize at a time unless the client asks for another ?limit=.
+type HabitCompletionHandler struct {
+	repo      repository.HabitCompletionRepository
+	habits    repository.HabitRepository
+	timezones UserTimezones
+	cursors   *pagination.Cursors
+	pageSize  int
+}
+
+func NewHabitCompletionHandler(repo repository.HabitCompletionRepository, habits repository.HabitRepository, timezones UserTimezones, cursors *pagination.Cursors, pageSize int) *HabitCompletionHandler {
+	return &HabitCompletionHandler{repo: repo, habits: habits, timezones: timezones, cursors: cursors, pageSize: pageSize}
+}
+
+func (h *HabitCompletionHandler) Delete(c *gin.Context) {
+	completionID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	if err := h.repo.Delete(c.Request.Context(), completionID, userID); err == models.ErrNotFound {
+		appErr := apperrors.NewNotFoundWithID("habit completion", completionID)
+		respondError(c, appErr)
+		return
+	} else if err != nil {
+		respondDatabaseError(c, err, "Failed to delete habit completion", zap.String("completion_id", completionID.String()))
+		return
+	}
+
+	logger.Info("Habit completion deleted", zap.String("completion_id", completionID.String()), zap.String("user_id", userID.String()))
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// Undo removes the habit's most recent completion and responds with the
+// recalculated progress, with periods in the user's timezone, backing a quick
+// "oops" button on the client.
+func (h *HabitCompletionHandler) Undo(c *gin.Context) {
+	habitID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	ctx := repository.WithPrimary(c.Request.Context())
+	habit, err := h.habits.GetByID(ctx, habitID, userID)
+	if err == models.ErrNotFound {
+		appErr := apperrors.NewNotFoundWithID("habit", habitID)
+		respondError(c, appErr)
+		return
+	} else if err != nil {
+		respondDatabaseError(c, err, "Failed to get habit", zap.String("habit_id", habitID.String()))
+		return
+	}
+
+	removed, err := h.repo.DeleteLatest(ctx, habitID, userID)
+	if err == models.ErrNotFound {
+		appErr := apperrors.NewNotFound("habit completion")
+		respondError(c, appErr)
+		return
+	} else if err != nil {
+		respondDatabaseError(c, err, "Failed to undo habit completion", zap.String("habit_id", habitID.String()))
+		return
+	}
+
+	completions, err := h.repo.GetByHabitID(ctx, habitID, userID)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get habit completions", zap.String("habit_id", habitID.String()))
+		return
+	}
+
+	habit.RestDays, err = h.habits.GetRestDays(ctx, userID)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get rest days", zap.String("user_id", userID.String()))
+		return
+	}
+
+	loc, ok := h.userLocation(c, userID)
+	if !ok {
+		return
+	}
+
+	logger.Info("Habit completion undone", zap.String("completion_id", removed.ID.String()), zap.String("user_id", userID.String()))
+	c.JSON(http.StatusOK, gin.H{
+		"removed":  removed,
+		"progress": habit.Progress(completions, time.Now().In(loc)),
+	})
+}
+
+// BestTime reports the hour-of-day distribution of a habit's completions in
+// the user's timezone and the hour they most often complete it. An unknown
+// stored timezone falls back to UTC.
+func (h *HabitCompletionHandler) BestTime(c *gin.Context) {
+	habitID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err := h.habits.GetByID(ctx, habitID, userID); err == models.ErrNotFound {
+		appErr := apperrors.NewNotFoundWithID("habit", habitID)
+		respondError(c, appErr)
+		return
+	} else if err != nil {
+		respondDatabaseError(c, err, "Failed to get habit", zap.String("habit_id", habitID.String()))
+		return
+	}
+
+	loc, ok := h.userLocation(c, userID)
+	if !ok {
+		return
+	}
+
+	counts, err := h.repo.GetHourCounts(ctx, habitID, userID, loc.String())
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get habit completion hours", zap.String("habit_id", habitID.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewHabitBestTime(loc.String(), counts))
+}
+
+// ByWeekday reports the day-of-week distribution of a habit's completions in
+// the user's timezone, optionally limited to the from and to dates
+// (inclusive), which are also read in that timezone. An unknown stored
+// timezone falls back to UTC.
+func (h *HabitCompletionHandler) ByWeekday(c *gin.Context) {
+	habitID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	from, ok := optionalDateQuery(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := optionalDateQuery(c, "to")
+	if !ok {
+		return
+	}
+
+	if from != nil && to != nil && to.Before(*from) {
+		appErr := apperrors.NewBadRequest("to must not be before from")
+		respondError(c, appErr)
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err := h.habits.GetByID(ctx, habitID, userID); err == models.ErrNotFound {
+		appErr := apperrors.NewNotFoundWithID("habit", habitID)
+		respondError(c, appErr)
+		return
+	} else if err != nil {
+		respondDatabaseError(c, err, "Failed to get habit", zap.String("habit_id", habitID.String()))
+		return
+	}
+
+	loc, ok := h.userLocation(c, userID)
+	if !ok {
+		return
+	}
+
+	var start, end *time.Time
+	if from != nil {
+		day, _ := models.DayBounds(*from, loc)
+		start = &day
+	}
+	if to != nil {
+		_, next := models.DayBounds(*to, loc)
+		end = &next
+	}
+
+	counts, err := h.repo.GetWeekdayCounts(ctx, habitID, userID, loc.String(), start, end)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get habit completion weekdays", zap.String("habit_id", habitID.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewHabitWeekdays(loc.String(), counts))
+}
+
+// List returns a habit's completions, newest first, a page at a time. The
+// optional from and to dates (inclusive) are calendar days in the user's
+// timezone.
+func (h *HabitCompletionHandler) List(c *gin.Context) {
+	habitID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	from, ok := optionalDateQuery(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := optionalDateQuery(c, "to")
+	if !ok {
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	page := models.HabitCompletionPage{Limit: h.pageSize}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > models.MaxHabitCompletionPageSize {
+			appErr := apperrors.NewBadRequest(fmt.Sprintf("limit must be between 1 and %d", models.MaxHabitCompletionPageSize))
+			respondError(c, appErr)
+			return
+		}
+		page.Limit = limit
+	}
+
+	if token := c.Query("cursor"); token != "" {
+		cursor, err := h.cursors.Decode(userID, token)
+		if err != nil {
+			appErr := apperrors.NewBadRequest("invalid cursor")
+			respondError(c, appErr)
+			return
+		}
+		page.After = &cursor
+	}
+
+	ctx := c.Request.Context()
+	if _, err := h.habits.GetByID(ctx, habitID, userID); err == models.ErrNotFound {
+		appErr := apperrors.NewNotFoundWithID("habit", habitID)
+		respondError(c, appErr)
+		return
+	} else if err != nil {
+		respondDatabaseError(c, err, "Failed to get habit", zap.String("habit_id", habitID.String()))
+		return
+	}
+
+	if from != nil || to != nil {
+		loc, ok := h.userLocation(c, userID)
+		if !ok {
+			return
+		}
+		if from != nil {
+			start, _ := models.DayBounds(*from, loc)
+			page.From = &start
+		}
+		if to != nil {
+			_, end := models.DayBounds(*to, loc)
+			page.To = &end
+		}
+	}
+
+	// Fetch one extra row to learn whether another page follows.
+	query := page
+	if query.Limit > 0 {
+		query.Limit++
+	}
+
+	completions, err := h.repo.GetPage(ctx, habitID, userID, query)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get habit completions", zap.String("habit_id", habitID.String()))
+		return
+	}
+
+	completions, meta := pagination.CursorPage(completions, page.Limit, func(last models.HabitCompletion) string {
+		return h.cursors.Encode(userID, pagination.Cursor{Date: last.CompletedAt, ID: last.ID})
+	})
+
+	if completions == nil {
+		completions = []models.HabitCompletion{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       completions,
+		"count":      len(completions),
+		"pagination": meta,
+	})
+}
+
+// IncompleteToday lists the habits still due today in the user's timezone
+// that haven't met their target for the current period, for end-of-day
+// nudges.
+func (h *HabitCompletionHandler) IncompleteToday(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	loc, ok := h.userLocation(c, userID)
+	if !ok {
+		return
+	}
+
+	now := time.Now().In(loc)
+	habits, err := h.habits.GetIncompleteOn(c.Request.Context(), userID, now)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get incomplete habits", zap.String("user_id", userID.String()))
+		return
+	}
+
+	if habits == nil {
+		habits = []models.IncompleteHabit{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"date":     now.Format(dateLayout),
+		"timezone": loc.String(),
+		"data":     habits,
+		"count":    len(habits),
+	})
+}
+
+// userLocation loads the user's stored timezone, falling back to UTC when
+// none is stored or it is unknown. It responds itself on database errors.
+func (h *HabitCompletionHandler) userLocation(c *gin.Context, userID uuid.UUID) (*time.Location, bool) {
+	return loadUserLocation(c, h.timezones, userID)
+}
+
+// loadUserLocation is userLocation for any handler holding UserTimezones.
+func loadUserLocation(c *gin.Context, timezones UserTimezones, userID uuid.UUID) (*time.Location, bool) {
+	timezone, err := timezones.GetUserTimezone(c.Request.Context(), userID)
+	if err != nil && err != models.ErrNotFound {
+		respondDatabaseError(c, err, "Failed to get user timezone", zap.String("user_id", userID.String()))
+		return nil, false
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return loc, true
+}
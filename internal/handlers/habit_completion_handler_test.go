@@ -0,0 +1,656 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/middleware"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/pagination"
+	"github.com/lumen/backend/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockHabitCompletionRepository is a mock for habit completion repository
+type MockHabitCompletionRepository struct {
+	mock.Mock
+}
+
+func (m *MockHabitCompletionRepository) Create(ctx context.Context, completion *models.HabitCompletion) error {
+	args := m.Called(ctx, completion)
+	return args.Error(0)
+}
+
+func (m *MockHabitCompletionRepository) GetByHabitID(ctx context.Context, habitID, userID uuid.UUID) ([]models.HabitCompletion, error) {
+	args := m.Called(ctx, habitID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.HabitCompletion), args.Error(1)
+}
+
+func (m *MockHabitCompletionRepository) GetPage(ctx context.Context, habitID, userID uuid.UUID, page models.HabitCompletionPage) ([]models.HabitCompletion, error) {
+	args := m.Called(ctx, habitID, userID, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.HabitCompletion), args.Error(1)
+}
+
+func (m *MockHabitCompletionRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockHabitCompletionRepository) DeleteLatest(ctx context.Context, habitID, userID uuid.UUID) (*models.HabitCompletion, error) {
+	args := m.Called(ctx, habitID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HabitCompletion), args.Error(1)
+}
+
+func (m *MockHabitCompletionRepository) GetWeekdayCounts(ctx context.Context, habitID, userID uuid.UUID, timezone string, from, to *time.Time) ([]models.WeekdayCount, error) {
+	args := m.Called(ctx, habitID, userID, timezone, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WeekdayCount), args.Error(1)
+}
+
+func (m *MockHabitCompletionRepository) GetHourCounts(ctx context.Context, habitID, userID uuid.UUID, timezone string) ([]models.HourCount, error) {
+	args := m.Called(ctx, habitID, userID, timezone)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.HourCount), args.Error(1)
+}
+
+var completionCursors = pagination.NewCursors("secret")
+
+func setupCompletionRouter(repo *MockHabitCompletionRepository, userID uuid.UUID) *gin.Engine {
+	return setupCompletionRouterWithHabits(repo, new(MockHabitRepo), userID)
+}
+
+func setupCompletionRouterWithHabits(repo *MockHabitCompletionRepository, habits *MockHabitRepo, userID uuid.UUID) *gin.Engine {
+	return setupCompletionRouterWithTimezones(repo, habits, new(MockReminderRepository), userID)
+}
+
+func setupCompletionRouterWithTimezones(repo *MockHabitCompletionRepository, habits *MockHabitRepo, timezones *MockReminderRepository, userID uuid.UUID) *gin.Engine {
+	router := setupTestRouter()
+	handler := NewHabitCompletionHandler(repo, habits, timezones, completionCursors, 50)
+
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	})
+	router.Use(middleware.UUIDParams("id"))
+	router.DELETE("/habits/completions/:id", handler.Delete)
+	router.POST("/habits/:id/completions/undo", handler.Undo)
+	router.GET("/habits/:id/best-time", handler.BestTime)
+	router.GET("/habits/:id/by-weekday", handler.ByWeekday)
+	router.GET("/habits/:id/completions", handler.List)
+	router.GET("/habits/incomplete-today", handler.IncompleteToday)
+
+	return router
+}
+
+func TestDeleteHabitCompletion_Success(t *testing.T) {
+	mockRepo := new(MockHabitCompletionRepository)
+	userID := uuid.New()
+	completionID := uuid.New()
+
+	mockRepo.On("Delete", mock.Anything, completionID, userID).Return(nil)
+
+	router := setupCompletionRouter(mockRepo, userID)
+
+	req, _ := http.NewRequest("DELETE", "/habits/completions/"+completionID.String(), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, 204, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeleteHabitCompletion_NotFound(t *testing.T) {
+	mockRepo := new(MockHabitCompletionRepository)
+	userID := uuid.New()
+	completionID := uuid.New()
+
+	mockRepo.On("Delete", mock.Anything, completionID, userID).Return(models.ErrNotFound)
+
+	router := setupCompletionRouter(mockRepo, userID)
+
+	req, _ := http.NewRequest("DELETE", "/habits/completions/"+completionID.String(), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, 404, w.Code)
+	assert.Contains(t, w.Body.String(), "NOT_FOUND")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeleteHabitCompletion_OtherUsersCompletion(t *testing.T) {
+	mockRepo := new(MockHabitCompletionRepository)
+	owner := uuid.New()
+	intruder := uuid.New()
+	completionID := uuid.New()
+
+	// The repository scopes deletes by user, so another user's completion
+	// looks exactly like a missing one.
+	mockRepo.On("Delete", mock.Anything, completionID, owner).Return(nil).Maybe()
+	mockRepo.On("Delete", mock.Anything, completionID, intruder).Return(models.ErrNotFound)
+
+	router := setupCompletionRouter(mockRepo, intruder)
+
+	req, _ := http.NewRequest("DELETE", "/habits/completions/"+completionID.String(), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, 404, w.Code)
+	// The ID in the message is the one the caller sent, so naming it reveals
+	// nothing about the owner's data.
+	assert.Contains(t, w.Body.String(), "habit completion "+completionID.String()+" not found")
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, completionID, owner)
+}
+
+func TestDeleteHabitCompletion_InvalidID(t *testing.T) {
+	mockRepo := new(MockHabitCompletionRepository)
+	router := setupCompletionRouter(mockRepo, uuid.New())
+
+	req, _ := http.NewRequest("DELETE", "/habits/completions/not-a-uuid", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, 400, w.Code)
+	mockRepo.AssertNotCalled(t, "Delete")
+}
+
+func undoCompletion(router *gin.Engine, habitID uuid.UUID) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/habits/"+habitID.String()+"/completions/undo", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestUndoHabitCompletion_RemovesLatestAndRecalculates(t *testing.T) {
+	mockRepo := new(MockHabitCompletionRepository)
+	habitRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	habit := &models.Habit{ID: uuid.New(), UserID: userID, Frequency: "daily", TargetCount: 2}
+
+	now := time.Now()
+	latest := models.HabitCompletion{ID: uuid.New(), HabitID: habit.ID, UserID: userID, CompletedAt: now}
+	earlier := models.HabitCompletion{ID: uuid.New(), HabitID: habit.ID, UserID: userID, CompletedAt: now.Add(-time.Minute)}
+
+	habitRepo.On("GetByID", mock.Anything, habit.ID, userID).Return(habit, nil)
+	mockRepo.On("DeleteLatest", mock.Anything, habit.ID, userID).Return(&latest, nil)
+	mockRepo.On("GetByHabitID", mock.MatchedBy(repository.ReadsPrimary), habit.ID, userID).Return([]models.HabitCompletion{earlier}, nil)
+	habitRepo.On("GetRestDays", mock.Anything, userID).Return(nil, nil)
+	timezones := new(MockReminderRepository)
+	timezones.On("GetUserTimezone", mock.Anything, userID).Return("", models.ErrNotFound)
+
+	w := undoCompletion(setupCompletionRouterWithTimezones(mockRepo, habitRepo, timezones, userID), habit.ID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Removed  models.HabitCompletion `json:"removed"`
+		Progress models.HabitProgress   `json:"progress"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, latest.ID, response.Removed.ID)
+	assert.Equal(t, 1, response.Progress.PeriodCount)
+	assert.Equal(t, 2, response.Progress.PeriodTarget)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUndoHabitCompletion_ProgressInUserTimezone(t *testing.T) {
+	mockRepo := new(MockHabitCompletionRepository)
+	habitRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	habit := &models.Habit{ID: uuid.New(), UserID: userID, Frequency: "daily", TargetCount: 2}
+
+	// Kiritimati is UTC+14, so the user's day never lines up with the UTC
+	// one. The remaining completion is logged on the user's today but another
+	// UTC day, or on the user's yesterday but today in UTC.
+	loc, err := time.LoadLocation("Pacific/Kiritimati")
+	assert.NoError(t, err)
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	earlierAt, wantCount := today.Add(time.Minute), 1
+	if today.UTC().Day() == now.UTC().Day() {
+		earlierAt, wantCount = today.Add(-time.Minute), 0
+	}
+	latest := models.HabitCompletion{ID: uuid.New(), HabitID: habit.ID, UserID: userID, CompletedAt: now}
+	earlier := models.HabitCompletion{ID: uuid.New(), HabitID: habit.ID, UserID: userID, CompletedAt: earlierAt}
+
+	habitRepo.On("GetByID", mock.Anything, habit.ID, userID).Return(habit, nil)
+	mockRepo.On("DeleteLatest", mock.Anything, habit.ID, userID).Return(&latest, nil)
+	mockRepo.On("GetByHabitID", mock.Anything, habit.ID, userID).Return([]models.HabitCompletion{earlier}, nil)
+	habitRepo.On("GetRestDays", mock.Anything, userID).Return(nil, nil)
+	timezones := new(MockReminderRepository)
+	timezones.On("GetUserTimezone", mock.Anything, userID).Return("Pacific/Kiritimati", nil)
+
+	w := undoCompletion(setupCompletionRouterWithTimezones(mockRepo, habitRepo, timezones, userID), habit.ID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Progress models.HabitProgress `json:"progress"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, wantCount, response.Progress.PeriodCount)
+}
+
+func TestUndoHabitCompletion_NoCompletions(t *testing.T) {
+	mockRepo := new(MockHabitCompletionRepository)
+	habitRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	habit := &models.Habit{ID: uuid.New(), UserID: userID, Frequency: "daily", TargetCount: 1}
+
+	habitRepo.On("GetByID", mock.Anything, habit.ID, userID).Return(habit, nil)
+	mockRepo.On("DeleteLatest", mock.Anything, habit.ID, userID).Return(nil, models.ErrNotFound)
+
+	w := undoCompletion(setupCompletionRouterWithHabits(mockRepo, habitRepo, userID), habit.ID)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "habit completion not found")
+	mockRepo.AssertNotCalled(t, "GetByHabitID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUndoHabitCompletion_UnknownHabit(t *testing.T) {
+	mockRepo := new(MockHabitCompletionRepository)
+	habitRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	habitID := uuid.New()
+
+	habitRepo.On("GetByID", mock.Anything, habitID, userID).Return(nil, models.ErrNotFound)
+
+	w := undoCompletion(setupCompletionRouterWithHabits(mockRepo, habitRepo, userID), habitID)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "habit "+habitID.String()+" not found")
+	mockRepo.AssertNotCalled(t, "DeleteLatest", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func getBestTime(router *gin.Engine, habitID uuid.UUID) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/habits/"+habitID.String()+"/best-time", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestHabitBestTime_BucketsInUserTimezone(t *testing.T) {
+	// The same completions land in different local hours depending on the
+	// user's timezone, so the handler must bucket in the zone it looked up.
+	best := map[string]int{"America/New_York": 6, "Asia/Tokyo": 19}
+	for timezone, counts := range map[string][]models.HourCount{
+		"America/New_York": {{Hour: 6, Count: 4}, {Hour: 20, Count: 1}},
+		"Asia/Tokyo":       {{Hour: 9, Count: 1}, {Hour: 19, Count: 4}},
+	} {
+		t.Run(timezone, func(t *testing.T) {
+			mockRepo := new(MockHabitCompletionRepository)
+			habitRepo := new(MockHabitRepo)
+			timezones := new(MockReminderRepository)
+			userID := uuid.New()
+			habit := &models.Habit{ID: uuid.New(), UserID: userID}
+
+			habitRepo.On("GetByID", mock.Anything, habit.ID, userID).Return(habit, nil)
+			timezones.On("GetUserTimezone", mock.Anything, userID).Return(timezone, nil)
+			mockRepo.On("GetHourCounts", mock.Anything, habit.ID, userID, timezone).Return(counts, nil)
+
+			w := getBestTime(setupCompletionRouterWithTimezones(mockRepo, habitRepo, timezones, userID), habit.ID)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response models.HabitBestTime
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, timezone, response.Timezone)
+			assert.Equal(t, 5, response.Completions)
+			for _, count := range counts {
+				assert.Equal(t, count.Count, response.Distribution[count.Hour])
+			}
+			if assert.NotNil(t, response.BestHour) {
+				assert.Equal(t, best[timezone], *response.BestHour)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHabitBestTime_UnknownTimezoneFallsBackToUTC(t *testing.T) {
+	mockRepo := new(MockHabitCompletionRepository)
+	habitRepo := new(MockHabitRepo)
+	timezones := new(MockReminderRepository)
+	userID := uuid.New()
+	habit := &models.Habit{ID: uuid.New(), UserID: userID}
+
+	habitRepo.On("GetByID", mock.Anything, habit.ID, userID).Return(habit, nil)
+	timezones.On("GetUserTimezone", mock.Anything, userID).Return("Mars/Olympus_Mons", nil)
+	mockRepo.On("GetHourCounts", mock.Anything, habit.ID, userID, "UTC").Return(nil, nil)
+
+	w := getBestTime(setupCompletionRouterWithTimezones(mockRepo, habitRepo, timezones, userID), habit.ID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"timezone":"UTC"`)
+	assert.Contains(t, w.Body.String(), `"best_hour":null`)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestHabitBestTime_UnknownHabit(t *testing.T) {
+	mockRepo := new(MockHabitCompletionRepository)
+	habitRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	habitID := uuid.New()
+
+	habitRepo.On("GetByID", mock.Anything, habitID, userID).Return(nil, models.ErrNotFound)
+
+	w := getBestTime(setupCompletionRouterWithHabits(mockRepo, habitRepo, userID), habitID)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockRepo.AssertNotCalled(t, "GetHourCounts", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func getByWeekday(router *gin.Engine, habitID uuid.UUID, query string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodGet, "/habits/"+habitID.String()+"/by-weekday?"+query, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestHabitByWeekday_RangeCrossesTimezoneBoundary(t *testing.T) {
+	// Monday 2026-10-12 in Tokyo starts at 15:00 UTC on Sunday, and in
+	// Los Angeles at 07:00 UTC on Monday; the week's bounds and the bucketing
+	// zone must both follow the user's timezone.
+	for timezone, bounds := range map[string][2]time.Time{
+		"Asia/Tokyo": {
+			time.Date(2026, 10, 11, 15, 0, 0, 0, time.UTC),
+			time.Date(2026, 10, 18, 15, 0, 0, 0, time.UTC),
+		},
+		"America/Los_Angeles": {
+			time.Date(2026, 10, 12, 7, 0, 0, 0, time.UTC),
+			time.Date(2026, 10, 19, 7, 0, 0, 0, time.UTC),
+		},
+	} {
+		t.Run(timezone, func(t *testing.T) {
+			mockRepo := new(MockHabitCompletionRepository)
+			habitRepo := new(MockHabitRepo)
+			timezones := new(MockReminderRepository)
+			userID := uuid.New()
+			habit := &models.Habit{ID: uuid.New(), UserID: userID}
+
+			habitRepo.On("GetByID", mock.Anything, habit.ID, userID).Return(habit, nil)
+			timezones.On("GetUserTimezone", mock.Anything, userID).Return(timezone, nil)
+			mockRepo.On("GetWeekdayCounts", mock.Anything, habit.ID, userID, timezone,
+				mock.MatchedBy(func(from *time.Time) bool { return from != nil && from.Equal(bounds[0]) }),
+				mock.MatchedBy(func(to *time.Time) bool { return to != nil && to.Equal(bounds[1]) }),
+			).Return([]models.WeekdayCount{{Weekday: 1, Count: 3}, {Weekday: 0, Count: 1}}, nil)
+
+			w := getByWeekday(setupCompletionRouterWithTimezones(mockRepo, habitRepo, timezones, userID), habit.ID,
+				"from=2026-10-12&to=2026-10-18")
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response models.HabitWeekdays
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, timezone, response.Timezone)
+			assert.Equal(t, [7]int{1, 3, 0, 0, 0, 0, 0}, response.Distribution)
+			assert.Equal(t, 4, response.Completions)
+			if assert.NotNil(t, response.BestWeekday) {
+				assert.Equal(t, 1, *response.BestWeekday)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHabitByWeekday_AllTime(t *testing.T) {
+	mockRepo := new(MockHabitCompletionRepository)
+	habitRepo := new(MockHabitRepo)
+	timezones := new(MockReminderRepository)
+	userID := uuid.New()
+	habit := &models.Habit{ID: uuid.New(), UserID: userID}
+
+	habitRepo.On("GetByID", mock.Anything, habit.ID, userID).Return(habit, nil)
+	timezones.On("GetUserTimezone", mock.Anything, userID).Return("UTC", nil)
+	mockRepo.On("GetWeekdayCounts", mock.Anything, habit.ID, userID, "UTC", (*time.Time)(nil), (*time.Time)(nil)).Return(nil, nil)
+
+	w := getByWeekday(setupCompletionRouterWithTimezones(mockRepo, habitRepo, timezones, userID), habit.ID, "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"distribution":[0,0,0,0,0,0,0]`)
+	assert.Contains(t, w.Body.String(), `"best_weekday":null`)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestHabitByWeekday_RejectsInvertedRange(t *testing.T) {
+	mockRepo := new(MockHabitCompletionRepository)
+
+	w := getByWeekday(setupCompletionRouter(mockRepo, uuid.New()), uuid.New(), "from=2026-10-18&to=2026-10-12")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockRepo.AssertNotCalled(t, "GetWeekdayCounts", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHabitByWeekday_UnknownHabit(t *testing.T) {
+	mockRepo := new(MockHabitCompletionRepository)
+	habitRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	habitID := uuid.New()
+
+	habitRepo.On("GetByID", mock.Anything, habitID, userID).Return(nil, models.ErrNotFound)
+
+	w := getByWeekday(setupCompletionRouterWithHabits(mockRepo, habitRepo, userID), habitID, "")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockRepo.AssertNotCalled(t, "GetWeekdayCounts", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func getHabitCompletions(router *gin.Engine, habitID uuid.UUID, query string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodGet, "/habits/"+habitID.String()+"/completions?"+query, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+type completionPageResponse struct {
+	Data       []models.HabitCompletion `json:"data"`
+	Count      int                      `json:"count"`
+	Pagination pagination.Pagination    `json:"pagination"`
+}
+
+func TestListHabitCompletions_PagesThroughHistory(t *testing.T) {
+	mockRepo := new(MockHabitCompletionRepository)
+	habitRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	habit := &models.Habit{ID: uuid.New(), UserID: userID}
+
+	// Two completions share a timestamp, so the page boundary falls between
+	// them and only the id tiebreak keeps the second page from repeating one.
+	at := time.Date(2026, 10, 16, 7, 30, 0, 0, time.UTC)
+	completions := []models.HabitCompletion{
+		{ID: uuid.New(), HabitID: habit.ID, UserID: userID, CompletedAt: at},
+		{ID: uuid.New(), HabitID: habit.ID, UserID: userID, CompletedAt: at},
+		{ID: uuid.New(), HabitID: habit.ID, UserID: userID, CompletedAt: at.Add(-24 * time.Hour)},
+	}
+	after := pagination.Cursor{Date: completions[0].CompletedAt, ID: completions[0].ID}
+
+	habitRepo.On("GetByID", mock.Anything, habit.ID, userID).Return(habit, nil)
+	mockRepo.On("GetPage", mock.Anything, habit.ID, userID,
+		models.HabitCompletionPage{Limit: 2}).Return(completions[:2], nil)
+	mockRepo.On("GetPage", mock.Anything, habit.ID, userID,
+		models.HabitCompletionPage{After: &after, Limit: 2}).Return(completions[1:], nil)
+
+	router := setupCompletionRouterWithHabits(mockRepo, habitRepo, userID)
+
+	var first completionPageResponse
+	w := getHabitCompletions(router, habit.ID, "limit=1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &first))
+	assert.Equal(t, []uuid.UUID{completions[0].ID}, completionIDs(first.Data))
+	assert.True(t, first.Pagination.HasMore)
+
+	var seen []uuid.UUID
+	seen = append(seen, completionIDs(first.Data)...)
+	if assert.NotNil(t, first.Pagination.NextCursor) {
+		var second completionPageResponse
+		w = getHabitCompletions(router, habit.ID, "limit=1&cursor="+*first.Pagination.NextCursor)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &second))
+		assert.Equal(t, 1, second.Count)
+		assert.True(t, second.Pagination.HasMore)
+		seen = append(seen, completionIDs(second.Data)...)
+	}
+
+	assert.Equal(t, []uuid.UUID{completions[0].ID, completions[1].ID}, seen)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestListHabitCompletions_DefaultsToConfiguredPageSize(t *testing.T) {
+	mockRepo := new(MockHabitCompletionRepository)
+	habitRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	habit := &models.Habit{ID: uuid.New(), UserID: userID}
+
+	habitRepo.On("GetByID", mock.Anything, habit.ID, userID).Return(habit, nil)
+	mockRepo.On("GetPage", mock.Anything, habit.ID, userID,
+		models.HabitCompletionPage{Limit: 51}).Return(nil, nil)
+
+	w := getHabitCompletions(setupCompletionRouterWithHabits(mockRepo, habitRepo, userID), habit.ID, "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"data":[]`)
+	assert.Contains(t, w.Body.String(), `"pagination":{"has_more":false}`)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestListHabitCompletions_DateFilterNarrowsToUserDays(t *testing.T) {
+	mockRepo := new(MockHabitCompletionRepository)
+	habitRepo := new(MockHabitRepo)
+	timezones := new(MockReminderRepository)
+	userID := uuid.New()
+	habit := &models.Habit{ID: uuid.New(), UserID: userID}
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+
+	// from and to are inclusive local days: midnight Oct 10 up to (not
+	// including) midnight Oct 13 in the user's timezone.
+	from := time.Date(2026, 10, 10, 0, 0, 0, 0, tokyo)
+	to := time.Date(2026, 10, 13, 0, 0, 0, 0, tokyo)
+	inRange := models.HabitCompletion{ID: uuid.New(), HabitID: habit.ID, UserID: userID, CompletedAt: from.Add(time.Hour)}
+
+	habitRepo.On("GetByID", mock.Anything, habit.ID, userID).Return(habit, nil)
+	timezones.On("GetUserTimezone", mock.Anything, userID).Return("Asia/Tokyo", nil)
+	mockRepo.On("GetPage", mock.Anything, habit.ID, userID, mock.MatchedBy(func(page models.HabitCompletionPage) bool {
+		return page.From != nil && page.From.Equal(from) && page.To != nil && page.To.Equal(to)
+	})).Return([]models.HabitCompletion{inRange}, nil)
+
+	w := getHabitCompletions(setupCompletionRouterWithTimezones(mockRepo, habitRepo, timezones, userID),
+		habit.ID, "from=2026-10-10&to=2026-10-12")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response completionPageResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []uuid.UUID{inRange.ID}, completionIDs(response.Data))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestListHabitCompletions_RejectsBadQuery(t *testing.T) {
+	for name, query := range map[string]string{
+		"limit too large": "limit=501",
+		"limit zero":      "limit=0",
+		"bad date":        "from=10/10/2026",
+		"bad cursor":      "cursor=nope",
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(MockHabitCompletionRepository)
+			w := getHabitCompletions(setupCompletionRouter(mockRepo, uuid.New()), uuid.New(), query)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockRepo.AssertNotCalled(t, "GetPage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestListHabitCompletions_UnknownHabit(t *testing.T) {
+	mockRepo := new(MockHabitCompletionRepository)
+	habitRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	habitID := uuid.New()
+
+	habitRepo.On("GetByID", mock.Anything, habitID, userID).Return(nil, models.ErrNotFound)
+
+	w := getHabitCompletions(setupCompletionRouterWithHabits(mockRepo, habitRepo, userID), habitID, "")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockRepo.AssertNotCalled(t, "GetPage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func completionIDs(completions []models.HabitCompletion) []uuid.UUID {
+	ids := make([]uuid.UUID, len(completions))
+	for i, completion := range completions {
+		ids[i] = completion.ID
+	}
+	return ids
+}
+
+func TestIncompleteToday_UsesUserTimezone(t *testing.T) {
+	mockRepo := new(MockHabitCompletionRepository)
+	habitRepo := new(MockHabitRepo)
+	timezones := new(MockReminderRepository)
+	userID := uuid.New()
+	habit := models.Habit{ID: uuid.New(), UserID: userID, Name: "Water", Frequency: "daily", TargetCount: 8, IsActive: true}
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+
+	timezones.On("GetUserTimezone", mock.Anything, userID).Return("Asia/Tokyo", nil)
+	habitRepo.On("GetIncompleteOn", mock.Anything, userID, mock.MatchedBy(func(date time.Time) bool {
+		// Periods are bounded in the date's location, so it must be Tokyo's.
+		return date.Location().String() == tokyo.String()
+	})).Return([]models.IncompleteHabit{{Habit: habit, PeriodCount: 5, PeriodTarget: 8, Remaining: 3}}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/habits/incomplete-today", nil)
+	w := httptest.NewRecorder()
+	setupCompletionRouterWithTimezones(mockRepo, habitRepo, timezones, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Date     string                   `json:"date"`
+		Timezone string                   `json:"timezone"`
+		Data     []models.IncompleteHabit `json:"data"`
+		Count    int                      `json:"count"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, time.Now().In(tokyo).Format(time.DateOnly), response.Date)
+	assert.Equal(t, "Asia/Tokyo", response.Timezone)
+	if assert.Len(t, response.Data, 1) {
+		assert.Equal(t, habit.ID, response.Data[0].ID)
+		assert.Equal(t, 3.0, response.Data[0].Remaining)
+	}
+	habitRepo.AssertExpectations(t)
+}
+
+func TestIncompleteToday_NothingLeft(t *testing.T) {
+	mockRepo := new(MockHabitCompletionRepository)
+	habitRepo := new(MockHabitRepo)
+	timezones := new(MockReminderRepository)
+	userID := uuid.New()
+
+	timezones.On("GetUserTimezone", mock.Anything, userID).Return("", models.ErrNotFound)
+	habitRepo.On("GetIncompleteOn", mock.Anything, userID, mock.Anything).Return(nil, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/habits/incomplete-today", nil)
+	w := httptest.NewRecorder()
+	setupCompletionRouterWithTimezones(mockRepo, habitRepo, timezones, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"timezone":"UTC"`)
+	assert.Contains(t, w.Body.String(), `"data":[]`)
+}
@@ -1,10 +1,16 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/events"
+	"github.com/lumen/backend/internal/middleware"
 	"github.com/lumen/backend/internal/models"
 	"github.com/lumen/backend/internal/repository"
 	apperrors "github.com/lumen/backend/pkg/errors"
@@ -12,52 +18,62 @@ import (
 	"go.uber.org/zap"
 )
 
+// HabitHandler serves habit CRUD; every committed change is published to
+// events.
 type HabitHandler struct {
-	repo repository.HabitRepository
+	repo   repository.HabitRepository
+	events events.Publisher
 }
 
-func NewHabitHandler(repo repository.HabitRepository) *HabitHandler {
-	return &HabitHandler{repo: repo}
+func NewHabitHandler(repo repository.HabitRepository, publisher events.Publisher) *HabitHandler {
+	return &HabitHandler{repo: repo, events: publisher}
 }
 
 func (h *HabitHandler) Create(c *gin.Context) {
 	var req models.CreateHabitRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		appErr := apperrors.NewValidationError(err.Error())
-		c.JSON(appErr.StatusCode, appErr)
+	if err := bindJSON(c, &req); err != nil {
+		appErr := bindError(err)
+		respondError(c, appErr)
 		return
 	}
 
 	userID := getUserID(c)
 	if userID == uuid.Nil {
 		appErr := apperrors.NewUnauthorized("user not authenticated")
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
 		return
 	}
 
 	habit := &models.Habit{
-		UserID:      userID,
-		Name:        req.Name,
-		Color:       req.Color,
-		Icon:        req.Icon,
-		Frequency:   req.Frequency,
-		TargetCount: req.TargetCount,
+		UserID:        userID,
+		Name:          req.Name,
+		Color:         req.Color,
+		Icon:          req.Icon,
+		Frequency:     req.Frequency,
+		TargetCount:   req.TargetCount,
+		TargetAmount:  req.TargetAmount,
+		ReminderTimes: req.ReminderTimes,
 	}
 
 	if err := habit.Validate(); err != nil {
 		appErr := apperrors.NewValidationError(err.Error())
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
+		return
+	}
+
+	if err := middleware.ValidationProfile(c).ValidateIcon(habit.Icon); err != nil {
+		appErr := apperrors.NewValidationError(err.Error())
+		respondError(c, appErr)
 		return
 	}
 
 	if err := h.repo.Create(c.Request.Context(), habit); err != nil {
-		logger.Error("Failed to create habit", zap.Error(err), zap.String("user_id", userID.String()))
-		appErr := apperrors.NewDatabaseError(err)
-		c.JSON(appErr.StatusCode, appErr)
+		respondDatabaseError(c, err, "Failed to create habit", zap.String("user_id", userID.String()))
 		return
 	}
 
 	logger.Info("Habit created", zap.String("habit_id", habit.ID.String()), zap.String("user_id", userID.String()))
+	h.events.Publish(events.HabitCreated{Habit: *habit})
 	c.JSON(http.StatusCreated, habit)
 }
 
@@ -65,15 +81,20 @@ func (h *HabitHandler) GetAll(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == uuid.Nil {
 		appErr := apperrors.NewUnauthorized("user not authenticated")
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
 		return
 	}
 
-	habits, err := h.repo.GetByUserID(c.Request.Context(), userID)
+	sortBy := c.DefaultQuery("sort_by", models.HabitSortPosition)
+	if err := models.ValidateHabitSort(sortBy); err != nil {
+		appErr := apperrors.NewBadRequest(err.Error())
+		respondError(c, appErr)
+		return
+	}
+
+	habits, err := h.repo.GetByUserID(c.Request.Context(), userID, sortBy)
 	if err != nil {
-		logger.Error("Failed to get habits", zap.Error(err), zap.String("user_id", userID.String()))
-		appErr := apperrors.NewDatabaseError(err)
-		c.JSON(appErr.StatusCode, appErr)
+		respondDatabaseError(c, err, "Failed to get habits", zap.String("user_id", userID.String()))
 		return
 	}
 
@@ -87,73 +108,293 @@ func (h *HabitHandler) GetAll(c *gin.Context) {
 	})
 }
 
-func (h *HabitHandler) GetByID(c *gin.Context) {
-	habitID, err := uuid.Parse(c.Param("id"))
+// GetDue lists the habits due on ?date= (YYYY-MM-DD or RFC3339), today in
+// UTC when omitted.
+func (h *HabitHandler) GetDue(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	date := normalizeDate(time.Now())
+	if value := c.Query("date"); value != "" {
+		parsed, err := parseDate(value)
+		if err != nil {
+			appErr := apperrors.NewBadRequest(err.Error())
+			respondError(c, appErr)
+			return
+		}
+		date = parsed
+	}
+
+	habits, err := h.repo.GetDueOn(c.Request.Context(), userID, date)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get due habits", zap.String("user_id", userID.String()))
+		return
+	}
+
+	if habits == nil {
+		habits = []models.Habit{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"date":  date.Format(dateLayout),
+		"data":  habits,
+		"count": len(habits),
+	})
+}
+
+// GetStreaks returns every habit's current and longest streak, highest
+// current streak first.
+func (h *HabitHandler) GetStreaks(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	ctx := c.Request.Context()
+	habits, err := h.repo.GetByUserID(ctx, userID, models.HabitSortPosition)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get habits", zap.String("user_id", userID.String()))
+		return
+	}
+
+	completions, err := h.repo.GetAllCompletions(ctx, userID)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get habit completions", zap.String("user_id", userID.String()))
+		return
+	}
+
+	restDays, err := h.repo.GetRestDays(ctx, userID)
 	if err != nil {
-		appErr := apperrors.NewBadRequest("invalid habit ID")
-		c.JSON(appErr.StatusCode, appErr)
+		respondDatabaseError(c, err, "Failed to get rest days", zap.String("user_id", userID.String()))
 		return
 	}
+	for i := range habits {
+		habits[i].RestDays = restDays
+	}
+
+	streaks := models.HabitStreaks(habits, completions, time.Now())
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  streaks,
+		"count": len(streaks),
+	})
+}
+
+// GetByID returns the habit with its completion rate over the last ?window=
+// days (default 30).
+func (h *HabitHandler) GetByID(c *gin.Context) {
+	habitID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	window := models.DefaultCompletionRateWindow
+	if value := c.Query("window"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 1 || days > models.MaxCompletionRateWindow {
+			appErr := apperrors.NewBadRequest(fmt.Sprintf("invalid window: must be between 1 and %d days", models.MaxCompletionRateWindow))
+			respondError(c, appErr)
+			return
+		}
+		window = days
+	}
 
 	userID := getUserID(c)
 	if userID == uuid.Nil {
 		appErr := apperrors.NewUnauthorized("user not authenticated")
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
 		return
 	}
 
 	habit, err := h.repo.GetByID(c.Request.Context(), habitID, userID)
 	if err == models.ErrNotFound {
-		appErr := apperrors.NewNotFound("habit")
-		c.JSON(appErr.StatusCode, appErr)
+		appErr := apperrors.NewNotFoundWithID("habit", habitID)
+		respondError(c, appErr)
 		return
 	}
 
 	if err != nil {
-		logger.Error("Failed to get habit", zap.Error(err), zap.String("habit_id", habitID.String()))
-		appErr := apperrors.NewDatabaseError(err)
-		c.JSON(appErr.StatusCode, appErr)
+		respondDatabaseError(c, err, "Failed to get habit", zap.String("habit_id", habitID.String()))
 		return
 	}
 
-	c.JSON(http.StatusOK, habit)
+	now := time.Now()
+	since := habit.CompletionRateStart(window, now)
+	completions, err := h.repo.GetCompletionsSince(c.Request.Context(), habitID, userID, since)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get habit completions", zap.String("habit_id", habitID.String()))
+		return
+	}
+
+	habit.RestDays, err = h.repo.GetRestDays(c.Request.Context(), userID)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get rest days", zap.String("user_id", userID.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.HabitDetail{
+		Habit:                *habit,
+		CompletionRate:       habit.CompletionRate(completions, window, now),
+		CompletionRateWindow: window,
+	})
 }
 
-func (h *HabitHandler) Update(c *gin.Context) {
-	habitID, err := uuid.Parse(c.Param("id"))
+// Adherence scores the habit from 0 to 100 over the last ?window= (default
+// 30d), weighing recent periods more than older ones.
+func (h *HabitHandler) Adherence(c *gin.Context) {
+	habitID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	window := models.DefaultCompletionRateWindow
+	if value := c.Query("window"); value != "" {
+		days, err := models.ParseAdherenceWindow(value)
+		if err != nil {
+			appErr := apperrors.NewBadRequest(err.Error())
+			respondError(c, appErr)
+			return
+		}
+		window = days
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	habit, err := h.repo.GetByID(c.Request.Context(), habitID, userID)
+	if err == models.ErrNotFound {
+		appErr := apperrors.NewNotFoundWithID("habit", habitID)
+		respondError(c, appErr)
+		return
+	}
+
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get habit", zap.String("habit_id", habitID.String()))
+		return
+	}
+
+	now := time.Now()
+	since := habit.CompletionRateStart(window, now)
+	completions, err := h.repo.GetCompletionsSince(c.Request.Context(), habitID, userID, since)
 	if err != nil {
-		appErr := apperrors.NewBadRequest("invalid habit ID")
-		c.JSON(appErr.StatusCode, appErr)
+		respondDatabaseError(c, err, "Failed to get habit completions", zap.String("habit_id", habitID.String()))
 		return
 	}
 
+	habit.RestDays, err = h.repo.GetRestDays(c.Request.Context(), userID)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get rest days", zap.String("user_id", userID.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.HabitAdherence{
+		HabitID:        habit.ID,
+		Score:          habit.AdherenceScore(completions, window, now),
+		CompletionRate: habit.CompletionRate(completions, window, now),
+		WindowDays:     window,
+	})
+}
+
+// Consistency reports how evenly the habit's completions over the last
+// ?window= (default 30d) are spread, from the variance of the gaps between
+// them, skipping the periods Adherence skips.
+func (h *HabitHandler) Consistency(c *gin.Context) {
+	habitID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	window := models.DefaultCompletionRateWindow
+	if value := c.Query("window"); value != "" {
+		days, err := models.ParseAdherenceWindow(value)
+		if err != nil {
+			appErr := apperrors.NewBadRequest(err.Error())
+			respondError(c, appErr)
+			return
+		}
+		window = days
+	}
+
 	userID := getUserID(c)
 	if userID == uuid.Nil {
 		appErr := apperrors.NewUnauthorized("user not authenticated")
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
 		return
 	}
 
 	habit, err := h.repo.GetByID(c.Request.Context(), habitID, userID)
 	if err == models.ErrNotFound {
-		appErr := apperrors.NewNotFound("habit")
-		c.JSON(appErr.StatusCode, appErr)
+		appErr := apperrors.NewNotFoundWithID("habit", habitID)
+		respondError(c, appErr)
 		return
 	}
 
 	if err != nil {
-		appErr := apperrors.NewDatabaseError(err)
-		c.JSON(appErr.StatusCode, appErr)
+		respondDatabaseError(c, err, "Failed to get habit", zap.String("habit_id", habitID.String()))
+		return
+	}
+
+	now := time.Now()
+	since := habit.CompletionRateStart(window, now)
+	completions, err := h.repo.GetCompletionsSince(c.Request.Context(), habitID, userID, since)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get habit completions", zap.String("habit_id", habitID.String()))
+		return
+	}
+
+	habit.RestDays, err = h.repo.GetRestDays(c.Request.Context(), userID)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get rest days", zap.String("user_id", userID.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, habit.Consistency(completions, window, now))
+}
+
+func (h *HabitHandler) Update(c *gin.Context) {
+	habitID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	habit, err := h.repo.GetByID(repository.WithPrimary(c.Request.Context()), habitID, userID)
+	if err == models.ErrNotFound {
+		appErr := apperrors.NewNotFoundWithID("habit", habitID)
+		respondError(c, appErr)
+		return
+	}
+
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get habit", zap.String("habit_id", habitID.String()))
 		return
 	}
 
 	var req models.UpdateHabitRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		appErr := apperrors.NewValidationError(err.Error())
-		c.JSON(appErr.StatusCode, appErr)
+	if err := bindJSON(c, &req); err != nil {
+		appErr := bindError(err)
+		respondError(c, appErr)
 		return
 	}
 
+	changes := req.Changes(habit)
+
 	if req.Name != nil {
 		habit.Name = *req.Name
 	}
@@ -161,6 +402,13 @@ func (h *HabitHandler) Update(c *gin.Context) {
 		habit.Color = *req.Color
 	}
 	if req.Icon != nil {
+		// Only a newly set icon is checked, so habits saved under the
+		// lenient profile can still be edited.
+		if err := middleware.ValidationProfile(c).ValidateIcon(*req.Icon); err != nil {
+			appErr := apperrors.NewValidationError(err.Error())
+			respondError(c, appErr)
+			return
+		}
 		habit.Icon = *req.Icon
 	}
 	if req.Frequency != nil {
@@ -169,53 +417,384 @@ func (h *HabitHandler) Update(c *gin.Context) {
 	if req.TargetCount != nil {
 		habit.TargetCount = *req.TargetCount
 	}
+	if req.TargetAmount.Set {
+		habit.TargetAmount = req.TargetAmount.Value
+	}
 	if req.IsActive != nil {
 		habit.IsActive = *req.IsActive
 	}
+	if req.ReminderTimes != nil {
+		habit.ReminderTimes = *req.ReminderTimes
+	}
 
 	if err := habit.Validate(); err != nil {
 		appErr := apperrors.NewValidationError(err.Error())
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
 		return
 	}
 
 	if err := h.repo.Update(c.Request.Context(), habit); err != nil {
-		logger.Error("Failed to update habit", zap.Error(err), zap.String("habit_id", habitID.String()))
-		appErr := apperrors.NewDatabaseError(err)
-		c.JSON(appErr.StatusCode, appErr)
+		respondDatabaseError(c, err, "Failed to update habit", zap.String("habit_id", habitID.String()))
 		return
 	}
 
 	logger.Info("Habit updated", zap.String("habit_id", habitID.String()), zap.String("user_id", userID.String()))
+	h.events.Publish(events.HabitUpdated{Habit: *habit})
+	respondUpdated(c, habit, changes)
+}
+
+func (h *HabitHandler) Pause(c *gin.Context) {
+	habitID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	var req models.PauseHabitRequest
+	if err := bindJSON(c, &req); err != nil {
+		appErr := bindError(err)
+		respondError(c, appErr)
+		return
+	}
+
+	habit, err := h.repo.GetByID(repository.WithPrimary(c.Request.Context()), habitID, userID)
+	if err == models.ErrNotFound {
+		appErr := apperrors.NewNotFoundWithID("habit", habitID)
+		respondError(c, appErr)
+		return
+	}
+
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get habit", zap.String("habit_id", habitID.String()))
+		return
+	}
+
+	if err := habit.Pause(req.Until, time.Now()); err != nil {
+		appErr := apperrors.NewValidationError(err.Error())
+		respondError(c, appErr)
+		return
+	}
+
+	if err := h.repo.Pause(c.Request.Context(), habit); err != nil {
+		respondDatabaseError(c, err, "Failed to pause habit", zap.String("habit_id", habitID.String()))
+		return
+	}
+
+	logger.Info("Habit paused", zap.String("habit_id", habitID.String()), zap.Time("paused_until", req.Until))
+	h.events.Publish(events.HabitUpdated{Habit: *habit})
 	c.JSON(http.StatusOK, habit)
 }
 
-func (h *HabitHandler) Delete(c *gin.Context) {
-	habitID, err := uuid.Parse(c.Param("id"))
+// GetStreakFreezes returns the user's streak freeze token balance.
+func (h *HabitHandler) GetStreakFreezes(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	balance, err := h.repo.GetStreakFreezeBalance(c.Request.Context(), userID)
+	if err == models.ErrNotFound {
+		appErr := apperrors.NewNotFound("user")
+		respondError(c, appErr)
+		return
+	}
+
 	if err != nil {
-		appErr := apperrors.NewBadRequest("invalid habit ID")
-		c.JSON(appErr.StatusCode, appErr)
+		respondDatabaseError(c, err, "Failed to get streak freeze balance", zap.String("user_id", userID.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"balance": balance})
+}
+
+// SpendStreakFreeze spends a token on the single missed period breaking the
+// habit's current streak, picking the period automatically, and returns the
+// recalculated progress.
+func (h *HabitHandler) SpendStreakFreeze(c *gin.Context) {
+	habitID, ok := pathUUID(c, "id")
+	if !ok {
 		return
 	}
 
 	userID := getUserID(c)
 	if userID == uuid.Nil {
 		appErr := apperrors.NewUnauthorized("user not authenticated")
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
 		return
 	}
 
-	if err := h.repo.Delete(c.Request.Context(), habitID, userID); err == models.ErrNotFound {
+	ctx := repository.WithPrimary(c.Request.Context())
+	habit, err := h.repo.GetByID(ctx, habitID, userID)
+	if err == models.ErrNotFound {
+		appErr := apperrors.NewNotFoundWithID("habit", habitID)
+		respondError(c, appErr)
+		return
+	}
+
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get habit", zap.String("habit_id", habitID.String()))
+		return
+	}
+
+	completions, err := h.repo.GetCompletionsSince(ctx, habitID, userID, time.Time{})
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get habit completions", zap.String("habit_id", habitID.String()))
+		return
+	}
+
+	habit.RestDays, err = h.repo.GetRestDays(ctx, userID)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get rest days", zap.String("user_id", userID.String()))
+		return
+	}
+
+	now := time.Now()
+	period, ok := habit.FreezablePeriod(completions, now)
+	if !ok {
+		appErr := apperrors.NewConflict(models.ErrNothingToFreeze.Error())
+		respondError(c, appErr)
+		return
+	}
+
+	balance, err := h.repo.SpendStreakFreeze(ctx, habitID, userID, period)
+	if err == models.ErrNoFreezeTokens || err == models.ErrNothingToFreeze {
+		appErr := apperrors.NewConflict(err.Error())
+		respondError(c, appErr)
+		return
+	}
+
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to spend streak freeze", zap.String("habit_id", habitID.String()))
+		return
+	}
+
+	habit.FrozenPeriods = append(habit.FrozenPeriods, period)
+
+	logger.Info("Streak freeze spent", zap.String("habit_id", habitID.String()), zap.String("period", period.Format(time.DateOnly)))
+	c.JSON(http.StatusOK, gin.H{
+		"frozen_period": period.Format(time.DateOnly),
+		"balance":       balance,
+		"progress":      habit.Progress(completions, now),
+	})
+}
+
+// ResetStreak starts the habit's streak afresh from now without deleting any
+// completions, and returns the recalculated progress.
+func (h *HabitHandler) ResetStreak(c *gin.Context) {
+	habitID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	ctx := repository.WithPrimary(c.Request.Context())
+	habit, err := h.repo.GetByID(ctx, habitID, userID)
+	if err == models.ErrNotFound {
+		appErr := apperrors.NewNotFoundWithID("habit", habitID)
+		respondError(c, appErr)
+		return
+	}
+
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get habit", zap.String("habit_id", habitID.String()))
+		return
+	}
+
+	if err := h.repo.ResetStreak(ctx, habit); err != nil {
+		respondDatabaseError(c, err, "Failed to reset habit streak", zap.String("habit_id", habitID.String()))
+		return
+	}
+
+	completions, err := h.repo.GetCompletionsSince(ctx, habitID, userID, time.Time{})
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get habit completions", zap.String("habit_id", habitID.String()))
+		return
+	}
+
+	habit.RestDays, err = h.repo.GetRestDays(ctx, userID)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get rest days", zap.String("user_id", userID.String()))
+		return
+	}
+
+	logger.Info("Habit streak reset", zap.String("habit_id", habitID.String()))
+	h.events.Publish(events.HabitUpdated{Habit: *habit})
+	c.JSON(http.StatusOK, gin.H{
+		"streak_reset_at": habit.StreakResetAt,
+		"progress":        habit.Progress(completions, time.Now()),
+	})
+}
+
+func (h *HabitHandler) Reorder(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	var req models.ReorderHabitsRequest
+	if err := bindJSON(c, &req); err != nil {
+		appErr := bindError(err)
+		respondError(c, appErr)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		appErr := apperrors.NewValidationError(err.Error())
+		respondError(c, appErr)
+		return
+	}
+
+	if err := h.repo.Reorder(c.Request.Context(), userID, req.HabitIDs); err == models.ErrInvalidHabitOrder {
+		appErr := apperrors.NewValidationError(err.Error())
+		respondError(c, appErr)
+		return
+	} else if err != nil {
+		respondDatabaseError(c, err, "Failed to reorder habits", zap.String("user_id", userID.String()))
+		return
+	}
+
+	logger.Info("Habits reordered", zap.String("user_id", userID.String()), zap.Int("count", len(req.HabitIDs)))
+	c.Request = c.Request.WithContext(repository.WithPrimary(c.Request.Context()))
+	h.GetAll(c)
+}
+
+// ShiftReminders moves every reminder time of the user's habits by the
+// requested offset, wrapping around midnight, and returns the habits.
+func (h *HabitHandler) ShiftReminders(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	var req models.ShiftRemindersRequest
+	if err := bindJSON(c, &req); err != nil {
+		appErr := bindError(err)
+		respondError(c, appErr)
+		return
+	}
+
+	offset, err := req.ParseOffset()
+	if err != nil {
+		appErr := apperrors.NewValidationError(err.Error())
+		respondError(c, appErr)
+		return
+	}
+
+	count, err := h.repo.ShiftReminderTimes(c.Request.Context(), userID, offset)
+	if errors.Is(err, models.ErrInvalidReminderTime) || errors.Is(err, models.ErrDuplicateReminder) || errors.Is(err, models.ErrTooManyReminders) {
+		appErr := apperrors.NewValidationError(err.Error())
+		respondError(c, appErr)
+		return
+	} else if err != nil {
+		respondDatabaseError(c, err, "Failed to shift reminder times", zap.String("user_id", userID.String()))
+		return
+	}
+
+	logger.Info("Reminder times shifted", zap.String("user_id", userID.String()), zap.Duration("offset", offset), zap.Int("habits", count))
+	c.Request = c.Request.WithContext(repository.WithPrimary(c.Request.Context()))
+	h.GetAll(c)
+}
+
+// Merge folds the habit in the path into req.TargetID, combining their
+// completion history and deleting the merged habit. The target keeps its own
+// frequency, target count and other settings.
+func (h *HabitHandler) Merge(c *gin.Context) {
+	sourceID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	var req models.MergeHabitsRequest
+	if err := bindJSON(c, &req); err != nil {
+		appErr := bindError(err)
+		respondError(c, appErr)
+		return
+	}
+
+	if req.TargetID == sourceID {
+		appErr := apperrors.NewValidationError(models.ErrMergeSameHabit.Error())
+		respondError(c, appErr)
+		return
+	}
+
+	ctx := repository.WithPrimary(c.Request.Context())
+	moved, err := h.repo.Merge(ctx, sourceID, req.TargetID, userID)
+	if err == models.ErrNotFound {
 		appErr := apperrors.NewNotFound("habit")
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
+		return
+	} else if err != nil {
+		respondDatabaseError(c, err, "Failed to merge habits", zap.String("habit_id", sourceID.String()), zap.String("target_id", req.TargetID.String()))
+		return
+	}
+
+	target, err := h.repo.GetByID(ctx, req.TargetID, userID)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get merged habit", zap.String("habit_id", req.TargetID.String()))
+		return
+	}
+
+	logger.Info("Habits merged",
+		zap.String("habit_id", sourceID.String()),
+		zap.String("target_id", req.TargetID.String()),
+		zap.Int("completions_moved", moved),
+	)
+	h.events.Publish(events.HabitDeleted{UserID: userID, HabitID: sourceID})
+	h.events.Publish(events.HabitUpdated{Habit: *target})
+	c.JSON(http.StatusOK, gin.H{
+		"habit":              target,
+		"merged_completions": moved,
+	})
+}
+
+func (h *HabitHandler) Delete(c *gin.Context) {
+	habitID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	if err := h.repo.Delete(c.Request.Context(), habitID, userID); err == models.ErrNotFound {
+		appErr := apperrors.NewNotFoundWithID("habit", habitID)
+		respondError(c, appErr)
 		return
 	} else if err != nil {
-		logger.Error("Failed to delete habit", zap.Error(err), zap.String("habit_id", habitID.String()))
-		appErr := apperrors.NewDatabaseError(err)
-		c.JSON(appErr.StatusCode, appErr)
+		respondDatabaseError(c, err, "Failed to delete habit", zap.String("habit_id", habitID.String()))
 		return
 	}
 
 	logger.Info("Habit deleted", zap.String("habit_id", habitID.String()), zap.String("user_id", userID.String()))
+	h.events.Publish(events.HabitDeleted{UserID: userID, HabitID: habitID})
 	c.JSON(http.StatusNoContent, nil)
 }
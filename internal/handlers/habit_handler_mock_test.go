@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/events"
+	"github.com/lumen/backend/internal/middleware"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockHabitRepo is a mock for repository.HabitRepository
+type MockHabitRepo struct {
+	mock.Mock
+}
+
+func (m *MockHabitRepo) Create(ctx context.Context, habit *models.Habit) error {
+	args := m.Called(ctx, habit)
+	return args.Error(0)
+}
+
+func (m *MockHabitRepo) GetByID(ctx context.Context, id, userID uuid.UUID) (*models.Habit, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Habit), args.Error(1)
+}
+
+func (m *MockHabitRepo) GetByUserID(ctx context.Context, userID uuid.UUID, sortBy string) ([]models.Habit, error) {
+	args := m.Called(ctx, userID, sortBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Habit), args.Error(1)
+}
+
+func (m *MockHabitRepo) GetDueOn(ctx context.Context, userID uuid.UUID, date time.Time) ([]models.Habit, error) {
+	args := m.Called(ctx, userID, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Habit), args.Error(1)
+}
+
+func (m *MockHabitRepo) GetIncompleteOn(ctx context.Context, userID uuid.UUID, date time.Time) ([]models.IncompleteHabit, error) {
+	args := m.Called(ctx, userID, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.IncompleteHabit), args.Error(1)
+}
+
+func (m *MockHabitRepo) GetAllCompletions(ctx context.Context, userID uuid.UUID) ([]models.HabitCompletion, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.HabitCompletion), args.Error(1)
+}
+
+func (m *MockHabitRepo) GetCompletionsBetween(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.HabitCompletion, error) {
+	args := m.Called(ctx, userID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.HabitCompletion), args.Error(1)
+}
+
+func (m *MockHabitRepo) GetRestDays(ctx context.Context, userID uuid.UUID) ([]time.Time, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]time.Time), args.Error(1)
+}
+
+func (m *MockHabitRepo) GetStreakFreezeBalance(ctx context.Context, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockHabitRepo) SpendStreakFreeze(ctx context.Context, habitID, userID uuid.UUID, period time.Time) (int, error) {
+	args := m.Called(ctx, habitID, userID, period)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockHabitRepo) GetCompletionsSince(ctx context.Context, habitID, userID uuid.UUID, since time.Time) ([]models.HabitCompletion, error) {
+	args := m.Called(ctx, habitID, userID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.HabitCompletion), args.Error(1)
+}
+
+func (m *MockHabitRepo) Update(ctx context.Context, habit *models.Habit) error {
+	args := m.Called(ctx, habit)
+	return args.Error(0)
+}
+
+func (m *MockHabitRepo) Pause(ctx context.Context, habit *models.Habit) error {
+	args := m.Called(ctx, habit)
+	return args.Error(0)
+}
+
+func (m *MockHabitRepo) ShiftReminderTimes(ctx context.Context, userID uuid.UUID, offset time.Duration) (int, error) {
+	args := m.Called(ctx, userID, offset)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockHabitRepo) ResetStreak(ctx context.Context, habit *models.Habit) error {
+	args := m.Called(ctx, habit)
+	return args.Error(0)
+}
+
+func (m *MockHabitRepo) Reorder(ctx context.Context, userID uuid.UUID, habitIDs []uuid.UUID) error {
+	args := m.Called(ctx, userID, habitIDs)
+	return args.Error(0)
+}
+
+func (m *MockHabitRepo) Merge(ctx context.Context, sourceID, targetID, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, sourceID, targetID, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockHabitRepo) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func setupHabitRouter(repo *MockHabitRepo, userID uuid.UUID) *gin.Engine {
+	return setupHabitRouterWithEvents(repo, userID, &recordingPublisher{})
+}
+
+func setupHabitRouterWithEvents(repo *MockHabitRepo, userID uuid.UUID, publisher events.Publisher) *gin.Engine {
+	router := setupTestRouter()
+	handler := NewHabitHandler(repo, publisher)
+
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	})
+	router.Use(middleware.UUIDParams("id"))
+	router.GET("/habits", handler.GetAll)
+	router.POST("/habits", handler.Create)
+	router.GET("/habits/due", handler.GetDue)
+	router.GET("/habits/streaks", handler.GetStreaks)
+	router.GET("/habits/:id", handler.GetByID)
+	router.GET("/habits/:id/adherence", handler.Adherence)
+	router.GET("/habits/:id/consistency", handler.Consistency)
+	router.PATCH("/habits/reorder", handler.Reorder)
+	router.PATCH("/habits/reminders/shift", handler.ShiftReminders)
+	router.PATCH("/habits/:id", handler.Update)
+	router.GET("/habits/streak-freezes", handler.GetStreakFreezes)
+	router.POST("/habits/:id/streak-freeze", handler.SpendStreakFreeze)
+	router.POST("/habits/:id/reset-streak", handler.ResetStreak)
+	router.POST("/habits/:id/merge", handler.Merge)
+
+	return router
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHabitAdherence_ScoresWindow(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	now := time.Now()
+	habit := &models.Habit{ID: uuid.New(), UserID: userID, Frequency: "daily", TargetCount: 1, IsActive: true, CreatedAt: now.AddDate(0, -2, 0)}
+
+	// Done every finished day but the last two.
+	var completions []models.HabitCompletion
+	for daysAgo := 3; daysAgo < 14; daysAgo++ {
+		completions = append(completions, models.HabitCompletion{HabitID: habit.ID, CompletedAt: now.AddDate(0, 0, -daysAgo)})
+	}
+
+	mockRepo.On("GetByID", mock.Anything, habit.ID, userID).Return(habit, nil)
+	mockRepo.On("GetCompletionsSince", mock.Anything, habit.ID, userID, mock.AnythingOfType("time.Time")).Return(completions, nil)
+	mockRepo.On("GetRestDays", mock.Anything, userID).Return(nil, nil)
+
+	req, _ := http.NewRequest("GET", "/habits/"+habit.ID.String()+"/adherence?window=14d", nil)
+	w := httptest.NewRecorder()
+	setupHabitRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var adherence models.HabitAdherence
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &adherence))
+	assert.Equal(t, habit.ID, adherence.HabitID)
+	assert.Equal(t, 14, adherence.WindowDays)
+	assert.Equal(t, 0.85, adherence.CompletionRate)
+	assert.Less(t, float64(adherence.Score), adherence.CompletionRate*100, "recent misses weigh more than the plain rate")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestHabitAdherence_RejectsInvalidWindow(t *testing.T) {
+	for _, window := range []string{"30", "0d", "400d", "month"} {
+		mockRepo := new(MockHabitRepo)
+
+		req, _ := http.NewRequest("GET", "/habits/"+uuid.New().String()+"/adherence?window="+url.QueryEscape(window), nil)
+		w := httptest.NewRecorder()
+		setupHabitRouter(mockRepo, uuid.New()).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, window)
+		mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
+	}
+}
+
+func TestHabitAdherence_NotFound(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	habitID := uuid.New()
+
+	mockRepo.On("GetByID", mock.Anything, habitID, userID).Return(nil, models.ErrNotFound)
+
+	req, _ := http.NewRequest("GET", "/habits/"+habitID.String()+"/adherence", nil)
+	w := httptest.NewRecorder()
+	setupHabitRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHabitConsistency_EvenVersusClustered(t *testing.T) {
+	userID := uuid.New()
+	now := time.Now()
+	habit := &models.Habit{ID: uuid.New(), UserID: userID, Frequency: "daily", TargetCount: 1, IsActive: true}
+
+	var even, clustered []models.HabitCompletion
+	for daysAgo := 1; daysAgo <= 12; daysAgo++ {
+		even = append(even, models.HabitCompletion{HabitID: habit.ID, CompletedAt: now.AddDate(0, 0, -daysAgo)})
+	}
+	// The same number of completions, bunched into two bursts.
+	for i := 0; i < 6; i++ {
+		clustered = append(clustered,
+			models.HabitCompletion{HabitID: habit.ID, CompletedAt: now.AddDate(0, 0, -13).Add(time.Duration(i) * time.Hour)},
+			models.HabitCompletion{HabitID: habit.ID, CompletedAt: now.AddDate(0, 0, -1).Add(time.Duration(i) * time.Hour)},
+		)
+	}
+
+	measure := func(completions []models.HabitCompletion) models.HabitConsistency {
+		mockRepo := new(MockHabitRepo)
+		mockRepo.On("GetByID", mock.Anything, habit.ID, userID).Return(habit, nil)
+		mockRepo.On("GetCompletionsSince", mock.Anything, habit.ID, userID, mock.AnythingOfType("time.Time")).Return(completions, nil)
+		mockRepo.On("GetRestDays", mock.Anything, userID).Return(nil, nil)
+
+		req, _ := http.NewRequest("GET", "/habits/"+habit.ID.String()+"/consistency?window=14d", nil)
+		w := httptest.NewRecorder()
+		setupHabitRouter(mockRepo, userID).ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var consistency models.HabitConsistency
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &consistency))
+		return consistency
+	}
+
+	steady := measure(even)
+	erratic := measure(clustered)
+
+	assert.Equal(t, models.ConsistencySteady, steady.Label)
+	assert.Equal(t, models.ConsistencyErratic, erratic.Label)
+	assert.Equal(t, 14, steady.WindowDays)
+	assert.Equal(t, 12, erratic.Completions)
+	assert.Less(t, steady.GapVariance, erratic.GapVariance)
+	if assert.NotNil(t, steady.CoefficientOfVariation) && assert.NotNil(t, erratic.CoefficientOfVariation) {
+		assert.Less(t, *steady.CoefficientOfVariation, *erratic.CoefficientOfVariation)
+	}
+}
+
+func TestHabitConsistency_SkipsRestDaysFromWindowStart(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	now := time.Now()
+	habit := &models.Habit{ID: uuid.New(), UserID: userID, Frequency: "daily", TargetCount: 1, IsActive: true}
+	restDay := now.AddDate(0, 0, -3)
+	var completions []models.HabitCompletion
+	for _, daysAgo := range []int{6, 4, 3, 2} {
+		completions = append(completions, models.HabitCompletion{HabitID: habit.ID, CompletedAt: now.AddDate(0, 0, -daysAgo)})
+	}
+
+	mockRepo.On("GetByID", mock.Anything, habit.ID, userID).Return(habit, nil)
+	mockRepo.On("GetCompletionsSince", mock.Anything, habit.ID, userID, habit.CompletionRateStart(7, now)).
+		Return(completions, nil)
+	mockRepo.On("GetRestDays", mock.Anything, userID).Return([]time.Time{restDay}, nil)
+
+	req, _ := http.NewRequest("GET", "/habits/"+habit.ID.String()+"/consistency?window=7d", nil)
+	w := httptest.NewRecorder()
+	setupHabitRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var consistency models.HabitConsistency
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &consistency))
+	assert.Equal(t, 3, consistency.Completions)
+	assert.Equal(t, models.ConsistencySteady, consistency.Label)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestHabitConsistency_RejectsInvalidWindow(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+
+	req, _ := http.NewRequest("GET", "/habits/"+uuid.New().String()+"/consistency?window=month", nil)
+	w := httptest.NewRecorder()
+	setupHabitRouter(mockRepo, uuid.New()).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHabitConsistency_NotFound(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	habitID := uuid.New()
+
+	mockRepo.On("GetByID", mock.Anything, habitID, userID).Return(nil, models.ErrNotFound)
+
+	req, _ := http.NewRequest("GET", "/habits/"+habitID.String()+"/consistency", nil)
+	w := httptest.NewRecorder()
+	setupHabitRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/events"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func mergeHabits(router *gin.Engine, sourceID uuid.UUID, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/habits/"+sourceID.String()+"/merge", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMergeHabits_CombinesIntoTarget(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	publisher := &recordingPublisher{}
+	userID := uuid.New()
+	sourceID := uuid.New()
+	// The target keeps its own settings even though the source was weekly.
+	target := &models.Habit{ID: uuid.New(), UserID: userID, Name: "Run", Frequency: "daily", TargetCount: 1}
+
+	mockRepo.On("Merge", mock.Anything, sourceID, target.ID, userID).Return(4, nil)
+	mockRepo.On("GetByID", mock.Anything, target.ID, userID).Return(target, nil)
+
+	w := mergeHabits(setupHabitRouterWithEvents(mockRepo, userID, publisher), sourceID, `{"target_id": "`+target.ID.String()+`"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Habit             models.Habit `json:"habit"`
+		MergedCompletions int          `json:"merged_completions"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, target.ID, resp.Habit.ID)
+	assert.Equal(t, "daily", resp.Habit.Frequency)
+	assert.Equal(t, 1, resp.Habit.TargetCount)
+	assert.Equal(t, 4, resp.MergedCompletions)
+
+	if assert.Len(t, publisher.events, 2) {
+		assert.Equal(t, events.HabitDeleted{UserID: userID, HabitID: sourceID}, publisher.events[0])
+		assert.Equal(t, events.HabitUpdated{Habit: *target}, publisher.events[1])
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMergeHabits_ReturnsTargetFromPrimary(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	sourceID := uuid.New()
+	target := &models.Habit{ID: uuid.New(), UserID: userID, Name: "Run", Frequency: "daily", TargetCount: 1}
+
+	mockRepo.On("Merge", mock.Anything, sourceID, target.ID, userID).Return(1, nil)
+	mockRepo.On("GetByID", mock.MatchedBy(repository.ReadsPrimary), target.ID, userID).Return(target, nil)
+
+	w := mergeHabits(setupHabitRouter(mockRepo, userID), sourceID, `{"target_id": "`+target.ID.String()+`"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMergeHabits_RequiresOwnershipOfBoth(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	publisher := &recordingPublisher{}
+	userID := uuid.New()
+	sourceID, targetID := uuid.New(), uuid.New()
+
+	mockRepo.On("Merge", mock.Anything, sourceID, targetID, userID).Return(0, models.ErrNotFound)
+
+	w := mergeHabits(setupHabitRouterWithEvents(mockRepo, userID, publisher), sourceID, `{"target_id": "`+targetID.String()+`"}`)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, publisher.events)
+	mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMergeHabits_RejectsSelfMerge(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	habitID := uuid.New()
+
+	w := mergeHabits(setupHabitRouter(mockRepo, uuid.New()), habitID, `{"target_id": "`+habitID.String()+`"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "target_id must be a different habit")
+	mockRepo.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMergeHabits_RequiresTarget(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+
+	w := mergeHabits(setupHabitRouter(mockRepo, uuid.New()), uuid.New(), `{}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockRepo.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetDueHabits_ParsesDate(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	date := time.Date(2025, 11, 13, 0, 0, 0, 0, time.UTC)
+
+	mockRepo.On("GetDueOn", mock.Anything, userID, date).Return([]models.Habit{
+		{ID: uuid.New(), UserID: userID, Name: "Read", Frequency: "daily", IsActive: true},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/habits/due?date=2025-11-13", nil)
+	w := httptest.NewRecorder()
+	setupHabitRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"date":"2025-11-13"`)
+	assert.Contains(t, w.Body.String(), `"count":1`)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetDueHabits_InvalidDate(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+
+	req, _ := http.NewRequest("GET", "/habits/due?date=tomorrow", nil)
+	w := httptest.NewRecorder()
+	setupHabitRouter(mockRepo, uuid.New()).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockRepo.AssertNotCalled(t, "GetDueOn", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetHabit_IncludesCompletionRate(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	now := time.Now()
+	habit := &models.Habit{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Name:        "Read",
+		Frequency:   "daily",
+		TargetCount: 1,
+		IsActive:    true,
+		CreatedAt:   now.AddDate(0, -2, 0),
+	}
+
+	// Done on 6 of the 10 full days before today.
+	var completions []models.HabitCompletion
+	for _, daysAgo := range []int{1, 2, 3, 5, 8, 10} {
+		completions = append(completions, models.HabitCompletion{HabitID: habit.ID, CompletedAt: now.AddDate(0, 0, -daysAgo)})
+	}
+
+	mockRepo.On("GetByID", mock.Anything, habit.ID, userID).Return(habit, nil)
+	mockRepo.On("GetCompletionsSince", mock.Anything, habit.ID, userID, mock.AnythingOfType("time.Time")).Return(completions, nil)
+	mockRepo.On("GetRestDays", mock.Anything, userID).Return(nil, nil)
+
+	req, _ := http.NewRequest("GET", "/habits/"+habit.ID.String()+"?window=11", nil)
+	w := httptest.NewRecorder()
+	setupHabitRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var detail models.HabitDetail
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &detail))
+	assert.Equal(t, habit.ID, detail.ID)
+	assert.Equal(t, 0.6, detail.CompletionRate)
+	assert.Equal(t, 11, detail.CompletionRateWindow)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetHabit_NewHabitDefaultsToZero(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	habit := &models.Habit{ID: uuid.New(), UserID: userID, Frequency: "daily", TargetCount: 1, IsActive: true, CreatedAt: time.Now()}
+
+	mockRepo.On("GetByID", mock.Anything, habit.ID, userID).Return(habit, nil)
+	mockRepo.On("GetCompletionsSince", mock.Anything, habit.ID, userID, mock.AnythingOfType("time.Time")).Return(nil, nil)
+	mockRepo.On("GetRestDays", mock.Anything, userID).Return(nil, nil)
+
+	req, _ := http.NewRequest("GET", "/habits/"+habit.ID.String(), nil)
+	w := httptest.NewRecorder()
+	setupHabitRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"completion_rate":0`)
+	assert.Contains(t, w.Body.String(), `"completion_rate_window_days":30`)
+}
+
+func TestGetHabit_InvalidWindow(t *testing.T) {
+	for _, window := range []string{"0", "-3", "abc", "366"} {
+		mockRepo := new(MockHabitRepo)
+		req, _ := http.NewRequest("GET", "/habits/"+uuid.NewString()+"?window="+window, nil)
+		w := httptest.NewRecorder()
+		setupHabitRouter(mockRepo, uuid.New()).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, window)
+		mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
+	}
+}
+
+func listHabits(router *gin.Engine, query string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/habits"+query, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetAllHabits_SortBy(t *testing.T) {
+	for query, sortBy := range map[string]string{
+		"":                  models.HabitSortPosition,
+		"?sort_by=position": models.HabitSortPosition,
+		"?sort_by=name":     models.HabitSortName,
+		"?sort_by=created":  models.HabitSortCreated,
+	} {
+		t.Run(sortBy+query, func(t *testing.T) {
+			mockRepo := new(MockHabitRepo)
+			userID := uuid.New()
+			habits := []models.Habit{{ID: uuid.New(), UserID: userID}, {ID: uuid.New(), UserID: userID}}
+
+			mockRepo.On("GetByUserID", mock.Anything, userID, sortBy).Return(habits, nil)
+
+			w := listHabits(setupHabitRouter(mockRepo, userID), query)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			var resp struct {
+				Data []models.Habit `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			if assert.Len(t, resp.Data, 2) {
+				assert.Equal(t, habits[0].ID, resp.Data[0].ID)
+				assert.Equal(t, habits[1].ID, resp.Data[1].ID)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetAllHabits_RejectsUnknownSort(t *testing.T) {
+	for _, sortBy := range []string{"name;DROP TABLE habits", "created_at", "NAME", "target_count"} {
+		mockRepo := new(MockHabitRepo)
+
+		w := listHabits(setupHabitRouter(mockRepo, uuid.New()), "?sort_by="+url.QueryEscape(sortBy))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, sortBy)
+		assert.Contains(t, w.Body.String(), "invalid sort_by")
+		mockRepo.AssertNotCalled(t, "GetByUserID", mock.Anything, mock.Anything, mock.Anything)
+	}
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func reorderHabits(router *gin.Engine, ids []uuid.UUID) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(map[string]interface{}{"habit_ids": ids})
+	req, _ := http.NewRequest("PATCH", "/habits/reorder", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestReorderHabits_PersistsOrder(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+	order := []uuid.UUID{third, first, second}
+
+	mockRepo.On("Reorder", mock.Anything, userID, order).Return(nil)
+	mockRepo.On("GetByUserID", mock.Anything, userID, models.HabitSortPosition).Return([]models.Habit{
+		{ID: third, UserID: userID, Position: 0},
+		{ID: first, UserID: userID, Position: 1},
+		{ID: second, UserID: userID, Position: 2},
+	}, nil)
+
+	w := reorderHabits(setupHabitRouter(mockRepo, userID), order)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Data  []models.Habit `json:"data"`
+		Count int            `json:"count"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 3, resp.Count)
+	for i, habit := range resp.Data {
+		assert.Equal(t, order[i], habit.ID)
+		assert.Equal(t, i, habit.Position)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func shiftReminders(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("PATCH", "/habits/reminders/shift", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestShiftReminders_ShiftsAndReturnsHabits(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+
+	mockRepo.On("ShiftReminderTimes", mock.Anything, userID, -90*time.Minute).Return(2, nil)
+	mockRepo.On("GetByUserID", mock.Anything, userID, models.HabitSortPosition).Return([]models.Habit{
+		{ID: uuid.New(), UserID: userID, ReminderTimes: []string{"05:30"}},
+		{ID: uuid.New(), UserID: userID, ReminderTimes: []string{"22:45", "06:00"}},
+	}, nil)
+
+	w := shiftReminders(setupHabitRouter(mockRepo, userID), `{"offset": "-1h30m"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"reminder_times":["22:45","06:00"]`)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestShiftReminders_RejectsInvalidOffset(t *testing.T) {
+	for _, body := range []string{`{"offset": "+25h"}`, `{"offset": "10s"}`, `{"offset": "0m"}`, `{}`} {
+		mockRepo := new(MockHabitRepo)
+
+		w := shiftReminders(setupHabitRouter(mockRepo, uuid.New()), body)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, body)
+		mockRepo.AssertNotCalled(t, "ShiftReminderTimes", mock.Anything, mock.Anything, mock.Anything)
+	}
+}
+
+func TestShiftReminders_InvalidStoredTimesRollBack(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+
+	mockRepo.On("ShiftReminderTimes", mock.Anything, userID, 30*time.Minute).
+		Return(0, fmt.Errorf("%w: %q", models.ErrInvalidReminderTime, "7am"))
+
+	w := shiftReminders(setupHabitRouter(mockRepo, userID), `{"offset": "+30m"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockRepo.AssertNotCalled(t, "GetByUserID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReorderHabits_ReturnsHabitsFromPrimary(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	order := []uuid.UUID{uuid.New()}
+
+	mockRepo.On("Reorder", mock.Anything, userID, order).Return(nil)
+	mockRepo.On("GetByUserID", mock.MatchedBy(repository.ReadsPrimary), userID, models.HabitSortPosition).
+		Return([]models.Habit{{ID: order[0], UserID: userID}}, nil)
+
+	w := reorderHabits(setupHabitRouter(mockRepo, userID), order)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestReorderHabits_RejectsDuplicates(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	id := uuid.New()
+
+	w := reorderHabits(setupHabitRouter(mockRepo, userID), []uuid.UUID{id, id})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockRepo.AssertNotCalled(t, "Reorder", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReorderHabits_IncompleteOrder(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	order := []uuid.UUID{uuid.New()}
+
+	mockRepo.On("Reorder", mock.Anything, userID, order).Return(models.ErrInvalidHabitOrder)
+
+	w := reorderHabits(setupHabitRouter(mockRepo, userID), order)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockRepo.AssertNotCalled(t, "GetByUserID", mock.Anything, mock.Anything, mock.Anything)
+}
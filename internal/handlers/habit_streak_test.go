@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetHabitStreaks_SortedByCurrentStreak(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	now := time.Now()
+	first := models.Habit{ID: uuid.New(), UserID: userID, Name: "Floss", Frequency: "daily", TargetCount: 1}
+	second := models.Habit{ID: uuid.New(), UserID: userID, Name: "Walk", Frequency: "daily", TargetCount: 1}
+
+	completions := []models.HabitCompletion{
+		{HabitID: first.ID, CompletedAt: now},
+		{HabitID: second.ID, CompletedAt: now.AddDate(0, 0, -1)},
+		{HabitID: second.ID, CompletedAt: now},
+	}
+
+	mockRepo.On("GetByUserID", mock.Anything, userID, models.HabitSortPosition).Return([]models.Habit{first, second}, nil)
+	mockRepo.On("GetAllCompletions", mock.Anything, userID).Return(completions, nil)
+	mockRepo.On("GetRestDays", mock.Anything, userID).Return(nil, nil)
+
+	req, _ := http.NewRequest("GET", "/habits/streaks", nil)
+	w := httptest.NewRecorder()
+	setupHabitRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data  []models.HabitStreak `json:"data"`
+		Count int                  `json:"count"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.Len(t, response.Data, 2) {
+		assert.Equal(t, second.ID, response.Data[0].HabitID)
+		assert.Equal(t, 2, response.Data[0].CurrentStreak)
+		assert.Equal(t, first.ID, response.Data[1].HabitID)
+		assert.Equal(t, 1, response.Data[1].CurrentStreak)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetHabitStreaks_RestDayKeepsStreak(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	now := time.Now()
+	habit := models.Habit{ID: uuid.New(), UserID: userID, Name: "Run", Frequency: "daily", TargetCount: 1}
+
+	// Yesterday was a rest day, so the streak carries over from the day before.
+	completions := []models.HabitCompletion{
+		{HabitID: habit.ID, CompletedAt: now.AddDate(0, 0, -2)},
+		{HabitID: habit.ID, CompletedAt: now},
+	}
+	yesterday := now.AddDate(0, 0, -1)
+	restDay := time.Date(yesterday.Year(), yesterday.Month(), yesterday.Day(), 0, 0, 0, 0, time.UTC)
+
+	mockRepo.On("GetByUserID", mock.Anything, userID, models.HabitSortPosition).Return([]models.Habit{habit}, nil)
+	mockRepo.On("GetAllCompletions", mock.Anything, userID).Return(completions, nil)
+	mockRepo.On("GetRestDays", mock.Anything, userID).Return([]time.Time{restDay}, nil)
+
+	req, _ := http.NewRequest("GET", "/habits/streaks", nil)
+	w := httptest.NewRecorder()
+	setupHabitRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data []models.HabitStreak `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.Len(t, response.Data, 1) {
+		assert.Equal(t, 2, response.Data[0].CurrentStreak)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetStreakFreezes_ReturnsBalance(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+
+	mockRepo.On("GetStreakFreezeBalance", mock.Anything, userID).Return(3, nil)
+
+	req, _ := http.NewRequest("GET", "/habits/streak-freezes", nil)
+	w := httptest.NewRecorder()
+	setupHabitRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"balance": 3}`, w.Body.String())
+}
+
+func spendStreakFreeze(router *gin.Engine, habitID uuid.UUID) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/habits/"+habitID.String()+"/streak-freeze", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSpendStreakFreeze_BridgesMissedDay(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	habit := &models.Habit{ID: uuid.New(), UserID: userID, Frequency: "daily", TargetCount: 1}
+
+	// Done today and on the three days before yesterday; yesterday was missed.
+	now := time.Now()
+	var completions []models.HabitCompletion
+	for _, daysAgo := range []int{0, 2, 3, 4} {
+		completions = append(completions, models.HabitCompletion{HabitID: habit.ID, CompletedAt: now.AddDate(0, 0, -daysAgo)})
+	}
+	yesterday := habit.PeriodStart(now.AddDate(0, 0, -1))
+
+	mockRepo.On("GetByID", mock.Anything, habit.ID, userID).Return(habit, nil)
+	mockRepo.On("GetCompletionsSince", mock.Anything, habit.ID, userID, time.Time{}).Return(completions, nil)
+	mockRepo.On("GetRestDays", mock.Anything, userID).Return(nil, nil)
+	mockRepo.On("SpendStreakFreeze", mock.Anything, habit.ID, userID, yesterday).Return(1, nil)
+
+	w := spendStreakFreeze(setupHabitRouter(mockRepo, userID), habit.ID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		FrozenPeriod string               `json:"frozen_period"`
+		Balance      int                  `json:"balance"`
+		Progress     models.HabitProgress `json:"progress"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, yesterday.Format(time.DateOnly), response.FrozenPeriod)
+	assert.Equal(t, 1, response.Balance)
+	assert.Equal(t, 4, response.Progress.CurrentStreak)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSpendStreakFreeze_TwoMissedDaysConflict(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	habit := &models.Habit{ID: uuid.New(), UserID: userID, Frequency: "daily", TargetCount: 1}
+
+	now := time.Now()
+	completions := []models.HabitCompletion{
+		{HabitID: habit.ID, CompletedAt: now},
+		{HabitID: habit.ID, CompletedAt: now.AddDate(0, 0, -3)},
+	}
+
+	mockRepo.On("GetByID", mock.Anything, habit.ID, userID).Return(habit, nil)
+	mockRepo.On("GetCompletionsSince", mock.Anything, habit.ID, userID, time.Time{}).Return(completions, nil)
+	mockRepo.On("GetRestDays", mock.Anything, userID).Return(nil, nil)
+
+	w := spendStreakFreeze(setupHabitRouter(mockRepo, userID), habit.ID)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockRepo.AssertNotCalled(t, "SpendStreakFreeze", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSpendStreakFreeze_NoTokensLeft(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	habit := &models.Habit{ID: uuid.New(), UserID: userID, Frequency: "daily", TargetCount: 1}
+
+	now := time.Now()
+	completions := []models.HabitCompletion{
+		{HabitID: habit.ID, CompletedAt: now},
+		{HabitID: habit.ID, CompletedAt: now.AddDate(0, 0, -2)},
+	}
+
+	mockRepo.On("GetByID", mock.Anything, habit.ID, userID).Return(habit, nil)
+	mockRepo.On("GetCompletionsSince", mock.Anything, habit.ID, userID, time.Time{}).Return(completions, nil)
+	mockRepo.On("GetRestDays", mock.Anything, userID).Return(nil, nil)
+	mockRepo.On("SpendStreakFreeze", mock.Anything, habit.ID, userID, mock.AnythingOfType("time.Time")).Return(0, models.ErrNoFreezeTokens)
+
+	w := spendStreakFreeze(setupHabitRouter(mockRepo, userID), habit.ID)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "no streak freeze tokens left")
+}
+
+func TestResetStreak_RestartsStreakAndKeepsHistory(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	habit := &models.Habit{ID: uuid.New(), UserID: userID, Frequency: "daily", TargetCount: 1}
+
+	// A five-day run ending yesterday, then a reset just now.
+	now := time.Now()
+	var completions []models.HabitCompletion
+	for daysAgo := 1; daysAgo <= 5; daysAgo++ {
+		completions = append(completions, models.HabitCompletion{HabitID: habit.ID, CompletedAt: now.AddDate(0, 0, -daysAgo)})
+	}
+	resetAt := now.Add(-time.Minute)
+
+	mockRepo.On("GetByID", mock.Anything, habit.ID, userID).Return(habit, nil)
+	mockRepo.On("ResetStreak", mock.Anything, habit).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Habit).StreakResetAt = &resetAt
+	}).Return(nil)
+	mockRepo.On("GetCompletionsSince", mock.Anything, habit.ID, userID, time.Time{}).Return(completions, nil)
+	mockRepo.On("GetRestDays", mock.Anything, userID).Return(nil, nil)
+
+	req, _ := http.NewRequest("POST", "/habits/"+habit.ID.String()+"/reset-streak", nil)
+	w := httptest.NewRecorder()
+	setupHabitRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		StreakResetAt time.Time            `json:"streak_reset_at"`
+		Progress      models.HabitProgress `json:"progress"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, resetAt.Equal(response.StreakResetAt))
+	assert.Equal(t, 0, response.Progress.CurrentStreak)
+	assert.Equal(t, 0, response.Progress.LongestStreak)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestResetStreak_UnknownHabit(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	habitID := uuid.New()
+
+	mockRepo.On("GetByID", mock.Anything, habitID, userID).Return(nil, models.ErrNotFound)
+
+	req, _ := http.NewRequest("POST", "/habits/"+habitID.String()+"/reset-streak", nil)
+	w := httptest.NewRecorder()
+	setupHabitRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockRepo.AssertNotCalled(t, "ResetStreak", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateHabit_AppendedAtEnd(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+
+	// The repository assigns the next free position; the handler must not
+	// pick one itself.
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(h *models.Habit) bool {
+		return h.Position == 0
+	})).Run(func(args mock.Arguments) {
+		habit := args.Get(1).(*models.Habit)
+		habit.ID = uuid.New()
+		habit.Position = 3
+	}).Return(nil)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"name":         "Stretch",
+		"color":        "#4CAF50",
+		"icon":         "yoga",
+		"frequency":    "daily",
+		"target_count": 1,
+		"position":     0,
+	})
+	req, _ := http.NewRequest("POST", "/habits", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	setupHabitRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var habit models.Habit
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &habit))
+	assert.Equal(t, 3, habit.Position)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateHabit_RejectsInvalidReminderTimes(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"name":           "Stretch",
+		"color":          "#4CAF50",
+		"icon":           "yoga",
+		"frequency":      "daily",
+		"target_count":   1,
+		"reminder_times": []string{"08:00", "25:00"},
+	})
+	req, _ := http.NewRequest("POST", "/habits", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	setupHabitRouter(mockRepo, uuid.New()).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid reminder time")
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestUpdateHabit_RejectsDuplicateReminderTimes(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	habit := &models.Habit{ID: uuid.New(), UserID: userID, Name: "Stretch", Frequency: "daily", TargetCount: 1}
+
+	mockRepo.On("GetByID", mock.Anything, habit.ID, userID).Return(habit, nil)
+
+	body, _ := json.Marshal(map[string]interface{}{"reminder_times": []string{"08:00", "08:00"}})
+	req, _ := http.NewRequest("PATCH", "/habits/"+habit.ID.String(), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	setupHabitRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdateHabit_LowersTargetBelowLoggedCompletions(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	habit := &models.Habit{ID: uuid.New(), UserID: userID, Name: "Push-ups", Frequency: "weekly", TargetCount: 5, IsActive: true}
+
+	// Completions are left untouched; only the target changes.
+	mockRepo.On("GetByID", mock.Anything, habit.ID, userID).Return(habit, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(h *models.Habit) bool {
+		return h.TargetCount == 1
+	})).Return(nil)
+
+	body, _ := json.Marshal(map[string]interface{}{"target_count": 1})
+	req, _ := http.NewRequest("PATCH", "/habits/"+habit.ID.String(), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	setupHabitRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"target_count":1`)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateHabit_SetsAndClearsTargetAmount(t *testing.T) {
+	userID := uuid.New()
+
+	patch := func(habit *models.Habit, body string, matches func(*models.Habit) bool) *httptest.ResponseRecorder {
+		mockRepo := new(MockHabitRepo)
+		mockRepo.On("GetByID", mock.Anything, habit.ID, userID).Return(habit, nil)
+		mockRepo.On("Update", mock.Anything, mock.MatchedBy(matches)).Return(nil)
+
+		req, _ := http.NewRequest("PATCH", "/habits/"+habit.ID.String()+"?return=changes", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupHabitRouter(mockRepo, userID).ServeHTTP(w, req)
+		mockRepo.AssertExpectations(t)
+		return w
+	}
+
+	countBased := &models.Habit{ID: uuid.New(), UserID: userID, Name: "Water", Frequency: "daily", TargetCount: 1}
+	w := patch(countBased, `{"target_amount": 2000}`, func(h *models.Habit) bool {
+		return h.TargetAmount != nil && *h.TargetAmount == 2000
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"changes":["target_amount"]`)
+
+	amount := 2000.0
+	amountBased := &models.Habit{ID: uuid.New(), UserID: userID, Name: "Water", Frequency: "daily", TargetCount: 1, TargetAmount: &amount}
+	w = patch(amountBased, `{"target_amount": null}`, func(h *models.Habit) bool {
+		return h.TargetAmount == nil
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"changes":["target_amount"]`)
+	assert.Contains(t, w.Body.String(), `"target_amount":null`)
+}
+
+func TestUpdateHabit_RejectsNonPositiveTargetAmount(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	habit := &models.Habit{ID: uuid.New(), UserID: userID, Name: "Water", Frequency: "daily", TargetCount: 1}
+
+	mockRepo.On("GetByID", mock.Anything, habit.ID, userID).Return(habit, nil)
+
+	req, _ := http.NewRequest("PATCH", "/habits/"+habit.ID.String(), bytes.NewBufferString(`{"target_amount": 0}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	setupHabitRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdateHabit_RejectsZeroTarget(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	habit := &models.Habit{ID: uuid.New(), UserID: userID, Name: "Push-ups", Frequency: "weekly", TargetCount: 5}
+
+	mockRepo.On("GetByID", mock.Anything, habit.ID, userID).Return(habit, nil)
+
+	body, _ := json.Marshal(map[string]interface{}{"target_count": 0})
+	req, _ := http.NewRequest("PATCH", "/habits/"+habit.ID.String(), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	setupHabitRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestCreateHabit_NormalizesName(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(h *models.Habit) bool {
+		return h.Name == "Drink water"
+	})).Return(nil)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"name":         "  Drink    water ",
+		"color":        "#4CAF50",
+		"icon":         "glass",
+		"frequency":    "daily",
+		"target_count": 1,
+	})
+	req, _ := http.NewRequest("POST", "/habits", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	setupHabitRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateHabit_RejectsWhitespaceOnlyName(t *testing.T) {
+	mockRepo := new(MockHabitRepo)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"name":         "   \t ",
+		"color":        "#4CAF50",
+		"icon":         "glass",
+		"frequency":    "daily",
+		"target_count": 1,
+	})
+	req, _ := http.NewRequest("POST", "/habits", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	setupHabitRouter(mockRepo, uuid.New()).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
@@ -5,15 +5,22 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lumen/backend/internal/middleware"
 	"github.com/lumen/backend/internal/repository"
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
 )
 
 type HealthHandler struct {
 	db *repository.Database
+	// readinessTables are queried by Ready on top of the ping, if any.
+	readinessTables []string
+	// bodySizes is reported by Metrics, if set.
+	bodySizes *middleware.BodySizeMetrics
 }
 
-func NewHealthHandler(db *repository.Database) *HealthHandler {
-	return &HealthHandler{db: db}
+func NewHealthHandler(db *repository.Database, readinessTables []string, bodySizes *middleware.BodySizeMetrics) *HealthHandler {
+	return &HealthHandler{db: db, readinessTables: readinessTables, bodySizes: bodySizes}
 }
 
 func (h *HealthHandler) Check(c *gin.Context) {
@@ -51,6 +58,17 @@ func (h *HealthHandler) Ready(c *gin.Context) {
 		return
 	}
 
+	if len(h.readinessTables) > 0 {
+		if err := h.db.QueryHealth(ctx, h.readinessTables); err != nil {
+			logger.Warn("Readiness query failed", zap.Error(err))
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"status":  "not ready",
+				"message": "database tables not queryable",
+			})
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"status": "ready",
 	})
@@ -59,7 +77,8 @@ func (h *HealthHandler) Ready(c *gin.Context) {
 func (h *HealthHandler) Metrics(c *gin.Context) {
 	stats := h.db.Stats()
 	c.JSON(http.StatusOK, gin.H{
-		"database": stats,
-		"timestamp": time.Now().Format(time.RFC3339),
+		"database":           stats,
+		"request_body_bytes": h.bodySizes.Snapshot(),
+		"timestamp":          time.Now().Format(time.RFC3339),
 	})
 }
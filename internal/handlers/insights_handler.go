@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/repository"
+	apperrors "github.com/lumen/backend/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// MaxInsightDays caps how many days one insight request may cover.
+const MaxInsightDays = 366
+
+// InsightsHandler serves figures derived from the user's daily logs.
+type InsightsHandler struct {
+	dailyLogs repository.DailyLogRepository
+}
+
+func NewInsightsHandler(dailyLogs repository.DailyLogRepository) *InsightsHandler {
+	return &InsightsHandler{dailyLogs: dailyLogs}
+}
+
+// SleepProductivity returns how strongly sleep correlates with productivity
+// over the logs between start_date and end_date (inclusive).
+func (h *InsightsHandler) SleepProductivity(c *gin.Context) {
+	startDateStr := c.Query("start_date")
+	endDateStr := c.Query("end_date")
+
+	if startDateStr == "" || endDateStr == "" {
+		appErr := apperrors.NewBadRequest("start_date and end_date query parameters are required")
+		respondError(c, appErr)
+		return
+	}
+
+	startDate, err := parseDate(startDateStr)
+	if err != nil {
+		appErr := apperrors.NewBadRequest("start_date: " + err.Error())
+		respondError(c, appErr)
+		return
+	}
+
+	endDate, err := parseDate(endDateStr)
+	if err != nil {
+		appErr := apperrors.NewBadRequest("end_date: " + err.Error())
+		respondError(c, appErr)
+		return
+	}
+
+	if endDate.Before(startDate) {
+		appErr := apperrors.NewBadRequest("end_date must not be before start_date")
+		respondError(c, appErr)
+		return
+	}
+
+	if endDate.Sub(startDate) >= MaxInsightDays*24*time.Hour {
+		appErr := apperrors.NewBadRequest(fmt.Sprintf("the range may cover at most %d days", MaxInsightDays))
+		respondError(c, appErr)
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	logs, err := h.dailyLogs.GetByDateRange(c.Request.Context(), userID, startDate, endDate, models.DailyLogPage{})
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get daily logs", zap.String("user_id", userID.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       models.NewSleepProductivity(logs),
+		"start_date": startDateStr,
+		"end_date":   endDateStr,
+	})
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupInsightsRouter(repo *MockDailyLogRepository, userID uuid.UUID) *gin.Engine {
+	router := setupTestRouter()
+	handler := NewInsightsHandler(repo)
+
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	})
+	router.GET("/insights/sleep-productivity", handler.SleepProductivity)
+
+	return router
+}
+
+func getSleepProductivity(router *gin.Engine, query string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/insights/sleep-productivity"+query, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSleepProductivity_CorrelatesLogsInRange(t *testing.T) {
+	mockRepo := new(MockDailyLogRepository)
+	userID := uuid.New()
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	mockRepo.On("GetByDateRange", mock.Anything, userID, start, end, models.DailyLogPage{}).Return([]models.DailyLog{
+		{SleepHours: 6, ProductivityRating: 2},
+		{SleepHours: 7, ProductivityRating: 3},
+		{SleepHours: 8, ProductivityRating: 4},
+	}, nil)
+
+	w := getSleepProductivity(setupInsightsRouter(mockRepo, userID), "?start_date=2026-10-01&end_date=2026-10-16")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data models.SleepProductivity `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.NotNil(t, response.Data.Coefficient) {
+		assert.Equal(t, 1.0, *response.Data.Coefficient)
+	}
+	assert.Equal(t, 3, response.Data.SampleSize)
+	assert.NotEmpty(t, response.Data.Interpretation)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSleepProductivity_InsufficientDataIsNull(t *testing.T) {
+	mockRepo := new(MockDailyLogRepository)
+	userID := uuid.New()
+
+	mockRepo.On("GetByDateRange", mock.Anything, userID, mock.Anything, mock.Anything, mock.Anything).
+		Return([]models.DailyLog{{SleepHours: 7, ProductivityRating: 3}}, nil)
+
+	w := getSleepProductivity(setupInsightsRouter(mockRepo, userID), "?start_date=2026-10-01&end_date=2026-10-16")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"coefficient":null`)
+	assert.Contains(t, w.Body.String(), `"sample_size":1`)
+}
+
+func TestSleepProductivity_RejectsBadRanges(t *testing.T) {
+	for _, query := range []string{
+		"",
+		"?start_date=2026-10-01",
+		"?start_date=2026-10-16&end_date=2026-10-01",
+		"?start_date=2025-01-01&end_date=2026-10-16",
+		"?start_date=yesterday&end_date=2026-10-16",
+	} {
+		t.Run(query, func(t *testing.T) {
+			mockRepo := new(MockDailyLogRepository)
+			w := getSleepProductivity(setupInsightsRouter(mockRepo, uuid.New()), query)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockRepo.AssertNotCalled(t, "GetByDateRange", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
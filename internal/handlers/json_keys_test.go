@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/middleware"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// nonSnakeCaseKeys returns the paths of object keys in value holding an
+// uppercase letter, i.e. camelCase or PascalCase names leaking from untagged
+// struct fields.
+func nonSnakeCaseKeys(value interface{}, path string) []string {
+	var found []string
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if strings.ToLower(key) != key {
+				found = append(found, path+key)
+			}
+			found = append(found, nonSnakeCaseKeys(child, path+key+".")...)
+		}
+	case []interface{}:
+		for i, child := range v {
+			found = append(found, nonSnakeCaseKeys(child, fmt.Sprintf("%s[%d].", path, i))...)
+		}
+	}
+	return found
+}
+
+func TestResponses_UseSnakeCaseKeys(t *testing.T) {
+	userID := uuid.New()
+	now := time.Now()
+	task := existingTask(userID)
+	task.Tags = []string{"work"}
+	task.DueDate = &now
+	habit := models.Habit{
+		ID: uuid.New(), UserID: userID, Name: "Run", Color: "#10B981", Icon: "run",
+		Frequency: "daily", TargetCount: 1, IsActive: true, ReminderTimes: []string{"07:00"},
+		CreatedAt: now.AddDate(0, 0, -10),
+	}
+	completion := models.HabitCompletion{ID: uuid.New(), HabitID: habit.ID, UserID: userID, CompletedAt: now}
+
+	tasks := new(MockTaskRepository)
+	tasks.On("GetByUserID", mock.Anything, userID, mock.Anything).Return([]models.Task{*task}, nil)
+	tasks.On("GetByID", mock.Anything, task.ID, userID).Return(task, nil)
+	tasks.On("GetByID", mock.Anything, mock.Anything, userID).Return(nil, models.ErrNotFound)
+	tasks.On("GetCompletedSince", mock.Anything, userID, mock.Anything).Return([]time.Time{now}, nil)
+	tasks.On("Create", mock.Anything, mock.Anything).Return(nil)
+	tasks.On("Update", mock.Anything, task).Return(nil)
+	tasks.On("Snooze", mock.Anything, task).Return(nil)
+	taskRouter := setupTaskRouter(tasks, userID)
+
+	habits := new(MockHabitRepo)
+	habits.On("GetByUserID", mock.Anything, userID, mock.Anything).Return([]models.Habit{habit}, nil)
+	habits.On("GetDueOn", mock.Anything, userID, mock.Anything).Return([]models.Habit{habit}, nil)
+	habits.On("GetIncompleteOn", mock.Anything, userID, mock.Anything).
+		Return([]models.IncompleteHabit{{Habit: habit, PeriodTarget: 1, Remaining: 1}}, nil)
+	habits.On("GetByID", mock.Anything, habit.ID, userID).Return(&habit, nil)
+	habits.On("GetAllCompletions", mock.Anything, userID).Return([]models.HabitCompletion{completion}, nil)
+	habits.On("GetCompletionsSince", mock.Anything, habit.ID, userID, mock.Anything).Return([]models.HabitCompletion{completion}, nil)
+	habits.On("GetRestDays", mock.Anything, userID).Return([]time.Time{}, nil)
+	habits.On("GetStreakFreezeBalance", mock.Anything, userID).Return(2, nil)
+	habits.On("Create", mock.Anything, mock.Anything).Return(nil)
+	habits.On("Update", mock.Anything, mock.Anything).Return(nil)
+	habits.On("Reorder", mock.Anything, userID, []uuid.UUID{habit.ID}).Return(nil)
+	mergedID := uuid.New()
+	habits.On("Merge", mock.Anything, mergedID, habit.ID, userID).Return(3, nil)
+	habitRouter := setupHabitRouter(habits, userID)
+
+	completions := new(MockHabitCompletionRepository)
+	completions.On("GetHourCounts", mock.Anything, habit.ID, userID, mock.Anything).Return([]models.HourCount{{Hour: 7, Count: 3}}, nil)
+	completions.On("GetWeekdayCounts", mock.Anything, habit.ID, userID, mock.Anything, mock.Anything, mock.Anything).Return([]models.WeekdayCount{{Weekday: 2, Count: 3}}, nil)
+	timezones := new(MockReminderRepository)
+	timezones.On("GetUserTimezone", mock.Anything, userID).Return("UTC", nil)
+	completionRouter := setupCompletionRouterWithTimezones(completions, habits, timezones, userID)
+
+	dailyLogs := new(MockDailyLogRepository)
+	dailyLogs.On("GetByDateRange", mock.Anything, userID, mock.Anything, mock.Anything, mock.Anything).
+		Return([]models.DailyLog{{ID: uuid.New(), UserID: userID, Date: now}}, nil)
+	dailyLogs.On("GetAverages", mock.Anything, userID, mock.Anything, mock.Anything).Return(&models.DailyLogAverages{}, nil)
+	dailyLogs.On("Create", mock.Anything, mock.Anything).Return(nil)
+	dailyLogs.On("GetByDate", mock.Anything, userID, mock.Anything).Return(&models.DailyLog{ID: uuid.New(), UserID: userID, Date: now}, nil)
+	dailyLogs.On("Update", mock.Anything, mock.Anything).Return(nil)
+	dailyLogRouter := setupDailyLogRouter(dailyLogs, pagination.NewCursors("secret"), userID)
+	insightsRouter := setupInsightsRouter(dailyLogs, userID)
+
+	stats := new(MockDailyStats)
+	stats.On("Stats", mock.Anything, userID, mock.Anything, mock.Anything, mock.Anything).
+		Return([]models.DailyLogStats{{Date: now, HabitsCompleted: 1, HabitsTotal: 2}}, nil)
+	statsRouter := setupDailyStatsRouter(stats, userID)
+
+	goalID := uuid.New()
+	goals := new(MockGoalRepository)
+	goals.On("GetByID", mock.Anything, goalID, userID).Return(&models.Goal{ID: goalID, UserID: userID}, nil)
+	goals.On("GetItems", mock.Anything, goalID, userID).Return(models.GoalItems{
+		Habits: []models.Habit{habit},
+		Tasks:  []models.Task{*task},
+	}, nil)
+	goalRouter := setupGoalRouter(goals, userID)
+
+	reminders := new(MockReminderEventRepository)
+	reminders.On("GetSince", mock.Anything, userID, mock.Anything).
+		Return([]models.ReminderEvent{{ID: uuid.New(), UserID: userID, Kind: "habit", SentAt: now.Add(-3 * time.Hour)}}, nil)
+	reminderRouter := setupReminderRouter(reminders, &recordingPublisher{}, userID)
+
+	maintenanceRouter := setupMaintenanceRouter(middleware.NewMaintenance(false, "Back soon"))
+
+	for _, tc := range []struct {
+		router *gin.Engine
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{taskRouter, "GET", "/tasks?status=todo&has_due_date=true&tags=work", "", http.StatusOK},
+		{taskRouter, "GET", "/tasks/" + task.ID.String(), "", http.StatusOK},
+		{taskRouter, "GET", "/tasks/" + uuid.New().String(), "", http.StatusNotFound},
+		{taskRouter, "GET", "/tasks/velocity?period=week", "", http.StatusOK},
+		{taskRouter, "POST", "/tasks", `{"title": "Write report", "horizon": "now", "priority": "high", "tags": ["work"]}`, http.StatusCreated},
+		{taskRouter, "POST", "/tasks", `{"title": ""}`, http.StatusUnprocessableEntity},
+		{taskRouter, "PATCH", "/tasks/" + task.ID.String() + "?return=changes", `{"priority": "urgent"}`, http.StatusOK},
+		{taskRouter, "POST", "/tasks/" + task.ID.String() + "/snooze", `{"duration": "1d"}`, http.StatusOK},
+		{habitRouter, "GET", "/habits", "", http.StatusOK},
+		{habitRouter, "GET", "/habits/due", "", http.StatusOK},
+		{habitRouter, "GET", "/habits/streaks", "", http.StatusOK},
+		{habitRouter, "GET", "/habits/streak-freezes", "", http.StatusOK},
+		{habitRouter, "GET", "/habits/" + habit.ID.String(), "", http.StatusOK},
+		{habitRouter, "POST", "/habits", `{"name": "Read", "color": "#10B981", "icon": "book", "frequency": "daily", "target_count": 1}`, http.StatusCreated},
+		{habitRouter, "PATCH", "/habits/" + habit.ID.String() + "?return=changes", `{"name": "Run far"}`, http.StatusOK},
+		{habitRouter, "PATCH", "/habits/reorder", `{"habit_ids": ["` + habit.ID.String() + `"]}`, http.StatusOK},
+		{habitRouter, "POST", "/habits/" + mergedID.String() + "/merge", `{"target_id": "` + habit.ID.String() + `"}`, http.StatusOK},
+		{completionRouter, "GET", "/habits/" + habit.ID.String() + "/best-time", "", http.StatusOK},
+		{completionRouter, "GET", "/habits/" + habit.ID.String() + "/by-weekday", "", http.StatusOK},
+		{completionRouter, "GET", "/habits/incomplete-today", "", http.StatusOK},
+		{dailyLogRouter, "GET", "/daily-log?start_date=2026-10-01&end_date=2026-10-16", "", http.StatusOK},
+		{dailyLogRouter, "GET", "/daily-log/averages", "", http.StatusOK},
+		{dailyLogRouter, "GET", "/daily-log/incomplete?start_date=2026-10-01&end_date=2026-10-16", "", http.StatusOK},
+		{dailyLogRouter, "POST", "/daily-log", `{"date": "2026-10-15T00:00:00Z", "water_intake": 4, "mood_rating": 4}`, http.StatusCreated},
+		{dailyLogRouter, "PUT", "/daily-log/2026-10-15", `{"sleep_hours": 7.5}`, http.StatusOK},
+		{insightsRouter, "GET", "/insights/sleep-productivity?start_date=2026-10-01&end_date=2026-10-16", "", http.StatusOK},
+		{statsRouter, "GET", "/daily-log/stats?start_date=2026-10-14&end_date=2026-10-15", "", http.StatusOK},
+		{goalRouter, "GET", "/goals/" + goalID.String() + "/items", "", http.StatusOK},
+		{reminderRouter, "GET", "/reminders/effectiveness", "", http.StatusOK},
+		{maintenanceRouter, "GET", "/maintenance", "", http.StatusOK},
+	} {
+		name := tc.method + " " + tc.path
+		req, _ := http.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+		if tc.body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		w := httptest.NewRecorder()
+		tc.router.ServeHTTP(w, req)
+
+		assert.Equal(t, tc.status, w.Code, name)
+		var body interface{}
+		if assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), name) {
+			assert.Empty(t, nonSnakeCaseKeys(body, ""), "%s responded with non-snake_case keys", name)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lumen/backend/internal/middleware"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// MaintenanceSwitch reads and toggles maintenance mode.
+type MaintenanceSwitch interface {
+	Status() (bool, string)
+	Set(enabled bool, message string)
+}
+
+type MaintenanceHandler struct {
+	maintenance MaintenanceSwitch
+}
+
+func NewMaintenanceHandler(maintenance MaintenanceSwitch) *MaintenanceHandler {
+	return &MaintenanceHandler{maintenance: maintenance}
+}
+
+// Status returns the current maintenance state so clients can show a banner.
+func (h *MaintenanceHandler) Status(c *gin.Context) {
+	c.JSON(http.StatusOK, h.status())
+}
+
+// Update turns maintenance mode on or off. It is restricted to service keys.
+func (h *MaintenanceHandler) Update(c *gin.Context) {
+	var req models.UpdateMaintenanceRequest
+	if err := bindJSON(c, &req); err != nil {
+		appErr := bindError(err)
+		respondError(c, appErr)
+		return
+	}
+
+	h.maintenance.Set(*req.Enabled, req.Message)
+
+	service, _ := middleware.GetServiceIdentity(c)
+	logger.Info("Maintenance mode updated", zap.Bool("enabled", *req.Enabled), zap.String("service", service))
+	c.JSON(http.StatusOK, h.status())
+}
+
+func (h *MaintenanceHandler) status() models.MaintenanceStatus {
+	enabled, message := h.maintenance.Status()
+	return models.MaintenanceStatus{Enabled: enabled, Message: message}
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lumen/backend/internal/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupMaintenanceRouter(m *middleware.Maintenance) *gin.Engine {
+	router := setupTestRouter()
+	handler := NewMaintenanceHandler(m)
+
+	router.Use(middleware.ExceptPaths(m.ReadOnly(), "/admin/"))
+	router.GET("/maintenance", handler.Status)
+	router.PUT("/admin/maintenance", handler.Update)
+	router.GET("/tasks", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/tasks", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	return router
+}
+
+func putMaintenance(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("PUT", "/admin/maintenance", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMaintenance_ToggleBlocksWritesOnly(t *testing.T) {
+	m := middleware.NewMaintenance(false, "Scheduled maintenance")
+	router := setupMaintenanceRouter(m)
+
+	w := putMaintenance(router, `{"enabled": true}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"enabled": true, "message": "Scheduled maintenance"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/tasks", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/tasks", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/maintenance", nil)
+	router.ServeHTTP(w, req)
+	assert.JSONEq(t, `{"enabled": true, "message": "Scheduled maintenance"}`, w.Body.String())
+
+	// The toggle itself stays reachable while maintenance is on.
+	w = putMaintenance(router, `{"enabled": false}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/tasks", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestMaintenance_UpdateRequiresEnabled(t *testing.T) {
+	router := setupMaintenanceRouter(middleware.NewMaintenance(false, ""))
+
+	w := putMaintenance(router, `{"message": "hi"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
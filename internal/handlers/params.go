@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/middleware"
+	apperrors "github.com/lumen/backend/pkg/errors"
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// pathUUID returns a path param already validated by middleware.UUIDParams.
+// A missing value means the route was wired without the middleware.
+func pathUUID(c *gin.Context, name string) (uuid.UUID, bool) {
+	id, ok := middleware.GetUUIDParam(c, name)
+	if !ok {
+		logger.Error("Route is missing UUIDParams middleware", zap.String("param", name), zap.String("path", c.FullPath()))
+		appErr := apperrors.NewInternalServer(nil)
+		respondError(c, appErr)
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/repository"
+	apperrors "github.com/lumen/backend/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// PlannerHandler serves the week view combining due tasks and due habits.
+type PlannerHandler struct {
+	habits    repository.HabitRepository
+	tasks     repository.TaskRepository
+	timezones UserTimezones
+	profiles  repository.ProfileRepository
+}
+
+func NewPlannerHandler(habits repository.HabitRepository, tasks repository.TaskRepository, timezones UserTimezones, profiles repository.ProfileRepository) *PlannerHandler {
+	return &PlannerHandler{habits: habits, tasks: tasks, timezones: timezones, profiles: profiles}
+}
+
+// Week returns the seven days from ?start= (YYYY-MM-DD or RFC3339), each with
+// the tasks due and the habits due that day in the user's timezone. Without
+// start the week begins on the first day of the current week, Monday or
+// Sunday as the user's week start says.
+func (h *PlannerHandler) Week(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	loc, ok := loadUserLocation(c, h.timezones, userID)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	weekStart, err := h.profiles.GetWeekStart(ctx, userID)
+	if err != nil && err != models.ErrNotFound {
+		respondDatabaseError(c, err, "Failed to get week start", zap.String("user_id", userID.String()))
+		return
+	}
+
+	start := weekStart.StartOf(time.Now().In(loc))
+	if value := c.Query("start"); value != "" {
+		date, err := parseDate(value)
+		if err != nil {
+			appErr := apperrors.NewBadRequest("start: " + err.Error())
+			respondError(c, appErr)
+			return
+		}
+		start, _ = models.DayBounds(date, loc)
+	}
+	_, end := models.DayBounds(start.AddDate(0, 0, models.PlannerDays-1), loc)
+
+	habits, err := h.habits.GetByUserID(ctx, userID, models.HabitSortPosition)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get habits", zap.String("user_id", userID.String()))
+		return
+	}
+
+	completions, err := h.habits.GetCompletionsBetween(ctx, userID, models.PlannerCompletionsSince(start, weekStart), end)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get habit completions", zap.String("user_id", userID.String()))
+		return
+	}
+
+	tasks, err := h.tasks.GetDueBetween(ctx, userID, start, end)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get due tasks", zap.String("user_id", userID.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"start":    start.Format(dateLayout),
+		"end":      end.AddDate(0, 0, -1).Format(dateLayout),
+		"timezone": loc.String(),
+		"days":     models.PlanWeek(start, loc, habits, completions, tasks),
+	})
+}
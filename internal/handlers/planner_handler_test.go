@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type plannerWeekResponse struct {
+	Start    string              `json:"start"`
+	End      string              `json:"end"`
+	Timezone string              `json:"timezone"`
+	Days     []models.PlannerDay `json:"days"`
+}
+
+func setupPlannerRouter(habits *MockHabitRepo, tasks *MockTaskRepository, timezones *MockReminderRepository, userID uuid.UUID) *gin.Engine {
+	return setupPlannerRouterWithWeekStart(habits, tasks, timezones, models.WeekStartMonday, userID)
+}
+
+func setupPlannerRouterWithWeekStart(habits *MockHabitRepo, tasks *MockTaskRepository, timezones *MockReminderRepository, weekStart models.WeekStart, userID uuid.UUID) *gin.Engine {
+	router := setupTestRouter()
+	profiles := new(MockProfileRepository)
+	profiles.On("GetWeekStart", mock.Anything, userID).Return(string(weekStart), nil)
+	handler := NewPlannerHandler(habits, tasks, timezones, profiles)
+
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	})
+	router.GET("/planner/week", handler.Week)
+
+	return router
+}
+
+func TestPlannerWeek_ComposesDaysInUserTimezone(t *testing.T) {
+	habits := new(MockHabitRepo)
+	tasks := new(MockTaskRepository)
+	timezones := new(MockReminderRepository)
+	userID := uuid.New()
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+
+	start := time.Date(2026, 10, 12, 0, 0, 0, 0, tokyo)
+	end := time.Date(2026, 10, 19, 0, 0, 0, 0, tokyo)
+	monthStart := time.Date(2026, 10, 1, 0, 0, 0, 0, tokyo)
+
+	stretch := models.Habit{ID: uuid.New(), Name: "Stretch", Frequency: "daily", TargetCount: 1, IsActive: true}
+	review := models.Habit{ID: uuid.New(), Name: "Review", Frequency: "weekly", TargetCount: 1, IsActive: true}
+	dueAt := time.Date(2026, 10, 16, 0, 30, 0, 0, tokyo) // Friday in Tokyo, Thursday in UTC
+	report := models.Task{ID: uuid.New(), Title: "Report", DueDate: &dueAt}
+
+	timezones.On("GetUserTimezone", mock.Anything, userID).Return("Asia/Tokyo", nil)
+	habits.On("GetByUserID", mock.Anything, userID, models.HabitSortPosition).Return([]models.Habit{stretch, review}, nil)
+	habits.On("GetCompletionsBetween", mock.Anything, userID, monthStart, end).Return([]models.HabitCompletion{
+		{HabitID: review.ID, CompletedAt: time.Date(2026, 10, 14, 9, 0, 0, 0, tokyo)},
+	}, nil)
+	tasks.On("GetDueBetween", mock.Anything, userID, start, end).Return([]models.Task{report}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/planner/week?start=2026-10-12", nil)
+	w := httptest.NewRecorder()
+	setupPlannerRouter(habits, tasks, timezones, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response plannerWeekResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "2026-10-12", response.Start)
+	assert.Equal(t, "2026-10-18", response.End)
+	assert.Equal(t, "Asia/Tokyo", response.Timezone)
+	assert.Len(t, response.Days, 7)
+
+	for i, day := range response.Days {
+		names := []string{}
+		for _, habit := range day.Habits {
+			names = append(names, habit.Name)
+		}
+		if i <= 2 {
+			assert.Equal(t, []string{"Stretch", "Review"}, names, "day %d", i)
+		} else {
+			assert.Equal(t, []string{"Stretch"}, names, "day %d", i)
+		}
+
+		if i == 4 {
+			assert.Len(t, day.Tasks, 1)
+			assert.Equal(t, report.ID, day.Tasks[0].ID)
+		} else {
+			assert.Empty(t, day.Tasks, "day %d", i)
+		}
+	}
+
+	habits.AssertExpectations(t)
+	tasks.AssertExpectations(t)
+}
+
+func TestPlannerWeek_RejectsBadStart(t *testing.T) {
+	habits := new(MockHabitRepo)
+	tasks := new(MockTaskRepository)
+	timezones := new(MockReminderRepository)
+	userID := uuid.New()
+
+	timezones.On("GetUserTimezone", mock.Anything, userID).Return("UTC", nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/planner/week?start=next-monday", nil)
+	w := httptest.NewRecorder()
+	setupPlannerRouter(habits, tasks, timezones, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	tasks.AssertNotCalled(t, "GetDueBetween", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPlannerWeek_DefaultStartFollowsWeekStart(t *testing.T) {
+	for _, weekStart := range []models.WeekStart{models.WeekStartMonday, models.WeekStartSunday} {
+		t.Run(string(weekStart), func(t *testing.T) {
+			habits := new(MockHabitRepo)
+			tasks := new(MockTaskRepository)
+			timezones := new(MockReminderRepository)
+			userID := uuid.New()
+
+			timezones.On("GetUserTimezone", mock.Anything, userID).Return("UTC", nil)
+			habits.On("GetByUserID", mock.Anything, userID, models.HabitSortPosition).Return([]models.Habit{}, nil)
+			habits.On("GetCompletionsBetween", mock.Anything, userID, mock.Anything, mock.Anything).Return([]models.HabitCompletion{}, nil)
+			tasks.On("GetDueBetween", mock.Anything, userID, mock.Anything, mock.Anything).Return([]models.Task{}, nil)
+
+			req, _ := http.NewRequest(http.MethodGet, "/planner/week", nil)
+			w := httptest.NewRecorder()
+			setupPlannerRouterWithWeekStart(habits, tasks, timezones, weekStart, userID).ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response plannerWeekResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			start, err := time.Parse(time.DateOnly, response.Start)
+			assert.NoError(t, err)
+			assert.Equal(t, weekStart.Weekday(), start.Weekday())
+			assert.Equal(t, weekStart.StartOf(time.Now().UTC()), start)
+		})
+	}
+}
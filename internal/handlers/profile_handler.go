@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/repository"
+	apperrors "github.com/lumen/backend/pkg/errors"
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type ProfileHandler struct {
+	reminders repository.ReminderRepository
+	profiles  repository.ProfileRepository
+}
+
+func NewProfileHandler(reminders repository.ReminderRepository, profiles repository.ProfileRepository) *ProfileHandler {
+	return &ProfileHandler{reminders: reminders, profiles: profiles}
+}
+
+// UpdateDailyLogReminder sets or clears the local time at which the user is
+// reminded to fill in the day's log.
+func (h *ProfileHandler) UpdateDailyLogReminder(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	var req models.UpdateDailyLogReminderRequest
+	if err := bindJSON(c, &req); err != nil {
+		appErr := bindError(err)
+		respondError(c, appErr)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		appErr := apperrors.NewValidationError(err.Error())
+		respondError(c, appErr)
+		return
+	}
+
+	err := h.reminders.SetDailyLogReminderTime(c.Request.Context(), userID, req.Time)
+	if err == models.ErrNotFound {
+		appErr := apperrors.NewNotFound("user")
+		respondError(c, appErr)
+		return
+	}
+
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to update daily log reminder", zap.String("user_id", userID.String()))
+		return
+	}
+
+	logger.Info("Daily log reminder updated", zap.String("user_id", userID.String()))
+	c.JSON(http.StatusOK, gin.H{"daily_log_reminder_time": req.Time})
+}
+
+// UpdateWaterUnit sets the unit the user reads and enters daily log water
+// intake in. Stored logs are unaffected; they are converted on read.
+func (h *ProfileHandler) UpdateWaterUnit(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	var req models.UpdateWaterUnitRequest
+	if err := bindJSON(c, &req); err != nil {
+		appErr := bindError(err)
+		respondError(c, appErr)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		appErr := apperrors.NewValidationError(err.Error())
+		respondError(c, appErr)
+		return
+	}
+
+	err := h.profiles.SetWaterUnit(c.Request.Context(), userID, models.WaterUnit(req.Unit))
+	if err == models.ErrNotFound {
+		appErr := apperrors.NewNotFound("user")
+		respondError(c, appErr)
+		return
+	}
+
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to update water unit", zap.String("user_id", userID.String()))
+		return
+	}
+
+	logger.Info("Water unit updated", zap.String("user_id", userID.String()), zap.String("unit", req.Unit))
+	c.JSON(http.StatusOK, gin.H{"water_unit": req.Unit})
+}
+
+// UpdateWeekStart sets the day the user's weeks begin on. Weekly habit
+// periods, streaks and the planner week follow it from the next request.
+func (h *ProfileHandler) UpdateWeekStart(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	var req models.UpdateWeekStartRequest
+	if err := bindJSON(c, &req); err != nil {
+		appErr := bindError(err)
+		respondError(c, appErr)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		appErr := apperrors.NewValidationError(err.Error())
+		respondError(c, appErr)
+		return
+	}
+
+	err := h.profiles.SetWeekStart(c.Request.Context(), userID, models.WeekStart(req.Day))
+	if err == models.ErrNotFound {
+		appErr := apperrors.NewNotFound("user")
+		respondError(c, appErr)
+		return
+	}
+
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to update week start", zap.String("user_id", userID.String()))
+		return
+	}
+
+	logger.Info("Week start updated", zap.String("user_id", userID.String()), zap.String("day", req.Day))
+	c.JSON(http.StatusOK, gin.H{"week_start": req.Day})
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockReminderRepository is a mock for reminder repository
+type MockReminderRepository struct {
+	mock.Mock
+}
+
+func (m *MockReminderRepository) ListUserIDs(ctx context.Context, after uuid.UUID, limit int) ([]uuid.UUID, error) {
+	args := m.Called(ctx, after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockReminderRepository) GetUserTimezone(ctx context.Context, userID uuid.UUID) (string, error) {
+	args := m.Called(ctx, userID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockReminderRepository) GetDailyLogReminderTime(ctx context.Context, userID uuid.UUID) (string, error) {
+	args := m.Called(ctx, userID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockReminderRepository) SetDailyLogReminderTime(ctx context.Context, userID uuid.UUID, at *string) error {
+	args := m.Called(ctx, userID, at)
+	return args.Error(0)
+}
+
+// MockProfileRepository is a mock for profile repository
+type MockProfileRepository struct {
+	mock.Mock
+}
+
+func (m *MockProfileRepository) GetWaterUnit(ctx context.Context, userID uuid.UUID) (models.WaterUnit, error) {
+	args := m.Called(ctx, userID)
+	return models.WaterUnit(args.String(0)), args.Error(1)
+}
+
+func (m *MockProfileRepository) SetWaterUnit(ctx context.Context, userID uuid.UUID, unit models.WaterUnit) error {
+	args := m.Called(ctx, userID, unit)
+	return args.Error(0)
+}
+
+func (m *MockProfileRepository) GetWeekStart(ctx context.Context, userID uuid.UUID) (models.WeekStart, error) {
+	args := m.Called(ctx, userID)
+	return models.WeekStart(args.String(0)), args.Error(1)
+}
+
+func (m *MockProfileRepository) SetWeekStart(ctx context.Context, userID uuid.UUID, start models.WeekStart) error {
+	args := m.Called(ctx, userID, start)
+	return args.Error(0)
+}
+
+// glassesProfile returns a profile repository for a user who keeps the
+// default water unit.
+func glassesProfile() *MockProfileRepository {
+	profiles := new(MockProfileRepository)
+	profiles.On("GetWaterUnit", mock.Anything, mock.Anything).Return(string(models.WaterUnitGlasses), nil).Maybe()
+	return profiles
+}
+
+func setupProfileRouter(repo *MockReminderRepository, userID uuid.UUID) *gin.Engine {
+	return setupProfileRouterWithProfiles(repo, glassesProfile(), userID)
+}
+
+func setupProfileRouterWithProfiles(repo *MockReminderRepository, profiles *MockProfileRepository, userID uuid.UUID) *gin.Engine {
+	router := setupTestRouter()
+	handler := NewProfileHandler(repo, profiles)
+
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	})
+	router.PUT("/profile/daily-log-reminder", handler.UpdateDailyLogReminder)
+	router.PUT("/profile/water-unit", handler.UpdateWaterUnit)
+	router.PUT("/profile/week-start", handler.UpdateWeekStart)
+
+	return router
+}
+
+func putDailyLogReminder(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("PUT", "/profile/daily-log-reminder", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestUpdateDailyLogReminder_SetsTime(t *testing.T) {
+	mockRepo := new(MockReminderRepository)
+	userID := uuid.New()
+
+	mockRepo.On("SetDailyLogReminderTime", mock.Anything, userID, mock.MatchedBy(func(at *string) bool {
+		return at != nil && *at == "21:30"
+	})).Return(nil)
+
+	w := putDailyLogReminder(setupProfileRouter(mockRepo, userID), `{"time": "21:30"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"daily_log_reminder_time": "21:30"}`, w.Body.String())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateDailyLogReminder_NullTurnsOff(t *testing.T) {
+	mockRepo := new(MockReminderRepository)
+	userID := uuid.New()
+
+	mockRepo.On("SetDailyLogReminderTime", mock.Anything, userID, (*string)(nil)).Return(nil)
+
+	w := putDailyLogReminder(setupProfileRouter(mockRepo, userID), `{"time": null}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"daily_log_reminder_time": null}`, w.Body.String())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateDailyLogReminder_InvalidTime(t *testing.T) {
+	mockRepo := new(MockReminderRepository)
+
+	w := putDailyLogReminder(setupProfileRouter(mockRepo, uuid.New()), `{"time": "9pm"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockRepo.AssertNotCalled(t, "SetDailyLogReminderTime", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func putWaterUnit(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("PUT", "/profile/water-unit", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestUpdateWaterUnit_SetsUnit(t *testing.T) {
+	profiles := new(MockProfileRepository)
+	userID := uuid.New()
+
+	profiles.On("SetWaterUnit", mock.Anything, userID, models.WaterUnitLiters).Return(nil)
+
+	w := putWaterUnit(setupProfileRouterWithProfiles(new(MockReminderRepository), profiles, userID), `{"unit": "liters"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"water_unit": "liters"}`, w.Body.String())
+	profiles.AssertExpectations(t)
+}
+
+func TestUpdateWaterUnit_RejectsUnknownUnit(t *testing.T) {
+	profiles := new(MockProfileRepository)
+
+	w := putWaterUnit(setupProfileRouterWithProfiles(new(MockReminderRepository), profiles, uuid.New()), `{"unit": "cups"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	profiles.AssertNotCalled(t, "SetWaterUnit", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func putWeekStart(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("PUT", "/profile/week-start", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestUpdateWeekStart_SetsDay(t *testing.T) {
+	profiles := new(MockProfileRepository)
+	userID := uuid.New()
+
+	profiles.On("SetWeekStart", mock.Anything, userID, models.WeekStartSunday).Return(nil)
+
+	w := putWeekStart(setupProfileRouterWithProfiles(new(MockReminderRepository), profiles, userID), `{"day": "sunday"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"week_start": "sunday"}`, w.Body.String())
+	profiles.AssertExpectations(t)
+}
+
+func TestUpdateWeekStart_RejectsUnknownDay(t *testing.T) {
+	profiles := new(MockProfileRepository)
+
+	w := putWeekStart(setupProfileRouterWithProfiles(new(MockReminderRepository), profiles, uuid.New()), `{"day": "saturday"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	profiles.AssertNotCalled(t, "SetWeekStart", mock.Anything, mock.Anything, mock.Anything)
+}
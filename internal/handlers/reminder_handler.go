@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/events"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/repository"
+	apperrors "github.com/lumen/backend/pkg/errors"
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ReminderHandler serves acknowledgments of sent reminders. An
+// acknowledgment within ackWindow of the reminder counts as the user acting
+// on it.
+type ReminderHandler struct {
+	repo      repository.ReminderEventRepository
+	events    events.Publisher
+	ackWindow time.Duration
+}
+
+func NewReminderHandler(repo repository.ReminderEventRepository, publisher events.Publisher, ackWindow time.Duration) *ReminderHandler {
+	return &ReminderHandler{repo: repo, events: publisher, ackWindow: ackWindow}
+}
+
+// Acknowledge records that the user acted on a reminder. Repeat calls keep
+// the first acknowledgment.
+func (h *ReminderHandler) Acknowledge(c *gin.Context) {
+	eventID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	// Postgres keeps microseconds, so a first acknowledgment reads back equal.
+	now := time.Now().Truncate(time.Microsecond)
+
+	event, err := h.repo.Acknowledge(c.Request.Context(), eventID, userID, now)
+	if err == models.ErrNotFound {
+		appErr := apperrors.NewNotFoundWithID("reminder", eventID)
+		respondError(c, appErr)
+		return
+	}
+
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to acknowledge reminder", zap.String("reminder_id", eventID.String()))
+		return
+	}
+
+	actedOn := event.ActedOn(h.ackWindow)
+	if event.AcknowledgedAt.Equal(now) {
+		logger.Info("Reminder acknowledged", zap.String("reminder_id", eventID.String()), zap.Bool("acted_on", actedOn))
+		h.events.Publish(events.ReminderAcknowledged{Reminder: *event, ActedOn: actedOn})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":     event,
+		"acted_on": actedOn,
+	})
+}
+
+// GetEffectiveness reports how many of the reminders sent over the last
+// ?days= days (default 30) the user acted on.
+func (h *ReminderHandler) GetEffectiveness(c *gin.Context) {
+	days := models.DefaultReminderEffectivenessDays
+	if value := c.Query("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > models.MaxReminderEffectivenessDays {
+			appErr := apperrors.NewBadRequest(fmt.Sprintf("invalid days: must be between 1 and %d", models.MaxReminderEffectivenessDays))
+			respondError(c, appErr)
+			return
+		}
+		days = parsed
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	now := time.Now()
+	since := now.AddDate(0, 0, -days)
+	reminders, err := h.repo.GetSince(c.Request.Context(), userID, since)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get reminder events", zap.String("user_id", userID.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": models.ComputeReminderEffectiveness(reminders, h.ackWindow, now),
+		"days": days,
+	})
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/events"
+	"github.com/lumen/backend/internal/middleware"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReminderEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockReminderEventRepository) Create(ctx context.Context, event *models.ReminderEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+// Acknowledge returns the mocked event, or calls a func(time.Time) return
+// value with the acknowledgment time, as the database would stamp it.
+func (m *MockReminderEventRepository) Acknowledge(ctx context.Context, id, userID uuid.UUID, at time.Time) (*models.ReminderEvent, error) {
+	args := m.Called(ctx, id, userID, at)
+	if fn, ok := args.Get(0).(func(time.Time) *models.ReminderEvent); ok {
+		return fn(at), args.Error(1)
+	}
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReminderEvent), args.Error(1)
+}
+
+func (m *MockReminderEventRepository) GetSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]models.ReminderEvent, error) {
+	args := m.Called(ctx, userID, since)
+	return args.Get(0).([]models.ReminderEvent), args.Error(1)
+}
+
+func setupReminderRouter(repo *MockReminderEventRepository, publisher events.Publisher, userID uuid.UUID) *gin.Engine {
+	router := setupTestRouter()
+	handler := NewReminderHandler(repo, publisher, time.Hour)
+
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	})
+	router.Use(middleware.UUIDParams("id"))
+	router.POST("/reminders/:id/ack", handler.Acknowledge)
+	router.GET("/reminders/effectiveness", handler.GetEffectiveness)
+
+	return router
+}
+
+func ackReminder(router *gin.Engine, id uuid.UUID) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/reminders/"+id.String()+"/ack", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAcknowledgeReminder_RecordsAndPublishes(t *testing.T) {
+	mockRepo := new(MockReminderEventRepository)
+	publisher := &recordingPublisher{}
+	userID, eventID := uuid.New(), uuid.New()
+	sent := time.Now().Add(-10 * time.Minute)
+
+	mockRepo.On("Acknowledge", mock.Anything, eventID, userID, mock.Anything).Return(func(at time.Time) *models.ReminderEvent {
+		return &models.ReminderEvent{ID: eventID, UserID: userID, Kind: "habit", SentAt: sent, AcknowledgedAt: &at}
+	}, nil)
+
+	w := ackReminder(setupReminderRouter(mockRepo, publisher, userID), eventID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Data    models.ReminderEvent `json:"data"`
+		ActedOn bool                 `json:"acted_on"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.ActedOn)
+	assert.NotNil(t, response.Data.AcknowledgedAt)
+
+	if assert.Len(t, publisher.events, 1) {
+		acked := publisher.events[0].(events.ReminderAcknowledged)
+		assert.True(t, acked.ActedOn)
+		assert.Equal(t, true, acked.EventProperties()["acted_on"])
+		assert.Equal(t, int64(600), acked.EventProperties()["latency_seconds"])
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAcknowledgeReminder_RepeatKeepsFirstAndDoesNotRepublish(t *testing.T) {
+	mockRepo := new(MockReminderEventRepository)
+	publisher := &recordingPublisher{}
+	userID, eventID := uuid.New(), uuid.New()
+	sent := time.Now().Add(-5 * time.Hour)
+	first := sent.Add(3 * time.Hour)
+
+	mockRepo.On("Acknowledge", mock.Anything, eventID, userID, mock.Anything).
+		Return(&models.ReminderEvent{ID: eventID, UserID: userID, SentAt: sent, AcknowledgedAt: &first}, nil)
+
+	w := ackReminder(setupReminderRouter(mockRepo, publisher, userID), eventID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"acted_on":false`)
+	assert.Empty(t, publisher.events)
+}
+
+func TestAcknowledgeReminder_OtherUsersReminderIsNotFound(t *testing.T) {
+	mockRepo := new(MockReminderEventRepository)
+	publisher := &recordingPublisher{}
+	userID, eventID := uuid.New(), uuid.New()
+
+	mockRepo.On("Acknowledge", mock.Anything, eventID, userID, mock.Anything).Return(nil, models.ErrNotFound)
+
+	w := ackReminder(setupReminderRouter(mockRepo, publisher, userID), eventID)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, publisher.events)
+}
+
+func TestGetReminderEffectiveness(t *testing.T) {
+	mockRepo := new(MockReminderEventRepository)
+	userID := uuid.New()
+	sent := time.Now().Add(-24 * time.Hour)
+	quick, late := sent.Add(time.Minute), sent.Add(3*time.Hour)
+
+	mockRepo.On("GetSince", mock.Anything, userID, mock.MatchedBy(func(since time.Time) bool {
+		return time.Since(since) > 6*24*time.Hour && time.Since(since) < 8*24*time.Hour
+	})).Return([]models.ReminderEvent{
+		{SentAt: sent, AcknowledgedAt: &quick},
+		{SentAt: sent, AcknowledgedAt: &late},
+		{SentAt: sent},
+		{SentAt: sent},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/reminders/effectiveness?days=7", nil)
+	w := httptest.NewRecorder()
+	setupReminderRouter(mockRepo, &recordingPublisher{}, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":{"sent":4,"acted_on":1,"rate":25},"days":7}`, w.Body.String())
+}
+
+func TestGetReminderEffectiveness_RejectsInvalidDays(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/reminders/effectiveness?days=0", nil)
+	w := httptest.NewRecorder()
+	setupReminderRouter(new(MockReminderEventRepository), &recordingPublisher{}, uuid.New()).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lumen/backend/internal/middleware"
+	apperrors "github.com/lumen/backend/pkg/errors"
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// respondUpdated writes the updated resource. With ?return=changes the
+// resource is wrapped alongside the names of the fields that actually changed.
+func respondUpdated(c *gin.Context, resource interface{}, changes []string) {
+	if c.Query("return") == "changes" {
+		c.JSON(http.StatusOK, gin.H{
+			"data":    resource,
+			"changes": changes,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resource)
+}
+
+// respondError writes appErr localized for the client; see
+// middleware.RespondError.
+func respondError(c *gin.Context, appErr *apperrors.AppError) {
+	middleware.RespondError(c, appErr)
+}
+
+// respondDatabaseError logs a failed repository call and responds 500. If the
+// client has already disconnected, the failure is most likely the canceled
+// context aborting the query, so nothing is logged or written beyond
+// middleware.StatusClientClosedRequest; middleware.ClientClosed records the
+// disconnect instead.
+func respondDatabaseError(c *gin.Context, err error, msg string, fields ...zap.Field) {
+	if middleware.ClientCanceled(c) {
+		c.AbortWithStatus(middleware.StatusClientClosedRequest)
+		return
+	}
+
+	logger.Error(msg, append([]zap.Field{zap.Error(err)}, fields...)...)
+	appErr := apperrors.NewDatabaseError(err)
+	respondError(c, appErr)
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/middleware"
+	"github.com/lumen/backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func observeLogs(t *testing.T) *observer.ObservedLogs {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger.SetGlobal(zap.New(core))
+	t.Cleanup(func() { logger.SetGlobal(zap.NewNop()) })
+	return logs
+}
+
+// canceledRequest builds a request whose client has already disconnected.
+func canceledRequest(method, path, body string) *http.Request {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(ctx, method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestRespondDatabaseError_ClientCanceledCreate(t *testing.T) {
+	logs := observeLogs(t)
+	mockRepo := new(MockTaskRepository)
+	publisher := &recordingPublisher{}
+	userID := uuid.New()
+
+	// pgx aborts the insert with the request's canceled context.
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(context.Canceled)
+
+	w := httptest.NewRecorder()
+	setupTaskRouterWithEvents(mockRepo, userID, publisher).ServeHTTP(w,
+		canceledRequest("POST", "/tasks", `{"title": "Renew passport", "horizon": "later", "priority": "medium"}`))
+
+	assert.Equal(t, middleware.StatusClientClosedRequest, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Zero(t, logs.FilterLevelExact(zapcore.ErrorLevel).Len())
+	assert.Empty(t, publisher.events)
+}
+
+func TestRespondDatabaseError_ClientCanceledMergeStops(t *testing.T) {
+	logs := observeLogs(t)
+	mockRepo := new(MockHabitRepo)
+	publisher := &recordingPublisher{}
+	userID := uuid.New()
+	sourceID, targetID := uuid.New(), uuid.New()
+
+	// The merge transaction rolls back when its context is canceled, so the
+	// handler must not go on to read the target or announce a merge.
+	mockRepo.On("Merge", mock.Anything, sourceID, targetID, userID).Return(0, context.Canceled)
+
+	w := httptest.NewRecorder()
+	setupHabitRouterWithEvents(mockRepo, userID, publisher).ServeHTTP(w,
+		canceledRequest("POST", "/habits/"+sourceID.String()+"/merge", `{"target_id": "`+targetID.String()+`"}`))
+
+	assert.Equal(t, middleware.StatusClientClosedRequest, w.Code)
+	assert.Zero(t, logs.FilterLevelExact(zapcore.ErrorLevel).Len())
+	assert.Empty(t, publisher.events)
+	mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRespondDatabaseError_ConnectedClientGets500(t *testing.T) {
+	logs := observeLogs(t)
+	mockRepo := new(MockHabitRepo)
+	userID := uuid.New()
+	habitID := uuid.New()
+
+	mockRepo.On("GetByID", mock.Anything, habitID, userID).Return(nil, errors.New("connection reset"))
+
+	req, _ := http.NewRequest("GET", "/habits/"+habitID.String(), nil)
+	w := httptest.NewRecorder()
+	setupHabitRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "DATABASE_ERROR")
+	entries := logs.FilterLevelExact(zapcore.ErrorLevel).All()
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "Failed to get habit", entries[0].Message)
+		assert.Equal(t, habitID.String(), entries[0].ContextMap()["habit_id"])
+	}
+}
@@ -1,10 +1,17 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/events"
+	"github.com/lumen/backend/internal/middleware"
 	"github.com/lumen/backend/internal/models"
 	"github.com/lumen/backend/internal/repository"
 	apperrors "github.com/lumen/backend/pkg/errors"
@@ -12,26 +19,34 @@ import (
 	"go.uber.org/zap"
 )
 
+// TaskHandler serves task CRUD. maxDueDateYears bounds how far ahead a due
+// date may be set, tagLimits how many tags a task carries and how long they
+// are, and enforceDependencies keeps blocked tasks from being started or
+// finished; every committed change is published to events.
 type TaskHandler struct {
-	repo repository.TaskRepository
+	repo                repository.TaskRepository
+	maxDueDateYears     int
+	tagLimits           models.TagLimits
+	enforceDependencies bool
+	events              events.Publisher
 }
 
-func NewTaskHandler(repo repository.TaskRepository) *TaskHandler {
-	return &TaskHandler{repo: repo}
+func NewTaskHandler(repo repository.TaskRepository, maxDueDateYears int, tagLimits models.TagLimits, enforceDependencies bool, publisher events.Publisher) *TaskHandler {
+	return &TaskHandler{repo: repo, maxDueDateYears: maxDueDateYears, tagLimits: tagLimits, enforceDependencies: enforceDependencies, events: publisher}
 }
 
 func (h *TaskHandler) Create(c *gin.Context) {
 	var req models.CreateTaskRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		appErr := apperrors.NewValidationError(err.Error())
-		c.JSON(appErr.StatusCode, appErr)
+	if err := bindJSON(c, &req); err != nil {
+		appErr := bindError(err)
+		respondError(c, appErr)
 		return
 	}
 
 	userID := getUserID(c)
 	if userID == uuid.Nil {
 		appErr := apperrors.NewUnauthorized("user not authenticated")
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
 		return
 	}
 
@@ -41,23 +56,51 @@ func (h *TaskHandler) Create(c *gin.Context) {
 		Description: req.Description,
 		Horizon:     req.Horizon,
 		Priority:    req.Priority,
+		Status:      "todo",
+		Tags:        models.NormalizeTags(req.Tags),
 		DueDate:     req.DueDate,
 	}
 
 	if err := task.Validate(); err != nil {
 		appErr := apperrors.NewValidationError(err.Error())
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
+		return
+	}
+
+	if err := models.ValidateDueDate(req.DueDate, time.Now(), h.maxDueDateYears); err != nil {
+		appErr := apperrors.NewValidationError(err.Error())
+		respondError(c, appErr)
+		return
+	}
+
+	if err := middleware.ValidationProfile(c).ValidateDueDate(req.DueDate, time.Now()); err != nil {
+		appErr := apperrors.NewValidationError(err.Error())
+		respondError(c, appErr)
+		return
+	}
+
+	if err := models.ValidateTags(task.Tags, h.tagLimits); err != nil {
+		appErr := apperrors.NewValidationError(err.Error())
+		respondError(c, appErr)
+		return
+	}
+
+	if req.BlockedBy != nil && !h.setBlockers(c, task, req.BlockedBy) {
 		return
 	}
 
 	if err := h.repo.Create(c.Request.Context(), task); err != nil {
-		logger.Error("Failed to create task", zap.Error(err), zap.String("user_id", userID.String()))
-		appErr := apperrors.NewDatabaseError(err)
-		c.JSON(appErr.StatusCode, appErr)
+		if errors.Is(err, models.ErrUnknownBlocker) {
+			appErr := apperrors.NewValidationError(err.Error())
+			respondError(c, appErr)
+			return
+		}
+		respondDatabaseError(c, err, "Failed to create task", zap.String("user_id", userID.String()))
 		return
 	}
 
 	logger.Info("Task created", zap.String("task_id", task.ID.String()), zap.String("user_id", userID.String()))
+	h.events.Publish(events.TaskCreated{Task: *task})
 	c.JSON(http.StatusCreated, task)
 }
 
@@ -65,22 +108,20 @@ func (h *TaskHandler) GetAll(c *gin.Context) {
 	userID := getUserID(c)
 	if userID == uuid.Nil {
 		appErr := apperrors.NewUnauthorized("user not authenticated")
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
 		return
 	}
 
 	var filter models.TaskFilter
 	if err := c.ShouldBindQuery(&filter); err != nil {
 		appErr := apperrors.NewBadRequest("invalid query parameters")
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
 		return
 	}
 
 	tasks, err := h.repo.GetByUserID(c.Request.Context(), userID, filter)
 	if err != nil {
-		logger.Error("Failed to get tasks", zap.Error(err), zap.String("user_id", userID.String()))
-		appErr := apperrors.NewDatabaseError(err)
-		c.JSON(appErr.StatusCode, appErr)
+		respondDatabaseError(c, err, "Failed to get tasks", zap.String("user_id", userID.String()))
 		return
 	}
 
@@ -95,32 +136,71 @@ func (h *TaskHandler) GetAll(c *gin.Context) {
 	})
 }
 
-func (h *TaskHandler) GetByID(c *gin.Context) {
-	taskID, err := uuid.Parse(c.Param("id"))
+// ByHorizon returns the user's tasks grouped by horizon for the board, each
+// group holding the newest ?limit= tasks (default 20). The other task
+// filters apply to every group; horizon is ignored.
+func (h *TaskHandler) ByHorizon(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	var filter models.TaskFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		appErr := apperrors.NewBadRequest("invalid query parameters")
+		respondError(c, appErr)
+		return
+	}
+	filter.Horizon = ""
+
+	limit := models.DefaultHorizonGroupSize
+	if limitStr := c.Query("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n < 1 || n > models.MaxHorizonGroupSize {
+			appErr := apperrors.NewBadRequest(fmt.Sprintf("limit must be between 1 and %d", models.MaxHorizonGroupSize))
+			respondError(c, appErr)
+			return
+		}
+		limit = n
+	}
+
+	groups, err := h.repo.GetByHorizon(c.Request.Context(), userID, filter, limit)
 	if err != nil {
-		appErr := apperrors.NewBadRequest("invalid task ID")
-		c.JSON(appErr.StatusCode, appErr)
+		respondDatabaseError(c, err, "Failed to get tasks by horizon", zap.String("user_id", userID.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   groups,
+		"limit":  limit,
+		"filter": filter,
+	})
+}
+
+func (h *TaskHandler) GetByID(c *gin.Context) {
+	taskID, ok := pathUUID(c, "id")
+	if !ok {
 		return
 	}
 
 	userID := getUserID(c)
 	if userID == uuid.Nil {
 		appErr := apperrors.NewUnauthorized("user not authenticated")
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
 		return
 	}
 
 	task, err := h.repo.GetByID(c.Request.Context(), taskID, userID)
 	if err == models.ErrNotFound {
-		appErr := apperrors.NewNotFound("task")
-		c.JSON(appErr.StatusCode, appErr)
+		appErr := apperrors.NewNotFoundWithID("task", taskID)
+		respondError(c, appErr)
 		return
 	}
 
 	if err != nil {
-		logger.Error("Failed to get task", zap.Error(err), zap.String("task_id", taskID.String()))
-		appErr := apperrors.NewDatabaseError(err)
-		c.JSON(appErr.StatusCode, appErr)
+		respondDatabaseError(c, err, "Failed to get task", zap.String("task_id", taskID.String()))
 		return
 	}
 
@@ -128,37 +208,61 @@ func (h *TaskHandler) GetByID(c *gin.Context) {
 }
 
 func (h *TaskHandler) Update(c *gin.Context) {
-	taskID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		appErr := apperrors.NewBadRequest("invalid task ID")
-		c.JSON(appErr.StatusCode, appErr)
+	taskID, ok := pathUUID(c, "id")
+	if !ok {
 		return
 	}
 
 	userID := getUserID(c)
 	if userID == uuid.Nil {
 		appErr := apperrors.NewUnauthorized("user not authenticated")
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
 		return
 	}
 
-	task, err := h.repo.GetByID(c.Request.Context(), taskID, userID)
+	task, err := h.repo.GetByID(repository.WithPrimary(c.Request.Context()), taskID, userID)
 	if err == models.ErrNotFound {
-		appErr := apperrors.NewNotFound("task")
-		c.JSON(appErr.StatusCode, appErr)
+		appErr := apperrors.NewNotFoundWithID("task", taskID)
+		respondError(c, appErr)
 		return
 	}
 
 	if err != nil {
-		appErr := apperrors.NewDatabaseError(err)
-		c.JSON(appErr.StatusCode, appErr)
+		respondDatabaseError(c, err, "Failed to get task", zap.String("task_id", taskID.String()))
 		return
 	}
 
 	var req models.UpdateTaskRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := bindJSON(c, &req); err != nil {
+		appErr := bindError(err)
+		respondError(c, appErr)
+		return
+	}
+
+	// Only a newly set due date is checked, so tasks saved before the limit
+	// existed can still be edited.
+	if err := models.ValidateDueDate(req.DueDate, time.Now(), h.maxDueDateYears); err != nil {
 		appErr := apperrors.NewValidationError(err.Error())
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
+		return
+	}
+
+	changes := req.Changes(task)
+	previousStatus := task.Status
+
+	// An overdue task can still be edited as long as its due date stays put.
+	if slices.Contains(changes, "due_date") {
+		if err := middleware.ValidationProfile(c).ValidateDueDate(req.DueDate, time.Now()); err != nil {
+			appErr := apperrors.NewValidationError(err.Error())
+			respondError(c, appErr)
+			return
+		}
+	}
+
+	// Marking a done task done again is a no-op: completed_at keeps its
+	// original value and no duplicate event goes out.
+	if previousStatus == "done" && req.Status != nil && *req.Status == "done" && len(changes) == 0 {
+		respondUpdated(c, task, changes)
 		return
 	}
 
@@ -177,53 +281,267 @@ func (h *TaskHandler) Update(c *gin.Context) {
 	if req.Status != nil {
 		task.Status = *req.Status
 	}
+	if req.Tags != nil {
+		task.Tags = models.NormalizeTags(*req.Tags)
+		// Only newly set tags are checked, so tasks saved under looser
+		// limits can still be edited.
+		if err := models.ValidateTags(task.Tags, h.tagLimits); err != nil {
+			appErr := apperrors.NewValidationError(err.Error())
+			respondError(c, appErr)
+			return
+		}
+	}
 	if req.DueDate != nil {
 		task.DueDate = req.DueDate
 	}
+	if req.BlockedBy != nil && !h.setBlockers(c, task, *req.BlockedBy) {
+		return
+	}
 
 	if err := task.Validate(); err != nil {
 		appErr := apperrors.NewValidationError(err.Error())
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
+		return
+	}
+
+	if h.enforceDependencies && task.IsBlocked && models.StartsWork(previousStatus, task.Status) {
+		appErr := apperrors.NewConflict(models.ErrTaskBlocked.Error())
+		respondError(c, appErr)
 		return
 	}
 
 	if err := h.repo.Update(c.Request.Context(), task); err != nil {
-		logger.Error("Failed to update task", zap.Error(err), zap.String("task_id", taskID.String()))
-		appErr := apperrors.NewDatabaseError(err)
-		c.JSON(appErr.StatusCode, appErr)
+		if errors.Is(err, models.ErrDependencyCycle) || errors.Is(err, models.ErrUnknownBlocker) {
+			appErr := apperrors.NewValidationError(err.Error())
+			respondError(c, appErr)
+			return
+		}
+		respondDatabaseError(c, err, "Failed to update task", zap.String("task_id", taskID.String()))
 		return
 	}
 
 	logger.Info("Task updated", zap.String("task_id", taskID.String()), zap.String("user_id", userID.String()))
+	h.events.Publish(events.TaskUpdated{Task: *task})
+	respondUpdated(c, task, changes)
+}
+
+// setBlockers makes blockedBy task's blockers, looking up their statuses to
+// tell whether task is blocked. It responds itself and returns false when a
+// blocker is invalid or can't be looked up.
+func (h *TaskHandler) setBlockers(c *gin.Context, task *models.Task, blockedBy []uuid.UUID) bool {
+	ids, err := models.NormalizeBlockedBy(task.ID, blockedBy)
+	if err != nil {
+		appErr := apperrors.NewValidationError(err.Error())
+		respondError(c, appErr)
+		return false
+	}
+
+	statuses, err := h.repo.GetStatuses(repository.WithPrimary(c.Request.Context()), task.UserID, ids)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get blocking tasks", zap.String("user_id", task.UserID.String()))
+		return false
+	}
+
+	deps := make([]models.TaskDependency, 0, len(ids))
+	for _, id := range ids {
+		status, ok := statuses[id]
+		if !ok {
+			appErr := apperrors.NewValidationError(models.ErrUnknownBlocker.Error())
+			respondError(c, appErr)
+			return false
+		}
+		deps = append(deps, models.TaskDependency{TaskID: task.ID, BlockedByID: id, BlockedByStatus: status})
+	}
+
+	task.SetBlockers(deps)
+	return true
+}
+
+func (h *TaskHandler) Snooze(c *gin.Context) {
+	taskID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	var req models.SnoozeTaskRequest
+	if err := bindJSON(c, &req); err != nil {
+		appErr := bindError(err)
+		respondError(c, appErr)
+		return
+	}
+
+	task, err := h.repo.GetByID(repository.WithPrimary(c.Request.Context()), taskID, userID)
+	if err == models.ErrNotFound {
+		appErr := apperrors.NewNotFoundWithID("task", taskID)
+		respondError(c, appErr)
+		return
+	}
+
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get task", zap.String("task_id", taskID.String()))
+		return
+	}
+
+	now := time.Now()
+	dueDate, err := req.SnoozedDueDate(task, now)
+	if err == nil {
+		err = models.ValidateDueDate(&dueDate, now, h.maxDueDateYears)
+	}
+	if err != nil {
+		appErr := apperrors.NewValidationError(err.Error())
+		respondError(c, appErr)
+		return
+	}
+	task.DueDate = &dueDate
+
+	if err := h.repo.Snooze(c.Request.Context(), task); err != nil {
+		respondDatabaseError(c, err, "Failed to snooze task", zap.String("task_id", taskID.String()))
+		return
+	}
+
+	logger.Info("Task snoozed", zap.String("task_id", taskID.String()), zap.Time("due_date", dueDate), zap.Int("snooze_count", task.SnoozeCount))
+	h.events.Publish(events.TaskUpdated{Task: *task})
 	c.JSON(http.StatusOK, task)
 }
 
-func (h *TaskHandler) Delete(c *gin.Context) {
-	taskID, err := uuid.Parse(c.Param("id"))
+// Velocity reports how many tasks the user completed per ?period= (day or
+// week, default day) over the last ?window= days (e.g. 30d, the default).
+func (h *TaskHandler) Velocity(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	window := models.DefaultVelocityWindowDays
+	if value := c.Query("window"); value != "" {
+		days, err := models.ParseVelocityWindow(value)
+		if err != nil {
+			appErr := apperrors.NewBadRequest(err.Error())
+			respondError(c, appErr)
+			return
+		}
+		window = days
+	}
+
+	period := c.DefaultQuery("period", models.VelocityPeriodDay)
+	if err := models.ValidateVelocityPeriod(period); err != nil {
+		appErr := apperrors.NewBadRequest(err.Error())
+		respondError(c, appErr)
+		return
+	}
+
+	now := time.Now()
+	completed, err := h.repo.GetCompletedSince(c.Request.Context(), userID, models.VelocityWindowStart(window, now))
 	if err != nil {
-		appErr := apperrors.NewBadRequest("invalid task ID")
-		c.JSON(appErr.StatusCode, appErr)
+		respondDatabaseError(c, err, "Failed to get task completions", zap.String("user_id", userID.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ComputeTaskVelocity(completed, window, period, now))
+}
+
+// ArchiveCompleted moves all of the user's done tasks to archived, optionally
+// only those completed before ?completed_before=, and returns how many moved.
+func (h *TaskHandler) ArchiveCompleted(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	var req models.ArchiveCompletedRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		appErr := apperrors.NewBadRequest("invalid query parameters")
+		respondError(c, appErr)
+		return
+	}
+
+	tasks, err := h.repo.ArchiveCompleted(c.Request.Context(), userID, req.CompletedBefore)
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to archive completed tasks", zap.String("user_id", userID.String()))
+		return
+	}
+
+	logger.Info("Completed tasks archived", zap.String("user_id", userID.String()), zap.Int("count", len(tasks)))
+	for _, task := range tasks {
+		h.events.Publish(events.TaskUpdated{Task: task})
+	}
+	c.JSON(http.StatusOK, gin.H{"archived": len(tasks)})
+}
+
+// MoveToGoal reassigns the listed tasks to another goal, or unlinks them
+// when goal_id is null, all or nothing.
+func (h *TaskHandler) MoveToGoal(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	var req models.MoveTasksToGoalRequest
+	if err := bindJSON(c, &req); err != nil {
+		appErr := bindError(err)
+		respondError(c, appErr)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		appErr := apperrors.NewValidationError(err.Error())
+		respondError(c, appErr)
+		return
+	}
+
+	tasks, err := h.repo.MoveToGoal(c.Request.Context(), userID, req.TaskIDs, req.GoalID)
+	if errors.Is(err, models.ErrUnknownTask) || errors.Is(err, models.ErrUnknownGoal) {
+		appErr := apperrors.NewValidationError(err.Error())
+		respondError(c, appErr)
+		return
+	} else if err != nil {
+		respondDatabaseError(c, err, "Failed to move tasks to goal", zap.String("user_id", userID.String()))
+		return
+	}
+
+	logger.Info("Tasks moved to goal", zap.String("user_id", userID.String()), zap.Int("count", len(tasks)))
+	for _, task := range tasks {
+		h.events.Publish(events.TaskUpdated{Task: task})
+	}
+	c.JSON(http.StatusOK, gin.H{"moved": len(tasks), "goal_id": req.GoalID})
+}
+
+func (h *TaskHandler) Delete(c *gin.Context) {
+	taskID, ok := pathUUID(c, "id")
+	if !ok {
 		return
 	}
 
 	userID := getUserID(c)
 	if userID == uuid.Nil {
 		appErr := apperrors.NewUnauthorized("user not authenticated")
-		c.JSON(appErr.StatusCode, appErr)
+		respondError(c, appErr)
 		return
 	}
 
 	if err := h.repo.Delete(c.Request.Context(), taskID, userID); err == models.ErrNotFound {
-		appErr := apperrors.NewNotFound("task")
-		c.JSON(appErr.StatusCode, appErr)
+		appErr := apperrors.NewNotFoundWithID("task", taskID)
+		respondError(c, appErr)
 		return
 	} else if err != nil {
-		logger.Error("Failed to delete task", zap.Error(err), zap.String("task_id", taskID.String()))
-		appErr := apperrors.NewDatabaseError(err)
-		c.JSON(appErr.StatusCode, appErr)
+		respondDatabaseError(c, err, "Failed to delete task", zap.String("task_id", taskID.String()))
 		return
 	}
 
 	logger.Info("Task deleted", zap.String("task_id", taskID.String()), zap.String("user_id", userID.String()))
+	h.events.Publish(events.TaskDeleted{UserID: userID, TaskID: taskID})
 	c.JSON(http.StatusNoContent, nil)
 }
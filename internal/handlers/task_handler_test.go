@@ -0,0 +1,1143 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/events"
+	"github.com/lumen/backend/internal/middleware"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTaskRepository is a mock for task repository
+type MockTaskRepository struct {
+	mock.Mock
+}
+
+func (m *MockTaskRepository) Create(ctx context.Context, task *models.Task) error {
+	args := m.Called(ctx, task)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) CreateMany(ctx context.Context, tasks []*models.Task) error {
+	args := m.Called(ctx, tasks)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) GetByID(ctx context.Context, id, userID uuid.UUID) (*models.Task, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) GetByUserID(ctx context.Context, userID uuid.UUID, filter models.TaskFilter) ([]models.Task, error) {
+	args := m.Called(ctx, userID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) GetByHorizon(ctx context.Context, userID uuid.UUID, filter models.TaskFilter, limit int) (map[string]models.HorizonGroup, error) {
+	args := m.Called(ctx, userID, filter, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]models.HorizonGroup), args.Error(1)
+}
+
+func (m *MockTaskRepository) Update(ctx context.Context, task *models.Task) error {
+	args := m.Called(ctx, task)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) Snooze(ctx context.Context, task *models.Task) error {
+	args := m.Called(ctx, task)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) GetDueWithin(ctx context.Context, userID uuid.UUID, within time.Duration) ([]models.Task, error) {
+	args := m.Called(ctx, userID, within)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) GetDueBetween(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.Task, error) {
+	args := m.Called(ctx, userID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) GetCompletedSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]time.Time, error) {
+	args := m.Called(ctx, userID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]time.Time), args.Error(1)
+}
+
+func (m *MockTaskRepository) ArchiveCompleted(ctx context.Context, userID uuid.UUID, completedBefore *time.Time) ([]models.Task, error) {
+	args := m.Called(ctx, userID, completedBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) MoveToGoal(ctx context.Context, userID uuid.UUID, taskIDs []uuid.UUID, goalID *uuid.UUID) ([]models.Task, error) {
+	args := m.Called(ctx, userID, taskIDs, goalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) GetStatuses(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]string, error) {
+	args := m.Called(ctx, userID, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID]string), args.Error(1)
+}
+
+func (m *MockTaskRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+// recordingPublisher collects published events in place of the bus.
+type recordingPublisher struct {
+	events []events.Event
+}
+
+func (p *recordingPublisher) Publish(event events.Event) {
+	p.events = append(p.events, event)
+}
+
+func setupTaskRouter(repo *MockTaskRepository, userID uuid.UUID) *gin.Engine {
+	return setupTaskRouterWithEvents(repo, userID, &recordingPublisher{})
+}
+
+func setupTaskRouterWithEvents(repo *MockTaskRepository, userID uuid.UUID, publisher events.Publisher) *gin.Engine {
+	return taskRouter(NewTaskHandler(repo, models.DefaultMaxDueDateYears, models.DefaultTagLimits, true, publisher), userID)
+}
+
+func taskRouter(handler *TaskHandler, userID uuid.UUID) *gin.Engine {
+	router := setupTestRouter()
+
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	})
+	router.Use(middleware.UUIDParams("id"))
+	router.POST("/tasks", handler.Create)
+	router.POST("/tasks/archive-completed", handler.ArchiveCompleted)
+	router.PATCH("/tasks/move-to-goal", handler.MoveToGoal)
+	router.GET("/tasks", handler.GetAll)
+	router.GET("/tasks/velocity", handler.Velocity)
+	router.GET("/tasks/by-horizon", handler.ByHorizon)
+	router.GET("/tasks/:id", handler.GetByID)
+	router.PATCH("/tasks/:id", handler.Update)
+	router.POST("/tasks/:id/snooze", handler.Snooze)
+
+	return router
+}
+
+func existingTask(userID uuid.UUID) *models.Task {
+	return &models.Task{
+		ID:       uuid.New(),
+		UserID:   userID,
+		Title:    "Write report",
+		Horizon:  "now",
+		Priority: "medium",
+		Status:   "todo",
+	}
+}
+
+func patchTask(router *gin.Engine, taskID uuid.UUID, query string, body map[string]interface{}) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	req, _ := http.NewRequest("PATCH", "/tasks/"+taskID.String()+query, bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestUpdateTask_ReturnChanges(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := existingTask(userID)
+
+	mockRepo.On("GetByID", mock.Anything, task.ID, userID).Return(task, nil)
+	mockRepo.On("Update", mock.Anything, task).Return(nil)
+
+	router := setupTaskRouter(mockRepo, userID)
+	w := patchTask(router, task.ID, "?return=changes", map[string]interface{}{
+		"title":    "Write report",
+		"priority": "high",
+	})
+
+	assert.Equal(t, 200, w.Code)
+
+	var response struct {
+		Data    models.Task `json:"data"`
+		Changes []string    `json:"changes"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []string{"priority"}, response.Changes)
+	assert.Equal(t, "high", response.Data.Priority)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateTask_ReturnChangesNoOp(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := existingTask(userID)
+
+	mockRepo.On("GetByID", mock.Anything, task.ID, userID).Return(task, nil)
+	mockRepo.On("Update", mock.Anything, task).Return(nil)
+
+	router := setupTaskRouter(mockRepo, userID)
+	w := patchTask(router, task.ID, "?return=changes", map[string]interface{}{
+		"title":   "Write report",
+		"horizon": "now",
+	})
+
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"changes":[]`)
+}
+
+func TestUpdateTask_DefaultResponseUnchanged(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := existingTask(userID)
+
+	mockRepo.On("GetByID", mock.Anything, task.ID, userID).Return(task, nil)
+	mockRepo.On("Update", mock.Anything, task).Return(nil)
+
+	router := setupTaskRouter(mockRepo, userID)
+	w := patchTask(router, task.ID, "", map[string]interface{}{"priority": "high"})
+
+	assert.Equal(t, 200, w.Code)
+
+	var response models.Task
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "high", response.Priority)
+	assert.NotContains(t, w.Body.String(), "changes")
+}
+
+func snoozeTask(router *gin.Engine, taskID uuid.UUID, body map[string]interface{}) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	req, _ := http.NewRequest("POST", "/tasks/"+taskID.String()+"/snooze", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSnoozeTask_ShiftsDueDateAndCountsSnooze(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := existingTask(userID)
+	due := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+	task.DueDate = &due
+
+	mockRepo.On("GetByID", mock.Anything, task.ID, userID).Return(task, nil)
+	mockRepo.On("Snooze", mock.Anything, task).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Task).SnoozeCount++
+	}).Return(nil)
+
+	router := setupTaskRouter(mockRepo, userID)
+	w := snoozeTask(router, task.ID, map[string]interface{}{"duration": "1d"})
+
+	assert.Equal(t, 200, w.Code)
+
+	var response models.Task
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.DueDate.Equal(due.Add(24*time.Hour)))
+	assert.Equal(t, 1, response.SnoozeCount)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSnoozeTask_ReadsTaskFromPrimary(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := existingTask(userID)
+
+	mockRepo.On("GetByID", mock.MatchedBy(repository.ReadsPrimary), task.ID, userID).Return(task, nil)
+	mockRepo.On("Snooze", mock.Anything, task).Return(nil)
+
+	w := snoozeTask(setupTaskRouter(mockRepo, userID), task.ID, map[string]interface{}{"duration": "1d"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSnoozeTask_UntilDate(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := existingTask(userID)
+	until := time.Now().Add(72 * time.Hour).Truncate(time.Second).UTC()
+
+	mockRepo.On("GetByID", mock.Anything, task.ID, userID).Return(task, nil)
+	mockRepo.On("Snooze", mock.Anything, task).Return(nil)
+
+	router := setupTaskRouter(mockRepo, userID)
+	w := snoozeTask(router, task.ID, map[string]interface{}{"until": until})
+
+	assert.Equal(t, 200, w.Code)
+
+	var response models.Task
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.DueDate.Equal(until))
+}
+
+func TestSnoozeTask_RejectsInvalidInput(t *testing.T) {
+	userID := uuid.New()
+
+	cases := map[string]map[string]interface{}{
+		"garbage duration":  {"duration": "tomorrow-ish"},
+		"negative duration": {"duration": "-2h"},
+		"zero days":         {"duration": "0d"},
+		"neither":           {},
+		"both":              {"duration": "1h", "until": time.Now().Add(time.Hour)},
+		"until in past":     {"until": time.Now().Add(-time.Hour)},
+	}
+
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepository)
+			task := existingTask(userID)
+			mockRepo.On("GetByID", mock.Anything, task.ID, userID).Return(task, nil)
+
+			router := setupTaskRouter(mockRepo, userID)
+			w := snoozeTask(router, task.ID, body)
+
+			assert.Equal(t, 422, w.Code)
+			mockRepo.AssertNotCalled(t, "Snooze", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestSnoozeTask_RejectsDueDateBeyondLimit(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := existingTask(userID)
+
+	mockRepo.On("GetByID", mock.Anything, task.ID, userID).Return(task, nil)
+
+	w := snoozeTask(setupTaskRouter(mockRepo, userID), task.ID, map[string]interface{}{"until": "9999-01-01T00:00:00Z"})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "too far in the future")
+	mockRepo.AssertNotCalled(t, "Snooze", mock.Anything, mock.Anything)
+}
+
+func createTask(router *gin.Engine, body map[string]interface{}) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	req, _ := http.NewRequest("POST", "/tasks", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreateTask_AcceptsDueDateWithinLimit(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	due := time.Now().AddDate(2, 0, 0).UTC().Truncate(time.Second)
+
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(task *models.Task) bool {
+		return task.DueDate != nil && task.DueDate.Equal(due)
+	})).Return(nil)
+
+	w := createTask(setupTaskRouter(mockRepo, userID), map[string]interface{}{
+		"title":    "Renew passport",
+		"horizon":  "later",
+		"priority": "medium",
+		"due_date": due.Format(time.RFC3339),
+	})
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateTask_PublishesEventAfterCommit(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	publisher := &recordingPublisher{}
+	userID := uuid.New()
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	w := createTask(setupTaskRouterWithEvents(mockRepo, userID, publisher), map[string]interface{}{
+		"title":    "Renew passport",
+		"horizon":  "later",
+		"priority": "medium",
+	})
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	if assert.Len(t, publisher.events, 1) {
+		created, ok := publisher.events[0].(events.TaskCreated)
+		assert.True(t, ok)
+		assert.Equal(t, userID, created.Task.UserID)
+		assert.Equal(t, "Renew passport", created.Task.Title)
+	}
+}
+
+func TestCreateTask_FailedCommitPublishesNothing(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	publisher := &recordingPublisher{}
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(errors.New("connection reset"))
+
+	w := createTask(setupTaskRouterWithEvents(mockRepo, uuid.New(), publisher), map[string]interface{}{
+		"title":    "Renew passport",
+		"horizon":  "later",
+		"priority": "medium",
+	})
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Empty(t, publisher.events)
+}
+
+func TestCreateTask_RejectsDueDateBeyondLimit(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+
+	w := createTask(setupTaskRouter(mockRepo, userID), map[string]interface{}{
+		"title":    "Renew passport",
+		"horizon":  "later",
+		"priority": "medium",
+		"due_date": "9999-01-01T00:00:00Z",
+	})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid due date: too far in the future")
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestUpdateTask_RejectsDueDateBeyondLimit(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := existingTask(userID)
+
+	mockRepo.On("GetByID", mock.Anything, task.ID, userID).Return(task, nil)
+
+	w := patchTask(setupTaskRouter(mockRepo, userID), task.ID, "", map[string]interface{}{
+		"due_date": time.Now().AddDate(models.DefaultMaxDueDateYears+1, 0, 0).Format(time.RFC3339),
+	})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Nil(t, task.DueDate)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdateTask_KeepsExistingFarDueDateEditable(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := existingTask(userID)
+	farOff := time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+	task.DueDate = &farOff
+
+	mockRepo.On("GetByID", mock.Anything, task.ID, userID).Return(task, nil)
+	mockRepo.On("Update", mock.Anything, task).Return(nil)
+
+	w := patchTask(setupTaskRouter(mockRepo, userID), task.ID, "", map[string]interface{}{"priority": "high"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetTask_NotFoundNamesID(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	taskID := uuid.New()
+
+	mockRepo.On("GetByID", mock.Anything, taskID, userID).Return(nil, models.ErrNotFound)
+
+	req, _ := http.NewRequest("GET", "/tasks/"+taskID.String(), nil)
+	w := httptest.NewRecorder()
+	setupTaskRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"message":"task `+taskID.String()+` not found"`)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateTask_OtherUsersTaskNotFound(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	owner := uuid.New()
+	otherUser := uuid.New()
+	task := existingTask(owner)
+
+	// GetByID is scoped to the caller, so another user's lookup of the same
+	// ID finds nothing and the unscoped Update is never reached.
+	mockRepo.On("GetByID", mock.Anything, task.ID, otherUser).Return(nil, models.ErrNotFound)
+
+	w := patchTask(setupTaskRouter(mockRepo, otherUser), task.ID, "", map[string]interface{}{
+		"title": "Hijacked",
+	})
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"message":"task `+task.ID.String()+` not found"`)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, task.ID, owner)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestGetTask_NotFoundLocalizedForAcceptLanguage(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	taskID := uuid.New()
+
+	mockRepo.On("GetByID", mock.Anything, taskID, userID).Return(nil, models.ErrNotFound)
+
+	req, _ := http.NewRequest("GET", "/tasks/"+taskID.String(), nil)
+	req.Header.Set("Accept-Language", "es")
+	w := httptest.NewRecorder()
+	setupTaskRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"message":"Recurso no encontrado"`)
+	assert.Contains(t, w.Body.String(), `"detail":"task `+taskID.String()+` not found"`)
+}
+
+func TestGetTasks_BindsTagsWithOtherFilters(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+
+	mockRepo.On("GetByUserID", mock.Anything, userID, mock.MatchedBy(func(f models.TaskFilter) bool {
+		return f.Priority == "urgent" && f.Status == "todo" &&
+			assert.ObjectsAreEqual([]string{"work", "q4"}, models.NormalizeTags(f.Tags))
+	})).Return([]models.Task{}, nil)
+
+	req, _ := http.NewRequest("GET", "/tasks?priority=urgent&status=todo&tags=work&tags=Q4", nil)
+	w := httptest.NewRecorder()
+	setupTaskRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetTasks_BindsHasDueDate(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+
+	mockRepo.On("GetByUserID", mock.Anything, userID, mock.MatchedBy(func(f models.TaskFilter) bool {
+		return f.Status == "todo" && f.HasDueDate != nil && !*f.HasDueDate
+	})).Return([]models.Task{}, nil)
+
+	req, _ := http.NewRequest("GET", "/tasks?has_due_date=false&status=todo", nil)
+	w := httptest.NewRecorder()
+	setupTaskRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetTasks_RejectsInvalidHasDueDate(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+
+	req, _ := http.NewRequest("GET", "/tasks?has_due_date=maybe", nil)
+	w := httptest.NewRecorder()
+	setupTaskRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockRepo.AssertNotCalled(t, "GetByUserID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateTask_NormalizesTitle(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := existingTask(userID)
+
+	mockRepo.On("GetByID", mock.Anything, task.ID, userID).Return(task, nil)
+	mockRepo.On("Update", mock.Anything, task).Return(nil)
+
+	router := setupTaskRouter(mockRepo, userID)
+	w := patchTask(router, task.ID, "?return=changes", map[string]interface{}{"title": "  Write   report  "})
+
+	assert.Equal(t, 200, w.Code)
+	// The padded title normalizes to the stored one, so nothing changed.
+	assert.Contains(t, w.Body.String(), `"changes":[]`)
+	assert.Equal(t, "Write report", task.Title)
+}
+
+func TestUpdateTask_RejectsWhitespaceOnlyTitle(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := existingTask(userID)
+
+	mockRepo.On("GetByID", mock.Anything, task.ID, userID).Return(task, nil)
+
+	w := patchTask(setupTaskRouter(mockRepo, userID), task.ID, "", map[string]interface{}{"title": "   "})
+
+	assert.Equal(t, 422, w.Code)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func archiveCompleted(router *gin.Engine, query string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/tasks/archive-completed"+query, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestArchiveCompleted_ReturnsCountAndPublishesUpdates(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	publisher := &recordingPublisher{}
+	userID := uuid.New()
+
+	archived := []models.Task{*existingTask(userID), *existingTask(userID)}
+	for i := range archived {
+		archived[i].Status = "archived"
+	}
+	mockRepo.On("ArchiveCompleted", mock.Anything, userID, (*time.Time)(nil)).Return(archived, nil)
+
+	w := archiveCompleted(setupTaskRouterWithEvents(mockRepo, userID, publisher), "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"archived": 2}`, w.Body.String())
+	if assert.Len(t, publisher.events, 2) {
+		updated, ok := publisher.events[0].(events.TaskUpdated)
+		assert.True(t, ok)
+		assert.Equal(t, "archived", updated.Task.Status)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestArchiveCompleted_PassesCutoff(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	cutoff := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+
+	mockRepo.On("ArchiveCompleted", mock.Anything, userID, mock.MatchedBy(func(before *time.Time) bool {
+		return before != nil && before.Equal(cutoff)
+	})).Return([]models.Task{}, nil)
+
+	w := archiveCompleted(setupTaskRouter(mockRepo, userID), "?completed_before=2026-10-12T00:00:00Z")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"archived": 0}`, w.Body.String())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestArchiveCompleted_RejectsInvalidCutoff(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+
+	w := archiveCompleted(setupTaskRouter(mockRepo, uuid.New()), "?completed_before=last-week")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockRepo.AssertNotCalled(t, "ArchiveCompleted", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func getVelocity(router *gin.Engine, query string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/tasks/velocity"+query, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestTaskVelocity_WeeklyOverWindow(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	now := time.Now()
+	start := models.VelocityWindowStart(14, now)
+
+	mockRepo.On("GetCompletedSince", mock.Anything, userID, start).Return([]time.Time{
+		start.Add(2 * time.Hour),
+		start.AddDate(0, 0, 8),
+		start.AddDate(0, 0, 13).Add(time.Hour),
+	}, nil)
+
+	w := getVelocity(setupTaskRouter(mockRepo, userID), "?window=14d&period=week")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var velocity models.TaskVelocity
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &velocity))
+	assert.Equal(t, 14, velocity.WindowDays)
+	assert.Equal(t, "week", velocity.Period)
+	assert.Equal(t, 3, velocity.Completed)
+	assert.Equal(t, 1.5, velocity.Average)
+	if assert.Len(t, velocity.Series, 2) {
+		assert.Equal(t, 1, velocity.Series[0].Completed)
+		assert.Equal(t, 2, velocity.Series[1].Completed)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTaskVelocity_RejectsInvalidParams(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	router := setupTaskRouter(mockRepo, uuid.New())
+
+	for _, query := range []string{"?window=30", "?window=0d", "?window=400d", "?period=month"} {
+		w := getVelocity(router, query)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	mockRepo.AssertNotCalled(t, "GetCompletedSince", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateTask_BlockedByUnfinishedTask(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	blocker := uuid.New()
+
+	mockRepo.On("GetStatuses", mock.Anything, userID, []uuid.UUID{blocker}).
+		Return(map[uuid.UUID]string{blocker: "in_progress"}, nil)
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(task *models.Task) bool {
+		return assert.ObjectsAreEqual([]uuid.UUID{blocker}, task.BlockedBy)
+	})).Return(nil)
+
+	w := createTask(setupTaskRouter(mockRepo, userID), map[string]interface{}{
+		"title":      "Ship release",
+		"horizon":    "next",
+		"priority":   "high",
+		"blocked_by": []string{blocker.String(), blocker.String()},
+	})
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var task models.Task
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &task))
+	assert.Equal(t, []uuid.UUID{blocker}, task.BlockedBy)
+	assert.True(t, task.IsBlocked)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateTask_RejectsUnknownBlocker(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	blocker := uuid.New()
+
+	mockRepo.On("GetStatuses", mock.Anything, userID, []uuid.UUID{blocker}).Return(map[uuid.UUID]string{}, nil)
+
+	w := createTask(setupTaskRouter(mockRepo, userID), map[string]interface{}{
+		"title":      "Ship release",
+		"horizon":    "next",
+		"priority":   "high",
+		"blocked_by": []string{blocker.String()},
+	})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), models.ErrUnknownBlocker.Error())
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestUpdateTask_BlockedTaskCannotStart(t *testing.T) {
+	for _, status := range []string{"in_progress", "done"} {
+		t.Run(status, func(t *testing.T) {
+			mockRepo := new(MockTaskRepository)
+			userID := uuid.New()
+			task := existingTask(userID)
+			task.BlockedBy = []uuid.UUID{uuid.New()}
+			task.IsBlocked = true
+
+			mockRepo.On("GetByID", mock.Anything, task.ID, userID).Return(task, nil)
+
+			w := patchTask(setupTaskRouter(mockRepo, userID), task.ID, "", map[string]interface{}{"status": status})
+
+			assert.Equal(t, http.StatusConflict, w.Code)
+			assert.Contains(t, w.Body.String(), models.ErrTaskBlocked.Error())
+			mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUpdateTask_BlockedTaskCanStillBeEdited(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := existingTask(userID)
+	task.Status = "in_progress"
+	task.BlockedBy = []uuid.UUID{uuid.New()}
+	task.IsBlocked = true
+
+	mockRepo.On("GetByID", mock.Anything, task.ID, userID).Return(task, nil)
+	mockRepo.On("Update", mock.Anything, task).Return(nil)
+
+	w := patchTask(setupTaskRouter(mockRepo, userID), task.ID, "", map[string]interface{}{
+		"title":  "Write the report",
+		"status": "in_progress",
+	})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateTask_ClearingResolvedBlockersUnblocks(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := existingTask(userID)
+	finished := uuid.New()
+	task.BlockedBy = []uuid.UUID{uuid.New()}
+	task.IsBlocked = true
+
+	mockRepo.On("GetByID", mock.Anything, task.ID, userID).Return(task, nil)
+	mockRepo.On("GetStatuses", mock.Anything, userID, []uuid.UUID{finished}).
+		Return(map[uuid.UUID]string{finished: "done"}, nil)
+	mockRepo.On("Update", mock.Anything, task).Return(nil)
+
+	w := patchTask(setupTaskRouter(mockRepo, userID), task.ID, "", map[string]interface{}{
+		"status":     "in_progress",
+		"blocked_by": []string{finished.String()},
+	})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []uuid.UUID{finished}, task.BlockedBy)
+	assert.False(t, task.IsBlocked)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateTask_ReadsTaskAndBlockersFromPrimary(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := existingTask(userID)
+	blocker := uuid.New()
+	primary := mock.MatchedBy(repository.ReadsPrimary)
+
+	mockRepo.On("GetByID", primary, task.ID, userID).Return(task, nil)
+	mockRepo.On("GetStatuses", primary, userID, []uuid.UUID{blocker}).
+		Return(map[uuid.UUID]string{blocker: "done"}, nil)
+	mockRepo.On("Update", mock.Anything, task).Return(nil)
+
+	w := patchTask(setupTaskRouter(mockRepo, userID), task.ID, "", map[string]interface{}{
+		"blocked_by": []string{blocker.String()},
+	})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateTask_BlockedTransitionsAllowedWhenNotEnforced(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := existingTask(userID)
+	task.BlockedBy = []uuid.UUID{uuid.New()}
+	task.IsBlocked = true
+
+	mockRepo.On("GetByID", mock.Anything, task.ID, userID).Return(task, nil)
+	mockRepo.On("Update", mock.Anything, task).Return(nil)
+
+	handler := NewTaskHandler(mockRepo, models.DefaultMaxDueDateYears, models.DefaultTagLimits, false, &recordingPublisher{})
+	w := patchTask(taskRouter(handler, userID), task.ID, "", map[string]interface{}{"status": "done"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateTask_RejectsDependencyCycle(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := existingTask(userID)
+	blocker := uuid.New()
+
+	mockRepo.On("GetByID", mock.Anything, task.ID, userID).Return(task, nil)
+	mockRepo.On("GetStatuses", mock.Anything, userID, []uuid.UUID{blocker}).
+		Return(map[uuid.UUID]string{blocker: "todo"}, nil)
+	mockRepo.On("Update", mock.Anything, task).Return(models.ErrDependencyCycle)
+
+	w := patchTask(setupTaskRouter(mockRepo, userID), task.ID, "", map[string]interface{}{
+		"blocked_by": []string{blocker.String()},
+	})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), models.ErrDependencyCycle.Error())
+}
+
+func TestUpdateTask_RejectsSelfDependency(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := existingTask(userID)
+
+	mockRepo.On("GetByID", mock.Anything, task.ID, userID).Return(task, nil)
+
+	w := patchTask(setupTaskRouter(mockRepo, userID), task.ID, "", map[string]interface{}{
+		"blocked_by": []string{task.ID.String()},
+	})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), models.ErrTaskBlocksItself.Error())
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestCreateTask_RejectsTooManyTags(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	handler := NewTaskHandler(mockRepo, models.DefaultMaxDueDateYears, models.TagLimits{MaxCount: 2, MaxLength: 10}, true, &recordingPublisher{})
+
+	w := createTask(taskRouter(handler, userID), map[string]interface{}{
+		"title":    "Plan trip",
+		"horizon":  "later",
+		"priority": "low",
+		"tags":     []string{"travel", "family, summer"},
+	})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid tags: too many tags: at most 2 tags allowed, got 3")
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateTask_DuplicateTagsCountOnce(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	handler := NewTaskHandler(mockRepo, models.DefaultMaxDueDateYears, models.TagLimits{MaxCount: 2, MaxLength: 10}, true, &recordingPublisher{})
+
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(task *models.Task) bool {
+		return assert.ObjectsAreEqual([]string{"travel", "family"}, task.Tags)
+	})).Return(nil)
+
+	w := createTask(taskRouter(handler, userID), map[string]interface{}{
+		"title":    "Plan trip",
+		"horizon":  "later",
+		"priority": "low",
+		"tags":     []string{"Travel", "travel, family", " FAMILY "},
+	})
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateTask_RejectsOverLongTag(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := existingTask(userID)
+	handler := NewTaskHandler(mockRepo, models.DefaultMaxDueDateYears, models.TagLimits{MaxCount: 2, MaxLength: 10}, true, &recordingPublisher{})
+
+	mockRepo.On("GetByID", mock.Anything, task.ID, userID).Return(task, nil)
+
+	w := patchTask(taskRouter(handler, userID), task.ID, "", map[string]interface{}{
+		"tags": []string{"work", "  Quarterly-Planning  "},
+	})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid tags: tag too long")
+	assert.Contains(t, w.Body.String(), "quarterly-planning")
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdateTask_KeepsExistingTagsEditable(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := existingTask(userID)
+	task.Tags = []string{"a", "b", "c"}
+	handler := NewTaskHandler(mockRepo, models.DefaultMaxDueDateYears, models.TagLimits{MaxCount: 2, MaxLength: 10}, true, &recordingPublisher{})
+
+	mockRepo.On("GetByID", mock.Anything, task.ID, userID).Return(task, nil)
+	mockRepo.On("Update", mock.Anything, task).Return(nil)
+
+	w := patchTask(taskRouter(handler, userID), task.ID, "", map[string]interface{}{"priority": "high"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateTask_RepeatedDoneKeepsCompletedAt(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	publisher := &recordingPublisher{}
+	userID := uuid.New()
+	completedAt := time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC)
+	task := existingTask(userID)
+	task.Status = "done"
+	task.CompletedAt = &completedAt
+
+	mockRepo.On("GetByID", mock.Anything, task.ID, userID).Return(task, nil)
+
+	router := setupTaskRouterWithEvents(mockRepo, userID, publisher)
+	for i := 0; i < 2; i++ {
+		w := patchTask(router, task.ID, "", map[string]interface{}{"status": "done"})
+
+		assert.Equal(t, 200, w.Code)
+		var response models.Task
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		if assert.NotNil(t, response.CompletedAt) {
+			assert.True(t, completedAt.Equal(*response.CompletedAt))
+		}
+	}
+
+	assert.Empty(t, publisher.events)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdateTask_EditingDoneTaskKeepsCompletedAt(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	completedAt := time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC)
+	task := existingTask(userID)
+	task.Status = "done"
+	task.CompletedAt = &completedAt
+
+	mockRepo.On("GetByID", mock.Anything, task.ID, userID).Return(task, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(updated *models.Task) bool {
+		return updated.CompletedAt != nil && updated.CompletedAt.Equal(completedAt)
+	})).Return(nil)
+
+	router := setupTaskRouter(mockRepo, userID)
+	w := patchTask(router, task.ID, "?return=changes", map[string]interface{}{"status": "done", "priority": "high"})
+
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"changes":["priority"]`)
+	mockRepo.AssertExpectations(t)
+}
+
+func getTasksByHorizon(router *gin.Engine, query string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/tasks/by-horizon"+query, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestTasksByHorizon_ReturnsGroupsWithCap(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+
+	now := existingTask(userID)
+	later := existingTask(userID)
+	later.Horizon = "later"
+	groups := models.GroupTasksByHorizon([]models.Task{*now, *later}, map[string]int{"now": 4, "later": 1}, 1)
+
+	mockRepo.On("GetByHorizon", mock.Anything, userID, models.TaskFilter{Status: "todo"}, 1).Return(groups, nil)
+
+	w := getTasksByHorizon(setupTaskRouter(mockRepo, userID), "?limit=1&status=todo&horizon=now")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data  map[string]models.HorizonGroup `json:"data"`
+		Limit int                            `json:"limit"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Limit)
+	assert.Len(t, resp.Data, 4)
+	if assert.Len(t, resp.Data["now"].Tasks, 1) {
+		assert.Equal(t, now.ID, resp.Data["now"].Tasks[0].ID)
+	}
+	assert.True(t, resp.Data["now"].HasMore)
+	assert.Equal(t, later.ID, resp.Data["later"].Tasks[0].ID)
+	assert.Empty(t, resp.Data["next"].Tasks)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTasksByHorizon_DefaultsLimit(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+
+	mockRepo.On("GetByHorizon", mock.Anything, userID, models.TaskFilter{}, models.DefaultHorizonGroupSize).
+		Return(models.GroupTasksByHorizon(nil, nil, models.DefaultHorizonGroupSize), nil)
+
+	w := getTasksByHorizon(setupTaskRouter(mockRepo, userID), "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTasksByHorizon_RejectsInvalidLimit(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	router := setupTaskRouter(mockRepo, uuid.New())
+
+	for _, query := range []string{"?limit=0", "?limit=101", "?limit=ten"} {
+		w := getTasksByHorizon(router, query)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	mockRepo.AssertNotCalled(t, "GetByHorizon", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func moveToGoal(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("PATCH", "/tasks/move-to-goal", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMoveToGoal_MovesTasksAndPublishesUpdates(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	publisher := &recordingPublisher{}
+	userID := uuid.New()
+	goalID := uuid.New()
+
+	moved := []models.Task{*existingTask(userID), *existingTask(userID)}
+	taskIDs := []uuid.UUID{moved[0].ID, moved[1].ID}
+	mockRepo.On("MoveToGoal", mock.Anything, userID, taskIDs, &goalID).Return(moved, nil)
+
+	body := fmt.Sprintf(`{"task_ids": [%q, %q], "goal_id": %q}`, taskIDs[0], taskIDs[1], goalID)
+	w := moveToGoal(setupTaskRouterWithEvents(mockRepo, userID, publisher), body)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, fmt.Sprintf(`{"moved": 2, "goal_id": %q}`, goalID), w.Body.String())
+	assert.Len(t, publisher.events, 2)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMoveToGoal_NullGoalUnlinks(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+
+	task := existingTask(userID)
+	mockRepo.On("MoveToGoal", mock.Anything, userID, []uuid.UUID{task.ID}, (*uuid.UUID)(nil)).Return([]models.Task{*task}, nil)
+
+	w := moveToGoal(setupTaskRouter(mockRepo, userID), fmt.Sprintf(`{"task_ids": [%q], "goal_id": null}`, task.ID))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"moved": 1, "goal_id": null}`, w.Body.String())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMoveToGoal_RejectsTasksOrGoalNotOwned(t *testing.T) {
+	for _, repoErr := range []error{models.ErrUnknownTask, models.ErrUnknownGoal} {
+		t.Run(repoErr.Error(), func(t *testing.T) {
+			mockRepo := new(MockTaskRepository)
+			publisher := &recordingPublisher{}
+			userID := uuid.New()
+			taskID := uuid.New()
+			goalID := uuid.New()
+
+			mockRepo.On("MoveToGoal", mock.Anything, userID, []uuid.UUID{taskID}, &goalID).Return(nil, repoErr)
+
+			body := fmt.Sprintf(`{"task_ids": [%q], "goal_id": %q}`, taskID, goalID)
+			w := moveToGoal(setupTaskRouterWithEvents(mockRepo, userID, publisher), body)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+			assert.Contains(t, w.Body.String(), repoErr.Error())
+			assert.Empty(t, publisher.events)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMoveToGoal_RejectsInvalidRequests(t *testing.T) {
+	taskID := uuid.New()
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"missing task ids", `{"goal_id": null}`},
+		{"empty task ids", `{"task_ids": []}`},
+		{"duplicate task ids", fmt.Sprintf(`{"task_ids": [%q, %q]}`, taskID, taskID)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepository)
+
+			w := moveToGoal(setupTaskRouter(mockRepo, uuid.New()), tt.body)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+			mockRepo.AssertNotCalled(t, "MoveToGoal", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
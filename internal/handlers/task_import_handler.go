@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/repository"
+	apperrors "github.com/lumen/backend/pkg/errors"
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// TaskImportHandler imports tasks from an uploaded CSV file. The upload size
+// is bounded by the route's MultipartLimit; maxRows bounds the row count.
+type TaskImportHandler struct {
+	repo    repository.TaskRepository
+	maxRows int
+}
+
+func NewTaskImportHandler(repo repository.TaskRepository, maxRows int) *TaskImportHandler {
+	return &TaskImportHandler{repo: repo, maxRows: maxRows}
+}
+
+func (h *TaskImportHandler) Import(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		appErr := apperrors.NewBadRequest("missing CSV file in form field \"file\"")
+		respondError(c, appErr)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		appErr := apperrors.NewBadRequest("unable to read uploaded file")
+		respondError(c, appErr)
+		return
+	}
+	defer file.Close()
+
+	tasks, rowErrors, err := models.ParseTaskCSV(file, userID, h.maxRows)
+	if err != nil {
+		appErr := apperrors.NewValidationError(err.Error())
+		if errors.Is(err, models.ErrTooManyImportRows) {
+			appErr = apperrors.NewPayloadTooLarge(err.Error())
+		}
+		respondError(c, appErr)
+		return
+	}
+
+	if len(tasks) > 0 {
+		if err := h.repo.CreateMany(c.Request.Context(), tasks); err != nil {
+			respondDatabaseError(c, err, "Failed to import tasks", zap.String("user_id", userID.String()))
+			return
+		}
+	}
+
+	if rowErrors == nil {
+		rowErrors = []models.TaskImportError{}
+	}
+
+	logger.Info("Tasks imported",
+		zap.String("user_id", userID.String()),
+		zap.Int("imported", len(tasks)),
+		zap.Int("skipped", len(rowErrors)),
+	)
+
+	status := http.StatusCreated
+	if len(tasks) == 0 {
+		status = http.StatusUnprocessableEntity
+	}
+
+	c.JSON(status, models.TaskImportResult{
+		Imported: len(tasks),
+		Skipped:  len(rowErrors),
+		Errors:   rowErrors,
+	})
+}
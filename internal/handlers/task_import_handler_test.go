@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupImportRouter(repo *MockTaskRepository, userID uuid.UUID, maxRows int) *gin.Engine {
+	router := setupTestRouter()
+	handler := NewTaskImportHandler(repo, maxRows)
+
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	})
+	router.POST("/tasks/import", handler.Import)
+
+	return router
+}
+
+func uploadCSV(router *gin.Engine, content string) *httptest.ResponseRecorder {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("file", "tasks.csv")
+	part.Write([]byte(content))
+	writer.Close()
+
+	req, _ := http.NewRequest("POST", "/tasks/import", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestImportTasks_WellFormed(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+
+	mockRepo.On("CreateMany", mock.Anything, mock.MatchedBy(func(tasks []*models.Task) bool {
+		return len(tasks) == 2 && tasks[0].Title == "Pay rent" && tasks[1].UserID == userID
+	})).Return(nil)
+
+	w := uploadCSV(setupImportRouter(mockRepo, userID, 100),
+		"title,horizon,priority\r\nPay rent,now,high\r\n\"Call mom, Sunday\",next,medium\r\n")
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var result models.TaskImportResult
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, 2, result.Imported)
+	assert.Equal(t, 0, result.Skipped)
+	assert.Empty(t, result.Errors)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportTasks_ReportsInvalidRows(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+
+	mockRepo.On("CreateMany", mock.Anything, mock.MatchedBy(func(tasks []*models.Task) bool {
+		return len(tasks) == 1 && tasks[0].Title == "Valid"
+	})).Return(nil)
+
+	w := uploadCSV(setupImportRouter(mockRepo, userID, 100),
+		"title,horizon,priority\nValid,now,low\nNope,whenever,low\n")
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var result models.TaskImportResult
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, 1, result.Imported)
+	assert.Equal(t, []models.TaskImportError{{Row: 3, Message: models.ErrInvalidHorizon.Error()}}, result.Errors)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportTasks_NoValidRows(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+
+	w := uploadCSV(setupImportRouter(mockRepo, uuid.New(), 100), "title,horizon,priority\n,now,low\n")
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `"imported":0`)
+	mockRepo.AssertNotCalled(t, "CreateMany", mock.Anything, mock.Anything)
+}
+
+func TestImportTasks_TooManyRows(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+
+	w := uploadCSV(setupImportRouter(mockRepo, uuid.New(), 1), "title,horizon,priority\nA,now,low\nB,now,low\n")
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	mockRepo.AssertNotCalled(t, "CreateMany", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/events"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/repository"
+	apperrors "github.com/lumen/backend/pkg/errors"
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// TrashHandler serves the trash: tasks and habits deleted within the
+// retention window, and restoring them.
+type TrashHandler struct {
+	trash     repository.TrashRepository
+	retention time.Duration
+	events    events.Publisher
+}
+
+func NewTrashHandler(trash repository.TrashRepository, retention time.Duration, publisher events.Publisher) *TrashHandler {
+	return &TrashHandler{trash: trash, retention: retention, events: publisher}
+}
+
+// List returns the user's deleted tasks and habits that can still be
+// restored, most recently deleted first, each with the path that restores
+// it.
+func (h *TrashHandler) List(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	items, err := h.trash.List(c.Request.Context(), userID, time.Now().Add(-h.retention))
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to get trash", zap.String("user_id", userID.String()))
+		return
+	}
+
+	base := strings.TrimSuffix(c.Request.URL.Path, "/")
+	for i := range items {
+		items[i].PurgeAt = items[i].DeletedAt.Add(h.retention)
+		items[i].RestoreURL = base + "/" + items[i].Type + "s/" + items[i].ID.String() + "/restore"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  items,
+		"count": len(items),
+	})
+}
+
+// RestoreTask brings a task back from the trash.
+func (h *TrashHandler) RestoreTask(c *gin.Context) {
+	taskID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	task, err := h.trash.RestoreTask(c.Request.Context(), taskID, userID, time.Now().Add(-h.retention))
+	if err == models.ErrNotFound {
+		appErr := apperrors.NewNotFoundWithID("task", taskID)
+		respondError(c, appErr)
+		return
+	}
+
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to restore task", zap.String("task_id", taskID.String()))
+		return
+	}
+
+	logger.Info("Task restored", zap.String("task_id", taskID.String()), zap.String("user_id", userID.String()))
+	h.events.Publish(events.TaskRestored{Task: *task})
+	c.JSON(http.StatusOK, task)
+}
+
+// RestoreHabit brings a habit back from the trash, with its completion
+// history.
+func (h *TrashHandler) RestoreHabit(c *gin.Context) {
+	habitID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	habit, err := h.trash.RestoreHabit(c.Request.Context(), habitID, userID, time.Now().Add(-h.retention))
+	if err == models.ErrNotFound {
+		appErr := apperrors.NewNotFoundWithID("habit", habitID)
+		respondError(c, appErr)
+		return
+	}
+
+	if err != nil {
+		respondDatabaseError(c, err, "Failed to restore habit", zap.String("habit_id", habitID.String()))
+		return
+	}
+
+	logger.Info("Habit restored", zap.String("habit_id", habitID.String()), zap.String("user_id", userID.String()))
+	h.events.Publish(events.HabitRestored{Habit: *habit})
+	c.JSON(http.StatusOK, habit)
+}
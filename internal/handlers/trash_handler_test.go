@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/events"
+	"github.com/lumen/backend/internal/middleware"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTrashRepository is a mock for trash repository
+type MockTrashRepository struct {
+	mock.Mock
+}
+
+func (m *MockTrashRepository) List(ctx context.Context, userID uuid.UUID, since time.Time) ([]models.TrashItem, error) {
+	args := m.Called(ctx, userID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.TrashItem), args.Error(1)
+}
+
+func (m *MockTrashRepository) RestoreTask(ctx context.Context, id, userID uuid.UUID, since time.Time) (*models.Task, error) {
+	args := m.Called(ctx, id, userID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Task), args.Error(1)
+}
+
+func (m *MockTrashRepository) RestoreHabit(ctx context.Context, id, userID uuid.UUID, since time.Time) (*models.Habit, error) {
+	args := m.Called(ctx, id, userID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Habit), args.Error(1)
+}
+
+func (m *MockTrashRepository) Purge(ctx context.Context, before time.Time) (int, error) {
+	args := m.Called(ctx, before)
+	return args.Int(0), args.Error(1)
+}
+
+const testTrashRetention = 30 * 24 * time.Hour
+
+func setupTrashRouter(trash *MockTrashRepository, publisher events.Publisher, userID uuid.UUID) *gin.Engine {
+	router := setupTestRouter()
+	handler := NewTrashHandler(trash, testTrashRetention, publisher)
+
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	})
+	router.Use(middleware.UUIDParams("id"))
+	router.GET("/api/v1/trash", handler.List)
+	router.POST("/api/v1/trash/tasks/:id/restore", handler.RestoreTask)
+	router.POST("/api/v1/trash/habits/:id/restore", handler.RestoreHabit)
+
+	return router
+}
+
+// withinRetention matches a cutoff of now minus the retention window.
+func withinRetention() interface{} {
+	return mock.MatchedBy(func(since time.Time) bool {
+		return time.Since(since.Add(testTrashRetention)).Abs() < time.Minute
+	})
+}
+
+func TestTrashList_ScopesToUserAndRetention(t *testing.T) {
+	trash := new(MockTrashRepository)
+	userID := uuid.New()
+	deletedAt := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	task := models.TrashItem{Type: models.TrashTypeTask, ID: uuid.New(), Title: "Write report", DeletedAt: deletedAt}
+	habit := models.TrashItem{Type: models.TrashTypeHabit, ID: uuid.New(), Title: "Stretch", DeletedAt: deletedAt.Add(-time.Hour)}
+
+	trash.On("List", mock.Anything, userID, withinRetention()).Return([]models.TrashItem{task, habit}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/trash", nil)
+	w := httptest.NewRecorder()
+	setupTrashRouter(trash, &recordingPublisher{}, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data  []models.TrashItem `json:"data"`
+		Count int                `json:"count"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.Count)
+	if assert.Len(t, response.Data, 2) {
+		assert.Equal(t, "/api/v1/trash/tasks/"+task.ID.String()+"/restore", response.Data[0].RestoreURL)
+		assert.Equal(t, deletedAt.Add(testTrashRetention), response.Data[0].PurgeAt)
+		assert.Equal(t, "/api/v1/trash/habits/"+habit.ID.String()+"/restore", response.Data[1].RestoreURL)
+	}
+	trash.AssertExpectations(t)
+}
+
+func TestTrashList_EmptyIsAnArray(t *testing.T) {
+	trash := new(MockTrashRepository)
+	userID := uuid.New()
+
+	trash.On("List", mock.Anything, userID, withinRetention()).Return([]models.TrashItem{}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/trash", nil)
+	w := httptest.NewRecorder()
+	setupTrashRouter(trash, &recordingPublisher{}, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data": [], "count": 0}`, w.Body.String())
+}
+
+func TestTrashRestoreTask_PublishesRestoredTask(t *testing.T) {
+	trash := new(MockTrashRepository)
+	publisher := &recordingPublisher{}
+	userID := uuid.New()
+	task := existingTask(userID)
+
+	trash.On("RestoreTask", mock.Anything, task.ID, userID, withinRetention()).Return(task, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/trash/tasks/"+task.ID.String()+"/restore", nil)
+	w := httptest.NewRecorder()
+	setupTrashRouter(trash, publisher, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	if assert.Len(t, publisher.events, 1) {
+		restored, ok := publisher.events[0].(events.TaskRestored)
+		assert.True(t, ok)
+		assert.Equal(t, task.ID, restored.Task.ID)
+	}
+	trash.AssertExpectations(t)
+}
+
+func TestTrashRestoreHabit_PublishesRestoredHabit(t *testing.T) {
+	trash := new(MockTrashRepository)
+	publisher := &recordingPublisher{}
+	userID := uuid.New()
+	habit := &models.Habit{ID: uuid.New(), UserID: userID, Name: "Stretch", Frequency: "daily", TargetCount: 1}
+
+	trash.On("RestoreHabit", mock.Anything, habit.ID, userID, withinRetention()).Return(habit, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/trash/habits/"+habit.ID.String()+"/restore", nil)
+	w := httptest.NewRecorder()
+	setupTrashRouter(trash, publisher, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	if assert.Len(t, publisher.events, 1) {
+		_, ok := publisher.events[0].(events.HabitRestored)
+		assert.True(t, ok)
+	}
+	trash.AssertExpectations(t)
+}
+
+func TestTrashRestore_NotInTrashIsNotFound(t *testing.T) {
+	// Live items, items past retention and other users' items all come back
+	// from the repository as not found.
+	trash := new(MockTrashRepository)
+	publisher := &recordingPublisher{}
+	userID := uuid.New()
+	taskID := uuid.New()
+	habitID := uuid.New()
+
+	trash.On("RestoreTask", mock.Anything, taskID, userID, withinRetention()).Return(nil, models.ErrNotFound)
+	trash.On("RestoreHabit", mock.Anything, habitID, userID, withinRetention()).Return(nil, models.ErrNotFound)
+	router := setupTrashRouter(trash, publisher, userID)
+
+	for _, path := range []string{"/api/v1/trash/tasks/" + taskID.String() + "/restore", "/api/v1/trash/habits/" + habitID.String() + "/restore"} {
+		req, _ := http.NewRequest(http.MethodPost, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+	}
+	assert.Empty(t, publisher.events)
+	trash.AssertExpectations(t)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/middleware"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// setupValidationRouter serves habit and task creation under profile, wired the
+// way the server wires it for APP_ENV.
+func setupValidationRouter(appEnv string, habits *MockHabitRepo, tasks *MockTaskRepository, userID uuid.UUID) *gin.Engine {
+	profile, _ := models.ValidationProfileFor(appEnv, "")
+
+	router := setupTestRouter()
+	router.Use(middleware.JSONStrictness(profile.RejectUnknownFields))
+	router.Use(middleware.Validation(profile))
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	})
+	router.POST("/habits", NewHabitHandler(habits, &recordingPublisher{}).Create)
+	router.POST("/tasks", NewTaskHandler(tasks, models.DefaultMaxDueDateYears, models.DefaultTagLimits, true, &recordingPublisher{}).Create)
+	return router
+}
+
+func postValidationJSON(router *gin.Engine, path, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestValidationProfile_CustomIconOnlyInDevelopment(t *testing.T) {
+	body := `{"name":"Juggle","color":"#10B981","icon":"juggling-clubs","frequency":"daily","target_count":1}`
+
+	habits := new(MockHabitRepo)
+	habits.On("Create", mock.Anything, mock.Anything).Return(nil)
+	w := postValidationJSON(setupValidationRouter("development", habits, new(MockTaskRepository), uuid.New()), "/habits", body)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	habits = new(MockHabitRepo)
+	w = postValidationJSON(setupValidationRouter("production", habits, new(MockTaskRepository), uuid.New()), "/habits", body)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid icon")
+	habits.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestValidationProfile_SupportedIconPassesInProduction(t *testing.T) {
+	habits := new(MockHabitRepo)
+	habits.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	w := postValidationJSON(setupValidationRouter("production", habits, new(MockTaskRepository), uuid.New()), "/habits",
+		`{"name":"Run","color":"#10B981","icon":"run","frequency":"daily","target_count":1}`)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestValidationProfile_PastDueDateOnlyInDevelopment(t *testing.T) {
+	body := `{"title":"File taxes","horizon":"now","priority":"high","due_date":"` + time.Now().AddDate(0, 0, -7).Format(time.RFC3339) + `"}`
+
+	tasks := new(MockTaskRepository)
+	tasks.On("Create", mock.Anything, mock.Anything).Return(nil)
+	w := postValidationJSON(setupValidationRouter("development", new(MockHabitRepo), tasks, uuid.New()), "/tasks", body)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	tasks = new(MockTaskRepository)
+	w = postValidationJSON(setupValidationRouter("production", new(MockHabitRepo), tasks, uuid.New()), "/tasks", body)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), models.ErrDueDateInPast.Error())
+	tasks.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestValidationProfile_UnknownFieldsOnlyInDevelopment(t *testing.T) {
+	body := `{"title":"File taxes","horizon":"now","priority":"high","prority":"low"}`
+
+	tasks := new(MockTaskRepository)
+	tasks.On("Create", mock.Anything, mock.Anything).Return(nil)
+	w := postValidationJSON(setupValidationRouter("development", new(MockHabitRepo), tasks, uuid.New()), "/tasks", body)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = postValidationJSON(setupValidationRouter("production", new(MockHabitRepo), new(MockTaskRepository), uuid.New()), "/tasks", body)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "unknown fields: prority")
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/repository"
+	"github.com/lumen/backend/internal/webhooks"
+	apperrors "github.com/lumen/backend/pkg/errors"
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// WebhookSender makes a single delivery attempt, returning the response
+// status (0 if none was received).
+type WebhookSender interface {
+	Send(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) (int, error)
+}
+
+type WebhookHandler struct {
+	repo   repository.WebhookRepository
+	sender WebhookSender
+}
+
+func NewWebhookHandler(repo repository.WebhookRepository, sender WebhookSender) *WebhookHandler {
+	return &WebhookHandler{repo: repo, sender: sender}
+}
+
+// RetryDelivery re-sends the stored payload of a failed delivery, signed with
+// the webhook's current secret, and records the attempt. The response carries
+// the delivery with the new outcome, which may be another failure.
+func (h *WebhookHandler) RetryDelivery(c *gin.Context) {
+	webhookID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+	deliveryID, ok := pathUUID(c, "delivery_id")
+	if !ok {
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	// The webhook is read from the primary so a secret rotated just before
+	// the retry is the one it is signed with.
+	ctx := repository.WithPrimary(c.Request.Context())
+	webhook, err := h.repo.GetByID(ctx, webhookID, userID)
+	if err == models.ErrNotFound {
+		appErr := apperrors.NewNotFoundWithID("webhook", webhookID)
+		respondError(c, appErr)
+		return
+	} else if err != nil {
+		respondDatabaseError(c, err, "Failed to get webhook", zap.String("webhook_id", webhookID.String()))
+		return
+	}
+
+	delivery, err := h.repo.GetDelivery(ctx, deliveryID, webhook.ID)
+	if err == models.ErrNotFound {
+		appErr := apperrors.NewNotFoundWithID("webhook delivery", deliveryID)
+		respondError(c, appErr)
+		return
+	} else if err != nil {
+		respondDatabaseError(c, err, "Failed to get webhook delivery", zap.String("delivery_id", deliveryID.String()))
+		return
+	}
+
+	if delivery.Status != models.WebhookDeliveryFailed {
+		appErr := apperrors.NewConflict(models.ErrDeliveryNotFailed.Error())
+		respondError(c, appErr)
+		return
+	}
+
+	statusCode, sendErr := h.sender.Send(ctx, webhook, delivery)
+	delivery.RecordAttempt(webhook.SecretVersion, statusCode, sendErr, time.Now())
+
+	if err := h.repo.RecordAttempt(ctx, delivery); err != nil {
+		respondDatabaseError(c, err, "Failed to record webhook delivery attempt", zap.String("delivery_id", deliveryID.String()))
+		return
+	}
+
+	logger.Info("Webhook delivery retried",
+		zap.String("delivery_id", deliveryID.String()),
+		zap.String("status", delivery.Status),
+		zap.Int("attempts", delivery.Attempts),
+		zap.String("user_id", userID.String()),
+	)
+	c.JSON(http.StatusOK, delivery)
+}
+
+// RotateSecret gives the webhook a new signing secret, returned once in the
+// response, and bumps its secret_version. Every later attempt, including
+// retries of deliveries first signed with the old secret, is signed with it.
+func (h *WebhookHandler) RotateSecret(c *gin.Context) {
+	webhookID, ok := pathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		appErr := apperrors.NewUnauthorized("user not authenticated")
+		respondError(c, appErr)
+		return
+	}
+
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		logger.Error("Failed to generate webhook secret", zap.Error(err))
+		appErr := apperrors.NewInternalServer(err)
+		respondError(c, appErr)
+		return
+	}
+
+	webhook, err := h.repo.RotateSecret(c.Request.Context(), webhookID, userID, secret)
+	if err == models.ErrNotFound {
+		appErr := apperrors.NewNotFoundWithID("webhook", webhookID)
+		respondError(c, appErr)
+		return
+	} else if err != nil {
+		respondDatabaseError(c, err, "Failed to rotate webhook secret", zap.String("webhook_id", webhookID.String()))
+		return
+	}
+
+	logger.Info("Webhook secret rotated",
+		zap.String("webhook_id", webhookID.String()),
+		zap.Int("secret_version", webhook.SecretVersion),
+		zap.String("user_id", userID.String()),
+	)
+	c.JSON(http.StatusOK, gin.H{
+		"id":             webhook.ID,
+		"secret":         webhook.Secret,
+		"secret_version": webhook.SecretVersion,
+	})
+}
@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/integrations"
+	"github.com/lumen/backend/internal/middleware"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/repository"
+	"github.com/lumen/backend/internal/webhooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWebhookRepository is a mock for webhook repository
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) GetByID(ctx context.Context, id, userID uuid.UUID) (*models.Webhook, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetDelivery(ctx context.Context, id, webhookID uuid.UUID) (*models.WebhookDelivery, error) {
+	args := m.Called(ctx, id, webhookID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) RotateSecret(ctx context.Context, id, userID uuid.UUID, secret string) (*models.Webhook, error) {
+	args := m.Called(ctx, id, userID, secret)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Webhook), args.Error(1)
+}
+
+func setupWebhookRouter(repo *MockWebhookRepository, userID uuid.UUID) *gin.Engine {
+	router := setupTestRouter()
+	handler := NewWebhookHandler(repo, webhooks.NewSender(integrations.Timeouts{Webhook: 2 * time.Second}))
+
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Next()
+	})
+	router.Use(middleware.UUIDParams("id", "delivery_id"))
+	router.POST("/webhooks/:id/deliveries/:delivery_id/retry", handler.RetryDelivery)
+	router.POST("/webhooks/:id/rotate-secret", handler.RotateSecret)
+
+	return router
+}
+
+func retryDelivery(router *gin.Engine, webhookID, deliveryID uuid.UUID) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/webhooks/"+webhookID.String()+"/deliveries/"+deliveryID.String()+"/retry", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func failedDelivery(webhook *models.Webhook) *models.WebhookDelivery {
+	lastError := "webhook endpoint responded with status 503"
+	lastStatus := http.StatusServiceUnavailable
+	return &models.WebhookDelivery{
+		ID:                 uuid.New(),
+		WebhookID:          webhook.ID,
+		UserID:             webhook.UserID,
+		Event:              "task.created",
+		Payload:            json.RawMessage(`{"task_id":"123"}`),
+		Status:             models.WebhookDeliveryFailed,
+		Attempts:           1,
+		LastResponseStatus: &lastStatus,
+		LastError:          &lastError,
+	}
+}
+
+func TestRetryWebhookDelivery_HealthyEndpoint(t *testing.T) {
+	var received []byte
+	var signature string
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(webhooks.SignatureHeader)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer endpoint.Close()
+
+	mockRepo := new(MockWebhookRepository)
+	userID := uuid.New()
+	webhook := &models.Webhook{ID: uuid.New(), UserID: userID, URL: endpoint.URL, Secret: "whsec", IsActive: true}
+	delivery := failedDelivery(webhook)
+
+	mockRepo.On("GetByID", mock.Anything, webhook.ID, userID).Return(webhook, nil)
+	mockRepo.On("GetDelivery", mock.Anything, delivery.ID, webhook.ID).Return(delivery, nil)
+	mockRepo.On("RecordAttempt", mock.Anything, mock.MatchedBy(func(d *models.WebhookDelivery) bool {
+		return d.ID == delivery.ID && d.Status == models.WebhookDeliverySucceeded && d.Attempts == 2
+	})).Return(nil)
+
+	w := retryDelivery(setupWebhookRouter(mockRepo, userID), webhook.ID, delivery.ID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"task_id":"123"}`, string(received))
+	assert.Equal(t, webhooks.Sign("whsec", received), signature)
+
+	var response models.WebhookDelivery
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, models.WebhookDeliverySucceeded, response.Status)
+	assert.Equal(t, 2, response.Attempts)
+	assert.Nil(t, response.LastError)
+	if assert.NotNil(t, response.LastResponseStatus) {
+		assert.Equal(t, http.StatusOK, *response.LastResponseStatus)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRetryWebhookDelivery_StillFailing(t *testing.T) {
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer endpoint.Close()
+
+	mockRepo := new(MockWebhookRepository)
+	userID := uuid.New()
+	webhook := &models.Webhook{ID: uuid.New(), UserID: userID, URL: endpoint.URL, Secret: "whsec"}
+	delivery := failedDelivery(webhook)
+
+	mockRepo.On("GetByID", mock.Anything, webhook.ID, userID).Return(webhook, nil)
+	mockRepo.On("GetDelivery", mock.Anything, delivery.ID, webhook.ID).Return(delivery, nil)
+	mockRepo.On("RecordAttempt", mock.Anything, delivery).Return(nil)
+
+	w := retryDelivery(setupWebhookRouter(mockRepo, userID), webhook.ID, delivery.ID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.WebhookDeliveryFailed, delivery.Status)
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.Equal(t, http.StatusBadGateway, *delivery.LastResponseStatus)
+}
+
+func TestRetryWebhookDelivery_NotOwnedWebhook(t *testing.T) {
+	mockRepo := new(MockWebhookRepository)
+	intruder := uuid.New()
+	webhookID := uuid.New()
+	deliveryID := uuid.New()
+
+	// Lookups are scoped by user, so another user's webhook is simply missing.
+	mockRepo.On("GetByID", mock.Anything, webhookID, intruder).Return(nil, models.ErrNotFound)
+
+	w := retryDelivery(setupWebhookRouter(mockRepo, intruder), webhookID, deliveryID)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "webhook "+webhookID.String()+" not found")
+	mockRepo.AssertNotCalled(t, "GetDelivery", mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "RecordAttempt", mock.Anything, mock.Anything)
+}
+
+func TestRetryWebhookDelivery_OnlyFailedDeliveries(t *testing.T) {
+	mockRepo := new(MockWebhookRepository)
+	userID := uuid.New()
+	webhook := &models.Webhook{ID: uuid.New(), UserID: userID, URL: "http://127.0.0.1:0", Secret: "whsec"}
+	delivery := failedDelivery(webhook)
+	delivery.Status = models.WebhookDeliverySucceeded
+
+	mockRepo.On("GetByID", mock.Anything, webhook.ID, userID).Return(webhook, nil)
+	mockRepo.On("GetDelivery", mock.Anything, delivery.ID, webhook.ID).Return(delivery, nil)
+
+	w := retryDelivery(setupWebhookRouter(mockRepo, userID), webhook.ID, delivery.ID)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockRepo.AssertNotCalled(t, "RecordAttempt", mock.Anything, mock.Anything)
+}
+
+func rotateSecret(router *gin.Engine, webhookID uuid.UUID) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/webhooks/"+webhookID.String()+"/rotate-secret", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRetryWebhookDelivery_AfterRotationSignsWithNewSecret(t *testing.T) {
+	var received []byte
+	var signature, version string
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(webhooks.SignatureHeader)
+		version = r.Header.Get(webhooks.SignatureVersionHeader)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer endpoint.Close()
+
+	mockRepo := new(MockWebhookRepository)
+	userID := uuid.New()
+	webhook := &models.Webhook{ID: uuid.New(), UserID: userID, URL: endpoint.URL, Secret: "whsec_old", SecretVersion: 1, IsActive: true}
+	delivery := failedDelivery(webhook)
+	signedWith := 1
+	delivery.SecretVersion = &signedWith
+	router := setupWebhookRouter(mockRepo, userID)
+
+	rotated := *webhook
+	rotated.SecretVersion = 2
+	mockRepo.On("RotateSecret", mock.Anything, webhook.ID, userID, mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { rotated.Secret = args.String(3) }).
+		Return(&rotated, nil)
+
+	w := rotateSecret(router, webhook.ID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var rotation struct {
+		Secret        string `json:"secret"`
+		SecretVersion int    `json:"secret_version"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &rotation))
+	assert.True(t, strings.HasPrefix(rotation.Secret, "whsec_"))
+	assert.NotEqual(t, "whsec_old", rotation.Secret)
+	assert.Equal(t, 2, rotation.SecretVersion)
+
+	mockRepo.On("GetByID", mock.MatchedBy(repository.ReadsPrimary), webhook.ID, userID).Return(&rotated, nil)
+	mockRepo.On("GetDelivery", mock.Anything, delivery.ID, webhook.ID).Return(delivery, nil)
+	mockRepo.On("RecordAttempt", mock.Anything, mock.MatchedBy(func(d *models.WebhookDelivery) bool {
+		return d.SecretVersion != nil && *d.SecretVersion == 2
+	})).Return(nil)
+
+	w = retryDelivery(router, webhook.ID, delivery.ID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, webhooks.Sign(rotation.Secret, received), signature)
+	assert.NotEqual(t, webhooks.Sign("whsec_old", received), signature)
+	assert.Equal(t, "2", version)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRotateWebhookSecret_NotOwnedWebhook(t *testing.T) {
+	mockRepo := new(MockWebhookRepository)
+	intruder := uuid.New()
+	webhookID := uuid.New()
+
+	mockRepo.On("RotateSecret", mock.Anything, webhookID, intruder, mock.AnythingOfType("string")).Return(nil, models.ErrNotFound)
+
+	w := rotateSecret(setupWebhookRouter(mockRepo, intruder), webhookID)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.NotContains(t, w.Body.String(), "whsec_")
+}
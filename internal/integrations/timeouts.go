@@ -0,0 +1,94 @@
+// Package integrations holds shared plumbing for calls to external services
+// (Supabase, webhook receivers, SMTP) so each one runs under its own budget.
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lumen/backend/pkg/config"
+)
+
+// Integration names an external dependency with its own timeout.
+type Integration string
+
+const (
+	SupabaseJWKS Integration = "supabase_jwks"
+	Webhook      Integration = "webhook"
+	SMTP         Integration = "smtp"
+)
+
+// Timeouts is the per-call budget for each integration. A zero value means
+// calls to that integration are bounded only by the caller's context.
+type Timeouts struct {
+	SupabaseJWKS time.Duration
+	Webhook      time.Duration
+	SMTP         time.Duration
+}
+
+func TimeoutsFromConfig(cfg *config.Config) Timeouts {
+	return Timeouts{
+		SupabaseJWKS: cfg.SupabaseJWKSTimeout,
+		Webhook:      cfg.WebhookTimeout,
+		SMTP:         cfg.SMTPTimeout,
+	}
+}
+
+func (t Timeouts) For(integration Integration) time.Duration {
+	switch integration {
+	case SupabaseJWKS:
+		return t.SupabaseJWKS
+	case Webhook:
+		return t.Webhook
+	case SMTP:
+		return t.SMTP
+	default:
+		return 0
+	}
+}
+
+// TimeoutError reports that an integration call exceeded its budget.
+type TimeoutError struct {
+	Integration Integration
+	Timeout     time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s call timed out after %s", e.Integration, e.Timeout)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// Call runs fn under a context bounded by the integration's timeout. It
+// returns as soon as the deadline passes even if fn ignores its context
+// (net/smtp, for one, takes none); fn then finishes in the background and its
+// result is dropped. Cancellation of ctx itself is returned as ctx.Err().
+func (t Timeouts) Call(ctx context.Context, integration Integration, fn func(context.Context) error) error {
+	timeout := t.For(integration)
+	callCtx, cancel := ctx, context.CancelFunc(func() {})
+	if timeout > 0 {
+		callCtx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(callCtx)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil && callCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+			return &TimeoutError{Integration: integration, Timeout: timeout}
+		}
+		return err
+	case <-callCtx.Done():
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return &TimeoutError{Integration: integration, Timeout: timeout}
+	}
+}
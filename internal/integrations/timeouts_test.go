@@ -0,0 +1,95 @@
+package integrations
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// hang blocks until its context is done, like a dependency that never answers.
+func hang(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestCall_TimesOutAtConfiguredBound(t *testing.T) {
+	timeouts := Timeouts{SupabaseJWKS: 50 * time.Millisecond}
+
+	start := time.Now()
+	err := timeouts.Call(context.Background(), SupabaseJWKS, hang)
+	elapsed := time.Since(start)
+
+	var timeoutErr *TimeoutError
+	assert.True(t, errors.As(err, &timeoutErr))
+	assert.Equal(t, SupabaseJWKS, timeoutErr.Integration)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.GreaterOrEqual(t, elapsed, 50*time.Millisecond)
+	assert.Less(t, elapsed, 500*time.Millisecond)
+}
+
+func TestCall_SlowDependencyDoesNotAffectOthers(t *testing.T) {
+	timeouts := Timeouts{
+		SupabaseJWKS: 30 * time.Millisecond,
+		Webhook:      time.Second,
+		SMTP:         time.Second,
+	}
+
+	var wg sync.WaitGroup
+	results := make(map[Integration]error)
+	var mu sync.Mutex
+	run := func(integration Integration, fn func(context.Context) error) {
+		defer wg.Done()
+		err := timeouts.Call(context.Background(), integration, fn)
+		mu.Lock()
+		results[integration] = err
+		mu.Unlock()
+	}
+
+	wg.Add(3)
+	go run(SupabaseJWKS, hang)
+	go run(Webhook, func(ctx context.Context) error {
+		time.Sleep(80 * time.Millisecond)
+		return nil
+	})
+	go run(SMTP, func(ctx context.Context) error {
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.Greater(t, time.Until(deadline), 500*time.Millisecond)
+		return nil
+	})
+	wg.Wait()
+
+	assert.ErrorIs(t, results[SupabaseJWKS], context.DeadlineExceeded)
+	assert.NoError(t, results[Webhook])
+	assert.NoError(t, results[SMTP])
+}
+
+func TestCall_ReturnsEvenIfDependencyIgnoresContext(t *testing.T) {
+	timeouts := Timeouts{SMTP: 20 * time.Millisecond}
+	release := make(chan struct{})
+	defer close(release)
+
+	start := time.Now()
+	err := timeouts.Call(context.Background(), SMTP, func(context.Context) error {
+		<-release
+		return nil
+	})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestCall_PassesThroughResultsAndCancellation(t *testing.T) {
+	timeouts := Timeouts{Webhook: time.Second}
+	boom := errors.New("connection refused")
+
+	assert.ErrorIs(t, timeouts.Call(context.Background(), Webhook, func(context.Context) error { return boom }), boom)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, timeouts.Call(ctx, Webhook, hang), context.Canceled)
+}
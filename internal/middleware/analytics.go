@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lumen/backend/internal/analytics"
+)
+
+// Analytics records an "api_request" event per request. Emitting never
+// blocks or fails, so the collector's health can't affect the response.
+func Analytics(emitter *analytics.Emitter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		event := analytics.Event{
+			Name: "api_request",
+			Properties: map[string]interface{}{
+				"method":      c.Request.Method,
+				"route":       c.FullPath(),
+				"status":      c.Writer.Status(),
+				"duration_ms": time.Since(start).Milliseconds(),
+			},
+		}
+		if userID, exists := c.Get("user_id"); exists {
+			event.UserID = fmt.Sprint(userID)
+		}
+
+		emitter.Emit(event)
+	}
+}
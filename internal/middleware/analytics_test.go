@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lumen/backend/internal/analytics"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingSink struct{}
+
+func (failingSink) Send(ctx context.Context, events []analytics.Event) error {
+	return errors.New("collector returned 503")
+}
+
+func TestAnalytics_FailingSinkDoesNotAffectRequests(t *testing.T) {
+	emitter := analytics.NewEmitter(failingSink{}, analytics.Options{
+		BufferSize:       5,
+		BatchSize:        1,
+		FlushInterval:    time.Hour,
+		SendTimeout:      time.Second,
+		FailureThreshold: 2,
+		Cooldown:         time.Hour,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go emitter.Run(ctx)
+
+	router := setupTestRouter()
+	router.Use(Analytics(emitter))
+	router.GET("/api/v1/ping", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "pong"})
+	})
+
+	for i := 0; i < 50; i++ {
+		req, _ := http.NewRequest("GET", "/api/v1/ping", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	assert.Eventually(t, emitter.BreakerOpen, time.Second, 5*time.Millisecond)
+	assert.Positive(t, emitter.Dropped())
+}
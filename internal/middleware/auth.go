@@ -1,29 +1,77 @@
 package middleware
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	apperrors "github.com/lumen/backend/pkg/errors"
 )
 
+// AuthMiddleware authenticates requests carrying an HS256 JWT signed with
+// jwtSecret. When set, issuer and audience must match the token's iss and
+// aud claims, so tokens minted for another service are refused.
 type AuthMiddleware struct {
 	jwtSecret string
+	issuer    string
+	audience  string
 }
 
-func NewAuthMiddleware(jwtSecret string) *AuthMiddleware {
+func NewAuthMiddleware(jwtSecret, issuer, audience string) *AuthMiddleware {
 	return &AuthMiddleware{
 		jwtSecret: jwtSecret,
+		issuer:    issuer,
+		audience:  audience,
 	}
 }
 
+var (
+	errMalformedToken = errors.New("malformed token")
+	errBadSignature   = errors.New("token signature mismatch")
+	errTokenExpired   = errors.New("token expired")
+	errWrongIssuer    = errors.New("token issuer mismatch")
+	errWrongAudience  = errors.New("token audience mismatch")
+)
+
+// tokenClaims are the registered claims validateToken checks.
+type tokenClaims struct {
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss"`
+	Audience  audience `json:"aud"`
+	ExpiresAt int64    `json:"exp"`
+}
+
+// audience is the aud claim, which may be a single string or a list.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
 func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			appErr := apperrors.NewUnauthorized("missing authorization header")
-			c.JSON(appErr.StatusCode, appErr)
+			RespondError(c, appErr)
 			c.Abort()
 			return
 		}
@@ -31,7 +79,7 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
 			appErr := apperrors.NewUnauthorized("invalid authorization header format")
-			c.JSON(appErr.StatusCode, appErr)
+			RespondError(c, appErr)
 			c.Abort()
 			return
 		}
@@ -40,7 +88,7 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 		userID, err := m.validateToken(token)
 		if err != nil {
 			appErr := apperrors.NewUnauthorized("invalid or expired token")
-			c.JSON(appErr.StatusCode, appErr)
+			RespondError(c, appErr)
 			c.Abort()
 			return
 		}
@@ -71,14 +119,58 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 	}
 }
 
+// validateToken verifies token's signature, expiry, issuer and audience and
+// returns the user in its sub claim.
 func (m *AuthMiddleware) validateToken(token string) (uuid.UUID, error) {
-	userIDStr := token
-	userID, err := uuid.Parse(userIDStr)
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return uuid.Nil, errMalformedToken
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return uuid.Nil, errMalformedToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
 	if err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, errMalformedToken
+	}
+	mac := hmac.New(sha256.New, []byte(m.jwtSecret))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return uuid.Nil, errBadSignature
+	}
+
+	var claims tokenClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return uuid.Nil, errMalformedToken
+	}
+
+	if claims.ExpiresAt == 0 || time.Now().Unix() >= claims.ExpiresAt {
+		return uuid.Nil, errTokenExpired
+	}
+
+	if m.issuer != "" && claims.Issuer != m.issuer {
+		return uuid.Nil, errWrongIssuer
+	}
+
+	if m.audience != "" && !slices.Contains(claims.Audience, m.audience) {
+		return uuid.Nil, errWrongAudience
 	}
 
-	return userID, nil
+	return uuid.Parse(claims.Subject)
+}
+
+// decodeSegment decodes a base64url JWT segment into v.
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
 }
 
 func (m *AuthMiddleware) RequireRole(roles ...string) gin.HandlerFunc {
@@ -86,7 +178,7 @@ func (m *AuthMiddleware) RequireRole(roles ...string) gin.HandlerFunc {
 		userRole, exists := c.Get("user_role")
 		if !exists {
 			appErr := apperrors.NewForbidden("user role not found")
-			c.JSON(appErr.StatusCode, appErr)
+			RespondError(c, appErr)
 			c.Abort()
 			return
 		}
@@ -94,7 +186,7 @@ func (m *AuthMiddleware) RequireRole(roles ...string) gin.HandlerFunc {
 		role, ok := userRole.(string)
 		if !ok {
 			appErr := apperrors.NewForbidden("invalid user role")
-			c.JSON(appErr.StatusCode, appErr)
+			RespondError(c, appErr)
 			c.Abort()
 			return
 		}
@@ -109,7 +201,7 @@ func (m *AuthMiddleware) RequireRole(roles ...string) gin.HandlerFunc {
 
 		if !hasRole {
 			appErr := apperrors.NewForbidden("insufficient permissions")
-			c.JSON(appErr.StatusCode, appErr)
+			RespondError(c, appErr)
 			c.Abort()
 			return
 		}
@@ -1,11 +1,17 @@
 package middleware
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -172,3 +178,140 @@ func TestAuthMiddleware_UserContext(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "user-123")
 	assert.Contains(t, w.Body.String(), "test@example.com")
 }
+
+const (
+	testJWTSecret = "test-secret"
+	testIssuer    = "https://example.supabase.co/auth/v1"
+	testAudience  = "authenticated"
+)
+
+// signToken builds an HS256 JWT carrying claims.
+func signToken(secret string, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// supabaseClaims are the claims of a valid token for userID; overrides
+// replace or, when nil, remove individual claims.
+func supabaseClaims(userID uuid.UUID, overrides map[string]interface{}) map[string]interface{} {
+	claims := map[string]interface{}{
+		"sub": userID.String(),
+		"iss": testIssuer,
+		"aud": testAudience,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for name, value := range overrides {
+		if value == nil {
+			delete(claims, name)
+			continue
+		}
+		claims[name] = value
+	}
+	return claims
+}
+
+func authenticatedRequest(m *AuthMiddleware, token string) *httptest.ResponseRecorder {
+	router := setupTestRouter()
+	router.Use(m.Authenticate())
+	router.GET("/protected", func(c *gin.Context) {
+		userID, _ := GetUserID(c)
+		c.JSON(200, gin.H{"user_id": userID})
+	})
+
+	req, _ := http.NewRequest("GET", "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestValidateToken_AcceptsExpectedIssuerAndAudience(t *testing.T) {
+	m := NewAuthMiddleware(testJWTSecret, testIssuer, testAudience)
+	userID := uuid.New()
+
+	w := authenticatedRequest(m, signToken(testJWTSecret, supabaseClaims(userID, nil)))
+
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), userID.String())
+}
+
+func TestValidateToken_AcceptsAudienceList(t *testing.T) {
+	m := NewAuthMiddleware(testJWTSecret, testIssuer, testAudience)
+	userID := uuid.New()
+
+	got, err := m.validateToken(signToken(testJWTSecret, supabaseClaims(userID, map[string]interface{}{
+		"aud": []string{"other-service", testAudience},
+	})))
+
+	assert.NoError(t, err)
+	assert.Equal(t, userID, got)
+}
+
+func TestValidateToken_RejectsWrongIssuer(t *testing.T) {
+	m := NewAuthMiddleware(testJWTSecret, testIssuer, testAudience)
+
+	for name, iss := range map[string]interface{}{
+		"other project": "https://other.supabase.co/auth/v1",
+		"missing":       nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			token := signToken(testJWTSecret, supabaseClaims(uuid.New(), map[string]interface{}{"iss": iss}))
+
+			_, err := m.validateToken(token)
+			assert.ErrorIs(t, err, errWrongIssuer)
+			assert.Equal(t, 401, authenticatedRequest(m, token).Code)
+		})
+	}
+}
+
+func TestValidateToken_RejectsWrongAudience(t *testing.T) {
+	m := NewAuthMiddleware(testJWTSecret, testIssuer, testAudience)
+
+	for name, aud := range map[string]interface{}{
+		"other audience": "anon",
+		"list without":   []string{"anon", "service_role"},
+		"missing":        nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			token := signToken(testJWTSecret, supabaseClaims(uuid.New(), map[string]interface{}{"aud": aud}))
+
+			_, err := m.validateToken(token)
+			assert.ErrorIs(t, err, errWrongAudience)
+			assert.Equal(t, 401, authenticatedRequest(m, token).Code)
+		})
+	}
+}
+
+func TestValidateToken_UnsetIssuerAndAudienceAreNotChecked(t *testing.T) {
+	m := NewAuthMiddleware(testJWTSecret, "", "")
+	userID := uuid.New()
+
+	got, err := m.validateToken(signToken(testJWTSecret, supabaseClaims(userID, map[string]interface{}{
+		"iss": "anyone",
+		"aud": nil,
+	})))
+
+	assert.NoError(t, err)
+	assert.Equal(t, userID, got)
+}
+
+func TestValidateToken_RejectsBadSignatureAndExpiry(t *testing.T) {
+	m := NewAuthMiddleware(testJWTSecret, testIssuer, testAudience)
+	userID := uuid.New()
+
+	_, err := m.validateToken(signToken("another-secret", supabaseClaims(userID, nil)))
+	assert.ErrorIs(t, err, errBadSignature)
+
+	_, err = m.validateToken(signToken(testJWTSecret, supabaseClaims(userID, map[string]interface{}{
+		"exp": time.Now().Add(-time.Minute).Unix(),
+	})))
+	assert.ErrorIs(t, err, errTokenExpired)
+
+	_, err = m.validateToken(userID.String())
+	assert.ErrorIs(t, err, errMalformedToken)
+}
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/lumen/backend/pkg/errors"
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// BodyReadLimits bounds how a request body may arrive.
+type BodyReadLimits struct {
+	// Timeout answers 408 when the body hasn't fully arrived by then (0 = no limit).
+	Timeout time.Duration
+	// SlowThreshold logs bodies that took longer than this to arrive (0 = off).
+	SlowThreshold time.Duration
+	// MaxSize caps the buffered body; larger bodies get 413.
+	MaxSize int64
+	// Sizes, if set, records each fully read body's size under its route.
+	Sizes *BodySizeMetrics
+}
+
+var errBodyReadTimeout = errors.New("request body read timed out")
+
+// BodyReadTimeout reads the request body up front, so a client trickling it
+// in (slowloris-style) holds the connection for at most Timeout and gets a
+// 408 rather than tying up a handler. Slow but successful uploads are logged.
+// The connection's read deadline is set when the server supports it, which
+// also unblocks a client that stops sending entirely; the body is then
+// restored for the handler.
+func BodyReadTimeout(limits BodyReadLimits) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody || (limits.Timeout <= 0 && limits.SlowThreshold <= 0) {
+			c.Next()
+			return
+		}
+
+		if limits.MaxSize > 0 && c.Request.ContentLength > limits.MaxSize {
+			abortTooLarge(c, limits.MaxSize)
+			return
+		}
+
+		start := time.Now()
+		reader := &deadlineReader{r: c.Request.Body}
+		if limits.MaxSize > 0 {
+			reader.r = http.MaxBytesReader(c.Writer, c.Request.Body, limits.MaxSize)
+		}
+		if limits.Timeout > 0 {
+			reader.deadline = start.Add(limits.Timeout)
+			controller := http.NewResponseController(c.Writer)
+			if controller.SetReadDeadline(reader.deadline) == nil {
+				defer controller.SetReadDeadline(time.Time{})
+			}
+		}
+
+		body, err := io.ReadAll(reader)
+		elapsed := time.Since(start)
+
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.String("client_ip", c.ClientIP()),
+			zap.Int("bytes", len(body)),
+			zap.Duration("duration", elapsed),
+		}
+
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case isReadTimeout(err):
+			logger.Warn("Request body read timed out", fields...)
+			appErr := apperrors.NewRequestTimeout(fmt.Sprintf("request body not received within %s", limits.Timeout))
+			c.Header("Connection", "close")
+			RespondError(c, appErr)
+			c.Abort()
+			return
+		case errors.As(err, &maxBytesErr):
+			abortTooLarge(c, limits.MaxSize)
+			return
+		case err != nil:
+			// Let the handler's binding surface the read error.
+			c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), errReader{err}))
+			c.Next()
+			return
+		}
+
+		if limits.SlowThreshold > 0 && elapsed > limits.SlowThreshold {
+			logger.Warn("Slow request body", fields...)
+		}
+
+		if route := c.FullPath(); route != "" {
+			limits.Sizes.Observe(c.Request.Method+" "+route, len(body))
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
+
+// deadlineReader fails reads once deadline has passed. It catches clients
+// that keep sending just enough to avoid blocking a read past the deadline.
+type deadlineReader struct {
+	r        io.Reader
+	deadline time.Time
+}
+
+func (d *deadlineReader) Read(p []byte) (int, error) {
+	if !d.deadline.IsZero() && !time.Now().Before(d.deadline) {
+		return 0, errBodyReadTimeout
+	}
+	return d.r.Read(p)
+}
+
+func isReadTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, errBodyReadTimeout) || errors.Is(err, os.ErrDeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lumen/backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// slowBody hands out one chunk per Read, pausing before each.
+type slowBody struct {
+	chunks []string
+	delay  time.Duration
+}
+
+func (b *slowBody) Read(p []byte) (int, error) {
+	if len(b.chunks) == 0 {
+		return 0, io.EOF
+	}
+	time.Sleep(b.delay)
+	n := copy(p, b.chunks[0])
+	b.chunks = b.chunks[1:]
+	return n, nil
+}
+
+func observeBodyLogs(t *testing.T) *observer.ObservedLogs {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger.SetGlobal(zap.New(core))
+	t.Cleanup(func() { logger.SetGlobal(zap.NewNop()) })
+	return logs
+}
+
+func setupBodyReadRouter(limits BodyReadLimits, received *string) *gin.Engine {
+	router := setupTestRouter()
+	router.Use(BodyReadTimeout(limits))
+	router.POST("/upload", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		*received = string(body)
+		c.Status(http.StatusCreated)
+	})
+	return router
+}
+
+func postReader(router *gin.Engine, body io.Reader) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/upload", body)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestBodyReadTimeout_SlowBodyGets408(t *testing.T) {
+	logs := observeBodyLogs(t)
+	var received string
+	router := setupBodyReadRouter(BodyReadLimits{Timeout: 30 * time.Millisecond}, &received)
+
+	body := &slowBody{chunks: []string{"a", "b", "c", "d", "e"}, delay: 15 * time.Millisecond}
+	w := postReader(router, body)
+
+	assert.Equal(t, http.StatusRequestTimeout, w.Code)
+	assert.Contains(t, w.Body.String(), "REQUEST_TIMEOUT")
+	assert.Equal(t, "close", w.Header().Get("Connection"))
+	assert.Empty(t, received, "the handler never runs")
+
+	entries := logs.FilterMessage("Request body read timed out").All()
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+		assert.Equal(t, "/upload", entries[0].ContextMap()["path"])
+	}
+}
+
+func TestBodyReadTimeout_LogsSlowButCompleteBody(t *testing.T) {
+	logs := observeBodyLogs(t)
+	var received string
+	router := setupBodyReadRouter(BodyReadLimits{Timeout: time.Second, SlowThreshold: 10 * time.Millisecond}, &received)
+
+	w := postReader(router, &slowBody{chunks: []string{"ab", "cd"}, delay: 10 * time.Millisecond})
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "abcd", received)
+	entries := logs.FilterMessage("Slow request body").All()
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, int64(4), entries[0].ContextMap()["bytes"])
+	}
+}
+
+func TestBodyReadTimeout_FastBodyPassesQuietly(t *testing.T) {
+	logs := observeBodyLogs(t)
+	var received string
+	router := setupBodyReadRouter(BodyReadLimits{Timeout: time.Second, SlowThreshold: time.Second}, &received)
+
+	w := postReader(router, strings.NewReader(`{"title":"Renew passport"}`))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"title":"Renew passport"}`, received)
+	assert.Zero(t, logs.Len())
+}
+
+func TestBodyReadTimeout_OverMaxSize(t *testing.T) {
+	var received string
+	router := setupBodyReadRouter(BodyReadLimits{Timeout: time.Second, MaxSize: 4}, &received)
+
+	w := postReader(router, bytes.NewBufferString("too large"))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Empty(t, received)
+}
+
+func TestBodyReadTimeout_RecordsBodySizesPerRoute(t *testing.T) {
+	sizes := NewBodySizeMetrics()
+	router := setupTestRouter()
+	router.Use(BodyReadTimeout(BodyReadLimits{Timeout: time.Second, Sizes: sizes}))
+	router.POST("/tasks/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/upload", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	post := func(path, body string) {
+		req, _ := http.NewRequest("POST", path, strings.NewReader(body))
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	post("/tasks/1", `{"title":"Renew passport"}`)
+	post("/tasks/2", strings.Repeat("x", 2000))
+	post("/upload", strings.Repeat("y", 300))
+	post("/missing", "not routed")
+
+	snapshots := sizes.Snapshot()
+	if !assert.Len(t, snapshots, 2) {
+		return
+	}
+
+	tasks := snapshots[0]
+	assert.Equal(t, "POST /tasks/:id", tasks.Route)
+	assert.Equal(t, int64(2), tasks.Count)
+	assert.Equal(t, int64(26+2000), tasks.Sum)
+	assert.Equal(t, BodySizeBucket{LE: 256, Count: 1}, tasks.Buckets[0])
+	assert.Equal(t, BodySizeBucket{LE: 1 << 10, Count: 1}, tasks.Buckets[1])
+	assert.Equal(t, BodySizeBucket{LE: 4 << 10, Count: 2}, tasks.Buckets[2])
+
+	upload := snapshots[1]
+	assert.Equal(t, "POST /upload", upload.Route)
+	assert.Equal(t, int64(1), upload.Count)
+	assert.Equal(t, int64(300), upload.Sum)
+	assert.Equal(t, int64(0), upload.Buckets[0].Count)
+	assert.Equal(t, int64(1), upload.Buckets[1].Count)
+}
+
+func TestBodyReadTimeout_SkipsSizesOfRejectedBodies(t *testing.T) {
+	sizes := NewBodySizeMetrics()
+	var received string
+	router := setupBodyReadRouter(BodyReadLimits{Timeout: time.Second, MaxSize: 4, Sizes: sizes}, &received)
+
+	w := postReader(router, bytes.NewBufferString("too large"))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Empty(t, sizes.Snapshot())
+}
+
+func TestBodySizeMetrics_CountsBodiesAboveLastBucket(t *testing.T) {
+	sizes := NewBodySizeMetrics()
+
+	sizes.Observe("POST /import", 5<<20)
+
+	snapshot := sizes.Snapshot()[0]
+	assert.Equal(t, int64(1), snapshot.Count)
+	assert.Equal(t, int64(5<<20), snapshot.Sum)
+	for _, bucket := range snapshot.Buckets {
+		assert.Zero(t, bucket.Count, "le %d", bucket.LE)
+	}
+}
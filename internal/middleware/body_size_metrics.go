@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"sort"
+	"sync"
+)
+
+// BodySizeBuckets are the upper bounds, in bytes, of the request body size
+// histogram. Bodies larger than the last bound only show up in the count.
+var BodySizeBuckets = []int64{256, 1 << 10, 4 << 10, 16 << 10, 64 << 10, 256 << 10, 1 << 20, 4 << 20}
+
+// BodySizeMetrics is a histogram of request body bytes per route, fed by
+// BodyReadTimeout from the bytes it already buffered.
+type BodySizeMetrics struct {
+	mu     sync.Mutex
+	routes map[string]*bodySizeHistogram
+}
+
+type bodySizeHistogram struct {
+	buckets []int64
+	count   int64
+	sum     int64
+}
+
+// BodySizeBucket counts the bodies of at most LE bytes.
+type BodySizeBucket struct {
+	LE    int64 `json:"le"`
+	Count int64 `json:"count"`
+}
+
+// BodySizeSnapshot is one route's histogram. Buckets are cumulative, as in
+// Prometheus; Count includes bodies above the last bucket.
+type BodySizeSnapshot struct {
+	Route   string           `json:"route"`
+	Count   int64            `json:"count"`
+	Sum     int64            `json:"sum"`
+	Buckets []BodySizeBucket `json:"buckets"`
+}
+
+func NewBodySizeMetrics() *BodySizeMetrics {
+	return &BodySizeMetrics{routes: make(map[string]*bodySizeHistogram)}
+}
+
+// Observe records a body of size bytes for route. It is safe on a nil
+// receiver, which records nothing.
+func (m *BodySizeMetrics) Observe(route string, size int) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h, ok := m.routes[route]
+	if !ok {
+		h = &bodySizeHistogram{buckets: make([]int64, len(BodySizeBuckets))}
+		m.routes[route] = h
+	}
+	h.count++
+	h.sum += int64(size)
+	for i, le := range BodySizeBuckets {
+		if int64(size) <= le {
+			h.buckets[i]++
+		}
+	}
+}
+
+// Snapshot returns every route's histogram, ordered by route.
+func (m *BodySizeMetrics) Snapshot() []BodySizeSnapshot {
+	if m == nil {
+		return []BodySizeSnapshot{}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshots := make([]BodySizeSnapshot, 0, len(m.routes))
+	for route, h := range m.routes {
+		buckets := make([]BodySizeBucket, len(BodySizeBuckets))
+		for i, le := range BodySizeBuckets {
+			buckets[i] = BodySizeBucket{LE: le, Count: h.buckets[i]}
+		}
+		snapshots = append(snapshots, BodySizeSnapshot{Route: route, Count: h.count, Sum: h.sum, Buckets: buckets})
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Route < snapshots[j].Route })
+	return snapshots
+}
@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	apperrors "github.com/lumen/backend/pkg/errors"
+)
+
+// FeedTokenParam is the query parameter carrying a calendar feed token.
+// Calendar clients subscribe to a plain URL and can't send Authorization.
+const FeedTokenParam = "token"
+
+var errInvalidFeedToken = errors.New("invalid feed token")
+
+// FeedTokenVersions looks up the version of a user's calendar feed token.
+// Rotating a user's feed bumps it, revoking the URLs issued before.
+type FeedTokenVersions interface {
+	GetFeedTokenVersion(ctx context.Context, userID uuid.UUID) (int, error)
+}
+
+// FeedTokens signs and verifies per-user calendar feed tokens of the form
+// "<user_id>.<version>.<hmac>". A token is only accepted while its version is
+// the user's current one, so a user can revoke their own feed URLs; changing
+// the secret still revokes everyone's.
+type FeedTokens struct {
+	secret   []byte
+	versions FeedTokenVersions
+}
+
+func NewFeedTokens(secret string, versions FeedTokenVersions) *FeedTokens {
+	return &FeedTokens{secret: []byte(secret), versions: versions}
+}
+
+func (f *FeedTokens) Sign(userID uuid.UUID, version int) string {
+	return userID.String() + "." + strconv.Itoa(version) + "." + f.mac(userID, version)
+}
+
+// Verify checks the token's signature and returns the user and version it
+// was signed for. Whether the version is still current is up to the caller.
+func (f *FeedTokens) Verify(token string) (uuid.UUID, int, error) {
+	id, rest, ok := strings.Cut(token, ".")
+	if !ok {
+		return uuid.Nil, 0, errInvalidFeedToken
+	}
+	v, sig, ok := strings.Cut(rest, ".")
+	if !ok {
+		return uuid.Nil, 0, errInvalidFeedToken
+	}
+
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, 0, errInvalidFeedToken
+	}
+
+	version, err := strconv.Atoi(v)
+	if err != nil || version < 1 {
+		return uuid.Nil, 0, errInvalidFeedToken
+	}
+
+	if !hmac.Equal([]byte(sig), []byte(f.mac(userID, version))) {
+		return uuid.Nil, 0, errInvalidFeedToken
+	}
+
+	return userID, version, nil
+}
+
+func (f *FeedTokens) mac(userID uuid.UUID, version int) string {
+	h := hmac.New(sha256.New, f.secret)
+	h.Write([]byte("calendar-feed:" + userID.String() + ":" + strconv.Itoa(version)))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Authenticate resolves the feed token query parameter to a user ID,
+// rejecting tokens signed for a version the user has since rotated away.
+func (f *FeedTokens) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, version, err := f.Verify(c.Query(FeedTokenParam))
+		if err != nil {
+			rejectFeedToken(c)
+			return
+		}
+
+		current, err := f.versions.GetFeedTokenVersion(c.Request.Context(), userID)
+		if errors.Is(err, models.ErrNotFound) || (err == nil && current != version) {
+			rejectFeedToken(c)
+			return
+		}
+		if err != nil {
+			RespondError(c, apperrors.NewInternalServer(err))
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", userID)
+		c.Next()
+	}
+}
+
+func rejectFeedToken(c *gin.Context) {
+	appErr := apperrors.NewUnauthorized("invalid or missing feed token")
+	RespondError(c, appErr)
+	c.Abort()
+}
+
+// FeedCORS allows cross-origin reads of the calendar feed. With no origins
+// configured any origin may read it; credentials are never allowed since the
+// feed authenticates with its URL token. The feed is read-only, so preflights
+// advertise GET alone; register it for OPTIONS too so they are answered.
+func FeedCORS(allowedOrigins []string) gin.HandlerFunc {
+	config := cors.Config{
+		AllowOrigins: allowedOrigins,
+		AllowMethods: []string{"GET"},
+		AllowHeaders: []string{"Origin", "Accept"},
+		MaxAge:       12 * time.Hour,
+	}
+
+	if len(allowedOrigins) == 0 {
+		config.AllowOrigins = nil
+		config.AllowAllOrigins = true
+	}
+
+	return cors.New(config)
+}
+
+// ExceptPaths runs handler for every request except those whose path starts
+// with one of prefixes, e.g. to keep the global CORS policy off routes that
+// install their own.
+func ExceptPaths(handler gin.HandlerFunc, prefixes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(c.Request.URL.Path, prefix) {
+				c.Next()
+				return
+			}
+		}
+
+		handler(c)
+	}
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const feedPath = "/api/v1/calendar/feed.ics"
+
+// feedVersions holds each user's current feed token version; users it
+// doesn't know are on version 1.
+type feedVersions map[uuid.UUID]int
+
+func (v feedVersions) GetFeedTokenVersion(ctx context.Context, userID uuid.UUID) (int, error) {
+	if version, ok := v[userID]; ok {
+		return version, nil
+	}
+	return 1, nil
+}
+
+func setupFeedRouter(tokens *FeedTokens) *gin.Engine {
+	router := setupTestRouter()
+	router.Use(ExceptPaths(CORSWithMethodOverrides(cors.Config{
+		AllowOrigins:     []string{"https://app.lumen.test"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowCredentials: true,
+	}, ParseCORSMethods([]string{"/api/v1/calendar=GET", "/api/v1/calendar/feed-url/rotate=POST"})), feedPath))
+
+	feedCORS := FeedCORS(nil)
+	router.GET(feedPath, feedCORS, tokens.Authenticate(), func(c *gin.Context) {
+		userID, _ := GetUserID(c)
+		c.String(http.StatusOK, userID.String())
+	})
+	router.OPTIONS(feedPath, feedCORS)
+	router.GET("/api/v1/tasks", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	return router
+}
+
+func crossOriginGet(router *gin.Engine, path, origin string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", path, nil)
+	req.Header.Set("Origin", origin)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestFeedCORS_AllowsCrossOriginGet(t *testing.T) {
+	tokens := NewFeedTokens("feed-secret", feedVersions{})
+	userID := uuid.New()
+	router := setupFeedRouter(tokens)
+
+	w := crossOriginGet(router, feedPath+"?token="+tokens.Sign(userID, 1), "https://calendar.google.com")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, userID.String(), w.Body.String())
+}
+
+func TestFeedCORS_APIRoutesStillEnforceCORS(t *testing.T) {
+	router := setupFeedRouter(NewFeedTokens("feed-secret", feedVersions{}))
+
+	w := crossOriginGet(router, "/api/v1/tasks", "https://calendar.google.com")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = crossOriginGet(router, "/api/v1/tasks", "https://app.lumen.test")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "https://app.lumen.test", w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestFeedTokens_RejectsBadTokens(t *testing.T) {
+	tokens := NewFeedTokens("feed-secret", feedVersions{})
+	router := setupFeedRouter(tokens)
+	userID := uuid.New()
+
+	forged := NewFeedTokens("other-secret", feedVersions{}).Sign(userID, 1)
+	tampered := uuid.New().String() + tokens.Sign(userID, 1)[36:]
+	// Claiming another version without re-signing.
+	bumped := userID.String() + ".2" + tokens.Sign(userID, 1)[38:]
+
+	for name, token := range map[string]string{
+		"missing":  "",
+		"garbage":  "not-a-token",
+		"forged":   forged,
+		"tampered": tampered,
+		"bumped":   bumped,
+		"legacy":   userID.String() + "." + tokens.mac(userID, 1),
+	} {
+		w := crossOriginGet(router, feedPath+"?token="+token, "https://calendar.google.com")
+		assert.Equal(t, http.StatusUnauthorized, w.Code, name)
+	}
+}
+
+func TestFeedTokens_RotationRevokesOlderVersions(t *testing.T) {
+	userID, other := uuid.New(), uuid.New()
+	versions := feedVersions{userID: 1}
+	tokens := NewFeedTokens("feed-secret", versions)
+	router := setupFeedRouter(tokens)
+	old := tokens.Sign(userID, 1)
+
+	assert.Equal(t, http.StatusOK, crossOriginGet(router, feedPath+"?token="+old, "").Code)
+
+	versions[userID] = 2
+	assert.Equal(t, http.StatusUnauthorized, crossOriginGet(router, feedPath+"?token="+old, "").Code)
+	assert.Equal(t, http.StatusOK, crossOriginGet(router, feedPath+"?token="+tokens.Sign(userID, 2), "").Code)
+
+	// Other users' feeds are untouched.
+	assert.Equal(t, http.StatusOK, crossOriginGet(router, feedPath+"?token="+tokens.Sign(other, 1), "").Code)
+}
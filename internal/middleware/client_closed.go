@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// StatusClientClosedRequest is the nginx convention for a request the client
+// abandoned before a response was written. Nothing is sent back; it exists so
+// access logs and metrics can tell disconnects apart from server errors.
+const StatusClientClosedRequest = 499
+
+// ClientCanceled reports whether the client behind c has disconnected.
+func ClientCanceled(c *gin.Context) bool {
+	return c.Request.Context().Err() == context.Canceled
+}
+
+// ClientClosed logs requests whose client disconnected mid-request at level,
+// as a single "Client closed request" entry rather than an error. A request
+// abandoned before anything was written is given StatusClientClosedRequest.
+func ClientClosed(logger *zap.Logger, level zapcore.Level) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		if !ClientCanceled(c) {
+			return
+		}
+
+		if !c.Writer.Written() {
+			c.Status(StatusClientClosedRequest)
+		}
+
+		logger.Log(level, "Client closed request",
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", c.Writer.Status()),
+			zap.Duration("duration", time.Since(start)),
+			zap.String("request_id", c.GetString("request_id")),
+		)
+	}
+}
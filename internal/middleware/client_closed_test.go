@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func serveClientClosed(level zapcore.Level, handler gin.HandlerFunc, canceled bool) (*httptest.ResponseRecorder, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	router := setupTestRouter()
+	router.Use(ClientClosed(zap.New(core), level))
+	router.GET("/work", handler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if canceled {
+		cancel()
+	}
+
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/work", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w, logs
+}
+
+func TestClientClosed_LogsDisconnectAtConfiguredLevel(t *testing.T) {
+	w, logs := serveClientClosed(zapcore.WarnLevel, func(c *gin.Context) {}, true)
+
+	assert.Equal(t, StatusClientClosedRequest, w.Code)
+	entries := logs.FilterMessage("Client closed request").All()
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+		assert.Equal(t, int64(StatusClientClosedRequest), entries[0].ContextMap()["status"])
+	}
+	assert.Zero(t, logs.FilterLevelExact(zapcore.ErrorLevel).Len())
+}
+
+func TestClientClosed_KeepsStatusAlreadyWritten(t *testing.T) {
+	w, logs := serveClientClosed(zapcore.InfoLevel, func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}, true)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, logs.FilterMessage("Client closed request").Len())
+}
+
+func TestClientClosed_IgnoresConnectedClients(t *testing.T) {
+	w, logs := serveClientClosed(zapcore.InfoLevel, func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	}, false)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Zero(t, logs.Len())
+}
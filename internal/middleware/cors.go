@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -23,3 +25,66 @@ func CORS(allowedOrigins []string) gin.HandlerFunc {
 
 	return cors.New(config)
 }
+
+// CORSMethods narrows the methods advertised in preflight responses for paths
+// under Prefix, e.g. to keep read-only route groups GET-only.
+type CORSMethods struct {
+	Prefix  string
+	Methods []string
+}
+
+// ParseCORSMethods reads "prefix=METHOD|METHOD" entries, such as
+// "/api/v1/calendar=GET"; malformed entries are skipped.
+func ParseCORSMethods(entries []string) []CORSMethods {
+	var overrides []CORSMethods
+	for _, entry := range entries {
+		prefix, list, ok := strings.Cut(entry, "=")
+		prefix = strings.TrimSpace(prefix)
+		if !ok || !strings.HasPrefix(prefix, "/") {
+			continue
+		}
+
+		var methods []string
+		for _, method := range strings.Split(list, "|") {
+			if method = strings.ToUpper(strings.TrimSpace(method)); method != "" {
+				methods = append(methods, method)
+			}
+		}
+		if len(methods) == 0 {
+			continue
+		}
+
+		overrides = append(overrides, CORSMethods{Prefix: prefix, Methods: methods})
+	}
+	return overrides
+}
+
+// CORSWithMethodOverrides applies config to every request, except that paths
+// under an override's prefix advertise only the override's methods. The
+// longest matching prefix wins; origins, headers and credentials are shared.
+func CORSWithMethodOverrides(config cors.Config, overrides []CORSMethods) gin.HandlerFunc {
+	type route struct {
+		prefix  string
+		handler gin.HandlerFunc
+	}
+
+	routes := make([]route, 0, len(overrides))
+	for _, override := range overrides {
+		narrowed := config
+		narrowed.AllowMethods = override.Methods
+		routes = append(routes, route{prefix: override.Prefix, handler: cors.New(narrowed)})
+	}
+	sort.SliceStable(routes, func(i, j int) bool { return len(routes[i].prefix) > len(routes[j].prefix) })
+
+	fallback := cors.New(config)
+
+	return func(c *gin.Context) {
+		for _, r := range routes {
+			if strings.HasPrefix(c.Request.URL.Path, r.prefix) {
+				r.handler(c)
+				return
+			}
+		}
+		fallback(c)
+	}
+}
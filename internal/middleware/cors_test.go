@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func preflight(router http.Handler, path, origin, method string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("OPTIONS", path, nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", method)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORS_FeedPreflightOnlyAllowsGet(t *testing.T) {
+	router := setupFeedRouter(NewFeedTokens("feed-secret", feedVersions{}))
+
+	w := preflight(router, feedPath, "https://calendar.google.com", "GET")
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "GET", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORS_APIPreflightAllowsFullMethodSet(t *testing.T) {
+	router := setupFeedRouter(NewFeedTokens("feed-secret", feedVersions{}))
+
+	w := preflight(router, "/api/v1/tasks", "https://app.lumen.test", "DELETE")
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "GET,POST,PUT,PATCH,DELETE,OPTIONS", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "https://app.lumen.test", w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORS_OverridePrefixNarrowsMethods(t *testing.T) {
+	router := setupFeedRouter(NewFeedTokens("feed-secret", feedVersions{}))
+
+	w := preflight(router, "/api/v1/calendar/"+uuid.New().String(), "https://app.lumen.test", "GET")
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "GET", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "https://app.lumen.test", w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORS_LongerOverridePrefixAllowsRotate(t *testing.T) {
+	router := setupFeedRouter(NewFeedTokens("feed-secret", feedVersions{}))
+
+	w := preflight(router, "/api/v1/calendar/feed-url/rotate", "https://app.lumen.test", "POST")
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "POST", w.Header().Get("Access-Control-Allow-Methods"))
+}
+
+func TestParseCORSMethods(t *testing.T) {
+	overrides := ParseCORSMethods([]string{
+		"/api/v1/calendar=GET",
+		" /api/v1/exports = get | head ",
+		"no-slash=GET",
+		"/api/v1/empty=",
+		"/api/v1/missing",
+	})
+
+	assert.Equal(t, []CORSMethods{
+		{Prefix: "/api/v1/calendar", Methods: []string{"GET"}},
+		{Prefix: "/api/v1/exports", Methods: []string{"GET", "HEAD"}},
+	}, overrides)
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/lumen/backend/pkg/errors"
+	"github.com/lumen/backend/pkg/i18n"
+)
+
+// RespondError writes appErr in the language the client's Accept-Language
+// header prefers among i18n.Supported, falling back to English. It is how
+// every error response is written.
+func RespondError(c *gin.Context, appErr *apperrors.AppError) {
+	lang := i18n.Negotiate(c.GetHeader("Accept-Language"))
+	c.Header("Content-Language", lang)
+	c.Writer.Header().Add("Vary", "Accept-Language")
+	c.JSON(appErr.StatusCode, appErr.Localize(lang))
+}
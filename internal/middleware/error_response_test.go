@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/lumen/backend/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func getLocalizedError(acceptLanguage string) (*httptest.ResponseRecorder, apperrors.AppError) {
+	router := setupTestRouter()
+	router.GET("/missing", func(c *gin.Context) {
+		RespondError(c, apperrors.NewNotFound("habit"))
+	})
+
+	req, _ := http.NewRequest("GET", "/missing", nil)
+	if acceptLanguage != "" {
+		req.Header.Set("Accept-Language", acceptLanguage)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var body apperrors.AppError
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	return w, body
+}
+
+func TestRespondError_LocalizesForAcceptLanguage(t *testing.T) {
+	w, body := getLocalizedError("es-MX,es;q=0.9,en;q=0.8")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, apperrors.CodeNotFound, body.Code)
+	assert.Equal(t, "Recurso no encontrado", body.Message)
+	assert.Equal(t, "habit not found", body.Detail)
+	assert.Equal(t, "es", w.Header().Get("Content-Language"))
+	assert.Contains(t, w.Header().Values("Vary"), "Accept-Language")
+}
+
+func TestRespondError_FallsBackToEnglish(t *testing.T) {
+	for _, header := range []string{"", "fr-FR,fr;q=0.9", "en-GB", "xx;q=abc"} {
+		w, body := getLocalizedError(header)
+
+		assert.Equal(t, http.StatusNotFound, w.Code, header)
+		assert.Equal(t, "habit not found", body.Message, header)
+		assert.Empty(t, body.Detail, header)
+		assert.Equal(t, "en", w.Header().Get("Content-Language"), header)
+		assert.NotContains(t, w.Body.String(), `"detail"`, header)
+	}
+}
+
+func TestRespondError_LocalizesMiddlewareRejections(t *testing.T) {
+	router := setupTestRouter()
+	router.Use(RateLimit(1, 0))
+	router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	var w *httptest.ResponseRecorder
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest("GET", "/ping", nil)
+		req.Header.Set("Accept-Language", "es")
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+	}
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"RATE_LIMIT_EXCEEDED"`)
+	assert.Contains(t, w.Body.String(), "Demasiadas solicitudes")
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	apperrors "github.com/lumen/backend/pkg/errors"
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// FeatureOverrides looks up a user's feature flag overrides.
+type FeatureOverrides interface {
+	GetFeatureFlagOverrides(ctx context.Context, userID uuid.UUID) (map[string]bool, error)
+}
+
+// FeatureFlags resolves feature flags per user: a flag's global default from
+// config, unless the user has an override. Only globally defined flags exist;
+// an override cannot introduce a new one.
+type FeatureFlags struct {
+	defaults  map[string]bool
+	overrides FeatureOverrides
+}
+
+func NewFeatureFlags(defaults map[string]bool, overrides FeatureOverrides) *FeatureFlags {
+	return &FeatureFlags{defaults: defaults, overrides: overrides}
+}
+
+// Defined reports whether name is a globally defined flag.
+func (f *FeatureFlags) Defined(name string) bool {
+	_, ok := f.defaults[name]
+	return ok
+}
+
+// Resolve returns every flag's value for the user. When overrides cannot be
+// loaded it logs and falls back to the global defaults rather than failing
+// the request.
+func (f *FeatureFlags) Resolve(ctx context.Context, userID uuid.UUID) map[string]bool {
+	var overrides map[string]bool
+	if userID != uuid.Nil {
+		var err error
+		overrides, err = f.overrides.GetFeatureFlagOverrides(ctx, userID)
+		if err != nil {
+			logger.Warn("Failed to load feature flag overrides, using defaults", zap.Error(err), zap.String("user_id", userID.String()))
+		}
+	}
+	return models.ResolveFeatureFlags(f.defaults, overrides)
+}
+
+// Enabled reports whether the flag is on for the user. Undefined flags are off.
+func (f *FeatureFlags) Enabled(ctx context.Context, userID uuid.UUID, name string) bool {
+	return f.Resolve(ctx, userID)[name]
+}
+
+// Require hides a route behind a flag, answering 404 to users it is off for,
+// so beta endpoints can be opened to some users only. It must run after
+// authentication.
+func (f *FeatureFlags) Require(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := GetUserID(c)
+		if !f.Enabled(c.Request.Context(), userID, name) {
+			appErr := apperrors.NewNotFound("resource")
+			RespondError(c, appErr)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
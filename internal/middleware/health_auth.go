@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/lumen/backend/pkg/errors"
+)
+
+// RequireHealthToken guards operational endpoints such as /metrics. It lets
+// through requests bearing token as "Authorization: Bearer <token>" and
+// requests ServiceIdentity recognised. An empty token leaves the endpoints
+// public.
+func RequireHealthToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		if _, ok := GetServiceIdentity(c); ok {
+			c.Next()
+			return
+		}
+
+		presented, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			appErr := apperrors.NewUnauthorized("health token required")
+			RespondError(c, appErr)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lumen/backend/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupHealthRouter(token string) *gin.Engine {
+	router := setupTestRouter()
+	router.Use(ServiceIdentity(ParseServiceKeys([]string{"monitor:monitor-secret"})))
+
+	requireToken := RequireHealthToken(token)
+	router.GET("/healthz", api.Liveness)
+	router.GET("/health/details", requireToken, func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"database": "healthy"})
+	})
+	router.GET("/metrics", requireToken, func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"database": gin.H{}})
+	})
+	return router
+}
+
+func healthRequest(router *gin.Engine, path string, header, value string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	if header != "" {
+		req.Header.Set(header, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequireHealthToken_ProtectsDetailedEndpoints(t *testing.T) {
+	router := setupHealthRouter("health-secret")
+
+	for _, path := range []string{"/metrics", "/health/details"} {
+		w := healthRequest(router, path, "", "")
+		assert.Equal(t, http.StatusUnauthorized, w.Code, path)
+
+		w = healthRequest(router, path, "Authorization", "Bearer wrong-secret")
+		assert.Equal(t, http.StatusUnauthorized, w.Code, path)
+
+		w = healthRequest(router, path, "Authorization", "health-secret")
+		assert.Equal(t, http.StatusUnauthorized, w.Code, path)
+
+		w = healthRequest(router, path, "Authorization", "Bearer health-secret")
+		assert.Equal(t, http.StatusOK, w.Code, path)
+	}
+}
+
+func TestRequireHealthToken_AcceptsServiceKeys(t *testing.T) {
+	router := setupHealthRouter("health-secret")
+
+	w := healthRequest(router, "/metrics", ServiceKeyHeader, "monitor-secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = healthRequest(router, "/metrics", ServiceKeyHeader, "guessed-secret")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRequireHealthToken_LivenessStaysPublic(t *testing.T) {
+	router := setupHealthRouter("health-secret")
+
+	w := healthRequest(router, "/healthz", "", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status": "ok"}`, w.Body.String())
+}
+
+func TestRequireHealthToken_EmptyTokenLeavesEndpointsPublic(t *testing.T) {
+	router := setupHealthRouter("")
+
+	for _, path := range []string{"/healthz", "/metrics", "/health/details"} {
+		w := healthRequest(router, path, "", "")
+		assert.Equal(t, http.StatusOK, w.Code, path)
+	}
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/lumen/backend/pkg/errors"
+)
+
+// JSONDepthLimit rejects JSON request bodies nested deeper than maxDepth
+// with 400 before any handler binds them. Bodies are scanned byte by byte
+// without decoding, then restored for the handler. Requests with a non-JSON
+// content type (multipart uploads, forms) pass through untouched.
+func JSONDepthLimit(maxDepth int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody || !isJSONContent(c.ContentType()) {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			// Let the handler's binding surface the read error (e.g. size limits).
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+			c.Next()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		if jsonDepthExceeds(body, maxDepth) {
+			appErr := apperrors.NewBadRequest(fmt.Sprintf("request body exceeds maximum JSON nesting depth of %d", maxDepth))
+			RespondError(c, appErr)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// isJSONContent treats a missing content type as JSON, since
+// ShouldBindJSON decodes the body regardless of the header.
+func isJSONContent(contentType string) bool {
+	return contentType == "" || strings.Contains(contentType, "json")
+}
+
+// jsonDepthExceeds reports whether objects and arrays in data nest deeper
+// than maxDepth, ignoring brackets inside strings. It stops at the first
+// level over the limit.
+func jsonDepthExceeds(data []byte, maxDepth int) bool {
+	depth := 0
+	inString := false
+	escaped := false
+
+	for _, b := range data {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case b == '\\':
+				escaped = true
+			case b == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch b {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if depth > maxDepth {
+				return true
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func nestedJSON(depth int) string {
+	return strings.Repeat(`{"a":`, depth-1) + `{}` + strings.Repeat(`}`, depth-1)
+}
+
+func setupJSONDepthRouter(maxDepth int, reached *bool) *gin.Engine {
+	router := setupTestRouter()
+	router.Use(JSONDepthLimit(maxDepth))
+	router.POST("/api/v1/tasks", func(c *gin.Context) {
+		*reached = true
+		var body map[string]interface{}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.Status(http.StatusUnprocessableEntity)
+			return
+		}
+		c.Status(http.StatusCreated)
+	})
+	return router
+}
+
+func postBody(router *gin.Engine, contentType, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/api/v1/tasks", bytes.NewBufferString(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestJSONDepthLimit_AtLimitPasses(t *testing.T) {
+	reached := false
+	router := setupJSONDepthRouter(8, &reached)
+
+	w := postBody(router, "application/json", nestedJSON(8))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.True(t, reached, "handler still sees the restored body")
+}
+
+func TestJSONDepthLimit_BeyondLimitRejected(t *testing.T) {
+	for _, body := range []string{nestedJSON(9), strings.Repeat("[", 10000)} {
+		reached := false
+		router := setupJSONDepthRouter(8, &reached)
+
+		w := postBody(router, "application/json; charset=utf-8", body)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "maximum JSON nesting depth of 8")
+		assert.False(t, reached)
+	}
+}
+
+func TestJSONDepthLimit_MissingContentTypeStillChecked(t *testing.T) {
+	reached := false
+	router := setupJSONDepthRouter(2, &reached)
+
+	w := postBody(router, "", nestedJSON(3))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.False(t, reached)
+}
+
+func TestJSONDepthLimit_IgnoresBracketsInStrings(t *testing.T) {
+	reached := false
+	router := setupJSONDepthRouter(2, &reached)
+
+	w := postBody(router, "application/json", `{"title":"[[[{{{ \"quoted [\" }}}]]]","tags":["a"]}`)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestJSONDepthLimit_SkipsNonJSONBodies(t *testing.T) {
+	reached := false
+	router := setupJSONDepthRouter(2, &reached)
+
+	postBody(router, "text/csv", strings.Repeat("[", 100))
+
+	assert.True(t, reached)
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+const strictJSONKey = "strict_json"
+
+// JSONStrictness records whether handlers should reject JSON bodies with
+// fields the request type doesn't define. Lenient (the default) ignores them.
+func JSONStrictness(strict bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(strictJSONKey, strict)
+		c.Next()
+	}
+}
+
+// StrictJSON reports whether the request runs in strict JSON mode.
+func StrictJSON(c *gin.Context) bool {
+	return c.GetBool(strictJSONKey)
+}
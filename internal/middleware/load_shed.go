@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/lumen/backend/pkg/errors"
+)
+
+// loadShedRetryAfter is the Retry-After hint, in seconds, sent with shed
+// requests. In-flight requests usually drain well within it.
+const loadShedRetryAfter = "1"
+
+// ConcurrencyLimit sheds load by allowing at most maxInFlight requests to be
+// processed at once. Requests over the limit are rejected immediately with
+// 503 instead of queueing, so a backlog can't slow every request down. Wrap
+// it in ExceptPaths to keep health checks answering under overload.
+func ConcurrencyLimit(maxInFlight int) gin.HandlerFunc {
+	slots := make(chan struct{}, maxInFlight)
+
+	return func(c *gin.Context) {
+		select {
+		case slots <- struct{}{}:
+		default:
+			c.Header("Retry-After", loadShedRetryAfter)
+			RespondError(c, &apperrors.AppError{Code: apperrors.CodeServiceOverloaded, Message: "Server is busy, please try again shortly", StatusCode: http.StatusServiceUnavailable})
+			c.Abort()
+			return
+		}
+		defer func() { <-slots }()
+
+		c.Next()
+	}
+}
+
+// UserConcurrencyLimit allows each caller, keyed like RateLimit, at most
+// maxInFlight requests at once so no single caller can take all of the
+// capacity ConcurrencyLimit leaves. A caller over its share is rejected
+// immediately with 429 while others proceed. Service callers are exempt.
+// Mount it after Authenticate so callers are keyed by user, not IP.
+func UserConcurrencyLimit(maxInFlight int) gin.HandlerFunc {
+	var mu sync.Mutex
+	inFlight := make(map[string]int)
+
+	return func(c *gin.Context) {
+		if _, ok := GetServiceIdentity(c); ok {
+			c.Next()
+			return
+		}
+
+		key := rateLimitKey(c)
+
+		mu.Lock()
+		if inFlight[key] >= maxInFlight {
+			mu.Unlock()
+			c.Header("Retry-After", loadShedRetryAfter)
+			RespondError(c, &apperrors.AppError{Code: apperrors.CodeRateLimitExceeded, Message: "Too many requests in flight, please try again shortly", StatusCode: http.StatusTooManyRequests})
+			c.Abort()
+			return
+		}
+		inFlight[key]++
+		mu.Unlock()
+
+		defer func() {
+			mu.Lock()
+			if inFlight[key]--; inFlight[key] == 0 {
+				delete(inFlight, key)
+			}
+			mu.Unlock()
+		}()
+
+		c.Next()
+	}
+}
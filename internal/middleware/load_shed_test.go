@@ -0,0 +1,204 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingRouter holds /work requests until release is closed, signalling
+// started once per request that got past the limiter.
+func blockingRouter(limit int, started chan<- struct{}, release <-chan struct{}) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ExceptPaths(ConcurrencyLimit(limit), "/health"))
+	router.GET("/work", func(c *gin.Context) {
+		started <- struct{}{}
+		<-release
+		c.Status(http.StatusOK)
+	})
+	router.GET("/health", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestConcurrencyLimit_ShedsExcessRequests(t *testing.T) {
+	const limit, total = 3, 8
+	started := make(chan struct{}, total)
+	release := make(chan struct{})
+	router := blockingRouter(limit, started, release)
+
+	codes := make(chan *httptest.ResponseRecorder, total)
+	var wg sync.WaitGroup
+	serve := func() {
+		defer wg.Done()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/work", nil)
+		router.ServeHTTP(w, req)
+		codes <- w
+	}
+
+	// Fill every slot first so the rest are guaranteed to arrive over the limit.
+	wg.Add(limit)
+	for i := 0; i < limit; i++ {
+		go serve()
+	}
+	for i := 0; i < limit; i++ {
+		<-started
+	}
+
+	wg.Add(total - limit)
+	for i := 0; i < total-limit; i++ {
+		go serve()
+	}
+
+	var shed []*httptest.ResponseRecorder
+	for i := 0; i < total-limit; i++ {
+		shed = append(shed, <-codes)
+	}
+	close(release)
+	wg.Wait()
+	close(codes)
+
+	for _, w := range shed {
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "1", w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), "SERVICE_OVERLOADED")
+	}
+	for w := range codes {
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+}
+
+func TestConcurrencyLimit_ReleasesSlots(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	close(release)
+	router := blockingRouter(1, started, release)
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/work", nil)
+		router.ServeHTTP(w, req)
+		<-started
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+}
+
+func TestConcurrencyLimit_HealthExempt(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	router := blockingRouter(1, started, release)
+
+	go func() {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/work", nil)
+		router.ServeHTTP(w, req)
+	}()
+	<-started
+	defer close(release)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/health", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/work", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+// perUserRouter holds /work requests until release is closed, signalling
+// started with the user of each request that got past the limiter. The limit
+// sits behind the real Authenticate, as in production.
+func perUserRouter(limit int, started chan<- string, release <-chan struct{}) *gin.Engine {
+	router := setupTestRouter()
+	auth := NewAuthMiddleware(testJWTSecret, testIssuer, testAudience)
+	router.GET("/work", auth.Authenticate(), UserConcurrencyLimit(limit), func(c *gin.Context) {
+		userID, _ := GetUserID(c)
+		started <- userID.String()
+		<-release
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+// testUser is the user whose token serveAs sends for name.
+func testUser(name string) uuid.UUID {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(name))
+}
+
+// serveAs sends /work with a valid token for name. Every request comes from
+// the same client IP, so only the authenticated user tells callers apart.
+func serveAs(router *gin.Engine, name string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/work", nil)
+	req.Header.Set("Authorization", "Bearer "+signToken(testJWTSecret, supabaseClaims(testUser(name), nil)))
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestUserConcurrencyLimit_RejectsOneUsersExcessOnly(t *testing.T) {
+	const limit = 2
+	started := make(chan string, 2*limit)
+	release := make(chan struct{})
+	router := perUserRouter(limit, started, release)
+
+	var wg sync.WaitGroup
+	admitted := make(chan *httptest.ResponseRecorder, 2*limit)
+	wg.Add(limit)
+	for i := 0; i < limit; i++ {
+		go func() {
+			defer wg.Done()
+			admitted <- serveAs(router, "alice")
+		}()
+	}
+	for i := 0; i < limit; i++ {
+		<-started
+	}
+
+	// alice is at the limit: the next request is rejected at once.
+	w := serveAs(router, "alice")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "RATE_LIMIT_EXCEEDED")
+
+	// bob, from the same IP, still gets a separate set of slots.
+	wg.Add(limit)
+	for i := 0; i < limit; i++ {
+		go func() {
+			defer wg.Done()
+			admitted <- serveAs(router, "bob")
+		}()
+	}
+	for i := 0; i < limit; i++ {
+		assert.Equal(t, testUser("bob").String(), <-started)
+	}
+
+	close(release)
+	wg.Wait()
+	close(admitted)
+	for w := range admitted {
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+}
+
+func TestUserConcurrencyLimit_ReleasesSlots(t *testing.T) {
+	started := make(chan string, 1)
+	release := make(chan struct{})
+	close(release)
+	router := perUserRouter(1, started, release)
+
+	for i := 0; i < 3; i++ {
+		w := serveAs(router, "alice")
+		<-started
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+}
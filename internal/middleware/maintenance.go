@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/lumen/backend/pkg/errors"
+)
+
+// maintenanceRetryAfter is the Retry-After hint, in seconds, sent with writes
+// rejected during maintenance.
+const maintenanceRetryAfter = "60"
+
+// Maintenance is the API's read-only maintenance switch. It starts from
+// config and can be toggled at runtime without a restart.
+type Maintenance struct {
+	mu      sync.RWMutex
+	enabled bool
+	message string
+}
+
+func NewMaintenance(enabled bool, message string) *Maintenance {
+	return &Maintenance{enabled: enabled, message: message}
+}
+
+// Status reports whether maintenance is on and the message shown to clients.
+func (m *Maintenance) Status() (bool, string) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.enabled, m.message
+}
+
+// Set turns maintenance on or off. An empty message keeps the current one.
+func (m *Maintenance) Set(enabled bool, message string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.enabled = enabled
+	if message != "" {
+		m.message = message
+	}
+}
+
+// ReadOnly rejects write requests with 503 while maintenance is on. Reads
+// (GET, HEAD, OPTIONS) keep working. Wrap it in ExceptPaths to keep the admin
+// toggle reachable.
+func (m *Maintenance) ReadOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		enabled, message := m.Status()
+		if !enabled {
+			c.Next()
+			return
+		}
+
+		c.Header("Retry-After", maintenanceRetryAfter)
+		RespondError(c, &apperrors.AppError{Code: apperrors.CodeMaintenanceMode, Message: message, StatusCode: http.StatusServiceUnavailable})
+		c.Abort()
+	}
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupMaintenanceRouter(m *Maintenance) *gin.Engine {
+	router := setupTestRouter()
+	router.Use(ExceptPaths(m.ReadOnly(), "/admin"))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/tasks", ok)
+	router.HEAD("/tasks", ok)
+	router.POST("/tasks", ok)
+	router.PATCH("/tasks/1", ok)
+	router.DELETE("/tasks/1", ok)
+	router.PUT("/admin/maintenance", ok)
+	return router
+}
+
+func maintenanceRequest(router *gin.Engine, method, path string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMaintenance_BlocksWritesAllowsReads(t *testing.T) {
+	router := setupMaintenanceRouter(NewMaintenance(true, "Back soon"))
+
+	for _, method := range []string{"GET", "HEAD"} {
+		assert.Equal(t, http.StatusOK, maintenanceRequest(router, method, "/tasks").Code, method)
+	}
+
+	for _, tc := range []struct{ method, path string }{
+		{"POST", "/tasks"},
+		{"PATCH", "/tasks/1"},
+		{"DELETE", "/tasks/1"},
+	} {
+		w := maintenanceRequest(router, tc.method, tc.path)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code, tc.method)
+		assert.Contains(t, w.Body.String(), `"code":"MAINTENANCE_MODE"`)
+		assert.Contains(t, w.Body.String(), "Back soon")
+		assert.Equal(t, maintenanceRetryAfter, w.Header().Get("Retry-After"))
+	}
+
+	assert.Equal(t, http.StatusOK, maintenanceRequest(router, "PUT", "/admin/maintenance").Code)
+}
+
+func TestMaintenance_ToggleTakesEffectImmediately(t *testing.T) {
+	m := NewMaintenance(false, "Back soon")
+	router := setupMaintenanceRouter(m)
+
+	assert.Equal(t, http.StatusOK, maintenanceRequest(router, "POST", "/tasks").Code)
+
+	m.Set(true, "Migrating")
+	w := maintenanceRequest(router, "POST", "/tasks")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "Migrating")
+
+	m.Set(false, "")
+	assert.Equal(t, http.StatusOK, maintenanceRequest(router, "POST", "/tasks").Code)
+	_, message := m.Status()
+	assert.Equal(t, "Migrating", message)
+}
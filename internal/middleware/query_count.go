@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/lumen/backend/internal/querycount"
+)
+
+// QueryCountHeader carries the number of database queries a request issued.
+const QueryCountHeader = "X-DB-Query-Count"
+
+// QueryCount counts the database queries issued while handling each request
+// and logs the total at debug level, to help spot N+1 query patterns. With
+// exposeHeader set, the count is also returned in the X-DB-Query-Count header.
+// It is meant for development and is off by default.
+func QueryCount(logger *zap.Logger, exposeHeader bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, counter := querycount.NewContext(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+
+		if exposeHeader {
+			c.Writer = &queryCountWriter{ResponseWriter: c.Writer, counter: counter}
+		}
+
+		c.Next()
+
+		// Responses without a body are flushed by gin after the chain
+		// returns, so the header can still be set here.
+		if exposeHeader && !c.Writer.Written() {
+			c.Header(QueryCountHeader, strconv.FormatInt(counter.Count(), 10))
+		}
+
+		logger.Debug("Database queries",
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int64("queries", counter.Count()),
+			zap.String("request_id", c.GetString("request_id")),
+		)
+	}
+}
+
+// queryCountWriter sets the query count header just before the response
+// headers are sent, by which point the handler has issued its queries.
+type queryCountWriter struct {
+	gin.ResponseWriter
+	counter *querycount.Counter
+}
+
+func (w *queryCountWriter) setHeader() {
+	if !w.Written() {
+		w.Header().Set(QueryCountHeader, strconv.FormatInt(w.counter.Count(), 10))
+	}
+}
+
+func (w *queryCountWriter) WriteHeaderNow() {
+	w.setHeader()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *queryCountWriter) Write(data []byte) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *queryCountWriter) WriteString(s string) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.WriteString(s)
+}
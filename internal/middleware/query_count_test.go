@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/lumen/backend/internal/querycount"
+)
+
+// queryCountRouter serves /items, which issues one query per item the way an
+// N+1 listing would, and /empty, which issues two queries and has no body.
+func queryCountRouter(logger *zap.Logger, exposeHeader bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(QueryCount(logger, exposeHeader))
+	router.GET("/items", func(c *gin.Context) {
+		querycount.Inc(c.Request.Context())
+		items := []string{"a", "b", "c"}
+		for range items {
+			querycount.Inc(c.Request.Context())
+		}
+		c.JSON(http.StatusOK, gin.H{"data": items})
+	})
+	router.DELETE("/empty", func(c *gin.Context) {
+		querycount.Inc(c.Request.Context())
+		querycount.Inc(c.Request.Context())
+		c.Status(http.StatusNoContent)
+	})
+	return router
+}
+
+func TestQueryCount_HeaderReflectsHandlerQueries(t *testing.T) {
+	router := queryCountRouter(zap.NewNop(), true)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/items", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "4", w.Header().Get(QueryCountHeader))
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/empty", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "2", w.Header().Get(QueryCountHeader))
+}
+
+func TestQueryCount_CountsPerRequest(t *testing.T) {
+	router := queryCountRouter(zap.NewNop(), true)
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/items", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, "4", w.Header().Get(QueryCountHeader))
+	}
+}
+
+func TestQueryCount_LogsAtDebugWithoutHeader(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	router := queryCountRouter(zap.New(core), false)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/items", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Empty(t, w.Header().Get(QueryCountHeader))
+	entries := logs.FilterMessage("Database queries").All()
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, zapcore.DebugLevel, entries[0].Level)
+		assert.Equal(t, int64(4), entries[0].ContextMap()["queries"])
+	}
+}
@@ -1,105 +1,188 @@
 package middleware
 
 import (
+	"container/list"
+	"fmt"
 	"net/http"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	apperrors "github.com/lumen/backend/pkg/errors"
 )
 
+// RateLimitMemory bounds the memory the in-memory rate limiter holds on to.
+type RateLimitMemory struct {
+	// CleanupInterval is how often keys whose requests have all left the
+	// window are dropped. Zero cleans up once per window.
+	CleanupInterval time.Duration
+	// MaxKeys caps how many callers are tracked at once; past it the least
+	// recently seen caller is forgotten. Zero means no cap.
+	MaxKeys int
+}
+
 type rateLimiter struct {
-	requests map[string][]time.Time
+	// requests indexes the entries of order, which runs from the most to the
+	// least recently seen key.
+	requests map[string]*list.Element
+	order    *list.List
 	mu       sync.Mutex
 	limit    int
 	window   time.Duration
+	maxKeys  int
 }
 
-func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+type rateLimitEntry struct {
+	key      string
+	requests []time.Time
+}
+
+func newRateLimiter(limit int, window time.Duration, memory RateLimitMemory) *rateLimiter {
 	rl := &rateLimiter{
-		requests: make(map[string][]time.Time),
+		requests: make(map[string]*list.Element),
+		order:    list.New(),
 		limit:    limit,
 		window:   window,
+		maxKeys:  memory.MaxKeys,
 	}
 
-	go rl.cleanup()
+	interval := memory.CleanupInterval
+	if interval <= 0 {
+		interval = window
+	}
+	go rl.cleanup(interval)
 
 	return rl
 }
 
-func (rl *rateLimiter) allow(key string) bool {
+// allow records a request for key and reports how many requests the key has
+// made in the current window, this one included, and whether it is allowed.
+func (rl *rateLimiter) allow(key string) (int, bool) {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
 	now := time.Now()
 	windowStart := now.Add(-rl.window)
 
-	requests := rl.requests[key]
+	entry := rl.touch(key)
 	validRequests := []time.Time{}
 
-	for _, t := range requests {
+	for _, t := range entry.requests {
 		if t.After(windowStart) {
 			validRequests = append(validRequests, t)
 		}
 	}
 
 	if len(validRequests) >= rl.limit {
-		rl.requests[key] = validRequests
-		return false
+		entry.requests = validRequests
+		return len(validRequests), false
 	}
 
 	validRequests = append(validRequests, now)
-	rl.requests[key] = validRequests
+	entry.requests = validRequests
 
-	return true
+	return len(validRequests), true
 }
 
-func (rl *rateLimiter) cleanup() {
-	ticker := time.NewTicker(rl.window)
+// touch returns key's entry, marked as the most recently seen, adding it if
+// needed and evicting the least recently seen keys past maxKeys.
+func (rl *rateLimiter) touch(key string) *rateLimitEntry {
+	if elem, ok := rl.requests[key]; ok {
+		rl.order.MoveToFront(elem)
+		return elem.Value.(*rateLimitEntry)
+	}
+
+	entry := &rateLimitEntry{key: key}
+	rl.requests[key] = rl.order.PushFront(entry)
+
+	for rl.maxKeys > 0 && rl.order.Len() > rl.maxKeys {
+		oldest := rl.order.Back()
+		rl.order.Remove(oldest)
+		delete(rl.requests, oldest.Value.(*rateLimitEntry).key)
+	}
+
+	return entry
+}
+
+func (rl *rateLimiter) cleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for range ticker.C {
-		rl.mu.Lock()
-		now := time.Now()
-		windowStart := now.Add(-rl.window)
-
-		for key, requests := range rl.requests {
-			validRequests := []time.Time{}
-			for _, t := range requests {
-				if t.After(windowStart) {
-					validRequests = append(validRequests, t)
-				}
-			}
+		rl.flush(time.Now())
+	}
+}
 
-			if len(validRequests) == 0 {
-				delete(rl.requests, key)
-			} else {
-				rl.requests[key] = validRequests
+// flush drops requests that have left the window as of now, and keys left
+// with none.
+func (rl *rateLimiter) flush(now time.Time) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	windowStart := now.Add(-rl.window)
+
+	for key, elem := range rl.requests {
+		entry := elem.Value.(*rateLimitEntry)
+		validRequests := []time.Time{}
+		for _, t := range entry.requests {
+			if t.After(windowStart) {
+				validRequests = append(validRequests, t)
 			}
 		}
-		rl.mu.Unlock()
+
+		if len(validRequests) == 0 {
+			rl.order.Remove(elem)
+			delete(rl.requests, key)
+		} else {
+			entry.requests = validRequests
+		}
 	}
 }
 
-func RateLimit(requestsPerMinute int) gin.HandlerFunc {
-	limiter := newRateLimiter(requestsPerMinute, time.Minute)
+// RateLimitWarningHeader is set on allowed responses once a caller has used
+// the warning share of its limit for the current window.
+const RateLimitWarningHeader = "X-RateLimit-Warning"
 
-	return func(c *gin.Context) {
-		key := c.ClientIP()
+// RateLimit limits each caller to requestsPerMinute requests. Once a caller
+// has used warnPercent of the limit, allowed responses carry
+// RateLimitWarningHeader; a warnPercent of 0 disables the warning.
+func RateLimit(requestsPerMinute, warnPercent int) gin.HandlerFunc {
+	return BoundedRateLimit(requestsPerMinute, warnPercent, RateLimitMemory{})
+}
+
+// BoundedRateLimit is RateLimit with the limiter's memory bounded by memory.
+func BoundedRateLimit(requestsPerMinute, warnPercent int, memory RateLimitMemory) gin.HandlerFunc {
+	limiter := newRateLimiter(requestsPerMinute, time.Minute, memory)
+	return rateLimitHandler(requestsPerMinute, warnPercent, limiter.allow)
+}
 
-		if userID, exists := c.Get("user_id"); exists {
-			key = userID.(string)
+func rateLimitHandler(limit, warnPercent int, allow func(key string) (int, bool)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := GetServiceIdentity(c); ok {
+			c.Next()
+			return
 		}
 
-		if !limiter.allow(key) {
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"code":    "RATE_LIMIT_EXCEEDED",
-				"message": "Too many requests, please try again later",
-			})
+		used, ok := allow(rateLimitKey(c))
+		if !ok {
+			RespondError(c, &apperrors.AppError{Code: apperrors.CodeRateLimitExceeded, Message: "Too many requests, please try again later", StatusCode: http.StatusTooManyRequests})
 			c.Abort()
 			return
 		}
 
+		if warnPercent > 0 && used*100 >= warnPercent*limit {
+			c.Header(RateLimitWarningHeader, fmt.Sprintf("%d of %d requests used in the current window", used, limit))
+		}
+
 		c.Next()
 	}
 }
+
+// rateLimitKey identifies the caller: the authenticated user when known,
+// otherwise the client IP.
+func rateLimitKey(c *gin.Context) string {
+	if userID, exists := c.Get("user_id"); exists {
+		return fmt.Sprint(userID)
+	}
+	return c.ClientIP()
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// RateLimitStore is a shared counter backend used to rate limit across
+// instances. Increment returns the number of hits recorded for key in the
+// current window.
+type RateLimitStore interface {
+	Ping(ctx context.Context) error
+	Increment(ctx context.Context, key string, window time.Duration) (int64, error)
+}
+
+const storeTimeout = 500 * time.Millisecond
+
+// failoverLimiter prefers the shared store and falls back to the in-process
+// limiter whenever the store is unreachable. A background loop probes the
+// store and switches back once it responds again, until its context is
+// cancelled.
+type failoverLimiter struct {
+	store    RateLimitStore
+	fallback *rateLimiter
+	limit    int
+	window   time.Duration
+	healthy  atomic.Bool
+}
+
+func newFailoverLimiter(ctx context.Context, store RateLimitStore, limit int, window, reconnectInterval time.Duration, memory RateLimitMemory) *failoverLimiter {
+	fl := &failoverLimiter{
+		store:    store,
+		fallback: newRateLimiter(limit, window, memory),
+		limit:    limit,
+		window:   window,
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, storeTimeout)
+	defer cancel()
+
+	if err := store.Ping(pingCtx); err != nil {
+		logger.Warn("Rate limit store unavailable at startup, running in degraded mode with in-memory limiter",
+			zap.Error(err),
+			zap.Duration("reconnect_interval", reconnectInterval),
+		)
+	} else {
+		fl.healthy.Store(true)
+	}
+
+	go fl.reconnect(ctx, reconnectInterval)
+
+	return fl
+}
+
+func (fl *failoverLimiter) allow(key string) (int, bool) {
+	if fl.healthy.Load() {
+		ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+		defer cancel()
+
+		bucket := fmt.Sprintf("ratelimit:%s:%d", key, time.Now().UnixNano()/int64(fl.window))
+		count, err := fl.store.Increment(ctx, bucket, fl.window)
+		if err == nil {
+			return int(count), count <= int64(fl.limit)
+		}
+
+		if fl.healthy.CompareAndSwap(true, false) {
+			logger.Warn("Rate limit store failed, falling back to in-memory limiter", zap.Error(err))
+		}
+	}
+
+	return fl.fallback.allow(key)
+}
+
+func (fl *failoverLimiter) reconnect(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if fl.healthy.Load() {
+			continue
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, storeTimeout)
+		err := fl.store.Ping(pingCtx)
+		cancel()
+
+		if err == nil {
+			fl.healthy.Store(true)
+			logger.Info("Rate limit store recovered, resuming distributed rate limiting")
+		}
+	}
+}
+
+// DistributedRateLimit rate limits against a shared store, degrading to an
+// in-memory limiter while the store is down instead of failing requests.
+// warnPercent behaves as in RateLimit, and memory bounds the fallback limiter
+// as in BoundedRateLimit. The store is probed every reconnectInterval, which
+// must be positive, until ctx is cancelled.
+func DistributedRateLimit(ctx context.Context, store RateLimitStore, limit, warnPercent int, window, reconnectInterval time.Duration, memory RateLimitMemory) gin.HandlerFunc {
+	limiter := newFailoverLimiter(ctx, store, limit, window, reconnectInterval, memory)
+	return rateLimitHandler(limit, warnPercent, limiter.allow)
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRateLimitStore is an in-memory RateLimitStore that can be taken down.
+type fakeRateLimitStore struct {
+	mu     sync.Mutex
+	counts map[string]int64
+	down   atomic.Bool
+	hits   atomic.Int64
+	pings  atomic.Int64
+}
+
+func newFakeRateLimitStore() *fakeRateLimitStore {
+	return &fakeRateLimitStore{counts: make(map[string]int64)}
+}
+
+func (s *fakeRateLimitStore) Ping(ctx context.Context) error {
+	s.pings.Add(1)
+	if s.down.Load() {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (s *fakeRateLimitStore) Increment(ctx context.Context, key string, window time.Duration) (int64, error) {
+	if s.down.Load() {
+		return 0, errors.New("connection refused")
+	}
+	s.hits.Add(1)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counts[key]++
+	return s.counts[key], nil
+}
+
+func setupDistributedRouter(store RateLimitStore, limit int) *gin.Engine {
+	router := setupTestRouter()
+	router.Use(DistributedRateLimit(context.Background(), store, limit, 0, time.Minute, 10*time.Millisecond, RateLimitMemory{}))
+	router.GET("/api/test", func(c *gin.Context) {
+		c.JSON(200, gin.H{"message": "success"})
+	})
+	return router
+}
+
+func hitRateLimited(router *gin.Engine, ip string) int {
+	req, _ := http.NewRequest("GET", "/api/test", nil)
+	req.RemoteAddr = ip + ":1234"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestDistributedRateLimit_UsesStoreWhenHealthy(t *testing.T) {
+	store := newFakeRateLimitStore()
+	router := setupDistributedRouter(store, 2)
+
+	assert.Equal(t, 200, hitRateLimited(router, "10.0.0.1"))
+	assert.Equal(t, 200, hitRateLimited(router, "10.0.0.1"))
+	assert.Equal(t, 429, hitRateLimited(router, "10.0.0.1"))
+	assert.Equal(t, int64(3), store.hits.Load())
+}
+
+func TestDistributedRateLimit_DegradedAtStartup(t *testing.T) {
+	store := newFakeRateLimitStore()
+	store.down.Store(true)
+
+	router := setupDistributedRouter(store, 2)
+
+	// The in-memory limiter still enforces the limit while the store is down.
+	assert.Equal(t, 200, hitRateLimited(router, "10.0.0.1"))
+	assert.Equal(t, 200, hitRateLimited(router, "10.0.0.1"))
+	assert.Equal(t, 429, hitRateLimited(router, "10.0.0.1"))
+	assert.Equal(t, int64(0), store.hits.Load())
+}
+
+func TestDistributedRateLimit_RecoversWhenStoreReturns(t *testing.T) {
+	store := newFakeRateLimitStore()
+	store.down.Store(true)
+
+	router := setupDistributedRouter(store, 5)
+	assert.Equal(t, 200, hitRateLimited(router, "10.0.0.1"))
+	assert.Equal(t, int64(0), store.hits.Load())
+
+	store.down.Store(false)
+
+	assert.Eventually(t, func() bool {
+		hitRateLimited(router, "10.0.0.2")
+		return store.hits.Load() > 0
+	}, time.Second, 20*time.Millisecond)
+}
+
+func TestDistributedRateLimit_FallsBackWhenStoreDrops(t *testing.T) {
+	store := newFakeRateLimitStore()
+	router := setupDistributedRouter(store, 5)
+
+	assert.Equal(t, 200, hitRateLimited(router, "10.0.0.1"))
+	assert.Equal(t, int64(1), store.hits.Load())
+
+	store.down.Store(true)
+	assert.Equal(t, 200, hitRateLimited(router, "10.0.0.1"))
+	assert.Equal(t, int64(1), store.hits.Load())
+}
+
+func TestNewFailoverLimiter_BoundsFallbackMemory(t *testing.T) {
+	store := newFakeRateLimitStore()
+	store.down.Store(true)
+	limiter := newFailoverLimiter(context.Background(), store, 5, time.Minute, time.Hour, RateLimitMemory{CleanupInterval: time.Hour, MaxKeys: 2})
+
+	for _, key := range []string{"a", "b", "c"} {
+		limiter.allow(key)
+	}
+
+	assert.Len(t, limiter.fallback.requests, 2)
+	assert.NotContains(t, limiter.fallback.requests, "a")
+}
+
+func TestNewFailoverLimiter_StopsReconnectingWhenCancelled(t *testing.T) {
+	store := newFakeRateLimitStore()
+	store.down.Store(true)
+	ctx, cancel := context.WithCancel(context.Background())
+	newFailoverLimiter(ctx, store, 5, time.Minute, 5*time.Millisecond, RateLimitMemory{CleanupInterval: time.Hour})
+
+	assert.Eventually(t, func() bool { return store.pings.Load() > 1 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	// Let a probe already under way finish before sampling.
+	time.Sleep(20 * time.Millisecond)
+	pings := store.pings.Load()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, pings, store.pings.Load(), "reconnect loop kept probing after cancel")
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type redisRateLimitStore struct {
+	client *redis.Client
+}
+
+// NewRedisRateLimitStore backs distributed rate limiting with fixed-window
+// counters in Redis.
+func NewRedisRateLimitStore(client *redis.Client) RateLimitStore {
+	return &redisRateLimitStore{client: client}
+}
+
+func (s *redisRateLimitStore) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
+
+func (s *redisRateLimitStore) Increment(ctx context.Context, key string, window time.Duration) (int64, error) {
+	pipe := s.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, window)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -238,3 +239,100 @@ func TestRateLimitMiddleware_WhitelistedEndpoints(t *testing.T) {
 		}
 	}
 }
+
+func rateLimitWarnings(handler gin.HandlerFunc, requests int) []string {
+	router := setupTestRouter()
+	router.Use(handler)
+	router.GET("/api/test", func(c *gin.Context) {
+		c.JSON(200, gin.H{"message": "success"})
+	})
+
+	warnings := make([]string, 0, requests)
+	for i := 0; i < requests; i++ {
+		req, _ := http.NewRequest("GET", "/api/test", nil)
+		req.RemoteAddr = "192.168.1.1:1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		warnings = append(warnings, w.Header().Get(RateLimitWarningHeader))
+	}
+	return warnings
+}
+
+func TestRateLimit_WarnsNearLimit(t *testing.T) {
+	warnings := rateLimitWarnings(RateLimit(10, 90), 11)
+
+	for i, warning := range warnings[:8] {
+		assert.Empty(t, warning, "request %d is well below the limit", i+1)
+	}
+	assert.Equal(t, "9 of 10 requests used in the current window", warnings[8])
+	assert.Equal(t, "10 of 10 requests used in the current window", warnings[9])
+	// The blocked request gets a 429 instead of a warning.
+	assert.Empty(t, warnings[10])
+}
+
+func TestRateLimit_WarningDisabled(t *testing.T) {
+	for _, warning := range rateLimitWarnings(RateLimit(10, 0), 10) {
+		assert.Empty(t, warning)
+	}
+}
+
+func TestDistributedRateLimit_WarnsNearLimit(t *testing.T) {
+	warnings := rateLimitWarnings(DistributedRateLimit(context.Background(), newFakeRateLimitStore(), 10, 80, time.Minute, time.Minute, RateLimitMemory{}), 10)
+
+	assert.Empty(t, warnings[6])
+	assert.Equal(t, "8 of 10 requests used in the current window", warnings[7])
+	assert.NotEmpty(t, warnings[9])
+}
+
+func TestRateLimiter_EvictsLeastRecentlySeenPastMaxKeys(t *testing.T) {
+	limiter := newRateLimiter(2, time.Minute, RateLimitMemory{CleanupInterval: time.Hour, MaxKeys: 2})
+
+	limiter.allow("active")
+	limiter.allow("idle")
+	limiter.allow("active")
+	used, ok := limiter.allow("newcomer")
+
+	assert.True(t, ok)
+	assert.Equal(t, 1, used)
+	assert.Len(t, limiter.requests, 2)
+	assert.NotContains(t, limiter.requests, "idle")
+
+	// The active caller keeps its count, so its limit still holds.
+	_, ok = limiter.allow("active")
+	assert.False(t, ok)
+}
+
+func TestRateLimiter_NoCapKeepsEveryKey(t *testing.T) {
+	limiter := newRateLimiter(2, time.Minute, RateLimitMemory{CleanupInterval: time.Hour})
+
+	for _, key := range []string{"a", "b", "c", "d"} {
+		limiter.allow(key)
+	}
+
+	assert.Len(t, limiter.requests, 4)
+}
+
+func TestRateLimiter_FlushDropsOnlyExpiredKeys(t *testing.T) {
+	limiter := newRateLimiter(5, time.Minute, RateLimitMemory{CleanupInterval: time.Hour, MaxKeys: 10})
+
+	limiter.allow("stale")
+	limiter.flush(time.Now().Add(2 * time.Minute))
+	assert.Empty(t, limiter.requests)
+	assert.Zero(t, limiter.order.Len())
+
+	limiter.allow("fresh")
+	limiter.flush(time.Now())
+	assert.Contains(t, limiter.requests, "fresh")
+	assert.Equal(t, 1, limiter.order.Len())
+}
+
+func TestRateLimiter_PeriodicCleanupDropsIdleKeys(t *testing.T) {
+	limiter := newRateLimiter(5, 20*time.Millisecond, RateLimitMemory{CleanupInterval: 5 * time.Millisecond})
+	limiter.allow("caller")
+
+	assert.Eventually(t, func() bool {
+		limiter.mu.Lock()
+		defer limiter.mu.Unlock()
+		return len(limiter.requests) == 0
+	}, time.Second, 5*time.Millisecond)
+}
@@ -1,24 +1,101 @@
 package middleware
 
 import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 const RequestIDHeader = "X-Request-ID"
 
-// RequestID returns a middleware that generates a unique request ID
-func RequestID() gin.HandlerFunc {
+// Request ID generation strategies, for requests that arrive without an ID.
+const (
+	// RequestIDUUIDv4 generates a random UUID.
+	RequestIDUUIDv4 = "uuid4"
+	// RequestIDUUIDv7 generates a time-ordered UUID, so IDs sort by arrival.
+	RequestIDUUIDv7 = "uuid7"
+	// RequestIDTrace reuses the trace ID of a W3C traceparent header, tying
+	// the request to the caller's trace; requests without one get a UUIDv4.
+	RequestIDTrace = "trace"
+)
+
+// RequestIDOptions configures RequestID. Header carries the ID in and out
+// (default X-Request-ID); Strategy picks how missing IDs are generated
+// (default uuid4).
+type RequestIDOptions struct {
+	Header   string
+	Strategy string
+}
+
+// ValidRequestIDStrategy reports whether strategy is one RequestID knows.
+func ValidRequestIDStrategy(strategy string) bool {
+	switch strategy {
+	case RequestIDUUIDv4, RequestIDUUIDv7, RequestIDTrace:
+		return true
+	}
+	return false
+}
+
+// incomingRequestID bounds the IDs honored from clients, so they can't
+// smuggle control characters or arbitrary payloads into logs.
+var incomingRequestID = regexp.MustCompile(`^[A-Za-z0-9._:/+=-]{1,128}$`)
+
+// traceparent matches a W3C traceparent header, capturing the trace ID.
+var traceparent = regexp.MustCompile(`^[0-9a-f]{2}-([0-9a-f]{32})-[0-9a-f]{16}-[0-9a-f]{2}$`)
+
+// RequestID returns a middleware that tags each request with an ID, stored
+// as "request_id" for logging and echoed in the response header. A
+// well-formed ID sent in the header is honored; otherwise one is generated.
+func RequestID(opts RequestIDOptions) gin.HandlerFunc {
+	header := opts.Header
+	if header == "" {
+		header = RequestIDHeader
+	}
+
 	return func(c *gin.Context) {
-		requestID := c.GetHeader(RequestIDHeader)
+		requestID := c.GetHeader(header)
 
-		if requestID == "" {
-			requestID = uuid.New().String()
+		if !incomingRequestID.MatchString(requestID) {
+			requestID = generateRequestID(c, opts.Strategy)
 		}
 
 		c.Set("request_id", requestID)
-		c.Header(RequestIDHeader, requestID)
+		c.Header(header, requestID)
 
 		c.Next()
 	}
 }
+
+func generateRequestID(c *gin.Context, strategy string) string {
+	switch strategy {
+	case RequestIDUUIDv7:
+		return newUUIDv7(time.Now()).String()
+	case RequestIDTrace:
+		if m := traceparent.FindStringSubmatch(c.GetHeader("traceparent")); m != nil && m[1] != "00000000000000000000000000000000" {
+			return m[1]
+		}
+	}
+	return uuid.New().String()
+}
+
+// newUUIDv7 builds an RFC 9562 version 7 UUID: a 48-bit millisecond Unix
+// timestamp followed by random bits.
+func newUUIDv7(now time.Time) uuid.UUID {
+	var id uuid.UUID
+	if _, err := rand.Read(id[6:]); err != nil {
+		panic(fmt.Sprintf("request id: reading random bytes: %v", err))
+	}
+
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(now.UnixMilli()))
+	copy(id[:6], ms[2:])
+
+	id[6] = id[6]&0x0f | 0x70 // version 7
+	id[8] = id[8]&0x3f | 0x80 // RFC 4122 variant
+	return id
+}
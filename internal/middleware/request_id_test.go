@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func requestIDFor(opts RequestIDOptions, headers map[string]string) (string, *httptest.ResponseRecorder) {
+	router := setupTestRouter()
+	router.Use(RequestID(opts))
+
+	var stored string
+	router.GET("/ping", func(c *gin.Context) {
+		stored = c.GetString("request_id")
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest("GET", "/ping", nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return stored, w
+}
+
+func TestRequestID_HonorsProvidedID(t *testing.T) {
+	for _, strategy := range []string{RequestIDUUIDv4, RequestIDUUIDv7, RequestIDTrace} {
+		id, w := requestIDFor(RequestIDOptions{Strategy: strategy}, map[string]string{RequestIDHeader: "client-abc.123"})
+
+		assert.Equal(t, "client-abc.123", id, strategy)
+		assert.Equal(t, "client-abc.123", w.Header().Get(RequestIDHeader), strategy)
+	}
+}
+
+func TestRequestID_CustomHeader(t *testing.T) {
+	id, w := requestIDFor(RequestIDOptions{Header: "X-Correlation-ID"}, map[string]string{
+		"X-Correlation-ID": "corr-42",
+		RequestIDHeader:    "ignored",
+	})
+
+	assert.Equal(t, "corr-42", id)
+	assert.Equal(t, "corr-42", w.Header().Get("X-Correlation-ID"))
+	assert.Empty(t, w.Header().Get(RequestIDHeader))
+}
+
+func TestRequestID_ReplacesMalformedID(t *testing.T) {
+	id, _ := requestIDFor(RequestIDOptions{}, map[string]string{RequestIDHeader: "bad id\twith spaces"})
+
+	parsed, err := uuid.Parse(id)
+	assert.NoError(t, err)
+	assert.Equal(t, uuid.Version(4), parsed.Version())
+
+	id, _ = requestIDFor(RequestIDOptions{}, map[string]string{RequestIDHeader: strings.Repeat("a", 129)})
+	assert.Len(t, id, 36)
+}
+
+func TestRequestID_GeneratesUUIDv4ByDefault(t *testing.T) {
+	id, _ := requestIDFor(RequestIDOptions{}, nil)
+
+	parsed, err := uuid.Parse(id)
+	assert.NoError(t, err)
+	assert.Equal(t, uuid.Version(4), parsed.Version())
+}
+
+func TestRequestID_GeneratesUUIDv7(t *testing.T) {
+	before := time.Now().UnixMilli()
+	id, _ := requestIDFor(RequestIDOptions{Strategy: RequestIDUUIDv7}, nil)
+
+	parsed, err := uuid.Parse(id)
+	assert.NoError(t, err)
+	assert.Equal(t, uuid.Version(7), parsed.Version())
+	assert.Equal(t, uuid.RFC4122, parsed.Variant())
+
+	ms := int64(parsed[0])<<40 | int64(parsed[1])<<32 | int64(parsed[2])<<24 | int64(parsed[3])<<16 | int64(parsed[4])<<8 | int64(parsed[5])
+	assert.GreaterOrEqual(t, ms, before)
+	assert.LessOrEqual(t, ms, time.Now().UnixMilli())
+}
+
+func TestRequestID_UUIDv7SortsByTime(t *testing.T) {
+	earlier := newUUIDv7(time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC))
+	later := newUUIDv7(time.Date(2026, 10, 16, 9, 0, 0, int(time.Millisecond), time.UTC))
+
+	assert.Less(t, earlier.String(), later.String())
+}
+
+func TestRequestID_DerivesFromTrace(t *testing.T) {
+	id, _ := requestIDFor(RequestIDOptions{Strategy: RequestIDTrace}, map[string]string{
+		"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	})
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", id)
+}
+
+func TestRequestID_TraceFallsBackWithoutTraceparent(t *testing.T) {
+	for _, header := range []string{"", "not-a-traceparent", "00-00000000000000000000000000000000-00f067aa0ba902b7-01"} {
+		id, _ := requestIDFor(RequestIDOptions{Strategy: RequestIDTrace}, map[string]string{"traceparent": header})
+
+		_, err := uuid.Parse(id)
+		assert.NoError(t, err, "traceparent %q", header)
+	}
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/lumen/backend/pkg/errors"
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const ServiceKeyHeader = "X-Service-Key"
+
+// ServiceKey is an API key issued to an internal service (scheduler, digest
+// job). It identifies the service, never a user.
+type ServiceKey struct {
+	Name string
+	Key  string
+}
+
+// ParseServiceKeys reads "name:key" entries; malformed entries are skipped.
+func ParseServiceKeys(entries []string) []ServiceKey {
+	var keys []ServiceKey
+	for _, entry := range entries {
+		name, key, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || name == "" || key == "" {
+			continue
+		}
+		keys = append(keys, ServiceKey{Name: name, Key: key})
+	}
+	return keys
+}
+
+// ServiceIdentity recognises requests carrying a configured service key and
+// marks them with a service identity. It does not touch user_id, so service
+// calls still go through user auth wherever a route requires it. Unknown keys
+// are ignored and the request is treated like any other.
+func ServiceIdentity(keys []ServiceKey) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		presented := c.GetHeader(ServiceKeyHeader)
+		if presented == "" {
+			c.Next()
+			return
+		}
+
+		for _, k := range keys {
+			if subtle.ConstantTimeCompare([]byte(presented), []byte(k.Key)) == 1 {
+				c.Set("service_identity", k.Name)
+				c.Next()
+				return
+			}
+		}
+
+		logger.Warn("Ignoring unknown service key", zap.String("client_ip", c.ClientIP()))
+		c.Next()
+	}
+}
+
+// GetServiceIdentity returns the calling service's name for service-keyed requests.
+func GetServiceIdentity(c *gin.Context) (string, bool) {
+	name := c.GetString("service_identity")
+	return name, name != ""
+}
+
+// RequireService only lets through requests that ServiceIdentity recognised,
+// for operational endpoints that no user may call.
+func RequireService() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := GetServiceIdentity(c); !ok {
+			appErr := apperrors.NewUnauthorized("service key required")
+			RespondError(c, appErr)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupServiceRouter() *gin.Engine {
+	router := setupTestRouter()
+	router.Use(ServiceIdentity(ParseServiceKeys([]string{"digest:digest-secret", "scheduler:sched-secret"})))
+	router.Use(RateLimit(2, 0))
+	router.GET("/api/test", func(c *gin.Context) {
+		service, _ := GetServiceIdentity(c)
+		_, hasUser := c.Get("user_id")
+		c.JSON(200, gin.H{"service": service, "has_user": hasUser})
+	})
+	return router
+}
+
+func serviceRequest(router *gin.Engine, key string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/api/test", nil)
+	req.RemoteAddr = "10.1.1.1:1234"
+	if key != "" {
+		req.Header.Set(ServiceKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestServiceIdentity_BypassesRateLimit(t *testing.T) {
+	router := setupServiceRouter()
+
+	for i := 0; i < 5; i++ {
+		w := serviceRequest(router, "digest-secret")
+		assert.Equal(t, 200, w.Code)
+		assert.Contains(t, w.Body.String(), `"service":"digest"`)
+		assert.Contains(t, w.Body.String(), `"has_user":false`)
+	}
+}
+
+func TestServiceIdentity_InvalidKeyIsRateLimited(t *testing.T) {
+	router := setupServiceRouter()
+
+	assert.Equal(t, 200, serviceRequest(router, "guessed-secret").Code)
+	assert.Equal(t, 200, serviceRequest(router, "guessed-secret").Code)
+
+	w := serviceRequest(router, "guessed-secret")
+	assert.Equal(t, 429, w.Code)
+}
+
+func TestParseServiceKeys_SkipsMalformed(t *testing.T) {
+	keys := ParseServiceKeys([]string{"digest:abc", "nokey", ":abc", "sched:", " worker:xyz "})
+
+	assert.Equal(t, []ServiceKey{{Name: "digest", Key: "abc"}, {Name: "worker", Key: "xyz"}}, keys)
+}
+
+func TestRequireService_RejectsUnknownCallers(t *testing.T) {
+	router := setupTestRouter()
+	router.Use(ServiceIdentity(ParseServiceKeys([]string{"ops:ops-secret"})))
+	router.GET("/api/test", RequireService(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	assert.Equal(t, http.StatusOK, serviceRequest(router, "ops-secret").Code)
+	assert.Equal(t, http.StatusUnauthorized, serviceRequest(router, "wrong").Code)
+	assert.Equal(t, http.StatusUnauthorized, serviceRequest(router, "").Code)
+}
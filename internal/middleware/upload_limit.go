@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/lumen/backend/pkg/errors"
+)
+
+// UploadLimits bounds a multipart upload route. MaxMemory is how much of the
+// form is held in memory before file parts spill to temp files; MaxSize caps
+// the whole request body.
+type UploadLimits struct {
+	MaxMemory int64
+	MaxSize   int64
+}
+
+// MultipartLimit parses the multipart form up front under the route's limits,
+// so handlers can use c.FormFile without falling back to the engine-wide
+// MaxMultipartMemory. Bodies over MaxSize are rejected with 413, and any temp
+// files are removed once the handler chain returns.
+func MultipartLimit(limits UploadLimits) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > limits.MaxSize {
+			abortTooLarge(c, limits.MaxSize)
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limits.MaxSize)
+
+		if err := c.Request.ParseMultipartForm(limits.MaxMemory); err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				abortTooLarge(c, limits.MaxSize)
+				return
+			}
+
+			appErr := apperrors.NewBadRequest("invalid multipart form")
+			RespondError(c, appErr)
+			c.Abort()
+			return
+		}
+		defer c.Request.MultipartForm.RemoveAll()
+
+		c.Next()
+	}
+}
+
+func abortTooLarge(c *gin.Context, maxSize int64) {
+	appErr := apperrors.NewPayloadTooLarge(fmt.Sprintf("upload exceeds maximum size of %d bytes", maxSize))
+	RespondError(c, appErr)
+	c.Abort()
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func multipartBody(t *testing.T, size int) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormFile("file", "upload.csv")
+	assert.NoError(t, err)
+	_, err = part.Write(bytes.Repeat([]byte("a"), size))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	return body, writer.FormDataContentType()
+}
+
+func setupUploadRouter(limits UploadLimits) *gin.Engine {
+	router := setupTestRouter()
+	router.POST("/upload", MultipartLimit(limits), func(c *gin.Context) {
+		file, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(200, gin.H{"size": file.Size})
+	})
+	return router
+}
+
+func TestMultipartLimit_UnderLimit(t *testing.T) {
+	router := setupUploadRouter(UploadLimits{MaxMemory: 1 << 10, MaxSize: 1 << 16})
+
+	body, contentType := multipartBody(t, 512)
+	req, _ := http.NewRequest("POST", "/upload", body)
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"size":512`)
+}
+
+func TestMultipartLimit_SpillsToDiskAboveMemoryLimit(t *testing.T) {
+	router := setupUploadRouter(UploadLimits{MaxMemory: 1 << 10, MaxSize: 1 << 16})
+
+	body, contentType := multipartBody(t, 8<<10)
+	req, _ := http.NewRequest("POST", "/upload", body)
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"size":8192`)
+}
+
+func TestMultipartLimit_OverLimit(t *testing.T) {
+	router := setupUploadRouter(UploadLimits{MaxMemory: 1 << 10, MaxSize: 4 << 10})
+
+	body, contentType := multipartBody(t, 8<<10)
+	req, _ := http.NewRequest("POST", "/upload", body)
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, 413, w.Code)
+	assert.Contains(t, w.Body.String(), "PAYLOAD_TOO_LARGE")
+}
+
+func TestMultipartLimit_OverLimitWithoutContentLength(t *testing.T) {
+	router := setupUploadRouter(UploadLimits{MaxMemory: 1 << 10, MaxSize: 4 << 10})
+
+	body, contentType := multipartBody(t, 8<<10)
+	req, _ := http.NewRequest("POST", "/upload", body)
+	req.ContentLength = -1
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, 413, w.Code)
+}
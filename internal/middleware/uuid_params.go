@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	apperrors "github.com/lumen/backend/pkg/errors"
+)
+
+const uuidParamKeyPrefix = "uuid_param:"
+
+// UUIDParams validates the named path params as UUIDs before the handler
+// runs, responding 400 on malformed input. Params absent from the matched
+// route are skipped, so it can be mounted on a whole route group. Handlers
+// read the parsed values with GetUUIDParam.
+func UUIDParams(names ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, name := range names {
+			raw := c.Param(name)
+			if raw == "" {
+				continue
+			}
+
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				appErr := apperrors.NewBadRequest(fmt.Sprintf("invalid %s: must be a UUID", name))
+				RespondError(c, appErr)
+				c.Abort()
+				return
+			}
+
+			c.Set(uuidParamKeyPrefix+name, id)
+		}
+
+		c.Next()
+	}
+}
+
+// GetUUIDParam returns a path param validated by UUIDParams. ok is false if
+// the route wasn't mounted behind UUIDParams for that name.
+func GetUUIDParam(c *gin.Context, name string) (uuid.UUID, bool) {
+	val, exists := c.Get(uuidParamKeyPrefix + name)
+	if !exists {
+		return uuid.Nil, false
+	}
+
+	id, ok := val.(uuid.UUID)
+	return id, ok
+}
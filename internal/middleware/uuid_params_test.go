@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupUUIDParamRouter(reached *bool) *gin.Engine {
+	router := setupTestRouter()
+	goals := router.Group("/goals", UUIDParams("id", "milestone_id"))
+	handler := func(c *gin.Context) {
+		*reached = true
+		id, _ := GetUUIDParam(c, "id")
+		milestoneID, _ := GetUUIDParam(c, "milestone_id")
+		c.JSON(http.StatusOK, gin.H{"id": id, "milestone_id": milestoneID})
+	}
+	goals.GET("", handler)
+	goals.GET("/:id", handler)
+	goals.POST("/:id/milestones/:milestone_id/complete", handler)
+	return router
+}
+
+func TestUUIDParams_StoresParsedIDs(t *testing.T) {
+	reached := false
+	router := setupUUIDParamRouter(&reached)
+	goalID, milestoneID := uuid.New(), uuid.New()
+
+	req, _ := http.NewRequest("POST", "/goals/"+goalID.String()+"/milestones/"+milestoneID.String()+"/complete", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, reached)
+	assert.Contains(t, w.Body.String(), goalID.String())
+	assert.Contains(t, w.Body.String(), milestoneID.String())
+}
+
+func TestUUIDParams_RejectsMalformedBeforeHandler(t *testing.T) {
+	paths := map[string]string{
+		"/goals/not-a-uuid": "invalid id: must be a UUID",
+		"/goals/" + uuid.NewString() + "/milestones/42/complete":  "invalid milestone_id: must be a UUID",
+		"/goals/123/milestones/" + uuid.NewString() + "/complete": "invalid id: must be a UUID",
+	}
+
+	for path, message := range paths {
+		reached := false
+		router := setupUUIDParamRouter(&reached)
+
+		method := "GET"
+		if path != "/goals/not-a-uuid" {
+			method = "POST"
+		}
+		req, _ := http.NewRequest(method, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+		assert.Contains(t, w.Body.String(), message, path)
+		assert.False(t, reached, path)
+	}
+}
+
+func TestUUIDParams_SkipsRoutesWithoutParam(t *testing.T) {
+	reached := false
+	router := setupUUIDParamRouter(&reached)
+
+	req, _ := http.NewRequest("GET", "/goals", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, reached)
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lumen/backend/internal/models"
+)
+
+const validationProfileKey = "validation_profile"
+
+// Validation records the validation profile handlers apply to the request.
+func Validation(profile models.ValidationProfile) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(validationProfileKey, profile)
+		c.Next()
+	}
+}
+
+// ValidationProfile returns the request's validation profile, lenient when
+// none was set.
+func ValidationProfile(c *gin.Context) models.ValidationProfile {
+	if profile, ok := c.Get(validationProfileKey); ok {
+		return profile.(models.ValidationProfile)
+	}
+	return models.LenientValidation
+}
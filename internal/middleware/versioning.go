@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const APIVersionHeader = "X-API-Version"
+
+// APIVersion describes a mounted API version and where it is in its lifecycle.
+type APIVersion struct {
+	Name       string
+	Deprecated bool
+	// Sunset is the date the version stops being served. Zero means no
+	// sunset has been announced yet.
+	Sunset time.Time
+	// Link optionally points clients at migration docs for the successor.
+	Link string
+}
+
+// Versioned tags every request with its API version and, for deprecated
+// versions, emits the Deprecation (RFC 9745) and Sunset (RFC 8594) headers.
+func Versioned(version APIVersion) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("api_version", version.Name)
+		c.Header(APIVersionHeader, version.Name)
+
+		if version.Deprecated {
+			c.Header("Deprecation", "true")
+			if !version.Sunset.IsZero() {
+				c.Header("Sunset", version.Sunset.UTC().Format(http.TimeFormat))
+			}
+			if version.Link != "" {
+				c.Header("Link", "<"+version.Link+">; rel=\"deprecation\"")
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// RegisterVersion mounts /api/<name> with the Versioned middleware and lets
+// register attach that version's handlers. Paths under an unregistered
+// version fall through to the router's 404.
+func RegisterVersion(router gin.IRouter, version APIVersion, register func(*gin.RouterGroup)) *gin.RouterGroup {
+	group := router.Group("/api/"+version.Name, Versioned(version))
+	register(group)
+	return group
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupVersionedRouter() *gin.Engine {
+	router := setupTestRouter()
+
+	sunset := time.Date(2026, time.June, 30, 0, 0, 0, 0, time.UTC)
+	versions := []APIVersion{
+		{Name: "v1", Deprecated: true, Sunset: sunset, Link: "https://docs.lumen.app/migrate-v2"},
+		{Name: "v2"},
+	}
+
+	for _, v := range versions {
+		RegisterVersion(router, v, func(rg *gin.RouterGroup) {
+			rg.GET("/ping", func(c *gin.Context) {
+				c.JSON(200, gin.H{"version": c.GetString("api_version")})
+			})
+		})
+	}
+
+	return router
+}
+
+func TestVersioning_DeprecatedVersionEmitsHeaders(t *testing.T) {
+	router := setupVersionedRouter()
+
+	req, _ := http.NewRequest("GET", "/api/v1/ping", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "true", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Tue, 30 Jun 2026 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Contains(t, w.Header().Get("Link"), "rel=\"deprecation\"")
+	assert.Equal(t, "v1", w.Header().Get(APIVersionHeader))
+	assert.Contains(t, w.Body.String(), "v1")
+}
+
+func TestVersioning_CurrentVersionHasNoDeprecationHeaders(t *testing.T) {
+	router := setupVersionedRouter()
+
+	req, _ := http.NewRequest("GET", "/api/v2/ping", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Empty(t, w.Header().Get("Deprecation"))
+	assert.Empty(t, w.Header().Get("Sunset"))
+	assert.Equal(t, "v2", w.Header().Get(APIVersionHeader))
+}
+
+func TestVersioning_UnknownVersionNotFound(t *testing.T) {
+	router := setupVersionedRouter()
+
+	req, _ := http.NewRequest("GET", "/api/v3/ping", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, 404, w.Code)
+}
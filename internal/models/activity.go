@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/pagination"
+)
+
+// Activity feed entry types.
+const (
+	ActivityTaskCompleted  = "task.completed"
+	ActivityHabitCompleted = "habit.completed"
+	ActivityDailyLogSaved  = "daily_log.saved"
+	ActivityGoalReached    = "goal.reached"
+)
+
+const (
+	// DefaultActivityPageSize is the feed page size when no limit is given.
+	DefaultActivityPageSize = 20
+	// MaxActivityPageSize caps the limit a client may request per feed page.
+	MaxActivityPageSize = 100
+)
+
+// ActivityEvent is one entry in the user's activity feed. ID identifies the
+// row the event was read from: the task, habit completion, daily log or goal.
+// SubjectID is what the event is about, e.g. the habit for a completion.
+type ActivityEvent struct {
+	ID         uuid.UUID `json:"id"`
+	Type       string    `json:"type"`
+	SubjectID  uuid.UUID `json:"subject_id"`
+	Title      string    `json:"title"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// ActivityPage selects one page of the feed, newest first. After resumes
+// after the last event of the previous page.
+type ActivityPage struct {
+	After *pagination.Cursor
+	Limit int
+}
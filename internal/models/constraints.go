@@ -0,0 +1,92 @@
+package models
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FieldConstraint is the structured form of a request field's binding tag,
+// for clients (settings, API schemas) that need the rules without parsing
+// validator syntax. Min and Max bound string length, item count or value,
+// as in the validator. Rules keeps any tag rule without a dedicated field,
+// e.g. "hexcolor"; Items holds the rules applied to each element after "dive".
+type FieldConstraint struct {
+	Required bool             `json:"required"`
+	Min      *float64         `json:"min,omitempty"`
+	Max      *float64         `json:"max,omitempty"`
+	Enum     []string         `json:"enum,omitempty"`
+	Rules    []string         `json:"rules,omitempty"`
+	Items    *FieldConstraint `json:"items,omitempty"`
+}
+
+// ExtractConstraints reads the binding tags of req, a request struct or a
+// pointer to one, and returns the constraints keyed by JSON field name.
+// Fields without a binding tag are left out.
+func ExtractConstraints(req interface{}) map[string]FieldConstraint {
+	typ := reflect.TypeOf(req)
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	constraints := make(map[string]FieldConstraint)
+	if typ.Kind() != reflect.Struct {
+		return constraints
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag, ok := field.Tag.Lookup("binding")
+		if !ok || tag == "" || tag == "-" || !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		constraints[name] = parseConstraint(strings.Split(tag, ","))
+	}
+
+	return constraints
+}
+
+func parseConstraint(rules []string) FieldConstraint {
+	var constraint FieldConstraint
+	for i, rule := range rules {
+		key, param, _ := strings.Cut(rule, "=")
+		switch key {
+		case "omitempty":
+		case "required":
+			constraint.Required = true
+		case "min":
+			constraint.Min = parseBound(param)
+		case "max":
+			constraint.Max = parseBound(param)
+		case "len":
+			constraint.Min = parseBound(param)
+			constraint.Max = parseBound(param)
+		case "oneof":
+			constraint.Enum = strings.Fields(param)
+		case "dive":
+			items := parseConstraint(rules[i+1:])
+			constraint.Items = &items
+			return constraint
+		default:
+			constraint.Rules = append(constraint.Rules, rule)
+		}
+	}
+	return constraint
+}
+
+func parseBound(param string) *float64 {
+	value, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return nil
+	}
+	return &value
+}
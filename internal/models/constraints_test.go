@@ -0,0 +1,54 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func bound(v float64) *float64 {
+	return &v
+}
+
+func TestExtractConstraints_TaskRequests(t *testing.T) {
+	create := ExtractConstraints(CreateTaskRequest{})
+
+	assert.Equal(t, map[string]FieldConstraint{
+		"title":       {Required: true, Min: bound(1), Max: bound(200)},
+		"description": {Max: bound(1000)},
+		"horizon":     {Required: true, Enum: []string{"now", "next", "later", "someday"}},
+		"priority":    {Required: true, Enum: []string{"low", "medium", "high", "urgent"}},
+		"tags":        {Items: &FieldConstraint{Max: bound(50)}},
+	}, create)
+
+	update := ExtractConstraints(&UpdateTaskRequest{})
+
+	assert.Equal(t, FieldConstraint{Enum: []string{"todo", "in_progress", "done", "archived"}}, update["status"])
+	assert.Equal(t, FieldConstraint{Min: bound(1), Max: bound(200)}, update["title"])
+	assert.Equal(t, FieldConstraint{Items: &FieldConstraint{Max: bound(50)}}, update["tags"])
+	assert.NotContains(t, update, "due_date")
+}
+
+func TestExtractConstraints_HabitRequests(t *testing.T) {
+	create := ExtractConstraints(&CreateHabitRequest{})
+
+	assert.Equal(t, map[string]FieldConstraint{
+		"name":          {Required: true, Min: bound(1), Max: bound(100)},
+		"color":         {Required: true, Rules: []string{"hexcolor"}},
+		"icon":          {Required: true, Min: bound(1), Max: bound(50)},
+		"frequency":     {Required: true, Enum: []string{"daily", "weekly", "monthly"}},
+		"target_count":  {Required: true, Min: bound(1), Max: bound(100)},
+		"target_amount": {Rules: []string{"gt=0"}},
+	}, create)
+
+	update := ExtractConstraints(UpdateHabitRequest{})
+
+	assert.Equal(t, FieldConstraint{Rules: []string{"hexcolor"}}, update["color"])
+	assert.Equal(t, FieldConstraint{Min: bound(1), Max: bound(100)}, update["target_count"])
+	assert.NotContains(t, update, "is_active")
+	assert.NotContains(t, update, "reminder_times")
+}
+
+func TestExtractConstraints_NonStruct(t *testing.T) {
+	assert.Empty(t, ExtractConstraints("not a request"))
+}
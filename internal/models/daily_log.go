@@ -1,23 +1,51 @@
 package models
 
 import (
+	"fmt"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/pagination"
 )
 
+// MaxDailyLogPageSize caps the limit a client may request per page.
+const MaxDailyLogPageSize = 366
+
+// DailyLogPage selects one page of a date-range listing. A zero Limit means
+// no limit; After resumes after the last log of the previous page.
+type DailyLogPage struct {
+	After *pagination.Cursor
+	Limit int
+}
+
+// Unrated is how a rating the user left out is stored and returned. Ratings
+// otherwise run from 1 to 5, so 0 never collides with a real one.
+const Unrated = 0
+
+// RatingOrUnrated returns the rating r points to, or Unrated when it was
+// omitted.
+func RatingOrUnrated(r *int) int {
+	if r == nil {
+		return Unrated
+	}
+	return *r
+}
+
 type DailyLog struct {
-	ID                 uuid.UUID `json:"id" db:"id"`
-	UserID             uuid.UUID `json:"user_id" db:"user_id"`
-	Date               time.Time `json:"date" db:"date"`
-	MorningRoutine     bool      `json:"morning_routine" db:"morning_routine"`
-	EveningRoutine     bool      `json:"evening_routine" db:"evening_routine"`
+	ID             uuid.UUID `json:"id" db:"id"`
+	UserID         uuid.UUID `json:"user_id" db:"user_id"`
+	Date           time.Time `json:"date" db:"date"`
+	MorningRoutine bool      `json:"morning_routine" db:"morning_routine"`
+	EveningRoutine bool      `json:"evening_routine" db:"evening_routine"`
+	// WaterIntake is stored in glasses; see WaterUnit for the API's units.
 	WaterIntake        int       `json:"water_intake" db:"water_intake"`
 	SleepHours         float64   `json:"sleep_hours" db:"sleep_hours"`
 	EnergyLevel        int       `json:"energy_level" db:"energy_level"`
 	MoodRating         int       `json:"mood_rating" db:"mood_rating"`
 	ProductivityRating int       `json:"productivity_rating" db:"productivity_rating"`
 	Notes              string    `json:"notes" db:"notes"`
+	IsRestDay          bool      `json:"is_rest_day" db:"is_rest_day"`
 	CreatedAt          time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt          time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -26,23 +54,25 @@ type CreateDailyLogRequest struct {
 	Date               time.Time `json:"date" binding:"required"`
 	MorningRoutine     bool      `json:"morning_routine"`
 	EveningRoutine     bool      `json:"evening_routine"`
-	WaterIntake        int       `json:"water_intake" binding:"min=0,max=20"`
+	WaterIntake        float64   `json:"water_intake" binding:"min=0"`
 	SleepHours         float64   `json:"sleep_hours" binding:"min=0,max=24"`
-	EnergyLevel        int       `json:"energy_level" binding:"min=1,max=5"`
-	MoodRating         int       `json:"mood_rating" binding:"min=1,max=5"`
-	ProductivityRating int       `json:"productivity_rating" binding:"min=1,max=5"`
+	EnergyLevel        *int      `json:"energy_level" binding:"omitempty,min=1,max=5"`
+	MoodRating         *int      `json:"mood_rating" binding:"omitempty,min=1,max=5"`
+	ProductivityRating *int      `json:"productivity_rating" binding:"omitempty,min=1,max=5"`
 	Notes              string    `json:"notes" binding:"max=1000"`
+	IsRestDay          bool      `json:"is_rest_day"`
 }
 
 type UpdateDailyLogRequest struct {
 	MorningRoutine     *bool    `json:"morning_routine"`
 	EveningRoutine     *bool    `json:"evening_routine"`
-	WaterIntake        *int     `json:"water_intake" binding:"omitempty,min=0,max=20"`
+	WaterIntake        *float64 `json:"water_intake" binding:"omitempty,min=0"`
 	SleepHours         *float64 `json:"sleep_hours" binding:"omitempty,min=0,max=24"`
 	EnergyLevel        *int     `json:"energy_level" binding:"omitempty,min=1,max=5"`
 	MoodRating         *int     `json:"mood_rating" binding:"omitempty,min=1,max=5"`
 	ProductivityRating *int     `json:"productivity_rating" binding:"omitempty,min=1,max=5"`
 	Notes              *string  `json:"notes" binding:"omitempty,max=1000"`
+	IsRestDay          *bool    `json:"is_rest_day"`
 }
 
 type DailyLogStats struct {
@@ -57,25 +87,114 @@ type DailyLogStats struct {
 }
 
 func (d *DailyLog) Validate() error {
-	if d.WaterIntake < 0 || d.WaterIntake > 20 {
-		return ErrInvalidWaterIntake
+	if d.WaterIntake < 0 || d.WaterIntake > MaxWaterIntake {
+		return fmt.Errorf("%w: must be between 0 and %d glasses", ErrInvalidWaterIntake, MaxWaterIntake)
 	}
 
 	if d.SleepHours < 0 || d.SleepHours > 24 {
 		return ErrInvalidSleepHours
 	}
 
-	if d.EnergyLevel < 1 || d.EnergyLevel > 5 {
-		return ErrInvalidRating
+	for _, rating := range []int{d.EnergyLevel, d.MoodRating, d.ProductivityRating} {
+		if rating != Unrated && (rating < 1 || rating > 5) {
+			return ErrInvalidRating
+		}
 	}
 
-	if d.MoodRating < 1 || d.MoodRating > 5 {
-		return ErrInvalidRating
+	return nil
+}
+
+// DailyLogInUnit is a daily log as the API returns it, with water intake in
+// the user's preferred unit instead of glasses.
+type DailyLogInUnit struct {
+	DailyLog
+	WaterIntake float64   `json:"water_intake"`
+	WaterUnit   WaterUnit `json:"water_unit"`
+}
+
+// InUnit returns d with its water intake converted to unit.
+func (d DailyLog) InUnit(unit WaterUnit) DailyLogInUnit {
+	return DailyLogInUnit{
+		DailyLog:    d,
+		WaterIntake: unit.FromGlasses(float64(d.WaterIntake)),
+		WaterUnit:   unit,
 	}
+}
 
-	if d.ProductivityRating < 1 || d.ProductivityRating > 5 {
-		return ErrInvalidRating
+// DailyLogsInUnit converts every log's water intake to unit.
+func DailyLogsInUnit(logs []DailyLog, unit WaterUnit) []DailyLogInUnit {
+	converted := make([]DailyLogInUnit, 0, len(logs))
+	for _, log := range logs {
+		converted = append(converted, log.InUnit(unit))
 	}
+	return converted
+}
 
-	return nil
+// IncompleteDailyLog is a saved log whose key ratings were left unset.
+// MissingFields holds their JSON names.
+type IncompleteDailyLog struct {
+	DailyLogInUnit
+	MissingFields []string `json:"missing_fields"`
+}
+
+// UnsetRatings returns the JSON names of d's key ratings still Unrated, which
+// a log only has when it was partially filled. Productivity isn't expected on
+// rest days.
+func (d *DailyLog) UnsetRatings() []string {
+	unset := []string{}
+	if d.EnergyLevel == Unrated {
+		unset = append(unset, "energy_level")
+	}
+	if d.MoodRating == Unrated {
+		unset = append(unset, "mood_rating")
+	}
+	if d.ProductivityRating == Unrated && !d.IsRestDay {
+		unset = append(unset, "productivity_rating")
+	}
+	return unset
+}
+
+// IncompleteDailyLogs keeps the logs with unset key ratings, with water
+// intake in unit.
+func IncompleteDailyLogs(logs []DailyLog, unit WaterUnit) []IncompleteDailyLog {
+	incomplete := []IncompleteDailyLog{}
+	for _, log := range logs {
+		if unset := log.UnsetRatings(); len(unset) > 0 {
+			incomplete = append(incomplete, IncompleteDailyLog{DailyLogInUnit: log.InUnit(unit), MissingFields: unset})
+		}
+	}
+	return incomplete
+}
+
+// DailyLogAverages summarises the logged days in a range. Days without a log
+// are not counted, and every average is null when no day has one. Unrated
+// ratings are left out of their average, and rest days are excluded from the
+// productivity average only. WaterIntake is in glasses
+// until converted with InWaterUnit.
+type DailyLogAverages struct {
+	Days               int       `json:"days"`
+	SleepHours         *float64  `json:"sleep_hours"`
+	WaterIntake        *float64  `json:"water_intake"`
+	WaterUnit          WaterUnit `json:"water_unit"`
+	MoodRating         *float64  `json:"mood_rating"`
+	EnergyLevel        *float64  `json:"energy_level"`
+	ProductivityRating *float64  `json:"productivity_rating"`
+}
+
+// Round rounds every average to two decimals.
+func (a *DailyLogAverages) Round() {
+	for _, v := range []*float64{a.SleepHours, a.WaterIntake, a.MoodRating, a.EnergyLevel, a.ProductivityRating} {
+		if v != nil {
+			*v = math.Round(*v*100) / 100
+		}
+	}
+}
+
+// InWaterUnit converts the water intake average from glasses to unit.
+func (a *DailyLogAverages) InWaterUnit(unit WaterUnit) {
+	if a.WaterIntake != nil {
+		converted := unit.FromGlasses(*a.WaterIntake)
+		a.WaterIntake = &converted
+	}
+	a.WaterUnit = unit
 }
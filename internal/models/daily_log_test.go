@@ -0,0 +1,93 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDailyLogAverages_Round(t *testing.T) {
+	sleep, water := 7.333333, 8.0
+	averages := DailyLogAverages{Days: 3, SleepHours: &sleep, WaterIntake: &water}
+
+	averages.Round()
+
+	assert.Equal(t, 7.33, *averages.SleepHours)
+	assert.Equal(t, 8.0, *averages.WaterIntake)
+	assert.Nil(t, averages.MoodRating)
+}
+
+func TestDailyLog_ValidateSkipsUnratedFields(t *testing.T) {
+	waterOnly := DailyLog{WaterIntake: 4}
+	assert.NoError(t, waterOnly.Validate())
+
+	partial := DailyLog{MoodRating: 4}
+	assert.NoError(t, partial.Validate())
+
+	partial.EnergyLevel = 6
+	assert.ErrorIs(t, partial.Validate(), ErrInvalidRating)
+}
+
+func TestRatingOrUnrated(t *testing.T) {
+	rating := 3
+	assert.Equal(t, 3, RatingOrUnrated(&rating))
+	assert.Equal(t, Unrated, RatingOrUnrated(nil))
+}
+
+func TestDailyLog_UnsetRatings(t *testing.T) {
+	complete := DailyLog{EnergyLevel: 3, MoodRating: 4, ProductivityRating: 2}
+	assert.Empty(t, complete.UnsetRatings())
+
+	partial := DailyLog{EnergyLevel: 3}
+	assert.Equal(t, []string{"mood_rating", "productivity_rating"}, partial.UnsetRatings())
+}
+
+func TestDailyLog_UnsetRatingsSkipsProductivityOnRestDays(t *testing.T) {
+	rest := DailyLog{EnergyLevel: 2, MoodRating: 4, IsRestDay: true}
+	assert.Empty(t, rest.UnsetRatings())
+
+	rest.MoodRating = 0
+	assert.Equal(t, []string{"mood_rating"}, rest.UnsetRatings())
+}
+
+func TestIncompleteDailyLogs_KeepsOnlyPartialLogs(t *testing.T) {
+	logs := []DailyLog{
+		{Notes: "complete", EnergyLevel: 3, MoodRating: 4, ProductivityRating: 2},
+		{Notes: "partial", EnergyLevel: 3, MoodRating: 4},
+		{Notes: "empty"},
+	}
+
+	incomplete := IncompleteDailyLogs(logs, WaterUnitGlasses)
+
+	if assert.Len(t, incomplete, 2) {
+		assert.Equal(t, "partial", incomplete[0].Notes)
+		assert.Equal(t, []string{"productivity_rating"}, incomplete[0].MissingFields)
+		assert.Equal(t, "empty", incomplete[1].Notes)
+		assert.Len(t, incomplete[1].MissingFields, 3)
+	}
+	assert.Empty(t, IncompleteDailyLogs(nil, WaterUnitGlasses))
+}
+
+func TestDailyLog_InUnitConvertsWaterIntake(t *testing.T) {
+	log := DailyLog{Notes: "hydrated", WaterIntake: 6}
+
+	liters := log.InUnit(WaterUnitLiters)
+	assert.Equal(t, 1.5, liters.WaterIntake)
+	assert.Equal(t, WaterUnitLiters, liters.WaterUnit)
+	assert.Equal(t, 6, liters.DailyLog.WaterIntake)
+	assert.Equal(t, "hydrated", liters.Notes)
+}
+
+func TestDailyLogAverages_InWaterUnit(t *testing.T) {
+	water := 7.5
+	averages := DailyLogAverages{Days: 2, WaterIntake: &water}
+
+	averages.InWaterUnit(WaterUnitOunces)
+
+	assert.Equal(t, 63.4, *averages.WaterIntake)
+	assert.Equal(t, WaterUnitOunces, averages.WaterUnit)
+
+	empty := DailyLogAverages{}
+	empty.InWaterUnit(WaterUnitLiters)
+	assert.Nil(t, empty.WaterIntake)
+}
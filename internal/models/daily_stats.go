@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DailyStatsData is the activity daily stats are computed from: logs in the
+// range, the user's habits, and the habit completions and tasks that fall in
+// the range.
+type DailyStatsData struct {
+	Logs        []DailyLog
+	Habits      []Habit
+	Completions []HabitCompletion
+	Tasks       []Task
+}
+
+// ComputeDailyStats returns one DailyLogStats per calendar day from start to
+// end inclusive, with day boundaries taken in loc. A habit counts towards a
+// day's total when it is active and was created by the end of that day, and
+// as completed when it has a completion during the day. Tasks are counted as
+// in CountTasksForDay. Ratings come from the day's log and are zero without one.
+func ComputeDailyStats(data DailyStatsData, start, end time.Time, loc *time.Location) []DailyLogStats {
+	logs := make(map[string]*DailyLog, len(data.Logs))
+	for i := range data.Logs {
+		logs[data.Logs[i].Date.Format(time.DateOnly)] = &data.Logs[i]
+	}
+
+	var stats []DailyLogStats
+	for day := StatsDate(start); !day.After(StatsDate(end)); day = day.AddDate(0, 0, 1) {
+		dayStart, dayEnd := DayBounds(day, loc)
+		within := func(t time.Time) bool { return !t.Before(dayStart) && t.Before(dayEnd) }
+
+		entry := DailyLogStats{Date: day}
+
+		counted := make(map[uuid.UUID]bool, len(data.Habits))
+		for i := range data.Habits {
+			habit := &data.Habits[i]
+			if habit.IsActive && habit.CreatedAt.Before(dayEnd) {
+				counted[habit.ID] = true
+				entry.HabitsTotal++
+			}
+		}
+
+		completed := make(map[uuid.UUID]bool)
+		for _, completion := range data.Completions {
+			if counted[completion.HabitID] && within(completion.CompletedAt) {
+				completed[completion.HabitID] = true
+			}
+		}
+		entry.HabitsCompleted = len(completed)
+
+		entry.TasksCompleted, entry.TasksTotal = CountTasksForDay(data.Tasks, day, loc)
+
+		if log, ok := logs[day.Format(time.DateOnly)]; ok {
+			entry.MoodRating = log.MoodRating
+			entry.EnergyLevel = log.EnergyLevel
+			entry.ProductivityRating = log.ProductivityRating
+		}
+
+		stats = append(stats, entry)
+	}
+
+	return stats
+}
+
+// StatsDate returns t's calendar date as midnight UTC, the form daily stats
+// and daily log dates are keyed by.
+func StatsDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
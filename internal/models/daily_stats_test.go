@@ -0,0 +1,51 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeDailyStats(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	read, stretch, retired := uuid.New(), uuid.New(), uuid.New()
+
+	data := DailyStatsData{
+		Logs: []DailyLog{
+			{Date: time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC), MoodRating: 4, EnergyLevel: 3, ProductivityRating: 5},
+		},
+		Habits: []Habit{
+			{ID: read, IsActive: true, CreatedAt: time.Date(2026, 10, 1, 12, 0, 0, 0, ny)},
+			// Created late on the 14th, so it first counts that day.
+			{ID: stretch, IsActive: true, CreatedAt: time.Date(2026, 10, 14, 22, 0, 0, 0, ny)},
+			{ID: retired, IsActive: false, CreatedAt: time.Date(2026, 10, 1, 12, 0, 0, 0, ny)},
+		},
+		Completions: []HabitCompletion{
+			{HabitID: read, CompletedAt: time.Date(2026, 10, 13, 8, 0, 0, 0, ny)},
+			{HabitID: read, CompletedAt: time.Date(2026, 10, 13, 20, 0, 0, 0, ny)},
+			// 02:00 UTC on the 15th is still the 14th in New York.
+			{HabitID: stretch, CompletedAt: time.Date(2026, 10, 15, 2, 0, 0, 0, time.UTC)},
+			{HabitID: retired, CompletedAt: time.Date(2026, 10, 14, 9, 0, 0, 0, ny)},
+		},
+		Tasks: []Task{
+			{DueDate: at(time.Date(2026, 10, 13, 17, 0, 0, 0, ny)), CompletedAt: at(time.Date(2026, 10, 13, 15, 0, 0, 0, ny))},
+			{DueDate: at(time.Date(2026, 10, 14, 9, 0, 0, 0, ny))},
+		},
+	}
+
+	stats := ComputeDailyStats(data, time.Date(2026, 10, 13, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC), ny)
+
+	assert.Equal(t, []DailyLogStats{
+		{Date: time.Date(2026, 10, 13, 0, 0, 0, 0, time.UTC), HabitsCompleted: 1, HabitsTotal: 1, TasksCompleted: 1, TasksTotal: 1},
+		{Date: time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC), HabitsCompleted: 1, HabitsTotal: 2, TasksTotal: 1, MoodRating: 4, EnergyLevel: 3, ProductivityRating: 5},
+	}, stats)
+}
+
+func TestComputeDailyStats_EmptyDays(t *testing.T) {
+	stats := ComputeDailyStats(DailyStatsData{}, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC), time.UTC)
+
+	assert.Len(t, stats, 3)
+	assert.Equal(t, DailyLogStats{Date: time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC)}, stats[2])
+}
@@ -3,21 +3,60 @@ package models
 import "errors"
 
 var (
-	ErrInvalidFrequency    = errors.New("invalid frequency: must be daily, weekly, or monthly")
-	ErrInvalidTargetCount  = errors.New("invalid target count: must be at least 1")
-	ErrInvalidHorizon      = errors.New("invalid horizon: must be now, next, later, or someday")
-	ErrInvalidPriority     = errors.New("invalid priority: must be low, medium, high, or urgent")
-	ErrInvalidStatus       = errors.New("invalid status: must be todo, in_progress, done, or archived")
-	ErrInvalidWaterIntake  = errors.New("invalid water intake: must be between 0 and 20")
-	ErrInvalidSleepHours   = errors.New("invalid sleep hours: must be between 0 and 24")
-	ErrInvalidRating       = errors.New("invalid rating: must be between 1 and 5")
-	ErrNotFound            = errors.New("resource not found")
-	ErrUnauthorized        = errors.New("unauthorized access")
-	ErrForbidden           = errors.New("forbidden: insufficient permissions")
-	ErrConflict            = errors.New("resource conflict")
-	ErrInternalServer      = errors.New("internal server error")
-	ErrBadRequest          = errors.New("bad request")
-	ErrValidationFailed    = errors.New("validation failed")
-	ErrDatabaseConnection  = errors.New("database connection error")
-	ErrDatabaseQuery       = errors.New("database query error")
+	ErrInvalidFrequency         = errors.New("invalid frequency: must be daily, weekly, or monthly")
+	ErrInvalidTargetCount       = errors.New("invalid target count: must be at least 1")
+	ErrInvalidTargetAmount      = errors.New("invalid target amount: must be greater than 0")
+	ErrInvalidHorizon           = errors.New("invalid horizon: must be now, next, later, or someday")
+	ErrInvalidPriority          = errors.New("invalid priority: must be low, medium, high, or urgent")
+	ErrInvalidStatus            = errors.New("invalid status: must be todo, in_progress, done, or archived")
+	ErrInvalidWaterIntake       = errors.New("invalid water intake")
+	ErrInvalidWaterUnit         = errors.New("invalid water unit: must be glasses, liters, or ounces")
+	ErrInvalidWeekStart         = errors.New("invalid week start: must be monday or sunday")
+	ErrInvalidSleepHours        = errors.New("invalid sleep hours: must be between 0 and 24")
+	ErrInvalidRating            = errors.New("invalid rating: must be between 1 and 5")
+	ErrInvalidSnooze            = errors.New("invalid snooze: provide either a positive duration (e.g. 24h, 3d) or an until date")
+	ErrSnoozeInPast             = errors.New("invalid snooze: new due date must be in the future")
+	ErrDueDateTooFar            = errors.New("invalid due date: too far in the future")
+	ErrDueDateInPast            = errors.New("invalid due date: must not be in the past")
+	ErrInvalidIcon              = errors.New("invalid icon: not one of the supported icons")
+	ErrPauseInPast              = errors.New("invalid pause: until must be in the future")
+	ErrInvalidReminderTime      = errors.New("invalid reminder time: must be HH:MM in 24-hour format")
+	ErrDuplicateReminder        = errors.New("invalid reminder times: each time may only be listed once")
+	ErrTooManyReminders         = errors.New("invalid reminder times: too many reminders")
+	ErrInvalidReminderShift     = errors.New("invalid offset: must be a non-zero whole number of minutes under 24h, such as +30m or -1h")
+	ErrInvalidHabitOrder        = errors.New("invalid order: habit_ids must list each of your habits exactly once")
+	ErrInvalidHabitSort         = errors.New("invalid sort_by: must be position, name, or created")
+	ErrMergeSameHabit           = errors.New("invalid merge: target_id must be a different habit")
+	ErrInvalidVelocityWindow    = errors.New("invalid window: must be a number of days such as 30d, at most 365d")
+	ErrInvalidVelocityPeriod    = errors.New("invalid period: must be day or week")
+	ErrInvalidAdherenceWindow   = errors.New("invalid window: must be a number of days such as 30d, at most 365d")
+	ErrInvalidImportFile        = errors.New("invalid import file")
+	ErrEmptyImport              = errors.New("invalid import file: no rows to import")
+	ErrTooManyImportRows        = errors.New("import file has too many rows")
+	ErrDeliveryNotFailed        = errors.New("only failed webhook deliveries can be retried")
+	ErrNoFreezeTokens           = errors.New("no streak freeze tokens left")
+	ErrNothingToFreeze          = errors.New("streak has no single missed period to bridge")
+	ErrUnknownFeatureFlag       = errors.New("unknown feature flag")
+	ErrInvalidValidationProfile = errors.New("invalid validation profile: must be lenient or strict")
+	ErrDailyLogExists           = errors.New("a daily log already exists for this date")
+	ErrDuplicateCompletion      = errors.New("habit was already completed moments ago")
+	ErrTooManyTags              = errors.New("invalid tags: too many tags")
+	ErrTagTooLong               = errors.New("invalid tags: tag too long")
+	ErrTaskBlocksItself         = errors.New("invalid blocked_by: a task cannot block itself")
+	ErrTooManyBlockers          = errors.New("invalid blocked_by: too many blockers")
+	ErrUnknownBlocker           = errors.New("invalid blocked_by: every blocker must be one of your tasks")
+	ErrDependencyCycle          = errors.New("invalid blocked_by: dependencies would form a cycle")
+	ErrTaskBlocked              = errors.New("task is blocked by unfinished tasks")
+	ErrDuplicateTaskIDs         = errors.New("invalid task_ids: each task may only be listed once")
+	ErrUnknownTask              = errors.New("invalid task_ids: every task must be one of your tasks")
+	ErrUnknownGoal              = errors.New("invalid goal_id: must be one of your goals")
+	ErrNotFound                 = errors.New("resource not found")
+	ErrUnauthorized             = errors.New("unauthorized access")
+	ErrForbidden                = errors.New("forbidden: insufficient permissions")
+	ErrConflict                 = errors.New("resource conflict")
+	ErrInternalServer           = errors.New("internal server error")
+	ErrBadRequest               = errors.New("bad request")
+	ErrValidationFailed         = errors.New("validation failed")
+	ErrDatabaseConnection       = errors.New("database connection error")
+	ErrDatabaseQuery            = errors.New("database query error")
 )
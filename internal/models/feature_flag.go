@@ -0,0 +1,47 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SetFeatureFlagRequest pins a feature flag on or off for one user.
+type SetFeatureFlagRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// ParseFeatureFlags reads global flag defaults from "name" or "name:bool"
+// entries; a bare name is enabled. Malformed entries are skipped.
+func ParseFeatureFlags(entries []string) map[string]bool {
+	flags := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		name, value, hasValue := strings.Cut(strings.TrimSpace(entry), ":")
+		if name == "" {
+			continue
+		}
+		enabled := true
+		if hasValue {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				continue
+			}
+			enabled = parsed
+		}
+		flags[name] = enabled
+	}
+	return flags
+}
+
+// ResolveFeatureFlags returns every globally defined flag's value for one
+// user: the user's override where there is one, otherwise the global default.
+// Overrides of flags that are no longer defined globally are ignored.
+func ResolveFeatureFlags(defaults, overrides map[string]bool) map[string]bool {
+	resolved := make(map[string]bool, len(defaults))
+	for name, enabled := range defaults {
+		if override, ok := overrides[name]; ok {
+			enabled = override
+		}
+		resolved[name] = enabled
+	}
+	return resolved
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFeatureFlags(t *testing.T) {
+	flags := ParseFeatureFlags([]string{"beta_insights", " new_calendar:false ", "dark_mode:true", "broken:maybe", ":true", ""})
+
+	assert.Equal(t, map[string]bool{
+		"beta_insights": true,
+		"new_calendar":  false,
+		"dark_mode":     true,
+	}, flags)
+}
+
+func TestResolveFeatureFlags_OverrideTakesPrecedence(t *testing.T) {
+	defaults := map[string]bool{"beta_insights": false, "new_calendar": true}
+	overrides := map[string]bool{"beta_insights": true, "new_calendar": false}
+
+	assert.Equal(t, map[string]bool{"beta_insights": true, "new_calendar": false}, ResolveFeatureFlags(defaults, overrides))
+}
+
+func TestResolveFeatureFlags_FallsBackToGlobalDefault(t *testing.T) {
+	defaults := map[string]bool{"beta_insights": false, "new_calendar": true}
+
+	assert.Equal(t, defaults, ResolveFeatureFlags(defaults, map[string]bool{}))
+	assert.Equal(t, defaults, ResolveFeatureFlags(defaults, nil))
+}
+
+func TestResolveFeatureFlags_IgnoresOverridesOfUndefinedFlags(t *testing.T) {
+	defaults := map[string]bool{"beta_insights": false}
+
+	resolved := ResolveFeatureFlags(defaults, map[string]bool{"retired_flag": true})
+
+	assert.Equal(t, map[string]bool{"beta_insights": false}, resolved)
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Goal struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	UserID       uuid.UUID  `json:"user_id" db:"user_id"`
+	Title        string     `json:"title" db:"title"`
+	Description  string     `json:"description" db:"description"`
+	Timeframe    string     `json:"timeframe" db:"timeframe"`
+	EndDate      *time.Time `json:"end_date" db:"end_date"`
+	WinCondition string     `json:"win_condition" db:"win_condition"`
+	Status       string     `json:"status" db:"status"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+type GoalMilestone struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	GoalID      uuid.UUID  `json:"goal_id" db:"goal_id"`
+	UserID      uuid.UUID  `json:"user_id" db:"user_id"`
+	Title       string     `json:"title" db:"title"`
+	Weight      int        `json:"weight" db:"weight"`
+	CompletedAt *time.Time `json:"completed_at" db:"completed_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+type CreateGoalMilestoneRequest struct {
+	Title  string `json:"title" binding:"required,min=1,max=200"`
+	Weight int    `json:"weight" binding:"omitempty,min=1,max=100"`
+}
+
+// GoalItems are the habits and tasks linked to a goal.
+type GoalItems struct {
+	Habits []Habit `json:"habits"`
+	Tasks  []Task  `json:"tasks"`
+}
+
+// HabitPeriodProgress is a linked habit's total in its current period and
+// the goal it must reach: completions against TargetCount, or for an
+// amount-based habit summed amounts against TargetAmount.
+type HabitPeriodProgress struct {
+	Total  float64
+	Target float64
+}
+
+// GoalLinkedProgress is the state of the tasks and habits attached to a goal.
+type GoalLinkedProgress struct {
+	TasksCompleted int
+	TasksTotal     int
+	Habits         []HabitPeriodProgress
+}
+
+type GoalProgress struct {
+	Percent             float64 `json:"percent"`
+	MilestonesCompleted int     `json:"milestones_completed"`
+	MilestonesTotal     int     `json:"milestones_total"`
+	TasksCompleted      int     `json:"tasks_completed"`
+	TasksTotal          int     `json:"tasks_total"`
+	HabitsOnTrack       int     `json:"habits_on_track"`
+	HabitsTotal         int     `json:"habits_total"`
+}
+
+// ComputeGoalProgress weighs each milestone by its Weight and each linked task
+// and habit as 1. Tasks count once done; habits count by how much of their
+// current-period target is met. Percent is rounded to one decimal place.
+func ComputeGoalProgress(milestones []GoalMilestone, linked GoalLinkedProgress) GoalProgress {
+	progress := GoalProgress{
+		MilestonesTotal: len(milestones),
+		TasksCompleted:  linked.TasksCompleted,
+		TasksTotal:      linked.TasksTotal,
+		HabitsTotal:     len(linked.Habits),
+	}
+
+	var earned, total float64
+
+	for _, m := range milestones {
+		weight := float64(m.Weight)
+		if weight < 1 {
+			weight = 1
+		}
+		total += weight
+		if m.CompletedAt != nil {
+			earned += weight
+			progress.MilestonesCompleted++
+		}
+	}
+
+	total += float64(linked.TasksTotal)
+	earned += float64(linked.TasksCompleted)
+
+	for _, h := range linked.Habits {
+		target := h.Target
+		if target <= 0 {
+			target = 1
+		}
+		ratio := math.Min(h.Total/target, 1)
+		if ratio == 1 {
+			progress.HabitsOnTrack++
+		}
+		total++
+		earned += ratio
+	}
+
+	if total > 0 {
+		progress.Percent = math.Round(earned/total*1000) / 10
+	}
+
+	return progress
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GoalForecastWindowDays is how far back goal progress is measured when
+// projecting a completion date.
+const GoalForecastWindowDays = 28
+
+// MinGoalForecastEvents is how many milestones or tasks must have been
+// completed within the window before a date is projected; one completion
+// says little about a pace.
+const MinGoalForecastEvents = 2
+
+// Goal forecast outcomes.
+const (
+	GoalForecastProjected        = "projected"
+	GoalForecastComplete         = "complete"
+	GoalForecastInsufficientData = "insufficient_data"
+)
+
+// GoalForecast projects when a goal reaches 100% at its recent pace.
+// ProjectedDate is the UTC day it gets there, set only when Status is
+// projected.
+type GoalForecast struct {
+	GoalID        uuid.UUID  `json:"goal_id"`
+	Status        string     `json:"status"`
+	Percent       float64    `json:"percent"`
+	PercentPerDay float64    `json:"percent_per_day"`
+	WindowDays    int        `json:"window_days"`
+	ProjectedDate *time.Time `json:"projected_date"`
+}
+
+// ForecastGoal extends the goal's progress over the last
+// GoalForecastWindowDays in a straight line:
+//
+//	percent_per_day = percent gained in the window / window days
+//	days left       = (100 - percent) / percent_per_day
+//
+// Progress is weighed as in ComputeGoalProgress. Gains come from milestones
+// and tasks completed in the window (taskCompletions are the completed_at of
+// the goal's done tasks); linked habits count toward where the goal stands
+// but, measuring only the current period, not toward its pace.
+func ForecastGoal(milestones []GoalMilestone, linked GoalLinkedProgress, taskCompletions []time.Time, now time.Time) GoalForecast {
+	progress := ComputeGoalProgress(milestones, linked)
+	forecast := GoalForecast{
+		Status:     GoalForecastInsufficientData,
+		Percent:    progress.Percent,
+		WindowDays: GoalForecastWindowDays,
+	}
+
+	if progress.Percent >= 100 {
+		forecast.Status = GoalForecastComplete
+		return forecast
+	}
+
+	total := float64(linked.TasksTotal + len(linked.Habits))
+	for _, m := range milestones {
+		total += float64(max(m.Weight, 1))
+	}
+	if total == 0 {
+		return forecast
+	}
+
+	since := now.AddDate(0, 0, -GoalForecastWindowDays)
+	var gained float64
+	events := 0
+	for _, m := range milestones {
+		if m.CompletedAt != nil && !m.CompletedAt.Before(since) {
+			gained += float64(max(m.Weight, 1))
+			events++
+		}
+	}
+	for _, completedAt := range taskCompletions {
+		if !completedAt.Before(since) {
+			gained++
+			events++
+		}
+	}
+
+	if events < MinGoalForecastEvents {
+		return forecast
+	}
+
+	perDay := gained / total * 100 / GoalForecastWindowDays
+	forecast.PercentPerDay = math.Round(perDay*100) / 100
+
+	daysLeft := math.Ceil((100 - progress.Percent) / perDay)
+	year, month, day := now.UTC().AddDate(0, 0, int(daysLeft)).Date()
+	projected := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	forecast.Status = GoalForecastProjected
+	forecast.ProjectedDate = &projected
+
+	return forecast
+}
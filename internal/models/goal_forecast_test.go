@@ -0,0 +1,83 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func daysAgo(now time.Time, days ...int) []time.Time {
+	times := make([]time.Time, len(days))
+	for i, d := range days {
+		times[i] = now.AddDate(0, 0, -d)
+	}
+	return times
+}
+
+func TestForecastGoal_SteadyProgressProjectsDate(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	// Three of seven tasks done, two of them in the last four weeks.
+	linked := GoalLinkedProgress{TasksCompleted: 3, TasksTotal: 7}
+
+	forecast := ForecastGoal(nil, linked, daysAgo(now, 20, 6), now)
+
+	assert.Equal(t, GoalForecastProjected, forecast.Status)
+	assert.Equal(t, 42.9, forecast.Percent)
+	assert.Equal(t, 1.02, forecast.PercentPerDay)
+	// 57.1% left at ~1.02% a day is 56 days.
+	if assert.NotNil(t, forecast.ProjectedDate) {
+		assert.Equal(t, time.Date(2026, 12, 11, 0, 0, 0, 0, time.UTC), *forecast.ProjectedDate)
+	}
+}
+
+func TestForecastGoal_CountsMilestonesByWeight(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	done := daysAgo(now, 14, 7)
+	milestones := []GoalMilestone{
+		{Weight: 3, CompletedAt: &done[0]},
+		{Weight: 1, CompletedAt: &done[1]},
+		{Weight: 4},
+	}
+
+	forecast := ForecastGoal(milestones, GoalLinkedProgress{}, nil, now)
+
+	// Half the weight in four weeks leaves four more.
+	assert.Equal(t, GoalForecastProjected, forecast.Status)
+	assert.Equal(t, 50.0, forecast.Percent)
+	if assert.NotNil(t, forecast.ProjectedDate) {
+		assert.Equal(t, time.Date(2026, 11, 13, 0, 0, 0, 0, time.UTC), *forecast.ProjectedDate)
+	}
+}
+
+func TestForecastGoal_FlatProgressIsInsufficientData(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	linked := GoalLinkedProgress{TasksCompleted: 4, TasksTotal: 10}
+
+	forecast := ForecastGoal(nil, linked, nil, now)
+
+	assert.Equal(t, GoalForecastInsufficientData, forecast.Status)
+	assert.Equal(t, 40.0, forecast.Percent)
+	assert.Zero(t, forecast.PercentPerDay)
+	assert.Nil(t, forecast.ProjectedDate)
+}
+
+func TestForecastGoal_SingleCompletionIsInsufficientData(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	linked := GoalLinkedProgress{TasksCompleted: 1, TasksTotal: 5}
+
+	forecast := ForecastGoal(nil, linked, daysAgo(now, 2), now)
+
+	assert.Equal(t, GoalForecastInsufficientData, forecast.Status)
+	assert.Nil(t, forecast.ProjectedDate)
+}
+
+func TestForecastGoal_CompleteGoal(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	linked := GoalLinkedProgress{TasksCompleted: 2, TasksTotal: 2}
+
+	forecast := ForecastGoal(nil, linked, daysAgo(now, 3, 1), now)
+
+	assert.Equal(t, GoalForecastComplete, forecast.Status)
+	assert.Nil(t, forecast.ProjectedDate)
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func milestone(weight int, done bool) GoalMilestone {
+	m := GoalMilestone{Weight: weight}
+	if done {
+		now := time.Now()
+		m.CompletedAt = &now
+	}
+	return m
+}
+
+func TestComputeGoalProgress_WeightedMilestones(t *testing.T) {
+	milestones := []GoalMilestone{
+		milestone(3, true),
+		milestone(1, false),
+		milestone(1, true),
+	}
+
+	progress := ComputeGoalProgress(milestones, GoalLinkedProgress{})
+	assert.Equal(t, 80.0, progress.Percent)
+	assert.Equal(t, 2, progress.MilestonesCompleted)
+	assert.Equal(t, 3, progress.MilestonesTotal)
+}
+
+func TestComputeGoalProgress_AllMilestonesComplete(t *testing.T) {
+	milestones := []GoalMilestone{
+		milestone(5, true),
+		milestone(2, true),
+		milestone(1, true),
+	}
+
+	progress := ComputeGoalProgress(milestones, GoalLinkedProgress{})
+	assert.Equal(t, 100.0, progress.Percent)
+}
+
+func TestComputeGoalProgress_IncludesLinkedTasksAndHabits(t *testing.T) {
+	milestones := []GoalMilestone{milestone(4, true), milestone(2, false)}
+	linked := GoalLinkedProgress{
+		TasksCompleted: 1,
+		TasksTotal:     2,
+		Habits: []HabitPeriodProgress{
+			{Total: 3, Target: 3},
+			{Total: 1, Target: 2},
+		},
+	}
+
+	// earned = 4 + 1 + 1 + 0.5 = 6.5, total = 6 + 2 + 2 = 10
+	progress := ComputeGoalProgress(milestones, linked)
+	assert.Equal(t, 65.0, progress.Percent)
+	assert.Equal(t, 1, progress.HabitsOnTrack)
+	assert.Equal(t, 2, progress.HabitsTotal)
+}
+
+func TestComputeGoalProgress_AmountHabitsCountByAmount(t *testing.T) {
+	linked := GoalLinkedProgress{
+		Habits: []HabitPeriodProgress{
+			{Total: 500, Target: 2000},
+			{Total: 2500, Target: 2000},
+		},
+	}
+
+	// earned = 0.25 + 1 = 1.25, total = 2
+	progress := ComputeGoalProgress(nil, linked)
+	assert.Equal(t, 62.5, progress.Percent)
+	assert.Equal(t, 1, progress.HabitsOnTrack)
+}
+
+func TestComputeGoalProgress_Empty(t *testing.T) {
+	assert.Equal(t, 0.0, ComputeGoalProgress(nil, GoalLinkedProgress{}).Percent)
+}
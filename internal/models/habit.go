@@ -1,9 +1,13 @@
 package models
 
 import (
+	"fmt"
+	"regexp"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/pagination"
 )
 
 type Habit struct {
@@ -14,26 +18,90 @@ type Habit struct {
 	Icon        string    `json:"icon" db:"icon" binding:"required"`
 	Frequency   string    `json:"frequency" db:"frequency" binding:"required"`
 	TargetCount int       `json:"target_count" db:"target_count" binding:"required,min=1"`
-	IsActive    bool      `json:"is_active" db:"is_active"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	// TargetAmount makes the habit amount-based: a period is met once the
+	// amounts of its completions add up to it, however many there are.
+	TargetAmount *float64 `json:"target_amount" db:"target_amount"`
+	IsActive     bool     `json:"is_active" db:"is_active"`
+	Position     int      `json:"position" db:"position"`
+	// ReminderTimes are local "HH:MM" times the client schedules reminders at.
+	ReminderTimes []string `json:"reminder_times" db:"reminder_times"`
+	// PausedFrom/PausedUntil bound the most recent vacation-mode window.
+	PausedFrom  *time.Time `json:"paused_from" db:"paused_from"`
+	PausedUntil *time.Time `json:"paused_until" db:"paused_until"`
+	// StreakResetAt is when the user last reset the habit's streak. Streaks
+	// only count completions from then on; earlier ones stay in the history.
+	StreakResetAt *time.Time `json:"streak_reset_at" db:"streak_reset_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+	// RestDays are dates the user logged as rest days. They are loaded
+	// alongside completions for progress figures and are not stored on the habit.
+	RestDays []time.Time `json:"-" db:"-"`
+	// WeekStart is the day the owner's weeks begin on, which bounds weekly
+	// periods. It is read from the user's profile, not stored on the habit.
+	WeekStart WeekStart `json:"-" db:"-"`
+	// FrozenPeriods are the starts of missed periods bridged by a streak
+	// freeze token, as calendar dates.
+	FrozenPeriods []time.Time `json:"-" db:"-"`
+	// Pauses are every vacation-mode window the habit has had, the current
+	// one included, oldest first.
+	Pauses []HabitPause `json:"-" db:"-"`
+}
+
+// HabitPause is one vacation-mode window of a habit.
+type HabitPause struct {
+	From  time.Time `json:"paused_from"`
+	Until time.Time `json:"paused_until"`
 }
 
 type CreateHabitRequest struct {
-	Name        string `json:"name" binding:"required,min=1,max=100"`
-	Color       string `json:"color" binding:"required,hexcolor"`
-	Icon        string `json:"icon" binding:"required,min=1,max=50"`
-	Frequency   string `json:"frequency" binding:"required,oneof=daily weekly monthly"`
-	TargetCount int    `json:"target_count" binding:"required,min=1,max=100"`
+	Name          string   `json:"name" binding:"required,min=1,max=100"`
+	Color         string   `json:"color" binding:"required,hexcolor"`
+	Icon          string   `json:"icon" binding:"required,min=1,max=50"`
+	Frequency     string   `json:"frequency" binding:"required,oneof=daily weekly monthly"`
+	TargetCount   int      `json:"target_count" binding:"required,min=1,max=100"`
+	TargetAmount  *float64 `json:"target_amount" binding:"omitempty,gt=0"`
+	ReminderTimes []string `json:"reminder_times"`
 }
 
+// UpdateHabitRequest changes the fields it sets. A target_amount of null
+// clears the amount, making the habit count-based again; a new amount is
+// checked by Habit.Validate.
 type UpdateHabitRequest struct {
-	Name        *string `json:"name" binding:"omitempty,min=1,max=100"`
-	Color       *string `json:"color" binding:"omitempty,hexcolor"`
-	Icon        *string `json:"icon" binding:"omitempty,min=1,max=50"`
-	Frequency   *string `json:"frequency" binding:"omitempty,oneof=daily weekly monthly"`
-	TargetCount *int    `json:"target_count" binding:"omitempty,min=1,max=100"`
-	IsActive    *bool   `json:"is_active"`
+	Name          *string       `json:"name" binding:"omitempty,min=1,max=100"`
+	Color         *string       `json:"color" binding:"omitempty,hexcolor"`
+	Icon          *string       `json:"icon" binding:"omitempty,min=1,max=50"`
+	Frequency     *string       `json:"frequency" binding:"omitempty,oneof=daily weekly monthly"`
+	TargetCount   *int          `json:"target_count" binding:"omitempty,min=1,max=100"`
+	TargetAmount  NullableFloat `json:"target_amount"`
+	IsActive      *bool         `json:"is_active"`
+	ReminderTimes *[]string     `json:"reminder_times"`
+}
+
+type PauseHabitRequest struct {
+	Until time.Time `json:"until" binding:"required"`
+}
+
+// ReorderHabitsRequest lists every one of the user's habit IDs in the desired
+// display order.
+type ReorderHabitsRequest struct {
+	HabitIDs []uuid.UUID `json:"habit_ids" binding:"required,min=1"`
+}
+
+func (r *ReorderHabitsRequest) Validate() error {
+	seen := make(map[uuid.UUID]bool, len(r.HabitIDs))
+	for _, id := range r.HabitIDs {
+		if seen[id] {
+			return ErrInvalidHabitOrder
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// MergeHabitsRequest names the habit that survives a merge. The habit in the
+// path is folded into it and deleted; the target keeps its own settings.
+type MergeHabitsRequest struct {
+	TargetID uuid.UUID `json:"target_id" binding:"required"`
 }
 
 type HabitCompletion struct {
@@ -41,8 +109,114 @@ type HabitCompletion struct {
 	HabitID     uuid.UUID `json:"habit_id" db:"habit_id"`
 	UserID      uuid.UUID `json:"user_id" db:"user_id"`
 	CompletedAt time.Time `json:"completed_at" db:"completed_at"`
-	Notes       string    `json:"notes" db:"notes"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	// Amount is how much this completion contributes towards an amount-based
+	// habit's TargetAmount. Count-based habits ignore it.
+	Amount    *float64  `json:"amount" db:"amount"`
+	Notes     string    `json:"notes" db:"notes"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// MaxHabitCompletionPageSize caps the limit a client may request per page
+// of completion history.
+const MaxHabitCompletionPageSize = 500
+
+// HabitCompletionPage selects one page of a habit's completion history,
+// newest first. After resumes after the last completion of the previous
+// page; From and To, when set, bound completed_at to [From, To).
+type HabitCompletionPage struct {
+	After *pagination.Cursor
+	Limit int
+	From  *time.Time
+	To    *time.Time
+}
+
+// Changes lists the JSON names of fields the request would actually change
+// on h. It must be called before the request is applied.
+func (r *UpdateHabitRequest) Changes(h *Habit) []string {
+	changes := []string{}
+
+	if r.Name != nil && *r.Name != h.Name {
+		changes = append(changes, "name")
+	}
+	if r.Color != nil && *r.Color != h.Color {
+		changes = append(changes, "color")
+	}
+	if r.Icon != nil && *r.Icon != h.Icon {
+		changes = append(changes, "icon")
+	}
+	if r.Frequency != nil && *r.Frequency != h.Frequency {
+		changes = append(changes, "frequency")
+	}
+	if r.TargetCount != nil && *r.TargetCount != h.TargetCount {
+		changes = append(changes, "target_count")
+	}
+	if !r.TargetAmount.Equal(h.TargetAmount) {
+		changes = append(changes, "target_amount")
+	}
+	if r.IsActive != nil && *r.IsActive != h.IsActive {
+		changes = append(changes, "is_active")
+	}
+	if r.ReminderTimes != nil && !slices.Equal(*r.ReminderTimes, h.ReminderTimes) {
+		changes = append(changes, "reminder_times")
+	}
+
+	return changes
+}
+
+// IsPausedAt reports whether t falls inside the habit's pause window.
+func (h *Habit) IsPausedAt(t time.Time) bool {
+	if h.PausedFrom == nil || h.PausedUntil == nil {
+		return false
+	}
+	return !t.Before(*h.PausedFrom) && !t.After(*h.PausedUntil)
+}
+
+// Pause puts the habit in vacation mode until the given time. Pausing an
+// already paused habit extends the current window instead of starting a new one.
+func (h *Habit) Pause(until, now time.Time) error {
+	if !until.After(now) {
+		return ErrPauseInPast
+	}
+
+	if !h.IsPausedAt(now) {
+		h.PausedFrom = &now
+	}
+	h.PausedUntil = &until
+
+	for i := range h.Pauses {
+		if h.Pauses[i].From.Equal(*h.PausedFrom) {
+			h.Pauses[i].Until = until
+			return nil
+		}
+	}
+	h.Pauses = append(h.Pauses, HabitPause{From: *h.PausedFrom, Until: until})
+
+	return nil
+}
+
+// pauseWindows returns every pause window of the habit: its pause history
+// and the current window, which may not have been loaded into it.
+func (h *Habit) pauseWindows() []HabitPause {
+	if h.PausedFrom == nil || h.PausedUntil == nil {
+		return h.Pauses
+	}
+	return append(slices.Clip(h.Pauses), HabitPause{From: *h.PausedFrom, Until: *h.PausedUntil})
+}
+
+// Habit list orders accepted by ?sort_by=.
+const (
+	HabitSortPosition = "position"
+	HabitSortName     = "name"
+	HabitSortCreated  = "created"
+)
+
+// ValidateHabitSort checks sortBy against the allowed habit list orders.
+func ValidateHabitSort(sortBy string) error {
+	switch sortBy {
+	case HabitSortPosition, HabitSortName, HabitSortCreated:
+		return nil
+	}
+	return ErrInvalidHabitSort
 }
 
 func (h *Habit) Validate() error {
@@ -60,5 +234,112 @@ func (h *Habit) Validate() error {
 		return ErrInvalidTargetCount
 	}
 
+	if h.TargetAmount != nil && *h.TargetAmount <= 0 {
+		return ErrInvalidTargetAmount
+	}
+
+	return ValidateReminderTimes(h.ReminderTimes)
+}
+
+// RemindersBetween returns the habit's reminder times falling in (from, to],
+// read as wall-clock times in to's location. The window may span midnight.
+func (h *Habit) RemindersBetween(from, to time.Time) []time.Time {
+	return RemindersBetween(h.ReminderTimes, from, to)
+}
+
+// RemindersBetween returns the occurrences of the "HH:MM" times falling in
+// (from, to], read as wall-clock times in to's location.
+func RemindersBetween(times []string, from, to time.Time) []time.Time {
+	from = from.In(to.Location())
+
+	var due []time.Time
+	for day := startOfDay(from); !day.After(to); day = day.AddDate(0, 0, 1) {
+		for _, value := range times {
+			clock, err := time.Parse("15:04", value)
+			if err != nil {
+				continue
+			}
+			at := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, to.Location())
+			if at.After(from) && !at.After(to) {
+				due = append(due, at)
+			}
+		}
+	}
+
+	return due
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// MaxReminderTimes caps how many reminders a single habit can schedule.
+const MaxReminderTimes = 10
+
+var reminderTimePattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+// ValidateReminderTimes checks that every entry is a 24-hour "HH:MM" time,
+// that none repeat and that there are at most MaxReminderTimes. An empty list
+// means the habit has no reminders.
+func ValidateReminderTimes(times []string) error {
+	if len(times) > MaxReminderTimes {
+		return fmt.Errorf("%w: at most %d allowed", ErrTooManyReminders, MaxReminderTimes)
+	}
+
+	seen := make(map[string]bool, len(times))
+	for _, t := range times {
+		if !reminderTimePattern.MatchString(t) {
+			return fmt.Errorf("%w: %q", ErrInvalidReminderTime, t)
+		}
+		if seen[t] {
+			return fmt.Errorf("%w: %q", ErrDuplicateReminder, t)
+		}
+		seen[t] = true
+	}
+
 	return nil
 }
+
+// ShiftRemindersRequest moves every reminder time of the user's habits by
+// Offset, a signed duration such as "+30m" or "-1h30m".
+type ShiftRemindersRequest struct {
+	Offset string `json:"offset" binding:"required"`
+}
+
+// ParseOffset returns the offset as a duration. It must be a whole number
+// of minutes, non-zero and shorter than a day.
+func (r *ShiftRemindersRequest) ParseOffset() (time.Duration, error) {
+	offset, err := time.ParseDuration(r.Offset)
+	if err != nil || offset == 0 || offset%time.Minute != 0 || offset <= -24*time.Hour || offset >= 24*time.Hour {
+		return 0, ErrInvalidReminderShift
+	}
+	return offset, nil
+}
+
+// ShiftReminderTimes moves each "HH:MM" time by offset, wrapping around
+// midnight, and keeps their order. The times are validated before and after
+// shifting, so stored values that are already invalid are reported rather
+// than shifted.
+func ShiftReminderTimes(times []string, offset time.Duration) ([]string, error) {
+	if err := ValidateReminderTimes(times); err != nil {
+		return nil, err
+	}
+
+	const minutesPerDay = 24 * 60
+	shiftMinutes := int(offset / time.Minute)
+
+	shifted := make([]string, 0, len(times))
+	for _, value := range times {
+		clock, err := time.Parse("15:04", value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidReminderTime, value)
+		}
+		minutes := ((clock.Hour()*60+clock.Minute()+shiftMinutes)%minutesPerDay + minutesPerDay) % minutesPerDay
+		shifted = append(shifted, fmt.Sprintf("%02d:%02d", minutes/60, minutes%60))
+	}
+
+	if err := ValidateReminderTimes(shifted); err != nil {
+		return nil, err
+	}
+	return shifted, nil
+}
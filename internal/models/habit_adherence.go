@@ -0,0 +1,72 @@
+package models
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AdherenceHalfLifeDays is how quickly a period's say in the adherence score
+// fades: a period that ended this many days ago weighs half as much as one
+// that just ended.
+const AdherenceHalfLifeDays = 7.0
+
+// HabitAdherence is a habit's adherence score over a window, alongside the
+// unweighted completion rate it refines.
+type HabitAdherence struct {
+	HabitID        uuid.UUID `json:"habit_id"`
+	Score          int       `json:"score"`
+	CompletionRate float64   `json:"completion_rate"`
+	WindowDays     int       `json:"window_days"`
+}
+
+// ParseAdherenceWindow reads a window such as "30d" as a number of days, at
+// most MaxCompletionRateWindow.
+func ParseAdherenceWindow(value string) (int, error) {
+	days, ok := strings.CutSuffix(value, "d")
+	if !ok {
+		return 0, ErrInvalidAdherenceWindow
+	}
+	n, err := strconv.Atoi(days)
+	if err != nil || n < 1 || n > MaxCompletionRateWindow {
+		return 0, ErrInvalidAdherenceWindow
+	}
+	return n, nil
+}
+
+// AdherenceScore rates how well the habit has been kept up over the last
+// windowDays days, from 0 to 100. It counts the same periods and credit as
+// CompletionRate, each period earning min(total, target) / target, but
+// weighs them by recency:
+//
+//	weight = 0.5 ^ (days since the period ended / AdherenceHalfLifeDays)
+//	score  = round(100 * Σ(weight * credit) / Σ weight)
+//
+// so a miss last week costs more than the same miss a month ago. A met
+// current period counts as just ended; an unmet one is left out, as are
+// neutral periods, so a habit with no periods to judge scores 0.
+func (h *Habit) AdherenceScore(completions []HabitCompletion, windowDays int, now time.Time) int {
+	target := h.periodGoal()
+	counts := h.periodTotals(completions, now.Location())
+
+	current := h.PeriodStart(now)
+	var weighted, total float64
+	for p := h.CompletionRateStart(windowDays, now); !p.After(current); p = h.nextPeriod(p) {
+		if counts[p] < target && (p.Equal(current) || h.isNeutralPeriod(p)) {
+			continue
+		}
+
+		age := now.Sub(h.nextPeriod(p)).Hours() / 24
+		weight := math.Pow(0.5, math.Max(age, 0)/AdherenceHalfLifeDays)
+		weighted += weight * min(counts[p], target) / target
+		total += weight
+	}
+
+	if total == 0 {
+		return 0
+	}
+	return int(math.Round(weighted / total * 100))
+}
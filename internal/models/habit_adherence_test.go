@@ -0,0 +1,79 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// dailyExcept returns a completion on each of the windowDays days before now,
+// skipping the given numbers of days ago.
+func dailyExcept(now time.Time, windowDays int, missedDaysAgo ...int) []HabitCompletion {
+	missed := make(map[int]bool)
+	for _, d := range missedDaysAgo {
+		missed[d] = true
+	}
+	var days []time.Time
+	for d := 1; d < windowDays; d++ {
+		if !missed[d] {
+			days = append(days, now.AddDate(0, 0, -d))
+		}
+	}
+	return completionsOn(days...)
+}
+
+func TestHabit_AdherenceScore_RecentMissesCostMore(t *testing.T) {
+	now := time.Date(2025, 11, 30, 18, 0, 0, 0, time.UTC)
+	habit := &Habit{Frequency: "daily", TargetCount: 1, CreatedAt: now.AddDate(-1, 0, 0)}
+
+	recentMisses := habit.AdherenceScore(dailyExcept(now, 30, 1, 2, 3), 30, now)
+	oldMisses := habit.AdherenceScore(dailyExcept(now, 30, 26, 27, 28), 30, now)
+
+	// Same completion rate either way; only when the misses happened differs.
+	assert.Equal(t,
+		habit.CompletionRate(dailyExcept(now, 30, 1, 2, 3), 30, now),
+		habit.CompletionRate(dailyExcept(now, 30, 26, 27, 28), 30, now))
+	assert.Less(t, recentMisses, oldMisses)
+	assert.Equal(t, 73, recentMisses)
+	assert.Equal(t, 98, oldMisses)
+}
+
+func TestHabit_AdherenceScore_Bounds(t *testing.T) {
+	now := time.Date(2025, 11, 30, 18, 0, 0, 0, time.UTC)
+	habit := &Habit{Frequency: "daily", TargetCount: 1, CreatedAt: now.AddDate(-1, 0, 0)}
+
+	assert.Equal(t, 100, habit.AdherenceScore(dailyExcept(now, 30), 30, now))
+	assert.Equal(t, 0, habit.AdherenceScore(nil, 30, now))
+
+	// Not done yet today is not a miss.
+	withToday := append(dailyExcept(now, 30), HabitCompletion{CompletedAt: now})
+	assert.Equal(t, 100, habit.AdherenceScore(withToday, 30, now))
+
+	fresh := &Habit{Frequency: "daily", TargetCount: 1, CreatedAt: now.Add(-time.Hour)}
+	assert.Equal(t, 0, fresh.AdherenceScore(nil, 30, now))
+}
+
+func TestHabit_AdherenceScore_PartialCreditAndRestDays(t *testing.T) {
+	now := time.Date(2025, 11, 30, 18, 0, 0, 0, time.UTC)
+	twice := &Habit{Frequency: "daily", TargetCount: 2, CreatedAt: now.AddDate(0, 0, -2)}
+
+	// Two finished days, each half done.
+	assert.Equal(t, 50, twice.AdherenceScore(completionsOn(now.AddDate(0, 0, -1), now.AddDate(0, 0, -2)), 30, now))
+
+	// A rest day is neutral rather than a recent miss.
+	habit := &Habit{Frequency: "daily", TargetCount: 1, CreatedAt: now.AddDate(-1, 0, 0)}
+	habit.RestDays = []time.Time{time.Date(2025, 11, 29, 0, 0, 0, 0, time.UTC)}
+	assert.Equal(t, 100, habit.AdherenceScore(dailyExcept(now, 30, 1), 30, now))
+}
+
+func TestParseAdherenceWindow(t *testing.T) {
+	days, err := ParseAdherenceWindow("30d")
+	assert.NoError(t, err)
+	assert.Equal(t, 30, days)
+
+	for _, invalid := range []string{"30", "0d", "366d", "-5d", "4w", "d"} {
+		_, err := ParseAdherenceWindow(invalid)
+		assert.ErrorIs(t, err, ErrInvalidAdherenceWindow, invalid)
+	}
+}
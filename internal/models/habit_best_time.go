@@ -0,0 +1,39 @@
+package models
+
+// HourCount is the number of completions logged within one local hour of
+// the day, 0 through 23.
+type HourCount struct {
+	Hour  int `json:"hour"`
+	Count int `json:"count"`
+}
+
+// HabitBestTime is the hour-of-day distribution of a habit's completions in
+// the user's timezone. BestHour is the hour with the most completions, the
+// earliest on a tie, and nil when the habit has never been completed.
+type HabitBestTime struct {
+	Timezone     string  `json:"timezone"`
+	Distribution [24]int `json:"distribution"`
+	BestHour     *int    `json:"best_hour"`
+	Completions  int     `json:"completions"`
+}
+
+// NewHabitBestTime builds the distribution from per-hour counts, ignoring
+// hours outside 0-23.
+func NewHabitBestTime(timezone string, counts []HourCount) HabitBestTime {
+	best := HabitBestTime{Timezone: timezone}
+	for _, count := range counts {
+		if count.Hour < 0 || count.Hour > 23 {
+			continue
+		}
+		best.Distribution[count.Hour] += count.Count
+		best.Completions += count.Count
+	}
+
+	for hour, count := range best.Distribution {
+		if count > 0 && (best.BestHour == nil || count > best.Distribution[*best.BestHour]) {
+			best.BestHour = &hour
+		}
+	}
+
+	return best
+}
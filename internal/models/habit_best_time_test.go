@@ -0,0 +1,49 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHabitBestTime_PicksModalHour(t *testing.T) {
+	best := NewHabitBestTime("Europe/Berlin", []HourCount{
+		{Hour: 7, Count: 3},
+		{Hour: 21, Count: 5},
+		{Hour: 22, Count: 1},
+	})
+
+	assert.Equal(t, "Europe/Berlin", best.Timezone)
+	assert.Equal(t, 9, best.Completions)
+	assert.Equal(t, 3, best.Distribution[7])
+	assert.Equal(t, 5, best.Distribution[21])
+	assert.Equal(t, 0, best.Distribution[8])
+	if assert.NotNil(t, best.BestHour) {
+		assert.Equal(t, 21, *best.BestHour)
+	}
+}
+
+func TestNewHabitBestTime_TieGoesToEarliestHour(t *testing.T) {
+	best := NewHabitBestTime("UTC", []HourCount{{Hour: 18, Count: 2}, {Hour: 6, Count: 2}})
+
+	if assert.NotNil(t, best.BestHour) {
+		assert.Equal(t, 6, *best.BestHour)
+	}
+}
+
+func TestNewHabitBestTime_NoCompletions(t *testing.T) {
+	best := NewHabitBestTime("UTC", nil)
+
+	assert.Nil(t, best.BestHour)
+	assert.Equal(t, 0, best.Completions)
+	assert.Equal(t, [24]int{}, best.Distribution)
+}
+
+func TestNewHabitBestTime_IgnoresOutOfRangeHours(t *testing.T) {
+	best := NewHabitBestTime("UTC", []HourCount{{Hour: 24, Count: 9}, {Hour: -1, Count: 9}, {Hour: 3, Count: 1}})
+
+	assert.Equal(t, 1, best.Completions)
+	if assert.NotNil(t, best.BestHour) {
+		assert.Equal(t, 3, *best.BestHour)
+	}
+}
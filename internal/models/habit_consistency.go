@@ -0,0 +1,113 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Habit consistency labels.
+const (
+	ConsistencySteady           = "steady"
+	ConsistencyErratic          = "erratic"
+	ConsistencyInsufficientData = "insufficient_data"
+)
+
+// MinConsistencyCompletions is how many completions a window needs before
+// their spacing is judged; fewer leave too few gaps to compare.
+const MinConsistencyCompletions = 3
+
+// SteadyMaxVariation is the largest coefficient of variation of the gaps
+// between completions that still counts as steady.
+const SteadyMaxVariation = 0.5
+
+// HabitConsistency describes how evenly a habit's completions in a window
+// are spread. Gaps are in days. CoefficientOfVariation is the standard
+// deviation of the gaps over their mean: 0 for perfectly even spacing,
+// growing as completions clump together. It is null when there are too few
+// completions or no time between them.
+type HabitConsistency struct {
+	HabitID                uuid.UUID `json:"habit_id"`
+	Label                  string    `json:"label"`
+	Completions            int       `json:"completions"`
+	MeanGapDays            float64   `json:"mean_gap_days"`
+	GapVariance            float64   `json:"gap_variance"`
+	CoefficientOfVariation *float64  `json:"coefficient_of_variation"`
+	WindowDays             int       `json:"window_days"`
+}
+
+// Consistency measures the gaps between consecutive completions over the
+// same periods CompletionRate scores, and labels them steady when their
+// coefficient of variation is at most SteadyMaxVariation. Periods that are
+// neutral for streaks don't count: completions in paused, rest or frozen
+// periods or before a streak reset are left out, and a gap spanning neutral
+// periods is shortened by their length. Completions all logged at the same
+// instant have no spread to measure and count as erratic.
+func (h *Habit) Consistency(completions []HabitCompletion, windowDays int, now time.Time) HabitConsistency {
+	start := h.CompletionRateStart(windowDays, now)
+	kept := make([]time.Time, 0, len(completions))
+	for _, completion := range h.sinceStreakReset(completions) {
+		at := completion.CompletedAt.In(now.Location())
+		if at.Before(start) || h.isNeutralPeriod(h.PeriodStart(at)) {
+			continue
+		}
+		kept = append(kept, at)
+	}
+
+	consistency := HabitConsistency{
+		HabitID:     h.ID,
+		Label:       ConsistencyInsufficientData,
+		Completions: len(kept),
+		WindowDays:  windowDays,
+	}
+	if len(kept) < MinConsistencyCompletions {
+		return consistency
+	}
+
+	sort.Slice(kept, func(i, j int) bool { return kept[i].Before(kept[j]) })
+
+	gaps := make([]float64, 0, len(kept)-1)
+	var sum float64
+	for i := 1; i < len(kept); i++ {
+		gap := h.activeGapDays(kept[i-1], kept[i])
+		gaps = append(gaps, gap)
+		sum += gap
+	}
+	mean := sum / float64(len(gaps))
+
+	var squares float64
+	for _, gap := range gaps {
+		squares += (gap - mean) * (gap - mean)
+	}
+	variance := squares / float64(len(gaps))
+
+	consistency.MeanGapDays = math.Round(mean*100) / 100
+	consistency.GapVariance = math.Round(variance*100) / 100
+	consistency.Label = ConsistencyErratic
+	if mean == 0 {
+		return consistency
+	}
+
+	cv := math.Round(math.Sqrt(variance)/mean*100) / 100
+	consistency.CoefficientOfVariation = &cv
+	if cv <= SteadyMaxVariation {
+		consistency.Label = ConsistencySteady
+	}
+
+	return consistency
+}
+
+// activeGapDays returns the days from one completion to the next, less the
+// neutral periods lying wholly between them.
+func (h *Habit) activeGapDays(from, to time.Time) float64 {
+	gap := to.Sub(from)
+	last := h.PeriodStart(to)
+	for p := h.nextPeriod(h.PeriodStart(from)); p.Before(last); p = h.nextPeriod(p) {
+		if h.isNeutralPeriod(p) {
+			gap -= h.nextPeriod(p).Sub(p)
+		}
+	}
+	return gap.Hours() / 24
+}
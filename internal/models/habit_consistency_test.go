@@ -0,0 +1,133 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHabitConsistency_EvenSpacingIsSteady(t *testing.T) {
+	daily := &Habit{ID: uuid.New(), Frequency: "daily", TargetCount: 1}
+	start := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	now := start.AddDate(0, 0, 20)
+	var days []time.Time
+	for i := 0; i < 10; i++ {
+		days = append(days, start.AddDate(0, 0, 2*i))
+	}
+
+	consistency := daily.Consistency(completionsOn(days...), 30, now)
+
+	assert.Equal(t, ConsistencySteady, consistency.Label)
+	assert.Equal(t, 2.0, consistency.MeanGapDays)
+	assert.Equal(t, 0.0, consistency.GapVariance)
+	if assert.NotNil(t, consistency.CoefficientOfVariation) {
+		assert.Equal(t, 0.0, *consistency.CoefficientOfVariation)
+	}
+}
+
+func TestHabitConsistency_ClusteredIsErratic(t *testing.T) {
+	daily := &Habit{ID: uuid.New(), Frequency: "daily", TargetCount: 1}
+	start := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	now := start.AddDate(0, 0, 20)
+	// Ten completions over the same 18 days, in two bursts of five.
+	var days []time.Time
+	for i := 0; i < 5; i++ {
+		days = append(days, start.Add(time.Duration(i)*time.Hour), start.AddDate(0, 0, 18).Add(time.Duration(i)*time.Hour))
+	}
+
+	consistency := daily.Consistency(completionsOn(days...), 30, now)
+
+	assert.Equal(t, ConsistencyErratic, consistency.Label)
+	assert.Greater(t, consistency.GapVariance, 10.0)
+	if assert.NotNil(t, consistency.CoefficientOfVariation) {
+		assert.Greater(t, *consistency.CoefficientOfVariation, SteadyMaxVariation)
+	}
+}
+
+func TestHabitConsistency_IgnoresOrder(t *testing.T) {
+	daily := &Habit{ID: uuid.New(), Frequency: "daily", TargetCount: 1}
+	start := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	now := start.AddDate(0, 0, 20)
+	days := []time.Time{start.AddDate(0, 0, 4), start, start.AddDate(0, 0, 2)}
+
+	consistency := daily.Consistency(completionsOn(days...), 30, now)
+
+	assert.Equal(t, ConsistencySteady, consistency.Label)
+	assert.Equal(t, 2.0, consistency.MeanGapDays)
+}
+
+func TestHabitConsistency_InsufficientData(t *testing.T) {
+	daily := &Habit{ID: uuid.New(), Frequency: "daily", TargetCount: 1}
+	start := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	now := start.AddDate(0, 0, 20)
+
+	consistency := daily.Consistency(completionsOn(start, start.AddDate(0, 0, 1)), 30, now)
+
+	assert.Equal(t, ConsistencyInsufficientData, consistency.Label)
+	assert.Equal(t, 2, consistency.Completions)
+	assert.Nil(t, consistency.CoefficientOfVariation)
+
+	same := daily.Consistency(completionsOn(start, start, start), 30, now)
+	assert.Equal(t, ConsistencyErratic, same.Label)
+	assert.Nil(t, same.CoefficientOfVariation)
+}
+
+func TestHabitConsistency_SkipsNeutralPeriods(t *testing.T) {
+	start := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	now := start.AddDate(0, 0, 20)
+	pausedFrom, pausedUntil := start.AddDate(0, 0, 5), start.AddDate(0, 0, 9)
+	habit := &Habit{
+		ID:            uuid.New(),
+		Frequency:     "daily",
+		TargetCount:   1,
+		PausedFrom:    &pausedFrom,
+		PausedUntil:   &pausedUntil,
+		RestDays:      []time.Time{start.AddDate(0, 0, 13)},
+		FrozenPeriods: []time.Time{start.AddDate(0, 0, 16)},
+	}
+
+	// Every other active day, plus a completion on the rest day that is
+	// ignored. The pause, rest day and frozen day don't widen the gaps.
+	days := []time.Time{
+		start, start.AddDate(0, 0, 2), start.AddDate(0, 0, 4),
+		start.AddDate(0, 0, 11), start.AddDate(0, 0, 13), start.AddDate(0, 0, 14), start.AddDate(0, 0, 17),
+	}
+
+	consistency := habit.Consistency(completionsOn(days...), 30, now)
+
+	assert.Equal(t, ConsistencySteady, consistency.Label)
+	assert.Equal(t, 6, consistency.Completions)
+	assert.Equal(t, 2.0, consistency.MeanGapDays)
+	assert.Equal(t, 0.0, consistency.GapVariance)
+}
+
+func TestHabitConsistency_IgnoresCompletionsBeforeStreakReset(t *testing.T) {
+	start := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	now := start.AddDate(0, 0, 20)
+	reset := start.AddDate(0, 0, 10)
+	habit := &Habit{ID: uuid.New(), Frequency: "daily", TargetCount: 1, StreakResetAt: &reset}
+
+	days := []time.Time{start, start.AddDate(0, 0, 1), start.AddDate(0, 0, 10), start.AddDate(0, 0, 12), start.AddDate(0, 0, 14)}
+
+	consistency := habit.Consistency(completionsOn(days...), 30, now)
+
+	assert.Equal(t, 3, consistency.Completions)
+	assert.Equal(t, 2.0, consistency.MeanGapDays)
+	assert.Equal(t, ConsistencySteady, consistency.Label)
+}
+
+func TestHabitConsistency_UsesCompletionRateWindow(t *testing.T) {
+	now := time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC)
+	habit := &Habit{ID: uuid.New(), Frequency: "daily", TargetCount: 1}
+
+	// A 7-day window starts at midnight six days back, as CompletionRate's
+	// does, so the completion seven days back is outside it.
+	days := []time.Time{now.AddDate(0, 0, -7), now.AddDate(0, 0, -6), now.AddDate(0, 0, -4), now.AddDate(0, 0, -2)}
+
+	consistency := habit.Consistency(completionsOn(days...), 7, now)
+
+	assert.Equal(t, 3, consistency.Completions)
+	assert.Equal(t, 2.0, consistency.MeanGapDays)
+}
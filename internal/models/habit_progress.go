@@ -0,0 +1,501 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HabitProgress summarises a habit's completion history as of a point in time.
+// It is always derived from the stored completions, so adding or deleting a
+// completion is reflected the next time it is computed. Lowering a habit's
+// target below the completions already logged keeps those completions:
+// PeriodCount may exceed PeriodTarget, but PeriodPercent caps at 100.
+//
+// For an amount-based habit PeriodAmount and PeriodTargetAmount are set, and
+// PeriodPercent and streaks follow the summed amount rather than the count.
+type HabitProgress struct {
+	CurrentStreak      int      `json:"current_streak"`
+	LongestStreak      int      `json:"longest_streak"`
+	PeriodCount        int      `json:"period_count"`
+	PeriodTarget       int      `json:"period_target"`
+	PeriodAmount       *float64 `json:"period_amount,omitempty"`
+	PeriodTargetAmount *float64 `json:"period_target_amount,omitempty"`
+	PeriodPercent      float64  `json:"period_percent"`
+}
+
+// Completion rate look-back window, in days.
+const (
+	DefaultCompletionRateWindow = 30
+	MaxCompletionRateWindow     = 365
+)
+
+// HabitDetail is a habit together with figures derived from its completions.
+type HabitDetail struct {
+	Habit
+	CompletionRate       float64 `json:"completion_rate"`
+	CompletionRateWindow int     `json:"completion_rate_window_days"`
+}
+
+// IsAmountBased reports whether the habit is met by summed completion
+// amounts rather than by a number of completions.
+func (h *Habit) IsAmountBased() bool {
+	return h.TargetAmount != nil && *h.TargetAmount > 0
+}
+
+// periodGoal is what a period's total must reach to be met: TargetAmount for
+// an amount-based habit, otherwise TargetCount, at least 1.
+func (h *Habit) periodGoal() float64 {
+	if h.IsAmountBased() {
+		return *h.TargetAmount
+	}
+	return float64(max(h.TargetCount, 1))
+}
+
+// completionValue is what a completion adds to its period's total: its
+// amount for an amount-based habit, where a completion without one adds
+// nothing, and 1 otherwise.
+func (h *Habit) completionValue(completion HabitCompletion) float64 {
+	if !h.IsAmountBased() {
+		return 1
+	}
+	if completion.Amount == nil {
+		return 0
+	}
+	return *completion.Amount
+}
+
+// periodTotals sums completions per period, in loc, as periodGoal measures
+// them.
+func (h *Habit) periodTotals(completions []HabitCompletion, loc *time.Location) map[time.Time]float64 {
+	totals := make(map[time.Time]float64)
+	for _, completion := range completions {
+		totals[h.PeriodStart(completion.CompletedAt.In(loc))] += h.completionValue(completion)
+	}
+	return totals
+}
+
+// PeriodStart returns the start of the frequency period containing t, in t's
+// location. Weeks start on the habit's WeekStart, Monday by default.
+func (h *Habit) PeriodStart(t time.Time) time.Time {
+	year, month, day := t.Date()
+
+	switch h.Frequency {
+	case "weekly":
+		return h.WeekStart.StartOf(t)
+	case "monthly":
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// nextPeriod returns the start of the period following the one starting at start.
+func (h *Habit) nextPeriod(start time.Time) time.Time {
+	switch h.Frequency {
+	case "weekly":
+		return start.AddDate(0, 0, 7)
+	case "monthly":
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// prevPeriod returns the start of the period preceding the one starting at start.
+func (h *Habit) prevPeriod(start time.Time) time.Time {
+	switch h.Frequency {
+	case "weekly":
+		return start.AddDate(0, 0, -7)
+	case "monthly":
+		return start.AddDate(0, -1, 0)
+	default:
+		return start.AddDate(0, 0, -1)
+	}
+}
+
+// isPausedPeriod reports whether the period starting at start overlaps any
+// of the habit's pause windows.
+func (h *Habit) isPausedPeriod(start time.Time) bool {
+	for _, pause := range h.pauseWindows() {
+		from := h.PeriodStart(pause.From.In(start.Location()))
+		until := h.PeriodStart(pause.Until.In(start.Location()))
+		if !start.Before(from) && !start.After(until) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRestPeriod reports whether the period starting at start is a day the user
+// logged as a rest day. Only daily periods qualify: a single rest day does not
+// excuse a whole week or month.
+func (h *Habit) isRestPeriod(start time.Time) bool {
+	if h.Frequency == "weekly" || h.Frequency == "monthly" {
+		return false
+	}
+	day := start.Format(time.DateOnly)
+	for _, rest := range h.RestDays {
+		if rest.UTC().Format(time.DateOnly) == day {
+			return true
+		}
+	}
+	return false
+}
+
+// isFrozenPeriod reports whether a streak freeze was spent on the period
+// starting at start.
+func (h *Habit) isFrozenPeriod(start time.Time) bool {
+	day := start.Format(time.DateOnly)
+	for _, frozen := range h.FrozenPeriods {
+		if frozen.UTC().Format(time.DateOnly) == day {
+			return true
+		}
+	}
+	return false
+}
+
+// sinceStreakReset keeps the completions logged at or after the habit's
+// streak reset, the only ones that count towards its streaks.
+func (h *Habit) sinceStreakReset(completions []HabitCompletion) []HabitCompletion {
+	if h.StreakResetAt == nil {
+		return completions
+	}
+
+	kept := make([]HabitCompletion, 0, len(completions))
+	for _, completion := range completions {
+		if !completion.CompletedAt.Before(*h.StreakResetAt) {
+			kept = append(kept, completion)
+		}
+	}
+	return kept
+}
+
+// beforeStreakReset reports whether the period starting at start precedes the
+// one the habit's streak was reset in, so it can't carry a streak.
+func (h *Habit) beforeStreakReset(start time.Time) bool {
+	if h.StreakResetAt == nil {
+		return false
+	}
+	return start.Before(h.PeriodStart(h.StreakResetAt.In(start.Location())))
+}
+
+// isNeutralPeriod reports whether an unmet period should neither extend nor
+// break a streak.
+func (h *Habit) isNeutralPeriod(start time.Time) bool {
+	return h.isPausedPeriod(start) || h.isRestPeriod(start) || h.isFrozenPeriod(start)
+}
+
+// FreezablePeriod returns the missed period a streak freeze would bridge: the
+// most recent past period that breaks the current streak, provided it is a
+// single missed period with a met period right before it. A gap of two or
+// more missed periods cannot be bridged, and neither can a period before the
+// last streak reset.
+func (h *Habit) FreezablePeriod(completions []HabitCompletion, now time.Time) (time.Time, bool) {
+	target := h.periodGoal()
+	kept := h.sinceStreakReset(completions)
+	counts := h.periodTotals(kept, now.Location())
+	earliest := now
+	for _, completion := range kept {
+		if at := completion.CompletedAt.In(now.Location()); at.Before(earliest) {
+			earliest = at
+		}
+	}
+
+	current := h.PeriodStart(now)
+	p := h.prevPeriod(current)
+	for counts[p] >= target || h.isNeutralPeriod(p) {
+		if p.Before(earliest) || h.beforeStreakReset(p) {
+			return time.Time{}, false
+		}
+		p = h.prevPeriod(p)
+	}
+
+	if counts[h.prevPeriod(p)] < target {
+		return time.Time{}, false
+	}
+	return p, true
+}
+
+// StreakFreezeMilestone is the streak length, in periods, that earns a
+// streak freeze token: one each time a habit's current streak reaches a
+// multiple of it.
+const StreakFreezeMilestone = 7
+
+// EarnedStreakFreeze reports whether logging added takes the habit's current
+// streak past a multiple of StreakFreezeMilestone, given the completions
+// logged before it. It returns the period added falls in, which keys the
+// grant so undoing and logging the completion again earns nothing more.
+func (h *Habit) EarnedStreakFreeze(completions []HabitCompletion, added HabitCompletion, now time.Time) (time.Time, bool) {
+	before := h.Progress(completions, now).CurrentStreak
+	after := h.Progress(append(completions[:len(completions):len(completions)], added), now).CurrentStreak
+	if after/StreakFreezeMilestone <= before/StreakFreezeMilestone {
+		return time.Time{}, false
+	}
+	return h.PeriodStart(added.CompletedAt.In(now.Location())), true
+}
+
+// Progress computes streaks and current-period progress from completions.
+// A period counts towards a streak once it reaches TargetCount completions,
+// or for an amount-based habit once their amounts add up to TargetAmount.
+// The current period is still in progress, so an unmet current period does
+// not break the streak carried over from the previous one. Unmet periods
+// inside a pause window, on a rest day or bridged by a streak freeze are
+// neutral: they neither extend nor break a streak. Streaks start counting
+// anew from the habit's streak reset, while the current period's progress
+// still counts every completion in it.
+func (h *Habit) Progress(completions []HabitCompletion, now time.Time) HabitProgress {
+	target := h.periodGoal()
+
+	current := h.PeriodStart(now)
+	periodCount := 0
+	periodTotal := 0.0
+	for _, completion := range completions {
+		if h.PeriodStart(completion.CompletedAt.In(now.Location())).Equal(current) {
+			periodCount++
+			periodTotal += h.completionValue(completion)
+		}
+	}
+
+	progress := HabitProgress{
+		PeriodCount:   periodCount,
+		PeriodTarget:  max(h.TargetCount, 1),
+		PeriodPercent: math.Round(math.Min(periodTotal/target, 1)*1000) / 10,
+	}
+	if h.IsAmountBased() {
+		progress.PeriodAmount = &periodTotal
+		progress.PeriodTargetAmount = &target
+	}
+
+	kept := h.sinceStreakReset(completions)
+	counts := h.periodTotals(kept, now.Location())
+	seen := make(map[time.Time]bool, len(counts))
+	var periods []time.Time
+	for _, completion := range kept {
+		start := h.PeriodStart(completion.CompletedAt.In(now.Location()))
+		if !seen[start] {
+			seen[start] = true
+			periods = append(periods, start)
+		}
+	}
+
+	if len(periods) == 0 {
+		return progress
+	}
+
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Before(periods[j]) })
+
+	run := 0
+	for p := periods[0]; !p.After(current); p = h.nextPeriod(p) {
+		if counts[p] >= target {
+			run++
+			if run > progress.LongestStreak {
+				progress.LongestStreak = run
+			}
+		} else if !p.Equal(current) && !h.isNeutralPeriod(p) {
+			run = 0
+		}
+	}
+
+	p := current
+	if counts[p] < target {
+		p = h.prevPeriod(p)
+	}
+	for (counts[p] >= target || h.isNeutralPeriod(p)) && !h.beforeStreakReset(p) {
+		if counts[p] >= target {
+			progress.CurrentStreak++
+		}
+		p = h.prevPeriod(p)
+	}
+
+	return progress
+}
+
+// HabitStreak is one habit's entry in the cross-habit streak overview.
+type HabitStreak struct {
+	HabitID       uuid.UUID `json:"habit_id"`
+	Name          string    `json:"name"`
+	Frequency     string    `json:"frequency"`
+	CurrentStreak int       `json:"current_streak"`
+	LongestStreak int       `json:"longest_streak"`
+}
+
+// HabitStreaks computes each habit's streaks from the user's completions
+// across all habits, ordered by current streak, then longest streak, both
+// descending, and otherwise keeping the habits' order.
+func HabitStreaks(habits []Habit, completions []HabitCompletion, now time.Time) []HabitStreak {
+	byHabit := make(map[uuid.UUID][]HabitCompletion, len(habits))
+	for _, completion := range completions {
+		byHabit[completion.HabitID] = append(byHabit[completion.HabitID], completion)
+	}
+
+	streaks := make([]HabitStreak, 0, len(habits))
+	for i := range habits {
+		habit := &habits[i]
+		progress := habit.Progress(byHabit[habit.ID], now)
+		streaks = append(streaks, HabitStreak{
+			HabitID:       habit.ID,
+			Name:          habit.Name,
+			Frequency:     habit.Frequency,
+			CurrentStreak: progress.CurrentStreak,
+			LongestStreak: progress.LongestStreak,
+		})
+	}
+
+	sort.SliceStable(streaks, func(i, j int) bool {
+		if streaks[i].CurrentStreak != streaks[j].CurrentStreak {
+			return streaks[i].CurrentStreak > streaks[j].CurrentStreak
+		}
+		return streaks[i].LongestStreak > streaks[j].LongestStreak
+	})
+
+	return streaks
+}
+
+// PeriodBounds returns the [start, end) of the frequency period containing t.
+func (h *Habit) PeriodBounds(t time.Time) (time.Time, time.Time) {
+	start := h.PeriodStart(t)
+	return start, h.nextPeriod(start)
+}
+
+// PeriodTally is what a habit has logged in one period: how many
+// completions, and the sum of their amounts.
+type PeriodTally struct {
+	Count  int
+	Amount float64
+}
+
+// Add counts completion towards the tally.
+func (t *PeriodTally) Add(completion HabitCompletion) {
+	t.Count++
+	if completion.Amount != nil {
+		t.Amount += *completion.Amount
+	}
+}
+
+// tallyTotal is the tally as periodGoal measures it: the summed amount for an
+// amount-based habit, otherwise the number of completions.
+func (h *Habit) tallyTotal(tally PeriodTally) float64 {
+	if h.IsAmountBased() {
+		return tally.Amount
+	}
+	return float64(tally.Count)
+}
+
+// IsDueOn reports whether the habit still needs doing on date, given what it
+// has already logged in the period containing date. Daily habits are due
+// every day; weekly and monthly habits stop being due once the period's
+// target, a count or for an amount-based habit an amount, is met. Inactive
+// habits and habits paused at any point that day are never due.
+func (h *Habit) IsDueOn(date time.Time, tally PeriodTally) bool {
+	if !h.IsActive {
+		return false
+	}
+
+	dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	for _, pause := range h.pauseWindows() {
+		if pause.From.Before(dayEnd) && pause.Until.After(dayStart) {
+			return false
+		}
+	}
+
+	if h.Frequency == "daily" {
+		return true
+	}
+
+	return h.tallyTotal(tally) < h.periodGoal()
+}
+
+// IncompleteHabit is a habit still due on a date together with how far it
+// is from its target for the period containing that date. Remaining is in
+// completions, or for an amount-based habit in its amount, in which case
+// PeriodAmount and PeriodTargetAmount are set as in HabitProgress.
+type IncompleteHabit struct {
+	Habit
+	PeriodCount        int      `json:"period_count"`
+	PeriodTarget       int      `json:"period_target"`
+	PeriodAmount       *float64 `json:"period_amount,omitempty"`
+	PeriodTargetAmount *float64 `json:"period_target_amount,omitempty"`
+	Remaining          float64  `json:"remaining"`
+}
+
+// Remaining returns how much more the habit needs in the period containing
+// date, given tally already logged there: completions, or amount for an
+// amount-based habit. It is 0 when the habit isn't due that day.
+func (h *Habit) Remaining(date time.Time, tally PeriodTally) float64 {
+	if !h.IsDueOn(date, tally) {
+		return 0
+	}
+	return max(h.periodGoal()-h.tallyTotal(tally), 0)
+}
+
+// IncompleteOn keeps the habits that still need completions on date.
+// tallies[i] is what habits[i] has logged in its period around date.
+func IncompleteOn(habits []Habit, tallies []PeriodTally, date time.Time) []IncompleteHabit {
+	incomplete := []IncompleteHabit{}
+	for i, habit := range habits {
+		remaining := habit.Remaining(date, tallies[i])
+		if remaining == 0 {
+			continue
+		}
+		entry := IncompleteHabit{
+			Habit:        habit,
+			PeriodCount:  tallies[i].Count,
+			PeriodTarget: max(habit.TargetCount, 1),
+			Remaining:    remaining,
+		}
+		if habit.IsAmountBased() {
+			amount, target := tallies[i].Amount, habit.periodGoal()
+			entry.PeriodAmount = &amount
+			entry.PeriodTargetAmount = &target
+		}
+		incomplete = append(incomplete, entry)
+	}
+	return incomplete
+}
+
+// CompletionRateStart returns the start of the first period CompletionRate
+// considers: the period containing the first day of the window, or of the
+// habit's creation day if that is later.
+func (h *Habit) CompletionRateStart(windowDays int, now time.Time) time.Time {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	from := today.AddDate(0, 0, -(windowDays - 1))
+	if !h.CreatedAt.IsZero() {
+		created := h.CreatedAt.In(now.Location())
+		if created.After(from) {
+			from = created
+		}
+	}
+	return h.PeriodStart(from)
+}
+
+// CompletionRate returns the share of the habit's target met over the last
+// windowDays days, from 0 to 1 rounded to two decimals. Each period in the
+// window earns credit for up to TargetCount completions, or up to
+// TargetAmount of summed amounts for an amount-based habit. As with Progress,
+// the current period and paused or rest periods only count once they are met,
+// so a habit created today with no completions has a rate of 0 rather than
+// being penalised for periods that haven't happened yet.
+func (h *Habit) CompletionRate(completions []HabitCompletion, windowDays int, now time.Time) float64 {
+	target := h.periodGoal()
+	counts := h.periodTotals(completions, now.Location())
+
+	current := h.PeriodStart(now)
+	eligible := 0
+	credit := 0.0
+	for p := h.CompletionRateStart(windowDays, now); !p.After(current); p = h.nextPeriod(p) {
+		if counts[p] < target && (p.Equal(current) || h.isNeutralPeriod(p)) {
+			continue
+		}
+		eligible++
+		credit += min(counts[p], target) / target
+	}
+
+	if eligible == 0 {
+		return 0
+	}
+	return math.Round(credit/float64(eligible)*100) / 100
+}
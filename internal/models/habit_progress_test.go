@@ -0,0 +1,765 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func completionsOn(days ...time.Time) []HabitCompletion {
+	completions := make([]HabitCompletion, 0, len(days))
+	for _, d := range days {
+		completions = append(completions, HabitCompletion{CompletedAt: d})
+	}
+	return completions
+}
+
+func TestHabitProgress_DailyStreak(t *testing.T) {
+	habit := &Habit{Frequency: "daily", TargetCount: 1}
+	now := time.Date(2025, 11, 13, 18, 0, 0, 0, time.UTC)
+
+	completions := completionsOn(
+		now.AddDate(0, 0, -2),
+		now.AddDate(0, 0, -1),
+		now,
+	)
+
+	progress := habit.Progress(completions, now)
+	assert.Equal(t, 3, progress.CurrentStreak)
+	assert.Equal(t, 3, progress.LongestStreak)
+	assert.Equal(t, 1, progress.PeriodCount)
+}
+
+func TestHabitProgress_DeletedCompletionBreaksStreak(t *testing.T) {
+	habit := &Habit{Frequency: "daily", TargetCount: 1}
+	now := time.Date(2025, 11, 13, 18, 0, 0, 0, time.UTC)
+
+	completions := completionsOn(
+		now.AddDate(0, 0, -2),
+		now.AddDate(0, 0, -1),
+		now,
+	)
+
+	// Deleting yesterday's completion leaves a gap in the run.
+	remaining := append([]HabitCompletion{}, completions[0], completions[2])
+
+	progress := habit.Progress(remaining, now)
+	assert.Equal(t, 1, progress.CurrentStreak)
+	assert.Equal(t, 1, progress.LongestStreak)
+
+	// Deleting today's completion resets current-period progress but the
+	// streak from earlier days carries over while today is still open.
+	progress = habit.Progress(completions[:2], now)
+	assert.Equal(t, 2, progress.CurrentStreak)
+	assert.Equal(t, 0, progress.PeriodCount)
+}
+
+func TestHabitProgress_WeeklyTargetCount(t *testing.T) {
+	habit := &Habit{Frequency: "weekly", TargetCount: 2}
+	now := time.Date(2025, 11, 13, 12, 0, 0, 0, time.UTC) // Thursday
+
+	completions := completionsOn(
+		time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 11, 5, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 11, 10, 9, 0, 0, 0, time.UTC),
+	)
+
+	progress := habit.Progress(completions, now)
+	assert.Equal(t, 1, progress.CurrentStreak)
+	assert.Equal(t, 1, progress.PeriodCount)
+	assert.Equal(t, 2, progress.PeriodTarget)
+	assert.Equal(t, 50.0, progress.PeriodPercent)
+}
+
+func TestHabitProgress_WeeklyFollowsWeekStart(t *testing.T) {
+	now := time.Date(2025, 11, 13, 12, 0, 0, 0, time.UTC) // Thursday
+	completions := completionsOn(
+		time.Date(2025, 11, 9, 9, 0, 0, 0, time.UTC), // Sunday
+		time.Date(2025, 11, 10, 9, 0, 0, 0, time.UTC),
+	)
+
+	monday := &Habit{Frequency: "weekly", TargetCount: 2, WeekStart: WeekStartMonday}
+	progress := monday.Progress(completions, now)
+	assert.Equal(t, 1, progress.PeriodCount)
+	assert.Equal(t, 0, progress.CurrentStreak)
+
+	sunday := &Habit{Frequency: "weekly", TargetCount: 2, WeekStart: WeekStartSunday}
+	progress = sunday.Progress(completions, now)
+	assert.Equal(t, 2, progress.PeriodCount)
+	assert.Equal(t, 1, progress.CurrentStreak)
+}
+
+func TestHabitProgress_TargetLoweredBelowCompletions(t *testing.T) {
+	now := time.Date(2025, 11, 13, 12, 0, 0, 0, time.UTC) // Thursday
+	completions := completionsOn(
+		time.Date(2025, 11, 10, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 11, 11, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC),
+	)
+
+	// Three completions logged against a target of 5, then lowered to 2.
+	habit := &Habit{Frequency: "weekly", TargetCount: 5, IsActive: true}
+	before := habit.Progress(completions, now)
+	assert.Equal(t, 60.0, before.PeriodPercent)
+	assert.Equal(t, 0, before.CurrentStreak)
+
+	habit.TargetCount = 2
+	after := habit.Progress(completions, now)
+	assert.Equal(t, 3, after.PeriodCount)
+	assert.Equal(t, 2, after.PeriodTarget)
+	assert.Equal(t, 100.0, after.PeriodPercent)
+	assert.Equal(t, 1, after.CurrentStreak)
+	assert.False(t, habit.IsDueOn(now, PeriodTally{Count: after.PeriodCount}))
+
+	// Extra completions earn no more than full credit for the week.
+	assert.Equal(t, 1.0, habit.CompletionRate(completions, 4, now))
+}
+
+func amountOn(at time.Time, amount float64) HabitCompletion {
+	return HabitCompletion{CompletedAt: at, Amount: &amount}
+}
+
+func TestHabitProgress_AmountReachesTarget(t *testing.T) {
+	target := 64.0
+	habit := &Habit{Frequency: "daily", TargetCount: 1, TargetAmount: &target}
+	now := time.Date(2025, 11, 13, 18, 0, 0, 0, time.UTC)
+
+	completions := []HabitCompletion{
+		amountOn(now.Add(-6*time.Hour), 16),
+		amountOn(now.Add(-3*time.Hour), 16),
+	}
+
+	// Two completions would meet a count target of 1, but only half the water is in.
+	partial := habit.Progress(completions, now)
+	assert.Equal(t, 2, partial.PeriodCount)
+	assert.Equal(t, 32.0, *partial.PeriodAmount)
+	assert.Equal(t, 64.0, *partial.PeriodTargetAmount)
+	assert.Equal(t, 50.0, partial.PeriodPercent)
+	assert.Equal(t, 0, partial.CurrentStreak)
+
+	completions = append(completions, amountOn(now.Add(-time.Hour), 32))
+	met := habit.Progress(completions, now)
+	assert.Equal(t, 64.0, *met.PeriodAmount)
+	assert.Equal(t, 100.0, met.PeriodPercent)
+	assert.Equal(t, 1, met.CurrentStreak)
+}
+
+func TestHabitProgress_AmountExceedsTarget(t *testing.T) {
+	target := 64.0
+	habit := &Habit{Frequency: "daily", TargetCount: 1, TargetAmount: &target}
+	now := time.Date(2025, 11, 13, 18, 0, 0, 0, time.UTC)
+
+	completions := []HabitCompletion{
+		amountOn(now.AddDate(0, 0, -1), 40),
+		amountOn(now.AddDate(0, 0, -1).Add(time.Hour), 40),
+		amountOn(now, 100),
+		// A completion without an amount adds nothing to an amount-based habit.
+		{CompletedAt: now.AddDate(0, 0, -2)},
+	}
+
+	progress := habit.Progress(completions, now)
+	assert.Equal(t, 100.0, *progress.PeriodAmount)
+	assert.Equal(t, 100.0, progress.PeriodPercent)
+	assert.Equal(t, 2, progress.CurrentStreak)
+	assert.Equal(t, 2, progress.LongestStreak)
+
+	// Overshooting a day earns no more than full credit for it.
+	habit.CreatedAt = now.AddDate(0, 0, -2)
+	assert.Equal(t, 0.67, habit.CompletionRate(completions, 30, now))
+}
+
+func TestHabitProgress_CountBasedIgnoresAmount(t *testing.T) {
+	habit := &Habit{Frequency: "daily", TargetCount: 2}
+	now := time.Date(2025, 11, 13, 18, 0, 0, 0, time.UTC)
+
+	progress := habit.Progress([]HabitCompletion{amountOn(now, 500)}, now)
+	assert.Equal(t, 1, progress.PeriodCount)
+	assert.Equal(t, 50.0, progress.PeriodPercent)
+	assert.Nil(t, progress.PeriodAmount)
+	assert.Nil(t, progress.PeriodTargetAmount)
+}
+
+func TestHabitProgress_StreakSurvivesPausedGap(t *testing.T) {
+	now := time.Date(2025, 11, 13, 18, 0, 0, 0, time.UTC)
+	pausedFrom := now.AddDate(0, 0, -5)
+	pausedUntil := now.AddDate(0, 0, -2)
+	habit := &Habit{Frequency: "daily", TargetCount: 1, PausedFrom: &pausedFrom, PausedUntil: &pausedUntil}
+
+	// Completed the three days before the pause and every day since.
+	completions := completionsOn(
+		now.AddDate(0, 0, -8),
+		now.AddDate(0, 0, -7),
+		now.AddDate(0, 0, -6),
+		now.AddDate(0, 0, -1),
+		now,
+	)
+
+	progress := habit.Progress(completions, now)
+	assert.Equal(t, 5, progress.CurrentStreak)
+	assert.Equal(t, 5, progress.LongestStreak)
+}
+
+func TestHabitProgress_UnpausedMissBreaksStreak(t *testing.T) {
+	now := time.Date(2025, 11, 13, 18, 0, 0, 0, time.UTC)
+	pausedFrom := now.AddDate(0, 0, -5)
+	pausedUntil := now.AddDate(0, 0, -3)
+	habit := &Habit{Frequency: "daily", TargetCount: 1, PausedFrom: &pausedFrom, PausedUntil: &pausedUntil}
+
+	// Day -2 is after the pause ended and was missed.
+	completions := completionsOn(
+		now.AddDate(0, 0, -7),
+		now.AddDate(0, 0, -6),
+		now.AddDate(0, 0, -1),
+		now,
+	)
+
+	progress := habit.Progress(completions, now)
+	assert.Equal(t, 2, progress.CurrentStreak)
+	assert.Equal(t, 2, progress.LongestStreak)
+}
+
+func TestHabitProgress_EveryPauseIsNeutral(t *testing.T) {
+	now := time.Date(2025, 11, 13, 18, 0, 0, 0, time.UTC)
+	day := func(offset int) time.Time { return now.AddDate(0, 0, offset) }
+	// Two separate pauses: days -10 to -8 and days -4 to -3. The second is
+	// the current window; the first only survives in the pause history.
+	pausedFrom, pausedUntil := day(-4), day(-3)
+	habit := &Habit{
+		Frequency:   "daily",
+		TargetCount: 1,
+		PausedFrom:  &pausedFrom,
+		PausedUntil: &pausedUntil,
+		Pauses: []HabitPause{
+			{From: day(-10), Until: day(-8)},
+			{From: day(-4), Until: day(-3)},
+		},
+	}
+
+	completions := completionsOn(day(-13), day(-12), day(-11), day(-7), day(-6), day(-5), day(-2), day(-1), now)
+
+	progress := habit.Progress(completions, now)
+	assert.Equal(t, 9, progress.CurrentStreak)
+	assert.Equal(t, 9, progress.LongestStreak)
+	// Both paused gaps drop out of the rate, so every counted day was met.
+	assert.Equal(t, 1.0, habit.CompletionRate(completions, 14, now))
+
+	// Without the history only the latest pause is neutral.
+	habit.Pauses = nil
+	assert.Equal(t, 6, habit.Progress(completions, now).CurrentStreak)
+	assert.Less(t, habit.CompletionRate(completions, 14, now), 1.0)
+}
+
+func TestHabitProgress_RestDayDoesNotBreakStreak(t *testing.T) {
+	now := time.Date(2025, 11, 13, 18, 0, 0, 0, time.UTC)
+	restDay := time.Date(2025, 11, 11, 0, 0, 0, 0, time.UTC)
+	habit := &Habit{Frequency: "daily", TargetCount: 1, RestDays: []time.Time{restDay}}
+
+	// Day -2 was logged as a rest day and skipped.
+	completions := completionsOn(
+		now.AddDate(0, 0, -4),
+		now.AddDate(0, 0, -3),
+		now.AddDate(0, 0, -1),
+		now,
+	)
+
+	progress := habit.Progress(completions, now)
+	assert.Equal(t, 4, progress.CurrentStreak)
+	assert.Equal(t, 4, progress.LongestStreak)
+
+	habit.RestDays = nil
+	progress = habit.Progress(completions, now)
+	assert.Equal(t, 2, progress.CurrentStreak)
+}
+
+func TestHabitProgress_RestDayDoesNotExcuseWeek(t *testing.T) {
+	now := time.Date(2025, 11, 13, 18, 0, 0, 0, time.UTC)
+	restDay := time.Date(2025, 11, 5, 0, 0, 0, 0, time.UTC)
+	habit := &Habit{Frequency: "weekly", TargetCount: 1, RestDays: []time.Time{restDay}}
+
+	// The week containing the rest day was missed.
+	completions := completionsOn(now.AddDate(0, 0, -14), now)
+
+	progress := habit.Progress(completions, now)
+	assert.Equal(t, 1, progress.CurrentStreak)
+}
+
+func TestHabit_CompletionRate_SkipsRestDays(t *testing.T) {
+	now := time.Date(2025, 11, 13, 18, 0, 0, 0, time.UTC)
+	habit := &Habit{
+		Frequency:   "daily",
+		TargetCount: 1,
+		RestDays:    []time.Time{time.Date(2025, 11, 12, 0, 0, 0, 0, time.UTC)},
+	}
+
+	// Missed day -3, done on day -2; day -1 was a rest day and today is in progress.
+	assert.Equal(t, 0.5, habit.CompletionRate(completionsOn(now.AddDate(0, 0, -2)), 4, now))
+}
+
+func TestHabit_FreezeBridgesOneMissedPeriod(t *testing.T) {
+	now := time.Date(2025, 11, 13, 18, 0, 0, 0, time.UTC)
+	habit := &Habit{Frequency: "daily", TargetCount: 1}
+
+	// Day -2 was missed.
+	completions := completionsOn(
+		now.AddDate(0, 0, -4),
+		now.AddDate(0, 0, -3),
+		now.AddDate(0, 0, -1),
+		now,
+	)
+	assert.Equal(t, 2, habit.Progress(completions, now).CurrentStreak)
+
+	period, ok := habit.FreezablePeriod(completions, now)
+	if assert.True(t, ok) {
+		assert.Equal(t, time.Date(2025, 11, 11, 0, 0, 0, 0, time.UTC), period)
+	}
+
+	habit.FrozenPeriods = []time.Time{period}
+	assert.Equal(t, 4, habit.Progress(completions, now).CurrentStreak)
+
+	// Nothing left to bridge once the gap is frozen.
+	_, ok = habit.FreezablePeriod(completions, now)
+	assert.False(t, ok)
+}
+
+func TestHabit_EarnedStreakFreeze_AtMilestone(t *testing.T) {
+	now := time.Date(2025, 11, 13, 18, 0, 0, 0, time.UTC)
+	habit := &Habit{Frequency: "daily", TargetCount: 1}
+
+	var sixDays []time.Time
+	for i := -6; i < 0; i++ {
+		sixDays = append(sixDays, now.AddDate(0, 0, i))
+	}
+	earlier := completionsOn(sixDays...)
+
+	// The seventh day in a row earns a token, keyed by today.
+	period, ok := habit.EarnedStreakFreeze(earlier, HabitCompletion{CompletedAt: now}, now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2025, 11, 13, 0, 0, 0, 0, time.UTC), period)
+
+	// A sixth day doesn't, and neither does an eighth.
+	_, ok = habit.EarnedStreakFreeze(earlier[1:], HabitCompletion{CompletedAt: now}, now)
+	assert.False(t, ok)
+	eighth := completionsOn(append([]time.Time{now.AddDate(0, 0, -7)}, sixDays...)...)
+	_, ok = habit.EarnedStreakFreeze(eighth, HabitCompletion{CompletedAt: now}, now)
+	assert.False(t, ok)
+
+	// A second completion on an already met day adds nothing to the streak.
+	_, ok = habit.EarnedStreakFreeze(append(earlier, HabitCompletion{CompletedAt: now}), HabitCompletion{CompletedAt: now}, now)
+	assert.False(t, ok)
+}
+
+func TestHabit_EarnedStreakFreeze_BackfillReachingMilestone(t *testing.T) {
+	now := time.Date(2025, 11, 13, 18, 0, 0, 0, time.UTC)
+	habit := &Habit{Frequency: "daily", TargetCount: 1}
+
+	// Every day of the last nine but day -4, so the streak is 4.
+	var days []time.Time
+	for i := -8; i <= 0; i++ {
+		if i != -4 {
+			days = append(days, now.AddDate(0, 0, i))
+		}
+	}
+
+	// Backfilling day -4 joins the streaks into one of 9, past 7.
+	period, ok := habit.EarnedStreakFreeze(completionsOn(days...), HabitCompletion{CompletedAt: now.AddDate(0, 0, -4)}, now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2025, 11, 9, 0, 0, 0, 0, time.UTC), period)
+}
+
+func TestHabit_EarnedStreakFreezeBridgesLaterMiss(t *testing.T) {
+	now := time.Date(2025, 11, 13, 18, 0, 0, 0, time.UTC)
+	habit := &Habit{Frequency: "daily", TargetCount: 1}
+
+	// A week in a row up to day -2 earns a token on day -2.
+	var week []time.Time
+	for i := -8; i < -2; i++ {
+		week = append(week, now.AddDate(0, 0, i))
+	}
+	loggedAt := now.AddDate(0, 0, -2)
+	_, ok := habit.EarnedStreakFreeze(completionsOn(week...), HabitCompletion{CompletedAt: loggedAt}, loggedAt)
+	assert.True(t, ok)
+
+	// Day -1 is missed; spending the token on it keeps the streak going.
+	completions := completionsOn(append(week, loggedAt, now)...)
+	period, ok := habit.FreezablePeriod(completions, now)
+	assert.True(t, ok)
+	habit.FrozenPeriods = append(habit.FrozenPeriods, period)
+	assert.Equal(t, 8, habit.Progress(completions, now).CurrentStreak)
+}
+
+func TestHabit_FreezeCannotBridgeTwoMissedPeriods(t *testing.T) {
+	now := time.Date(2025, 11, 13, 18, 0, 0, 0, time.UTC)
+	habit := &Habit{Frequency: "daily", TargetCount: 1}
+
+	// Days -3 and -2 were both missed.
+	completions := completionsOn(
+		now.AddDate(0, 0, -5),
+		now.AddDate(0, 0, -4),
+		now.AddDate(0, 0, -1),
+		now,
+	)
+
+	_, ok := habit.FreezablePeriod(completions, now)
+	assert.False(t, ok)
+
+	// Freezing one of the two days still leaves the streak broken.
+	habit.FrozenPeriods = []time.Time{time.Date(2025, 11, 11, 0, 0, 0, 0, time.UTC)}
+	assert.Equal(t, 2, habit.Progress(completions, now).CurrentStreak)
+}
+
+func TestHabit_FreezeOnlyBridgesMostRecentGap(t *testing.T) {
+	now := time.Date(2025, 11, 13, 18, 0, 0, 0, time.UTC)
+	habit := &Habit{Frequency: "daily", TargetCount: 1}
+
+	// Days -4 and -2 were missed, each on its own.
+	completions := completionsOn(
+		now.AddDate(0, 0, -5),
+		now.AddDate(0, 0, -3),
+		now.AddDate(0, 0, -1),
+		now,
+	)
+
+	period, ok := habit.FreezablePeriod(completions, now)
+	if assert.True(t, ok) {
+		assert.Equal(t, time.Date(2025, 11, 11, 0, 0, 0, 0, time.UTC), period)
+	}
+
+	habit.FrozenPeriods = []time.Time{period}
+	assert.Equal(t, 3, habit.Progress(completions, now).CurrentStreak)
+}
+
+func TestHabit_FreezeNeedsAStreakToProtect(t *testing.T) {
+	now := time.Date(2025, 11, 13, 18, 0, 0, 0, time.UTC)
+	habit := &Habit{Frequency: "daily", TargetCount: 1}
+
+	_, ok := habit.FreezablePeriod(nil, now)
+	assert.False(t, ok)
+
+	_, ok = habit.FreezablePeriod(completionsOn(now), now)
+	assert.False(t, ok)
+}
+
+func TestHabit_Pause(t *testing.T) {
+	now := time.Date(2025, 11, 13, 18, 0, 0, 0, time.UTC)
+	habit := &Habit{Frequency: "daily", TargetCount: 1}
+
+	assert.ErrorIs(t, habit.Pause(now.Add(-time.Hour), now), ErrPauseInPast)
+
+	assert.NoError(t, habit.Pause(now.AddDate(0, 0, 3), now))
+	assert.True(t, habit.IsPausedAt(now.AddDate(0, 0, 1)))
+
+	// Extending an active pause keeps the original start.
+	later := now.AddDate(0, 0, 1)
+	assert.NoError(t, habit.Pause(now.AddDate(0, 0, 7), later))
+	assert.True(t, habit.PausedFrom.Equal(now))
+	assert.True(t, habit.PausedUntil.Equal(now.AddDate(0, 0, 7)))
+	assert.Equal(t, []HabitPause{{From: now, Until: now.AddDate(0, 0, 7)}}, habit.Pauses)
+
+	// A pause after the last one ended starts a new window in the history.
+	again := now.AddDate(0, 0, 10)
+	assert.NoError(t, habit.Pause(again.AddDate(0, 0, 2), again))
+	assert.Len(t, habit.Pauses, 2)
+	assert.True(t, habit.Pauses[1].From.Equal(again))
+}
+
+func TestHabit_IsDueOn(t *testing.T) {
+	// 2025-11-13 is a Thursday.
+	thursday := time.Date(2025, 11, 13, 0, 0, 0, 0, time.UTC)
+	monday := time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC)
+	monthEnd := time.Date(2025, 11, 30, 0, 0, 0, 0, time.UTC)
+
+	daily := &Habit{Frequency: "daily", TargetCount: 1, IsActive: true}
+	weekly := &Habit{Frequency: "weekly", TargetCount: 3, IsActive: true}
+	monthly := &Habit{Frequency: "monthly", TargetCount: 1, IsActive: true}
+
+	cases := []struct {
+		name        string
+		habit       *Habit
+		date        time.Time
+		periodCount int
+		due         bool
+	}{
+		{"daily with nothing done", daily, thursday, 0, true},
+		{"daily already done today", daily, thursday, 1, true},
+		{"daily on a monday", daily, monday, 0, true},
+		{"weekly at start of week", weekly, monday, 0, true},
+		{"weekly partway to target", weekly, thursday, 2, true},
+		{"weekly target met", weekly, thursday, 3, false},
+		{"monthly not yet done", monthly, monthEnd, 0, true},
+		{"monthly done this month", monthly, thursday, 1, false},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.due, tc.habit.IsDueOn(tc.date, PeriodTally{Count: tc.periodCount}), tc.name)
+	}
+}
+
+func TestHabit_IsDueOn_InactiveOrPaused(t *testing.T) {
+	day := time.Date(2025, 11, 13, 0, 0, 0, 0, time.UTC)
+
+	inactive := &Habit{Frequency: "daily", TargetCount: 1}
+	assert.False(t, inactive.IsDueOn(day, PeriodTally{}))
+
+	// Paused from mid-morning still covers the day.
+	from := day.Add(10 * time.Hour)
+	until := day.AddDate(0, 0, 3)
+	paused := &Habit{Frequency: "daily", TargetCount: 1, IsActive: true, PausedFrom: &from, PausedUntil: &until}
+	assert.False(t, paused.IsDueOn(day, PeriodTally{}))
+	assert.True(t, paused.IsDueOn(day.AddDate(0, 0, -1), PeriodTally{}))
+	assert.True(t, paused.IsDueOn(day.AddDate(0, 0, 4), PeriodTally{}))
+}
+
+func TestIncompleteOn_ExcludesCompletedAndShowsRemaining(t *testing.T) {
+	thursday := time.Date(2025, 11, 13, 20, 0, 0, 0, time.UTC)
+
+	done := Habit{ID: uuid.New(), Name: "Meditate", Frequency: "daily", TargetCount: 1, IsActive: true}
+	partial := Habit{ID: uuid.New(), Name: "Water", Frequency: "daily", TargetCount: 8, IsActive: true}
+	untouched := Habit{ID: uuid.New(), Name: "Read", Frequency: "daily", TargetCount: 1, IsActive: true}
+	weeklyMet := Habit{ID: uuid.New(), Name: "Gym", Frequency: "weekly", TargetCount: 3, IsActive: true}
+	weeklyShort := Habit{ID: uuid.New(), Name: "Call family", Frequency: "weekly", TargetCount: 2, IsActive: true}
+	over := Habit{ID: uuid.New(), Name: "Stretch", Frequency: "daily", TargetCount: 1, IsActive: true}
+
+	incomplete := IncompleteOn(
+		[]Habit{done, partial, untouched, weeklyMet, weeklyShort, over},
+		[]PeriodTally{{Count: 1}, {Count: 5}, {Count: 0}, {Count: 3}, {Count: 1}, {Count: 2}},
+		thursday,
+	)
+
+	if assert.Len(t, incomplete, 3) {
+		assert.Equal(t, partial.ID, incomplete[0].ID)
+		assert.Equal(t, 5, incomplete[0].PeriodCount)
+		assert.Equal(t, 8, incomplete[0].PeriodTarget)
+		assert.Equal(t, 3.0, incomplete[0].Remaining)
+		assert.Nil(t, incomplete[0].PeriodAmount)
+
+		assert.Equal(t, untouched.ID, incomplete[1].ID)
+		assert.Equal(t, 1.0, incomplete[1].Remaining)
+
+		assert.Equal(t, weeklyShort.ID, incomplete[2].ID)
+		assert.Equal(t, 1.0, incomplete[2].Remaining)
+	}
+}
+
+func TestHabit_IsDueOn_AmountBased(t *testing.T) {
+	thursday := time.Date(2025, 11, 13, 0, 0, 0, 0, time.UTC)
+	target := 10.0
+	weekly := &Habit{Frequency: "weekly", TargetCount: 1, TargetAmount: &target, IsActive: true}
+
+	// One completion meets TargetCount but not the amount.
+	assert.True(t, weekly.IsDueOn(thursday, PeriodTally{Count: 1, Amount: 4}))
+	assert.False(t, weekly.IsDueOn(thursday, PeriodTally{Count: 2, Amount: 10}))
+}
+
+func TestIncompleteOn_AmountBasedRemainingIsAnAmount(t *testing.T) {
+	thursday := time.Date(2025, 11, 13, 20, 0, 0, 0, time.UTC)
+	target := 2000.0
+	water := Habit{ID: uuid.New(), Name: "Water", Frequency: "daily", TargetCount: 1, TargetAmount: &target, IsActive: true}
+	run := Habit{ID: uuid.New(), Name: "Run", Frequency: "weekly", TargetCount: 1, TargetAmount: &target, IsActive: true}
+
+	incomplete := IncompleteOn([]Habit{water, run}, []PeriodTally{{Count: 1, Amount: 500}, {Count: 3, Amount: 2500}}, thursday)
+
+	if assert.Len(t, incomplete, 1) {
+		assert.Equal(t, water.ID, incomplete[0].ID)
+		assert.Equal(t, 1, incomplete[0].PeriodCount)
+		assert.Equal(t, 1500.0, incomplete[0].Remaining)
+		assert.Equal(t, 500.0, *incomplete[0].PeriodAmount)
+		assert.Equal(t, 2000.0, *incomplete[0].PeriodTargetAmount)
+	}
+}
+
+func TestHabit_Remaining_PausedIsNotDue(t *testing.T) {
+	day := time.Date(2025, 11, 13, 20, 0, 0, 0, time.UTC)
+	from := day.AddDate(0, 0, -1)
+	until := day.AddDate(0, 0, 2)
+	paused := &Habit{Frequency: "daily", TargetCount: 2, IsActive: true, PausedFrom: &from, PausedUntil: &until}
+
+	assert.Equal(t, 0.0, paused.Remaining(day, PeriodTally{}))
+	assert.Empty(t, IncompleteOn([]Habit{*paused}, []PeriodTally{{}}, day))
+}
+
+func TestHabit_PeriodBounds(t *testing.T) {
+	thursday := time.Date(2025, 11, 13, 15, 0, 0, 0, time.UTC)
+
+	start, end := (&Habit{Frequency: "weekly"}).PeriodBounds(thursday)
+	assert.Equal(t, time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2025, 11, 17, 0, 0, 0, 0, time.UTC), end)
+
+	start, end = (&Habit{Frequency: "monthly"}).PeriodBounds(thursday)
+	assert.Equal(t, time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC), end)
+
+	start, end = (&Habit{Frequency: "daily"}).PeriodBounds(thursday)
+	assert.Equal(t, time.Date(2025, 11, 13, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2025, 11, 14, 0, 0, 0, 0, time.UTC), end)
+}
+
+func TestHabit_CompletionRate(t *testing.T) {
+	now := time.Date(2025, 11, 30, 18, 0, 0, 0, time.UTC)
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	daily := &Habit{Frequency: "daily", TargetCount: 1, CreatedAt: created}
+	var done []time.Time
+	for day := 1; day <= 29; day += 2 { // 15 of the 29 finished days of November
+		done = append(done, time.Date(2025, 11, day, 9, 0, 0, 0, time.UTC))
+	}
+	assert.Equal(t, 0.52, daily.CompletionRate(completionsOn(done...), 30, now))
+
+	// Meeting today counts; not meeting it yet doesn't.
+	withToday := append(completionsOn(done...), HabitCompletion{CompletedAt: now})
+	assert.Equal(t, 0.53, daily.CompletionRate(withToday, 30, now))
+
+	// Weekly target 2: partial weeks earn partial credit, extra completions don't overflow.
+	weekly := &Habit{Frequency: "weekly", TargetCount: 2, CreatedAt: created}
+	weeklyDone := completionsOn(
+		time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC),  // week of Nov 3: 1/2
+		time.Date(2025, 11, 10, 9, 0, 0, 0, time.UTC), // week of Nov 10: 3/2
+		time.Date(2025, 11, 11, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC),
+	)
+	// Window starts Nov 1 (week of Oct 27, 0/2); the current week is unmet and skipped.
+	assert.Equal(t, 0.38, weekly.CompletionRate(weeklyDone, 30, now))
+}
+
+func TestHabit_CompletionRate_NewHabit(t *testing.T) {
+	now := time.Date(2025, 11, 30, 18, 0, 0, 0, time.UTC)
+
+	fresh := &Habit{Frequency: "daily", TargetCount: 1, CreatedAt: now.Add(-time.Hour)}
+	assert.Equal(t, 0.0, fresh.CompletionRate(nil, 30, now))
+
+	// Created three days ago and done every day since: the window is clipped
+	// to the creation date instead of counting the days before it as misses.
+	young := &Habit{Frequency: "daily", TargetCount: 1, CreatedAt: now.AddDate(0, 0, -3)}
+	assert.Equal(t, 1.0, young.CompletionRate(completionsOn(
+		now.AddDate(0, 0, -3), now.AddDate(0, 0, -2), now.AddDate(0, 0, -1),
+	), 30, now))
+}
+
+func completionsFor(habitID uuid.UUID, days ...time.Time) []HabitCompletion {
+	completions := completionsOn(days...)
+	for i := range completions {
+		completions[i].HabitID = habitID
+	}
+	return completions
+}
+
+func TestHabitProgress_StreakRestartsFromReset(t *testing.T) {
+	now := time.Date(2025, 11, 13, 18, 0, 0, 0, time.UTC)
+	resetAt := time.Date(2025, 11, 11, 9, 0, 0, 0, time.UTC)
+	habit := &Habit{Frequency: "daily", TargetCount: 1, StreakResetAt: &resetAt}
+
+	// Ten straight days up to today, reset on the morning of day -2 after
+	// that day's completion was already logged.
+	var days []time.Time
+	for daysAgo := 9; daysAgo >= 0; daysAgo-- {
+		days = append(days, time.Date(2025, 11, 13-daysAgo, 8, 0, 0, 0, time.UTC))
+	}
+	completions := completionsOn(days...)
+
+	progress := habit.Progress(completions, now)
+	assert.Equal(t, 2, progress.CurrentStreak)
+	assert.Equal(t, 2, progress.LongestStreak)
+	assert.Equal(t, 1, progress.PeriodCount)
+
+	// History is untouched: clearing the reset restores the full run.
+	habit.StreakResetAt = nil
+	progress = habit.Progress(completions, now)
+	assert.Equal(t, 10, progress.CurrentStreak)
+	assert.Equal(t, 10, progress.LongestStreak)
+}
+
+func TestHabitProgress_ResetTodayKeepsCurrentPeriodCount(t *testing.T) {
+	now := time.Date(2025, 11, 13, 18, 0, 0, 0, time.UTC)
+	resetAt := time.Date(2025, 11, 13, 12, 0, 0, 0, time.UTC)
+	habit := &Habit{Frequency: "weekly", TargetCount: 3, StreakResetAt: &resetAt}
+
+	completions := completionsOn(
+		time.Date(2025, 11, 3, 8, 0, 0, 0, time.UTC),
+		time.Date(2025, 11, 4, 8, 0, 0, 0, time.UTC),
+		time.Date(2025, 11, 5, 8, 0, 0, 0, time.UTC),
+		time.Date(2025, 11, 10, 8, 0, 0, 0, time.UTC),
+		time.Date(2025, 11, 11, 8, 0, 0, 0, time.UTC),
+		time.Date(2025, 11, 12, 8, 0, 0, 0, time.UTC),
+	)
+
+	progress := habit.Progress(completions, now)
+	assert.Equal(t, 0, progress.CurrentStreak)
+	assert.Equal(t, 0, progress.LongestStreak)
+	assert.Equal(t, 3, progress.PeriodCount)
+}
+
+func TestHabitProgress_NeutralPeriodsBeforeResetDontCarryStreak(t *testing.T) {
+	now := time.Date(2025, 11, 13, 18, 0, 0, 0, time.UTC)
+	resetAt := time.Date(2025, 11, 12, 0, 0, 0, 0, time.UTC)
+	habit := &Habit{
+		Frequency:     "daily",
+		TargetCount:   1,
+		StreakResetAt: &resetAt,
+		FrozenPeriods: []time.Time{time.Date(2025, 11, 11, 0, 0, 0, 0, time.UTC)},
+	}
+
+	completions := completionsOn(
+		time.Date(2025, 11, 10, 8, 0, 0, 0, time.UTC),
+		time.Date(2025, 11, 12, 8, 0, 0, 0, time.UTC),
+		now,
+	)
+
+	assert.Equal(t, 2, habit.Progress(completions, now).CurrentStreak)
+}
+
+func TestHabit_FreezeCannotBridgeGapBeforeReset(t *testing.T) {
+	now := time.Date(2025, 11, 13, 18, 0, 0, 0, time.UTC)
+	resetAt := time.Date(2025, 11, 12, 0, 0, 0, 0, time.UTC)
+	habit := &Habit{Frequency: "daily", TargetCount: 1, StreakResetAt: &resetAt}
+
+	// Day -2 was missed, but the streak was reset the day after.
+	completions := completionsOn(
+		now.AddDate(0, 0, -3),
+		now.AddDate(0, 0, -1),
+		now,
+	)
+
+	_, ok := habit.FreezablePeriod(completions, now)
+	assert.False(t, ok)
+}
+
+func TestHabitStreaks_PerHabitAndOrdered(t *testing.T) {
+	now := time.Date(2025, 11, 13, 18, 0, 0, 0, time.UTC)
+	read := Habit{ID: uuid.New(), Name: "Read", Frequency: "daily", TargetCount: 1}
+	run := Habit{ID: uuid.New(), Name: "Run", Frequency: "daily", TargetCount: 1}
+	stretch := Habit{ID: uuid.New(), Name: "Stretch", Frequency: "daily", TargetCount: 1}
+	journal := Habit{ID: uuid.New(), Name: "Journal", Frequency: "daily", TargetCount: 1}
+
+	var completions []HabitCompletion
+	// Read: a 4-day run that ended a week ago, then 1 day today.
+	completions = append(completions, completionsFor(read.ID,
+		now.AddDate(0, 0, -10), now.AddDate(0, 0, -9), now.AddDate(0, 0, -8), now.AddDate(0, 0, -7), now)...)
+	// Run: 3 days in a row up to today.
+	completions = append(completions, completionsFor(run.ID,
+		now.AddDate(0, 0, -2), now.AddDate(0, 0, -1), now)...)
+	// Stretch: same current streak as Read but a shorter best.
+	completions = append(completions, completionsFor(stretch.ID, now.AddDate(0, 0, -1))...)
+	// Journal: never completed.
+
+	streaks := HabitStreaks([]Habit{read, run, stretch, journal}, completions, now)
+
+	if !assert.Len(t, streaks, 4) {
+		return
+	}
+	assert.Equal(t, []string{"Run", "Read", "Stretch", "Journal"},
+		[]string{streaks[0].Name, streaks[1].Name, streaks[2].Name, streaks[3].Name})
+
+	assert.Equal(t, run.ID, streaks[0].HabitID)
+	assert.Equal(t, 3, streaks[0].CurrentStreak)
+	assert.Equal(t, 3, streaks[0].LongestStreak)
+
+	assert.Equal(t, 1, streaks[1].CurrentStreak)
+	assert.Equal(t, 4, streaks[1].LongestStreak)
+
+	assert.Equal(t, 1, streaks[2].CurrentStreak)
+	assert.Equal(t, 1, streaks[2].LongestStreak)
+
+	assert.Equal(t, 0, streaks[3].CurrentStreak)
+	assert.Equal(t, 0, streaks[3].LongestStreak)
+}
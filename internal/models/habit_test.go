@@ -0,0 +1,170 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateHabitRequest_Changes(t *testing.T) {
+	habit := &Habit{Name: "Read", Color: "#FF5733", Icon: "book", Frequency: "daily", TargetCount: 1, IsActive: true}
+
+	name := "Read"
+	frequency := "weekly"
+	active := true
+	req := UpdateHabitRequest{Name: &name, Frequency: &frequency, IsActive: &active}
+
+	assert.Equal(t, []string{"frequency"}, req.Changes(habit))
+}
+
+func TestUpdateHabitRequest_ChangesNoOp(t *testing.T) {
+	habit := &Habit{Name: "Read", Color: "#FF5733", Icon: "book", Frequency: "daily", TargetCount: 1}
+
+	name := "Read"
+	target := 1
+	req := UpdateHabitRequest{Name: &name, TargetCount: &target}
+
+	changes := req.Changes(habit)
+	assert.NotNil(t, changes)
+	assert.Empty(t, changes)
+	assert.Empty(t, (&UpdateHabitRequest{}).Changes(habit))
+}
+
+func TestUpdateHabitRequest_ChangesTargetAmount(t *testing.T) {
+	amount := 2000.0
+	countBased := &Habit{Frequency: "daily", TargetCount: 1}
+	amountBased := &Habit{Frequency: "daily", TargetCount: 1, TargetAmount: &amount}
+
+	var set, cleared, omitted UpdateHabitRequest
+	assert.NoError(t, json.Unmarshal([]byte(`{"target_amount": 2000}`), &set))
+	assert.NoError(t, json.Unmarshal([]byte(`{"target_amount": null}`), &cleared))
+	assert.NoError(t, json.Unmarshal([]byte(`{}`), &omitted))
+
+	assert.Equal(t, []string{"target_amount"}, set.Changes(countBased))
+	assert.Empty(t, set.Changes(amountBased))
+	assert.Equal(t, []string{"target_amount"}, cleared.Changes(amountBased))
+	assert.Empty(t, cleared.Changes(countBased))
+	assert.Empty(t, omitted.Changes(amountBased))
+
+	assert.True(t, cleared.TargetAmount.Set)
+	assert.Nil(t, cleared.TargetAmount.Value)
+	assert.False(t, omitted.TargetAmount.Set)
+}
+
+func TestReorderHabitsRequest_Validate(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+
+	valid := ReorderHabitsRequest{HabitIDs: []uuid.UUID{a, b}}
+	assert.NoError(t, valid.Validate())
+
+	dup := ReorderHabitsRequest{HabitIDs: []uuid.UUID{a, b, a}}
+	assert.ErrorIs(t, dup.Validate(), ErrInvalidHabitOrder)
+}
+
+func TestValidateReminderTimes(t *testing.T) {
+	assert.NoError(t, ValidateReminderTimes([]string{"00:00", "09:30", "23:59"}))
+	assert.NoError(t, ValidateReminderTimes([]string{}))
+	assert.NoError(t, ValidateReminderTimes(nil))
+
+	for _, invalid := range []string{"25:00", "24:00", "9:00", "09:60", "0900", "09:00pm", ""} {
+		assert.ErrorIs(t, ValidateReminderTimes([]string{invalid}), ErrInvalidReminderTime, invalid)
+	}
+
+	assert.ErrorIs(t, ValidateReminderTimes([]string{"08:00", "20:00", "08:00"}), ErrDuplicateReminder)
+
+	tooMany := make([]string, MaxReminderTimes+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("%02d:00", i)
+	}
+	assert.ErrorIs(t, ValidateReminderTimes(tooMany), ErrTooManyReminders)
+	assert.NoError(t, ValidateReminderTimes(tooMany[:MaxReminderTimes]))
+}
+
+func TestShiftReminderTimes(t *testing.T) {
+	shifted, err := ShiftReminderTimes([]string{"07:00", "12:45", "21:30"}, 30*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"07:30", "13:15", "22:00"}, shifted)
+
+	shifted, err = ShiftReminderTimes([]string{"08:00", "20:15"}, -(2*time.Hour + 15*time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"05:45", "18:00"}, shifted)
+
+	shifted, err = ShiftReminderTimes(nil, time.Hour)
+	assert.NoError(t, err)
+	assert.Empty(t, shifted)
+}
+
+func TestShiftReminderTimes_WrapsAroundMidnight(t *testing.T) {
+	shifted, err := ShiftReminderTimes([]string{"23:30", "22:00", "00:10"}, 45*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"00:15", "22:45", "00:55"}, shifted)
+
+	shifted, err = ShiftReminderTimes([]string{"00:15", "06:00"}, -30*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"23:45", "05:30"}, shifted)
+
+	shifted, err = ShiftReminderTimes([]string{"00:00"}, 23*time.Hour+59*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"23:59"}, shifted)
+
+	shifted, err = ShiftReminderTimes([]string{"23:59"}, -(23*time.Hour + 59*time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"00:00"}, shifted)
+}
+
+func TestShiftReminderTimes_RejectsInvalidStoredTimes(t *testing.T) {
+	_, err := ShiftReminderTimes([]string{"08:00", "25:00"}, time.Hour)
+	assert.ErrorIs(t, err, ErrInvalidReminderTime)
+
+	_, err = ShiftReminderTimes([]string{"08:00", "08:00"}, time.Hour)
+	assert.ErrorIs(t, err, ErrDuplicateReminder)
+}
+
+func TestShiftRemindersRequest_ParseOffset(t *testing.T) {
+	for input, want := range map[string]time.Duration{
+		"+30m":   30 * time.Minute,
+		"30m":    30 * time.Minute,
+		"-1h":    -time.Hour,
+		"+1h30m": 90 * time.Minute,
+		"23h59m": 23*time.Hour + 59*time.Minute,
+	} {
+		offset, err := (&ShiftRemindersRequest{Offset: input}).ParseOffset()
+		if assert.NoError(t, err, input) {
+			assert.Equal(t, want, offset, input)
+		}
+	}
+
+	for _, invalid := range []string{"0m", "+24h", "-24h", "90s", "1h30m15s", "soon", "30"} {
+		_, err := (&ShiftRemindersRequest{Offset: invalid}).ParseOffset()
+		assert.ErrorIs(t, err, ErrInvalidReminderShift, invalid)
+	}
+}
+
+func TestHabitValidate_ChecksReminderTimes(t *testing.T) {
+	habit := &Habit{Frequency: "daily", TargetCount: 1, ReminderTimes: []string{"07:15"}}
+	assert.NoError(t, habit.Validate())
+
+	habit.ReminderTimes = []string{"25:00"}
+	assert.ErrorIs(t, habit.Validate(), ErrInvalidReminderTime)
+}
+
+func TestHabitRemindersBetween(t *testing.T) {
+	habit := &Habit{ReminderTimes: []string{"00:00", "09:00", "23:59"}}
+	loc := time.FixedZone("UTC-5", -5*60*60)
+
+	due := habit.RemindersBetween(time.Date(2026, 10, 16, 8, 59, 0, 0, loc), time.Date(2026, 10, 16, 9, 0, 0, 0, loc))
+	if assert.Len(t, due, 1) {
+		assert.Equal(t, time.Date(2026, 10, 16, 9, 0, 0, 0, loc), due[0])
+	}
+
+	// A window spanning midnight picks up times on both days.
+	due = habit.RemindersBetween(time.Date(2026, 10, 16, 23, 58, 0, 0, loc), time.Date(2026, 10, 17, 0, 0, 0, 0, loc))
+	assert.Len(t, due, 2)
+
+	// The window is open at its start.
+	assert.Empty(t, habit.RemindersBetween(time.Date(2026, 10, 16, 9, 0, 0, 0, loc), time.Date(2026, 10, 16, 9, 1, 0, 0, loc)))
+}
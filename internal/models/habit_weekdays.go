@@ -0,0 +1,41 @@
+package models
+
+// WeekdayCount is the number of completions logged on one local day of the
+// week, 0 (Sunday) through 6 (Saturday), as Postgres's EXTRACT(DOW) numbers
+// them.
+type WeekdayCount struct {
+	Weekday int `json:"weekday"`
+	Count   int `json:"count"`
+}
+
+// HabitWeekdays is the day-of-week distribution of a habit's completions in
+// the user's timezone, Sunday first. BestWeekday is the day with the most
+// completions, the earliest in the week on a tie, and nil when there are none
+// in the range.
+type HabitWeekdays struct {
+	Timezone     string `json:"timezone"`
+	Distribution [7]int `json:"distribution"`
+	BestWeekday  *int   `json:"best_weekday"`
+	Completions  int    `json:"completions"`
+}
+
+// NewHabitWeekdays builds the distribution from per-weekday counts, ignoring
+// days outside 0-6.
+func NewHabitWeekdays(timezone string, counts []WeekdayCount) HabitWeekdays {
+	weekdays := HabitWeekdays{Timezone: timezone}
+	for _, count := range counts {
+		if count.Weekday < 0 || count.Weekday > 6 {
+			continue
+		}
+		weekdays.Distribution[count.Weekday] += count.Count
+		weekdays.Completions += count.Count
+	}
+
+	for day, count := range weekdays.Distribution {
+		if count > 0 && (weekdays.BestWeekday == nil || count > weekdays.Distribution[*weekdays.BestWeekday]) {
+			weekdays.BestWeekday = &day
+		}
+	}
+
+	return weekdays
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHabitWeekdays_PicksModalDay(t *testing.T) {
+	weekdays := NewHabitWeekdays("America/New_York", []WeekdayCount{
+		{Weekday: 1, Count: 4},
+		{Weekday: 3, Count: 6},
+		{Weekday: 6, Count: 1},
+	})
+
+	assert.Equal(t, "America/New_York", weekdays.Timezone)
+	assert.Equal(t, 11, weekdays.Completions)
+	assert.Equal(t, [7]int{0, 4, 0, 6, 0, 0, 1}, weekdays.Distribution)
+	if assert.NotNil(t, weekdays.BestWeekday) {
+		assert.Equal(t, 3, *weekdays.BestWeekday)
+	}
+}
+
+func TestNewHabitWeekdays_TieGoesToEarliestDay(t *testing.T) {
+	weekdays := NewHabitWeekdays("UTC", []WeekdayCount{{Weekday: 5, Count: 2}, {Weekday: 0, Count: 2}})
+
+	if assert.NotNil(t, weekdays.BestWeekday) {
+		assert.Equal(t, 0, *weekdays.BestWeekday)
+	}
+}
+
+func TestNewHabitWeekdays_EmptyAndOutOfRange(t *testing.T) {
+	weekdays := NewHabitWeekdays("UTC", []WeekdayCount{{Weekday: 7, Count: 3}, {Weekday: -1, Count: 1}})
+
+	assert.Nil(t, weekdays.BestWeekday)
+	assert.Equal(t, 0, weekdays.Completions)
+	assert.Equal(t, [7]int{}, weekdays.Distribution)
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"fmt"
+	"math"
+)
+
+// MinCorrelationSamples is the fewest days with both figures logged that a
+// correlation is computed from.
+const MinCorrelationSamples = 3
+
+// SleepProductivity is the Pearson correlation between hours slept and the
+// productivity rating over the days that have both. Coefficient is null when
+// there are too few such days or either figure never varies.
+type SleepProductivity struct {
+	Coefficient    *float64 `json:"coefficient"`
+	SampleSize     int      `json:"sample_size"`
+	Interpretation string   `json:"interpretation"`
+}
+
+// NewSleepProductivity correlates sleep with productivity across logs. Logs
+// missing either figure are skipped, as are rest days, which don't expect a
+// productivity rating.
+func NewSleepProductivity(logs []DailyLog) SleepProductivity {
+	var sleep, productivity []float64
+	for _, log := range logs {
+		if log.IsRestDay || log.SleepHours <= 0 || log.ProductivityRating == 0 {
+			continue
+		}
+		sleep = append(sleep, log.SleepHours)
+		productivity = append(productivity, float64(log.ProductivityRating))
+	}
+
+	result := SleepProductivity{SampleSize: len(sleep)}
+	if len(sleep) < MinCorrelationSamples {
+		result.Interpretation = fmt.Sprintf("Not enough data: log both sleep and productivity on at least %d days.", MinCorrelationSamples)
+		return result
+	}
+
+	r, ok := pearson(sleep, productivity)
+	if !ok {
+		result.Interpretation = "Not enough variation: sleep or productivity was the same every day."
+		return result
+	}
+
+	r = math.Round(r*100) / 100
+	result.Coefficient = &r
+	result.Interpretation = interpretCorrelation(r)
+	return result
+}
+
+// pearson returns the Pearson correlation coefficient of xs and ys, which
+// must be the same length, reporting false when either has no variance.
+func pearson(xs, ys []float64) (float64, bool) {
+	n := float64(len(xs))
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= n
+	meanY /= n
+
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+
+	if varX == 0 || varY == 0 {
+		return 0, false
+	}
+	return cov / math.Sqrt(varX*varY), true
+}
+
+// interpretCorrelation describes a sleep-productivity coefficient in words.
+func interpretCorrelation(r float64) string {
+	var strength string
+	switch abs := math.Abs(r); {
+	case abs < 0.1:
+		return "Sleep and productivity show no clear relationship."
+	case abs < 0.3:
+		strength = "weakly"
+	case abs < 0.5:
+		strength = "moderately"
+	default:
+		strength = "strongly"
+	}
+
+	direction := "higher"
+	if r < 0 {
+		direction = "lower"
+	}
+	return fmt.Sprintf("More sleep is %s associated with %s productivity.", strength, direction)
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sleepLogs(pairs ...[2]float64) []DailyLog {
+	logs := make([]DailyLog, 0, len(pairs))
+	for _, p := range pairs {
+		logs = append(logs, DailyLog{SleepHours: p[0], ProductivityRating: int(p[1])})
+	}
+	return logs
+}
+
+func TestNewSleepProductivity_PerfectlyCorrelated(t *testing.T) {
+	result := NewSleepProductivity(sleepLogs([2]float64{5, 1}, [2]float64{6, 2}, [2]float64{7, 3}, [2]float64{8, 4}, [2]float64{9, 5}))
+
+	if assert.NotNil(t, result.Coefficient) {
+		assert.Equal(t, 1.0, *result.Coefficient)
+	}
+	assert.Equal(t, 5, result.SampleSize)
+	assert.Equal(t, "More sleep is strongly associated with higher productivity.", result.Interpretation)
+}
+
+func TestNewSleepProductivity_AntiCorrelated(t *testing.T) {
+	result := NewSleepProductivity(sleepLogs([2]float64{5, 5}, [2]float64{6, 4}, [2]float64{7, 3}, [2]float64{8, 2}, [2]float64{9, 1}))
+
+	if assert.NotNil(t, result.Coefficient) {
+		assert.Equal(t, -1.0, *result.Coefficient)
+	}
+	assert.Equal(t, "More sleep is strongly associated with lower productivity.", result.Interpretation)
+}
+
+func TestNewSleepProductivity_Weak(t *testing.T) {
+	result := NewSleepProductivity(sleepLogs([2]float64{6, 2}, [2]float64{7, 2}, [2]float64{8, 2}, [2]float64{6, 2}, [2]float64{7, 4}, [2]float64{8, 3}, [2]float64{7, 4}))
+
+	if assert.NotNil(t, result.Coefficient) {
+		assert.Equal(t, 0.21, *result.Coefficient)
+	}
+	assert.Equal(t, "More sleep is weakly associated with higher productivity.", result.Interpretation)
+}
+
+func TestNewSleepProductivity_InsufficientData(t *testing.T) {
+	result := NewSleepProductivity(sleepLogs([2]float64{7, 3}, [2]float64{8, 4}))
+	assert.Nil(t, result.Coefficient)
+	assert.Equal(t, 2, result.SampleSize)
+	assert.Contains(t, result.Interpretation, "Not enough data")
+
+	result = NewSleepProductivity(nil)
+	assert.Nil(t, result.Coefficient)
+	assert.Equal(t, 0, result.SampleSize)
+}
+
+func TestNewSleepProductivity_SkipsIncompleteAndRestDays(t *testing.T) {
+	logs := sleepLogs([2]float64{5, 1}, [2]float64{7, 3}, [2]float64{9, 5})
+	logs = append(logs,
+		DailyLog{SleepHours: 10, ProductivityRating: 1, IsRestDay: true},
+		DailyLog{SleepHours: 0, ProductivityRating: 5},
+		DailyLog{SleepHours: 4},
+	)
+
+	result := NewSleepProductivity(logs)
+
+	assert.Equal(t, 3, result.SampleSize)
+	if assert.NotNil(t, result.Coefficient) {
+		assert.Equal(t, 1.0, *result.Coefficient)
+	}
+}
+
+func TestNewSleepProductivity_NoVariation(t *testing.T) {
+	result := NewSleepProductivity(sleepLogs([2]float64{8, 2}, [2]float64{8, 3}, [2]float64{8, 4}))
+
+	assert.Nil(t, result.Coefficient)
+	assert.Equal(t, 3, result.SampleSize)
+	assert.Contains(t, result.Interpretation, "Not enough variation")
+}
@@ -0,0 +1,15 @@
+package models
+
+// MaintenanceStatus is the API's read-only maintenance state, shown to
+// clients as a banner.
+type MaintenanceStatus struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message"`
+}
+
+// UpdateMaintenanceRequest toggles maintenance mode. An omitted message
+// keeps the current one.
+type UpdateMaintenanceRequest struct {
+	Enabled *bool  `json:"enabled" binding:"required"`
+	Message string `json:"message" binding:"max=500"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "strings"
+
+// NormalizeText trims a user-supplied title or name and collapses runs of
+// internal whitespace to single spaces, so "  Read   book " and "Read book"
+// are stored identically.
+func NormalizeText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func normalizeTextPtr(s *string) {
+	if s != nil {
+		*s = NormalizeText(*s)
+	}
+}
+
+func (r *CreateTaskRequest) Normalize() {
+	r.Title = NormalizeText(r.Title)
+}
+
+func (r *UpdateTaskRequest) Normalize() {
+	normalizeTextPtr(r.Title)
+}
+
+func (r *CreateHabitRequest) Normalize() {
+	r.Name = NormalizeText(r.Name)
+}
+
+func (r *UpdateHabitRequest) Normalize() {
+	normalizeTextPtr(r.Name)
+}
+
+func (r *CreateGoalMilestoneRequest) Normalize() {
+	r.Title = NormalizeText(r.Title)
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeText(t *testing.T) {
+	assert.Equal(t, "Read book", NormalizeText("  Read   book \t"))
+	assert.Equal(t, "Read book", NormalizeText("Read\n book"))
+	assert.Equal(t, "", NormalizeText(" \t\n "))
+	assert.Equal(t, "Already clean", NormalizeText("Already clean"))
+}
+
+func TestUpdateRequestsNormalizeOnlySetFields(t *testing.T) {
+	title := "  Write   report "
+	task := &UpdateTaskRequest{Title: &title}
+	task.Normalize()
+	assert.Equal(t, "Write report", *task.Title)
+
+	habit := &UpdateHabitRequest{}
+	habit.Normalize()
+	assert.Nil(t, habit.Name)
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// NullableFloat is a PATCH field that tells an omitted value apart from an
+// explicit null: Set is true whenever the key is present, and Value is nil
+// when it was null.
+type NullableFloat struct {
+	Set   bool
+	Value *float64
+}
+
+func (n *NullableFloat) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		n.Value = nil
+		return nil
+	}
+
+	var value float64
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	n.Value = &value
+	return nil
+}
+
+// Equal reports whether n would leave v as it is: n is unset, or both are
+// null, or both hold the same number.
+func (n NullableFloat) Equal(v *float64) bool {
+	if !n.Set {
+		return true
+	}
+	if n.Value == nil || v == nil {
+		return n.Value == nil && v == nil
+	}
+	return *n.Value == *v
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PlannerDays is how many days a planner week spans.
+const PlannerDays = 7
+
+// PlannerDay is one day of the weekly planner: the tasks due that day and
+// the habits that still need doing on it.
+type PlannerDay struct {
+	Date   time.Time `json:"date"`
+	Tasks  []Task    `json:"tasks"`
+	Habits []Habit   `json:"habits"`
+}
+
+// PlannerCompletionsSince returns the earliest completion that can bear on a
+// week starting at start: habits are judged per frequency period, and both
+// the month and, when start is not the user's first day of the week, the
+// weekly period containing start may begin before it.
+func PlannerCompletionsSince(start time.Time, weekStart WeekStart) time.Time {
+	monthStart := (&Habit{Frequency: "monthly"}).PeriodStart(start)
+	if week := weekStart.StartOf(start); week.Before(monthStart) {
+		return week
+	}
+	return monthStart
+}
+
+// PlanWeek lays out the PlannerDays days from start, with day boundaries
+// taken in loc. A task lands on the day its due date falls on. A habit is
+// listed on each day it is due by IsDueOn, tallying the completions in its
+// period logged before that day starts, so a weekly habit drops off the days
+// after its target was met. Habits created after a day are left off it.
+// Tasks and habits keep the order they are given in.
+func PlanWeek(start time.Time, loc *time.Location, habits []Habit, completions []HabitCompletion, tasks []Task) []PlannerDay {
+	byHabit := make(map[uuid.UUID][]HabitCompletion, len(habits))
+	for _, completion := range completions {
+		byHabit[completion.HabitID] = append(byHabit[completion.HabitID], completion)
+	}
+
+	days := make([]PlannerDay, 0, PlannerDays)
+	for i := 0; i < PlannerDays; i++ {
+		dayStart, dayEnd := DayBounds(start.AddDate(0, 0, i), loc)
+		day := PlannerDay{Date: StatsDate(dayStart), Tasks: []Task{}, Habits: []Habit{}}
+
+		for _, task := range tasks {
+			if task.DueDate != nil && !task.DueDate.Before(dayStart) && task.DueDate.Before(dayEnd) {
+				day.Tasks = append(day.Tasks, task)
+			}
+		}
+
+		for j := range habits {
+			habit := &habits[j]
+			if !habit.CreatedAt.IsZero() && !habit.CreatedAt.Before(dayEnd) {
+				continue
+			}
+
+			periodStart := habit.PeriodStart(dayStart)
+			var tally PeriodTally
+			for _, completion := range byHabit[habit.ID] {
+				if !completion.CompletedAt.Before(periodStart) && completion.CompletedAt.Before(dayStart) {
+					tally.Add(completion)
+				}
+			}
+
+			if habit.IsDueOn(dayStart, tally) {
+				day.Habits = append(day.Habits, *habit)
+			}
+		}
+
+		days = append(days, day)
+	}
+
+	return days
+}
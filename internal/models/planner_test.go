@@ -0,0 +1,127 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func plannerTaskTitles(day PlannerDay) []string {
+	titles := []string{}
+	for _, task := range day.Tasks {
+		titles = append(titles, task.Title)
+	}
+	return titles
+}
+
+func plannerHabitNames(day PlannerDay) []string {
+	names := []string{}
+	for _, habit := range day.Habits {
+		names = append(names, habit.Name)
+	}
+	return names
+}
+
+func TestPlanWeek_ComposesEachDay(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	start := time.Date(2026, 10, 12, 0, 0, 0, 0, ny) // Monday
+	created := time.Date(2026, 9, 1, 0, 0, 0, 0, ny)
+
+	water := Habit{ID: uuid.New(), Name: "Water", Frequency: "daily", TargetCount: 1, IsActive: true, CreatedAt: created}
+	gym := Habit{ID: uuid.New(), Name: "Gym", Frequency: "weekly", TargetCount: 2, IsActive: true, CreatedAt: created}
+	budget := Habit{ID: uuid.New(), Name: "Budget", Frequency: "monthly", TargetCount: 1, IsActive: true, CreatedAt: created}
+	journal := Habit{ID: uuid.New(), Name: "Journal", Frequency: "daily", TargetCount: 1, IsActive: true, CreatedAt: time.Date(2026, 10, 15, 20, 0, 0, 0, ny)}
+	retired := Habit{ID: uuid.New(), Name: "Retired", Frequency: "daily", TargetCount: 1, CreatedAt: created}
+
+	completions := []HabitCompletion{
+		// The month's budget was already done before the week began.
+		{HabitID: budget.ID, CompletedAt: time.Date(2026, 10, 3, 9, 0, 0, 0, ny)},
+		// Gym meets its weekly target of 2 on Tuesday.
+		{HabitID: gym.ID, CompletedAt: time.Date(2026, 10, 12, 7, 0, 0, 0, ny)},
+		{HabitID: gym.ID, CompletedAt: time.Date(2026, 10, 13, 7, 0, 0, 0, ny)},
+		// Daily habits stay due every day regardless.
+		{HabitID: water.ID, CompletedAt: time.Date(2026, 10, 12, 8, 0, 0, 0, ny)},
+	}
+
+	due := func(day, hour, minute int) *time.Time {
+		t := time.Date(2026, 10, day, hour, minute, 0, 0, ny)
+		return &t
+	}
+	tasks := []Task{
+		{Title: "Standup notes", DueDate: due(12, 9, 0)},
+		// 23:30 local on Wednesday is already Thursday in UTC.
+		{Title: "Late report", DueDate: due(14, 23, 30)},
+		{Title: "Groceries", DueDate: due(18, 10, 0)},
+		{Title: "Next week", DueDate: due(19, 9, 0)},
+		{Title: "Someday"},
+	}
+
+	days := PlanWeek(start, ny, []Habit{water, gym, budget, journal, retired}, completions, tasks)
+
+	assert.Len(t, days, PlannerDays)
+	for i, day := range days {
+		assert.Equal(t, time.Date(2026, 10, 12+i, 0, 0, 0, 0, time.UTC), day.Date)
+	}
+
+	assert.Equal(t, []string{"Standup notes"}, plannerTaskTitles(days[0]))
+	assert.Empty(t, plannerTaskTitles(days[1]))
+	assert.Equal(t, []string{"Late report"}, plannerTaskTitles(days[2]))
+	assert.Empty(t, plannerTaskTitles(days[3]))
+	assert.Equal(t, []string{"Groceries"}, plannerTaskTitles(days[6]))
+
+	assert.Equal(t, []string{"Water", "Gym"}, plannerHabitNames(days[0]))
+	assert.Equal(t, []string{"Water", "Gym"}, plannerHabitNames(days[1]))
+	assert.Equal(t, []string{"Water"}, plannerHabitNames(days[2]))
+	assert.Equal(t, []string{"Water", "Journal"}, plannerHabitNames(days[3]))
+	assert.Equal(t, []string{"Water", "Journal"}, plannerHabitNames(days[6]))
+}
+
+func TestPlanWeek_PausedHabitSkipsPausedDays(t *testing.T) {
+	start := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	from := time.Date(2026, 10, 13, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	walk := Habit{ID: uuid.New(), Name: "Walk", Frequency: "daily", TargetCount: 1, IsActive: true, PausedFrom: &from, PausedUntil: &until}
+
+	days := PlanWeek(start, time.UTC, []Habit{walk}, nil, nil)
+
+	assert.Equal(t, []string{"Walk"}, plannerHabitNames(days[0]))
+	assert.Empty(t, plannerHabitNames(days[1]))
+	assert.Empty(t, plannerHabitNames(days[2]))
+	assert.Equal(t, []string{"Walk"}, plannerHabitNames(days[3]))
+	assert.NotNil(t, days[1].Tasks)
+}
+
+func TestPlanWeek_AmountHabitStaysDueUntilAmountIsMet(t *testing.T) {
+	start := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC) // Monday
+	target := 20.0
+	run := Habit{ID: uuid.New(), Name: "Run", Frequency: "weekly", TargetCount: 1, TargetAmount: &target, IsActive: true}
+
+	completions := []HabitCompletion{
+		amountOn(time.Date(2026, 10, 12, 7, 0, 0, 0, time.UTC), 8),
+		amountOn(time.Date(2026, 10, 14, 7, 0, 0, 0, time.UTC), 12),
+	}
+	for i := range completions {
+		completions[i].HabitID = run.ID
+	}
+
+	days := PlanWeek(start, time.UTC, []Habit{run}, completions, nil)
+
+	// 8 km on Monday is one completion but short of 20 km.
+	assert.Equal(t, []string{"Run"}, plannerHabitNames(days[1]))
+	assert.Equal(t, []string{"Run"}, plannerHabitNames(days[2]))
+	assert.Empty(t, plannerHabitNames(days[3]))
+}
+
+func TestPlannerCompletionsSince(t *testing.T) {
+	start := time.Date(2026, 9, 28, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), PlannerCompletionsSince(start, WeekStartMonday))
+}
+
+func TestPlannerCompletionsSince_WeekBeganLastMonth(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC) // Thursday
+
+	assert.Equal(t, time.Date(2026, 9, 28, 0, 0, 0, 0, time.UTC), PlannerCompletionsSince(start, WeekStartMonday))
+	assert.Equal(t, time.Date(2026, 9, 27, 0, 0, 0, 0, time.UTC), PlannerCompletionsSince(start, WeekStartSunday))
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultReminderAckWindow is how soon after a reminder an acknowledgment
+// still counts as the user acting on it.
+const DefaultReminderAckWindow = 2 * time.Hour
+
+// Reminder effectiveness is reported over the last N days.
+const (
+	DefaultReminderEffectivenessDays = 30
+	MaxReminderEffectivenessDays     = 365
+)
+
+// ReminderEvent records a reminder that was sent and, once the user acts on
+// it, when they acknowledged it. HabitID is only set for habit reminders.
+type ReminderEvent struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	UserID         uuid.UUID  `json:"user_id" db:"user_id"`
+	HabitID        *uuid.UUID `json:"habit_id" db:"habit_id"`
+	Kind           string     `json:"kind" db:"kind"`
+	SentAt         time.Time  `json:"sent_at" db:"sent_at"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at" db:"acknowledged_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+// ActedOn reports whether the reminder was acknowledged within window of
+// being sent.
+func (e ReminderEvent) ActedOn(window time.Duration) bool {
+	return e.AcknowledgedAt != nil && e.AcknowledgedAt.Sub(e.SentAt) <= window
+}
+
+// ReminderEffectiveness summarizes how often reminders led the user to act.
+// Rate is the acted-on share as a percentage, rounded to one decimal place.
+type ReminderEffectiveness struct {
+	Sent    int     `json:"sent"`
+	ActedOn int     `json:"acted_on"`
+	Rate    float64 `json:"rate"`
+}
+
+// ComputeReminderEffectiveness counts the events acted on within window.
+// Reminders still inside their window and not yet acknowledged as of now are
+// left out, since the user may yet act on them.
+func ComputeReminderEffectiveness(events []ReminderEvent, window time.Duration, now time.Time) ReminderEffectiveness {
+	var result ReminderEffectiveness
+	for _, e := range events {
+		switch {
+		case e.ActedOn(window):
+			result.Sent++
+			result.ActedOn++
+		case e.AcknowledgedAt != nil || !now.Before(e.SentAt.Add(window)):
+			result.Sent++
+		}
+	}
+
+	if result.Sent > 0 {
+		result.Rate = math.Round(float64(result.ActedOn)/float64(result.Sent)*1000) / 10
+	}
+
+	return result
+}
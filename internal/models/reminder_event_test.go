@@ -0,0 +1,49 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func reminderSentAt(sent time.Time, ackAfter *time.Duration) ReminderEvent {
+	event := ReminderEvent{SentAt: sent}
+	if ackAfter != nil {
+		at := sent.Add(*ackAfter)
+		event.AcknowledgedAt = &at
+	}
+	return event
+}
+
+func after(d time.Duration) *time.Duration { return &d }
+
+func TestReminderEvent_ActedOn(t *testing.T) {
+	sent := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	assert.True(t, reminderSentAt(sent, after(30*time.Minute)).ActedOn(time.Hour))
+	assert.True(t, reminderSentAt(sent, after(time.Hour)).ActedOn(time.Hour), "the window is inclusive")
+	assert.False(t, reminderSentAt(sent, after(61*time.Minute)).ActedOn(time.Hour))
+	assert.False(t, reminderSentAt(sent, nil).ActedOn(time.Hour))
+}
+
+func TestComputeReminderEffectiveness(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	window := 2 * time.Hour
+
+	events := []ReminderEvent{
+		reminderSentAt(now.Add(-48*time.Hour), after(10*time.Minute)), // acted on
+		reminderSentAt(now.Add(-24*time.Hour), after(5*time.Hour)),    // acknowledged too late
+		reminderSentAt(now.Add(-6*time.Hour), nil),                    // ignored
+		reminderSentAt(now.Add(-30*time.Minute), after(time.Minute)),  // acted on, window still open
+		reminderSentAt(now.Add(-time.Hour), nil),                      // may still be acted on
+	}
+
+	result := ComputeReminderEffectiveness(events, window, now)
+
+	assert.Equal(t, ReminderEffectiveness{Sent: 4, ActedOn: 2, Rate: 50}, result)
+}
+
+func TestComputeReminderEffectiveness_Empty(t *testing.T) {
+	assert.Equal(t, ReminderEffectiveness{}, ComputeReminderEffectiveness(nil, time.Hour, time.Now()))
+}
@@ -1,7 +1,12 @@
 package models
 
 import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
@@ -14,35 +19,183 @@ type Task struct {
 	Horizon     string     `json:"horizon" db:"horizon" binding:"required"`
 	Priority    string     `json:"priority" db:"priority" binding:"required"`
 	Status      string     `json:"status" db:"status" binding:"required"`
+	Tags        []string   `json:"tags" db:"tags"`
 	DueDate     *time.Time `json:"due_date" db:"due_date"`
 	CompletedAt *time.Time `json:"completed_at" db:"completed_at"`
+	SnoozeCount int        `json:"snooze_count" db:"snooze_count"`
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	// BlockedBy lists the tasks this one waits on, stored in
+	// task_dependencies; IsBlocked is computed from their statuses.
+	BlockedBy []uuid.UUID `json:"blocked_by"`
+	IsBlocked bool        `json:"is_blocked"`
 }
 
 type CreateTaskRequest struct {
-	Title       string     `json:"title" binding:"required,min=1,max=200"`
-	Description string     `json:"description" binding:"max=1000"`
-	Horizon     string     `json:"horizon" binding:"required,oneof=now next later someday"`
-	Priority    string     `json:"priority" binding:"required,oneof=low medium high urgent"`
-	DueDate     *time.Time `json:"due_date"`
+	Title       string      `json:"title" binding:"required,min=1,max=200"`
+	Description string      `json:"description" binding:"max=1000"`
+	Horizon     string      `json:"horizon" binding:"required,oneof=now next later someday"`
+	Priority    string      `json:"priority" binding:"required,oneof=low medium high urgent"`
+	Tags        []string    `json:"tags" binding:"omitempty,dive,max=50"`
+	DueDate     *time.Time  `json:"due_date"`
+	BlockedBy   []uuid.UUID `json:"blocked_by"`
 }
 
 type UpdateTaskRequest struct {
-	Title       *string    `json:"title" binding:"omitempty,min=1,max=200"`
-	Description *string    `json:"description" binding:"omitempty,max=1000"`
-	Horizon     *string    `json:"horizon" binding:"omitempty,oneof=now next later someday"`
-	Priority    *string    `json:"priority" binding:"omitempty,oneof=low medium high urgent"`
-	Status      *string    `json:"status" binding:"omitempty,oneof=todo in_progress done archived"`
-	DueDate     *time.Time `json:"due_date"`
+	Title       *string      `json:"title" binding:"omitempty,min=1,max=200"`
+	Description *string      `json:"description" binding:"omitempty,max=1000"`
+	Horizon     *string      `json:"horizon" binding:"omitempty,oneof=now next later someday"`
+	Priority    *string      `json:"priority" binding:"omitempty,oneof=low medium high urgent"`
+	Status      *string      `json:"status" binding:"omitempty,oneof=todo in_progress done archived"`
+	Tags        *[]string    `json:"tags" binding:"omitempty,dive,max=50"`
+	DueDate     *time.Time   `json:"due_date"`
+	BlockedBy   *[]uuid.UUID `json:"blocked_by"`
 }
 
+// SnoozeTaskRequest pushes a task's due date out. Exactly one of Duration
+// (e.g. "24h", "3d") or Until must be set.
+type SnoozeTaskRequest struct {
+	Duration string     `json:"duration"`
+	Until    *time.Time `json:"until"`
+}
+
+// MoveTasksToGoalRequest reassigns up to 500 tasks to GoalID, or unlinks
+// them from their goal when GoalID is null or omitted.
+type MoveTasksToGoalRequest struct {
+	TaskIDs []uuid.UUID `json:"task_ids" binding:"required,min=1,max=500"`
+	GoalID  *uuid.UUID  `json:"goal_id"`
+}
+
+func (r *MoveTasksToGoalRequest) Validate() error {
+	seen := make(map[uuid.UUID]bool, len(r.TaskIDs))
+	for _, id := range r.TaskIDs {
+		if seen[id] {
+			return ErrDuplicateTaskIDs
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// TaskFilter narrows a task listing; all set fields must match. Tags keeps
+// tasks carrying every given tag (repeat the parameter or comma-separate).
+// HasDueDate, when set, keeps only tasks with (true) or without (false) a due date.
+// It is echoed back in listings, so it carries JSON names too.
 type TaskFilter struct {
-	Horizon  string     `form:"horizon"`
-	Status   string     `form:"status"`
-	Priority string     `form:"priority"`
-	FromDate *time.Time `form:"from_date"`
-	ToDate   *time.Time `form:"to_date"`
+	Horizon    string     `form:"horizon" json:"horizon"`
+	Status     string     `form:"status" json:"status"`
+	Priority   string     `form:"priority" json:"priority"`
+	Tags       []string   `form:"tags" json:"tags"`
+	FromDate   *time.Time `form:"from_date" json:"from_date"`
+	ToDate     *time.Time `form:"to_date" json:"to_date"`
+	HasDueDate *bool      `form:"has_due_date" json:"has_due_date"`
+}
+
+// ArchiveCompletedRequest optionally limits a bulk archive to tasks completed
+// before CompletedBefore.
+type ArchiveCompletedRequest struct {
+	CompletedBefore *time.Time `form:"completed_before"`
+}
+
+// Changes lists the JSON names of fields the request would actually change
+// on t. It must be called before the request is applied.
+func (r *UpdateTaskRequest) Changes(t *Task) []string {
+	changes := []string{}
+
+	if r.Title != nil && *r.Title != t.Title {
+		changes = append(changes, "title")
+	}
+	if r.Description != nil && *r.Description != t.Description {
+		changes = append(changes, "description")
+	}
+	if r.Horizon != nil && *r.Horizon != t.Horizon {
+		changes = append(changes, "horizon")
+	}
+	if r.Priority != nil && *r.Priority != t.Priority {
+		changes = append(changes, "priority")
+	}
+	if r.Status != nil && *r.Status != t.Status {
+		changes = append(changes, "status")
+	}
+	if r.Tags != nil && !slices.Equal(NormalizeTags(*r.Tags), NormalizeTags(t.Tags)) {
+		changes = append(changes, "tags")
+	}
+	if r.DueDate != nil && (t.DueDate == nil || !r.DueDate.Equal(*t.DueDate)) {
+		changes = append(changes, "due_date")
+	}
+	if r.BlockedBy != nil && !sameBlockers(*r.BlockedBy, t.BlockedBy) {
+		changes = append(changes, "blocked_by")
+	}
+
+	return changes
+}
+
+// sameBlockers reports whether a and b name the same set of tasks.
+func sameBlockers(a, b []uuid.UUID) bool {
+	for _, id := range a {
+		if !slices.Contains(b, id) {
+			return false
+		}
+	}
+	for _, id := range b {
+		if !slices.Contains(a, id) {
+			return false
+		}
+	}
+	return true
+}
+
+// TagLimits bounds a resource's tags after normalization. Zero disables a
+// limit.
+type TagLimits struct {
+	MaxCount  int
+	MaxLength int
+}
+
+// Default tag limits.
+const (
+	DefaultMaxTags      = 20
+	DefaultMaxTagLength = 50
+)
+
+// DefaultTagLimits are the limits used when none are configured.
+var DefaultTagLimits = TagLimits{MaxCount: DefaultMaxTags, MaxLength: DefaultMaxTagLength}
+
+// ValidateTags checks normalized tags against limits. Lengths are counted in
+// characters, not bytes.
+func ValidateTags(tags []string, limits TagLimits) error {
+	if limits.MaxCount > 0 && len(tags) > limits.MaxCount {
+		return fmt.Errorf("%w: at most %d tags allowed, got %d", ErrTooManyTags, limits.MaxCount, len(tags))
+	}
+
+	if limits.MaxLength > 0 {
+		for _, tag := range tags {
+			if utf8.RuneCountInString(tag) > limits.MaxLength {
+				return fmt.Errorf("%w: %q is longer than %d characters", ErrTagTooLong, tag, limits.MaxLength)
+			}
+		}
+	}
+
+	return nil
+}
+
+// NormalizeTags trims and lowercases tags, splits comma-separated values and
+// drops empties and duplicates, keeping first-seen order. It never returns nil
+// so an empty set is stored as an empty array.
+func NormalizeTags(tags []string) []string {
+	normalized := []string{}
+	seen := make(map[string]bool)
+	for _, value := range tags {
+		for _, tag := range strings.Split(value, ",") {
+			tag = strings.ToLower(strings.TrimSpace(tag))
+			if tag == "" || seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
 }
 
 func (t *Task) Validate() error {
@@ -81,3 +234,103 @@ func (t *Task) Validate() error {
 
 	return nil
 }
+
+// DefaultMaxDueDateYears is how far ahead a due date may be set by default.
+const DefaultMaxDueDateYears = 10
+
+// ValidateDueDate rejects a due date more than maxYears years after now, which
+// is almost always a typo (a year like 9999). maxYears <= 0 disables the check.
+func ValidateDueDate(due *time.Time, now time.Time, maxYears int) error {
+	if due == nil || maxYears <= 0 {
+		return nil
+	}
+
+	limit := now.AddDate(maxYears, 0, 0)
+	if due.After(limit) {
+		return fmt.Errorf("%w: must be no later than %s", ErrDueDateTooFar, limit.Format(time.DateOnly))
+	}
+
+	return nil
+}
+
+// SnoozedDueDate resolves the request into a new due date for t. Durations
+// are added to the current due date, or to now if the task is already
+// overdue or has none. The result must be in the future.
+func (r *SnoozeTaskRequest) SnoozedDueDate(t *Task, now time.Time) (time.Time, error) {
+	if (r.Duration == "") == (r.Until == nil) {
+		return time.Time{}, ErrInvalidSnooze
+	}
+
+	var due time.Time
+	if r.Until != nil {
+		due = *r.Until
+	} else {
+		d, err := parseSnoozeDuration(r.Duration)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		base := now
+		if t.DueDate != nil && t.DueDate.After(now) {
+			base = *t.DueDate
+		}
+		due = base.Add(d)
+	}
+
+	if !due.After(now) {
+		return time.Time{}, ErrSnoozeInPast
+	}
+
+	return due, nil
+}
+
+// parseSnoozeDuration accepts Go durations ("90m", "24h") and whole days ("3d").
+func parseSnoozeDuration(value string) (time.Duration, error) {
+	var d time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, ErrInvalidSnooze
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, ErrInvalidSnooze
+		}
+		d = parsed
+	}
+
+	if d <= 0 {
+		return 0, ErrInvalidSnooze
+	}
+
+	return d, nil
+}
+
+// DayBounds returns the [start, end) instants of date's calendar day in loc.
+func DayBounds(date time.Time, loc *time.Location) (time.Time, time.Time) {
+	year, month, day := date.Date()
+	start := time.Date(year, month, day, 0, 0, 0, 0, loc)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// CountTasksForDay counts tasks completed during date's calendar day in loc
+// (by completed_at) and tasks due that same day.
+func CountTasksForDay(tasks []Task, date time.Time, loc *time.Location) (completed, total int) {
+	start, end := DayBounds(date, loc)
+	within := func(t *time.Time) bool {
+		return t != nil && !t.Before(start) && t.Before(end)
+	}
+
+	for i := range tasks {
+		if within(tasks[i].CompletedAt) {
+			completed++
+		}
+		if within(tasks[i].DueDate) {
+			total++
+		}
+	}
+
+	return completed, total
+}
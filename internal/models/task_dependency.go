@@ -0,0 +1,108 @@
+package models
+
+import (
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// MaxTaskBlockers caps how many tasks may block a single task.
+const MaxTaskBlockers = 50
+
+// TaskDependency records that TaskID can't start until BlockedByID is done.
+// BlockedByStatus is the blocker's current status when read back.
+type TaskDependency struct {
+	TaskID          uuid.UUID `json:"task_id" db:"task_id"`
+	BlockedByID     uuid.UUID `json:"blocked_by_id" db:"blocked_by_id"`
+	BlockedByStatus string    `json:"-" db:"status"`
+}
+
+// BlockerResolved reports whether a blocker in status no longer holds up the
+// tasks it blocks: done and archived tasks are finished with.
+func BlockerResolved(status string) bool {
+	return status == "done" || status == "archived"
+}
+
+// StartsWork reports whether moving a task from status from to status to
+// begins or finishes it, which a blocked task may not do.
+func StartsWork(from, to string) bool {
+	return from != to && (to == "in_progress" || to == "done")
+}
+
+// NormalizeBlockedBy drops duplicate blockers, keeping first-seen order, and
+// rejects a task blocking itself. It never returns nil so "no blockers" is
+// distinguishable from "not given".
+func NormalizeBlockedBy(taskID uuid.UUID, blockedBy []uuid.UUID) ([]uuid.UUID, error) {
+	normalized := []uuid.UUID{}
+	for _, id := range blockedBy {
+		if id == taskID {
+			return nil, ErrTaskBlocksItself
+		}
+		if !slices.Contains(normalized, id) {
+			normalized = append(normalized, id)
+		}
+	}
+
+	if len(normalized) > MaxTaskBlockers {
+		return nil, ErrTooManyBlockers
+	}
+
+	return normalized, nil
+}
+
+// ApplyDependencies fills in BlockedBy and IsBlocked on tasks from deps, the
+// dependencies of those tasks with their blockers' statuses. A task is
+// blocked while any of its blockers is unresolved.
+func ApplyDependencies(tasks []Task, deps []TaskDependency) {
+	byTask := make(map[uuid.UUID][]TaskDependency)
+	for _, dep := range deps {
+		byTask[dep.TaskID] = append(byTask[dep.TaskID], dep)
+	}
+
+	for i := range tasks {
+		tasks[i].SetBlockers(byTask[tasks[i].ID])
+	}
+}
+
+// SetBlockers replaces t's BlockedBy with the blockers in deps and marks it
+// blocked while any of them is unresolved.
+func (t *Task) SetBlockers(deps []TaskDependency) {
+	t.BlockedBy = []uuid.UUID{}
+	t.IsBlocked = false
+	for _, dep := range deps {
+		t.BlockedBy = append(t.BlockedBy, dep.BlockedByID)
+		if !BlockerResolved(dep.BlockedByStatus) {
+			t.IsBlocked = true
+		}
+	}
+}
+
+// DependencyCycle reports whether making taskID blocked by blockedBy would
+// close a cycle, given edges, the user's existing dependencies. taskID's own
+// existing dependencies are ignored since blockedBy replaces them.
+func DependencyCycle(edges []TaskDependency, taskID uuid.UUID, blockedBy []uuid.UUID) bool {
+	next := make(map[uuid.UUID][]uuid.UUID)
+	for _, edge := range edges {
+		if edge.TaskID != taskID {
+			next[edge.TaskID] = append(next[edge.TaskID], edge.BlockedByID)
+		}
+	}
+
+	// A cycle exists if taskID is reachable from any of its new blockers.
+	seen := make(map[uuid.UUID]bool)
+	stack := slices.Clone(blockedBy)
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == taskID {
+			return true
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		stack = append(stack, next[id]...)
+	}
+
+	return false
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencyCycle(t *testing.T) {
+	a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	// b is blocked by a, c by b.
+	edges := []TaskDependency{{TaskID: b, BlockedByID: a}, {TaskID: c, BlockedByID: b}}
+
+	assert.True(t, DependencyCycle(edges, a, []uuid.UUID{b}), "direct cycle")
+	assert.True(t, DependencyCycle(edges, a, []uuid.UUID{d, c}), "transitive cycle")
+	assert.False(t, DependencyCycle(edges, c, []uuid.UUID{a}), "shortcut along the chain")
+	assert.False(t, DependencyCycle(edges, d, []uuid.UUID{a, b, c}), "new task blocked by the chain")
+	assert.False(t, DependencyCycle(edges, b, nil), "clearing blockers")
+}
+
+func TestDependencyCycle_IgnoresReplacedEdges(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	edges := []TaskDependency{{TaskID: a, BlockedByID: b}}
+
+	// a is blocked by b, so b can't be blocked by a, but a's own edges are
+	// being replaced and never count against it.
+	assert.True(t, DependencyCycle(edges, b, []uuid.UUID{a}))
+	assert.False(t, DependencyCycle(edges, a, []uuid.UUID{}))
+}
+
+func TestDependencyCycle_Diamond(t *testing.T) {
+	top, left, right, bottom := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	edges := []TaskDependency{
+		{TaskID: left, BlockedByID: top},
+		{TaskID: right, BlockedByID: top},
+	}
+
+	assert.False(t, DependencyCycle(edges, bottom, []uuid.UUID{left, right}))
+	assert.True(t, DependencyCycle(append(edges,
+		TaskDependency{TaskID: bottom, BlockedByID: left},
+		TaskDependency{TaskID: bottom, BlockedByID: right},
+	), top, []uuid.UUID{bottom}))
+}
+
+func TestApplyDependencies(t *testing.T) {
+	free, waiting, cleared := Task{ID: uuid.New()}, Task{ID: uuid.New()}, Task{ID: uuid.New()}
+	open, done, archived := uuid.New(), uuid.New(), uuid.New()
+
+	tasks := []Task{free, waiting, cleared}
+	ApplyDependencies(tasks, []TaskDependency{
+		{TaskID: waiting.ID, BlockedByID: done, BlockedByStatus: "done"},
+		{TaskID: waiting.ID, BlockedByID: open, BlockedByStatus: "todo"},
+		{TaskID: cleared.ID, BlockedByID: done, BlockedByStatus: "done"},
+		{TaskID: cleared.ID, BlockedByID: archived, BlockedByStatus: "archived"},
+	})
+
+	assert.Equal(t, []uuid.UUID{}, tasks[0].BlockedBy)
+	assert.False(t, tasks[0].IsBlocked)
+	assert.Equal(t, []uuid.UUID{done, open}, tasks[1].BlockedBy)
+	assert.True(t, tasks[1].IsBlocked)
+	assert.Equal(t, []uuid.UUID{done, archived}, tasks[2].BlockedBy)
+	assert.False(t, tasks[2].IsBlocked)
+}
+
+func TestNormalizeBlockedBy(t *testing.T) {
+	task, a, b := uuid.New(), uuid.New(), uuid.New()
+
+	ids, err := NormalizeBlockedBy(task, []uuid.UUID{a, b, a})
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{a, b}, ids)
+
+	ids, err = NormalizeBlockedBy(task, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{}, ids)
+
+	_, err = NormalizeBlockedBy(task, []uuid.UUID{a, task})
+	assert.ErrorIs(t, err, ErrTaskBlocksItself)
+
+	tooMany := make([]uuid.UUID, MaxTaskBlockers+1)
+	for i := range tooMany {
+		tooMany[i] = uuid.New()
+	}
+	_, err = NormalizeBlockedBy(task, tooMany)
+	assert.ErrorIs(t, err, ErrTooManyBlockers)
+}
+
+func TestStartsWork(t *testing.T) {
+	assert.True(t, StartsWork("todo", "in_progress"))
+	assert.True(t, StartsWork("todo", "done"))
+	assert.True(t, StartsWork("in_progress", "done"))
+	assert.False(t, StartsWork("in_progress", "in_progress"))
+	assert.False(t, StartsWork("todo", "archived"))
+	assert.False(t, StartsWork("in_progress", "todo"))
+}
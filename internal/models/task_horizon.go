@@ -0,0 +1,50 @@
+package models
+
+// TaskHorizons are the task horizons, nearest first.
+var TaskHorizons = []string{"now", "next", "later", "someday"}
+
+const (
+	// DefaultHorizonGroupSize is how many tasks each horizon group holds
+	// when no limit is given.
+	DefaultHorizonGroupSize = 20
+	// MaxHorizonGroupSize caps the per-group limit a client may request.
+	MaxHorizonGroupSize = 100
+)
+
+// HorizonGroup is one horizon's column of the task board: its newest tasks,
+// up to the requested limit, and how many match in all. HasMore tells the
+// client to page through the rest with GET /tasks?horizon=.
+type HorizonGroup struct {
+	Tasks   []Task `json:"tasks"`
+	Total   int    `json:"total"`
+	HasMore bool   `json:"has_more"`
+}
+
+// GroupTasksByHorizon files tasks, already ordered within each horizon, into
+// a group per horizon holding at most limit of them. totals gives the number
+// of matching tasks per horizon, which may exceed what tasks carries. Every
+// horizon gets a group, empty if nothing matched; tasks with an unknown
+// horizon are dropped.
+func GroupTasksByHorizon(tasks []Task, totals map[string]int, limit int) map[string]HorizonGroup {
+	groups := make(map[string]HorizonGroup, len(TaskHorizons))
+	for _, horizon := range TaskHorizons {
+		groups[horizon] = HorizonGroup{Tasks: []Task{}}
+	}
+
+	for _, task := range tasks {
+		group, ok := groups[task.Horizon]
+		if !ok || len(group.Tasks) >= limit {
+			continue
+		}
+		group.Tasks = append(group.Tasks, task)
+		groups[task.Horizon] = group
+	}
+
+	for horizon, group := range groups {
+		group.Total = max(totals[horizon], len(group.Tasks))
+		group.HasMore = group.Total > len(group.Tasks)
+		groups[horizon] = group
+	}
+
+	return groups
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func horizonTasks(horizon string, n int) []Task {
+	tasks := make([]Task, n)
+	for i := range tasks {
+		tasks[i] = Task{ID: uuid.New(), Title: fmt.Sprintf("%s %d", horizon, i), Horizon: horizon}
+	}
+	return tasks
+}
+
+func TestGroupTasksByHorizon_EachGroupHoldsOnlyItsHorizon(t *testing.T) {
+	var tasks []Task
+	tasks = append(tasks, horizonTasks("later", 2)...)
+	tasks = append(tasks, horizonTasks("now", 3)...)
+	tasks = append(tasks, horizonTasks("next", 1)...)
+
+	groups := GroupTasksByHorizon(tasks, map[string]int{"now": 3, "next": 1, "later": 2}, 10)
+
+	assert.Len(t, groups, len(TaskHorizons))
+	for horizon, group := range groups {
+		for _, task := range group.Tasks {
+			assert.Equal(t, horizon, task.Horizon, "task %q filed under %s", task.Title, horizon)
+		}
+	}
+	assert.Len(t, groups["now"].Tasks, 3)
+	assert.Len(t, groups["next"].Tasks, 1)
+	assert.Len(t, groups["later"].Tasks, 2)
+	assert.Equal(t, "now 0", groups["now"].Tasks[0].Title, "keeps the given order")
+}
+
+func TestGroupTasksByHorizon_RespectsPerGroupCap(t *testing.T) {
+	var tasks []Task
+	tasks = append(tasks, horizonTasks("now", 5)...)
+	tasks = append(tasks, horizonTasks("someday", 2)...)
+
+	groups := GroupTasksByHorizon(tasks, map[string]int{"now": 12, "someday": 2}, 3)
+
+	assert.Len(t, groups["now"].Tasks, 3)
+	assert.Equal(t, 12, groups["now"].Total)
+	assert.True(t, groups["now"].HasMore)
+
+	assert.Len(t, groups["someday"].Tasks, 2)
+	assert.Equal(t, 2, groups["someday"].Total)
+	assert.False(t, groups["someday"].HasMore)
+}
+
+func TestGroupTasksByHorizon_EmptyAndUnknownHorizons(t *testing.T) {
+	groups := GroupTasksByHorizon([]Task{{ID: uuid.New(), Horizon: "eventually"}}, nil, 5)
+
+	for _, horizon := range TaskHorizons {
+		assert.NotNil(t, groups[horizon].Tasks, "%s renders as an empty list", horizon)
+		assert.Empty(t, groups[horizon].Tasks)
+		assert.Zero(t, groups[horizon].Total)
+	}
+	assert.NotContains(t, groups, "eventually")
+}
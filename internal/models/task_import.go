@@ -0,0 +1,160 @@
+package models
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// TaskImportError reports why a single CSV row was skipped. Row numbers are
+// 1-based and count the header, so they match what a spreadsheet shows.
+type TaskImportError struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+// TaskImportResult summarizes a CSV import.
+type TaskImportResult struct {
+	Imported int               `json:"imported"`
+	Skipped  int               `json:"skipped"`
+	Errors   []TaskImportError `json:"errors"`
+}
+
+var taskImportRequiredColumns = []string{"title", "horizon", "priority"}
+
+// ParseTaskCSV reads tasks for userID from a CSV with a header row. title,
+// horizon and priority columns are required; description and due_date
+// (YYYY-MM-DD or RFC3339) are optional, and other columns are ignored.
+// Imported tasks start as todo. Column order and case don't
+// matter. Quoted fields and LF, CRLF or CR line endings are accepted.
+//
+// Invalid rows are reported rather than failing the whole file. An error is
+// returned only when the file itself is unusable: unreadable CSV, a missing
+// required column, no data rows, or more than maxRows data rows.
+func ParseTaskCSV(r io.Reader, userID uuid.UUID, maxRows int) ([]*Task, []TaskImportError, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+	}
+
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	data = bytes.ReplaceAll(data, []byte("\r"), []byte("\n"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, ErrEmptyImport
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range taskImportRequiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, fmt.Errorf("%w: missing %q column", ErrInvalidImportFile, name)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var tasks []*Task
+	var rowErrors []TaskImportError
+	rows := 0
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		rows++
+		if rows > maxRows {
+			return nil, nil, fmt.Errorf("%w: limit is %d", ErrTooManyImportRows, maxRows)
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rowErrors = append(rowErrors, TaskImportError{Row: row, Message: parseErr.Err.Error()})
+				continue
+			}
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+		}
+
+		task, err := taskFromImportRow(field, record)
+		if err != nil {
+			rowErrors = append(rowErrors, TaskImportError{Row: row, Message: err.Error()})
+			continue
+		}
+
+		task.UserID = userID
+		tasks = append(tasks, task)
+	}
+
+	if rows == 0 {
+		return nil, nil, ErrEmptyImport
+	}
+
+	return tasks, rowErrors, nil
+}
+
+func taskFromImportRow(field func([]string, string) string, record []string) (*Task, error) {
+	task := &Task{
+		Title:       NormalizeText(field(record, "title")),
+		Description: field(record, "description"),
+		Horizon:     strings.ToLower(field(record, "horizon")),
+		Priority:    strings.ToLower(field(record, "priority")),
+		Status:      "todo",
+	}
+
+	if task.Title == "" {
+		return nil, errors.New("title is required")
+	}
+	if utf8.RuneCountInString(task.Title) > 200 {
+		return nil, errors.New("title must be at most 200 characters")
+	}
+	if utf8.RuneCountInString(task.Description) > 1000 {
+		return nil, errors.New("description must be at most 1000 characters")
+	}
+
+	if due := field(record, "due_date"); due != "" {
+		dueDate, err := parseImportDate(due)
+		if err != nil {
+			return nil, fmt.Errorf("invalid due_date %q: use YYYY-MM-DD or RFC3339", due)
+		}
+		task.DueDate = &dueDate
+	}
+
+	if err := task.Validate(); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+func parseImportDate(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
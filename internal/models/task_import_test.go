@@ -0,0 +1,90 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTaskCSV_WellFormed(t *testing.T) {
+	userID := uuid.New()
+	csv := "Title,Horizon,Priority,Description,Due_Date\r\n" +
+		"Pay rent,now,high,,2025-12-01\r\n" +
+		"\"Plan trip, Lisbon\",later,low,\"Book \"\"nice\"\" hotel\nand flights\",2025-12-20T09:30:00Z\r\n"
+
+	tasks, rowErrors, err := ParseTaskCSV(strings.NewReader(csv), userID, 10)
+
+	assert.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	if !assert.Len(t, tasks, 2) {
+		return
+	}
+
+	assert.Equal(t, "Pay rent", tasks[0].Title)
+	assert.Equal(t, "high", tasks[0].Priority)
+	assert.Equal(t, "todo", tasks[0].Status)
+	assert.Equal(t, userID, tasks[0].UserID)
+	assert.True(t, tasks[0].DueDate.Equal(time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)))
+
+	assert.Equal(t, "Plan trip, Lisbon", tasks[1].Title)
+	assert.Equal(t, "Book \"nice\" hotel\nand flights", tasks[1].Description)
+	assert.True(t, tasks[1].DueDate.Equal(time.Date(2025, 12, 20, 9, 30, 0, 0, time.UTC)))
+}
+
+func TestParseTaskCSV_LineEndings(t *testing.T) {
+	for name, sep := range map[string]string{"LF": "\n", "CRLF": "\r\n", "CR": "\r"} {
+		csv := "title,horizon,priority" + sep + "A,now,low" + sep + "B,next,medium" + sep
+		tasks, rowErrors, err := ParseTaskCSV(strings.NewReader(csv), uuid.New(), 10)
+
+		assert.NoError(t, err, name)
+		assert.Empty(t, rowErrors, name)
+		assert.Len(t, tasks, 2, name)
+	}
+}
+
+func TestParseTaskCSV_InvalidRows(t *testing.T) {
+	csv := strings.Join([]string{
+		"title,horizon,priority,due_date",
+		"Valid,now,low,",
+		",now,low,",
+		"Bad horizon,soon,low,",
+		"Bad priority,now,critical,",
+		"Bad date,now,low,next tuesday",
+		"Also valid,someday,urgent,2026-01-05",
+	}, "\n")
+
+	tasks, rowErrors, err := ParseTaskCSV(strings.NewReader(csv), uuid.New(), 10)
+
+	assert.NoError(t, err)
+	if !assert.Len(t, tasks, 2) {
+		return
+	}
+	assert.Equal(t, "Valid", tasks[0].Title)
+	assert.Equal(t, "Also valid", tasks[1].Title)
+
+	if !assert.Len(t, rowErrors, 4) {
+		return
+	}
+	assert.Equal(t, TaskImportError{Row: 3, Message: "title is required"}, rowErrors[0])
+	assert.Equal(t, TaskImportError{Row: 4, Message: ErrInvalidHorizon.Error()}, rowErrors[1])
+	assert.Equal(t, TaskImportError{Row: 5, Message: ErrInvalidPriority.Error()}, rowErrors[2])
+	assert.Equal(t, 6, rowErrors[3].Row)
+	assert.Contains(t, rowErrors[3].Message, "due_date")
+}
+
+func TestParseTaskCSV_FileErrors(t *testing.T) {
+	_, _, err := ParseTaskCSV(strings.NewReader(""), uuid.New(), 10)
+	assert.ErrorIs(t, err, ErrEmptyImport)
+
+	_, _, err = ParseTaskCSV(strings.NewReader("title,horizon,priority\n"), uuid.New(), 10)
+	assert.ErrorIs(t, err, ErrEmptyImport)
+
+	_, _, err = ParseTaskCSV(strings.NewReader("title,priority\nA,low\n"), uuid.New(), 10)
+	assert.ErrorIs(t, err, ErrInvalidImportFile)
+
+	_, _, err = ParseTaskCSV(strings.NewReader("title,horizon,priority\nA,now,low\nB,now,low\nC,now,low\n"), uuid.New(), 2)
+	assert.ErrorIs(t, err, ErrTooManyImportRows)
+}
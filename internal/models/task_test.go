@@ -0,0 +1,111 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func at(t time.Time) *time.Time {
+	return &t
+}
+
+func TestCountTasksForDay(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	date := time.Date(2025, 11, 13, 0, 0, 0, 0, time.UTC)
+
+	tasks := []Task{
+		// Due and completed the same day.
+		{DueDate: at(time.Date(2025, 11, 13, 17, 0, 0, 0, ny)), CompletedAt: at(time.Date(2025, 11, 13, 15, 0, 0, 0, ny))},
+		// Due that day, still open.
+		{DueDate: at(time.Date(2025, 11, 13, 9, 0, 0, 0, ny))},
+		// Due tomorrow but finished early today.
+		{DueDate: at(time.Date(2025, 11, 14, 9, 0, 0, 0, ny)), CompletedAt: at(time.Date(2025, 11, 13, 20, 0, 0, 0, ny))},
+		// 02:00 UTC on the 14th is still the 13th in New York.
+		{DueDate: at(time.Date(2025, 11, 14, 2, 0, 0, 0, time.UTC))},
+		// Completed yesterday local time.
+		{CompletedAt: at(time.Date(2025, 11, 12, 23, 30, 0, 0, ny))},
+		// No dates at all.
+		{},
+	}
+
+	completed, total := CountTasksForDay(tasks, date, ny)
+	assert.Equal(t, 2, completed)
+	assert.Equal(t, 3, total)
+
+	// The same tasks bucketed in UTC shift across the day boundary.
+	completed, total = CountTasksForDay(tasks, date, time.UTC)
+	assert.Equal(t, 2, completed)
+	assert.Equal(t, 2, total)
+}
+
+func TestDayBounds(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	assert.NoError(t, err)
+
+	start, end := DayBounds(time.Date(2025, 11, 13, 0, 0, 0, 0, time.UTC), tokyo)
+	assert.True(t, start.Equal(time.Date(2025, 11, 12, 15, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 24*time.Hour, end.Sub(start))
+}
+
+func TestNormalizeTags(t *testing.T) {
+	assert.Equal(t, []string{"work", "urgent", "home"}, NormalizeTags([]string{" Work ", "urgent,home", "WORK", ""}))
+	assert.Equal(t, []string{}, NormalizeTags(nil))
+}
+
+func TestUpdateTaskRequest_ChangesTags(t *testing.T) {
+	task := &Task{Tags: []string{"work", "q4"}}
+
+	same := []string{"Work", "q4"}
+	assert.Empty(t, (&UpdateTaskRequest{Tags: &same}).Changes(task))
+
+	changed := []string{"work"}
+	assert.Equal(t, []string{"tags"}, (&UpdateTaskRequest{Tags: &changed}).Changes(task))
+}
+
+func TestValidateTags(t *testing.T) {
+	limits := TagLimits{MaxCount: 3, MaxLength: 10}
+
+	assert.NoError(t, ValidateTags([]string{"work", "home", "errands"}, limits))
+	assert.NoError(t, ValidateTags([]string{"überfällig"}, limits), "length counts characters, not bytes")
+
+	err := ValidateTags([]string{"a", "b", "c", "d"}, limits)
+	assert.ErrorIs(t, err, ErrTooManyTags)
+	assert.Contains(t, err.Error(), "at most 3 tags allowed, got 4")
+
+	err = ValidateTags([]string{"work", "procrastination"}, limits)
+	assert.ErrorIs(t, err, ErrTagTooLong)
+	assert.Contains(t, err.Error(), `"procrastination" is longer than 10 characters`)
+
+	assert.NoError(t, ValidateTags([]string{"a", "b", "c", "d", "procrastination"}, TagLimits{}))
+}
+
+func TestValidateTags_CountsNormalizedTags(t *testing.T) {
+	limits := TagLimits{MaxCount: 2, MaxLength: 4}
+
+	// Four raw values, but only two distinct tags once normalized.
+	assert.NoError(t, ValidateTags(NormalizeTags([]string{"Work", "work, HOME", " home "}), limits))
+}
+
+func TestValidateDueDate(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, ValidateDueDate(nil, now, 10))
+	assert.NoError(t, ValidateDueDate(at(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)), now, 10))
+	assert.NoError(t, ValidateDueDate(at(now.AddDate(10, 0, 0)), now, 10))
+
+	err := ValidateDueDate(at(now.AddDate(10, 0, 1)), now, 10)
+	assert.ErrorIs(t, err, ErrDueDateTooFar)
+	assert.Contains(t, err.Error(), "2036-10-16")
+
+	assert.ErrorIs(t, ValidateDueDate(at(time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)), now, 10), ErrDueDateTooFar)
+}
+
+func TestValidateDueDate_ZeroDisablesLimit(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, ValidateDueDate(at(time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)), now, 0))
+}
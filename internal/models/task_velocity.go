@@ -0,0 +1,99 @@
+package models
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Task velocity is reported over the last N days, given as "30d".
+const (
+	DefaultVelocityWindowDays = 30
+	MaxVelocityWindowDays     = 365
+)
+
+// Periods task velocity can be bucketed by.
+const (
+	VelocityPeriodDay  = "day"
+	VelocityPeriodWeek = "week"
+)
+
+// VelocityPoint is the number of tasks completed in the period starting at
+// Start (midnight UTC).
+type VelocityPoint struct {
+	Start     time.Time `json:"start"`
+	Completed int       `json:"completed"`
+}
+
+// TaskVelocity summarizes task completions over a window of whole UTC days
+// ending today. Average is completions per period across the whole window,
+// rounded to two decimal places; Series has one point per period, oldest
+// first, and includes periods with no completions.
+type TaskVelocity struct {
+	WindowDays int             `json:"window_days"`
+	Period     string          `json:"period"`
+	Completed  int             `json:"completed"`
+	Average    float64         `json:"average"`
+	Series     []VelocityPoint `json:"series"`
+}
+
+// ParseVelocityWindow reads a window such as "30d" as a number of days.
+func ParseVelocityWindow(value string) (int, error) {
+	days, ok := strings.CutSuffix(value, "d")
+	if !ok {
+		return 0, ErrInvalidVelocityWindow
+	}
+	n, err := strconv.Atoi(days)
+	if err != nil || n < 1 || n > MaxVelocityWindowDays {
+		return 0, ErrInvalidVelocityWindow
+	}
+	return n, nil
+}
+
+// ValidateVelocityPeriod checks period against the supported buckets.
+func ValidateVelocityPeriod(period string) error {
+	if period != VelocityPeriodDay && period != VelocityPeriodWeek {
+		return ErrInvalidVelocityPeriod
+	}
+	return nil
+}
+
+// VelocityWindowStart returns midnight UTC of the first day of a windowDays
+// window ending on now's UTC date.
+func VelocityWindowStart(windowDays int, now time.Time) time.Time {
+	year, month, day := now.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1-windowDays)
+}
+
+// ComputeTaskVelocity buckets completedAt into periods over the window. Week
+// periods are consecutive 7-day spans from the window start, so the last one
+// may be shorter. Completions outside the window are ignored.
+func ComputeTaskVelocity(completedAt []time.Time, windowDays int, period string, now time.Time) TaskVelocity {
+	periodDays := 1
+	if period == VelocityPeriodWeek {
+		periodDays = 7
+	}
+
+	start := VelocityWindowStart(windowDays, now)
+	end := start.AddDate(0, 0, windowDays)
+
+	velocity := TaskVelocity{WindowDays: windowDays, Period: period, Series: []VelocityPoint{}}
+	for day := 0; day < windowDays; day += periodDays {
+		velocity.Series = append(velocity.Series, VelocityPoint{Start: start.AddDate(0, 0, day)})
+	}
+
+	for _, at := range completedAt {
+		if at.Before(start) || !at.Before(end) {
+			continue
+		}
+		days := int(at.Sub(start) / (24 * time.Hour))
+		velocity.Series[days/periodDays].Completed++
+		velocity.Completed++
+	}
+
+	periods := float64(windowDays) / float64(periodDays)
+	velocity.Average = math.Round(float64(velocity.Completed)/periods*100) / 100
+
+	return velocity
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeTaskVelocity_Daily(t *testing.T) {
+	now := time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)
+	completed := []time.Time{
+		time.Date(2026, 10, 10, 8, 0, 0, 0, time.UTC), // before the window
+		time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC),
+		time.Date(2026, 10, 14, 18, 0, 0, 0, time.UTC),
+		time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 10, 16, 11, 30, 0, 0, time.UTC),
+		time.Date(2026, 10, 16, 14, 0, 0, 0, time.UTC),
+	}
+
+	velocity := ComputeTaskVelocity(completed, 4, VelocityPeriodDay, now)
+
+	assert.Equal(t, 4, velocity.WindowDays)
+	assert.Equal(t, 5, velocity.Completed)
+	assert.Equal(t, 1.25, velocity.Average)
+	assert.Equal(t, []VelocityPoint{
+		{Start: time.Date(2026, 10, 13, 0, 0, 0, 0, time.UTC), Completed: 0},
+		{Start: time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC), Completed: 2},
+		{Start: time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC), Completed: 0},
+		{Start: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), Completed: 3},
+	}, velocity.Series)
+}
+
+func TestComputeTaskVelocity_WeeklyAverageUsesWholeWindow(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	start := VelocityWindowStart(30, now)
+	var completed []time.Time
+	for i := 0; i < 15; i++ {
+		completed = append(completed, start.AddDate(0, 0, i*2).Add(9*time.Hour))
+	}
+
+	velocity := ComputeTaskVelocity(completed, 30, VelocityPeriodWeek, now)
+
+	assert.Equal(t, 15, velocity.Completed)
+	// 15 completions over 30/7 weeks.
+	assert.Equal(t, 3.5, velocity.Average)
+	// Four full weeks and a trailing two-day period.
+	if assert.Len(t, velocity.Series, 5) {
+		assert.Equal(t, start.AddDate(0, 0, 28), velocity.Series[4].Start)
+		assert.Equal(t, 1, velocity.Series[4].Completed)
+	}
+}
+
+func TestComputeTaskVelocity_NoCompletions(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	velocity := ComputeTaskVelocity(nil, 7, VelocityPeriodDay, now)
+
+	assert.Equal(t, 0, velocity.Completed)
+	assert.Equal(t, 0.0, velocity.Average)
+	assert.Len(t, velocity.Series, 7)
+	for _, point := range velocity.Series {
+		assert.Equal(t, 0, point.Completed)
+	}
+}
+
+func TestParseVelocityWindow(t *testing.T) {
+	days, err := ParseVelocityWindow("30d")
+	assert.NoError(t, err)
+	assert.Equal(t, 30, days)
+
+	for _, value := range []string{"30", "d", "0d", "-1d", "366d", "4w"} {
+		_, err := ParseVelocityWindow(value)
+		assert.ErrorIs(t, err, ErrInvalidVelocityWindow, value)
+	}
+}
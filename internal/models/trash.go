@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Trash item types.
+const (
+	TrashTypeTask  = "task"
+	TrashTypeHabit = "habit"
+)
+
+// TrashItem is a deleted task or habit that can still be restored. Title is
+// the task's title or the habit's name. PurgeAt is when it is deleted for
+// good, and RestoreURL is the path to POST to bring it back.
+type TrashItem struct {
+	Type       string    `json:"type"`
+	ID         uuid.UUID `json:"id"`
+	Title      string    `json:"title"`
+	DeletedAt  time.Time `json:"deleted_at"`
+	PurgeAt    time.Time `json:"purge_at"`
+	RestoreURL string    `json:"restore_url"`
+}
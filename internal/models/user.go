@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,6 +13,50 @@ type User struct {
 	Name      string    `json:"name" db:"name"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	// DailyLogReminderTime is the local "HH:MM" time to remind the user to
+	// fill in the day's log, if they haven't yet. Nil disables the reminder.
+	DailyLogReminderTime *string `json:"daily_log_reminder_time" db:"daily_log_reminder_time"`
+	// WaterUnit is the unit daily log water intake is read and entered in.
+	WaterUnit WaterUnit `json:"water_unit" db:"water_unit"`
+	// WeekStart is the day the user's weeks begin on in weekly reports.
+	WeekStart WeekStart `json:"week_start" db:"week_start"`
+}
+
+// UpdateDailyLogReminderRequest sets the daily log reminder time; a null time
+// turns the reminder off.
+type UpdateDailyLogReminderRequest struct {
+	Time *string `json:"time"`
+}
+
+func (r *UpdateDailyLogReminderRequest) Validate() error {
+	if r.Time == nil {
+		return nil
+	}
+	if !reminderTimePattern.MatchString(*r.Time) {
+		return fmt.Errorf("%w: %q", ErrInvalidReminderTime, *r.Time)
+	}
+	return nil
+}
+
+// UpdateWaterUnitRequest sets the unit daily log water intake is read and
+// entered in.
+type UpdateWaterUnitRequest struct {
+	Unit string `json:"unit" binding:"required"`
+}
+
+func (r *UpdateWaterUnitRequest) Validate() error {
+	_, err := ParseWaterUnit(r.Unit)
+	return err
+}
+
+// UpdateWeekStartRequest sets the day the user's weeks begin on.
+type UpdateWeekStartRequest struct {
+	Day string `json:"day" binding:"required"`
+}
+
+func (r *UpdateWeekStartRequest) Validate() error {
+	_, err := ParseWeekStart(r.Day)
+	return err
 }
 
 type UserContext struct {
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ValidationProfile sets how strictly input is checked beyond the rules every
+// request must meet. Development runs lenient, so clients can experiment with
+// new icons or backfill old tasks; production runs strict.
+type ValidationProfile struct {
+	Name string
+	// RestrictIcons limits habit icons to HabitIcons.
+	RestrictIcons bool
+	// RejectUnknownFields rejects JSON bodies with fields the request type
+	// doesn't define, as STRICT_JSON does.
+	RejectUnknownFields bool
+	// RejectPastDueDates rejects newly set task due dates that have passed.
+	RejectPastDueDates bool
+}
+
+var (
+	LenientValidation = ValidationProfile{Name: "lenient"}
+	StrictValidation  = ValidationProfile{
+		Name:                "strict",
+		RestrictIcons:       true,
+		RejectUnknownFields: true,
+		RejectPastDueDates:  true,
+	}
+)
+
+// ValidationProfileFor picks the profile for an environment: name when set,
+// otherwise strict in production and lenient anywhere else.
+func ValidationProfileFor(appEnv, name string) (ValidationProfile, error) {
+	switch name {
+	case "":
+		if appEnv == "production" {
+			return StrictValidation, nil
+		}
+		return LenientValidation, nil
+	case LenientValidation.Name:
+		return LenientValidation, nil
+	case StrictValidation.Name:
+		return StrictValidation, nil
+	}
+	return ValidationProfile{}, fmt.Errorf("%w: %q", ErrInvalidValidationProfile, name)
+}
+
+// HabitIcons are the icons the apps can draw, enforced under RestrictIcons.
+var HabitIcons = []string{
+	"bed", "bike", "book", "brain", "check", "checklist", "code", "coffee",
+	"dumbbell", "glass", "heart", "leaf", "meditation", "moon", "music", "pen",
+	"run", "running", "star", "sun", "target", "walk", "water", "yoga",
+}
+
+// ValidateIcon rejects an icon outside HabitIcons when the profile restricts
+// icons.
+func (p ValidationProfile) ValidateIcon(icon string) error {
+	if p.RestrictIcons && !slices.Contains(HabitIcons, icon) {
+		return fmt.Errorf("%w: %q", ErrInvalidIcon, icon)
+	}
+	return nil
+}
+
+// ValidateDueDate rejects a due date that has passed when the profile rejects
+// past due dates. A day of grace keeps a task due earlier today valid in any
+// timezone.
+func (p ValidationProfile) ValidateDueDate(due *time.Time, now time.Time) error {
+	if p.RejectPastDueDates && due != nil && due.Before(now.AddDate(0, 0, -1)) {
+		return ErrDueDateInPast
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidationProfileFor(t *testing.T) {
+	for _, tc := range []struct {
+		env, name string
+		want      ValidationProfile
+	}{
+		{"production", "", StrictValidation},
+		{"development", "", LenientValidation},
+		{"staging", "", LenientValidation},
+		{"production", "lenient", LenientValidation},
+		{"development", "strict", StrictValidation},
+	} {
+		profile, err := ValidationProfileFor(tc.env, tc.name)
+		assert.NoError(t, err, tc.env+"/"+tc.name)
+		assert.Equal(t, tc.want, profile, tc.env+"/"+tc.name)
+	}
+
+	_, err := ValidationProfileFor("production", "paranoid")
+	assert.ErrorIs(t, err, ErrInvalidValidationProfile)
+}
+
+func TestValidationProfile_ValidateIcon(t *testing.T) {
+	assert.NoError(t, LenientValidation.ValidateIcon("juggling-clubs"))
+	assert.NoError(t, StrictValidation.ValidateIcon("dumbbell"))
+	assert.ErrorIs(t, StrictValidation.ValidateIcon("juggling-clubs"), ErrInvalidIcon)
+}
+
+func TestValidationProfile_ValidateDueDate(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	lastWeek := now.AddDate(0, 0, -7)
+	earlierToday := now.Add(-6 * time.Hour)
+	tomorrow := now.AddDate(0, 0, 1)
+
+	assert.NoError(t, LenientValidation.ValidateDueDate(&lastWeek, now))
+	assert.ErrorIs(t, StrictValidation.ValidateDueDate(&lastWeek, now), ErrDueDateInPast)
+	assert.NoError(t, StrictValidation.ValidateDueDate(&earlierToday, now))
+	assert.NoError(t, StrictValidation.ValidateDueDate(&tomorrow, now))
+	assert.NoError(t, StrictValidation.ValidateDueDate(nil, now))
+}
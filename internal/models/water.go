@@ -0,0 +1,75 @@
+package models
+
+import (
+	"fmt"
+	"math"
+)
+
+// WaterUnit is the unit a user reads and enters water intake in. Intake is
+// always stored in whole glasses of 250 ml; the API converts to and from the
+// user's unit at the edge.
+type WaterUnit string
+
+const (
+	WaterUnitGlasses WaterUnit = "glasses"
+	WaterUnitLiters  WaterUnit = "liters"
+	WaterUnitOunces  WaterUnit = "ounces"
+)
+
+// DefaultWaterUnit is used until the user picks another unit.
+const DefaultWaterUnit = WaterUnitGlasses
+
+// MaxWaterIntake is the most water a daily log may record, in glasses.
+const MaxWaterIntake = 20
+
+const (
+	millilitersPerGlass = 250.0
+	millilitersPerOunce = 29.5735295625 // US fluid ounce
+)
+
+// waterUnitScale gives how many of each unit make a glass and how many
+// decimals amounts in that unit are rounded to.
+var waterUnitScale = map[WaterUnit]struct {
+	perGlass float64
+	decimals int
+}{
+	WaterUnitGlasses: {1, 2},
+	WaterUnitLiters:  {millilitersPerGlass / 1000, 2},
+	WaterUnitOunces:  {millilitersPerGlass / millilitersPerOunce, 1},
+}
+
+// ParseWaterUnit validates a unit name.
+func ParseWaterUnit(s string) (WaterUnit, error) {
+	unit := WaterUnit(s)
+	if _, ok := waterUnitScale[unit]; !ok {
+		return "", ErrInvalidWaterUnit
+	}
+	return unit, nil
+}
+
+// FromGlasses converts an amount in glasses to u, rounded to u's precision.
+func (u WaterUnit) FromGlasses(glasses float64) float64 {
+	scale := waterUnitScale[u.orDefault()]
+	pow := math.Pow(10, float64(scale.decimals))
+	return math.Round(glasses*scale.perGlass*pow) / pow
+}
+
+// ToGlasses converts an amount in u to the nearest whole glass. Any amount
+// that rounds to between 0 and MaxWaterIntake glasses is accepted, so the
+// rounded values FromGlasses returns always convert back to the same count.
+func (u WaterUnit) ToGlasses(amount float64) (int, error) {
+	u = u.orDefault()
+	glasses := math.Round(amount / waterUnitScale[u].perGlass)
+	if amount < 0 || glasses > MaxWaterIntake {
+		return 0, fmt.Errorf("%w: must be between 0 and %g %s", ErrInvalidWaterIntake, u.FromGlasses(MaxWaterIntake), u)
+	}
+	return int(glasses), nil
+}
+
+// orDefault returns u, or DefaultWaterUnit when u isn't a known unit.
+func (u WaterUnit) orDefault() WaterUnit {
+	if _, ok := waterUnitScale[u]; !ok {
+		return DefaultWaterUnit
+	}
+	return u
+}
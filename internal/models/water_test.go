@@ -0,0 +1,87 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWaterUnit(t *testing.T) {
+	for _, name := range []string{"glasses", "liters", "ounces"} {
+		unit, err := ParseWaterUnit(name)
+		assert.NoError(t, err)
+		assert.Equal(t, WaterUnit(name), unit)
+	}
+
+	_, err := ParseWaterUnit("cups")
+	assert.ErrorIs(t, err, ErrInvalidWaterUnit)
+
+	_, err = ParseWaterUnit("")
+	assert.ErrorIs(t, err, ErrInvalidWaterUnit)
+}
+
+func TestWaterUnit_FromGlasses(t *testing.T) {
+	assert.Equal(t, 8.0, WaterUnitGlasses.FromGlasses(8))
+	assert.Equal(t, 2.0, WaterUnitLiters.FromGlasses(8))
+	assert.Equal(t, 0.25, WaterUnitLiters.FromGlasses(1))
+	assert.Equal(t, 8.5, WaterUnitOunces.FromGlasses(1))
+	assert.Equal(t, 169.1, WaterUnitOunces.FromGlasses(MaxWaterIntake))
+}
+
+func TestWaterUnit_RoundTripsEveryStoredAmount(t *testing.T) {
+	for _, unit := range []WaterUnit{WaterUnitGlasses, WaterUnitLiters, WaterUnitOunces} {
+		for glasses := 0; glasses <= MaxWaterIntake; glasses++ {
+			back, err := unit.ToGlasses(unit.FromGlasses(float64(glasses)))
+			if assert.NoError(t, err, "%d glasses in %s", glasses, unit) {
+				assert.Equal(t, glasses, back, "%d glasses in %s", glasses, unit)
+			}
+		}
+	}
+}
+
+func TestWaterUnit_ToGlassesRoundsToNearestGlass(t *testing.T) {
+	glasses, err := WaterUnitLiters.ToGlasses(1.3)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, glasses)
+
+	glasses, err = WaterUnitOunces.ToGlasses(64)
+	assert.NoError(t, err)
+	assert.Equal(t, 8, glasses)
+
+	glasses, err = WaterUnitGlasses.ToGlasses(7.4)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, glasses)
+}
+
+func TestWaterUnit_ToGlassesBoundaries(t *testing.T) {
+	tests := []struct {
+		unit     WaterUnit
+		max      float64
+		tooMuch  float64
+		negative float64
+	}{
+		{WaterUnitGlasses, 20, 20.5, -1},
+		{WaterUnitLiters, 5, 5.13, -0.25},
+		{WaterUnitOunces, 169.1, 173.5, -0.1},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.unit), func(t *testing.T) {
+			glasses, err := tt.unit.ToGlasses(0)
+			assert.NoError(t, err)
+			assert.Equal(t, 0, glasses)
+
+			glasses, err = tt.unit.ToGlasses(tt.max)
+			assert.NoError(t, err)
+			assert.Equal(t, MaxWaterIntake, glasses)
+
+			_, err = tt.unit.ToGlasses(tt.tooMuch)
+			if assert.ErrorIs(t, err, ErrInvalidWaterIntake) {
+				assert.Contains(t, err.Error(), string(tt.unit))
+			}
+
+			_, err = tt.unit.ToGlasses(tt.negative)
+			assert.ErrorIs(t, err, ErrInvalidWaterIntake)
+		})
+	}
+}
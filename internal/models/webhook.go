@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// Webhook is a user-registered endpoint. SecretVersion starts at 1 and goes
+// up each time Secret is rotated.
+type Webhook struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	UserID        uuid.UUID `json:"user_id" db:"user_id"`
+	URL           string    `json:"url" db:"url"`
+	Secret        string    `json:"-" db:"secret"`
+	SecretVersion int       `json:"secret_version" db:"secret_version"`
+	IsActive      bool      `json:"is_active" db:"is_active"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookDelivery is one event sent (or to be sent) to a webhook. The Last*
+// fields and SecretVersion, the version of the secret that signed it,
+// describe the most recent attempt.
+type WebhookDelivery struct {
+	ID                 uuid.UUID       `json:"id" db:"id"`
+	WebhookID          uuid.UUID       `json:"webhook_id" db:"webhook_id"`
+	UserID             uuid.UUID       `json:"user_id" db:"user_id"`
+	Event              string          `json:"event" db:"event"`
+	Payload            json.RawMessage `json:"payload" db:"payload"`
+	Status             string          `json:"status" db:"status"`
+	Attempts           int             `json:"attempts" db:"attempts"`
+	LastResponseStatus *int            `json:"last_response_status" db:"last_response_status"`
+	LastError          *string         `json:"last_error" db:"last_error"`
+	LastAttemptAt      *time.Time      `json:"last_attempt_at" db:"last_attempt_at"`
+	SecretVersion      *int            `json:"secret_version" db:"secret_version"`
+	CreatedAt          time.Time       `json:"created_at" db:"created_at"`
+}
+
+// RecordAttempt applies the outcome of a delivery attempt signed with
+// secretVersion and made at the given time. statusCode is 0 when no response
+// was received.
+func (d *WebhookDelivery) RecordAttempt(secretVersion, statusCode int, err error, at time.Time) {
+	d.Attempts++
+	d.LastAttemptAt = &at
+	d.SecretVersion = &secretVersion
+	d.LastResponseStatus = nil
+	if statusCode != 0 {
+		d.LastResponseStatus = &statusCode
+	}
+
+	if err != nil {
+		message := err.Error()
+		d.Status = WebhookDeliveryFailed
+		d.LastError = &message
+		return
+	}
+
+	d.Status = WebhookDeliverySucceeded
+	d.LastError = nil
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// WeekStart is the day a user's weeks begin on. Weekly habit periods,
+// streaks and the planner week all start on it.
+type WeekStart string
+
+const (
+	WeekStartMonday WeekStart = "monday"
+	WeekStartSunday WeekStart = "sunday"
+)
+
+// DefaultWeekStart is used until the user picks another day.
+const DefaultWeekStart = WeekStartMonday
+
+// ParseWeekStart validates a week start name.
+func ParseWeekStart(s string) (WeekStart, error) {
+	switch start := WeekStart(s); start {
+	case WeekStartMonday, WeekStartSunday:
+		return start, nil
+	}
+	return "", ErrInvalidWeekStart
+}
+
+// Weekday returns the day w names, or DefaultWeekStart's day when w isn't a
+// known week start.
+func (w WeekStart) Weekday() time.Weekday {
+	if w == WeekStartSunday {
+		return time.Sunday
+	}
+	return time.Monday
+}
+
+// StartOf returns midnight, in t's location, of the first day of the week
+// containing t.
+func (w WeekStart) StartOf(t time.Time) time.Time {
+	year, month, day := t.Date()
+	offset := (int(t.Weekday()) - int(w.Weekday()) + 7) % 7
+	return time.Date(year, month, day-offset, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWeekStart(t *testing.T) {
+	for _, name := range []string{"monday", "sunday"} {
+		start, err := ParseWeekStart(name)
+		assert.NoError(t, err)
+		assert.Equal(t, WeekStart(name), start)
+	}
+
+	for _, name := range []string{"", "Monday", "saturday"} {
+		_, err := ParseWeekStart(name)
+		assert.ErrorIs(t, err, ErrInvalidWeekStart, name)
+	}
+}
+
+func TestWeekStart_StartOf(t *testing.T) {
+	saturday := time.Date(2025, 11, 15, 18, 0, 0, 0, time.UTC)
+	sunday := time.Date(2025, 11, 16, 8, 0, 0, 0, time.UTC)
+	monday := time.Date(2025, 11, 17, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		start WeekStart
+		t     time.Time
+		want  time.Time
+	}{
+		{"monday week on saturday", WeekStartMonday, saturday, time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC)},
+		{"monday week on sunday", WeekStartMonday, sunday, time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC)},
+		{"monday week on monday", WeekStartMonday, monday, monday},
+		{"sunday week on saturday", WeekStartSunday, saturday, time.Date(2025, 11, 9, 0, 0, 0, 0, time.UTC)},
+		{"sunday week on sunday", WeekStartSunday, sunday, time.Date(2025, 11, 16, 0, 0, 0, 0, time.UTC)},
+		{"sunday week on monday", WeekStartSunday, monday, time.Date(2025, 11, 16, 0, 0, 0, 0, time.UTC)},
+		{"unset defaults to monday", "", sunday, time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.start.StartOf(tt.t))
+		})
+	}
+}
+
+func TestWeekStart_StartOfKeepsLocation(t *testing.T) {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	// Sunday morning in Tokyo is still Saturday in UTC.
+	at := time.Date(2025, 11, 16, 7, 0, 0, 0, tokyo)
+
+	assert.Equal(t, time.Date(2025, 11, 16, 0, 0, 0, 0, tokyo), WeekStartSunday.StartOf(at))
+}
@@ -0,0 +1,87 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursors_RoundTrip(t *testing.T) {
+	cursors := NewCursors("secret")
+	userID := uuid.New()
+	cursor := Cursor{Date: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), ID: uuid.New()}
+
+	decoded, err := cursors.Decode(userID, cursors.Encode(userID, cursor))
+
+	assert.NoError(t, err)
+	assert.True(t, decoded.Date.Equal(cursor.Date))
+	assert.Equal(t, cursor.ID, decoded.ID)
+}
+
+func TestCursors_RoundTripKeepsTimestamp(t *testing.T) {
+	cursors := NewCursors("secret")
+	userID := uuid.New()
+	at := time.Date(2026, 10, 16, 7, 30, 12, 345678900, time.FixedZone("EDT", -4*3600))
+
+	decoded, err := cursors.Decode(userID, cursors.Encode(userID, Cursor{Date: at, ID: uuid.New()}))
+
+	assert.NoError(t, err)
+	assert.True(t, decoded.Date.Equal(at))
+}
+
+func TestCursors_DecodesDateOnlyTokens(t *testing.T) {
+	cursors := NewCursors("secret")
+	userID := uuid.New()
+	id := uuid.New()
+	payload := userID.String() + "|2026-10-16|" + id.String()
+	token := base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + cursors.mac(payload)
+
+	decoded, err := cursors.Decode(userID, token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), decoded.Date)
+	assert.Equal(t, id, decoded.ID)
+}
+
+func TestCursors_RejectsTamperedPayload(t *testing.T) {
+	cursors := NewCursors("secret")
+	userID := uuid.New()
+	token := cursors.Encode(userID, Cursor{Date: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), ID: uuid.New()})
+
+	_, sig, _ := strings.Cut(token, ".")
+	forged := userID.String() + "|2020-01-01|" + uuid.New().String()
+	tampered := base64.RawURLEncoding.EncodeToString([]byte(forged)) + "." + sig
+
+	_, err := cursors.Decode(userID, tampered)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestCursors_RejectsOtherUsersCursor(t *testing.T) {
+	cursors := NewCursors("secret")
+	token := cursors.Encode(uuid.New(), Cursor{Date: time.Now(), ID: uuid.New()})
+
+	_, err := cursors.Decode(uuid.New(), token)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestCursors_RejectsMalformedAndForeignTokens(t *testing.T) {
+	cursors := NewCursors("secret")
+	userID := uuid.New()
+	cursor := Cursor{Date: time.Now(), ID: uuid.New()}
+
+	for name, token := range map[string]string{
+		"empty":        "",
+		"no signature": "abc",
+		"bad base64":   "!!!.abc",
+		"other secret": NewCursors("other").Encode(userID, cursor),
+		"raw date,id":  "2026-10-16," + cursor.ID.String(),
+		"truncated":    cursors.Encode(userID, cursor)[:40],
+	} {
+		_, err := cursors.Decode(userID, token)
+		assert.ErrorIs(t, err, ErrInvalidCursor, name)
+	}
+}
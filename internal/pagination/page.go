@@ -0,0 +1,50 @@
+package pagination
+
+// Pagination is the metadata a paginated listing returns alongside its
+// page. Cursor listings set NextCursor and offset listings NextOffset, only
+// while HasMore; Total is included only where counting is cheap.
+type Pagination struct {
+	HasMore    bool    `json:"has_more"`
+	NextCursor *string `json:"next_cursor,omitempty"`
+	NextOffset *int    `json:"next_offset,omitempty"`
+	Total      *int    `json:"total,omitempty"`
+}
+
+// CursorPage trims items, fetched with one row beyond limit to learn whether
+// another page follows, to the page itself and describes it. cursor encodes
+// the position of a page's last item. A limit of 0 means the listing is
+// unpaginated, so items is everything and its length is the total.
+func CursorPage[T any](items []T, limit int, cursor func(last T) string) ([]T, Pagination) {
+	if limit <= 0 {
+		total := len(items)
+		return items, Pagination{Total: &total}
+	}
+
+	if len(items) <= limit {
+		return items, Pagination{}
+	}
+
+	items = items[:limit]
+	next := cursor(items[len(items)-1])
+	return items, Pagination{HasMore: true, NextCursor: &next}
+}
+
+// OffsetPage trims items, fetched from offset with one row beyond limit, to
+// the page itself and describes it. total may be nil when counting isn't
+// cheap; when given, it decides HasMore.
+func OffsetPage[T any](items []T, offset, limit int, total *int) ([]T, Pagination) {
+	hasMore := len(items) > limit
+	if hasMore {
+		items = items[:limit]
+	}
+	if total != nil {
+		hasMore = offset+len(items) < *total
+	}
+
+	page := Pagination{HasMore: hasMore, Total: total}
+	if hasMore {
+		next := offset + len(items)
+		page.NextOffset = &next
+	}
+	return items, page
+}
@@ -0,0 +1,116 @@
+package pagination
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rows stands in for a listing of n items; each page query fetches one
+// row beyond limit, as the handlers do.
+func rows(n int) []int {
+	items := make([]int, n)
+	for i := range items {
+		items[i] = i
+	}
+	return items
+}
+
+func fetch(all []int, from, limit int) []int {
+	end := min(from+limit+1, len(all))
+	return all[from:end]
+}
+
+func itemCursor(last int) string { return strconv.Itoa(last) }
+
+func TestCursorPage_FirstMiddleLast(t *testing.T) {
+	all := rows(5)
+
+	first, meta := CursorPage(fetch(all, 0, 2), 2, itemCursor)
+	assert.Equal(t, []int{0, 1}, first)
+	assert.True(t, meta.HasMore)
+	if assert.NotNil(t, meta.NextCursor) {
+		assert.Equal(t, "1", *meta.NextCursor)
+	}
+	assert.Nil(t, meta.Total)
+
+	middle, meta := CursorPage(fetch(all, 2, 2), 2, itemCursor)
+	assert.Equal(t, []int{2, 3}, middle)
+	assert.True(t, meta.HasMore)
+	if assert.NotNil(t, meta.NextCursor) {
+		assert.Equal(t, "3", *meta.NextCursor)
+	}
+
+	last, meta := CursorPage(fetch(all, 4, 2), 2, itemCursor)
+	assert.Equal(t, []int{4}, last)
+	assert.Equal(t, Pagination{}, meta)
+}
+
+func TestCursorPage_ExactlyFullLastPage(t *testing.T) {
+	items, meta := CursorPage(fetch(rows(4), 2, 2), 2, itemCursor)
+
+	assert.Equal(t, []int{2, 3}, items)
+	assert.False(t, meta.HasMore)
+	assert.Nil(t, meta.NextCursor)
+}
+
+func TestCursorPage_UnpaginatedReportsTotal(t *testing.T) {
+	items, meta := CursorPage(rows(3), 0, itemCursor)
+
+	assert.Len(t, items, 3)
+	assert.False(t, meta.HasMore)
+	if assert.NotNil(t, meta.Total) {
+		assert.Equal(t, 3, *meta.Total)
+	}
+}
+
+func TestOffsetPage_FirstMiddleLast(t *testing.T) {
+	all := rows(5)
+
+	first, meta := OffsetPage(fetch(all, 0, 2), 0, 2, nil)
+	assert.Equal(t, []int{0, 1}, first)
+	assert.True(t, meta.HasMore)
+	if assert.NotNil(t, meta.NextOffset) {
+		assert.Equal(t, 2, *meta.NextOffset)
+	}
+
+	middle, meta := OffsetPage(fetch(all, 2, 2), 2, 2, nil)
+	assert.Equal(t, []int{2, 3}, middle)
+	if assert.NotNil(t, meta.NextOffset) {
+		assert.Equal(t, 4, *meta.NextOffset)
+	}
+
+	last, meta := OffsetPage(fetch(all, 4, 2), 4, 2, nil)
+	assert.Equal(t, []int{4}, last)
+	assert.False(t, meta.HasMore)
+	assert.Nil(t, meta.NextOffset)
+}
+
+func TestOffsetPage_WithTotal(t *testing.T) {
+	all := rows(5)
+	total := len(all)
+
+	_, meta := OffsetPage(fetch(all, 0, 2), 0, 2, &total)
+	assert.True(t, meta.HasMore)
+	assert.Equal(t, 2, *meta.NextOffset)
+	assert.Equal(t, 5, *meta.Total)
+
+	_, meta = OffsetPage(fetch(all, 2, 2), 2, 2, &total)
+	assert.True(t, meta.HasMore)
+	assert.Equal(t, 4, *meta.NextOffset)
+
+	_, meta = OffsetPage(fetch(all, 4, 2), 4, 2, &total)
+	assert.False(t, meta.HasMore)
+	assert.Nil(t, meta.NextOffset)
+	assert.Equal(t, 5, *meta.Total)
+}
+
+func TestOffsetPage_PastTheEnd(t *testing.T) {
+	total := 5
+
+	items, meta := OffsetPage([]int{}, 10, 2, &total)
+
+	assert.Empty(t, items)
+	assert.False(t, meta.HasMore)
+}
@@ -0,0 +1,39 @@
+// Package querycount tracks how many database queries are issued on behalf of
+// a single request, to make N+1 query patterns visible during development.
+package querycount
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type contextKey struct{}
+
+// Counter counts queries. It is safe for concurrent use.
+type Counter struct {
+	n atomic.Int64
+}
+
+// Count returns the number of queries recorded so far.
+func (c *Counter) Count() int64 {
+	return c.n.Load()
+}
+
+// NewContext returns a context carrying a fresh counter.
+func NewContext(ctx context.Context) (context.Context, *Counter) {
+	counter := &Counter{}
+	return context.WithValue(ctx, contextKey{}, counter), counter
+}
+
+// FromContext returns the counter carried by ctx, or nil.
+func FromContext(ctx context.Context) *Counter {
+	counter, _ := ctx.Value(contextKey{}).(*Counter)
+	return counter
+}
+
+// Inc records one query against the counter carried by ctx, if any.
+func Inc(ctx context.Context) {
+	if counter := FromContext(ctx); counter != nil {
+		counter.n.Add(1)
+	}
+}
@@ -0,0 +1,31 @@
+package querycount
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInc_CountsAgainstContextCounter(t *testing.T) {
+	ctx, counter := NewContext(context.Background())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			Inc(ctx)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(10), counter.Count())
+	assert.Same(t, counter, FromContext(ctx))
+}
+
+func TestInc_WithoutCounterIsNoop(t *testing.T) {
+	assert.NotPanics(t, func() { Inc(context.Background()) })
+	assert.Nil(t, FromContext(context.Background()))
+}
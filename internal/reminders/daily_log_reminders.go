@@ -0,0 +1,66 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+)
+
+// DailyLogReminderTimes returns a user's daily log reminder time as "HH:MM",
+// or "" when the reminder is off.
+type DailyLogReminderTimes interface {
+	GetDailyLogReminderTime(ctx context.Context, userID uuid.UUID) (string, error)
+}
+
+// DailyLogs looks up the user's log for a date, returning models.ErrNotFound
+// when there is none.
+type DailyLogs interface {
+	GetByDate(ctx context.Context, userID uuid.UUID, date time.Time) (*models.DailyLog, error)
+}
+
+// DailyLogReminders is the Processor that reminds a user to fill in the day's
+// log when their reminder time fell within the last window and no log exists
+// yet for their current local day.
+type DailyLogReminders struct {
+	settings  DailyLogReminderTimes
+	timezones UserTimezones
+	logs      DailyLogs
+	notifier  Notifier
+	window    time.Duration
+}
+
+func NewDailyLogReminders(settings DailyLogReminderTimes, timezones UserTimezones, logs DailyLogs, notifier Notifier, window time.Duration) *DailyLogReminders {
+	return &DailyLogReminders{settings: settings, timezones: timezones, logs: logs, notifier: notifier, window: window}
+}
+
+func (p *DailyLogReminders) ProcessUser(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	at, err := p.settings.GetDailyLogReminderTime(ctx, userID)
+	if err != nil || at == "" {
+		return err
+	}
+
+	local, err := userLocalTime(ctx, p.timezones, userID, now)
+	if err != nil {
+		return err
+	}
+
+	for _, due := range models.RemindersBetween([]string{at}, local.Add(-p.window), local) {
+		_, err := p.logs.GetByDate(ctx, userID, localDate(due))
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, models.ErrNotFound) {
+			return err
+		}
+
+		if err := p.notifier.Notify(ctx, Reminder{UserID: userID, Kind: KindDailyLog, At: due}); err != nil {
+			return fmt.Errorf("failed to notify daily log reminder: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeReminderTime string
+
+func (f fakeReminderTime) GetDailyLogReminderTime(ctx context.Context, userID uuid.UUID) (string, error) {
+	return string(f), nil
+}
+
+// fakeDailyLogs holds the dates that have a log.
+type fakeDailyLogs struct {
+	dates   map[time.Time]bool
+	queried []time.Time
+}
+
+func (f *fakeDailyLogs) GetByDate(ctx context.Context, userID uuid.UUID, date time.Time) (*models.DailyLog, error) {
+	f.queried = append(f.queried, date)
+	if !f.dates[date] {
+		return nil, models.ErrNotFound
+	}
+	return &models.DailyLog{UserID: userID, Date: date}, nil
+}
+
+func TestDailyLogReminders_FiresWhenNoLogExists(t *testing.T) {
+	logs := &fakeDailyLogs{}
+	notifier := &collectingNotifier{}
+	processor := NewDailyLogReminders(fakeReminderTime("21:00"), fakeTimezones("America/New_York"), logs, notifier, time.Minute)
+
+	// 01:00:30 UTC on the 17th is 21:00:30 on the 16th in New York (EDT).
+	now := time.Date(2026, 10, 17, 1, 0, 30, 0, time.UTC)
+	userID := uuid.New()
+	assert.NoError(t, processor.ProcessUser(context.Background(), userID, now))
+
+	if assert.Len(t, notifier.reminders, 1) {
+		assert.Equal(t, KindDailyLog, notifier.reminders[0].Kind)
+		assert.Equal(t, userID, notifier.reminders[0].UserID)
+		assert.Equal(t, 21, notifier.reminders[0].At.Hour())
+	}
+	assert.Equal(t, []time.Time{time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)}, logs.queried)
+}
+
+func TestDailyLogReminders_SuppressedOnceLogExists(t *testing.T) {
+	logs := &fakeDailyLogs{dates: map[time.Time]bool{}}
+	notifier := &collectingNotifier{}
+	processor := NewDailyLogReminders(fakeReminderTime("21:00"), fakeTimezones("UTC"), logs, notifier, time.Minute)
+	now := time.Date(2026, 10, 16, 21, 0, 30, 0, time.UTC)
+
+	logs.dates[time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)] = true
+	assert.NoError(t, processor.ProcessUser(context.Background(), uuid.New(), now))
+	assert.Empty(t, notifier.reminders)
+}
+
+func TestDailyLogReminders_OnlyAtReminderTime(t *testing.T) {
+	logs := &fakeDailyLogs{}
+	notifier := &collectingNotifier{}
+	processor := NewDailyLogReminders(fakeReminderTime("21:00"), fakeTimezones("UTC"), logs, notifier, time.Minute)
+
+	now := time.Date(2026, 10, 16, 20, 0, 30, 0, time.UTC)
+	assert.NoError(t, processor.ProcessUser(context.Background(), uuid.New(), now))
+	assert.Empty(t, notifier.reminders)
+	assert.Empty(t, logs.queried)
+}
+
+func TestDailyLogReminders_OffWhenUnset(t *testing.T) {
+	logs := &fakeDailyLogs{}
+	notifier := &collectingNotifier{}
+	processor := NewDailyLogReminders(fakeReminderTime(""), fakeTimezones("UTC"), logs, notifier, time.Minute)
+
+	now := time.Date(2026, 10, 16, 21, 0, 30, 0, time.UTC)
+	assert.NoError(t, processor.ProcessUser(context.Background(), uuid.New(), now))
+	assert.Empty(t, notifier.reminders)
+}
+
+type failingProcessor struct{ calls int }
+
+func (p *failingProcessor) ProcessUser(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	p.calls++
+	return errors.New("boom")
+}
+
+func TestProcessors_RunsEveryProcessor(t *testing.T) {
+	first, second := &failingProcessor{}, &failingProcessor{}
+
+	err := Processors{first, second}.ProcessUser(context.Background(), uuid.New(), time.Now())
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, first.calls)
+	assert.Equal(t, 1, second.calls)
+}
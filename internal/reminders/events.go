@@ -0,0 +1,44 @@
+package reminders
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lumen/backend/internal/models"
+)
+
+// ReminderEvents stores a record of each reminder sent.
+type ReminderEvents interface {
+	Create(ctx context.Context, event *models.ReminderEvent) error
+}
+
+// RecordingNotifier records a reminder event before handing the reminder,
+// now carrying the event's ID, on to the next notifier. The client
+// acknowledges that ID when the user acts on the reminder.
+type RecordingNotifier struct {
+	events ReminderEvents
+	next   Notifier
+}
+
+func NewRecordingNotifier(events ReminderEvents, next Notifier) *RecordingNotifier {
+	return &RecordingNotifier{events: events, next: next}
+}
+
+func (n *RecordingNotifier) Notify(ctx context.Context, reminder Reminder) error {
+	event := &models.ReminderEvent{
+		UserID: reminder.UserID,
+		Kind:   reminder.Kind,
+		SentAt: reminder.At,
+	}
+	if reminder.Kind == KindHabit {
+		habitID := reminder.Habit.ID
+		event.HabitID = &habitID
+	}
+
+	if err := n.events.Create(ctx, event); err != nil {
+		return fmt.Errorf("failed to record reminder event: %w", err)
+	}
+
+	reminder.EventID = event.ID
+	return n.next.Notify(ctx, reminder)
+}
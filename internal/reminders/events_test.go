@@ -0,0 +1,66 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeReminderEvents struct {
+	created []models.ReminderEvent
+	err     error
+}
+
+func (f *fakeReminderEvents) Create(ctx context.Context, event *models.ReminderEvent) error {
+	if f.err != nil {
+		return f.err
+	}
+	event.ID = uuid.New()
+	f.created = append(f.created, *event)
+	return nil
+}
+
+func TestRecordingNotifier_RecordsHabitReminder(t *testing.T) {
+	events := &fakeReminderEvents{}
+	next := &collectingNotifier{}
+	habit := models.Habit{ID: uuid.New(), Name: "Water"}
+	at := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+
+	err := NewRecordingNotifier(events, next).Notify(context.Background(), Reminder{UserID: userID, Kind: KindHabit, Habit: habit, At: at})
+
+	assert.NoError(t, err)
+	if assert.Len(t, events.created, 1) && assert.Len(t, next.reminders, 1) {
+		recorded := events.created[0]
+		assert.Equal(t, userID, recorded.UserID)
+		assert.Equal(t, KindHabit, recorded.Kind)
+		assert.Equal(t, &habit.ID, recorded.HabitID)
+		assert.Equal(t, at, recorded.SentAt)
+		assert.Equal(t, recorded.ID, next.reminders[0].EventID, "the delivered reminder carries the event to acknowledge")
+	}
+}
+
+func TestRecordingNotifier_DailyLogReminderHasNoHabit(t *testing.T) {
+	events := &fakeReminderEvents{}
+
+	err := NewRecordingNotifier(events, &collectingNotifier{}).Notify(context.Background(), Reminder{UserID: uuid.New(), Kind: KindDailyLog, At: time.Now()})
+
+	assert.NoError(t, err)
+	if assert.Len(t, events.created, 1) {
+		assert.Nil(t, events.created[0].HabitID)
+	}
+}
+
+func TestRecordingNotifier_DoesNotSendUnrecordedReminder(t *testing.T) {
+	next := &collectingNotifier{}
+
+	err := NewRecordingNotifier(&fakeReminderEvents{err: errors.New("boom")}, next).Notify(context.Background(), Reminder{UserID: uuid.New(), Kind: KindDailyLog})
+
+	assert.Error(t, err)
+	assert.Empty(t, next.reminders)
+}
@@ -0,0 +1,138 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Reminder kinds.
+const (
+	KindHabit    = "habit"
+	KindDailyLog = "daily_log"
+)
+
+// Reminder is a reminder that has come due. Habit is only set for habit
+// reminders. EventID, when set, identifies the recorded reminder event the
+// client acknowledges once the user acts on it.
+type Reminder struct {
+	UserID  uuid.UUID
+	Kind    string
+	Habit   models.Habit
+	At      time.Time
+	EventID uuid.UUID
+}
+
+// Notifier delivers a due reminder to the user.
+type Notifier interface {
+	Notify(ctx context.Context, reminder Reminder) error
+}
+
+// LogNotifier logs reminders instead of delivering them, until a push or
+// email channel is wired in.
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(ctx context.Context, reminder Reminder) error {
+	if reminder.Kind == KindDailyLog {
+		logger.Info("Daily log reminder due",
+			zap.String("user_id", reminder.UserID.String()),
+			zap.String("event_id", reminder.EventID.String()),
+			zap.Time("at", reminder.At),
+		)
+		return nil
+	}
+
+	logger.Info("Habit reminder due",
+		zap.String("user_id", reminder.UserID.String()),
+		zap.String("habit_id", reminder.Habit.ID.String()),
+		zap.String("event_id", reminder.EventID.String()),
+		zap.Time("at", reminder.At),
+	)
+	return nil
+}
+
+// Processors runs each processor for a user in turn. Every processor gets
+// its turn even if an earlier one fails; the errors are joined.
+type Processors []Processor
+
+func (ps Processors) ProcessUser(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	var errs []error
+	for _, p := range ps {
+		if err := p.ProcessUser(ctx, userID, now); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DueHabits returns the user's habits still due on a date.
+type DueHabits interface {
+	GetDueOn(ctx context.Context, userID uuid.UUID, date time.Time) ([]models.Habit, error)
+}
+
+type UserTimezones interface {
+	GetUserTimezone(ctx context.Context, userID uuid.UUID) (string, error)
+}
+
+// HabitReminders is the Processor that notifies a user of reminders whose
+// time fell within the last window (normally the scheduler interval), for
+// habits still due that day. Habits already done for the period are skipped.
+type HabitReminders struct {
+	habits    DueHabits
+	timezones UserTimezones
+	notifier  Notifier
+	window    time.Duration
+}
+
+func NewHabitReminders(habits DueHabits, timezones UserTimezones, notifier Notifier, window time.Duration) *HabitReminders {
+	return &HabitReminders{habits: habits, timezones: timezones, notifier: notifier, window: window}
+}
+
+func (p *HabitReminders) ProcessUser(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	local, err := userLocalTime(ctx, p.timezones, userID, now)
+	if err != nil {
+		return err
+	}
+
+	habits, err := p.habits.GetDueOn(ctx, userID, localDate(local))
+	if err != nil {
+		return err
+	}
+
+	for _, habit := range habits {
+		for _, at := range habit.RemindersBetween(local.Add(-p.window), local) {
+			if err := p.notifier.Notify(ctx, Reminder{UserID: userID, Kind: KindHabit, Habit: habit, At: at}); err != nil {
+				return fmt.Errorf("failed to notify habit reminder: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// userLocalTime returns now in the user's timezone, falling back to UTC when
+// the stored timezone is unknown.
+func userLocalTime(ctx context.Context, timezones UserTimezones, userID uuid.UUID, now time.Time) (time.Time, error) {
+	timezone, err := timezones.GetUserTimezone(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return now.In(loc), nil
+}
+
+// localDate is the user's calendar date as midnight UTC, the form dates are
+// stored and queried in (as with the date query parameter of GET /habits/due).
+func localDate(local time.Time) time.Time {
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
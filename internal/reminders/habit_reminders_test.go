@@ -0,0 +1,64 @@
+package reminders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeDueHabits struct {
+	habits []models.Habit
+	date   time.Time
+}
+
+func (f *fakeDueHabits) GetDueOn(ctx context.Context, userID uuid.UUID, date time.Time) ([]models.Habit, error) {
+	f.date = date
+	return f.habits, nil
+}
+
+type fakeTimezones string
+
+func (f fakeTimezones) GetUserTimezone(ctx context.Context, userID uuid.UUID) (string, error) {
+	return string(f), nil
+}
+
+type collectingNotifier struct {
+	reminders []Reminder
+}
+
+func (n *collectingNotifier) Notify(ctx context.Context, reminder Reminder) error {
+	n.reminders = append(n.reminders, reminder)
+	return nil
+}
+
+func TestHabitReminders_NotifiesRemindersInWindowInUserTimezone(t *testing.T) {
+	water := models.Habit{ID: uuid.New(), Name: "Water", ReminderTimes: []string{"09:00", "15:00"}}
+	read := models.Habit{ID: uuid.New(), Name: "Read", ReminderTimes: []string{"08:58"}}
+	habits := &fakeDueHabits{habits: []models.Habit{water, read}}
+	notifier := &collectingNotifier{}
+	processor := NewHabitReminders(habits, fakeTimezones("America/New_York"), notifier, time.Minute)
+
+	// 13:00:30 UTC is 09:00:30 in New York (EDT).
+	now := time.Date(2026, 10, 16, 13, 0, 30, 0, time.UTC)
+	assert.NoError(t, processor.ProcessUser(context.Background(), uuid.New(), now))
+
+	if assert.Len(t, notifier.reminders, 1) {
+		assert.Equal(t, water.ID, notifier.reminders[0].Habit.ID)
+		assert.Equal(t, 9, notifier.reminders[0].At.Hour())
+	}
+	assert.Equal(t, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), habits.date)
+}
+
+func TestHabitReminders_UnknownTimezoneFallsBackToUTC(t *testing.T) {
+	habit := models.Habit{ID: uuid.New(), ReminderTimes: []string{"13:00"}}
+	notifier := &collectingNotifier{}
+	processor := NewHabitReminders(&fakeDueHabits{habits: []models.Habit{habit}}, fakeTimezones("Mars/Olympus"), notifier, time.Minute)
+
+	now := time.Date(2026, 10, 16, 13, 0, 30, 0, time.UTC)
+	assert.NoError(t, processor.ProcessUser(context.Background(), uuid.New(), now))
+	assert.Len(t, notifier.reminders, 1)
+}
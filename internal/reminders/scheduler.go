@@ -0,0 +1,260 @@
+// Package reminders periodically works through every user with habit
+// reminders and notifies them of the ones that have come due.
+package reminders
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// UserLister pages through the users the scheduler should process, ordered
+// by ID; after is the last ID of the previous batch (uuid.Nil to start).
+type UserLister interface {
+	ListUserIDs(ctx context.Context, after uuid.UUID, limit int) ([]uuid.UUID, error)
+}
+
+// Processor handles one user for the tick that started at now.
+type Processor interface {
+	ProcessUser(ctx context.Context, userID uuid.UUID, now time.Time) error
+}
+
+type Options struct {
+	// Interval between ticks.
+	Interval time.Duration
+	// TickTimeout is a soft budget per tick: once it passes no further users
+	// are started, in-flight ones finish, and the rest wait for the next
+	// tick, which processes them against the cut-short tick's time.
+	TickTimeout time.Duration
+	// BatchSize is how many user IDs are listed per query.
+	BatchSize int
+	// Concurrency bounds how many users are processed at once.
+	Concurrency int
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Interval:    time.Minute,
+		TickTimeout: 50 * time.Second,
+		BatchSize:   500,
+		Concurrency: 8,
+	}
+}
+
+// TickResult describes one completed tick.
+type TickResult struct {
+	Started  time.Time
+	Duration time.Duration
+	Users    int
+	Failed   int
+	// TimedOut reports that the soft timeout cut the tick short.
+	TimedOut bool
+}
+
+// Stats are cumulative scheduler metrics.
+type Stats struct {
+	Ticks          int64         `json:"ticks"`
+	TicksSkipped   int64         `json:"ticks_skipped"`
+	TicksTimedOut  int64         `json:"ticks_timed_out"`
+	UsersProcessed int64         `json:"users_processed"`
+	LastDuration   time.Duration `json:"last_duration"`
+	LastUsers      int64         `json:"last_users"`
+}
+
+// Scheduler runs ticks on an interval. A tick that is still running when the
+// next one is due causes that one to be skipped, so ticks never overlap.
+type Scheduler struct {
+	users     UserLister
+	processor Processor
+	opts      Options
+
+	running atomic.Bool
+	// deferred are the passes cut short by the soft timeout, oldest first.
+	// The next tick finishes them before starting its own. Only touched by
+	// the running tick.
+	deferred []pass
+
+	ticks          atomic.Int64
+	ticksSkipped   atomic.Int64
+	ticksTimedOut  atomic.Int64
+	usersProcessed atomic.Int64
+	lastDuration   atomic.Int64
+	lastUsers      atomic.Int64
+}
+
+func NewScheduler(users UserLister, processor Processor, opts Options) *Scheduler {
+	if opts.BatchSize < 1 {
+		opts.BatchSize = 1
+	}
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+	return &Scheduler{users: users, processor: processor, opts: opts}
+}
+
+func (s *Scheduler) Stats() Stats {
+	return Stats{
+		Ticks:          s.ticks.Load(),
+		TicksSkipped:   s.ticksSkipped.Load(),
+		TicksTimedOut:  s.ticksTimedOut.Load(),
+		UsersProcessed: s.usersProcessed.Load(),
+		LastDuration:   time.Duration(s.lastDuration.Load()),
+		LastUsers:      s.lastUsers.Load(),
+	}
+}
+
+// Run starts a tick every Interval until ctx is cancelled, then waits for
+// the running tick to finish.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.opts.Interval)
+	defer ticker.Stop()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if !s.running.CompareAndSwap(false, true) {
+				s.ticksSkipped.Add(1)
+				logger.Warn("Reminder tick skipped, previous tick still running")
+				continue
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer s.running.Store(false)
+				s.Tick(ctx, now)
+			}()
+		}
+	}
+}
+
+// maxDeferredPasses caps how many cut-short passes wait for later ticks.
+// Past it the oldest is dropped: its reminders are too stale to be useful.
+const maxDeferredPasses = 5
+
+// pass is one walk over the user list in ID order, processing every user
+// after after against now.
+type pass struct {
+	now   time.Time
+	after uuid.UUID
+}
+
+// Tick processes users in batches on a bounded worker pool until every user
+// is done, the soft timeout passes, or ctx is cancelled. Passes deferred by
+// earlier ticks are finished first, each against its own tick's time so the
+// users it didn't reach still get the reminders of that tick's window; then
+// the tick walks every user against now. Ticks must not run concurrently;
+// Run guarantees that.
+func (s *Scheduler) Tick(ctx context.Context, now time.Time) TickResult {
+	result := TickResult{Started: time.Now()}
+
+	budget, cancel := ctx, context.CancelFunc(func() {})
+	if s.opts.TickTimeout > 0 {
+		budget, cancel = context.WithTimeout(ctx, s.opts.TickTimeout)
+	}
+	defer cancel()
+
+	var (
+		wg     sync.WaitGroup
+		slots  = make(chan struct{}, s.opts.Concurrency)
+		users  atomic.Int64
+		failed atomic.Int64
+	)
+
+	passes := append(s.deferred, pass{now: now})
+	s.deferred = nil
+
+	for i, p := range passes {
+		after, done := s.runPass(ctx, budget, p, &wg, slots, &users, &failed)
+		if done {
+			continue
+		}
+		s.deferred = append([]pass{{now: p.now, after: after}}, passes[i+1:]...)
+		if dropped := len(s.deferred) - maxDeferredPasses; dropped > 0 {
+			logger.Warn("Reminder passes dropped, ticks keep hitting their soft timeout", zap.Int("dropped", dropped))
+			s.deferred = s.deferred[dropped:]
+		}
+		break
+	}
+
+	wg.Wait()
+
+	result.Duration = time.Since(result.Started)
+	result.Users = int(users.Load())
+	result.Failed = int(failed.Load())
+	result.TimedOut = budget.Err() == context.DeadlineExceeded && ctx.Err() == nil
+
+	s.ticks.Add(1)
+	s.usersProcessed.Add(users.Load())
+	s.lastDuration.Store(int64(result.Duration))
+	s.lastUsers.Store(users.Load())
+	if result.TimedOut {
+		s.ticksTimedOut.Add(1)
+		logger.Warn("Reminder tick hit its soft timeout, remaining users deferred",
+			zap.Duration("timeout", s.opts.TickTimeout),
+			zap.Int("users_processed", result.Users),
+		)
+	}
+
+	logger.Info("Reminder tick finished",
+		zap.Duration("duration", result.Duration),
+		zap.Int("users_processed", result.Users),
+		zap.Int("users_failed", result.Failed),
+	)
+
+	return result
+}
+
+// runPass dispatches the users of p to the worker pool. It reports whether
+// the pass reached the end of the user list; if the soft budget ran out
+// first, after is the last user it started.
+func (s *Scheduler) runPass(ctx, budget context.Context, p pass, wg *sync.WaitGroup, slots chan struct{}, users, failed *atomic.Int64) (uuid.UUID, bool) {
+	after := p.after
+	for {
+		ids, err := s.users.ListUserIDs(budget, after, s.opts.BatchSize)
+		if err != nil {
+			if budget.Err() != nil {
+				return after, false
+			}
+			logger.Error("Failed to list users for reminders", zap.Error(err))
+			return after, true
+		}
+
+		for _, id := range ids {
+			select {
+			case slots <- struct{}{}:
+			case <-budget.Done():
+				return after, false
+			}
+			after = id
+
+			wg.Add(1)
+			go func(userID uuid.UUID) {
+				defer wg.Done()
+				defer func() { <-slots }()
+
+				// In-flight users run under ctx, not the soft budget, so a
+				// timeout never abandons a user halfway through.
+				if err := s.processor.ProcessUser(ctx, userID, p.now); err != nil {
+					failed.Add(1)
+					logger.Error("Failed to process reminders", zap.Error(err), zap.String("user_id", userID.String()))
+				}
+				users.Add(1)
+			}(id)
+		}
+
+		if len(ids) < s.opts.BatchSize {
+			return after, true
+		}
+	}
+}
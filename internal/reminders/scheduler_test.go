@@ -0,0 +1,237 @@
+package reminders
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeUsers struct {
+	ids []uuid.UUID
+}
+
+func newFakeUsers(n int) *fakeUsers {
+	ids := make([]uuid.UUID, n)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+	sort.Slice(ids, func(i, j int) bool { return bytes.Compare(ids[i][:], ids[j][:]) < 0 })
+	return &fakeUsers{ids: ids}
+}
+
+func (f *fakeUsers) ListUserIDs(ctx context.Context, after uuid.UUID, limit int) ([]uuid.UUID, error) {
+	var page []uuid.UUID
+	for _, id := range f.ids {
+		if bytes.Compare(id[:], after[:]) > 0 && len(page) < limit {
+			page = append(page, id)
+		}
+	}
+	return page, nil
+}
+
+// recordingProcessor counts calls per user and the peak number of users in
+// flight at once.
+type recordingProcessor struct {
+	delay time.Duration
+
+	mu      sync.Mutex
+	calls   map[uuid.UUID]int
+	nows    map[uuid.UUID][]time.Time
+	active  atomic.Int32
+	maxSeen atomic.Int32
+}
+
+func newRecordingProcessor(delay time.Duration) *recordingProcessor {
+	return &recordingProcessor{delay: delay, calls: make(map[uuid.UUID]int), nows: make(map[uuid.UUID][]time.Time)}
+}
+
+func (p *recordingProcessor) ProcessUser(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	active := p.active.Add(1)
+	defer p.active.Add(-1)
+	for {
+		peak := p.maxSeen.Load()
+		if active <= peak || p.maxSeen.CompareAndSwap(peak, active) {
+			break
+		}
+	}
+
+	time.Sleep(p.delay)
+
+	p.mu.Lock()
+	p.calls[userID]++
+	p.nows[userID] = append(p.nows[userID], now)
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *recordingProcessor) processed() map[uuid.UUID]int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	calls := make(map[uuid.UUID]int, len(p.calls))
+	for id, n := range p.calls {
+		calls[id] = n
+	}
+	return calls
+}
+
+// processedAt counts the users processed against now.
+func (p *recordingProcessor) processedAt(now time.Time) map[uuid.UUID]int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	calls := make(map[uuid.UUID]int)
+	for id, nows := range p.nows {
+		for _, at := range nows {
+			if at.Equal(now) {
+				calls[id]++
+			}
+		}
+	}
+	return calls
+}
+
+func TestTick_ProcessesAllUsersWithBoundedConcurrency(t *testing.T) {
+	users := newFakeUsers(200)
+	processor := newRecordingProcessor(time.Millisecond)
+	scheduler := NewScheduler(users, processor, Options{BatchSize: 25, Concurrency: 4})
+
+	result := scheduler.Tick(context.Background(), time.Now())
+
+	assert.Equal(t, 200, result.Users)
+	assert.False(t, result.TimedOut)
+	assert.LessOrEqual(t, processor.maxSeen.Load(), int32(4))
+	assert.Greater(t, processor.maxSeen.Load(), int32(1))
+
+	calls := processor.processed()
+	assert.Len(t, calls, 200)
+	for _, n := range calls {
+		assert.Equal(t, 1, n)
+	}
+
+	stats := scheduler.Stats()
+	assert.Equal(t, int64(1), stats.Ticks)
+	assert.Equal(t, int64(200), stats.UsersProcessed)
+	assert.Equal(t, int64(200), stats.LastUsers)
+}
+
+func TestRun_SkipsTicksInsteadOfOverlapping(t *testing.T) {
+	users := newFakeUsers(3)
+	// One worker and a per-user delay well over the interval: overlapping
+	// ticks would show up as more than one user in flight.
+	processor := newRecordingProcessor(15 * time.Millisecond)
+	scheduler := NewScheduler(users, processor, Options{Interval: 5 * time.Millisecond, BatchSize: 10, Concurrency: 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+	scheduler.Run(ctx)
+
+	stats := scheduler.Stats()
+	assert.Equal(t, int32(1), processor.maxSeen.Load())
+	assert.Greater(t, stats.TicksSkipped, int64(0))
+	assert.GreaterOrEqual(t, stats.Ticks, int64(1))
+}
+
+func TestTick_SoftTimeoutDefersRemainingUsers(t *testing.T) {
+	users := newFakeUsers(12)
+	processor := newRecordingProcessor(10 * time.Millisecond)
+	scheduler := NewScheduler(users, processor, Options{TickTimeout: 25 * time.Millisecond, BatchSize: 5, Concurrency: 1})
+
+	cutShort := time.Date(2026, 10, 16, 9, 0, 30, 0, time.UTC)
+	first := scheduler.Tick(context.Background(), cutShort)
+	assert.True(t, first.TimedOut)
+	assert.Less(t, first.Users, 12)
+
+	// Later ticks finish the cut-short pass against its own time before
+	// their own, so every user is processed for it exactly once.
+	for i := 1; i <= 20 && len(processor.processedAt(cutShort)) < 12; i++ {
+		scheduler.Tick(context.Background(), cutShort.Add(time.Duration(i)*time.Minute))
+	}
+
+	calls := processor.processedAt(cutShort)
+	assert.Len(t, calls, 12)
+	for _, n := range calls {
+		assert.Equal(t, 1, n)
+	}
+	assert.Greater(t, scheduler.Stats().TicksTimedOut, int64(0))
+}
+
+func TestTick_LaterTickStillCoversUsersBeforeTheCutOff(t *testing.T) {
+	users := newFakeUsers(6)
+	processor := newRecordingProcessor(10 * time.Millisecond)
+	scheduler := NewScheduler(users, processor, Options{TickTimeout: 25 * time.Millisecond, BatchSize: 5, Concurrency: 1})
+
+	cutShort := time.Date(2026, 10, 16, 9, 0, 30, 0, time.UTC)
+	assert.True(t, scheduler.Tick(context.Background(), cutShort).TimedOut)
+
+	// With no timeout the next tick finishes the deferred pass, then walks
+	// every user from the start of the list for its own time.
+	scheduler.opts.TickTimeout = 0
+	next := cutShort.Add(time.Minute)
+	scheduler.Tick(context.Background(), next)
+
+	assert.Len(t, processor.processedAt(cutShort), 6)
+	assert.Len(t, processor.processedAt(next), 6)
+	assert.Empty(t, scheduler.deferred)
+}
+
+// slowProcessor delays each user before handing it to the wrapped Processor.
+type slowProcessor struct {
+	Processor
+	delay time.Duration
+}
+
+func (p slowProcessor) ProcessUser(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	time.Sleep(p.delay)
+	return p.Processor.ProcessUser(ctx, userID, now)
+}
+
+// lockedNotifier collects reminders from concurrent users.
+type lockedNotifier struct {
+	mu        sync.Mutex
+	reminders []Reminder
+}
+
+func (n *lockedNotifier) Notify(ctx context.Context, reminder Reminder) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.reminders = append(n.reminders, reminder)
+	return nil
+}
+
+func TestTick_TimedOutUsersStillGetTheOriginalWindowsReminders(t *testing.T) {
+	users := newFakeUsers(8)
+	habit := models.Habit{ID: uuid.New(), ReminderTimes: []string{"09:00"}}
+	notifier := &lockedNotifier{}
+	reminders := NewHabitReminders(&fakeDueHabits{habits: []models.Habit{habit}}, fakeTimezones("UTC"), notifier, time.Minute)
+	scheduler := NewScheduler(users, slowProcessor{Processor: reminders, delay: 10 * time.Millisecond},
+		Options{TickTimeout: 25 * time.Millisecond, BatchSize: 5, Concurrency: 1})
+
+	// The 09:00 reminder falls in the first tick's window only; the later
+	// ticks' windows start at 09:00:30 and beyond.
+	start := time.Date(2026, 10, 16, 9, 0, 30, 0, time.UTC)
+	assert.True(t, scheduler.Tick(context.Background(), start).TimedOut)
+	for i := 1; i <= 10; i++ {
+		scheduler.Tick(context.Background(), start.Add(time.Duration(i)*time.Minute))
+	}
+
+	notified := make(map[uuid.UUID]int)
+	notifier.mu.Lock()
+	for _, reminder := range notifier.reminders {
+		assert.Equal(t, 9, reminder.At.Hour())
+		assert.Equal(t, 0, reminder.At.Minute())
+		notified[reminder.UserID]++
+	}
+	notifier.mu.Unlock()
+
+	assert.Len(t, notified, 8)
+	for _, n := range notified {
+		assert.Equal(t, 1, n)
+	}
+}
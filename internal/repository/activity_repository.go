@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+)
+
+type ActivityRepository interface {
+	GetFeed(ctx context.Context, userID uuid.UUID, page models.ActivityPage) ([]models.ActivityEvent, error)
+}
+
+type activityRepository struct {
+	db *Database
+}
+
+func NewActivityRepository(db *Database) ActivityRepository {
+	return &activityRepository{db: db}
+}
+
+// activityFeedSource is the user's activity ($1) as (id, type, subject_id,
+// title, occurred_at) rows, one branch per event type. Each row that records
+// an event is its own entry, so the feed needs no separate event table and
+// covers history from before the feed existed.
+const activityFeedSource = `
+	SELECT id, '` + models.ActivityTaskCompleted + `' AS type, id AS subject_id, title, completed_at AS occurred_at
+	FROM tasks
+	WHERE user_id = $1 AND completed_at IS NOT NULL AND deleted_at IS NULL
+	UNION ALL
+	SELECT hc.id, '` + models.ActivityHabitCompleted + `', h.id, h.name, hc.completed_at
+	FROM habit_completions hc
+	JOIN habits h ON h.id = hc.habit_id AND h.deleted_at IS NULL
+	WHERE hc.user_id = $1
+	UNION ALL
+	SELECT id, '` + models.ActivityDailyLogSaved + `', id, to_char(date, 'YYYY-MM-DD'), updated_at
+	FROM daily_logs
+	WHERE user_id = $1
+	UNION ALL
+	SELECT id, '` + models.ActivityGoalReached + `', id, title, updated_at
+	FROM goals
+	WHERE user_id = $1 AND status = 'completed'`
+
+// GetFeed returns one page of the user's activity, newest first, keyed on
+// (occurred_at, id) so pages stay stable while new activity arrives.
+func (r *activityRepository) GetFeed(ctx context.Context, userID uuid.UUID, page models.ActivityPage) ([]models.ActivityEvent, error) {
+	where, args := activityFeedClause(userID, page)
+	query := `
+		SELECT id, type, subject_id, title, occurred_at
+		FROM (` + activityFeedSource + `) AS activity
+		WHERE ` + where + `
+		ORDER BY occurred_at DESC, id DESC`
+
+	if page.Limit > 0 {
+		args = append(args, page.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.db.Reader(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get activity feed: %w", err)
+	}
+	defer rows.Close()
+
+	var feed []models.ActivityEvent
+	for rows.Next() {
+		var event models.ActivityEvent
+		if err := rows.Scan(&event.ID, &event.Type, &event.SubjectID, &event.Title, &event.OccurredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan activity event: %w", err)
+		}
+		feed = append(feed, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating activity feed: %w", err)
+	}
+
+	return feed, nil
+}
+
+// activityFeedClause builds the WHERE clause applied to the combined feed:
+// only entries after the cursor. $1 is the user, already bound by
+// activityFeedSource.
+func activityFeedClause(userID uuid.UUID, page models.ActivityPage) (string, []interface{}) {
+	where := "TRUE"
+	args := []interface{}{userID}
+
+	if page.After != nil {
+		args = append(args, page.After.Date, page.After.ID)
+		where = fmt.Sprintf("(occurred_at, id) < ($%d, $%d)", len(args)-1, len(args))
+	}
+
+	return where, args
+}
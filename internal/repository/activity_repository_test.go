@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/pagination"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActivityFeedSource_TypesEachBranch(t *testing.T) {
+	assert.Contains(t, activityFeedSource, "'task.completed' AS type")
+	assert.Contains(t, activityFeedSource, "'habit.completed', h.id, h.name, hc.completed_at")
+	assert.Contains(t, activityFeedSource, "'daily_log.saved', id, to_char(date, 'YYYY-MM-DD'), updated_at")
+	assert.Contains(t, activityFeedSource, "'goal.reached', id, title, updated_at")
+	assert.Equal(t, 4, strings.Count(activityFeedSource, "user_id = $1"))
+}
+
+func TestActivityFeedClause_FirstPage(t *testing.T) {
+	userID := uuid.New()
+
+	where, args := activityFeedClause(userID, models.ActivityPage{Limit: 20})
+
+	assert.Equal(t, "TRUE", where)
+	assert.Equal(t, []interface{}{userID}, args)
+}
+
+func TestActivityFeedClause_KeysetAfterCursor(t *testing.T) {
+	userID := uuid.New()
+	after := pagination.Cursor{Date: time.Date(2026, 10, 16, 7, 30, 0, 0, time.UTC), ID: uuid.New()}
+
+	where, args := activityFeedClause(userID, models.ActivityPage{After: &after})
+
+	// Matches the ORDER BY occurred_at DESC, id DESC tiebreak, so entries
+	// sharing a timestamp are neither skipped nor repeated across pages.
+	assert.Equal(t, "(occurred_at, id) < ($2, $3)", where)
+	assert.Equal(t, []interface{}{userID, after.Date, after.ID}, args)
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/lumen/backend/internal/models"
+)
+
+// CalendarFeedRepository stores the version of each user's calendar feed
+// token. Only tokens signed with the current version are accepted.
+type CalendarFeedRepository interface {
+	GetFeedTokenVersion(ctx context.Context, userID uuid.UUID) (int, error)
+	RotateFeedToken(ctx context.Context, userID uuid.UUID) (int, error)
+}
+
+type calendarFeedRepository struct {
+	db *Database
+}
+
+func NewCalendarFeedRepository(db *Database) CalendarFeedRepository {
+	return &calendarFeedRepository{db: db}
+}
+
+// GetFeedTokenVersion returns the user's current feed token version. It reads
+// from the primary so a rotation revokes the old URL at once, not once the
+// replica catches up.
+func (r *calendarFeedRepository) GetFeedTokenVersion(ctx context.Context, userID uuid.UUID) (int, error) {
+	var version int
+	err := r.db.Writer().QueryRow(ctx, `SELECT calendar_feed_token_version FROM users WHERE id = $1`, userID).Scan(&version)
+
+	if err == pgx.ErrNoRows {
+		return 0, models.ErrNotFound
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to get calendar feed token version: %w", err)
+	}
+
+	return version, nil
+}
+
+// RotateFeedToken bumps the user's feed token version, revoking every feed
+// URL issued before, and returns the new version.
+func (r *calendarFeedRepository) RotateFeedToken(ctx context.Context, userID uuid.UUID) (int, error) {
+	var version int
+	err := r.db.Writer().QueryRow(ctx, `
+		UPDATE users SET calendar_feed_token_version = calendar_feed_token_version + 1
+		WHERE id = $1
+		RETURNING calendar_feed_token_version
+	`, userID).Scan(&version)
+
+	if err == pgx.ErrNoRows {
+		return 0, models.ErrNotFound
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to rotate calendar feed token: %w", err)
+	}
+
+	return version, nil
+}
@@ -2,28 +2,35 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lumen/backend/internal/fieldcrypt"
 	"github.com/lumen/backend/internal/models"
 )
 
 type DailyLogRepository interface {
 	Create(ctx context.Context, log *models.DailyLog) error
 	GetByDate(ctx context.Context, userID uuid.UUID, date time.Time) (*models.DailyLog, error)
-	GetByDateRange(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) ([]models.DailyLog, error)
+	GetByDateRange(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, page models.DailyLogPage) ([]models.DailyLog, error)
+	GetAverages(ctx context.Context, userID uuid.UUID, startDate, endDate *time.Time) (*models.DailyLogAverages, error)
 	Update(ctx context.Context, log *models.DailyLog) error
 	Delete(ctx context.Context, id, userID uuid.UUID) error
 }
 
+// dailyLogRepository encrypts notes with the notes keyring on write and
+// decrypts them on read; a nil keyring stores them as plaintext.
 type dailyLogRepository struct {
-	db *Database
+	db    *Database
+	notes *fieldcrypt.Keyring
 }
 
-func NewDailyLogRepository(db *Database) DailyLogRepository {
-	return &dailyLogRepository{db: db}
+func NewDailyLogRepository(db *Database, notes *fieldcrypt.Keyring) DailyLogRepository {
+	return &dailyLogRepository{db: db, notes: notes}
 }
 
 func (r *dailyLogRepository) Create(ctx context.Context, log *models.DailyLog) error {
@@ -31,9 +38,9 @@ func (r *dailyLogRepository) Create(ctx context.Context, log *models.DailyLog) e
 		INSERT INTO daily_logs (
 			id, user_id, date, morning_routine, evening_routine, water_intake,
 			sleep_hours, energy_level, mood_rating, productivity_rating, notes,
-			created_at, updated_at
+			is_rest_day
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		ON CONFLICT (user_id, date) DO UPDATE SET
 			morning_routine = EXCLUDED.morning_routine,
 			evening_routine = EXCLUDED.evening_routine,
@@ -43,15 +50,19 @@ func (r *dailyLogRepository) Create(ctx context.Context, log *models.DailyLog) e
 			mood_rating = EXCLUDED.mood_rating,
 			productivity_rating = EXCLUDED.productivity_rating,
 			notes = EXCLUDED.notes,
-			updated_at = EXCLUDED.updated_at
+			is_rest_day = EXCLUDED.is_rest_day,
+			` + touchUpdatedAt + `
 		RETURNING id, created_at, updated_at
 	`
 
 	log.ID = uuid.New()
-	log.CreatedAt = time.Now()
-	log.UpdatedAt = time.Now()
 
-	err := r.db.Pool.QueryRow(
+	notes, err := r.notes.Encrypt(log.Notes)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt daily log notes: %w", err)
+	}
+
+	err = r.db.Writer().QueryRow(
 		ctx,
 		query,
 		log.ID,
@@ -64,29 +75,45 @@ func (r *dailyLogRepository) Create(ctx context.Context, log *models.DailyLog) e
 		log.EnergyLevel,
 		log.MoodRating,
 		log.ProductivityRating,
-		log.Notes,
-		log.CreatedAt,
-		log.UpdatedAt,
+		notes,
+		log.IsRestDay,
 	).Scan(&log.ID, &log.CreatedAt, &log.UpdatedAt)
 
 	if err != nil {
-		return fmt.Errorf("failed to create daily log: %w", err)
+		return dailyLogCreateError(err)
 	}
 
 	return nil
 }
 
+// dailyLogCreateError maps database errors from Create. The upsert relies on
+// the unique (user_id, date) index from the daily_log_unique_date migration:
+// a unique violation means another log holds the date, and a missing index
+// makes Postgres reject the ON CONFLICT clause outright.
+func dailyLogCreateError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return models.ErrDailyLogExists
+		case "42P10":
+			return fmt.Errorf("failed to create daily log: daily_logs lacks its unique (user_id, date) index: %w", err)
+		}
+	}
+	return fmt.Errorf("failed to create daily log: %w", err)
+}
+
 func (r *dailyLogRepository) GetByDate(ctx context.Context, userID uuid.UUID, date time.Time) (*models.DailyLog, error) {
 	query := `
 		SELECT id, user_id, date, morning_routine, evening_routine, water_intake,
 		       sleep_hours, energy_level, mood_rating, productivity_rating, notes,
-		       created_at, updated_at
+		       is_rest_day, created_at, updated_at
 		FROM daily_logs
 		WHERE user_id = $1 AND date = $2
 	`
 
 	var log models.DailyLog
-	err := r.db.Pool.QueryRow(ctx, query, userID, date).Scan(
+	err := r.db.Reader(ctx).QueryRow(ctx, query, userID, date).Scan(
 		&log.ID,
 		&log.UserID,
 		&log.Date,
@@ -98,6 +125,7 @@ func (r *dailyLogRepository) GetByDate(ctx context.Context, userID uuid.UUID, da
 		&log.MoodRating,
 		&log.ProductivityRating,
 		&log.Notes,
+		&log.IsRestDay,
 		&log.CreatedAt,
 		&log.UpdatedAt,
 	)
@@ -110,20 +138,39 @@ func (r *dailyLogRepository) GetByDate(ctx context.Context, userID uuid.UUID, da
 		return nil, fmt.Errorf("failed to get daily log: %w", err)
 	}
 
+	if log.Notes, err = r.notes.Decrypt(log.Notes); err != nil {
+		return nil, fmt.Errorf("failed to decrypt daily log notes: %w", err)
+	}
+
 	return &log, nil
 }
 
-func (r *dailyLogRepository) GetByDateRange(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) ([]models.DailyLog, error) {
+// GetByDateRange returns logs newest first. With page.After set it resumes
+// after that (date, id) position, and a positive page.Limit caps the rows.
+func (r *dailyLogRepository) GetByDateRange(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, page models.DailyLogPage) ([]models.DailyLog, error) {
 	query := `
 		SELECT id, user_id, date, morning_routine, evening_routine, water_intake,
 		       sleep_hours, energy_level, mood_rating, productivity_rating, notes,
-		       created_at, updated_at
+		       is_rest_day, created_at, updated_at
 		FROM daily_logs
-		WHERE user_id = $1 AND date BETWEEN $2 AND $3
-		ORDER BY date DESC
-	`
+		WHERE user_id = $1 AND date BETWEEN $2 AND $3`
+	args := []interface{}{userID, startDate, endDate}
+	argCount := 4
+
+	if page.After != nil {
+		query += fmt.Sprintf(" AND (date, id) < ($%d, $%d)", argCount, argCount+1)
+		args = append(args, page.After.Date, page.After.ID)
+		argCount += 2
+	}
 
-	rows, err := r.db.Pool.Query(ctx, query, userID, startDate, endDate)
+	query += " ORDER BY date DESC, id DESC"
+
+	if page.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", argCount)
+		args = append(args, page.Limit)
+	}
+
+	rows, err := r.db.Reader(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get daily logs: %w", err)
 	}
@@ -144,12 +191,16 @@ func (r *dailyLogRepository) GetByDateRange(ctx context.Context, userID uuid.UUI
 			&log.MoodRating,
 			&log.ProductivityRating,
 			&log.Notes,
+			&log.IsRestDay,
 			&log.CreatedAt,
 			&log.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan daily log: %w", err)
 		}
+		if log.Notes, err = r.notes.Decrypt(log.Notes); err != nil {
+			return nil, fmt.Errorf("failed to decrypt daily log notes: %w", err)
+		}
 		logs = append(logs, log)
 	}
 
@@ -160,19 +211,76 @@ func (r *dailyLogRepository) GetByDateRange(ctx context.Context, userID uuid.UUI
 	return logs, nil
 }
 
+// dailyLogAveragesColumns aggregates a range of logs. Unrated ratings are
+// stored as 0 and nulled out so they don't drag their average down. Rest days
+// carry no productivity expectation, so they are left out of that average.
+const dailyLogAveragesColumns = `COUNT(*), AVG(sleep_hours), AVG(water_intake), AVG(NULLIF(mood_rating, 0)),
+		       AVG(NULLIF(energy_level, 0)), AVG(NULLIF(productivity_rating, 0)) FILTER (WHERE NOT is_rest_day)`
+
+// GetAverages averages the user's logs between the optional bounds
+// (inclusive) in a single aggregate query.
+func (r *dailyLogRepository) GetAverages(ctx context.Context, userID uuid.UUID, startDate, endDate *time.Time) (*models.DailyLogAverages, error) {
+	where, args := dailyLogRangeClause(userID, startDate, endDate)
+	query := `
+		SELECT ` + dailyLogAveragesColumns + `
+		FROM daily_logs
+		WHERE ` + where
+
+	var averages models.DailyLogAverages
+	err := r.db.Reader(ctx).QueryRow(ctx, query, args...).Scan(
+		&averages.Days,
+		&averages.SleepHours,
+		&averages.WaterIntake,
+		&averages.MoodRating,
+		&averages.EnergyLevel,
+		&averages.ProductivityRating,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily log averages: %w", err)
+	}
+
+	averages.Round()
+	return &averages, nil
+}
+
+// dailyLogRangeClause builds the WHERE clause selecting a user's logs within
+// the optional date bounds.
+func dailyLogRangeClause(userID uuid.UUID, startDate, endDate *time.Time) (string, []interface{}) {
+	where := "user_id = $1"
+	args := []interface{}{userID}
+	argCount := 1
+
+	if startDate != nil {
+		argCount++
+		where += fmt.Sprintf(" AND date >= $%d", argCount)
+		args = append(args, *startDate)
+	}
+
+	if endDate != nil {
+		argCount++
+		where += fmt.Sprintf(" AND date <= $%d", argCount)
+		args = append(args, *endDate)
+	}
+
+	return where, args
+}
+
 func (r *dailyLogRepository) Update(ctx context.Context, log *models.DailyLog) error {
 	query := `
 		UPDATE daily_logs
 		SET morning_routine = $3, evening_routine = $4, water_intake = $5,
 		    sleep_hours = $6, energy_level = $7, mood_rating = $8,
-		    productivity_rating = $9, notes = $10, updated_at = $11
+		    productivity_rating = $9, notes = $10, is_rest_day = $11, ` + touchUpdatedAt + `
 		WHERE id = $1 AND user_id = $2
 		RETURNING updated_at
 	`
 
-	log.UpdatedAt = time.Now()
+	notes, err := r.notes.Encrypt(log.Notes)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt daily log notes: %w", err)
+	}
 
-	err := r.db.Pool.QueryRow(
+	err = r.db.Writer().QueryRow(
 		ctx,
 		query,
 		log.ID,
@@ -184,8 +292,8 @@ func (r *dailyLogRepository) Update(ctx context.Context, log *models.DailyLog) e
 		log.EnergyLevel,
 		log.MoodRating,
 		log.ProductivityRating,
-		log.Notes,
-		log.UpdatedAt,
+		notes,
+		log.IsRestDay,
 	).Scan(&log.UpdatedAt)
 
 	if err == pgx.ErrNoRows {
@@ -202,7 +310,7 @@ func (r *dailyLogRepository) Update(ctx context.Context, log *models.DailyLog) e
 func (r *dailyLogRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
 	query := `DELETE FROM daily_logs WHERE id = $1 AND user_id = $2`
 
-	result, err := r.db.Pool.Exec(ctx, query, id, userID)
+	result, err := r.db.Writer().Exec(ctx, query, id, userID)
 	if err != nil {
 		return fmt.Errorf("failed to delete daily log: %w", err)
 	}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDailyLogRangeClause(t *testing.T) {
+	userID := uuid.New()
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	where, args := dailyLogRangeClause(userID, &start, &end)
+	assert.Equal(t, "user_id = $1 AND date >= $2 AND date <= $3", where)
+	assert.Equal(t, []interface{}{userID, start, end}, args)
+
+	where, args = dailyLogRangeClause(userID, nil, &end)
+	assert.Equal(t, "user_id = $1 AND date <= $2", where)
+	assert.Equal(t, []interface{}{userID, end}, args)
+
+	where, args = dailyLogRangeClause(userID, nil, nil)
+	assert.Equal(t, "user_id = $1", where)
+	assert.Len(t, args, 1)
+}
+
+func TestDailyLogAveragesExcludeRestDaysFromProductivity(t *testing.T) {
+	assert.Contains(t, dailyLogAveragesColumns, "AVG(NULLIF(productivity_rating, 0)) FILTER (WHERE NOT is_rest_day)")
+	assert.Contains(t, dailyLogAveragesColumns, "AVG(NULLIF(mood_rating, 0)),")
+}
+
+func TestDailyLogAveragesSkipUnratedDays(t *testing.T) {
+	for _, column := range []string{"mood_rating", "energy_level", "productivity_rating"} {
+		assert.Contains(t, dailyLogAveragesColumns, "AVG(NULLIF("+column+", 0))")
+	}
+}
+
+func TestDailyLogCreateError_UniqueViolationIsConflict(t *testing.T) {
+	err := dailyLogCreateError(fmt.Errorf("insert: %w", &pgconn.PgError{Code: "23505", ConstraintName: "daily_logs_user_id_date_key"}))
+
+	assert.ErrorIs(t, err, models.ErrDailyLogExists)
+}
+
+func TestDailyLogCreateError_MissingIndexIsNamed(t *testing.T) {
+	err := dailyLogCreateError(&pgconn.PgError{Code: "42P10"})
+
+	assert.NotErrorIs(t, err, models.ErrDailyLogExists)
+	assert.Contains(t, err.Error(), "unique (user_id, date) index")
+}
+
+func TestDailyLogCreateError_OtherErrorsAreWrapped(t *testing.T) {
+	cause := errors.New("connection reset")
+
+	err := dailyLogCreateError(cause)
+
+	assert.ErrorIs(t, err, cause)
+	assert.NotErrorIs(t, err, models.ErrDailyLogExists)
+}
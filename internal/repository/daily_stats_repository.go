@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+)
+
+type DailyStatsRepository interface {
+	ListUserIDs(ctx context.Context, after uuid.UUID, limit int) ([]uuid.UUID, error)
+	GetDailyStatsData(ctx context.Context, userID uuid.UUID, from, to time.Time) (*models.DailyStatsData, error)
+	GetStoredDailyStats(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) ([]models.DailyLogStats, error)
+	SaveDailyStats(ctx context.Context, userID uuid.UUID, stats []models.DailyLogStats) error
+}
+
+type dailyStatsRepository struct {
+	db *Database
+}
+
+func NewDailyStatsRepository(db *Database) DailyStatsRepository {
+	return &dailyStatsRepository{db: db}
+}
+
+// ListUserIDs pages through every user in ID order starting after the given ID.
+func (r *dailyStatsRepository) ListUserIDs(ctx context.Context, after uuid.UUID, limit int) ([]uuid.UUID, error) {
+	rows, err := r.db.Reader(ctx).Query(ctx, `SELECT id FROM users WHERE id > $1 ORDER BY id LIMIT $2`, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users for daily stats: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// GetDailyStatsData loads the activity behind daily stats for the instants
+// [from, to): the logs dated in that span, all of the user's habits, and the
+// habit completions and tasks due or completed in it.
+func (r *dailyStatsRepository) GetDailyStatsData(ctx context.Context, userID uuid.UUID, from, to time.Time) (*models.DailyStatsData, error) {
+	var data models.DailyStatsData
+
+	logRows, err := r.db.Reader(ctx).Query(ctx, `
+		SELECT date, mood_rating, energy_level, productivity_rating
+		FROM daily_logs
+		WHERE user_id = $1 AND date >= $2 AND date < $3
+	`, userID, models.StatsDate(from), models.StatsDate(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily stats logs: %w", err)
+	}
+	for logRows.Next() {
+		var log models.DailyLog
+		if err := logRows.Scan(&log.Date, &log.MoodRating, &log.EnergyLevel, &log.ProductivityRating); err != nil {
+			logRows.Close()
+			return nil, fmt.Errorf("failed to scan daily stats log: %w", err)
+		}
+		data.Logs = append(data.Logs, log)
+	}
+	logRows.Close()
+	if err := logRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating daily stats logs: %w", err)
+	}
+
+	habitRows, err := r.db.Reader(ctx).Query(ctx, `
+		SELECT id, is_active, created_at FROM habits WHERE user_id = $1 AND deleted_at IS NULL
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily stats habits: %w", err)
+	}
+	for habitRows.Next() {
+		var habit models.Habit
+		if err := habitRows.Scan(&habit.ID, &habit.IsActive, &habit.CreatedAt); err != nil {
+			habitRows.Close()
+			return nil, fmt.Errorf("failed to scan daily stats habit: %w", err)
+		}
+		data.Habits = append(data.Habits, habit)
+	}
+	habitRows.Close()
+	if err := habitRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating daily stats habits: %w", err)
+	}
+
+	completionRows, err := r.db.Reader(ctx).Query(ctx, `
+		SELECT habit_id, completed_at
+		FROM habit_completions
+		WHERE user_id = $1 AND completed_at >= $2 AND completed_at < $3
+	`, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily stats completions: %w", err)
+	}
+	for completionRows.Next() {
+		var completion models.HabitCompletion
+		if err := completionRows.Scan(&completion.HabitID, &completion.CompletedAt); err != nil {
+			completionRows.Close()
+			return nil, fmt.Errorf("failed to scan daily stats completion: %w", err)
+		}
+		data.Completions = append(data.Completions, completion)
+	}
+	completionRows.Close()
+	if err := completionRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating daily stats completions: %w", err)
+	}
+
+	data.Tasks, err = getStatsTasks(ctx, r.db.Reader(ctx), userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// getStatsTasks loads the due and completion times of the user's tasks due or
+// completed in [from, to), which models.CountTasksForDay turns into each day's
+// task counts.
+func getStatsTasks(ctx context.Context, q Querier, userID uuid.UUID, from, to time.Time) ([]models.Task, error) {
+	rows, err := q.Query(ctx, `
+		SELECT due_date, completed_at
+		FROM tasks
+		WHERE user_id = $1 AND deleted_at IS NULL
+		  AND ((due_date >= $2 AND due_date < $3) OR (completed_at >= $2 AND completed_at < $3))
+	`, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily stats tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []models.Task
+	for rows.Next() {
+		var task models.Task
+		if err := rows.Scan(&task.DueDate, &task.CompletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan daily stats task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating daily stats tasks: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// GetStoredDailyStats returns the precomputed days between the dates
+// (inclusive), oldest first. Days not computed yet are simply absent.
+func (r *dailyStatsRepository) GetStoredDailyStats(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) ([]models.DailyLogStats, error) {
+	query := `
+		SELECT date, habits_completed, habits_total, tasks_completed, tasks_total,
+		       mood_rating, energy_level, productivity_rating
+		FROM daily_stats
+		WHERE user_id = $1 AND date BETWEEN $2 AND $3
+		ORDER BY date
+	`
+
+	rows, err := r.db.Reader(ctx).Query(ctx, query, userID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []models.DailyLogStats
+	for rows.Next() {
+		var s models.DailyLogStats
+		err := rows.Scan(
+			&s.Date,
+			&s.HabitsCompleted,
+			&s.HabitsTotal,
+			&s.TasksCompleted,
+			&s.TasksTotal,
+			&s.MoodRating,
+			&s.EnergyLevel,
+			&s.ProductivityRating,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan daily stats: %w", err)
+		}
+		stats = append(stats, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating daily stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// SaveDailyStats stores computed days, replacing any stored versions.
+func (r *dailyStatsRepository) SaveDailyStats(ctx context.Context, userID uuid.UUID, stats []models.DailyLogStats) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin saving daily stats: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO daily_stats (
+			user_id, date, habits_completed, habits_total, tasks_completed, tasks_total,
+			mood_rating, energy_level, productivity_rating
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (user_id, date) DO UPDATE SET
+			habits_completed = EXCLUDED.habits_completed,
+			habits_total = EXCLUDED.habits_total,
+			tasks_completed = EXCLUDED.tasks_completed,
+			tasks_total = EXCLUDED.tasks_total,
+			mood_rating = EXCLUDED.mood_rating,
+			energy_level = EXCLUDED.energy_level,
+			productivity_rating = EXCLUDED.productivity_rating,
+			computed_at = NOW()
+	`
+
+	for _, s := range stats {
+		_, err := tx.Exec(
+			ctx,
+			query,
+			userID,
+			s.Date,
+			s.HabitsCompleted,
+			s.HabitsTotal,
+			s.TasksCompleted,
+			s.TasksTotal,
+			s.MoodRating,
+			s.EnergyLevel,
+			s.ProductivityRating,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save daily stats: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit daily stats: %w", err)
+	}
+
+	return nil
+}
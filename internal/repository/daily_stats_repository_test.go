@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// taskTimesRows serves due_date, completed_at pairs.
+type taskTimesRows struct {
+	fakeRows
+	tasks []models.Task
+}
+
+func (r *taskTimesRows) Next() bool {
+	if r.next >= len(r.tasks) {
+		return false
+	}
+	r.next++
+	return true
+}
+
+func (r *taskTimesRows) Scan(dest ...any) error {
+	task := r.tasks[r.next-1]
+	*dest[0].(**time.Time) = task.DueDate
+	*dest[1].(**time.Time) = task.CompletedAt
+	return nil
+}
+
+// statsTasksQuerier holds the user's tasks and answers the stats task query
+// with those due or completed in [$2, $3), as the WHERE clause does.
+type statsTasksQuerier struct {
+	tasks []models.Task
+	rows  *taskTimesRows
+	args  []any
+}
+
+func (q *statsTasksQuerier) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, nil
+}
+
+func (q *statsTasksQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	q.args = args
+	from, to := args[1].(time.Time), args[2].(time.Time)
+	within := func(t *time.Time) bool { return t != nil && !t.Before(from) && t.Before(to) }
+
+	q.rows = &taskTimesRows{}
+	for _, task := range q.tasks {
+		if within(task.DueDate) || within(task.CompletedAt) {
+			q.rows.tasks = append(q.rows.tasks, task)
+		}
+	}
+	return q.rows, nil
+}
+
+func (q *statsTasksQuerier) QueryRow(context.Context, string, ...any) pgx.Row {
+	return nil
+}
+
+func TestGetStatsTasks_CountsTasksPerDayInUserTimezone(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	at := func(day, hour int) *time.Time {
+		t := time.Date(2026, 10, day, hour, 0, 0, 0, newYork)
+		return &t
+	}
+
+	q := &statsTasksQuerier{tasks: []models.Task{
+		{DueDate: at(15, 23)},                         // due late on the 15th, the 16th in UTC
+		{DueDate: at(15, 9), CompletedAt: at(15, 21)}, // due and done on the 15th
+		{DueDate: at(16, 9), CompletedAt: at(15, 22)}, // done early
+		{DueDate: at(14, 9), CompletedAt: at(14, 23)}, // the 14th, the 15th in UTC
+		{DueDate: at(17, 9)},                          // outside the range
+	}}
+	userID := uuid.New()
+	date := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	from, _ := models.DayBounds(date.AddDate(0, 0, -1), newYork)
+	_, to := models.DayBounds(date.AddDate(0, 0, 1), newYork)
+
+	tasks, err := getStatsTasks(context.Background(), q, userID, from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, []any{userID, from, to}, q.args)
+	assert.True(t, q.rows.closed)
+	assert.Len(t, tasks, 4)
+
+	completed, total := models.CountTasksForDay(tasks, date, newYork)
+	assert.Equal(t, 2, completed)
+	assert.Equal(t, 2, total)
+
+	completed, total = models.CountTasksForDay(tasks, date.AddDate(0, 0, -1), newYork)
+	assert.Equal(t, 1, completed)
+	assert.Equal(t, 1, total)
+
+	completed, total = models.CountTasksForDay(tasks, date.AddDate(0, 0, 1), newYork)
+	assert.Equal(t, 0, completed)
+	assert.Equal(t, 1, total)
+}
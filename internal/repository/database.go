@@ -2,19 +2,65 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/lumen/backend/internal/querycount"
 	"github.com/lumen/backend/pkg/logger"
 	"go.uber.org/zap"
 )
 
+// Database holds the primary pool and, when a read replica is configured,
+// a replica pool. Repositories send read-only queries to Reader and
+// everything else, including transactions, to the primary.
 type Database struct {
-	Pool *pgxpool.Pool
+	Pool    *pgxpool.Pool
+	Replica *pgxpool.Pool
+	// ScanErrors decides whether list queries skip rows that fail to scan.
+	ScanErrors ScanErrorMode
+
+	skippedRows atomic.Int64
+}
+
+// Querier is the query surface shared by both pools.
+type Querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 }
 
-func NewDatabase(dsn string) (*Database, error) {
+// touchUpdatedAt is the SET clause every update uses for updated_at so the
+// timestamp comes from the database clock rather than the app server's.
+// GREATEST keeps it from moving backwards if that clock is ever stepped back.
+const touchUpdatedAt = "updated_at = GREATEST(NOW(), updated_at)"
+
+// NewDatabase connects to the primary at dsn and, if replicaDSN is set, to
+// a read replica there.
+func NewDatabase(dsn, replicaDSN string) (*Database, error) {
+	pool, err := openPool(dsn, "primary")
+	if err != nil {
+		return nil, err
+	}
+
+	db := &Database{Pool: pool}
+	if replicaDSN != "" {
+		db.Replica, err = openPool(replicaDSN, "replica")
+		if err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("read replica: %w", err)
+		}
+	}
+
+	return db, nil
+}
+
+func openPool(dsn, role string) (*pgxpool.Pool, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -28,6 +74,7 @@ func NewDatabase(dsn string) (*Database, error) {
 	config.MaxConnLifetime = time.Hour
 	config.MaxConnIdleTime = 30 * time.Minute
 	config.HealthCheckPeriod = time.Minute
+	config.ConnConfig.Tracer = queryCountTracer{}
 
 	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
@@ -35,18 +82,110 @@ func NewDatabase(dsn string) (*Database, error) {
 	}
 
 	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
 	logger.Info("Database connection established",
+		zap.String("role", role),
 		zap.Int32("max_conns", config.MaxConns),
 		zap.Int32("min_conns", config.MinConns),
 	)
 
-	return &Database{Pool: pool}, nil
+	return pool, nil
 }
 
+// WarmUp opens each pool's MinConns connections up front, by acquiring them
+// all at once and releasing them, so the first requests after boot don't wait
+// on connection setup. It gives up once timeout has passed.
+func (db *Database) WarmUp(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := warmPool(ctx, db.Pool); err != nil {
+		return err
+	}
+	if db.Replica != nil {
+		if err := warmPool(ctx, db.Replica); err != nil {
+			return fmt.Errorf("read replica: %w", err)
+		}
+	}
+	return nil
+}
+
+func warmPool(ctx context.Context, pool *pgxpool.Pool) error {
+	n := int(pool.Config().MinConns)
+	conns := make([]*pgxpool.Conn, n)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for i := range conns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conns[i], errs[i] = pool.Acquire(ctx)
+		}()
+	}
+	wg.Wait()
+
+	for _, conn := range conns {
+		if conn != nil {
+			conn.Release()
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to warm up connection pool: %w", err)
+	}
+	return nil
+}
+
+type primaryReadsKey struct{}
+
+// WithPrimary marks ctx so that reads made with it go to the primary. Use it
+// for reads that back a write, such as loading a row to modify and save, and
+// for responses that must reflect a write just made, which a lagging replica
+// could miss.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryReadsKey{}, true)
+}
+
+// ReadsPrimary reports whether ctx was marked by WithPrimary.
+func ReadsPrimary(ctx context.Context) bool {
+	marked, _ := ctx.Value(primaryReadsKey{}).(bool)
+	return marked
+}
+
+// Reader returns the pool for read-only queries: the replica when one is
+// configured and ctx isn't marked by WithPrimary, otherwise the primary.
+// Replica reads may lag recent writes.
+func (db *Database) Reader(ctx context.Context) Querier {
+	if db.Replica != nil && !ReadsPrimary(ctx) {
+		return db.Replica
+	}
+	return db.Pool
+}
+
+// Writer returns the primary pool, for writes.
+func (db *Database) Writer() Querier {
+	return db.Pool
+}
+
+// queryCountTracer records every query against the request's query counter,
+// if the request carries one.
+type queryCountTracer struct{}
+
+func (queryCountTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	querycount.Inc(ctx)
+	return ctx
+}
+
+func (queryCountTracer) TraceQueryEnd(context.Context, *pgx.Conn, pgx.TraceQueryEndData) {}
+
 func (db *Database) Close() {
+	if db.Replica != nil {
+		db.Replica.Close()
+	}
 	if db.Pool != nil {
 		db.Pool.Close()
 		logger.Info("Database connection closed")
@@ -57,7 +196,44 @@ func (db *Database) Health(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
 
-	return db.Pool.Ping(ctx)
+	if err := db.Pool.Ping(ctx); err != nil {
+		return err
+	}
+	if db.Replica != nil {
+		if err := db.Replica.Ping(ctx); err != nil {
+			return fmt.Errorf("read replica: %w", err)
+		}
+	}
+	return nil
+}
+
+// QueryHealth runs a trivial SELECT against each table, catching missing
+// tables, schema drift and permission problems that Ping can't see.
+func (db *Database) QueryHealth(ctx context.Context, tables []string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	return checkTables(ctx, db.Pool, tables)
+}
+
+// healthQuerier is the part of the pool checkTables uses.
+type healthQuerier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+func checkTables(ctx context.Context, q healthQuerier, tables []string) error {
+	for _, table := range tables {
+		query := fmt.Sprintf("SELECT 1 FROM %s LIMIT 1", pgx.Identifier{table}.Sanitize())
+		if _, err := q.Exec(ctx, query); err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "42P01" {
+				return fmt.Errorf("table %s does not exist: %w", table, err)
+			}
+			return fmt.Errorf("failed to query %s: %w", table, err)
+		}
+	}
+
+	return nil
 }
 
 func (db *Database) Stats() map[string]interface{} {
@@ -72,5 +248,81 @@ func (db *Database) Stats() map[string]interface{} {
 		"canceled_count":     stats.CanceledAcquireCount(),
 		"constructing_conns": stats.ConstructingConns(),
 		"empty_acquire":      stats.EmptyAcquireCount(),
+		"skipped_rows":       db.SkippedRows(),
+	}
+}
+
+// SkippedRows returns how many rows list queries have skipped for failing to
+// scan since startup.
+func (db *Database) SkippedRows() int64 {
+	return db.skippedRows.Load()
+}
+
+// poolSample captures the cumulative pool counters that signal pressure.
+type poolSample struct {
+	EmptyAcquireCount    int64
+	CanceledAcquireCount int64
+}
+
+// poolPressureMonitor warns when acquires start waiting on an empty pool or
+// being cancelled. The counters are cumulative, so only increases between
+// samples are reported.
+type poolPressureMonitor struct {
+	last   poolSample
+	primed bool
+}
+
+func (m *poolPressureMonitor) observe(sample poolSample) bool {
+	if !m.primed {
+		m.last = sample
+		m.primed = true
+		return false
+	}
+
+	emptyDelta := sample.EmptyAcquireCount - m.last.EmptyAcquireCount
+	canceledDelta := sample.CanceledAcquireCount - m.last.CanceledAcquireCount
+	m.last = sample
+
+	if emptyDelta <= 0 && canceledDelta <= 0 {
+		return false
+	}
+
+	logger.Warn("Database pool under pressure",
+		zap.Int64("empty_acquire_delta", emptyDelta),
+		zap.Int64("canceled_acquire_delta", canceledDelta),
+		zap.Int64("empty_acquire_total", sample.EmptyAcquireCount),
+		zap.Int64("canceled_acquire_total", sample.CanceledAcquireCount),
+	)
+	return true
+}
+
+// LogStats periodically logs pool statistics and warns on pool pressure
+// until ctx is cancelled. An interval of zero or less disables it.
+func (db *Database) LogStats(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var monitor poolPressureMonitor
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stats := db.Pool.Stat()
+			logger.Debug("Database pool stats",
+				zap.Int32("acquired_conns", stats.AcquiredConns()),
+				zap.Int32("idle_conns", stats.IdleConns()),
+				zap.Int32("total_conns", stats.TotalConns()),
+				zap.Int32("max_conns", stats.MaxConns()),
+			)
+			monitor.observe(poolSample{
+				EmptyAcquireCount:    stats.EmptyAcquireCount(),
+				CanceledAcquireCount: stats.CanceledAcquireCount(),
+			})
+		}
 	}
 }
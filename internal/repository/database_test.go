@@ -0,0 +1,331 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgproto3"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/lumen/backend/internal/querycount"
+	"github.com/lumen/backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func observeLogs(t *testing.T) *observer.ObservedLogs {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger.SetGlobal(zap.New(core))
+	t.Cleanup(func() { logger.SetGlobal(zap.NewNop()) })
+	return logs
+}
+
+func TestPoolPressureMonitor_WarnsOnIncreaseOnly(t *testing.T) {
+	logs := observeLogs(t)
+	var monitor poolPressureMonitor
+
+	samples := []struct {
+		sample poolSample
+		warn   bool
+	}{
+		{poolSample{EmptyAcquireCount: 4, CanceledAcquireCount: 1}, false}, // first sample primes
+		{poolSample{EmptyAcquireCount: 4, CanceledAcquireCount: 1}, false}, // unchanged
+		{poolSample{EmptyAcquireCount: 7, CanceledAcquireCount: 1}, true},  // empty acquires grew
+		{poolSample{EmptyAcquireCount: 7, CanceledAcquireCount: 3}, true},  // cancellations grew
+		{poolSample{EmptyAcquireCount: 7, CanceledAcquireCount: 3}, false}, // steady again
+	}
+
+	for i, s := range samples {
+		assert.Equal(t, s.warn, monitor.observe(s.sample), "sample %d", i)
+	}
+
+	warnings := logs.FilterMessage("Database pool under pressure").All()
+	assert.Len(t, warnings, 2)
+	assert.Equal(t, zapcore.WarnLevel, warnings[0].Level)
+	assert.Equal(t, int64(3), warnings[0].ContextMap()["empty_acquire_delta"])
+	assert.Equal(t, int64(0), warnings[0].ContextMap()["canceled_acquire_delta"])
+	assert.Equal(t, int64(2), warnings[1].ContextMap()["canceled_acquire_delta"])
+}
+
+type fakeHealthQuerier struct {
+	missing map[string]bool
+	queries []string
+}
+
+func (q *fakeHealthQuerier) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	q.queries = append(q.queries, sql)
+	for table := range q.missing {
+		if strings.Contains(sql, `"`+table+`"`) {
+			return pgconn.CommandTag{}, &pgconn.PgError{Code: "42P01", Message: fmt.Sprintf("relation %q does not exist", table)}
+		}
+	}
+	return pgconn.NewCommandTag("SELECT 1"), nil
+}
+
+func TestCheckTables_PassesWhenTablesQueryable(t *testing.T) {
+	q := &fakeHealthQuerier{}
+
+	err := checkTables(context.Background(), q, []string{"tasks", "habits"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		`SELECT 1 FROM "tasks" LIMIT 1`,
+		`SELECT 1 FROM "habits" LIMIT 1`,
+	}, q.queries)
+}
+
+func TestCheckTables_FailsWhenTableMissing(t *testing.T) {
+	q := &fakeHealthQuerier{missing: map[string]bool{"habits": true}}
+
+	err := checkTables(context.Background(), q, []string{"tasks", "habits", "daily_logs"})
+
+	assert.ErrorContains(t, err, "table habits does not exist")
+	var pgErr *pgconn.PgError
+	assert.ErrorAs(t, err, &pgErr)
+	assert.Len(t, q.queries, 2, "stops at the first failing table")
+}
+
+func TestCheckTables_QuotesTableNames(t *testing.T) {
+	q := &fakeHealthQuerier{}
+
+	assert.NoError(t, checkTables(context.Background(), q, []string{`tasks"; DROP TABLE tasks; --`}))
+	assert.Equal(t, `SELECT 1 FROM "tasks""; DROP TABLE tasks; --" LIMIT 1`, q.queries[0])
+}
+
+func TestQueryCountTracer_CountsRequestQueries(t *testing.T) {
+	ctx, counter := querycount.NewContext(context.Background())
+	tracer := queryCountTracer{}
+
+	for i := 0; i < 3; i++ {
+		ctx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+		tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+	}
+
+	assert.Equal(t, int64(3), counter.Count())
+}
+
+// dialRecorder stands in for the network: every connection attempt is noted
+// under the pool's role and refused, so tests see which pool a query used
+// without a database.
+type dialRecorder struct {
+	dials []string
+}
+
+func (r *dialRecorder) pool(t *testing.T, role string) *pgxpool.Pool {
+	config, err := pgxpool.ParseConfig("postgres://lumen@" + role + ".invalid:5432/lumen?sslmode=disable&connect_timeout=1")
+	assert.NoError(t, err)
+	config.ConnConfig.LookupFunc = func(ctx context.Context, host string) ([]string, error) {
+		return []string{host}, nil
+	}
+	config.ConnConfig.DialFunc = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		r.dials = append(r.dials, role)
+		return nil, errors.New("dial refused in test")
+	}
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), config)
+	assert.NoError(t, err)
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestDatabase_ReaderFallsBackToPrimary(t *testing.T) {
+	var recorder dialRecorder
+	primary := recorder.pool(t, "primary")
+	db := &Database{Pool: primary}
+
+	assert.Same(t, primary, db.Reader(context.Background()))
+	assert.Same(t, primary, db.Writer())
+}
+
+func TestDatabase_ReadsHitReplicaWritesHitPrimary(t *testing.T) {
+	var recorder dialRecorder
+	primary := recorder.pool(t, "primary")
+	replica := recorder.pool(t, "replica")
+	db := &Database{Pool: primary, Replica: replica}
+
+	assert.Same(t, replica, db.Reader(context.Background()))
+	assert.Same(t, primary, db.Writer())
+
+	tasks := NewTaskRepository(db)
+	ctx := context.Background()
+
+	_, err := tasks.GetByID(ctx, uuid.New(), uuid.New())
+	assert.Error(t, err)
+	assert.Equal(t, []string{"replica"}, recorder.dials)
+
+	recorder.dials = nil
+	err = tasks.Delete(ctx, uuid.New(), uuid.New())
+	assert.Error(t, err)
+	assert.Equal(t, []string{"primary"}, recorder.dials)
+}
+
+func TestDatabase_WithPrimaryReadsFromPrimary(t *testing.T) {
+	var recorder dialRecorder
+	primary := recorder.pool(t, "primary")
+	replica := recorder.pool(t, "replica")
+	db := &Database{Pool: primary, Replica: replica}
+
+	ctx := WithPrimary(context.Background())
+	assert.Same(t, primary, db.Reader(ctx))
+
+	tasks := NewTaskRepository(db)
+	_, err := tasks.GetByID(ctx, uuid.New(), uuid.New())
+	assert.Error(t, err)
+	assert.Equal(t, []string{"primary"}, recorder.dials)
+
+	habits := NewHabitRepository(db, nil)
+	recorder.dials = nil
+	_, err = habits.GetByID(ctx, uuid.New(), uuid.New())
+	assert.Error(t, err)
+	assert.Equal(t, []string{"primary"}, recorder.dials)
+}
+
+func TestTouchUpdatedAt_DatabaseClockNeverMovesBackwards(t *testing.T) {
+	assert.Equal(t, "updated_at = GREATEST(NOW(), updated_at)", touchUpdatedAt)
+}
+
+// The repositories leave created_at/updated_at to column defaults and
+// touchUpdatedAt. The demo seed backdates rows on purpose and is exempt.
+func TestRepositories_TakeTimestampsFromDatabase(t *testing.T) {
+	boundTimestamp := regexp.MustCompile(`\b(created_at|updated_at|computed_at) = (\$|EXCLUDED)`)
+	insertedTimestamp := regexp.MustCompile(`INSERT INTO \w+ \([^)]*\b(created_at|updated_at|computed_at)\b`)
+	goTimestamp := regexp.MustCompile(`(CreatedAt|UpdatedAt) = time\.Now\(\)`)
+
+	files, err := filepath.Glob("*_repository.go")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, files)
+
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		assert.NoError(t, err)
+
+		assert.False(t, boundTimestamp.Match(src), "%s binds a timestamp column", file)
+		assert.False(t, insertedTimestamp.Match(src), "%s inserts a timestamp column", file)
+		assert.False(t, goTimestamp.Match(src), "%s stamps a model with the app clock", file)
+	}
+}
+
+// fakePostgres answers the startup handshake and pings on conn, enough for a
+// pool to open and keep connections without a database.
+func fakePostgres(conn net.Conn) {
+	defer conn.Close()
+	backend := pgproto3.NewBackend(conn, conn)
+	if _, err := backend.ReceiveStartupMessage(); err != nil {
+		return
+	}
+
+	backend.Send(&pgproto3.AuthenticationOk{})
+	backend.Send(&pgproto3.BackendKeyData{ProcessID: 1, SecretKey: 1})
+	backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+	if err := backend.Flush(); err != nil {
+		return
+	}
+
+	for {
+		msg, err := backend.Receive()
+		if err != nil {
+			return
+		}
+		switch msg.(type) {
+		case *pgproto3.Query:
+			backend.Send(&pgproto3.EmptyQueryResponse{})
+			backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+			if err := backend.Flush(); err != nil {
+				return
+			}
+		case *pgproto3.Terminate:
+			return
+		}
+	}
+}
+
+func warmUpPool(t *testing.T, minConns int32, dial func(ctx context.Context, network, addr string) (net.Conn, error)) *pgxpool.Pool {
+	config, err := pgxpool.ParseConfig("postgres://lumen@primary.invalid:5432/lumen?sslmode=disable")
+	assert.NoError(t, err)
+	config.MinConns = minConns
+	config.MaxConns = 10
+	config.ConnConfig.LookupFunc = func(ctx context.Context, host string) ([]string, error) {
+		return []string{host}, nil
+	}
+	config.ConnConfig.DialFunc = dial
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), config)
+	assert.NoError(t, err)
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestDatabase_WarmUpOpensMinConns(t *testing.T) {
+	var dials atomic.Int32
+	pool := warmUpPool(t, 4, func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dials.Add(1)
+		client, server := net.Pipe()
+		go fakePostgres(server)
+		return client, nil
+	})
+	db := &Database{Pool: pool}
+
+	assert.NoError(t, db.WarmUp(context.Background(), 5*time.Second))
+
+	stats := pool.Stat()
+	assert.GreaterOrEqual(t, stats.TotalConns(), int32(4))
+	assert.GreaterOrEqual(t, stats.IdleConns(), int32(4))
+	assert.Zero(t, stats.AcquiredConns(), "warm-up releases every connection it acquires")
+	assert.LessOrEqual(t, dials.Load(), int32(pool.Config().MaxConns))
+}
+
+func TestDatabase_WarmUpGivesUpAfterTimeout(t *testing.T) {
+	// The pool's own background dials aren't bound to the warm-up timeout,
+	// so they are refused once the test ends.
+	unreachable := make(chan struct{})
+	pool := warmUpPool(t, 2, func(ctx context.Context, network, addr string) (net.Conn, error) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-unreachable:
+			return nil, errors.New("dial refused in test")
+		}
+	})
+	t.Cleanup(func() { close(unreachable) })
+	db := &Database{Pool: pool}
+
+	start := time.Now()
+	err := db.WarmUp(context.Background(), 100*time.Millisecond)
+
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Zero(t, pool.Stat().AcquiredConns())
+}
+
+func TestDatabase_LogStatsDisabledByNonPositiveInterval(t *testing.T) {
+	db := &Database{}
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			// Never cancelled: LogStats must return on its own, not panic
+			// creating a ticker.
+			db.LogStats(context.Background(), interval)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("LogStats(%v) did not return", interval)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+)
+
+type FeatureFlagRepository interface {
+	GetFeatureFlagOverrides(ctx context.Context, userID uuid.UUID) (map[string]bool, error)
+	SetFeatureFlagOverride(ctx context.Context, userID uuid.UUID, flag string, enabled bool) error
+	DeleteFeatureFlagOverride(ctx context.Context, userID uuid.UUID, flag string) error
+}
+
+type featureFlagRepository struct {
+	db *Database
+}
+
+func NewFeatureFlagRepository(db *Database) FeatureFlagRepository {
+	return &featureFlagRepository{db: db}
+}
+
+// GetFeatureFlagOverrides returns the user's flag overrides keyed by flag name.
+func (r *featureFlagRepository) GetFeatureFlagOverrides(ctx context.Context, userID uuid.UUID) (map[string]bool, error) {
+	rows, err := r.db.Reader(ctx).Query(ctx, `SELECT flag, enabled FROM user_feature_flags WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature flag overrides: %w", err)
+	}
+	defer rows.Close()
+
+	overrides := make(map[string]bool)
+	for rows.Next() {
+		var flag string
+		var enabled bool
+		if err := rows.Scan(&flag, &enabled); err != nil {
+			return nil, fmt.Errorf("failed to scan feature flag override: %w", err)
+		}
+		overrides[flag] = enabled
+	}
+
+	return overrides, rows.Err()
+}
+
+// SetFeatureFlagOverride creates or replaces the user's override of flag.
+// It returns models.ErrNotFound when the user does not exist.
+func (r *featureFlagRepository) SetFeatureFlagOverride(ctx context.Context, userID uuid.UUID, flag string, enabled bool) error {
+	query := `
+		INSERT INTO user_feature_flags (user_id, flag, enabled)
+		SELECT id, $2, $3 FROM users WHERE id = $1
+		ON CONFLICT (user_id, flag) DO UPDATE
+		SET enabled = EXCLUDED.enabled, ` + touchUpdatedAt + `
+	`
+
+	result, err := r.db.Writer().Exec(ctx, query, userID, flag, enabled)
+	if err != nil {
+		return fmt.Errorf("failed to set feature flag override: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteFeatureFlagOverride removes the user's override of flag so the global
+// default applies again.
+func (r *featureFlagRepository) DeleteFeatureFlagOverride(ctx context.Context, userID uuid.UUID, flag string) error {
+	result, err := r.db.Writer().Exec(ctx, `DELETE FROM user_feature_flags WHERE user_id = $1 AND flag = $2`, userID, flag)
+	if err != nil {
+		return fmt.Errorf("failed to delete feature flag override: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,360 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/lumen/backend/internal/models"
+)
+
+type GoalRepository interface {
+	GetByID(ctx context.Context, id, userID uuid.UUID) (*models.Goal, error)
+	GetMilestones(ctx context.Context, goalID, userID uuid.UUID) ([]models.GoalMilestone, error)
+	CreateMilestone(ctx context.Context, milestone *models.GoalMilestone) error
+	CompleteMilestone(ctx context.Context, id, goalID, userID uuid.UUID) (*models.GoalMilestone, error)
+	GetLinkedProgress(ctx context.Context, goalID, userID uuid.UUID) (models.GoalLinkedProgress, error)
+	GetItems(ctx context.Context, goalID, userID uuid.UUID) (models.GoalItems, error)
+	GetTaskCompletions(ctx context.Context, goalID, userID uuid.UUID, since time.Time) ([]time.Time, error)
+}
+
+type goalRepository struct {
+	db *Database
+}
+
+func NewGoalRepository(db *Database) GoalRepository {
+	return &goalRepository{db: db}
+}
+
+func (r *goalRepository) GetByID(ctx context.Context, id, userID uuid.UUID) (*models.Goal, error) {
+	query := `
+		SELECT id, user_id, title, COALESCE(description, ''), COALESCE(timeframe, ''), end_date,
+		       COALESCE(win_condition, ''), COALESCE(status, 'active'), created_at, updated_at
+		FROM goals
+		WHERE id = $1 AND user_id = $2
+	`
+
+	var goal models.Goal
+	err := r.db.Reader(ctx).QueryRow(ctx, query, id, userID).Scan(
+		&goal.ID,
+		&goal.UserID,
+		&goal.Title,
+		&goal.Description,
+		&goal.Timeframe,
+		&goal.EndDate,
+		&goal.WinCondition,
+		&goal.Status,
+		&goal.CreatedAt,
+		&goal.UpdatedAt,
+	)
+
+	if err == pgx.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get goal: %w", err)
+	}
+
+	return &goal, nil
+}
+
+func (r *goalRepository) GetMilestones(ctx context.Context, goalID, userID uuid.UUID) ([]models.GoalMilestone, error) {
+	query := `
+		SELECT id, goal_id, user_id, title, weight, completed_at, created_at, updated_at
+		FROM goal_milestones
+		WHERE goal_id = $1 AND user_id = $2
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.Reader(ctx).Query(ctx, query, goalID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get goal milestones: %w", err)
+	}
+	defer rows.Close()
+
+	var milestones []models.GoalMilestone
+	for rows.Next() {
+		var milestone models.GoalMilestone
+		err := rows.Scan(
+			&milestone.ID,
+			&milestone.GoalID,
+			&milestone.UserID,
+			&milestone.Title,
+			&milestone.Weight,
+			&milestone.CompletedAt,
+			&milestone.CreatedAt,
+			&milestone.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan goal milestone: %w", err)
+		}
+		milestones = append(milestones, milestone)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating goal milestones: %w", err)
+	}
+
+	return milestones, nil
+}
+
+func (r *goalRepository) CreateMilestone(ctx context.Context, milestone *models.GoalMilestone) error {
+	query := `
+		INSERT INTO goal_milestones (id, goal_id, user_id, title, weight)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+
+	milestone.ID = uuid.New()
+
+	err := r.db.Writer().QueryRow(
+		ctx,
+		query,
+		milestone.ID,
+		milestone.GoalID,
+		milestone.UserID,
+		milestone.Title,
+		milestone.Weight,
+	).Scan(&milestone.ID, &milestone.CreatedAt, &milestone.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create goal milestone: %w", err)
+	}
+
+	return nil
+}
+
+// CompleteMilestone marks a milestone done. Completing an already completed
+// milestone keeps its original completion time.
+func (r *goalRepository) CompleteMilestone(ctx context.Context, id, goalID, userID uuid.UUID) (*models.GoalMilestone, error) {
+	query := `
+		UPDATE goal_milestones
+		SET completed_at = COALESCE(completed_at, NOW()), ` + touchUpdatedAt + `
+		WHERE id = $1 AND goal_id = $2 AND user_id = $3
+		RETURNING id, goal_id, user_id, title, weight, completed_at, created_at, updated_at
+	`
+
+	var milestone models.GoalMilestone
+	err := r.db.Writer().QueryRow(ctx, query, id, goalID, userID).Scan(
+		&milestone.ID,
+		&milestone.GoalID,
+		&milestone.UserID,
+		&milestone.Title,
+		&milestone.Weight,
+		&milestone.CompletedAt,
+		&milestone.CreatedAt,
+		&milestone.UpdatedAt,
+	)
+
+	if err == pgx.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete goal milestone: %w", err)
+	}
+
+	return &milestone, nil
+}
+
+// goalLinkedHabitsQuery selects each active linked habit's goal and its total
+// since the start of its current period, passed in as $3 for weekly, $4 for
+// monthly and $5 for daily habits.
+const goalLinkedHabitsQuery = `
+		SELECT ` + habitPeriodGoal + `,
+		       (SELECT COALESCE(SUM(` + habitCompletionValue + `), 0)::float8 FROM habit_completions hc
+		        WHERE hc.habit_id = h.id
+		          AND hc.completed_at >= CASE h.frequency WHEN 'weekly' THEN $3::timestamptz WHEN 'monthly' THEN $4::timestamptz ELSE $5::timestamptz END)
+		FROM habits h
+		WHERE h.goal_id = $1 AND h.user_id = $2 AND h.is_active AND h.deleted_at IS NULL
+	`
+
+// linkedPeriodStarts returns the starts of the week, month and day containing
+// now in the user's timezone, weeks beginning on firstDay. An unknown
+// timezone falls back to UTC.
+func linkedPeriodStarts(now time.Time, timezone string, firstDay models.WeekStart) (week, month, day time.Time) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	now = now.In(loc)
+
+	week = (&models.Habit{Frequency: "weekly", WeekStart: firstDay}).PeriodStart(now)
+	month = (&models.Habit{Frequency: "monthly"}).PeriodStart(now)
+	day = (&models.Habit{Frequency: "daily"}).PeriodStart(now)
+	return week, month, day
+}
+
+// GetLinkedProgress counts the goal's done/total tasks and, for each active
+// linked habit, its total in the current frequency period against its goal,
+// both summed amounts for an amount-based habit. Periods follow the user's
+// timezone and week start.
+func (r *goalRepository) GetLinkedProgress(ctx context.Context, goalID, userID uuid.UUID) (models.GoalLinkedProgress, error) {
+	var linked models.GoalLinkedProgress
+
+	taskQuery := `
+		SELECT COUNT(*) FILTER (WHERE status = 'done'), COUNT(*)
+		FROM tasks
+		WHERE goal_id = $1 AND user_id = $2 AND status <> 'archived' AND deleted_at IS NULL
+	`
+
+	err := r.db.Reader(ctx).QueryRow(ctx, taskQuery, goalID, userID).Scan(&linked.TasksCompleted, &linked.TasksTotal)
+	if err != nil {
+		return linked, fmt.Errorf("failed to count goal tasks: %w", err)
+	}
+
+	var firstDay models.WeekStart
+	var timezone string
+	err = r.db.Reader(ctx).QueryRow(ctx, `SELECT week_start, timezone FROM users WHERE id = $1`, userID).Scan(&firstDay, &timezone)
+	if err != nil && err != pgx.ErrNoRows {
+		return linked, fmt.Errorf("failed to get user calendar: %w", err)
+	}
+	weekStart, monthStart, dayStart := linkedPeriodStarts(time.Now(), timezone, firstDay)
+
+	rows, err := r.db.Reader(ctx).Query(ctx, goalLinkedHabitsQuery, goalID, userID, weekStart, monthStart, dayStart)
+	if err != nil {
+		return linked, fmt.Errorf("failed to get goal habits: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var habit models.HabitPeriodProgress
+		if err := rows.Scan(&habit.Target, &habit.Total); err != nil {
+			return linked, fmt.Errorf("failed to scan goal habit: %w", err)
+		}
+		linked.Habits = append(linked.Habits, habit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return linked, fmt.Errorf("error iterating goal habits: %w", err)
+	}
+
+	return linked, nil
+}
+
+// GetTaskCompletions returns when each of the goal's done tasks completed at
+// or after since, oldest first. Archived tasks are left out, as in
+// GetLinkedProgress.
+func (r *goalRepository) GetTaskCompletions(ctx context.Context, goalID, userID uuid.UUID, since time.Time) ([]time.Time, error) {
+	query := `
+		SELECT completed_at
+		FROM tasks
+		WHERE goal_id = $1 AND user_id = $2 AND status = 'done' AND completed_at >= $3 AND deleted_at IS NULL
+		ORDER BY completed_at
+	`
+
+	rows, err := r.db.Reader(ctx).Query(ctx, query, goalID, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get goal task completions: %w", err)
+	}
+	defer rows.Close()
+
+	var completions []time.Time
+	for rows.Next() {
+		var completedAt time.Time
+		if err := rows.Scan(&completedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan goal task completion: %w", err)
+		}
+		completions = append(completions, completedAt)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating goal task completions: %w", err)
+	}
+
+	return completions, nil
+}
+
+// GetItems returns the user's habits and tasks linked to the goal, in the
+// order their own listings use.
+func (r *goalRepository) GetItems(ctx context.Context, goalID, userID uuid.UUID) (models.GoalItems, error) {
+	var items models.GoalItems
+
+	habitQuery := `
+		SELECT id, user_id, name, color, icon, frequency, target_count, target_amount, is_active, COALESCE(reminder_times, '[]'), position, paused_from, paused_until, streak_reset_at, created_at, updated_at
+		FROM habits
+		WHERE goal_id = $1 AND user_id = $2 AND deleted_at IS NULL
+		ORDER BY position ASC, created_at DESC
+	`
+
+	rows, err := r.db.Reader(ctx).Query(ctx, habitQuery, goalID, userID)
+	if err != nil {
+		return items, fmt.Errorf("failed to get goal habits: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var habit models.Habit
+		err := rows.Scan(
+			&habit.ID,
+			&habit.UserID,
+			&habit.Name,
+			&habit.Color,
+			&habit.Icon,
+			&habit.Frequency,
+			&habit.TargetCount,
+			&habit.TargetAmount,
+			&habit.IsActive,
+			&habit.ReminderTimes,
+			&habit.Position,
+			&habit.PausedFrom,
+			&habit.PausedUntil,
+			&habit.StreakResetAt,
+			&habit.CreatedAt,
+			&habit.UpdatedAt,
+		)
+		if err != nil {
+			return items, fmt.Errorf("failed to scan goal habit: %w", err)
+		}
+		items.Habits = append(items.Habits, habit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return items, fmt.Errorf("error iterating goal habits: %w", err)
+	}
+
+	taskQuery := `
+		SELECT id, user_id, title, description, horizon, priority, status, tags, due_date, completed_at, snooze_count, created_at, updated_at
+		FROM tasks
+		WHERE goal_id = $1 AND user_id = $2 AND deleted_at IS NULL
+		ORDER BY created_at DESC
+	`
+
+	taskRows, err := r.db.Reader(ctx).Query(ctx, taskQuery, goalID, userID)
+	if err != nil {
+		return items, fmt.Errorf("failed to get goal tasks: %w", err)
+	}
+	defer taskRows.Close()
+
+	for taskRows.Next() {
+		var task models.Task
+		err := taskRows.Scan(
+			&task.ID,
+			&task.UserID,
+			&task.Title,
+			&task.Description,
+			&task.Horizon,
+			&task.Priority,
+			&task.Status,
+			&task.Tags,
+			&task.DueDate,
+			&task.CompletedAt,
+			&task.SnoozeCount,
+			&task.CreatedAt,
+			&task.UpdatedAt,
+		)
+		if err != nil {
+			return items, fmt.Errorf("failed to scan goal task: %w", err)
+		}
+		items.Tasks = append(items.Tasks, task)
+	}
+
+	if err := taskRows.Err(); err != nil {
+		return items, fmt.Errorf("error iterating goal tasks: %w", err)
+	}
+
+	return items, nil
+}
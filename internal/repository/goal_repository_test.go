@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoalLinkedHabitsQuery_UsesPassedPeriodStarts(t *testing.T) {
+	assert.Contains(t, goalLinkedHabitsQuery, "CASE h.frequency WHEN 'weekly' THEN $3::timestamptz WHEN 'monthly' THEN $4::timestamptz ELSE $5::timestamptz END")
+	assert.NotContains(t, goalLinkedHabitsQuery, "NOW()")
+}
+
+func TestLinkedPeriodStarts_AcrossTimezoneBoundary(t *testing.T) {
+	// Saturday 31 October 20:00 UTC is already Sunday 1 November, 10:00, in
+	// Kiritimati (UTC+14), so its day, week and month have all turned over.
+	now := time.Date(2026, 10, 31, 20, 0, 0, 0, time.UTC)
+	kiritimati, err := time.LoadLocation("Pacific/Kiritimati")
+	assert.NoError(t, err)
+	newMonth := time.Date(2026, 11, 1, 0, 0, 0, 0, kiritimati)
+
+	week, month, day := linkedPeriodStarts(now, "Pacific/Kiritimati", models.WeekStartSunday)
+	assert.True(t, week.Equal(newMonth), week)
+	assert.True(t, month.Equal(newMonth), month)
+	assert.True(t, day.Equal(newMonth), day)
+	// That is still 31 October in UTC.
+	assert.True(t, month.Equal(time.Date(2026, 10, 31, 10, 0, 0, 0, time.UTC)))
+
+	// In UTC the same instant is in October, and in a Monday week.
+	week, month, day = linkedPeriodStarts(now, "UTC", models.DefaultWeekStart)
+	assert.True(t, week.Equal(time.Date(2026, 10, 26, 0, 0, 0, 0, time.UTC)), week)
+	assert.True(t, month.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)), month)
+	assert.True(t, day.Equal(time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC)), day)
+}
+
+func TestLinkedPeriodStarts_UnknownTimezoneFallsBackToUTC(t *testing.T) {
+	now := time.Date(2026, 10, 31, 20, 0, 0, 0, time.UTC)
+
+	_, _, day := linkedPeriodStarts(now, "Not/AZone", models.DefaultWeekStart)
+	assert.True(t, day.Equal(time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC)))
+}
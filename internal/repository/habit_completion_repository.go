@@ -0,0 +1,451 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/lumen/backend/internal/fieldcrypt"
+	"github.com/lumen/backend/internal/models"
+)
+
+type HabitCompletionRepository interface {
+	Create(ctx context.Context, completion *models.HabitCompletion) error
+	GetByHabitID(ctx context.Context, habitID, userID uuid.UUID) ([]models.HabitCompletion, error)
+	GetPage(ctx context.Context, habitID, userID uuid.UUID, page models.HabitCompletionPage) ([]models.HabitCompletion, error)
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+	DeleteLatest(ctx context.Context, habitID, userID uuid.UUID) (*models.HabitCompletion, error)
+	GetHourCounts(ctx context.Context, habitID, userID uuid.UUID, timezone string) ([]models.HourCount, error)
+	GetWeekdayCounts(ctx context.Context, habitID, userID uuid.UUID, timezone string, from, to *time.Time) ([]models.WeekdayCount, error)
+}
+
+type habitCompletionRepository struct {
+	db       *Database
+	debounce CompletionDebounce
+	notes    *fieldcrypt.Keyring
+}
+
+// CompletionDebounce guards against double taps: a completion within Window
+// of the habit's latest one is a duplicate. Create ignores duplicates,
+// returning the existing completion, or with Reject fails them with
+// models.ErrDuplicateCompletion. A zero Window records every completion.
+type CompletionDebounce struct {
+	Window time.Duration
+	Reject bool
+}
+
+// NewHabitCompletionRepository returns a repository that encrypts completion
+// notes with the notes keyring on write and decrypts them on read; a nil
+// keyring stores them as plaintext.
+func NewHabitCompletionRepository(db *Database, debounce CompletionDebounce, notes *fieldcrypt.Keyring) HabitCompletionRepository {
+	return &habitCompletionRepository{db: db, debounce: debounce, notes: notes}
+}
+
+// decryptCompletionNotes decrypts the notes of completions in place.
+func decryptCompletionNotes(notes *fieldcrypt.Keyring, completions []models.HabitCompletion) error {
+	for i := range completions {
+		plaintext, err := notes.Decrypt(completions[i].Notes)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt habit completion notes: %w", err)
+		}
+		completions[i].Notes = plaintext
+	}
+	return nil
+}
+
+// scanHabitCompletions reads rows selecting id, habit_id, user_id,
+// completed_at, amount, notes and created_at, and closes them. Notes are left
+// encrypted.
+func scanHabitCompletions(db *Database, rows pgx.Rows) ([]models.HabitCompletion, error) {
+	return scanList(db, rows, "habit completion", func(row pgx.Rows, completion *models.HabitCompletion) error {
+		return row.Scan(
+			&completion.ID,
+			&completion.HabitID,
+			&completion.UserID,
+			&completion.CompletedAt,
+			&completion.Amount,
+			&completion.Notes,
+			&completion.CreatedAt,
+		)
+	})
+}
+
+// Create records a completion, subject to the repository's debounce. The
+// habit row is locked while checking so concurrent taps are serialized.
+func (r *habitCompletionRepository) Create(ctx context.Context, completion *models.HabitCompletion) error {
+	completion.ID = uuid.New()
+
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// NOW() is fixed for the transaction, so a completion without a time
+	// gets the same timestamp as its created_at default.
+	if completion.CompletedAt.IsZero() {
+		if err := tx.QueryRow(ctx, `SELECT NOW()`).Scan(&completion.CompletedAt); err != nil {
+			return fmt.Errorf("failed to read database time: %w", err)
+		}
+	}
+
+	if r.debounce.Window > 0 {
+		_, err := tx.Exec(ctx, `SELECT 1 FROM habits WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL FOR UPDATE`, completion.HabitID, completion.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to lock habit: %w", err)
+		}
+
+		var latest models.HabitCompletion
+		err = tx.QueryRow(ctx, `
+			SELECT id, habit_id, user_id, completed_at, amount, notes, created_at
+			FROM habit_completions
+			WHERE habit_id = $1 AND user_id = $2
+			ORDER BY completed_at DESC, created_at DESC
+			LIMIT 1
+		`, completion.HabitID, completion.UserID).Scan(
+			&latest.ID,
+			&latest.HabitID,
+			&latest.UserID,
+			&latest.CompletedAt,
+			&latest.Amount,
+			&latest.Notes,
+			&latest.CreatedAt,
+		)
+		if err != nil && err != pgx.ErrNoRows {
+			return fmt.Errorf("failed to get latest habit completion: %w", err)
+		}
+
+		if err == nil && isDuplicateCompletion(latest.CompletedAt, completion.CompletedAt, r.debounce.Window) {
+			if r.debounce.Reject {
+				return models.ErrDuplicateCompletion
+			}
+			if latest.Notes, err = r.notes.Decrypt(latest.Notes); err != nil {
+				return fmt.Errorf("failed to decrypt habit completion notes: %w", err)
+			}
+			*completion = latest
+			return nil
+		}
+	}
+
+	query := `
+		INSERT INTO habit_completions (id, habit_id, user_id, completed_at, amount, notes)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	notes, err := r.notes.Encrypt(completion.Notes)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt habit completion notes: %w", err)
+	}
+
+	err = tx.QueryRow(
+		ctx,
+		query,
+		completion.ID,
+		completion.HabitID,
+		completion.UserID,
+		completion.CompletedAt,
+		completion.Amount,
+		notes,
+	).Scan(&completion.ID, &completion.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create habit completion: %w", err)
+	}
+
+	if err := r.earnStreakFreeze(ctx, tx, completion); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// earnStreakFreeze credits the user a streak freeze token when the new
+// completion takes its habit's current streak to a milestone, inside the
+// transaction that records the completion. Streaks are measured as Progress
+// measures them, in the user's timezone by the database clock.
+func (r *habitCompletionRepository) earnStreakFreeze(ctx context.Context, tx pgx.Tx, completion *models.HabitCompletion) error {
+	var habit models.Habit
+	err := tx.QueryRow(ctx, `SELECT `+habitColumns+` FROM habits WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`,
+		completion.HabitID, completion.UserID).Scan(habitScanTargets(&habit)...)
+	if err == pgx.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get habit: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT id, habit_id, user_id, completed_at, amount, notes, created_at
+		FROM habit_completions
+		WHERE habit_id = $1 AND user_id = $2 AND id <> $3
+	`, completion.HabitID, completion.UserID, completion.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get habit completions: %w", err)
+	}
+	earlier, err := scanHabitCompletions(r.db, rows)
+	if err != nil {
+		return err
+	}
+
+	if habit.RestDays, err = getRestDays(ctx, tx, completion.UserID); err != nil {
+		return err
+	}
+
+	var now time.Time
+	var timezone string
+	err = tx.QueryRow(ctx, `SELECT NOW(), timezone FROM users WHERE id = $1`, completion.UserID).Scan(&now, &timezone)
+	if err != nil {
+		return fmt.Errorf("failed to get user timezone: %w", err)
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	period, ok := habit.EarnedStreakFreeze(earlier, *completion, now.In(loc))
+	if !ok {
+		return nil
+	}
+	_, err = grantStreakFreeze(ctx, tx, completion.HabitID, completion.UserID, period)
+	return err
+}
+
+// isDuplicateCompletion reports whether a completion at next falls within
+// window of the previous one, on either side, so a backdated double tap
+// counts too.
+func isDuplicateCompletion(previous, next time.Time, window time.Duration) bool {
+	gap := next.Sub(previous)
+	if gap < 0 {
+		gap = -gap
+	}
+	return gap < window
+}
+
+func (r *habitCompletionRepository) GetByHabitID(ctx context.Context, habitID, userID uuid.UUID) ([]models.HabitCompletion, error) {
+	query := `
+		SELECT id, habit_id, user_id, completed_at, amount, notes, created_at
+		FROM habit_completions
+		WHERE habit_id = $1 AND user_id = $2
+		ORDER BY completed_at DESC
+	`
+
+	rows, err := r.db.Reader(ctx).Query(ctx, query, habitID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get habit completions: %w", err)
+	}
+
+	completions, err := scanHabitCompletions(r.db, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := decryptCompletionNotes(r.notes, completions); err != nil {
+		return nil, err
+	}
+
+	return completions, nil
+}
+
+// Delete removes a single completion owned by userID. Completions belonging
+// to another user are reported as ErrNotFound so their existence isn't leaked.
+// Streaks and progress are derived from the remaining completions on read, so
+// nothing else needs to be recomputed here.
+// GetPage returns one page of the habit's completions, newest first, keyed
+// on (completed_at, id) so pages stay stable while completions are added.
+func (r *habitCompletionRepository) GetPage(ctx context.Context, habitID, userID uuid.UUID, page models.HabitCompletionPage) ([]models.HabitCompletion, error) {
+	where, args := habitCompletionPageClause(habitID, userID, page)
+	query := `
+		SELECT id, habit_id, user_id, completed_at, amount, notes, created_at
+		FROM habit_completions
+		WHERE ` + where + `
+		ORDER BY completed_at DESC, id DESC`
+
+	if page.Limit > 0 {
+		args = append(args, page.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.db.Reader(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get habit completions: %w", err)
+	}
+
+	completions, err := scanHabitCompletions(r.db, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := decryptCompletionNotes(r.notes, completions); err != nil {
+		return nil, err
+	}
+
+	return completions, nil
+}
+
+// habitCompletionPageClause builds the WHERE clause for page: the habit's
+// completions within the optional [From, To) range and after the cursor.
+func habitCompletionPageClause(habitID, userID uuid.UUID, page models.HabitCompletionPage) (string, []interface{}) {
+	where := "habit_id = $1 AND user_id = $2"
+	args := []interface{}{habitID, userID}
+
+	if page.From != nil {
+		args = append(args, *page.From)
+		where += fmt.Sprintf(" AND completed_at >= $%d", len(args))
+	}
+
+	if page.To != nil {
+		args = append(args, *page.To)
+		where += fmt.Sprintf(" AND completed_at < $%d", len(args))
+	}
+
+	if page.After != nil {
+		args = append(args, page.After.Date, page.After.ID)
+		where += fmt.Sprintf(" AND (completed_at, id) < ($%d, $%d)", len(args)-1, len(args))
+	}
+
+	return where, args
+}
+
+func (r *habitCompletionRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	query := `DELETE FROM habit_completions WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.Writer().Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete habit completion: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteLatest removes the most recent completion of a habit owned by userID
+// and returns it. ErrNotFound means the habit has no completions to undo.
+func (r *habitCompletionRepository) DeleteLatest(ctx context.Context, habitID, userID uuid.UUID) (*models.HabitCompletion, error) {
+	query := `
+		DELETE FROM habit_completions
+		WHERE id = (
+			SELECT id FROM habit_completions
+			WHERE habit_id = $1 AND user_id = $2
+			ORDER BY completed_at DESC, created_at DESC
+			LIMIT 1
+		)
+		RETURNING id, habit_id, user_id, completed_at, amount, notes, created_at
+	`
+
+	completion := &models.HabitCompletion{}
+	err := r.db.Writer().QueryRow(ctx, query, habitID, userID).Scan(
+		&completion.ID,
+		&completion.HabitID,
+		&completion.UserID,
+		&completion.CompletedAt,
+		&completion.Amount,
+		&completion.Notes,
+		&completion.CreatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete latest habit completion: %w", err)
+	}
+
+	if completion.Notes, err = r.notes.Decrypt(completion.Notes); err != nil {
+		return nil, fmt.Errorf("failed to decrypt habit completion notes: %w", err)
+	}
+
+	return completion, nil
+}
+
+// habitCompletionHourQuery buckets a habit's completions by local hour of
+// day. Converting with AT TIME ZONE before truncating puts each completion in
+// the hour the user saw on their clock, DST shifts included.
+const habitCompletionHourQuery = `
+	SELECT EXTRACT(HOUR FROM date_trunc('hour', completed_at AT TIME ZONE $3))::int AS hour, COUNT(*)::int
+	FROM habit_completions
+	WHERE habit_id = $1 AND user_id = $2
+	GROUP BY hour
+	ORDER BY hour
+`
+
+// GetHourCounts returns how many completions of a habit owned by userID fall
+// in each local hour of the day in timezone, which must be a valid IANA name.
+// Hours without completions are omitted.
+func (r *habitCompletionRepository) GetHourCounts(ctx context.Context, habitID, userID uuid.UUID, timezone string) ([]models.HourCount, error) {
+	rows, err := r.db.Reader(ctx).Query(ctx, habitCompletionHourQuery, habitID, userID, timezone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get habit completion hours: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []models.HourCount
+	for rows.Next() {
+		var count models.HourCount
+		if err := rows.Scan(&count.Hour, &count.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan habit completion hour: %w", err)
+		}
+		counts = append(counts, count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating habit completion hours: %w", err)
+	}
+
+	return counts, nil
+}
+
+// habitCompletionWeekdayQuery buckets a habit's completions in [from, to)
+// by local day of week. Converting with AT TIME ZONE before extracting puts a
+// late-evening completion on the day the user saw rather than the UTC one.
+func habitCompletionWeekdayQuery(habitID, userID uuid.UUID, timezone string, from, to *time.Time) (string, []interface{}) {
+	where, args := habitCompletionPageClause(habitID, userID, models.HabitCompletionPage{From: from, To: to})
+	args = append(args, timezone)
+
+	query := fmt.Sprintf(`
+		SELECT EXTRACT(DOW FROM completed_at AT TIME ZONE $%d)::int AS weekday, COUNT(*)::int
+		FROM habit_completions
+		WHERE %s
+		GROUP BY weekday
+		ORDER BY weekday
+	`, len(args), where)
+
+	return query, args
+}
+
+// GetWeekdayCounts returns how many completions of a habit owned by userID
+// fall on each local day of the week in timezone, which must be a valid IANA
+// name. from and to, when set, limit completions to [from, to). Days without
+// completions are omitted.
+func (r *habitCompletionRepository) GetWeekdayCounts(ctx context.Context, habitID, userID uuid.UUID, timezone string, from, to *time.Time) ([]models.WeekdayCount, error) {
+	query, args := habitCompletionWeekdayQuery(habitID, userID, timezone, from, to)
+
+	rows, err := r.db.Reader(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get habit completion weekdays: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []models.WeekdayCount
+	for rows.Next() {
+		var count models.WeekdayCount
+		if err := rows.Scan(&count.Weekday, &count.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan habit completion weekday: %w", err)
+		}
+		counts = append(counts, count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating habit completion weekdays: %w", err)
+	}
+
+	return counts, nil
+}
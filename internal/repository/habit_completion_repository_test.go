@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/internal/pagination"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHabitCompletionHourQuery_TruncatesInUserTimezone(t *testing.T) {
+	assert.Contains(t, habitCompletionHourQuery, "date_trunc('hour', completed_at AT TIME ZONE $3)")
+	assert.Contains(t, habitCompletionHourQuery, "WHERE habit_id = $1 AND user_id = $2")
+	assert.Contains(t, habitCompletionHourQuery, "GROUP BY hour")
+}
+
+func TestIsDuplicateCompletion_WithinWindow(t *testing.T) {
+	first := time.Date(2026, 10, 16, 7, 30, 0, 0, time.UTC)
+
+	assert.True(t, isDuplicateCompletion(first, first, 2*time.Second))
+	assert.True(t, isDuplicateCompletion(first, first.Add(1500*time.Millisecond), 2*time.Second))
+	// A backdated tap just before the latest completion is a duplicate too.
+	assert.True(t, isDuplicateCompletion(first, first.Add(-time.Second), 2*time.Second))
+}
+
+func TestIsDuplicateCompletion_OutsideWindow(t *testing.T) {
+	first := time.Date(2026, 10, 16, 7, 30, 0, 0, time.UTC)
+
+	assert.False(t, isDuplicateCompletion(first, first.Add(2*time.Second), 2*time.Second))
+	assert.False(t, isDuplicateCompletion(first, first.Add(time.Hour), 2*time.Second))
+	assert.False(t, isDuplicateCompletion(first, first, 0))
+}
+
+func TestHabitCompletionPageClause_FirstPage(t *testing.T) {
+	habitID, userID := uuid.New(), uuid.New()
+
+	where, args := habitCompletionPageClause(habitID, userID, models.HabitCompletionPage{Limit: 50})
+
+	assert.Equal(t, "habit_id = $1 AND user_id = $2", where)
+	assert.Equal(t, []interface{}{habitID, userID}, args)
+}
+
+func TestHabitCompletionPageClause_KeysetAfterCursor(t *testing.T) {
+	habitID, userID := uuid.New(), uuid.New()
+	after := pagination.Cursor{Date: time.Date(2026, 10, 16, 7, 30, 0, 0, time.UTC), ID: uuid.New()}
+
+	where, args := habitCompletionPageClause(habitID, userID, models.HabitCompletionPage{After: &after})
+
+	// The row comparison matches the ORDER BY completed_at DESC, id DESC
+	// tiebreak, so completions sharing a timestamp are neither skipped nor
+	// repeated across pages.
+	assert.Equal(t, "habit_id = $1 AND user_id = $2 AND (completed_at, id) < ($3, $4)", where)
+	assert.Equal(t, []interface{}{habitID, userID, after.Date, after.ID}, args)
+}
+
+func TestHabitCompletionPageClause_DateFilter(t *testing.T) {
+	habitID, userID := uuid.New(), uuid.New()
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 8, 0, 0, 0, 0, time.UTC)
+	after := pagination.Cursor{Date: to.Add(-time.Hour), ID: uuid.New()}
+
+	where, args := habitCompletionPageClause(habitID, userID, models.HabitCompletionPage{After: &after, From: &from, To: &to})
+
+	assert.Equal(t, "habit_id = $1 AND user_id = $2 AND completed_at >= $3 AND completed_at < $4 AND (completed_at, id) < ($5, $6)", where)
+	assert.Equal(t, []interface{}{habitID, userID, from, to, after.Date, after.ID}, args)
+}
+
+func TestHabitCompletionWeekdayQuery_ExtractsInUserTimezone(t *testing.T) {
+	habitID, userID := uuid.New(), uuid.New()
+
+	query, args := habitCompletionWeekdayQuery(habitID, userID, "Asia/Tokyo", nil, nil)
+
+	assert.Contains(t, query, "EXTRACT(DOW FROM completed_at AT TIME ZONE $3)")
+	assert.Contains(t, query, "WHERE habit_id = $1 AND user_id = $2\n")
+	assert.Contains(t, query, "GROUP BY weekday")
+	assert.Equal(t, []interface{}{habitID, userID, "Asia/Tokyo"}, args)
+}
+
+func TestHabitCompletionWeekdayQuery_Range(t *testing.T) {
+	habitID, userID := uuid.New(), uuid.New()
+	from := time.Date(2026, 10, 4, 15, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 11, 15, 0, 0, 0, time.UTC)
+
+	query, args := habitCompletionWeekdayQuery(habitID, userID, "Asia/Tokyo", &from, &to)
+
+	assert.Contains(t, query, "EXTRACT(DOW FROM completed_at AT TIME ZONE $5)")
+	assert.Contains(t, query, "WHERE habit_id = $1 AND user_id = $2 AND completed_at >= $3 AND completed_at < $4")
+	assert.Equal(t, []interface{}{habitID, userID, from, to, "Asia/Tokyo"}, args)
+}
@@ -7,38 +7,83 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/lumen/backend/internal/fieldcrypt"
 	"github.com/lumen/backend/internal/models"
 )
 
 type HabitRepository interface {
 	Create(ctx context.Context, habit *models.Habit) error
 	GetByID(ctx context.Context, id, userID uuid.UUID) (*models.Habit, error)
-	GetByUserID(ctx context.Context, userID uuid.UUID) ([]models.Habit, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID, sortBy string) ([]models.Habit, error)
+	GetDueOn(ctx context.Context, userID uuid.UUID, date time.Time) ([]models.Habit, error)
+	GetIncompleteOn(ctx context.Context, userID uuid.UUID, date time.Time) ([]models.IncompleteHabit, error)
+	GetCompletionsSince(ctx context.Context, habitID, userID uuid.UUID, since time.Time) ([]models.HabitCompletion, error)
+	GetAllCompletions(ctx context.Context, userID uuid.UUID) ([]models.HabitCompletion, error)
+	GetCompletionsBetween(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.HabitCompletion, error)
+	GetRestDays(ctx context.Context, userID uuid.UUID) ([]time.Time, error)
+	GetStreakFreezeBalance(ctx context.Context, userID uuid.UUID) (int, error)
+	SpendStreakFreeze(ctx context.Context, habitID, userID uuid.UUID, period time.Time) (int, error)
 	Update(ctx context.Context, habit *models.Habit) error
+	Pause(ctx context.Context, habit *models.Habit) error
+	ResetStreak(ctx context.Context, habit *models.Habit) error
+	Reorder(ctx context.Context, userID uuid.UUID, habitIDs []uuid.UUID) error
+	ShiftReminderTimes(ctx context.Context, userID uuid.UUID, offset time.Duration) (int, error)
+	Merge(ctx context.Context, sourceID, targetID, userID uuid.UUID) (int, error)
 	Delete(ctx context.Context, id, userID uuid.UUID) error
 }
 
+// habitRepository decrypts the completion notes it reads with the notes
+// keyring.
 type habitRepository struct {
-	db *Database
+	db    *Database
+	notes *fieldcrypt.Keyring
 }
 
-func NewHabitRepository(db *Database) HabitRepository {
-	return &habitRepository{db: db}
+func NewHabitRepository(db *Database, notes *fieldcrypt.Keyring) HabitRepository {
+	return &habitRepository{db: db, notes: notes}
 }
 
+// Create appends the habit after the user's others. The owner's row is
+// locked first so concurrent creates queue up, each seeing the last one's
+// position, instead of both taking the same MAX(position) + 1.
 func (r *habitRepository) Create(ctx context.Context, habit *models.Habit) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := createHabit(ctx, tx, habit); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// createHabit inserts the habit through q, which must be a transaction for
+// the owner lock to hold until the insert commits. Under READ COMMITTED the
+// insert takes a fresh snapshot after the lock is granted, so it sees habits
+// committed by whoever held the lock before.
+func createHabit(ctx context.Context, q Querier, habit *models.Habit) error {
+	if _, err := q.Exec(ctx, `SELECT 1 FROM users WHERE id = $1 FOR UPDATE`, habit.UserID); err != nil {
+		return fmt.Errorf("failed to lock habit owner: %w", err)
+	}
+
 	query := `
-		INSERT INTO habits (id, user_id, name, color, icon, frequency, target_count, is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-		RETURNING id, created_at, updated_at
+		INSERT INTO habits (id, user_id, name, color, icon, frequency, target_count, target_amount, is_active, reminder_times, position)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
+		        (SELECT COALESCE(MAX(position) + 1, 0) FROM habits WHERE user_id = $2))
+		RETURNING id, position, created_at, updated_at
 	`
 
 	habit.ID = uuid.New()
 	habit.IsActive = true
-	habit.CreatedAt = time.Now()
-	habit.UpdatedAt = time.Now()
 
-	err := r.db.Pool.QueryRow(
+	err := q.QueryRow(
 		ctx,
 		query,
 		habit.ID,
@@ -48,10 +93,10 @@ func (r *habitRepository) Create(ctx context.Context, habit *models.Habit) error
 		habit.Icon,
 		habit.Frequency,
 		habit.TargetCount,
+		habit.TargetAmount,
 		habit.IsActive,
-		habit.CreatedAt,
-		habit.UpdatedAt,
-	).Scan(&habit.ID, &habit.CreatedAt, &habit.UpdatedAt)
+		reminderTimes(habit),
+	).Scan(&habit.ID, &habit.Position, &habit.CreatedAt, &habit.UpdatedAt)
 
 	if err != nil {
 		return fmt.Errorf("failed to create habit: %w", err)
@@ -62,13 +107,44 @@ func (r *habitRepository) Create(ctx context.Context, habit *models.Habit) error
 
 func (r *habitRepository) GetByID(ctx context.Context, id, userID uuid.UUID) (*models.Habit, error) {
 	query := `
-		SELECT id, user_id, name, color, icon, frequency, target_count, is_active, created_at, updated_at
+		SELECT ` + habitColumns + `
 		FROM habits
-		WHERE id = $1 AND user_id = $2
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
 	`
 
 	var habit models.Habit
-	err := r.db.Pool.QueryRow(ctx, query, id, userID).Scan(
+	err := r.db.Reader(ctx).QueryRow(ctx, query, id, userID).Scan(habitScanTargets(&habit)...)
+
+	if err == pgx.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get habit: %w", err)
+	}
+
+	return &habit, nil
+}
+
+// habitWeekStart selects the day the habit owner's weeks begin on, for
+// models.Habit.WeekStart.
+const habitWeekStart = `COALESCE((SELECT u.week_start FROM users u WHERE u.id = habits.user_id), '` + string(models.DefaultWeekStart) + `')`
+
+// habitPauses selects the habit's pause history, oldest first, for
+// models.Habit.Pauses.
+const habitPauses = `COALESCE((SELECT jsonb_agg(jsonb_build_object('paused_from', p.paused_from, 'paused_until', p.paused_until) ORDER BY p.paused_from)
+		         FROM habit_pauses p WHERE p.habit_id = habits.id), '[]')`
+
+// habitColumns is the column list habitScanTargets expects, selected from
+// habits.
+const habitColumns = `id, user_id, name, color, icon, frequency, target_count, target_amount, is_active, COALESCE(reminder_times, '[]'), position, paused_from, paused_until, streak_reset_at, created_at, updated_at,
+		       ARRAY(SELECT f.period_start FROM habit_streak_freezes f WHERE f.habit_id = habits.id ORDER BY f.period_start),
+		       ` + habitPauses + `,
+		       ` + habitWeekStart
+
+// habitScanTargets returns the fields of habit matching habitColumns, in order.
+func habitScanTargets(habit *models.Habit) []any {
+	return []any{
 		&habit.ID,
 		&habit.UserID,
 		&habit.Name,
@@ -76,39 +152,126 @@ func (r *habitRepository) GetByID(ctx context.Context, id, userID uuid.UUID) (*m
 		&habit.Icon,
 		&habit.Frequency,
 		&habit.TargetCount,
+		&habit.TargetAmount,
 		&habit.IsActive,
+		&habit.ReminderTimes,
+		&habit.Position,
+		&habit.PausedFrom,
+		&habit.PausedUntil,
+		&habit.StreakResetAt,
 		&habit.CreatedAt,
 		&habit.UpdatedAt,
-	)
+		&habit.FrozenPeriods,
+		&habit.Pauses,
+		&habit.WeekStart,
+	}
+}
 
-	if err == pgx.ErrNoRows {
-		return nil, models.ErrNotFound
+// habitOrderBy maps each allowed habit sort to its ORDER BY clause. Habits
+// with the same position, as every habit has until the user first reorders,
+// fall back to newest first.
+var habitOrderBy = map[string]string{
+	models.HabitSortPosition: "position ASC, created_at DESC",
+	models.HabitSortName:     "lower(name) ASC, created_at DESC",
+	models.HabitSortCreated:  "created_at DESC",
+}
+
+// GetByUserID lists the user's habits in the order sortBy names, which must
+// be one of the models.HabitSort* constants.
+func (r *habitRepository) GetByUserID(ctx context.Context, userID uuid.UUID, sortBy string) ([]models.Habit, error) {
+	orderBy, ok := habitOrderBy[sortBy]
+	if !ok {
+		return nil, models.ErrInvalidHabitSort
+	}
+
+	query := `
+		SELECT ` + habitColumns + `
+		FROM habits
+		WHERE user_id = $1 AND deleted_at IS NULL
+		ORDER BY ` + orderBy
+
+	rows, err := r.db.Reader(ctx).Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get habits: %w", err)
 	}
 
+	return scanList(r.db, rows, "habit", func(row pgx.Rows, habit *models.Habit) error {
+		return row.Scan(habitScanTargets(habit)...)
+	})
+}
+
+// GetDueOn returns the user's habits that are due on date, in display order.
+// Each habit's completions are tallied over its own frequency period around
+// date and the due rule is applied by models.Habit.IsDueOn.
+func (r *habitRepository) GetDueOn(ctx context.Context, userID uuid.UUID, date time.Time) ([]models.Habit, error) {
+	all, tallies, err := r.getWithPeriodTallies(ctx, userID, date)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get habit: %w", err)
+		return nil, err
 	}
 
-	return &habit, nil
+	var habits []models.Habit
+	for i, habit := range all {
+		if habit.IsDueOn(date, tallies[i]) {
+			habits = append(habits, habit)
+		}
+	}
+
+	return habits, nil
 }
 
-func (r *habitRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]models.Habit, error) {
+// GetIncompleteOn returns the user's habits that still need completions on
+// date, in display order, with how many remain. Periods are taken in date's
+// location, so pass a date in the user's timezone.
+func (r *habitRepository) GetIncompleteOn(ctx context.Context, userID uuid.UUID, date time.Time) ([]models.IncompleteHabit, error) {
+	habits, tallies, err := r.getWithPeriodTallies(ctx, userID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.IncompleteOn(habits, tallies, date), nil
+}
+
+// getWithPeriodTallies returns the user's active habits in display order
+// and, for each, the number of completions in its frequency period around
+// date and the sum of their amounts.
+func (r *habitRepository) getWithPeriodTallies(ctx context.Context, userID uuid.UUID, date time.Time) ([]models.Habit, []models.PeriodTally, error) {
 	query := `
-		SELECT id, user_id, name, color, icon, frequency, target_count, is_active, created_at, updated_at
-		FROM habits
-		WHERE user_id = $1
-		ORDER BY created_at DESC
+		SELECT h.id, h.user_id, h.name, h.color, h.icon, h.frequency, h.target_count, h.target_amount, h.is_active, COALESCE(h.reminder_times, '[]'), h.position,
+		       h.paused_from, h.paused_until, h.streak_reset_at, h.created_at, h.updated_at,
+		       p.period_count, p.period_amount
+		FROM habits h
+		CROSS JOIN LATERAL (
+		    SELECT COUNT(*) AS period_count, COALESCE(SUM(hc.amount), 0)::float8 AS period_amount
+		    FROM habit_completions hc
+		    WHERE hc.habit_id = h.id
+		      AND hc.completed_at >= CASE h.frequency WHEN 'weekly' THEN $2::timestamptz WHEN 'monthly' THEN $4::timestamptz ELSE $6::timestamptz END
+		      AND hc.completed_at <  CASE h.frequency WHEN 'weekly' THEN $3::timestamptz WHEN 'monthly' THEN $5::timestamptz ELSE $7::timestamptz END
+		) p
+		WHERE h.user_id = $1 AND h.is_active = true AND h.deleted_at IS NULL
+		ORDER BY h.position ASC, h.created_at DESC
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, userID)
+	var firstDay models.WeekStart
+	err := r.db.Reader(ctx).QueryRow(ctx, `SELECT week_start FROM users WHERE id = $1`, userID).Scan(&firstDay)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, nil, fmt.Errorf("failed to get week start: %w", err)
+	}
+
+	weekStart, weekEnd := (&models.Habit{Frequency: "weekly", WeekStart: firstDay}).PeriodBounds(date)
+	monthStart, monthEnd := (&models.Habit{Frequency: "monthly"}).PeriodBounds(date)
+	dayStart, dayEnd := (&models.Habit{Frequency: "daily"}).PeriodBounds(date)
+
+	rows, err := r.db.Reader(ctx).Query(ctx, query, userID, weekStart, weekEnd, monthStart, monthEnd, dayStart, dayEnd)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get habits: %w", err)
+		return nil, nil, fmt.Errorf("failed to get due habits: %w", err)
 	}
 	defer rows.Close()
 
 	var habits []models.Habit
+	var tallies []models.PeriodTally
 	for rows.Next() {
 		var habit models.Habit
+		var tally models.PeriodTally
 		err := rows.Scan(
 			&habit.ID,
 			&habit.UserID,
@@ -117,34 +280,264 @@ func (r *habitRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]
 			&habit.Icon,
 			&habit.Frequency,
 			&habit.TargetCount,
+			&habit.TargetAmount,
 			&habit.IsActive,
+			&habit.ReminderTimes,
+			&habit.Position,
+			&habit.PausedFrom,
+			&habit.PausedUntil,
+			&habit.StreakResetAt,
 			&habit.CreatedAt,
 			&habit.UpdatedAt,
+			&tally.Count,
+			&tally.Amount,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan habit: %w", err)
+			return nil, nil, fmt.Errorf("failed to scan habit: %w", err)
 		}
+		habit.WeekStart = firstDay
 		habits = append(habits, habit)
+		tallies = append(tallies, tally)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating habits: %w", err)
+		return nil, nil, fmt.Errorf("error iterating habits: %w", err)
 	}
 
-	return habits, nil
+	return habits, tallies, nil
+}
+
+// habitPeriodGoal and habitCompletionValue are models.Habit's periodGoal and
+// completionValue in SQL, for a habit h and its completion hc: an
+// amount-based habit is met by summed amounts, any other by a count.
+const (
+	habitPeriodGoal      = `(CASE WHEN h.target_amount > 0 THEN h.target_amount ELSE GREATEST(h.target_count, 1) END)::float8`
+	habitCompletionValue = `CASE WHEN h.target_amount > 0 THEN COALESCE(hc.amount, 0) ELSE 1 END`
+)
+
+func (r *habitRepository) GetCompletionsSince(ctx context.Context, habitID, userID uuid.UUID, since time.Time) ([]models.HabitCompletion, error) {
+	query := `
+		SELECT id, habit_id, user_id, completed_at, amount, notes, created_at
+		FROM habit_completions
+		WHERE habit_id = $1 AND user_id = $2 AND completed_at >= $3
+		ORDER BY completed_at DESC
+	`
+
+	rows, err := r.db.Reader(ctx).Query(ctx, query, habitID, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get habit completions: %w", err)
+	}
+
+	completions, err := scanHabitCompletions(r.db, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := decryptCompletionNotes(r.notes, completions); err != nil {
+		return nil, err
+	}
+
+	return completions, nil
+}
+
+// GetRestDays returns the dates the user logged as rest days, which habit
+// progress treats as neutral.
+func (r *habitRepository) GetRestDays(ctx context.Context, userID uuid.UUID) ([]time.Time, error) {
+	return getRestDays(ctx, r.db.Reader(ctx), userID)
+}
+
+func getRestDays(ctx context.Context, q Querier, userID uuid.UUID) ([]time.Time, error) {
+	query := `
+		SELECT date
+		FROM daily_logs
+		WHERE user_id = $1 AND is_rest_day
+		ORDER BY date
+	`
+
+	rows, err := q.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rest days: %w", err)
+	}
+	defer rows.Close()
+
+	var days []time.Time
+	for rows.Next() {
+		var day time.Time
+		if err := rows.Scan(&day); err != nil {
+			return nil, fmt.Errorf("failed to scan rest day: %w", err)
+		}
+		days = append(days, day)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rest days: %w", err)
+	}
+
+	return days, nil
+}
+
+// GetStreakFreezeBalance returns how many streak freeze tokens the user has.
+func (r *habitRepository) GetStreakFreezeBalance(ctx context.Context, userID uuid.UUID) (int, error) {
+	var balance int
+	err := r.db.Reader(ctx).QueryRow(ctx, `SELECT streak_freeze_tokens FROM users WHERE id = $1`, userID).Scan(&balance)
+
+	if err == pgx.ErrNoRows {
+		return 0, models.ErrNotFound
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to get streak freeze balance: %w", err)
+	}
+
+	return balance, nil
+}
+
+// SpendStreakFreeze spends one of the user's tokens to bridge the habit's
+// period starting on the given date, returning the remaining balance. The
+// token is only taken if the period was not already frozen.
+func (r *habitRepository) SpendStreakFreeze(ctx context.Context, habitID, userID uuid.UUID, period time.Time) (int, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin streak freeze: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	balance, err := spendStreakFreeze(ctx, tx, habitID, userID, period)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit streak freeze: %w", err)
+	}
+
+	return balance, nil
+}
+
+// spendStreakFreeze records the freeze and takes the token through q, which
+// must be a transaction so neither happens without the other.
+func spendStreakFreeze(ctx context.Context, q Querier, habitID, userID uuid.UUID, period time.Time) (int, error) {
+	result, err := q.Exec(ctx, `
+		INSERT INTO habit_streak_freezes (habit_id, user_id, period_start)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (habit_id, period_start) DO NOTHING
+	`, habitID, userID, period.Format(time.DateOnly))
+	if err != nil {
+		return 0, fmt.Errorf("failed to record streak freeze: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return 0, models.ErrNothingToFreeze
+	}
+
+	var balance int
+	err = q.QueryRow(ctx, `
+		UPDATE users SET streak_freeze_tokens = streak_freeze_tokens - 1
+		WHERE id = $1 AND streak_freeze_tokens > 0
+		RETURNING streak_freeze_tokens
+	`, userID).Scan(&balance)
+
+	if err == pgx.ErrNoRows {
+		return 0, models.ErrNoFreezeTokens
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to spend streak freeze token: %w", err)
+	}
+
+	return balance, nil
+}
+
+// grantStreakFreeze credits the user a streak freeze token for the habit's
+// streak milestone reached in the period starting on the given date. A
+// milestone already credited for that period earns nothing, so undoing and
+// logging a completion again can't mint tokens. It reports whether a token
+// was credited.
+func grantStreakFreeze(ctx context.Context, q Querier, habitID, userID uuid.UUID, period time.Time) (bool, error) {
+	result, err := q.Exec(ctx, `
+		INSERT INTO habit_streak_freeze_grants (habit_id, user_id, period_start)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (habit_id, period_start) DO NOTHING
+	`, habitID, userID, period.Format(time.DateOnly))
+	if err != nil {
+		return false, fmt.Errorf("failed to record streak freeze grant: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	_, err = q.Exec(ctx, `UPDATE users SET streak_freeze_tokens = streak_freeze_tokens + 1 WHERE id = $1`, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to credit streak freeze token: %w", err)
+	}
+
+	return true, nil
+}
+
+// GetAllCompletions returns every completion across the user's habits in one
+// query, for views that summarise all habits at once.
+func (r *habitRepository) GetAllCompletions(ctx context.Context, userID uuid.UUID) ([]models.HabitCompletion, error) {
+	query := `
+		SELECT id, habit_id, user_id, completed_at, amount, notes, created_at
+		FROM habit_completions
+		WHERE user_id = $1
+		ORDER BY completed_at DESC
+	`
+
+	rows, err := r.db.Reader(ctx).Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get habit completions: %w", err)
+	}
+
+	completions, err := scanHabitCompletions(r.db, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := decryptCompletionNotes(r.notes, completions); err != nil {
+		return nil, err
+	}
+
+	return completions, nil
+}
+
+// GetCompletionsBetween returns the completions across the user's habits
+// logged in [from, to), like GetAllCompletions but for a bounded span.
+func (r *habitRepository) GetCompletionsBetween(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.HabitCompletion, error) {
+	query := `
+		SELECT id, habit_id, user_id, completed_at, amount, notes, created_at
+		FROM habit_completions
+		WHERE user_id = $1 AND completed_at >= $2 AND completed_at < $3
+		ORDER BY completed_at DESC
+	`
+
+	rows, err := r.db.Reader(ctx).Query(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get habit completions: %w", err)
+	}
+
+	completions, err := scanHabitCompletions(r.db, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := decryptCompletionNotes(r.notes, completions); err != nil {
+		return nil, err
+	}
+
+	return completions, nil
 }
 
 func (r *habitRepository) Update(ctx context.Context, habit *models.Habit) error {
 	query := `
 		UPDATE habits
-		SET name = $3, color = $4, icon = $5, frequency = $6, target_count = $7, is_active = $8, updated_at = $9
-		WHERE id = $1 AND user_id = $2
+		SET name = $3, color = $4, icon = $5, frequency = $6, target_count = $7, target_amount = $8, is_active = $9, reminder_times = $10, ` + touchUpdatedAt + `
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
 		RETURNING updated_at
 	`
 
-	habit.UpdatedAt = time.Now()
-
-	err := r.db.Pool.QueryRow(
+	err := r.db.Writer().QueryRow(
 		ctx,
 		query,
 		habit.ID,
@@ -154,8 +547,9 @@ func (r *habitRepository) Update(ctx context.Context, habit *models.Habit) error
 		habit.Icon,
 		habit.Frequency,
 		habit.TargetCount,
+		habit.TargetAmount,
 		habit.IsActive,
-		habit.UpdatedAt,
+		reminderTimes(habit),
 	).Scan(&habit.UpdatedAt)
 
 	if err == pgx.ErrNoRows {
@@ -169,10 +563,253 @@ func (r *habitRepository) Update(ctx context.Context, habit *models.Habit) error
 	return nil
 }
 
+// pauseHabitQuery stores the habit's current pause window and records it in
+// the habit's pause history in one statement. An extended pause keeps its
+// paused_from, so it moves the recorded window's end instead of adding one.
+const pauseHabitQuery = `
+		WITH paused AS (
+		    UPDATE habits
+		    SET paused_from = $3, paused_until = $4, ` + touchUpdatedAt + `
+		    WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+		    RETURNING id, user_id, updated_at
+		), history AS (
+		    INSERT INTO habit_pauses (habit_id, user_id, paused_from, paused_until)
+		    SELECT id, user_id, $3, $4 FROM paused
+		    ON CONFLICT (habit_id, paused_from) DO UPDATE SET paused_until = EXCLUDED.paused_until
+		)
+		SELECT updated_at FROM paused
+	`
+
+func (r *habitRepository) Pause(ctx context.Context, habit *models.Habit) error {
+	err := r.db.Writer().QueryRow(
+		ctx,
+		pauseHabitQuery,
+		habit.ID,
+		habit.UserID,
+		habit.PausedFrom,
+		habit.PausedUntil,
+	).Scan(&habit.UpdatedAt)
+
+	if err == pgx.ErrNoRows {
+		return models.ErrNotFound
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to pause habit: %w", err)
+	}
+
+	return nil
+}
+
+// ResetStreak marks the habit's streak as reset now, by the database clock.
+// Completions are kept; streaks just stop counting the ones before the mark.
+func (r *habitRepository) ResetStreak(ctx context.Context, habit *models.Habit) error {
+	query := `
+		UPDATE habits
+		SET streak_reset_at = NOW(), ` + touchUpdatedAt + `
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+		RETURNING streak_reset_at, updated_at
+	`
+
+	err := r.db.Writer().QueryRow(ctx, query, habit.ID, habit.UserID).Scan(&habit.StreakResetAt, &habit.UpdatedAt)
+
+	if err == pgx.ErrNoRows {
+		return models.ErrNotFound
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to reset habit streak: %w", err)
+	}
+
+	return nil
+}
+
+// Reorder sets each habit's position to its index in habitIDs within a
+// single transaction. habitIDs must cover all of the user's habits.
+func (r *habitRepository) Reorder(ctx context.Context, userID uuid.UUID, habitIDs []uuid.UUID) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin reorder: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var count int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM habits WHERE user_id = $1 AND deleted_at IS NULL`, userID).Scan(&count); err != nil {
+		return fmt.Errorf("failed to count habits: %w", err)
+	}
+
+	if count != len(habitIDs) {
+		return models.ErrInvalidHabitOrder
+	}
+
+	for position, id := range habitIDs {
+		result, err := tx.Exec(ctx,
+			`UPDATE habits SET position = $3, `+touchUpdatedAt+` WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`,
+			id, userID, position,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to reorder habit: %w", err)
+		}
+
+		if result.RowsAffected() == 0 {
+			return models.ErrInvalidHabitOrder
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit reorder: %w", err)
+	}
+
+	return nil
+}
+
+// ShiftReminderTimes moves the reminder times of all the user's habits by
+// offset within a single transaction, returning how many habits changed. If
+// any habit's times can't be shifted, none are.
+func (r *habitRepository) ShiftReminderTimes(ctx context.Context, userID uuid.UUID, offset time.Duration) (int, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin reminder shift: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT id, reminder_times
+		FROM habits
+		WHERE user_id = $1 AND deleted_at IS NULL
+		  AND jsonb_typeof(reminder_times) = 'array'
+		  AND reminder_times <> '[]'::jsonb
+		FOR UPDATE
+	`, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get reminder times: %w", err)
+	}
+
+	shifted := make(map[uuid.UUID][]string)
+	for rows.Next() {
+		var id uuid.UUID
+		var times []string
+		if err := rows.Scan(&id, &times); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan reminder times: %w", err)
+		}
+
+		shifted[id], err = models.ShiftReminderTimes(times, offset)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating reminder times: %w", err)
+	}
+
+	for id, times := range shifted {
+		_, err := tx.Exec(ctx,
+			`UPDATE habits SET reminder_times = $3, `+touchUpdatedAt+` WHERE id = $1 AND user_id = $2`,
+			id, userID, times,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to shift reminder times: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit reminder shift: %w", err)
+	}
+
+	return len(shifted), nil
+}
+
+// habitMergeHistoryQueries move the rest of a habit's history from $1 to $2
+// for user $3. Every table referencing habits belongs here, or its rows would
+// cascade away with the merged habit.
+var habitMergeHistoryQueries = []string{
+	`UPDATE habit_logs SET habit_id = $2 WHERE habit_id = $1 AND user_id = $3`,
+	`UPDATE reminder_events SET habit_id = $2 WHERE habit_id = $1 AND user_id = $3`,
+	`UPDATE habit_streak_freezes f SET habit_id = $2
+	 WHERE f.habit_id = $1 AND f.user_id = $3
+	   AND NOT EXISTS (
+	     SELECT 1 FROM habit_streak_freezes t WHERE t.habit_id = $2 AND t.period_start = f.period_start
+	   )`,
+}
+
+// Merge folds the source habit into the target within a single transaction:
+// completions, logs, reminder history and streak freezes move to the target
+// and the source is deleted. The target's own settings are left untouched.
+// A freeze on a period the target already has frozen is dropped with the
+// source. It returns how many completions moved; ErrNotFound means the user
+// does not own both habits.
+func (r *habitRepository) Merge(ctx context.Context, sourceID, targetID, userID uuid.UUID) (int, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin merge: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock both habits so a concurrent completion can't land on the source
+	// after its history has moved.
+	rows, err := tx.Query(ctx,
+		`SELECT id FROM habits WHERE id = ANY($1) AND user_id = $2 AND deleted_at IS NULL FOR UPDATE`,
+		[]uuid.UUID{sourceID, targetID}, userID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock habits for merge: %w", err)
+	}
+	locked := 0
+	for rows.Next() {
+		locked++
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to lock habits for merge: %w", err)
+	}
+
+	if locked != 2 {
+		return 0, models.ErrNotFound
+	}
+
+	result, err := tx.Exec(ctx,
+		`UPDATE habit_completions SET habit_id = $2 WHERE habit_id = $1 AND user_id = $3`,
+		sourceID, targetID, userID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move habit completions: %w", err)
+	}
+	moved := int(result.RowsAffected())
+
+	for _, query := range habitMergeHistoryQueries {
+		if _, err := tx.Exec(ctx, query, sourceID, targetID, userID); err != nil {
+			return 0, fmt.Errorf("failed to move habit history: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx,
+		`UPDATE habits SET `+touchUpdatedAt+` WHERE id = $1 AND user_id = $2`,
+		targetID, userID,
+	); err != nil {
+		return 0, fmt.Errorf("failed to touch merge target: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM habits WHERE id = $1 AND user_id = $2`, sourceID, userID); err != nil {
+		return 0, fmt.Errorf("failed to delete merged habit: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit merge: %w", err)
+	}
+
+	return moved, nil
+}
+
+// Delete moves the habit to the trash, from which TrashRepository can restore
+// it until it is purged.
 func (r *habitRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
-	query := `DELETE FROM habits WHERE id = $1 AND user_id = $2`
+	query := `UPDATE habits SET deleted_at = NOW() WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`
 
-	result, err := r.db.Pool.Exec(ctx, query, id, userID)
+	result, err := r.db.Writer().Exec(ctx, query, id, userID)
 	if err != nil {
 		return fmt.Errorf("failed to delete habit: %w", err)
 	}
@@ -183,3 +820,12 @@ func (r *habitRepository) Delete(ctx context.Context, id, userID uuid.UUID) erro
 
 	return nil
 }
+
+// reminderTimes keeps the stored column a JSON array even when the habit has
+// no reminders, rather than a JSON null.
+func reminderTimes(habit *models.Habit) []string {
+	if habit.ReminderTimes == nil {
+		return []string{}
+	}
+	return habit.ReminderTimes
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHabitOrderBy(t *testing.T) {
+	assert.Equal(t, "position ASC, created_at DESC", habitOrderBy[models.HabitSortPosition])
+	assert.Equal(t, "lower(name) ASC, created_at DESC", habitOrderBy[models.HabitSortName])
+	assert.Equal(t, "created_at DESC", habitOrderBy[models.HabitSortCreated])
+	assert.Len(t, habitOrderBy, 3)
+}
+
+func TestHabitOrderBy_CoversEveryValidSort(t *testing.T) {
+	for _, sortBy := range []string{models.HabitSortPosition, models.HabitSortName, models.HabitSortCreated} {
+		assert.NoError(t, models.ValidateHabitSort(sortBy))
+		assert.Contains(t, habitOrderBy, sortBy)
+	}
+	assert.ErrorIs(t, models.ValidateHabitSort("created_at"), models.ErrInvalidHabitSort)
+}
+
+func TestHabitMergeHistoryQueries_MoveEveryHabitTable(t *testing.T) {
+	for _, table := range []string{"habit_logs", "reminder_events", "habit_streak_freezes"} {
+		found := false
+		for _, query := range habitMergeHistoryQueries {
+			if strings.Contains(query, "UPDATE "+table+" ") {
+				found = true
+				assert.Contains(t, query, "SET habit_id = $2", table)
+			}
+		}
+		assert.True(t, found, "merge does not move %s", table)
+	}
+}
+
+func TestHabitMergeHistoryQueries_SkipFreezesTargetAlreadyHas(t *testing.T) {
+	freezes := habitMergeHistoryQueries[len(habitMergeHistoryQueries)-1]
+
+	assert.Contains(t, freezes, "NOT EXISTS")
+	assert.Contains(t, freezes, "t.period_start = f.period_start")
+}
+
+func TestPauseHabitQuery_RecordsPauseHistory(t *testing.T) {
+	assert.Contains(t, pauseHabitQuery, "INSERT INTO habit_pauses")
+	assert.Contains(t, pauseHabitQuery, "SELECT id, user_id, $3, $4 FROM paused")
+	// Extending the current pause must not add a second window.
+	assert.Contains(t, pauseHabitQuery, "ON CONFLICT (habit_id, paused_from) DO UPDATE SET paused_until = EXCLUDED.paused_until")
+}
+
+func TestHabitColumns_LoadPauseHistory(t *testing.T) {
+	assert.Contains(t, habitColumns, "FROM habit_pauses p WHERE p.habit_id = habits.id")
+
+	// jsonb_build_object renders timestamptz values like this.
+	raw := `[{"paused_from": "2025-11-01T09:00:00+00:00", "paused_until": "2025-11-03T09:00:00+00:00"},
+	         {"paused_from": "2025-11-08T18:30:00.5+01:00", "paused_until": "2025-11-09T18:30:00+01:00"}]`
+	var pauses []models.HabitPause
+	assert.NoError(t, json.Unmarshal([]byte(raw), &pauses))
+	assert.Len(t, pauses, 2)
+	assert.True(t, pauses[0].From.Equal(time.Date(2025, 11, 1, 9, 0, 0, 0, time.UTC)))
+	assert.True(t, pauses[1].Until.Equal(time.Date(2025, 11, 9, 17, 30, 0, 0, time.UTC)))
+}
+
+// tokenQuerier stands in for the transaction streak freezes run in. Inserts
+// affect a row unless conflict is set, and the token UPDATE ... RETURNING
+// finds the user only while balance is positive.
+type tokenQuerier struct {
+	conflict bool
+	balance  int
+	execs    []string
+}
+
+func (q *tokenQuerier) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	q.execs = append(q.execs, sql)
+	if strings.Contains(sql, "INSERT") && q.conflict {
+		return pgconn.NewCommandTag("INSERT 0 0"), nil
+	}
+	if strings.Contains(sql, "streak_freeze_tokens + 1") {
+		q.balance++
+		return pgconn.NewCommandTag("UPDATE 1"), nil
+	}
+	return pgconn.NewCommandTag("INSERT 0 1"), nil
+}
+
+func (q *tokenQuerier) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	return nil, nil
+}
+
+func (q *tokenQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	q.execs = append(q.execs, sql)
+	return balanceRow{q: q}
+}
+
+type balanceRow struct{ q *tokenQuerier }
+
+func (r balanceRow) Scan(dest ...any) error {
+	if r.q.balance <= 0 {
+		return pgx.ErrNoRows
+	}
+	r.q.balance--
+	*dest[0].(*int) = r.q.balance
+	return nil
+}
+
+func TestGrantStreakFreeze_CreditsOncePerMilestone(t *testing.T) {
+	period := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	q := &tokenQuerier{}
+
+	granted, err := grantStreakFreeze(context.Background(), q, uuid.New(), uuid.New(), period)
+	assert.NoError(t, err)
+	assert.True(t, granted)
+	assert.Equal(t, 1, q.balance)
+	assert.Contains(t, q.execs[0], "INSERT INTO habit_streak_freeze_grants")
+
+	// The milestone's period was already credited: no second token.
+	q.conflict = true
+	granted, err = grantStreakFreeze(context.Background(), q, uuid.New(), uuid.New(), period)
+	assert.NoError(t, err)
+	assert.False(t, granted)
+	assert.Equal(t, 1, q.balance)
+}
+
+func TestSpendStreakFreeze_SpendsEarnedToken(t *testing.T) {
+	ctx := context.Background()
+	habitID, userID := uuid.New(), uuid.New()
+	period := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	q := &tokenQuerier{}
+
+	// Without an earned token there is nothing to spend.
+	_, err := spendStreakFreeze(ctx, q, habitID, userID, period)
+	assert.ErrorIs(t, err, models.ErrNoFreezeTokens)
+
+	_, err = grantStreakFreeze(ctx, q, habitID, userID, period.AddDate(0, 0, -1))
+	assert.NoError(t, err)
+
+	balance, err := spendStreakFreeze(ctx, q, habitID, userID, period)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, balance)
+
+	// A period already frozen keeps its token.
+	q.conflict = true
+	_, err = spendStreakFreeze(ctx, q, habitID, userID, period)
+	assert.ErrorIs(t, err, models.ErrNothingToFreeze)
+}
+
+// createQuerier records the statements createHabit runs and hands back the
+// next position for the insert.
+type createQuerier struct {
+	statements []string
+	lockedUser any
+}
+
+func (q *createQuerier) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	q.statements = append(q.statements, sql)
+	q.lockedUser = args[0]
+	return pgconn.NewCommandTag("SELECT 1"), nil
+}
+
+func (q *createQuerier) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	return nil, nil
+}
+
+func (q *createQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	q.statements = append(q.statements, sql)
+	return createdHabitRow{position: 3}
+}
+
+type createdHabitRow struct{ position int }
+
+func (r createdHabitRow) Scan(dest ...any) error {
+	*dest[1].(*int) = r.position
+	return nil
+}
+
+func TestCreateHabit_LocksOwnerBeforeTakingPosition(t *testing.T) {
+	q := &createQuerier{}
+	habit := &models.Habit{UserID: uuid.New(), Name: "Read", Frequency: "daily", TargetCount: 1}
+
+	assert.NoError(t, createHabit(context.Background(), q, habit))
+
+	assert.Len(t, q.statements, 2)
+	assert.Contains(t, q.statements[0], "FROM users WHERE id = $1 FOR UPDATE")
+	assert.Equal(t, habit.UserID, q.lockedUser)
+	assert.Contains(t, q.statements[1], "INSERT INTO habits")
+	assert.Contains(t, q.statements[1], "SELECT COALESCE(MAX(position) + 1, 0) FROM habits WHERE user_id = $2")
+	assert.Equal(t, 3, habit.Position)
+	assert.True(t, habit.IsActive)
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/lumen/backend/internal/models"
+)
+
+// ProfileRepository stores per-user display preferences.
+type ProfileRepository interface {
+	GetWaterUnit(ctx context.Context, userID uuid.UUID) (models.WaterUnit, error)
+	SetWaterUnit(ctx context.Context, userID uuid.UUID, unit models.WaterUnit) error
+	GetWeekStart(ctx context.Context, userID uuid.UUID) (models.WeekStart, error)
+	SetWeekStart(ctx context.Context, userID uuid.UUID, start models.WeekStart) error
+}
+
+type profileRepository struct {
+	db *Database
+}
+
+func NewProfileRepository(db *Database) ProfileRepository {
+	return &profileRepository{db: db}
+}
+
+// GetWaterUnit returns the unit the user reads and enters water intake in.
+func (r *profileRepository) GetWaterUnit(ctx context.Context, userID uuid.UUID) (models.WaterUnit, error) {
+	var unit models.WaterUnit
+	err := r.db.Reader(ctx).QueryRow(ctx, `SELECT water_unit FROM users WHERE id = $1`, userID).Scan(&unit)
+
+	if err == pgx.ErrNoRows {
+		return "", models.ErrNotFound
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to get water unit: %w", err)
+	}
+
+	return unit, nil
+}
+
+// SetWaterUnit stores the unit the user reads and enters water intake in.
+func (r *profileRepository) SetWaterUnit(ctx context.Context, userID uuid.UUID, unit models.WaterUnit) error {
+	query := `UPDATE users SET water_unit = $2, ` + touchUpdatedAt + ` WHERE id = $1`
+
+	result, err := r.db.Writer().Exec(ctx, query, userID, unit)
+	if err != nil {
+		return fmt.Errorf("failed to set water unit: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// GetWeekStart returns the day the user's weeks begin on.
+func (r *profileRepository) GetWeekStart(ctx context.Context, userID uuid.UUID) (models.WeekStart, error) {
+	var start models.WeekStart
+	err := r.db.Reader(ctx).QueryRow(ctx, `SELECT week_start FROM users WHERE id = $1`, userID).Scan(&start)
+
+	if err == pgx.ErrNoRows {
+		return "", models.ErrNotFound
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to get week start: %w", err)
+	}
+
+	return start, nil
+}
+
+// SetWeekStart stores the day the user's weeks begin on.
+func (r *profileRepository) SetWeekStart(ctx context.Context, userID uuid.UUID, start models.WeekStart) error {
+	query := `UPDATE users SET week_start = $2, ` + touchUpdatedAt + ` WHERE id = $1`
+
+	result, err := r.db.Writer().Exec(ctx, query, userID, start)
+	if err != nil {
+		return fmt.Errorf("failed to set week start: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/lumen/backend/internal/models"
+)
+
+type ReminderEventRepository interface {
+	Create(ctx context.Context, event *models.ReminderEvent) error
+	Acknowledge(ctx context.Context, id, userID uuid.UUID, at time.Time) (*models.ReminderEvent, error)
+	GetSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]models.ReminderEvent, error)
+}
+
+type reminderEventRepository struct {
+	db *Database
+}
+
+func NewReminderEventRepository(db *Database) ReminderEventRepository {
+	return &reminderEventRepository{db: db}
+}
+
+func (r *reminderEventRepository) Create(ctx context.Context, event *models.ReminderEvent) error {
+	query := `
+		INSERT INTO reminder_events (id, user_id, habit_id, kind, sent_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	event.ID = uuid.New()
+
+	err := r.db.Writer().QueryRow(
+		ctx,
+		query,
+		event.ID,
+		event.UserID,
+		event.HabitID,
+		event.Kind,
+		event.SentAt,
+	).Scan(&event.ID, &event.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create reminder event: %w", err)
+	}
+
+	return nil
+}
+
+// Acknowledge marks the user's reminder as acted on. Acknowledging it again
+// keeps the original acknowledgment time.
+func (r *reminderEventRepository) Acknowledge(ctx context.Context, id, userID uuid.UUID, at time.Time) (*models.ReminderEvent, error) {
+	query := `
+		UPDATE reminder_events
+		SET acknowledged_at = COALESCE(acknowledged_at, $3)
+		WHERE id = $1 AND user_id = $2
+		RETURNING id, user_id, habit_id, kind, sent_at, acknowledged_at, created_at
+	`
+
+	var event models.ReminderEvent
+	err := r.db.Writer().QueryRow(ctx, query, id, userID, at).Scan(
+		&event.ID,
+		&event.UserID,
+		&event.HabitID,
+		&event.Kind,
+		&event.SentAt,
+		&event.AcknowledgedAt,
+		&event.CreatedAt,
+	)
+
+	if err == pgx.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to acknowledge reminder event: %w", err)
+	}
+
+	return &event, nil
+}
+
+// GetSince returns the user's reminder events sent at or after since.
+func (r *reminderEventRepository) GetSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]models.ReminderEvent, error) {
+	query := `
+		SELECT id, user_id, habit_id, kind, sent_at, acknowledged_at, created_at
+		FROM reminder_events
+		WHERE user_id = $1 AND sent_at >= $2
+		ORDER BY sent_at DESC
+	`
+
+	rows, err := r.db.Reader(ctx).Query(ctx, query, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reminder events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.ReminderEvent
+	for rows.Next() {
+		var event models.ReminderEvent
+		err := rows.Scan(
+			&event.ID,
+			&event.UserID,
+			&event.HabitID,
+			&event.Kind,
+			&event.SentAt,
+			&event.AcknowledgedAt,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reminder event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reminder events: %w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/lumen/backend/internal/models"
+)
+
+type ReminderRepository interface {
+	ListUserIDs(ctx context.Context, after uuid.UUID, limit int) ([]uuid.UUID, error)
+	GetUserTimezone(ctx context.Context, userID uuid.UUID) (string, error)
+	GetDailyLogReminderTime(ctx context.Context, userID uuid.UUID) (string, error)
+	SetDailyLogReminderTime(ctx context.Context, userID uuid.UUID, at *string) error
+}
+
+type reminderRepository struct {
+	db *Database
+}
+
+func NewReminderRepository(db *Database) ReminderRepository {
+	return &reminderRepository{db: db}
+}
+
+// ListUserIDs pages through users with at least one active habit that has
+// reminder times or with a daily log reminder, in ID order starting after the
+// given ID.
+func (r *reminderRepository) ListUserIDs(ctx context.Context, after uuid.UUID, limit int) ([]uuid.UUID, error) {
+	query := `
+		SELECT user_id FROM (
+			SELECT user_id
+			FROM habits
+			WHERE is_active AND deleted_at IS NULL
+			  AND jsonb_typeof(reminder_times) = 'array'
+			  AND reminder_times <> '[]'::jsonb
+			UNION
+			SELECT id
+			FROM users
+			WHERE daily_log_reminder_time IS NOT NULL
+		) reminder_users
+		WHERE user_id > $1
+		ORDER BY user_id
+		LIMIT $2
+	`
+
+	rows, err := r.db.Reader(ctx).Query(ctx, query, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reminder users: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan reminder user: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reminder users: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (r *reminderRepository) GetUserTimezone(ctx context.Context, userID uuid.UUID) (string, error) {
+	var timezone string
+	err := r.db.Reader(ctx).QueryRow(ctx, `SELECT timezone FROM users WHERE id = $1`, userID).Scan(&timezone)
+
+	if err == pgx.ErrNoRows {
+		return "", models.ErrNotFound
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to get user timezone: %w", err)
+	}
+
+	return timezone, nil
+}
+
+// GetDailyLogReminderTime returns the user's daily log reminder time, or ""
+// when the reminder is off.
+func (r *reminderRepository) GetDailyLogReminderTime(ctx context.Context, userID uuid.UUID) (string, error) {
+	var at *string
+	err := r.db.Reader(ctx).QueryRow(ctx, `SELECT daily_log_reminder_time FROM users WHERE id = $1`, userID).Scan(&at)
+
+	if err == pgx.ErrNoRows {
+		return "", models.ErrNotFound
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to get daily log reminder time: %w", err)
+	}
+
+	if at == nil {
+		return "", nil
+	}
+	return *at, nil
+}
+
+// SetDailyLogReminderTime stores the user's daily log reminder time; nil
+// turns the reminder off.
+func (r *reminderRepository) SetDailyLogReminderTime(ctx context.Context, userID uuid.UUID, at *string) error {
+	query := `UPDATE users SET daily_log_reminder_time = $2, ` + touchUpdatedAt + ` WHERE id = $1`
+
+	result, err := r.db.Writer().Exec(ctx, query, userID, at)
+	if err != nil {
+		return fmt.Errorf("failed to set daily log reminder time: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
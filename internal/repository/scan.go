@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ScanErrorMode decides what a list query does with a row that fails to
+// scan, such as one holding a value its model can't represent.
+type ScanErrorMode string
+
+const (
+	// ScanErrorsFail fails the whole listing on the first bad row.
+	ScanErrorsFail ScanErrorMode = "fail"
+	// ScanErrorsSkip logs and counts the bad row and lists the rest.
+	ScanErrorsSkip ScanErrorMode = "skip"
+)
+
+// ScanErrorModeFor picks the mode for an environment: mode when set,
+// otherwise skip in production, where one malformed row shouldn't take a
+// user's whole list down, and fail anywhere else, where it should be noticed.
+func ScanErrorModeFor(appEnv, mode string) (ScanErrorMode, error) {
+	switch ScanErrorMode(mode) {
+	case "":
+		if appEnv == "production" {
+			return ScanErrorsSkip, nil
+		}
+		return ScanErrorsFail, nil
+	case ScanErrorsFail, ScanErrorsSkip:
+		return ScanErrorMode(mode), nil
+	}
+	return "", fmt.Errorf("invalid scan error mode %q: must be fail or skip", mode)
+}
+
+// scanList reads every row with scan and closes rows; what names the rows in
+// errors and logs. A row that fails to scan fails the listing unless db skips
+// scan errors, in which case it is logged, counted in db.SkippedRows and left
+// out. Errors from the rows themselves always fail the listing.
+func scanList[T any](db *Database, rows pgx.Rows, what string, scan func(row pgx.Rows, item *T) error) ([]T, error) {
+	defer rows.Close()
+
+	var items []T
+	for rows.Next() {
+		var item T
+		if err := scan(rows, &item); err != nil {
+			if db == nil || db.ScanErrors != ScanErrorsSkip {
+				return nil, fmt.Errorf("failed to scan %s: %w", what, err)
+			}
+			db.skippedRows.Add(1)
+			logger.Warn("Skipped malformed row", zap.String("list", what), zap.Error(err))
+			continue
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating %ss: %w", what, err)
+	}
+
+	return items, nil
+}
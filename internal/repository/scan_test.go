@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRows serves one value per row; a nil value makes that row's Scan fail.
+type fakeRows struct {
+	values []*string
+	next   int
+	closed bool
+}
+
+func (r *fakeRows) Close()                                       { r.closed = true }
+func (r *fakeRows) Err() error                                   { return nil }
+func (r *fakeRows) CommandTag() pgconn.CommandTag                { return pgconn.CommandTag{} }
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *fakeRows) Values() ([]any, error)                       { return nil, nil }
+func (r *fakeRows) RawValues() [][]byte                          { return nil }
+func (r *fakeRows) Conn() *pgx.Conn                              { return nil }
+
+func (r *fakeRows) Next() bool {
+	if r.next >= len(r.values) {
+		return false
+	}
+	r.next++
+	return true
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	value := r.values[r.next-1]
+	if value == nil {
+		return errors.New("cannot scan NULL into *string")
+	}
+	// taskColumns puts the title third.
+	*dest[2].(*string) = *value
+	return nil
+}
+
+// fakeQuerier answers every query with rows.
+type fakeQuerier struct {
+	rows *fakeRows
+}
+
+func (q fakeQuerier) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, nil
+}
+
+func (q fakeQuerier) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	return q.rows, nil
+}
+
+func (q fakeQuerier) QueryRow(context.Context, string, ...any) pgx.Row {
+	return nil
+}
+
+func listTaskTitles(t *testing.T, db *Database, titles ...*string) ([]string, error) {
+	t.Helper()
+
+	var q Querier = fakeQuerier{rows: &fakeRows{values: titles}}
+	rows, err := q.Query(context.Background(), "SELECT "+taskColumns+" FROM tasks")
+	assert.NoError(t, err)
+
+	tasks, err := scanTasks(db, rows)
+	assert.True(t, rows.(*fakeRows).closed)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(tasks))
+	for i, task := range tasks {
+		names[i] = task.Title
+	}
+	return names, nil
+}
+
+func title(s string) *string { return &s }
+
+func TestScanList_SkipModeReturnsTheRest(t *testing.T) {
+	logs := observeLogs(t)
+	db := &Database{ScanErrors: ScanErrorsSkip}
+
+	titles, err := listTaskTitles(t, db, title("first"), nil, title("third"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first", "third"}, titles)
+	assert.Equal(t, int64(1), db.SkippedRows())
+
+	skipped := logs.FilterMessage("Skipped malformed row").All()
+	assert.Len(t, skipped, 1)
+	assert.Equal(t, "task", skipped[0].ContextMap()["list"])
+}
+
+func TestScanList_FailModeSurfacesTheError(t *testing.T) {
+	db := &Database{ScanErrors: ScanErrorsFail}
+
+	titles, err := listTaskTitles(t, db, title("first"), nil, title("third"))
+
+	assert.ErrorContains(t, err, "failed to scan task")
+	assert.Nil(t, titles)
+	assert.Zero(t, db.SkippedRows())
+}
+
+func TestScanList_NilDatabaseFails(t *testing.T) {
+	_, err := scanList(nil, &fakeRows{values: []*string{nil}}, "task", func(row pgx.Rows, task *models.Task) error {
+		return row.Scan(taskScanTargets(task)...)
+	})
+
+	assert.Error(t, err)
+}
+
+func TestScanErrorModeFor(t *testing.T) {
+	for _, tc := range []struct {
+		env, mode string
+		want      ScanErrorMode
+	}{
+		{"production", "", ScanErrorsSkip},
+		{"development", "", ScanErrorsFail},
+		{"production", "fail", ScanErrorsFail},
+		{"development", "skip", ScanErrorsSkip},
+	} {
+		mode, err := ScanErrorModeFor(tc.env, tc.mode)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.want, mode, "%s with %q", tc.env, tc.mode)
+	}
+
+	_, err := ScanErrorModeFor("production", "ignore")
+	assert.Error(t, err)
+}
@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+)
+
+// demoSeed is the fixed data set SeedDemoData inserts for one user.
+type demoSeed struct {
+	Habits      []models.Habit
+	Completions []models.HabitCompletion
+	Tasks       []models.Task
+	DailyLogs   []models.DailyLog
+}
+
+// demoID derives a stable ID for the n-th seeded row of kind, so seeding the
+// same user twice produces the same rows.
+func demoID(userID uuid.UUID, kind string, n int) uuid.UUID {
+	return uuid.NewSHA1(userID, []byte(fmt.Sprintf("lumen-demo:%s:%d", kind, n)))
+}
+
+// buildDemoSeed lays out the demo data for userID around today (a UTC date):
+// three habits with two weeks of completions, tasks across horizons and
+// statuses, and a week of daily logs.
+func buildDemoSeed(userID uuid.UUID, today time.Time) demoSeed {
+	created := today.AddDate(0, 0, -14)
+	var seed demoSeed
+
+	habits := []struct {
+		name, color, icon, frequency string
+		reminders                    []string
+		// every is the gap in days between completions.
+		every int
+	}{
+		{"Morning run", "#10B981", "run", "daily", []string{"07:00"}, 1},
+		{"Read 20 pages", "#3B82F6", "book", "daily", []string{"21:30"}, 2},
+		{"Weekly review", "#8B5CF6", "checklist", "weekly", nil, 7},
+	}
+	for i, h := range habits {
+		habit := models.Habit{
+			ID:            demoID(userID, "habit", i),
+			UserID:        userID,
+			Name:          h.name,
+			Color:         h.color,
+			Icon:          h.icon,
+			Frequency:     h.frequency,
+			TargetCount:   1,
+			IsActive:      true,
+			Position:      i,
+			ReminderTimes: h.reminders,
+			CreatedAt:     created,
+			UpdatedAt:     created,
+		}
+		seed.Habits = append(seed.Habits, habit)
+
+		for day := 0; day < 14; day += h.every {
+			completedAt := created.AddDate(0, 0, day+1).Add(time.Duration(7+i*6) * time.Hour)
+			seed.Completions = append(seed.Completions, models.HabitCompletion{
+				ID:          demoID(userID, "completion", len(seed.Completions)),
+				HabitID:     habit.ID,
+				UserID:      userID,
+				CompletedAt: completedAt,
+				CreatedAt:   completedAt,
+			})
+		}
+	}
+
+	tasks := []struct {
+		title, horizon, priority, status string
+		tags                             []string
+		// dueIn is days from today; nil leaves the task undated.
+		dueIn *int
+	}{
+		{"Finish quarterly report", "now", "urgent", "in_progress", []string{"work"}, intPtr(1)},
+		{"Book dentist appointment", "now", "medium", "todo", []string{"health"}, intPtr(3)},
+		{"Plan weekend hike", "next", "low", "todo", []string{"personal"}, intPtr(5)},
+		{"Learn Go generics", "later", "medium", "todo", []string{"learning"}, nil},
+		{"Renew passport", "someday", "low", "todo", nil, nil},
+		{"Set up budget spreadsheet", "now", "high", "done", []string{"finance"}, intPtr(-2)},
+	}
+	for i, t := range tasks {
+		task := models.Task{
+			ID:        demoID(userID, "task", i),
+			UserID:    userID,
+			Title:     t.title,
+			Horizon:   t.horizon,
+			Priority:  t.priority,
+			Status:    t.status,
+			Tags:      models.NormalizeTags(t.tags),
+			CreatedAt: created,
+			UpdatedAt: created,
+		}
+		if t.dueIn != nil {
+			due := today.AddDate(0, 0, *t.dueIn).Add(17 * time.Hour)
+			task.DueDate = &due
+		}
+		if t.status == "done" {
+			completedAt := today.AddDate(0, 0, -3).Add(15 * time.Hour)
+			task.CompletedAt = &completedAt
+		}
+		seed.Tasks = append(seed.Tasks, task)
+	}
+
+	for day := 7; day >= 1; day-- {
+		date := today.AddDate(0, 0, -day)
+		n := len(seed.DailyLogs)
+		seed.DailyLogs = append(seed.DailyLogs, models.DailyLog{
+			ID:                 demoID(userID, "daily-log", n),
+			UserID:             userID,
+			Date:               date,
+			MorningRoutine:     n%3 != 2,
+			EveningRoutine:     n%2 == 0,
+			WaterIntake:        6 + n%3,
+			SleepHours:         6.5 + float64(n%4)*0.5,
+			EnergyLevel:        3 + n%3,
+			MoodRating:         3 + (n+1)%3,
+			ProductivityRating: 2 + n%4,
+			IsRestDay:          date.Weekday() == time.Sunday,
+			CreatedAt:          date.Add(22 * time.Hour),
+			UpdatedAt:          date.Add(22 * time.Hour),
+		})
+	}
+
+	return seed
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+// Every seed insert skips rows that already exist, by ID or by a natural key
+// such as the daily log date, which is what makes seeding idempotent.
+const (
+	seedHabitQuery = `
+		INSERT INTO habits (id, user_id, name, color, icon, frequency, target_count, is_active, reminder_times, position, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT DO NOTHING`
+	seedCompletionQuery = `
+		INSERT INTO habit_completions (id, habit_id, user_id, completed_at, notes, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT DO NOTHING`
+	seedTaskQuery = `
+		INSERT INTO tasks (id, user_id, title, description, horizon, priority, status, tags, due_date, completed_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT DO NOTHING`
+	seedDailyLogQuery = `
+		INSERT INTO daily_logs (
+			id, user_id, date, morning_routine, evening_routine, water_intake,
+			sleep_hours, energy_level, mood_rating, productivity_rating, notes,
+			is_rest_day, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT DO NOTHING`
+)
+
+// SeedDemoData inserts a consistent set of habits, habit completions, tasks
+// and daily logs for an existing user, for local development. Rows already
+// present are left alone, so running it again inserts nothing; it returns
+// how many rows were inserted.
+func SeedDemoData(ctx context.Context, db *Database, userID uuid.UUID) (int, error) {
+	now := time.Now().UTC()
+	seed := buildDemoSeed(userID, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC))
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	inserted := 0
+	exec := func(what, query string, args ...interface{}) error {
+		result, err := tx.Exec(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to seed %s: %w", what, err)
+		}
+		inserted += int(result.RowsAffected())
+		return nil
+	}
+
+	for _, h := range seed.Habits {
+		if err := exec("habit", seedHabitQuery,
+			h.ID, h.UserID, h.Name, h.Color, h.Icon, h.Frequency, h.TargetCount,
+			h.IsActive, h.ReminderTimes, h.Position, h.CreatedAt, h.UpdatedAt,
+		); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, c := range seed.Completions {
+		if err := exec("habit completion", seedCompletionQuery,
+			c.ID, c.HabitID, c.UserID, c.CompletedAt, c.Notes, c.CreatedAt,
+		); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, t := range seed.Tasks {
+		if err := exec("task", seedTaskQuery,
+			t.ID, t.UserID, t.Title, t.Description, t.Horizon, t.Priority, t.Status,
+			t.Tags, t.DueDate, t.CompletedAt, t.CreatedAt, t.UpdatedAt,
+		); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, l := range seed.DailyLogs {
+		if err := exec("daily log", seedDailyLogQuery,
+			l.ID, l.UserID, l.Date, l.MorningRoutine, l.EveningRoutine, l.WaterIntake,
+			l.SleepHours, l.EnergyLevel, l.MoodRating, l.ProductivityRating, l.Notes,
+			l.IsRestDay, l.CreatedAt, l.UpdatedAt,
+		); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit seed: %w", err)
+	}
+
+	return inserted, nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func demoIDs(seed demoSeed) []uuid.UUID {
+	var ids []uuid.UUID
+	for _, h := range seed.Habits {
+		ids = append(ids, h.ID)
+	}
+	for _, c := range seed.Completions {
+		ids = append(ids, c.ID)
+	}
+	for _, t := range seed.Tasks {
+		ids = append(ids, t.ID)
+	}
+	for _, l := range seed.DailyLogs {
+		ids = append(ids, l.ID)
+	}
+	return ids
+}
+
+func TestBuildDemoSeed_RowCounts(t *testing.T) {
+	userID := uuid.New()
+	seed := buildDemoSeed(userID, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC))
+
+	assert.Len(t, seed.Habits, 3)
+	// Daily, every other day and weekly over two weeks.
+	assert.Len(t, seed.Completions, 14+7+2)
+	assert.Len(t, seed.Tasks, 6)
+	assert.Len(t, seed.DailyLogs, 7)
+
+	for _, c := range seed.Completions {
+		assert.Equal(t, userID, c.UserID)
+	}
+	for _, task := range seed.Tasks {
+		assert.NoError(t, task.Validate())
+	}
+	for _, habit := range seed.Habits {
+		assert.NoError(t, habit.Validate())
+	}
+}
+
+func TestBuildDemoSeed_SafeToRunTwice(t *testing.T) {
+	userID := uuid.New()
+	first := buildDemoSeed(userID, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC))
+	// A later run, even on another day, targets the same rows.
+	second := buildDemoSeed(userID, time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, demoIDs(first), demoIDs(second))
+
+	seen := make(map[uuid.UUID]bool)
+	for _, id := range demoIDs(first) {
+		assert.False(t, seen[id], "duplicate seed ID %s", id)
+		seen[id] = true
+	}
+
+	for _, query := range []string{seedHabitQuery, seedCompletionQuery, seedTaskQuery, seedDailyLogQuery} {
+		assert.Contains(t, query, "ON CONFLICT DO NOTHING")
+	}
+}
+
+func TestBuildDemoSeed_IDsDifferPerUser(t *testing.T) {
+	today := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	a := buildDemoSeed(uuid.New(), today)
+	b := buildDemoSeed(uuid.New(), today)
+
+	assert.NotEqual(t, a.Habits[0].ID, b.Habits[0].ID)
+	assert.NotEqual(t, a.Tasks[0].ID, b.Tasks[0].ID)
+}
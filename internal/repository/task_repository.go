@@ -13,9 +13,18 @@ import (
 
 type TaskRepository interface {
 	Create(ctx context.Context, task *models.Task) error
+	CreateMany(ctx context.Context, tasks []*models.Task) error
 	GetByID(ctx context.Context, id, userID uuid.UUID) (*models.Task, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID, filter models.TaskFilter) ([]models.Task, error)
+	GetByHorizon(ctx context.Context, userID uuid.UUID, filter models.TaskFilter, limit int) (map[string]models.HorizonGroup, error)
 	Update(ctx context.Context, task *models.Task) error
+	Snooze(ctx context.Context, task *models.Task) error
+	GetDueWithin(ctx context.Context, userID uuid.UUID, within time.Duration) ([]models.Task, error)
+	GetDueBetween(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.Task, error)
+	GetCompletedSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]time.Time, error)
+	ArchiveCompleted(ctx context.Context, userID uuid.UUID, completedBefore *time.Time) ([]models.Task, error)
+	MoveToGoal(ctx context.Context, userID uuid.UUID, taskIDs []uuid.UUID, goalID *uuid.UUID) ([]models.Task, error)
+	GetStatuses(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]string, error)
 	Delete(ctx context.Context, id, userID uuid.UUID) error
 }
 
@@ -27,19 +36,24 @@ func NewTaskRepository(db *Database) TaskRepository {
 	return &taskRepository{db: db}
 }
 
+// Create inserts task along with its BlockedBy dependencies, if any.
 func (r *taskRepository) Create(ctx context.Context, task *models.Task) error {
 	query := `
-		INSERT INTO tasks (id, user_id, title, description, horizon, priority, status, due_date, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO tasks (id, user_id, title, description, horizon, priority, status, tags, due_date)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id, created_at, updated_at
 	`
 
 	task.ID = uuid.New()
 	task.Status = "todo"
-	task.CreatedAt = time.Now()
-	task.UpdatedAt = time.Now()
 
-	err := r.db.Pool.QueryRow(
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(
 		ctx,
 		query,
 		task.ID,
@@ -49,27 +63,80 @@ func (r *taskRepository) Create(ctx context.Context, task *models.Task) error {
 		task.Horizon,
 		task.Priority,
 		task.Status,
+		models.NormalizeTags(task.Tags),
 		task.DueDate,
-		task.CreatedAt,
-		task.UpdatedAt,
 	).Scan(&task.ID, &task.CreatedAt, &task.UpdatedAt)
 
 	if err != nil {
 		return fmt.Errorf("failed to create task: %w", err)
 	}
 
+	if task.BlockedBy != nil {
+		if err := replaceTaskDependencies(ctx, tx, task); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit task: %w", err)
+	}
+
+	return nil
+}
+
+// CreateMany inserts tasks in a single transaction; either all are created
+// or none are. Each task keeps its own status.
+func (r *taskRepository) CreateMany(ctx context.Context, tasks []*models.Task) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin task import: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO tasks (id, user_id, title, description, horizon, priority, status, tags, due_date)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING created_at, updated_at
+	`
+
+	for _, task := range tasks {
+		task.ID = uuid.New()
+
+		err := tx.QueryRow(
+			ctx,
+			query,
+			task.ID,
+			task.UserID,
+			task.Title,
+			task.Description,
+			task.Horizon,
+			task.Priority,
+			task.Status,
+			models.NormalizeTags(task.Tags),
+			task.DueDate,
+		).Scan(&task.CreatedAt, &task.UpdatedAt)
+
+		if err != nil {
+			return fmt.Errorf("failed to create task: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit task import: %w", err)
+	}
+
 	return nil
 }
 
 func (r *taskRepository) GetByID(ctx context.Context, id, userID uuid.UUID) (*models.Task, error) {
 	query := `
-		SELECT id, user_id, title, description, horizon, priority, status, due_date, completed_at, created_at, updated_at
+		SELECT id, user_id, title, description, horizon, priority, status, tags, due_date, completed_at, snooze_count, created_at, updated_at
 		FROM tasks
-		WHERE id = $1 AND user_id = $2
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
 	`
 
 	var task models.Task
-	err := r.db.Pool.QueryRow(ctx, query, id, userID).Scan(
+	err := r.db.Reader(ctx).QueryRow(ctx, query, id, userID).Scan(
 		&task.ID,
 		&task.UserID,
 		&task.Title,
@@ -77,8 +144,10 @@ func (r *taskRepository) GetByID(ctx context.Context, id, userID uuid.UUID) (*mo
 		&task.Horizon,
 		&task.Priority,
 		&task.Status,
+		&task.Tags,
 		&task.DueDate,
 		&task.CompletedAt,
+		&task.SnoozeCount,
 		&task.CreatedAt,
 		&task.UpdatedAt,
 	)
@@ -91,74 +160,309 @@ func (r *taskRepository) GetByID(ctx context.Context, id, userID uuid.UUID) (*mo
 		return nil, fmt.Errorf("failed to get task: %w", err)
 	}
 
-	return &task, nil
+	tasks := []models.Task{task}
+	if err := r.loadDependencies(ctx, userID, tasks); err != nil {
+		return nil, err
+	}
+
+	return &tasks[0], nil
 }
 
+// taskColumns is the column list scanTasks expects.
+const taskColumns = `id, user_id, title, description, horizon, priority, status, tags, due_date, completed_at, snooze_count, created_at, updated_at`
+
 func (r *taskRepository) GetByUserID(ctx context.Context, userID uuid.UUID, filter models.TaskFilter) ([]models.Task, error) {
+	where, args := taskFilterClause(userID, filter)
 	query := `
-		SELECT id, user_id, title, description, horizon, priority, status, due_date, completed_at, created_at, updated_at
+		SELECT ` + taskColumns + `
 		FROM tasks
-		WHERE user_id = $1
-	`
+		WHERE ` + where
 
-	args := []interface{}{userID}
-	argCount := 1
+	query += " ORDER BY created_at DESC"
 
-	if filter.Horizon != "" {
-		argCount++
-		query += fmt.Sprintf(" AND horizon = $%d", argCount)
-		args = append(args, filter.Horizon)
+	rows, err := r.db.Reader(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
 	}
 
-	if filter.Status != "" {
-		argCount++
-		query += fmt.Sprintf(" AND status = $%d", argCount)
-		args = append(args, filter.Status)
+	tasks, err := scanTasks(r.db, rows)
+	if err != nil {
+		return nil, err
 	}
 
-	if filter.Priority != "" {
-		argCount++
-		query += fmt.Sprintf(" AND priority = $%d", argCount)
-		args = append(args, filter.Priority)
+	if err := r.loadDependencies(ctx, userID, tasks); err != nil {
+		return nil, err
 	}
 
-	query += " ORDER BY created_at DESC"
+	return tasks, nil
+}
+
+// GetByHorizon returns the user's tasks matching filter grouped by horizon,
+// newest first, with at most limit tasks per group. filter.Horizon is
+// ignored.
+func (r *taskRepository) GetByHorizon(ctx context.Context, userID uuid.UUID, filter models.TaskFilter, limit int) (map[string]models.HorizonGroup, error) {
+	filter.Horizon = ""
+	query, args := taskByHorizonQuery(userID, filter, limit)
 
-	rows, err := r.db.Pool.Query(ctx, query, args...)
+	rows, err := r.db.Reader(ctx).Query(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get tasks: %w", err)
+		return nil, fmt.Errorf("failed to get tasks by horizon: %w", err)
 	}
 	defer rows.Close()
 
 	var tasks []models.Task
+	totals := make(map[string]int)
 	for rows.Next() {
 		var task models.Task
-		err := rows.Scan(
-			&task.ID,
-			&task.UserID,
-			&task.Title,
-			&task.Description,
-			&task.Horizon,
-			&task.Priority,
-			&task.Status,
-			&task.DueDate,
-			&task.CompletedAt,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-		)
-		if err != nil {
+		var total int
+		if err := rows.Scan(append(taskScanTargets(&task), &total)...); err != nil {
 			return nil, fmt.Errorf("failed to scan task: %w", err)
 		}
 		tasks = append(tasks, task)
+		totals[task.Horizon] = total
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating tasks: %w", err)
 	}
 
-	return tasks, nil
+	if err := r.loadDependencies(ctx, userID, tasks); err != nil {
+		return nil, err
+	}
+
+	return models.GroupTasksByHorizon(tasks, totals, limit), nil
+}
+
+// taskByHorizonQuery ranks the tasks matching filter within their horizon
+// and keeps the newest limit of each, along with the horizon's full count,
+// so the whole board is one query.
+func taskByHorizonQuery(userID uuid.UUID, filter models.TaskFilter, limit int) (string, []interface{}) {
+	where, args := taskFilterClause(userID, filter)
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`
+		SELECT `+taskColumns+`, horizon_total
+		FROM (
+			SELECT `+taskColumns+`,
+				ROW_NUMBER() OVER (PARTITION BY horizon ORDER BY created_at DESC, id) AS horizon_rank,
+				COUNT(*) OVER (PARTITION BY horizon) AS horizon_total
+			FROM tasks
+			WHERE %s
+		) ranked
+		WHERE horizon_rank <= $%d
+		ORDER BY horizon, created_at DESC, id`, where, len(args))
+
+	return query, args
 }
 
+// taskDependenciesQuery lists the dependencies of the user's tasks among $2
+// with each blocker's current status. Blockers in the trash are left out
+// until restored.
+const taskDependenciesQuery = `
+	SELECT d.task_id, d.blocked_by_id, b.status
+	FROM task_dependencies d
+	JOIN tasks b ON b.id = d.blocked_by_id AND b.deleted_at IS NULL
+	WHERE d.user_id = $1 AND d.task_id = ANY($2)
+	ORDER BY d.created_at, d.blocked_by_id
+`
+
+// loadDependencies fills in BlockedBy and IsBlocked on tasks.
+func (r *taskRepository) loadDependencies(ctx context.Context, userID uuid.UUID, tasks []models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+
+	rows, err := r.db.Reader(ctx).Query(ctx, taskDependenciesQuery, userID, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get task dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	var deps []models.TaskDependency
+	for rows.Next() {
+		var dep models.TaskDependency
+		if err := rows.Scan(&dep.TaskID, &dep.BlockedByID, &dep.BlockedByStatus); err != nil {
+			return fmt.Errorf("failed to scan task dependency: %w", err)
+		}
+		deps = append(deps, dep)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating task dependencies: %w", err)
+	}
+
+	models.ApplyDependencies(tasks, deps)
+	return nil
+}
+
+// replaceTaskDependencies makes task.BlockedBy the task's complete set of
+// blockers within tx. Dependency edits are serialized per user so two
+// concurrent edits can't together close a cycle.
+func replaceTaskDependencies(ctx context.Context, tx pgx.Tx, task *models.Task) error {
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtextextended($1::uuid::text, 0))`, task.UserID); err != nil {
+		return fmt.Errorf("failed to lock task dependencies: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `SELECT task_id, blocked_by_id FROM task_dependencies WHERE user_id = $1`, task.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get task dependencies: %w", err)
+	}
+
+	var edges []models.TaskDependency
+	for rows.Next() {
+		var edge models.TaskDependency
+		if err := rows.Scan(&edge.TaskID, &edge.BlockedByID); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan task dependency: %w", err)
+		}
+		edges = append(edges, edge)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating task dependencies: %w", err)
+	}
+
+	if models.DependencyCycle(edges, task.ID, task.BlockedBy) {
+		return models.ErrDependencyCycle
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM task_dependencies WHERE task_id = $1`, task.ID); err != nil {
+		return fmt.Errorf("failed to clear task dependencies: %w", err)
+	}
+
+	for _, blockerID := range task.BlockedBy {
+		result, err := tx.Exec(ctx, taskDependencyInsert, task.ID, blockerID, task.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to add task dependency: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return models.ErrUnknownBlocker
+		}
+	}
+
+	return nil
+}
+
+// taskDependencyInsert adds a dependency only if the blocker is one of the
+// user's own tasks.
+const taskDependencyInsert = `
+	INSERT INTO task_dependencies (task_id, blocked_by_id, user_id)
+	SELECT $1, id, user_id
+	FROM tasks
+	WHERE id = $2 AND user_id = $3 AND deleted_at IS NULL
+`
+
+// GetStatuses returns the status of each of ids that is one of the user's
+// tasks; others are left out.
+func (r *taskRepository) GetStatuses(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]string, error) {
+	statuses := make(map[uuid.UUID]string, len(ids))
+	if len(ids) == 0 {
+		return statuses, nil
+	}
+
+	rows, err := r.db.Reader(ctx).Query(ctx, `SELECT id, status FROM tasks WHERE user_id = $1 AND id = ANY($2) AND deleted_at IS NULL`, userID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task statuses: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		var status string
+		if err := rows.Scan(&id, &status); err != nil {
+			return nil, fmt.Errorf("failed to scan task status: %w", err)
+		}
+		statuses[id] = status
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating task statuses: %w", err)
+	}
+
+	return statuses, nil
+}
+
+// scanTasks reads rows selecting taskColumns and closes them. Rows that fail
+// to scan are handled as db.ScanErrors says.
+func scanTasks(db *Database, rows pgx.Rows) ([]models.Task, error) {
+	return scanList(db, rows, "task", func(row pgx.Rows, task *models.Task) error {
+		return row.Scan(taskScanTargets(task)...)
+	})
+}
+
+// taskScanTargets returns the fields of task matching taskColumns, in order.
+func taskScanTargets(task *models.Task) []any {
+	return []any{
+		&task.ID,
+		&task.UserID,
+		&task.Title,
+		&task.Description,
+		&task.Horizon,
+		&task.Priority,
+		&task.Status,
+		&task.Tags,
+		&task.DueDate,
+		&task.CompletedAt,
+		&task.SnoozeCount,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+	}
+}
+
+// taskFilterClause builds the WHERE clause for filter. All conditions are
+// ANDed. Tags match with array containment, so a task must carry every
+// requested tag; this stays a single-table scan served by the GIN index on
+// tags rather than a join per tag.
+func taskFilterClause(userID uuid.UUID, filter models.TaskFilter) (string, []interface{}) {
+	where := "user_id = $1 AND deleted_at IS NULL"
+	args := []interface{}{userID}
+	argCount := 1
+
+	if filter.Horizon != "" {
+		argCount++
+		where += fmt.Sprintf(" AND horizon = $%d", argCount)
+		args = append(args, filter.Horizon)
+	}
+
+	if filter.Status != "" {
+		argCount++
+		where += fmt.Sprintf(" AND status = $%d", argCount)
+		args = append(args, filter.Status)
+	}
+
+	if filter.Priority != "" {
+		argCount++
+		where += fmt.Sprintf(" AND priority = $%d", argCount)
+		args = append(args, filter.Priority)
+	}
+
+	if tags := models.NormalizeTags(filter.Tags); len(tags) > 0 {
+		argCount++
+		where += fmt.Sprintf(" AND tags @> $%d", argCount)
+		args = append(args, tags)
+	}
+
+	if filter.HasDueDate != nil {
+		if *filter.HasDueDate {
+			where += " AND due_date IS NOT NULL"
+		} else {
+			where += " AND due_date IS NULL"
+		}
+	}
+
+	return where, args
+}
+
+// taskCompletedAtClause stamps completed_at with the database clock when a
+// task is marked done without one.
+const taskCompletedAtClause = "completed_at = CASE WHEN $7 = 'done' THEN COALESCE($10, NOW()) ELSE $10 END"
+
+// Update saves task. A non-nil BlockedBy replaces the task's dependencies.
 func (r *taskRepository) Update(ctx context.Context, task *models.Task) error {
 	setClauses := []string{
 		"title = $3",
@@ -167,25 +471,25 @@ func (r *taskRepository) Update(ctx context.Context, task *models.Task) error {
 		"priority = $6",
 		"status = $7",
 		"due_date = $8",
-		"updated_at = $9",
-	}
-
-	if task.Status == "done" && task.CompletedAt == nil {
-		now := time.Now()
-		task.CompletedAt = &now
-		setClauses = append(setClauses, "completed_at = $10")
+		"tags = $9",
+		taskCompletedAtClause,
+		touchUpdatedAt,
 	}
 
 	query := fmt.Sprintf(`
 		UPDATE tasks
 		SET %s
-		WHERE id = $1 AND user_id = $2
-		RETURNING updated_at
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+		RETURNING completed_at, updated_at
 	`, strings.Join(setClauses, ", "))
 
-	task.UpdatedAt = time.Now()
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
 
-	err := r.db.Pool.QueryRow(
+	err = tx.QueryRow(
 		ctx,
 		query,
 		task.ID,
@@ -196,9 +500,9 @@ func (r *taskRepository) Update(ctx context.Context, task *models.Task) error {
 		task.Priority,
 		task.Status,
 		task.DueDate,
-		task.UpdatedAt,
+		models.NormalizeTags(task.Tags),
 		task.CompletedAt,
-	).Scan(&task.UpdatedAt)
+	).Scan(&task.CompletedAt, &task.UpdatedAt)
 
 	if err == pgx.ErrNoRows {
 		return models.ErrNotFound
@@ -208,13 +512,232 @@ func (r *taskRepository) Update(ctx context.Context, task *models.Task) error {
 		return fmt.Errorf("failed to update task: %w", err)
 	}
 
+	if task.BlockedBy != nil {
+		if err := replaceTaskDependencies(ctx, tx, task); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit task update: %w", err)
+	}
+
+	return nil
+}
+
+// Snooze persists task.DueDate and bumps the task's snooze counter.
+func (r *taskRepository) Snooze(ctx context.Context, task *models.Task) error {
+	query := `
+		UPDATE tasks
+		SET due_date = $3, snooze_count = snooze_count + 1, ` + touchUpdatedAt + `
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+		RETURNING snooze_count, updated_at
+	`
+
+	err := r.db.Writer().QueryRow(
+		ctx,
+		query,
+		task.ID,
+		task.UserID,
+		task.DueDate,
+	).Scan(&task.SnoozeCount, &task.UpdatedAt)
+
+	if err == pgx.ErrNoRows {
+		return models.ErrNotFound
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to snooze task: %w", err)
+	}
+
 	return nil
 }
 
+// GetDueWithin returns the user's open tasks due between now and within from
+// now, soonest first. Overdue, done and archived tasks are left out.
+func (r *taskRepository) GetDueWithin(ctx context.Context, userID uuid.UUID, within time.Duration) ([]models.Task, error) {
+	where, args := taskDueWithinClause(userID, time.Now(), within)
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE ` + where + `
+		ORDER BY due_date ASC, created_at ASC
+	`
+
+	rows, err := r.db.Reader(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks due within %s: %w", within, err)
+	}
+
+	tasks, err := scanTasks(r.db, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadDependencies(ctx, userID, tasks); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// GetDueBetween returns the user's tasks due in [from, to), soonest first.
+// Done tasks are included so a planner can show them ticked off; archived
+// ones are not.
+func (r *taskRepository) GetDueBetween(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE user_id = $1 AND deleted_at IS NULL AND status <> 'archived' AND due_date >= $2 AND due_date < $3
+		ORDER BY due_date ASC, created_at ASC
+	`
+
+	rows, err := r.db.Reader(ctx).Query(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks due between %s and %s: %w", from.Format(time.RFC3339), to.Format(time.RFC3339), err)
+	}
+
+	tasks, err := scanTasks(r.db, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadDependencies(ctx, userID, tasks); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// taskDueWithinClause selects open tasks whose due date falls in
+// [now, now+within].
+func taskDueWithinClause(userID uuid.UUID, now time.Time, within time.Duration) (string, []interface{}) {
+	where := "user_id = $1 AND deleted_at IS NULL AND status IN ('todo', 'in_progress') AND due_date >= $2 AND due_date <= $3"
+	return where, []interface{}{userID, now, now.Add(within)}
+}
+
+// taskCompletedSinceQuery lists completion times of the user's tasks since
+// $2. Archived tasks keep their completed_at and still count; tasks in the
+// trash do not.
+const taskCompletedSinceQuery = `
+	SELECT completed_at
+	FROM tasks
+	WHERE user_id = $1 AND deleted_at IS NULL AND completed_at >= $2
+	ORDER BY completed_at
+`
+
+// GetCompletedSince returns when each of the user's tasks completed since
+// since was completed, oldest first.
+func (r *taskRepository) GetCompletedSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]time.Time, error) {
+	rows, err := r.db.Reader(ctx).Query(ctx, taskCompletedSinceQuery, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task completions: %w", err)
+	}
+	defer rows.Close()
+
+	var completed []time.Time
+	for rows.Next() {
+		var at time.Time
+		if err := rows.Scan(&at); err != nil {
+			return nil, fmt.Errorf("failed to scan task completion: %w", err)
+		}
+		completed = append(completed, at)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating task completions: %w", err)
+	}
+
+	return completed, nil
+}
+
+// ArchiveCompleted moves the user's done tasks, optionally only those
+// completed before completedBefore, to archived in one statement and returns
+// the archived tasks.
+func (r *taskRepository) ArchiveCompleted(ctx context.Context, userID uuid.UUID, completedBefore *time.Time) ([]models.Task, error) {
+	where, args := taskArchiveCompletedClause(userID, completedBefore)
+	query := fmt.Sprintf(`
+		UPDATE tasks
+		SET status = 'archived', %s
+		WHERE %s
+		RETURNING `+taskColumns, touchUpdatedAt, where)
+
+	rows, err := r.db.Writer().Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive completed tasks: %w", err)
+	}
+
+	return scanTasks(r.db, rows)
+}
+
+// taskArchiveCompletedClause selects done tasks, completed strictly before
+// completedBefore when it is set.
+func taskArchiveCompletedClause(userID uuid.UUID, completedBefore *time.Time) (string, []interface{}) {
+	where := "user_id = $1 AND deleted_at IS NULL AND status = 'done'"
+	args := []interface{}{userID}
+
+	if completedBefore != nil {
+		where += " AND completed_at < $2"
+		args = append(args, *completedBefore)
+	}
+
+	return where, args
+}
+
+// MoveToGoal links every task in taskIDs to goalID, or unlinks them when
+// goalID is nil, in one transaction, and returns the moved tasks. taskIDs
+// must be distinct. If goalID isn't one of the user's goals nothing moves and
+// ErrUnknownGoal is returned; if any task isn't one of theirs, nothing moves
+// and ErrUnknownTask is returned.
+func (r *taskRepository) MoveToGoal(ctx context.Context, userID uuid.UUID, taskIDs []uuid.UUID, goalID *uuid.UUID) ([]models.Task, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin task move: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if goalID != nil {
+		var exists bool
+		err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM goals WHERE id = $1 AND user_id = $2 FOR SHARE)`, *goalID, userID).Scan(&exists)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check goal: %w", err)
+		}
+		if !exists {
+			return nil, models.ErrUnknownGoal
+		}
+	}
+
+	rows, err := tx.Query(ctx, `
+		UPDATE tasks
+		SET goal_id = $3, `+touchUpdatedAt+`
+		WHERE user_id = $1 AND id = ANY($2) AND deleted_at IS NULL
+		RETURNING `+taskColumns, userID, taskIDs, goalID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to move tasks: %w", err)
+	}
+
+	tasks, err := scanTasks(r.db, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if rows.CommandTag().RowsAffected() != int64(len(taskIDs)) {
+		return nil, models.ErrUnknownTask
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit task move: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// Delete moves the task to the trash, from which TrashRepository can restore
+// it until it is purged.
 func (r *taskRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
-	query := `DELETE FROM tasks WHERE id = $1 AND user_id = $2`
+	query := `UPDATE tasks SET deleted_at = NOW() WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`
 
-	result, err := r.db.Pool.Exec(ctx, query, id, userID)
+	result, err := r.db.Writer().Exec(ctx, query, id, userID)
 	if err != nil {
 		return fmt.Errorf("failed to delete task: %w", err)
 	}
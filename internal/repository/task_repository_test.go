@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskFilterClause_CombinesTagsWithOtherFilters(t *testing.T) {
+	userID := uuid.New()
+
+	where, args := taskFilterClause(userID, models.TaskFilter{
+		Status:   "todo",
+		Priority: "urgent",
+		Tags:     []string{"Work", "q4, work"},
+	})
+
+	assert.Equal(t, "user_id = $1 AND deleted_at IS NULL AND status = $2 AND priority = $3 AND tags @> $4", where)
+	assert.Equal(t, []interface{}{userID, "todo", "urgent", []string{"work", "q4"}}, args)
+}
+
+func TestTaskFilterClause_TagsOnly(t *testing.T) {
+	userID := uuid.New()
+
+	where, args := taskFilterClause(userID, models.TaskFilter{Tags: []string{"home"}})
+
+	assert.Equal(t, "user_id = $1 AND deleted_at IS NULL AND tags @> $2", where)
+	assert.Equal(t, []interface{}{userID, []string{"home"}}, args)
+}
+
+func TestTaskFilterClause_IgnoresBlankTags(t *testing.T) {
+	userID := uuid.New()
+
+	where, args := taskFilterClause(userID, models.TaskFilter{Horizon: "now", Tags: []string{"", " , "}})
+
+	assert.Equal(t, "user_id = $1 AND deleted_at IS NULL AND horizon = $2", where)
+	assert.Len(t, args, 2)
+}
+
+func TestTaskFilterClause_WithoutDueDate(t *testing.T) {
+	userID := uuid.New()
+	hasDueDate := false
+
+	where, args := taskFilterClause(userID, models.TaskFilter{HasDueDate: &hasDueDate})
+
+	assert.Equal(t, "user_id = $1 AND deleted_at IS NULL AND due_date IS NULL", where)
+	assert.Equal(t, []interface{}{userID}, args)
+}
+
+func TestTaskFilterClause_WithDueDate(t *testing.T) {
+	userID := uuid.New()
+	hasDueDate := true
+
+	where, _ := taskFilterClause(userID, models.TaskFilter{HasDueDate: &hasDueDate})
+
+	assert.Equal(t, "user_id = $1 AND deleted_at IS NULL AND due_date IS NOT NULL", where)
+}
+
+func TestTaskFilterClause_WithoutDueDateCombinesWithOtherFilters(t *testing.T) {
+	userID := uuid.New()
+	hasDueDate := false
+
+	where, args := taskFilterClause(userID, models.TaskFilter{
+		Status:     "todo",
+		Tags:       []string{"work"},
+		HasDueDate: &hasDueDate,
+	})
+
+	assert.Equal(t, "user_id = $1 AND deleted_at IS NULL AND status = $2 AND tags @> $3 AND due_date IS NULL", where)
+	assert.Equal(t, []interface{}{userID, "todo", []string{"work"}}, args)
+}
+
+func TestTaskDueWithinClause_OpenTasksInWindow(t *testing.T) {
+	userID := uuid.New()
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	where, args := taskDueWithinClause(userID, now, 72*time.Hour)
+
+	// Done and archived tasks are excluded by status, overdue ones by the
+	// lower bound.
+	assert.Equal(t, "user_id = $1 AND deleted_at IS NULL AND status IN ('todo', 'in_progress') AND due_date >= $2 AND due_date <= $3", where)
+	assert.Equal(t, []interface{}{userID, now, time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)}, args)
+}
+
+func TestTaskDueWithinClause_ZeroWindow(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	_, args := taskDueWithinClause(uuid.New(), now, 0)
+
+	assert.Equal(t, now, args[1])
+	assert.Equal(t, now, args[2])
+}
+
+func TestTaskArchiveCompletedClause_OnlyDoneTasks(t *testing.T) {
+	userID := uuid.New()
+
+	where, args := taskArchiveCompletedClause(userID, nil)
+
+	assert.Equal(t, "user_id = $1 AND deleted_at IS NULL AND status = 'done'", where)
+	assert.Equal(t, []interface{}{userID}, args)
+}
+
+func TestTaskArchiveCompletedClause_CompletedBefore(t *testing.T) {
+	userID := uuid.New()
+	cutoff := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+
+	where, args := taskArchiveCompletedClause(userID, &cutoff)
+
+	assert.Equal(t, "user_id = $1 AND deleted_at IS NULL AND status = 'done' AND completed_at < $2", where)
+	assert.Equal(t, []interface{}{userID, cutoff}, args)
+}
+
+func TestTaskCompletedSinceQuery_CountsArchivedCompletions(t *testing.T) {
+	// Filtering on completed_at rather than status keeps archived tasks.
+	assert.Contains(t, taskCompletedSinceQuery, "WHERE user_id = $1 AND deleted_at IS NULL AND completed_at >= $2")
+	assert.NotContains(t, taskCompletedSinceQuery, "status")
+}
+
+func TestTaskDependencyInsert_OnlyLinksOwnTasks(t *testing.T) {
+	assert.Contains(t, taskDependencyInsert, "FROM tasks")
+	assert.Contains(t, taskDependencyInsert, "WHERE id = $2 AND user_id = $3 AND deleted_at IS NULL")
+}
+
+func TestTaskDependenciesQuery_JoinsBlockerStatus(t *testing.T) {
+	assert.Contains(t, taskDependenciesQuery, "JOIN tasks b ON b.id = d.blocked_by_id AND b.deleted_at IS NULL")
+	assert.Contains(t, taskDependenciesQuery, "WHERE d.user_id = $1 AND d.task_id = ANY($2)")
+}
+
+func TestTaskCompletedAtClause_StampsFromDatabase(t *testing.T) {
+	assert.Contains(t, taskCompletedAtClause, "WHEN $7 = 'done' THEN COALESCE($10, NOW())")
+	assert.Contains(t, taskCompletedAtClause, "ELSE $10")
+}
+
+func TestTaskByHorizonQuery_CapsEachHorizonInOneQuery(t *testing.T) {
+	userID := uuid.New()
+
+	query, args := taskByHorizonQuery(userID, models.TaskFilter{Status: "todo"}, 5)
+
+	assert.Contains(t, query, "ROW_NUMBER() OVER (PARTITION BY horizon ORDER BY created_at DESC, id) AS horizon_rank")
+	assert.Contains(t, query, "COUNT(*) OVER (PARTITION BY horizon) AS horizon_total")
+	assert.Contains(t, query, "WHERE user_id = $1 AND deleted_at IS NULL AND status = $2")
+	assert.Contains(t, query, "WHERE horizon_rank <= $3")
+	assert.Equal(t, []interface{}{userID, "todo", 5}, args)
+}
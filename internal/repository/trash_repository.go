@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/lumen/backend/internal/models"
+	"github.com/lumen/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// TrashRepository reads and restores deleted tasks and habits. Deleting one
+// only stamps its deleted_at; every other query leaves such rows out.
+type TrashRepository interface {
+	List(ctx context.Context, userID uuid.UUID, since time.Time) ([]models.TrashItem, error)
+	RestoreTask(ctx context.Context, id, userID uuid.UUID, since time.Time) (*models.Task, error)
+	RestoreHabit(ctx context.Context, id, userID uuid.UUID, since time.Time) (*models.Habit, error)
+	Purge(ctx context.Context, before time.Time) (int, error)
+}
+
+type trashRepository struct {
+	db *Database
+}
+
+func NewTrashRepository(db *Database) TrashRepository {
+	return &trashRepository{db: db}
+}
+
+// trashListQuery lists the user's ($1) tasks and habits deleted at or after
+// $2, most recently deleted first.
+const trashListQuery = `
+	SELECT '` + models.TrashTypeTask + `' AS type, id, title, deleted_at
+	FROM tasks
+	WHERE user_id = $1 AND deleted_at >= $2
+	UNION ALL
+	SELECT '` + models.TrashTypeHabit + `', id, name, deleted_at
+	FROM habits
+	WHERE user_id = $1 AND deleted_at >= $2
+	ORDER BY deleted_at DESC, id`
+
+// List returns the user's tasks and habits deleted at or after since, most
+// recently deleted first. PurgeAt and RestoreURL are left for the caller.
+func (r *trashRepository) List(ctx context.Context, userID uuid.UUID, since time.Time) ([]models.TrashItem, error) {
+	rows, err := r.db.Reader(ctx).Query(ctx, trashListQuery, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trash: %w", err)
+	}
+	defer rows.Close()
+
+	items := []models.TrashItem{}
+	for rows.Next() {
+		var item models.TrashItem
+		if err := rows.Scan(&item.Type, &item.ID, &item.Title, &item.DeletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan trash item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating trash: %w", err)
+	}
+
+	return items, nil
+}
+
+// RestoreTask takes the user's task out of the trash if it was deleted at
+// or after since, and returns it. ErrNotFound means there is no such task in
+// the trash.
+func (r *trashRepository) RestoreTask(ctx context.Context, id, userID uuid.UUID, since time.Time) (*models.Task, error) {
+	query := `
+		UPDATE tasks
+		SET deleted_at = NULL, ` + touchUpdatedAt + `
+		WHERE id = $1 AND user_id = $2 AND deleted_at >= $3
+		RETURNING ` + taskColumns
+
+	rows, err := r.db.Writer().Query(ctx, query, id, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore task: %w", err)
+	}
+
+	tasks, err := scanTasks(r.db, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tasks) == 0 {
+		return nil, models.ErrNotFound
+	}
+
+	if err := (&taskRepository{db: r.db}).loadDependencies(ctx, userID, tasks); err != nil {
+		return nil, err
+	}
+
+	return &tasks[0], nil
+}
+
+// RestoreHabit takes the user's habit out of the trash if it was deleted at
+// or after since, and returns it with its completions and streak freezes
+// intact. ErrNotFound means there is no such habit in the trash.
+func (r *trashRepository) RestoreHabit(ctx context.Context, id, userID uuid.UUID, since time.Time) (*models.Habit, error) {
+	query := `
+		UPDATE habits
+		SET deleted_at = NULL, ` + touchUpdatedAt + `
+		WHERE id = $1 AND user_id = $2 AND deleted_at >= $3
+		RETURNING ` + habitColumns
+
+	var habit models.Habit
+	err := r.db.Writer().QueryRow(ctx, query, id, userID, since).Scan(habitScanTargets(&habit)...)
+
+	if err == pgx.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore habit: %w", err)
+	}
+
+	return &habit, nil
+}
+
+// trashPurgeQueries delete, for good, tasks and habits deleted before $1.
+// Their dependencies, completions and other history cascade with them.
+var trashPurgeQueries = []string{
+	`DELETE FROM tasks WHERE deleted_at < $1`,
+	`DELETE FROM habits WHERE deleted_at < $1`,
+}
+
+// Purge permanently deletes every user's tasks and habits deleted before
+// before, returning how many went.
+func (r *trashRepository) Purge(ctx context.Context, before time.Time) (int, error) {
+	purged := 0
+	for _, query := range trashPurgeQueries {
+		result, err := r.db.Writer().Exec(ctx, query, before)
+		if err != nil {
+			return purged, fmt.Errorf("failed to purge trash: %w", err)
+		}
+		purged += int(result.RowsAffected())
+	}
+
+	return purged, nil
+}
+
+// RunTrashPurge purges tasks and habits deleted more than retention ago,
+// once every interval, until ctx is cancelled. A failed purge is logged and
+// retried on the next tick.
+func RunTrashPurge(ctx context.Context, trash TrashRepository, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := trash.Purge(ctx, time.Now().Add(-retention))
+			if err != nil {
+				logger.Error("Trash purge failed", zap.Error(err))
+				continue
+			}
+			if purged > 0 {
+				logger.Info("Trash purged", zap.Int("purged", purged))
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrashListQuery_OnlyOwnDeletedItemsInRetention(t *testing.T) {
+	// Live rows have a NULL deleted_at and fail the comparison, so each
+	// branch returns only the user's items deleted within the window.
+	assert.Equal(t, 2, strings.Count(trashListQuery, "WHERE user_id = $1 AND deleted_at >= $2"))
+	assert.Contains(t, trashListQuery, "'task' AS type, id, title, deleted_at\n\tFROM tasks")
+	assert.Contains(t, trashListQuery, "'habit', id, name, deleted_at\n\tFROM habits")
+	assert.Contains(t, trashListQuery, "ORDER BY deleted_at DESC, id")
+}
+
+func TestTrashPurgeQueries_OnlyPastRetention(t *testing.T) {
+	assert.Equal(t, []string{
+		`DELETE FROM tasks WHERE deleted_at < $1`,
+		`DELETE FROM habits WHERE deleted_at < $1`,
+	}, trashPurgeQueries)
+}
+
+func TestLiveQueries_LeaveOutTrash(t *testing.T) {
+	where, _ := taskFilterClause(uuid.New(), models.TaskFilter{})
+	assert.Equal(t, "user_id = $1 AND deleted_at IS NULL", where)
+
+	where, _ = taskDueWithinClause(uuid.New(), time.Now(), time.Hour)
+	assert.Contains(t, where, "deleted_at IS NULL")
+
+	assert.Contains(t, activityFeedSource, "completed_at IS NOT NULL AND deleted_at IS NULL")
+	assert.Contains(t, activityFeedSource, "JOIN habits h ON h.id = hc.habit_id AND h.deleted_at IS NULL")
+}
+
+// latestTrigger returns the body of the last CREATE TRIGGER for name across
+// the migrations, in the order they are applied.
+func latestTrigger(t *testing.T, name string) string {
+	t.Helper()
+
+	files, err := filepath.Glob("../../supabase/migrations/*.sql")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, files)
+
+	create := regexp.MustCompile(`(?s)CREATE TRIGGER ` + name + `\s+(.*?);`)
+	var latest string
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		assert.NoError(t, err)
+		for _, match := range create.FindAllSubmatch(src, -1) {
+			latest = string(match[1])
+		}
+	}
+	return latest
+}
+
+func TestTrash_SoftDeleteAndRestoreInvalidateDailyStats(t *testing.T) {
+	// Trashing and restoring only set deleted_at, so the triggers must
+	// watch it for stored days to be dropped like the live query's results.
+	tasks := latestTrigger(t, "tasks_invalidate_daily_stats")
+	assert.Contains(t, tasks, "UPDATE OF due_date, completed_at, deleted_at")
+	assert.Contains(t, tasks, "DELETE ON tasks")
+	assert.Contains(t, tasks, "invalidate_daily_stats_for_task()")
+
+	habits := latestTrigger(t, "habits_invalidate_daily_stats")
+	assert.Contains(t, habits, "UPDATE OF is_active, deleted_at")
+	assert.Contains(t, habits, "DELETE ON habits")
+	assert.Contains(t, habits, "invalidate_daily_stats_for_habit()")
+}
+
+type recordingTrash struct {
+	TrashRepository
+	mu      sync.Mutex
+	befores []time.Time
+}
+
+func (r *recordingTrash) Purge(ctx context.Context, before time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.befores = append(r.befores, before)
+	return 1, nil
+}
+
+func (r *recordingTrash) calls() []time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]time.Time(nil), r.befores...)
+}
+
+func TestRunTrashPurge_PurgesPastRetentionEachTick(t *testing.T) {
+	trash := &recordingTrash{}
+	retention := 30 * 24 * time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		RunTrashPurge(ctx, trash, retention, 5*time.Millisecond)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return len(trash.calls()) >= 2 }, time.Second, time.Millisecond)
+	cancel()
+	<-done
+
+	for _, before := range trash.calls() {
+		assert.WithinDuration(t, time.Now().Add(-retention), before, time.Second)
+	}
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/lumen/backend/internal/models"
+)
+
+type WebhookRepository interface {
+	GetByID(ctx context.Context, id, userID uuid.UUID) (*models.Webhook, error)
+	GetDelivery(ctx context.Context, id, webhookID uuid.UUID) (*models.WebhookDelivery, error)
+	RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery) error
+	RotateSecret(ctx context.Context, id, userID uuid.UUID, secret string) (*models.Webhook, error)
+}
+
+type webhookRepository struct {
+	db *Database
+}
+
+func NewWebhookRepository(db *Database) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) GetByID(ctx context.Context, id, userID uuid.UUID) (*models.Webhook, error) {
+	query := `
+		SELECT ` + webhookColumns + `
+		FROM webhooks
+		WHERE id = $1 AND user_id = $2
+	`
+
+	webhook, err := scanWebhook(r.db.Reader(ctx).QueryRow(ctx, query, id, userID))
+	if err == pgx.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// RotateSecret replaces the webhook's signing secret and bumps its version.
+// Deliveries are signed when sent, so failed ones retried from now on carry
+// signatures under the new secret.
+func (r *webhookRepository) RotateSecret(ctx context.Context, id, userID uuid.UUID, secret string) (*models.Webhook, error) {
+	query := `
+		UPDATE webhooks
+		SET secret = $3, secret_version = secret_version + 1, ` + touchUpdatedAt + `
+		WHERE id = $1 AND user_id = $2
+		RETURNING ` + webhookColumns
+
+	webhook, err := scanWebhook(r.db.Writer().QueryRow(ctx, query, id, userID, secret))
+	if err == pgx.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate webhook secret: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// webhookColumns is the column list scanWebhook expects.
+const webhookColumns = `id, user_id, url, secret, secret_version, is_active, created_at, updated_at`
+
+func scanWebhook(row pgx.Row) (*models.Webhook, error) {
+	var webhook models.Webhook
+	err := row.Scan(
+		&webhook.ID,
+		&webhook.UserID,
+		&webhook.URL,
+		&webhook.Secret,
+		&webhook.SecretVersion,
+		&webhook.IsActive,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// GetDelivery loads a delivery of webhookID. Callers resolve the webhook with
+// GetByID first, which is what scopes the lookup to the user.
+func (r *webhookRepository) GetDelivery(ctx context.Context, id, webhookID uuid.UUID) (*models.WebhookDelivery, error) {
+	query := `
+		SELECT id, webhook_id, user_id, event, payload, status, attempts,
+		       last_response_status, last_error, last_attempt_at, secret_version, created_at
+		FROM webhook_deliveries
+		WHERE id = $1 AND webhook_id = $2
+	`
+
+	var delivery models.WebhookDelivery
+	err := r.db.Reader(ctx).QueryRow(ctx, query, id, webhookID).Scan(
+		&delivery.ID,
+		&delivery.WebhookID,
+		&delivery.UserID,
+		&delivery.Event,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.LastResponseStatus,
+		&delivery.LastError,
+		&delivery.LastAttemptAt,
+		&delivery.SecretVersion,
+		&delivery.CreatedAt,
+	)
+
+	if err == pgx.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	return &delivery, nil
+}
+
+// RecordAttempt persists the outcome of the delivery's latest attempt.
+func (r *webhookRepository) RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, last_response_status = $4, last_error = $5, last_attempt_at = $6,
+		    secret_version = $7
+		WHERE id = $1
+	`
+
+	result, err := r.db.Writer().Exec(
+		ctx,
+		query,
+		delivery.ID,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.LastResponseStatus,
+		delivery.LastError,
+		delivery.LastAttemptAt,
+		delivery.SecretVersion,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook delivery attempt: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
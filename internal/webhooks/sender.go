@@ -0,0 +1,94 @@
+// Package webhooks sends signed event payloads to user-registered endpoints.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/lumen/backend/internal/integrations"
+	"github.com/lumen/backend/internal/models"
+)
+
+// Headers set on every delivery. Receivers verify SignatureHeader, which
+// carries "sha256=<hex hmac of the body keyed by the webhook secret>";
+// SignatureVersionHeader names the version of the secret used, so a receiver
+// mid-rotation knows which one to check against.
+const (
+	SignatureHeader        = "X-Lumen-Signature"
+	SignatureVersionHeader = "X-Lumen-Signature-Version"
+	EventHeader            = "X-Lumen-Event"
+	DeliveryHeader         = "X-Lumen-Delivery"
+)
+
+// Sender posts deliveries to their webhook's URL, each call bounded by the
+// webhook integration timeout.
+type Sender struct {
+	client   *http.Client
+	timeouts integrations.Timeouts
+}
+
+func NewSender(timeouts integrations.Timeouts) *Sender {
+	return &Sender{client: &http.Client{}, timeouts: timeouts}
+}
+
+// NewSecret returns a random signing secret.
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Sign returns the SignatureHeader value for body under secret.
+func Sign(secret string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(body)
+	return "sha256=" + hex.EncodeToString(h.Sum(nil))
+}
+
+// Send makes one delivery attempt and returns the response status (0 if none
+// was received). Any non-2xx response is reported as an error. The payload is
+// signed afresh with the webhook's current secret on every attempt, so a
+// retry after rotation verifies under the new secret.
+func (s *Sender) Send(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) (int, error) {
+	// Call may give up on fn at the deadline while it is still running, so
+	// the status is handed back atomically.
+	var statusCode atomic.Int64
+
+	err := s.timeouts.Call(ctx, integrations.Webhook, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+		if err != nil {
+			return fmt.Errorf("failed to build webhook request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(SignatureHeader, Sign(webhook.Secret, delivery.Payload))
+		req.Header.Set(SignatureVersionHeader, strconv.Itoa(webhook.SecretVersion))
+		req.Header.Set(EventHeader, delivery.Event)
+		req.Header.Set(DeliveryHeader, delivery.ID.String())
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("webhook request failed: %w", err)
+		}
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+		statusCode.Store(int64(resp.StatusCode))
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("webhook endpoint responded with status %d", resp.StatusCode)
+		}
+		return nil
+	})
+
+	return int(statusCode.Load()), err
+}
@@ -0,0 +1,78 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lumen/backend/internal/integrations"
+	"github.com/lumen/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func testDelivery() *models.WebhookDelivery {
+	return &models.WebhookDelivery{ID: uuid.New(), Event: "habit.completed", Payload: json.RawMessage(`{"ok":true}`)}
+}
+
+func TestSend_SignsPayload(t *testing.T) {
+	delivery := testDelivery()
+	var headers http.Header
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer endpoint.Close()
+
+	sender := NewSender(integrations.Timeouts{Webhook: time.Second})
+	status, err := sender.Send(context.Background(), &models.Webhook{URL: endpoint.URL, Secret: "whsec", SecretVersion: 3}, delivery)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, status)
+	assert.Equal(t, Sign("whsec", delivery.Payload), headers.Get(SignatureHeader))
+	assert.Equal(t, "3", headers.Get(SignatureVersionHeader))
+	assert.Equal(t, "habit.completed", headers.Get(EventHeader))
+	assert.Equal(t, delivery.ID.String(), headers.Get(DeliveryHeader))
+}
+
+func TestSend_Non2xxIsError(t *testing.T) {
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer endpoint.Close()
+
+	status, err := NewSender(integrations.Timeouts{}).Send(context.Background(), &models.Webhook{URL: endpoint.URL}, testDelivery())
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, status)
+}
+
+func TestSend_TimesOutAtWebhookBudget(t *testing.T) {
+	release := make(chan struct{})
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer endpoint.Close()
+	defer close(release)
+
+	sender := NewSender(integrations.Timeouts{Webhook: 20 * time.Millisecond})
+	status, err := sender.Send(context.Background(), &models.Webhook{URL: endpoint.URL}, testDelivery())
+
+	var timeoutErr *integrations.TimeoutError
+	assert.True(t, errors.As(err, &timeoutErr))
+	assert.Equal(t, 0, status)
+}
+
+func TestNewSecret_IsRandom(t *testing.T) {
+	a, err := NewSecret()
+	assert.NoError(t, err)
+	b, err := NewSecret()
+	assert.NoError(t, err)
+
+	assert.Regexp(t, `^whsec_[0-9a-f]{64}$`, a)
+	assert.NotEqual(t, a, b)
+}
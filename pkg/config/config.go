@@ -14,11 +14,66 @@ type Config struct {
 	AppPort string
 	AppName string
 
+	// Server
+	MaxHeaderBytes int
+	MaxJSONDepth   int
+	// Reject JSON bodies carrying fields the endpoint doesn't define
+	StrictJSON bool
+	// lenient or strict; empty picks strict in production, lenient elsewhere
+	ValidationProfile string
+	// Request bodies not fully received within BodyReadTimeout get 408;
+	// slower than SlowBodyThreshold are logged (0 disables either)
+	BodyReadTimeout   time.Duration
+	SlowBodyThreshold time.Duration
+	// Report a per-route histogram of request body sizes on /metrics
+	BodySizeMetrics bool
+	// Header carrying request IDs, and how missing ones are generated
+	// (uuid4, uuid7 or trace)
+	RequestIDHeader   string
+	RequestIDStrategy string
+	// Requests processed at once before shedding with 503, and per caller
+	// before rejecting with 429 (0 = unlimited)
+	MaxConcurrentRequests        int
+	MaxConcurrentRequestsPerUser int
+	// Read-only maintenance mode at boot; toggled at runtime via the admin API
+	MaintenanceMode    bool
+	MaintenanceMessage string
+
+	// Uploads (per-route multipart limits, in bytes)
+	UploadMaxMemory     int64
+	AvatarMaxUploadSize int64
+	ImportMaxUploadSize int64
+	TaskImportMaxRows   int
+
+	// Tasks: due dates more than this many years ahead are rejected (0 = no limit)
+	TaskMaxDueDateYears int
+	// Tasks: blocked tasks can't be moved to in_progress or done
+	TaskEnforceDependencies bool
+	// Tags per resource and characters per tag, after normalization (0 = no limit)
+	MaxTagsPerResource int
+	MaxTagLength       int
+
 	// Database
 	DatabaseURL         string
+	// Optional read replica; read-only queries go there when set
+	DatabaseReplicaURL  string
 	DBMaxOpenConns      int
 	DBMaxIdleConns      int
 	DBConnMaxLifetime   time.Duration
+	// How often pool stats are logged (0 = never)
+	DBStatsInterval time.Duration
+	// How long startup waits to open the pool's minimum connections (0 = skip)
+	DBWarmUpTimeout time.Duration
+	// fail or skip rows that fail to scan in list queries; empty picks skip
+	// in production, fail elsewhere
+	DBScanErrors string
+	// Tables /ready queries to prove the schema is usable (empty = ping only)
+	ReadinessCheckTables []string
+	// Bearer token required for /metrics and /health/details (empty = public)
+	HealthAuthToken string
+	// Count DB queries per request for N+1 detection (development only)
+	DBQueryCount       bool
+	DBQueryCountHeader bool
 
 	// Redis
 	RedisURL      string
@@ -29,31 +84,110 @@ type Config struct {
 	JWTSecret           string
 	JWTExpiry           time.Duration
 	RefreshTokenExpiry  time.Duration
+	// Expected iss and aud claims of user tokens (empty = not checked)
+	JWTIssuer           string
+	JWTAudience         string
+	// Signs pagination cursors (falls back to JWTSecret)
+	CursorSecret        string
+
+	// Notes encryption at rest: "id:base64key" AES keys, and the ID new
+	// notes are encrypted with (empty = written as plaintext)
+	NotesEncryptionKeys  []string
+	NotesEncryptionKeyID string
 
 	// Supabase
 	SupabaseURL        string
 	SupabaseServiceKey string
 	SupabaseJWTSecret  string
 
+	// External integration timeouts (per call)
+	SupabaseJWKSTimeout time.Duration
+	WebhookTimeout      time.Duration
+	SMTPTimeout         time.Duration
+
 	// CORS
 	CORSAllowedOrigins []string
 	CORSAllowedMethods []string
 	CORSAllowedHeaders []string
+	// Narrower method sets for route groups ("prefix=GET|HEAD")
+	CORSMethodOverrides []string
+
+	// Calendar feed (served cross-origin, authenticated by URL token)
+	CalendarFeedAllowedOrigins []string
+	CalendarFeedSecret         string
+
+	// API Versioning
+	APIDeprecatedVersions []string
+	APISunsetDate         time.Time
 
 	// Logging
 	LogLevel  string
 	LogFormat string
 	LogOutput string
+	// Level for the log line written when a client disconnects mid-request
+	ClientClosedLogLevel string
 
 	// Rate Limiting
 	RateLimitRequests int
 	RateLimitWindow   time.Duration
 	RateLimitEnabled  bool
+	RateLimitBackend  string
+	// Share of the limit, in percent, after which responses carry
+	// X-RateLimit-Warning (0 disables)
+	RateLimitWarnPercent int
+	// How often to retry Redis while rate limiting runs in degraded mode
+	RateLimitReconnectInterval time.Duration
+	// How often the in-memory limiter drops idle callers, and how many
+	// callers it tracks before evicting the least recently seen (0 = no cap)
+	RateLimitCleanupInterval time.Duration
+	RateLimitMaxKeys         int
+	// Internal service keys ("name:key") that bypass rate limiting
+	ServiceAPIKeys []string
+
+	// Analytics collector (used when EnableAnalytics is set)
+	AnalyticsURL              string
+	AnalyticsBufferSize       int
+	AnalyticsTimeout          time.Duration
+	AnalyticsFailureThreshold int
+	AnalyticsBreakerCooldown  time.Duration
+
+	// Reminders scheduler (used when EnableReminders is set)
+	ReminderInterval    time.Duration
+	ReminderTickTimeout time.Duration
+	ReminderBatchSize   int
+	ReminderConcurrency int
+	// Acknowledging a reminder within this long counts as acting on it
+	ReminderAckWindow time.Duration
+
+	// Habit completions within this long of the previous one are duplicates
+	// (0 disables); HabitCompletionDuplicates is "ignore" or "reject"
+	HabitCompletionDebounce   time.Duration
+	HabitCompletionDuplicates string
+	// Default page size of a habit's completion history
+	HabitCompletionPageSize int
+
+	// Nightly daily stats precompute (used when EnableDailyStats is set)
+	DailyStatsHour          int
+	DailyStatsRecomputeDays int
+	DailyStatsBatchSize     int
+
+	// Deleted tasks and habits stay in the trash for TrashRetention; a purge
+	// every TrashPurgeInterval deletes older ones for good (0 disables it)
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
+
+	// Per-subscriber queue of the in-process event bus
+	EventBusBufferSize int
 
 	// Feature Flags
-	EnableAnalytics bool
-	EnableDebug     bool
-	EnableProfiling bool
+	EnableAnalytics  bool
+	EnableDebug      bool
+	EnableProfiling  bool
+	EnableReminders  bool
+	EnableDailyStats bool
+	// Per-user feature flag defaults ("name:true"/"name:false"); users can be
+	// given overrides through the admin API
+	FeatureFlags []string
 }
 
 // Load reads configuration from environment variables
@@ -64,11 +198,50 @@ func Load() *Config {
 		AppPort: getEnv("APP_PORT", "8080"),
 		AppName: getEnv("APP_NAME", "lumen-backend"),
 
+		// Server
+		MaxHeaderBytes: getEnvAsInt("MAX_HEADER_BYTES", 1<<20),
+		MaxJSONDepth:   getEnvAsInt("MAX_JSON_DEPTH", 32),
+		StrictJSON:     getEnvAsBool("STRICT_JSON", false),
+
+		ValidationProfile: getEnv("VALIDATION_PROFILE", ""),
+
+		BodyReadTimeout:   getEnvAsDuration("BODY_READ_TIMEOUT", 8*time.Second),
+		SlowBodyThreshold: getEnvAsDuration("SLOW_BODY_THRESHOLD", 2*time.Second),
+		BodySizeMetrics:   getEnvAsBool("BODY_SIZE_METRICS", true),
+		RequestIDHeader:   getEnv("REQUEST_ID_HEADER", "X-Request-ID"),
+		RequestIDStrategy: getEnv("REQUEST_ID_STRATEGY", "uuid4"),
+
+		MaxConcurrentRequests:        getEnvAsInt("MAX_CONCURRENT_REQUESTS", 0),
+		MaxConcurrentRequestsPerUser: getEnvAsInt("MAX_CONCURRENT_REQUESTS_PER_USER", 0),
+
+		MaintenanceMode:    getEnvAsBool("MAINTENANCE_MODE", false),
+		MaintenanceMessage: getEnv("MAINTENANCE_MESSAGE", "The API is in read-only mode for maintenance. Please try again shortly."),
+
+		// Uploads
+		UploadMaxMemory:     getEnvAsInt64("UPLOAD_MAX_MEMORY", 4<<20),
+		AvatarMaxUploadSize: getEnvAsInt64("AVATAR_MAX_UPLOAD_SIZE", 2<<20),
+		ImportMaxUploadSize: getEnvAsInt64("IMPORT_MAX_UPLOAD_SIZE", 10<<20),
+		TaskImportMaxRows:   getEnvAsInt("TASK_IMPORT_MAX_ROWS", 1000),
+
+		TaskMaxDueDateYears:     getEnvAsInt("TASK_MAX_DUE_DATE_YEARS", 10),
+		TaskEnforceDependencies: getEnvAsBool("TASK_ENFORCE_DEPENDENCIES", true),
+		MaxTagsPerResource:      getEnvAsInt("MAX_TAGS_PER_RESOURCE", 20),
+		MaxTagLength:            getEnvAsInt("MAX_TAG_LENGTH", 50),
+
 		// Database
 		DatabaseURL:        getEnv("DATABASE_URL", ""),
+		DatabaseReplicaURL: getEnv("DATABASE_REPLICA_URL", ""),
 		DBMaxOpenConns:     getEnvAsInt("DB_MAX_OPEN_CONNS", 25),
 		DBMaxIdleConns:     getEnvAsInt("DB_MAX_IDLE_CONNS", 5),
 		DBConnMaxLifetime:  getEnvAsDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute),
+		DBStatsInterval:    getEnvAsDuration("DB_STATS_INTERVAL", time.Minute),
+		DBWarmUpTimeout:    getEnvAsDuration("DB_WARMUP_TIMEOUT", 5*time.Second),
+		DBScanErrors:       getEnv("DB_SCAN_ERRORS", ""),
+
+		ReadinessCheckTables: getEnvAsSlice("READINESS_CHECK_TABLES", []string{"tasks", "habits", "daily_logs"}),
+		HealthAuthToken:      getEnv("HEALTH_AUTH_TOKEN", ""),
+		DBQueryCount:         getEnvAsBool("DB_QUERY_COUNT", false),
+		DBQueryCountHeader:   getEnvAsBool("DB_QUERY_COUNT_HEADER", false),
 
 		// Redis
 		RedisURL:      getEnv("REDIS_URL", "redis://localhost:6379"),
@@ -79,31 +252,95 @@ func Load() *Config {
 		JWTSecret:          getEnv("JWT_SECRET", ""),
 		JWTExpiry:          getEnvAsDuration("JWT_EXPIRY", 24*time.Hour),
 		RefreshTokenExpiry: getEnvAsDuration("REFRESH_TOKEN_EXPIRY", 168*time.Hour),
+		JWTIssuer:          getEnv("JWT_ISSUER", ""),
+		JWTAudience:        getEnv("JWT_AUDIENCE", ""),
+		CursorSecret:       getEnv("CURSOR_SECRET", ""),
+
+		NotesEncryptionKeys:  getEnvAsSlice("NOTES_ENCRYPTION_KEYS", []string{}),
+		NotesEncryptionKeyID: getEnv("NOTES_ENCRYPTION_KEY_ID", ""),
 
 		// Supabase
 		SupabaseURL:        getEnv("SUPABASE_URL", ""),
 		SupabaseServiceKey: getEnv("SUPABASE_SERVICE_KEY", ""),
 		SupabaseJWTSecret:  getEnv("SUPABASE_JWT_SECRET", ""),
 
+		// External integration timeouts
+		SupabaseJWKSTimeout: getEnvAsDuration("SUPABASE_JWKS_TIMEOUT", 5*time.Second),
+		WebhookTimeout:      getEnvAsDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		SMTPTimeout:         getEnvAsDuration("SMTP_TIMEOUT", 15*time.Second),
+
 		// CORS
 		CORSAllowedOrigins: getEnvAsSlice("CORS_ALLOWED_ORIGINS", []string{"http://localhost:3000"}),
 		CORSAllowedMethods: getEnvAsSlice("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}),
 		CORSAllowedHeaders: getEnvAsSlice("CORS_ALLOWED_HEADERS", []string{"Origin", "Content-Type", "Authorization"}),
 
+		CORSMethodOverrides: getEnvAsSlice("CORS_METHOD_OVERRIDES", []string{"/api/v1/calendar=GET", "/api/v1/calendar/feed-url/rotate=POST"}),
+
+		// Calendar feed
+		CalendarFeedAllowedOrigins: getEnvAsSlice("CALENDAR_FEED_ALLOWED_ORIGINS", []string{}),
+		CalendarFeedSecret:         getEnv("CALENDAR_FEED_SECRET", ""),
+
+		// API Versioning
+		APIDeprecatedVersions: getEnvAsSlice("API_DEPRECATED_VERSIONS", []string{}),
+		APISunsetDate:         getEnvAsDate("API_SUNSET_DATE", time.Time{}),
+
 		// Logging
 		LogLevel:  getEnv("LOG_LEVEL", "debug"),
 		LogFormat: getEnv("LOG_FORMAT", "json"),
 		LogOutput: getEnv("LOG_OUTPUT", "stdout"),
 
+		ClientClosedLogLevel: getEnv("CLIENT_CLOSED_LOG_LEVEL", "info"),
+
 		// Rate Limiting
 		RateLimitRequests: getEnvAsInt("RATE_LIMIT_REQUESTS", 100),
 		RateLimitWindow:   getEnvAsDuration("RATE_LIMIT_WINDOW", 60*time.Second),
 		RateLimitEnabled:  getEnvAsBool("RATE_LIMIT_ENABLED", true),
+		RateLimitBackend:  getEnv("RATE_LIMIT_BACKEND", "memory"),
+
+		RateLimitWarnPercent:       getEnvAsInt("RATE_LIMIT_WARN_PERCENT", 90),
+		RateLimitReconnectInterval: getEnvAsDuration("RATE_LIMIT_RECONNECT_INTERVAL", 15*time.Second),
+		RateLimitCleanupInterval:   getEnvAsDuration("RATE_LIMIT_CLEANUP_INTERVAL", time.Minute),
+		RateLimitMaxKeys:           getEnvAsInt("RATE_LIMIT_MAX_KEYS", 100000),
+		ServiceAPIKeys:             getEnvAsSlice("SERVICE_API_KEYS", []string{}),
+
+		// Analytics
+		AnalyticsURL:              getEnv("ANALYTICS_URL", ""),
+		AnalyticsBufferSize:       getEnvAsInt("ANALYTICS_BUFFER_SIZE", 1000),
+		AnalyticsTimeout:          getEnvAsDuration("ANALYTICS_TIMEOUT", 2*time.Second),
+		AnalyticsFailureThreshold: getEnvAsInt("ANALYTICS_FAILURE_THRESHOLD", 5),
+		AnalyticsBreakerCooldown:  getEnvAsDuration("ANALYTICS_BREAKER_COOLDOWN", time.Minute),
+
+		// Reminders
+		ReminderInterval:    getEnvAsDuration("REMINDER_INTERVAL", time.Minute),
+		ReminderTickTimeout: getEnvAsDuration("REMINDER_TICK_TIMEOUT", 50*time.Second),
+		ReminderBatchSize:   getEnvAsInt("REMINDER_BATCH_SIZE", 500),
+		ReminderConcurrency: getEnvAsInt("REMINDER_CONCURRENCY", 8),
+		ReminderAckWindow:   getEnvAsDuration("REMINDER_ACK_WINDOW", 2*time.Hour),
+
+		// Habit completions
+		HabitCompletionDebounce:   getEnvAsDuration("HABIT_COMPLETION_DEBOUNCE", 2*time.Second),
+		HabitCompletionDuplicates: getEnv("HABIT_COMPLETION_DUPLICATES", "ignore"),
+		HabitCompletionPageSize:   getEnvAsInt("HABIT_COMPLETION_PAGE_SIZE", 50),
+
+		// Daily stats
+		DailyStatsHour:          getEnvAsInt("DAILY_STATS_HOUR", 3),
+		DailyStatsRecomputeDays: getEnvAsInt("DAILY_STATS_RECOMPUTE_DAYS", 7),
+		DailyStatsBatchSize:     getEnvAsInt("DAILY_STATS_BATCH_SIZE", 500),
+
+		// Trash
+		TrashRetention:     getEnvAsDuration("TRASH_RETENTION", 30*24*time.Hour),
+		TrashPurgeInterval: getEnvAsDuration("TRASH_PURGE_INTERVAL", time.Hour),
+
+		// Event bus
+		EventBusBufferSize: getEnvAsInt("EVENT_BUS_BUFFER_SIZE", 256),
 
 		// Feature Flags
-		EnableAnalytics: getEnvAsBool("ENABLE_ANALYTICS", false),
-		EnableDebug:     getEnvAsBool("ENABLE_DEBUG", false),
-		EnableProfiling: getEnvAsBool("ENABLE_PROFILING", false),
+		EnableAnalytics:  getEnvAsBool("ENABLE_ANALYTICS", false),
+		EnableDebug:      getEnvAsBool("ENABLE_DEBUG", false),
+		EnableProfiling:  getEnvAsBool("ENABLE_PROFILING", false),
+		EnableReminders:  getEnvAsBool("ENABLE_REMINDERS", false),
+		EnableDailyStats: getEnvAsBool("ENABLE_DAILY_STATS", false),
+		FeatureFlags:     getEnvAsSlice("FEATURE_FLAGS", []string{}),
 	}
 }
 
@@ -128,6 +365,18 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return value
 }
 
+func getEnvAsInt64(key string, defaultValue int64) int64 {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	var value int64
+	if _, err := fmt.Sscanf(valueStr, "%d", &value); err != nil {
+		return defaultValue
+	}
+	return value
+}
+
 func getEnvAsBool(key string, defaultValue bool) bool {
 	valueStr := os.Getenv(key)
 	if valueStr == "" {
@@ -148,6 +397,18 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	return value
 }
 
+func getEnvAsDate(key string, defaultValue time.Time) time.Time {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := time.Parse("2006-01-02", valueStr)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
 func getEnvAsSlice(key string, defaultValue []string) []string {
 	valueStr := os.Getenv(key)
 	if valueStr == "" {
@@ -0,0 +1,61 @@
+package errors
+
+import "net/http"
+
+// Code is the machine-readable error code returned in every error response.
+type Code string
+
+const (
+	CodeBadRequest        Code = "BAD_REQUEST"
+	CodeUnauthorized      Code = "UNAUTHORIZED"
+	CodeForbidden         Code = "FORBIDDEN"
+	CodeNotFound          Code = "NOT_FOUND"
+	CodeConflict          Code = "CONFLICT"
+	CodeRequestTimeout    Code = "REQUEST_TIMEOUT"
+	CodePayloadTooLarge   Code = "PAYLOAD_TOO_LARGE"
+	CodeValidation        Code = "VALIDATION_ERROR"
+	CodeRateLimitExceeded Code = "RATE_LIMIT_EXCEEDED"
+	CodeServiceOverloaded Code = "SERVICE_OVERLOADED"
+	CodeMaintenanceMode   Code = "MAINTENANCE_MODE"
+	CodeDatabase          Code = "DATABASE_ERROR"
+	CodeInternalServer    Code = "INTERNAL_SERVER_ERROR"
+)
+
+// CodeInfo documents an error code for clients.
+type CodeInfo struct {
+	Code        Code   `json:"code"`
+	Status      int    `json:"status"`
+	Description string `json:"description"`
+}
+
+// catalog lists every code the API can return, in the order documented.
+var catalog = []CodeInfo{
+	{CodeBadRequest, http.StatusBadRequest, "Invalid request parameters"},
+	{CodeUnauthorized, http.StatusUnauthorized, "Missing or invalid authentication"},
+	{CodeForbidden, http.StatusForbidden, "Insufficient permissions"},
+	{CodeNotFound, http.StatusNotFound, "Resource not found"},
+	{CodeConflict, http.StatusConflict, "Resource conflict, or the resource is not in a state that allows the action"},
+	{CodeRequestTimeout, http.StatusRequestTimeout, "The request body was not received in time"},
+	{CodePayloadTooLarge, http.StatusRequestEntityTooLarge, "Request body or upload is too large"},
+	{CodeValidation, http.StatusUnprocessableEntity, "Request validation failed"},
+	{CodeRateLimitExceeded, http.StatusTooManyRequests, "Too many requests; retry after Retry-After"},
+	{CodeServiceOverloaded, http.StatusServiceUnavailable, "Too many requests in flight; retry after Retry-After"},
+	{CodeMaintenanceMode, http.StatusServiceUnavailable, "Writes are disabled during maintenance; reads still work"},
+	{CodeDatabase, http.StatusInternalServerError, "Database operation failed"},
+	{CodeInternalServer, http.StatusInternalServerError, "Unexpected server error"},
+}
+
+// Catalog returns every error code with its HTTP status and description.
+func Catalog() []CodeInfo {
+	return append([]CodeInfo(nil), catalog...)
+}
+
+// IsKnown reports whether code is in the catalog.
+func IsKnown(code Code) bool {
+	for _, info := range catalog {
+		if info.Code == code {
+			return true
+		}
+	}
+	return false
+}
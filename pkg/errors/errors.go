@@ -6,8 +6,11 @@ import (
 )
 
 type AppError struct {
-	Code       string `json:"code"`
-	Message    string `json:"message"`
+	Code    Code   `json:"code"`
+	Message string `json:"message"`
+	// Detail carries the original English message once Localize has
+	// translated Message.
+	Detail     string `json:"detail,omitempty"`
 	StatusCode int    `json:"-"`
 	Err        error  `json:"-"`
 }
@@ -21,7 +24,7 @@ func (e *AppError) Error() string {
 
 func NewBadRequest(message string) *AppError {
 	return &AppError{
-		Code:       "BAD_REQUEST",
+		Code:       CodeBadRequest,
 		Message:    message,
 		StatusCode: http.StatusBadRequest,
 	}
@@ -29,15 +32,27 @@ func NewBadRequest(message string) *AppError {
 
 func NewNotFound(resource string) *AppError {
 	return &AppError{
-		Code:       "NOT_FOUND",
+		Code:       CodeNotFound,
 		Message:    fmt.Sprintf("%s not found", resource),
 		StatusCode: http.StatusNotFound,
 	}
 }
 
+// NewNotFoundWithID names the missing resource's ID, e.g. "task <id> not
+// found". Only use it for lookups scoped to the requesting user, where the ID
+// came from the caller; use NewNotFound where echoing an ID would reveal
+// something the caller didn't already know.
+func NewNotFoundWithID(resource string, id fmt.Stringer) *AppError {
+	return &AppError{
+		Code:       CodeNotFound,
+		Message:    fmt.Sprintf("%s %s not found", resource, id),
+		StatusCode: http.StatusNotFound,
+	}
+}
+
 func NewUnauthorized(message string) *AppError {
 	return &AppError{
-		Code:       "UNAUTHORIZED",
+		Code:       CodeUnauthorized,
 		Message:    message,
 		StatusCode: http.StatusUnauthorized,
 	}
@@ -45,7 +60,7 @@ func NewUnauthorized(message string) *AppError {
 
 func NewForbidden(message string) *AppError {
 	return &AppError{
-		Code:       "FORBIDDEN",
+		Code:       CodeForbidden,
 		Message:    message,
 		StatusCode: http.StatusForbidden,
 	}
@@ -53,15 +68,31 @@ func NewForbidden(message string) *AppError {
 
 func NewConflict(message string) *AppError {
 	return &AppError{
-		Code:       "CONFLICT",
+		Code:       CodeConflict,
 		Message:    message,
 		StatusCode: http.StatusConflict,
 	}
 }
 
+func NewRequestTimeout(message string) *AppError {
+	return &AppError{
+		Code:       CodeRequestTimeout,
+		Message:    message,
+		StatusCode: http.StatusRequestTimeout,
+	}
+}
+
+func NewPayloadTooLarge(message string) *AppError {
+	return &AppError{
+		Code:       CodePayloadTooLarge,
+		Message:    message,
+		StatusCode: http.StatusRequestEntityTooLarge,
+	}
+}
+
 func NewInternalServer(err error) *AppError {
 	return &AppError{
-		Code:       "INTERNAL_SERVER_ERROR",
+		Code:       CodeInternalServer,
 		Message:    "An internal server error occurred",
 		StatusCode: http.StatusInternalServerError,
 		Err:        err,
@@ -70,7 +101,7 @@ func NewInternalServer(err error) *AppError {
 
 func NewValidationError(message string) *AppError {
 	return &AppError{
-		Code:       "VALIDATION_ERROR",
+		Code:       CodeValidation,
 		Message:    message,
 		StatusCode: http.StatusUnprocessableEntity,
 	}
@@ -78,7 +109,7 @@ func NewValidationError(message string) *AppError {
 
 func NewDatabaseError(err error) *AppError {
 	return &AppError{
-		Code:       "DATABASE_ERROR",
+		Code:       CodeDatabase,
 		Message:    "Database operation failed",
 		StatusCode: http.StatusInternalServerError,
 		Err:        err,
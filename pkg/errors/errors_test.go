@@ -0,0 +1,95 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewNotFound(t *testing.T) {
+	err := NewNotFound("task")
+
+	assert.Equal(t, CodeNotFound, err.Code)
+	assert.Equal(t, "task not found", err.Message)
+	assert.Equal(t, http.StatusNotFound, err.StatusCode)
+}
+
+func TestNewNotFoundWithID(t *testing.T) {
+	id := uuid.MustParse("8d7f1c52-5b0e-4b8e-9f3a-2c6d1e4a7b90")
+	err := NewNotFoundWithID("habit completion", id)
+
+	assert.Equal(t, CodeNotFound, err.Code)
+	assert.Equal(t, "habit completion 8d7f1c52-5b0e-4b8e-9f3a-2c6d1e4a7b90 not found", err.Message)
+	assert.Equal(t, http.StatusNotFound, err.StatusCode)
+	assert.Equal(t, err.Message, err.Error())
+}
+
+func TestConstructorsUseCatalogCodes(t *testing.T) {
+	cause := fmt.Errorf("boom")
+	constructed := []*AppError{
+		NewBadRequest("bad"),
+		NewNotFound("task"),
+		NewNotFoundWithID("task", uuid.New()),
+		NewUnauthorized("no"),
+		NewForbidden("no"),
+		NewConflict("taken"),
+		NewRequestTimeout("slow"),
+		NewPayloadTooLarge("big"),
+		NewInternalServer(cause),
+		NewValidationError("invalid"),
+		NewDatabaseError(cause),
+	}
+
+	statuses := make(map[Code]int)
+	for _, info := range Catalog() {
+		statuses[info.Code] = info.Status
+	}
+
+	for _, err := range constructed {
+		assert.True(t, IsKnown(err.Code), "code %q is not in the catalog", err.Code)
+		assert.Equal(t, statuses[err.Code], err.StatusCode, "status for %s", err.Code)
+	}
+}
+
+func TestCatalog_UniqueAndDescribed(t *testing.T) {
+	seen := make(map[Code]bool)
+	for _, info := range Catalog() {
+		assert.False(t, seen[info.Code], "duplicate code %s", info.Code)
+		seen[info.Code] = true
+		assert.NotEmpty(t, info.Description, info.Code)
+		assert.NotZero(t, info.Status, info.Code)
+	}
+
+	assert.False(t, IsKnown("NOT_A_CODE"))
+}
+
+func TestLocalize_TranslatesByCodeAndKeepsDetail(t *testing.T) {
+	err := NewNotFoundWithID("task", uuid.MustParse("8d7f1c52-5b0e-4b8e-9f3a-2c6d1e4a7b90"))
+
+	localized := err.Localize("es")
+
+	assert.Equal(t, "Recurso no encontrado", localized.Message)
+	assert.Equal(t, "task 8d7f1c52-5b0e-4b8e-9f3a-2c6d1e4a7b90 not found", localized.Detail)
+	assert.Equal(t, CodeNotFound, localized.Code)
+	assert.Equal(t, http.StatusNotFound, localized.StatusCode)
+	assert.Equal(t, "task 8d7f1c52-5b0e-4b8e-9f3a-2c6d1e4a7b90 not found", err.Message, "the original is left alone")
+}
+
+func TestLocalize_EnglishAndUnknownLanguagesKeepMessage(t *testing.T) {
+	err := NewBadRequest("limit must be between 1 and 100")
+
+	for _, lang := range []string{"en", "fr", ""} {
+		localized := err.Localize(lang)
+		assert.Equal(t, "limit must be between 1 and 100", localized.Message, lang)
+		assert.Empty(t, localized.Detail, lang)
+	}
+}
+
+func TestLocalize_EveryCatalogCodeHasSpanish(t *testing.T) {
+	for _, info := range Catalog() {
+		assert.NotEmpty(t, messages["es"][info.Code], "no Spanish message for %s", info.Code)
+	}
+}
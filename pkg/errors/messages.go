@@ -0,0 +1,39 @@
+package errors
+
+import "github.com/lumen/backend/pkg/i18n"
+
+// messages holds each code's message in the languages other than English.
+// English responses keep the specific message they were built with.
+var messages = map[string]map[Code]string{
+	i18n.Spanish: {
+		CodeBadRequest:        "Parámetros de solicitud no válidos",
+		CodeUnauthorized:      "Autenticación ausente o no válida",
+		CodeForbidden:         "Permisos insuficientes",
+		CodeNotFound:          "Recurso no encontrado",
+		CodeConflict:          "El recurso está en conflicto o no admite esta acción en su estado actual",
+		CodeRequestTimeout:    "El cuerpo de la solicitud no se recibió a tiempo",
+		CodePayloadTooLarge:   "El cuerpo de la solicitud o el archivo es demasiado grande",
+		CodeValidation:        "La validación de la solicitud falló",
+		CodeRateLimitExceeded: "Demasiadas solicitudes; vuelve a intentarlo después de Retry-After",
+		CodeServiceOverloaded: "Demasiadas solicitudes en curso; vuelve a intentarlo después de Retry-After",
+		CodeMaintenanceMode:   "Las escrituras están desactivadas durante el mantenimiento; las lecturas siguen funcionando",
+		CodeDatabase:          "La operación de base de datos falló",
+		CodeInternalServer:    "Se produjo un error interno del servidor",
+	},
+}
+
+// Localize returns e as written for lang. Outside English the message is
+// replaced by the code's translation and the original, which may name a field
+// or ID, moves to Detail. e is returned as is for English or when lang has no
+// translation for the code.
+func (e *AppError) Localize(lang string) *AppError {
+	message, ok := messages[lang][e.Code]
+	if !ok {
+		return e
+	}
+
+	localized := *e
+	localized.Message = message
+	localized.Detail = e.Message
+	return &localized
+}
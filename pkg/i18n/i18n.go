@@ -0,0 +1,71 @@
+// Package i18n picks the language a response is written in.
+package i18n
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Supported languages, as primary language subtags.
+const (
+	English = "en"
+	Spanish = "es"
+)
+
+// Default is the language used when the client accepts none of Supported.
+const Default = English
+
+// Supported lists every language responses can be written in.
+var Supported = []string{English, Spanish}
+
+// Negotiate returns the supported language the client prefers according to
+// an Accept-Language header such as "es-MX,es;q=0.9,en;q=0.8". Regional
+// variants match their language ("es-MX" is "es"); ranges with q=0 are
+// refused; anything unsupported, malformed or missing falls back to Default.
+func Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if lang == "*" {
+			lang = Default
+		}
+		if slices.Contains(Supported, lang) {
+			candidates = append(candidates, candidate{lang, q})
+		}
+	}
+
+	// Stable, so equally weighted languages keep the client's order.
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		switch {
+		case a.q > b.q:
+			return -1
+		case a.q < b.q:
+			return 1
+		}
+		return 0
+	})
+
+	if len(candidates) == 0 {
+		return Default
+	}
+	return candidates[0].lang
+}
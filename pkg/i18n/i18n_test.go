@@ -0,0 +1,30 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	cases := []struct {
+		header string
+		want   string
+	}{
+		{"", English},
+		{"es", Spanish},
+		{"es-MX,es;q=0.9,en;q=0.8", Spanish},
+		{"ES-es", Spanish},
+		{"en-US,en;q=0.9,es;q=0.8", English},
+		{"fr-FR,fr;q=0.9,es;q=0.5", Spanish},
+		{"en;q=0.2,es;q=0.7", Spanish},
+		{"fr, de", English},
+		{"es;q=0, en", English},
+		{"es;q=abc", English},
+		{"*", English},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, Negotiate(tc.header), "Accept-Language: %q", tc.header)
+	}
+}
@@ -5,6 +5,10 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
+// global backs the package-level helpers below. It is replaced by New so
+// repositories and handlers can log without threading a logger through.
+var global = zap.NewNop()
+
 func New(level, format string) (*zap.Logger, error) {
 	var config zap.Config
 
@@ -29,5 +33,28 @@ func New(level, format string) (*zap.Logger, error) {
 		return nil, err
 	}
 
+	SetGlobal(logger)
+
 	return logger, nil
 }
+
+// SetGlobal replaces the logger behind the package-level helpers.
+func SetGlobal(l *zap.Logger) {
+	global = l
+}
+
+func Debug(msg string, fields ...zap.Field) {
+	global.Debug(msg, fields...)
+}
+
+func Info(msg string, fields ...zap.Field) {
+	global.Info(msg, fields...)
+}
+
+func Warn(msg string, fields ...zap.Field) {
+	global.Warn(msg, fields...)
+}
+
+func Error(msg string, fields ...zap.Field) {
+	global.Error(msg, fields...)
+}
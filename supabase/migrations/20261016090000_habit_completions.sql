@@ -0,0 +1,21 @@
+-- Habit completions
+-- Created: 2026-10-16
+-- Description: Individual habit check-ins used to derive streaks and progress
+
+CREATE TABLE IF NOT EXISTS habit_completions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  habit_id UUID NOT NULL REFERENCES habits(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  completed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  notes TEXT DEFAULT '',
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_habit_completions_habit ON habit_completions(habit_id, completed_at);
+CREATE INDEX IF NOT EXISTS idx_habit_completions_user ON habit_completions(user_id);
+
+ALTER TABLE habit_completions ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Users can CRUD their own habit completions" ON habit_completions;
+CREATE POLICY "Users can CRUD their own habit completions" ON habit_completions
+  FOR ALL USING (auth.uid() = user_id);
@@ -0,0 +1,5 @@
+-- Task snoozing
+-- Created: 2026-10-16
+-- Description: Track how many times a task's due date has been pushed out
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS snooze_count INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,28 @@
+-- Goal milestones
+-- Created: 2026-10-16
+-- Description: Weighted sub-milestones that drive goal progress
+
+CREATE TABLE IF NOT EXISTS goal_milestones (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  goal_id UUID NOT NULL REFERENCES goals(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  title TEXT NOT NULL,
+  weight INTEGER NOT NULL DEFAULT 1 CHECK (weight > 0),
+  completed_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_goal_milestones_goal ON goal_milestones(goal_id);
+
+ALTER TABLE goal_milestones ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Users can CRUD their own goal milestones" ON goal_milestones;
+CREATE POLICY "Users can CRUD their own goal milestones" ON goal_milestones
+  FOR ALL USING (auth.uid() = user_id);
+
+DROP TRIGGER IF EXISTS update_goal_milestones_updated_at ON goal_milestones;
+CREATE TRIGGER update_goal_milestones_updated_at
+  BEFORE UPDATE ON goal_milestones
+  FOR EACH ROW
+  EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,6 @@
+-- Habit pause (vacation mode)
+-- Created: 2026-10-16
+-- Description: Pause window during which missed periods don't break streaks
+
+ALTER TABLE habits ADD COLUMN IF NOT EXISTS paused_from TIMESTAMPTZ;
+ALTER TABLE habits ADD COLUMN IF NOT EXISTS paused_until TIMESTAMPTZ;
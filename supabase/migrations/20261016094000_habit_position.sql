@@ -0,0 +1,16 @@
+-- Habit ordering
+-- Created: 2026-10-16
+-- Description: Manual dashboard order for habits
+
+ALTER TABLE habits ADD COLUMN IF NOT EXISTS position INTEGER NOT NULL DEFAULT 0;
+
+-- Seed existing habits in their previous (newest first) order
+UPDATE habits h
+SET position = ordered.rn - 1
+FROM (
+  SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY created_at DESC) AS rn
+  FROM habits
+) ordered
+WHERE h.id = ordered.id;
+
+CREATE INDEX IF NOT EXISTS idx_habits_user_position ON habits(user_id, position);
@@ -0,0 +1,9 @@
+-- Task tags
+-- Created: 2026-10-16
+-- Description: Free-form tags on tasks, filterable together with status/priority/horizon
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+-- Tag filters use array containment (tags @> ARRAY[...]) so combining several
+-- tags stays a single-table lookup instead of one join per tag.
+CREATE INDEX IF NOT EXISTS idx_tasks_tags ON tasks USING GIN (tags);
@@ -0,0 +1,7 @@
+-- Daily Log Rest Day
+-- Created: 2026-10-16
+-- Description: Lets a daily log mark the day as a rest day, neutral for habit streaks and productivity averages
+
+ALTER TABLE daily_logs ADD COLUMN IF NOT EXISTS is_rest_day BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_daily_logs_rest_days ON daily_logs(user_id, date) WHERE is_rest_day;
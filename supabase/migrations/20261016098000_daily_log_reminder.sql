@@ -0,0 +1,7 @@
+-- Daily Log Reminder
+-- Created: 2026-10-16
+-- Description: Per-user local time for a reminder to fill in the day's log
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS daily_log_reminder_time TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_users_daily_log_reminder ON users(id) WHERE daily_log_reminder_time IS NOT NULL;
@@ -0,0 +1,22 @@
+-- Streak Freezes
+-- Created: 2026-10-16
+-- Description: Per-user streak freeze token balance and the habit periods each spent token bridged
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS streak_freeze_tokens INTEGER NOT NULL DEFAULT 0;
+
+-- A spent token bridges one missed period of one habit; period_start is the
+-- calendar date the period starts on.
+CREATE TABLE IF NOT EXISTS habit_streak_freezes (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  habit_id UUID NOT NULL REFERENCES habits(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  period_start DATE NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  UNIQUE (habit_id, period_start)
+);
+
+ALTER TABLE habit_streak_freezes ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Users can read their own streak freezes" ON habit_streak_freezes;
+CREATE POLICY "Users can read their own streak freezes" ON habit_streak_freezes
+  FOR SELECT USING (auth.uid() = user_id);
@@ -0,0 +1,15 @@
+-- User Feature Flags
+-- Created: 2026-10-16
+-- Description: Per-user overrides of the global feature flag defaults
+
+CREATE TABLE IF NOT EXISTS user_feature_flags (
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  flag TEXT NOT NULL,
+  enabled BOOLEAN NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  PRIMARY KEY (user_id, flag)
+);
+
+-- Overrides are managed by operators through the admin API only.
+ALTER TABLE user_feature_flags ENABLE ROW LEVEL SECURITY;
@@ -0,0 +1,110 @@
+-- Daily Stats
+-- Created: 2026-10-16
+-- Description: Precomputed per-day stats, filled nightly and on demand, and invalidated when the activity behind them changes
+
+CREATE TABLE IF NOT EXISTS daily_stats (
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  date DATE NOT NULL,
+  habits_completed INTEGER NOT NULL DEFAULT 0,
+  habits_total INTEGER NOT NULL DEFAULT 0,
+  tasks_completed INTEGER NOT NULL DEFAULT 0,
+  tasks_total INTEGER NOT NULL DEFAULT 0,
+  mood_rating INTEGER NOT NULL DEFAULT 0,
+  energy_level INTEGER NOT NULL DEFAULT 0,
+  productivity_rating INTEGER NOT NULL DEFAULT 0,
+  computed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (user_id, date)
+);
+
+ALTER TABLE daily_stats ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Users can read their own daily stats" ON daily_stats;
+CREATE POLICY "Users can read their own daily stats" ON daily_stats
+  FOR SELECT USING (auth.uid() = user_id);
+
+-- Stored days are dropped when the activity behind them changes, and are
+-- recomputed the next time they are read. Instants are matched to the UTC
+-- date with a day either side, which covers every user timezone.
+CREATE OR REPLACE FUNCTION invalidate_daily_stats(p_user_id UUID, p_at TIMESTAMPTZ)
+RETURNS VOID AS $$
+BEGIN
+  IF p_user_id IS NULL OR p_at IS NULL THEN
+    RETURN;
+  END IF;
+  DELETE FROM daily_stats
+  WHERE user_id = p_user_id
+    AND date BETWEEN (p_at AT TIME ZONE 'UTC')::date - 1 AND (p_at AT TIME ZONE 'UTC')::date + 1;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION invalidate_daily_stats_for_log()
+RETURNS TRIGGER AS $$
+BEGIN
+  IF TG_OP <> 'INSERT' THEN
+    PERFORM invalidate_daily_stats(OLD.user_id, OLD.date::timestamp AT TIME ZONE 'UTC');
+  END IF;
+  IF TG_OP <> 'DELETE' THEN
+    PERFORM invalidate_daily_stats(NEW.user_id, NEW.date::timestamp AT TIME ZONE 'UTC');
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION invalidate_daily_stats_for_completion()
+RETURNS TRIGGER AS $$
+BEGIN
+  IF TG_OP <> 'INSERT' THEN
+    PERFORM invalidate_daily_stats(OLD.user_id, OLD.completed_at);
+  END IF;
+  IF TG_OP <> 'DELETE' THEN
+    PERFORM invalidate_daily_stats(NEW.user_id, NEW.completed_at);
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION invalidate_daily_stats_for_task()
+RETURNS TRIGGER AS $$
+BEGIN
+  IF TG_OP <> 'INSERT' THEN
+    PERFORM invalidate_daily_stats(OLD.user_id, OLD.due_date);
+    PERFORM invalidate_daily_stats(OLD.user_id, OLD.completed_at);
+  END IF;
+  IF TG_OP <> 'DELETE' THEN
+    PERFORM invalidate_daily_stats(NEW.user_id, NEW.due_date);
+    PERFORM invalidate_daily_stats(NEW.user_id, NEW.completed_at);
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Adding, removing or (de)activating a habit changes the habit total of
+-- every day since it was created, so all of the user's stored days go.
+CREATE OR REPLACE FUNCTION invalidate_daily_stats_for_habit()
+RETURNS TRIGGER AS $$
+BEGIN
+  DELETE FROM daily_stats
+  WHERE user_id = CASE WHEN TG_OP = 'DELETE' THEN OLD.user_id ELSE NEW.user_id END;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS daily_logs_invalidate_daily_stats ON daily_logs;
+CREATE TRIGGER daily_logs_invalidate_daily_stats
+  AFTER INSERT OR UPDATE OR DELETE ON daily_logs
+  FOR EACH ROW EXECUTE FUNCTION invalidate_daily_stats_for_log();
+
+DROP TRIGGER IF EXISTS habit_completions_invalidate_daily_stats ON habit_completions;
+CREATE TRIGGER habit_completions_invalidate_daily_stats
+  AFTER INSERT OR UPDATE OR DELETE ON habit_completions
+  FOR EACH ROW EXECUTE FUNCTION invalidate_daily_stats_for_completion();
+
+DROP TRIGGER IF EXISTS tasks_invalidate_daily_stats ON tasks;
+CREATE TRIGGER tasks_invalidate_daily_stats
+  AFTER INSERT OR UPDATE OF due_date, completed_at OR DELETE ON tasks
+  FOR EACH ROW EXECUTE FUNCTION invalidate_daily_stats_for_task();
+
+DROP TRIGGER IF EXISTS habits_invalidate_daily_stats ON habits;
+CREATE TRIGGER habits_invalidate_daily_stats
+  AFTER INSERT OR UPDATE OF is_active OR DELETE ON habits
+  FOR EACH ROW EXECUTE FUNCTION invalidate_daily_stats_for_habit();
@@ -0,0 +1,18 @@
+-- Daily Log Unique Date
+-- Created: 2026-10-16
+-- Description: One daily log per user and date, the constraint POST /daily-log upserts against
+
+-- The initial schema only made (user_id, date, goal_id) unique, so
+-- ON CONFLICT (user_id, date) had no index to match. Duplicates must be
+-- resolved by hand before the index can be built.
+DO $$
+BEGIN
+  IF EXISTS (
+    SELECT 1 FROM daily_logs GROUP BY user_id, date HAVING COUNT(*) > 1
+  ) THEN
+    RAISE EXCEPTION 'daily_logs has several logs for the same user and date; merge them before applying this migration';
+  END IF;
+END;
+$$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS daily_logs_user_id_date_key ON daily_logs(user_id, date);
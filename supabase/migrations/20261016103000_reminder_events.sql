@@ -0,0 +1,22 @@
+-- Reminder Events
+-- Created: 2026-10-16
+-- Description: One row per reminder sent, acknowledged when the user acts on it
+
+CREATE TABLE IF NOT EXISTS reminder_events (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  -- Set for habit reminders only.
+  habit_id UUID REFERENCES habits(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL CHECK (kind IN ('habit', 'daily_log')),
+  sent_at TIMESTAMPTZ NOT NULL,
+  acknowledged_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reminder_events_user_sent ON reminder_events(user_id, sent_at DESC);
+
+ALTER TABLE reminder_events ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Users can read their own reminder events" ON reminder_events;
+CREATE POLICY "Users can read their own reminder events" ON reminder_events
+  FOR SELECT USING (auth.uid() = user_id);
@@ -0,0 +1,21 @@
+-- Task Dependencies
+-- Created: 2026-10-16
+-- Description: Blocked-by links between a user's tasks; a task is blocked while any blocker is unfinished
+
+CREATE TABLE IF NOT EXISTS task_dependencies (
+  task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  blocked_by_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  PRIMARY KEY (task_id, blocked_by_id),
+  CHECK (task_id <> blocked_by_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_dependencies_user ON task_dependencies(user_id);
+CREATE INDEX IF NOT EXISTS idx_task_dependencies_blocked_by ON task_dependencies(blocked_by_id);
+
+ALTER TABLE task_dependencies ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Users can read their own task dependencies" ON task_dependencies;
+CREATE POLICY "Users can read their own task dependencies" ON task_dependencies
+  FOR SELECT USING (auth.uid() = user_id);
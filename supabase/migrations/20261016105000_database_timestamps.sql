@@ -0,0 +1,32 @@
+-- Database Timestamps
+-- Created: 2026-10-16
+-- Description: created_at/updated_at come from the database clock; inserts rely on these defaults
+
+-- habits, tasks and daily_logs gained updated_at outside of migrations.
+ALTER TABLE habits ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+ALTER TABLE daily_logs ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+
+UPDATE habits SET updated_at = created_at WHERE updated_at IS NULL;
+UPDATE tasks SET updated_at = created_at WHERE updated_at IS NULL;
+UPDATE daily_logs SET updated_at = created_at WHERE updated_at IS NULL;
+
+ALTER TABLE habits
+  ALTER COLUMN created_at SET DEFAULT NOW(),
+  ALTER COLUMN updated_at SET DEFAULT NOW();
+ALTER TABLE tasks
+  ALTER COLUMN created_at SET DEFAULT NOW(),
+  ALTER COLUMN updated_at SET DEFAULT NOW();
+ALTER TABLE daily_logs
+  ALTER COLUMN created_at SET DEFAULT NOW(),
+  ALTER COLUMN updated_at SET DEFAULT NOW();
+
+-- Never move updated_at backwards, matching the repositories'
+-- updated_at = GREATEST(NOW(), updated_at).
+CREATE OR REPLACE FUNCTION update_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+  NEW.updated_at = GREATEST(NOW(), OLD.updated_at);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,5 @@
+-- Habit streak reset
+-- Created: 2026-10-16
+-- Description: Point from which a habit's streaks start counting anew; earlier completions are kept
+
+ALTER TABLE habits ADD COLUMN IF NOT EXISTS streak_reset_at TIMESTAMPTZ;
@@ -0,0 +1,9 @@
+-- User water unit
+-- Created: 2026-10-16
+-- Description: Unit each user reads and enters water intake in; daily logs keep storing glasses
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS water_unit TEXT NOT NULL DEFAULT 'glasses';
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_water_unit_check;
+ALTER TABLE users ADD CONSTRAINT users_water_unit_check
+  CHECK (water_unit IN ('glasses', 'liters', 'ounces'));
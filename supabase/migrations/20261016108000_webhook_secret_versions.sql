@@ -0,0 +1,8 @@
+-- Webhook secret versions
+-- Created: 2026-10-16
+-- Description: Version each webhook's signing secret and record which version signed a delivery's latest attempt
+
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS secret_version INTEGER NOT NULL DEFAULT 1;
+
+-- NULL until the delivery has been attempted.
+ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS secret_version INTEGER;
@@ -0,0 +1,14 @@
+-- Habit amounts
+-- Created: 2026-10-16
+-- Description: Amount-based habits met by summed completion amounts rather than a completion count
+
+ALTER TABLE habits ADD COLUMN IF NOT EXISTS target_amount NUMERIC;
+ALTER TABLE habit_completions ADD COLUMN IF NOT EXISTS amount NUMERIC;
+
+ALTER TABLE habits DROP CONSTRAINT IF EXISTS habits_target_amount_check;
+ALTER TABLE habits ADD CONSTRAINT habits_target_amount_check
+  CHECK (target_amount IS NULL OR target_amount > 0);
+
+ALTER TABLE habit_completions DROP CONSTRAINT IF EXISTS habit_completions_amount_check;
+ALTER TABLE habit_completions ADD CONSTRAINT habit_completions_amount_check
+  CHECK (amount IS NULL OR amount > 0);
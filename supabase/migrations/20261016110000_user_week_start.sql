@@ -0,0 +1,9 @@
+-- User week start
+-- Created: 2026-10-16
+-- Description: Day each user's weeks begin on for weekly habits, streaks and the planner
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS week_start TEXT NOT NULL DEFAULT 'monday';
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_week_start_check;
+ALTER TABLE users ADD CONSTRAINT users_week_start_check
+  CHECK (week_start IN ('monday', 'sunday'));
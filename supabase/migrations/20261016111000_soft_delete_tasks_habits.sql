@@ -0,0 +1,22 @@
+-- Soft-deleted tasks and habits
+-- Created: 2026-10-16
+-- Description: Deleting a task or habit stamps deleted_at; it stays restorable from the trash until purged after the retention window
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE habits ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_deleted ON tasks(user_id, deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_habits_deleted ON habits(user_id, deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Trashing, restoring and purging change what daily stats count, so the
+-- invalidation triggers from the daily stats migration also fire on
+-- deleted_at. Purging is a DELETE, which they already cover.
+DROP TRIGGER IF EXISTS tasks_invalidate_daily_stats ON tasks;
+CREATE TRIGGER tasks_invalidate_daily_stats
+  AFTER INSERT OR UPDATE OF due_date, completed_at, deleted_at OR DELETE ON tasks
+  FOR EACH ROW EXECUTE FUNCTION invalidate_daily_stats_for_task();
+
+DROP TRIGGER IF EXISTS habits_invalidate_daily_stats ON habits;
+CREATE TRIGGER habits_invalidate_daily_stats
+  AFTER INSERT OR UPDATE OF is_active, deleted_at OR DELETE ON habits
+  FOR EACH ROW EXECUTE FUNCTION invalidate_daily_stats_for_habit();
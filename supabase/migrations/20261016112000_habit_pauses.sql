@@ -0,0 +1,28 @@
+-- Habit pause history
+-- Created: 2026-10-16
+-- Description: Keep every vacation-mode window a habit has had, so periods in earlier pauses stay neutral for streaks and completion rates
+
+-- habits.paused_from/paused_until keep the most recent window; this table
+-- holds it too, along with every earlier one. Extending an active pause
+-- moves its paused_until, so a window is keyed by where it starts.
+CREATE TABLE IF NOT EXISTS habit_pauses (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  habit_id UUID NOT NULL REFERENCES habits(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  paused_from TIMESTAMPTZ NOT NULL,
+  paused_until TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  UNIQUE (habit_id, paused_from)
+);
+
+INSERT INTO habit_pauses (habit_id, user_id, paused_from, paused_until)
+SELECT id, user_id, paused_from, paused_until
+FROM habits
+WHERE paused_from IS NOT NULL AND paused_until IS NOT NULL
+ON CONFLICT (habit_id, paused_from) DO NOTHING;
+
+ALTER TABLE habit_pauses ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Users can read their own habit pauses" ON habit_pauses;
+CREATE POLICY "Users can read their own habit pauses" ON habit_pauses
+  FOR SELECT USING (auth.uid() = user_id);
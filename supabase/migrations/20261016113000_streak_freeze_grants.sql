@@ -0,0 +1,22 @@
+-- Streak freeze grants
+-- Created: 2026-10-16
+-- Description: Record the streak milestones that earned a streak freeze token, so each is credited once
+
+-- A habit's current streak earns a token each time it reaches a multiple of
+-- the milestone length; period_start is the calendar date of the period the
+-- completion reaching it fell in. Undoing and logging that completion again
+-- hits the same row and credits nothing.
+CREATE TABLE IF NOT EXISTS habit_streak_freeze_grants (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  habit_id UUID NOT NULL REFERENCES habits(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  period_start DATE NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  UNIQUE (habit_id, period_start)
+);
+
+ALTER TABLE habit_streak_freeze_grants ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS "Users can read their own streak freeze grants" ON habit_streak_freeze_grants;
+CREATE POLICY "Users can read their own streak freeze grants" ON habit_streak_freeze_grants
+  FOR SELECT USING (auth.uid() = user_id);
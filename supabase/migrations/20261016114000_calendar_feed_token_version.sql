@@ -0,0 +1,5 @@
+-- Calendar feed token versions
+-- Created: 2026-10-16
+-- Description: Version each user's calendar feed token so rotating it revokes that user's issued feed URLs
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS calendar_feed_token_version INTEGER NOT NULL DEFAULT 1;